@@ -16,6 +16,9 @@ func main() {
 		// Print startup banner
 		fx.Invoke(di.PrintBanner),
 
+		// Leave time to drain HTTP requests before stopping the rest
+		fx.StopTimeout(di.StopTimeout),
+
 		// Configure fx logger to use zap
 		fx.WithLogger(func(logger *zap.Logger) fxevent.Logger {
 			return &fxevent.ZapLogger{Logger: logger}
@@ -2,22 +2,30 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
+	"github.com/jrjohn/arcana-cloud-go/internal/health"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/handler"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/lock"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/queue"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/scheduler"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/worker"
+	"github.com/jrjohn/arcana-cloud-go/internal/observability"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
 	"github.com/jrjohn/arcana-cloud-go/pkg/logger"
 )
 
@@ -34,15 +42,18 @@ func main() {
 	redisClient := mustConnectRedis(cfg, ctx, log)
 	defer redisClient.Close()
 
-	jobQueue := queue.NewRedisQueue(redisClient)
+	jobQueue := setupJobQueue(redisClient, cfg, log)
 	lockManager := setupLockManager(redisClient, log)
 	pool := setupWorkerPool(jobQueue, lockManager, log)
 
 	registry := handler.NewRegistry(pool, log)
-	registerHandlers(registry, log)
+	registry.Use(handler.RecoverMiddleware)
+	breakers := resilience.NewCircuitBreakerRegistry(log)
+	registerHandlers(registry, cfg, breakers, log)
 
 	sched := setupScheduler(redisClient, jobQueue, log)
 	registerScheduledJobs(sched, log)
+	pool.SetLeaderChecker(sched)
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -54,7 +65,12 @@ func main() {
 		log.Fatal("Failed to start scheduler", zap.Error(err))
 	}
 
-	go startMetricsServer(sched, lockManager, log)
+	resilienceMetrics := observability.NewResilienceCollector()
+	resilienceMetrics.AddCircuitBreakers(breakers)
+	metricsRegistry := prometheus.NewRegistry()
+	metricsRegistry.MustRegister(resilienceMetrics)
+
+	go startMetricsServer(pool, setupHealthChecker(redisClient), metricsRegistry, log)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -96,6 +112,23 @@ func mustConnectRedis(cfg *config.Config, ctx context.Context, log *zap.Logger)
 	return client
 }
 
+func setupJobQueue(redisClient *redis.Client, cfg *config.Config, log *zap.Logger) *queue.RedisQueue {
+	queueConfig := queue.DefaultQueueConfig()
+	queueConfig.Compress = cfg.Jobs.CompressPayloads
+	if cfg.Jobs.CompressThreshold > 0 {
+		queueConfig.CompressThreshold = cfg.Jobs.CompressThreshold
+	}
+	queueConfig.StrictPriority = cfg.Jobs.StrictPriority
+	if len(cfg.Jobs.PriorityWeights) > 0 {
+		weights, err := queue.ParsePriorityWeights(cfg.Jobs.PriorityWeights)
+		if err != nil {
+			log.Fatal("Invalid jobs.priority_weights", zap.Error(err))
+		}
+		queueConfig.PriorityWeights = weights
+	}
+	return queue.NewRedisQueueWithConfig(redisClient, queueConfig)
+}
+
 func setupLockManager(redisClient *redis.Client, log *zap.Logger) *lock.LockManager {
 	lockConfig := lock.DefaultLockManagerConfig()
 	lm := lock.NewLockManager(redisClient, lockConfig)
@@ -118,6 +151,22 @@ func setupWorkerPool(jobQueue *queue.RedisQueue, lockManager *lock.LockManager,
 	if os.Getenv("ARCANA_WORKER_DISABLE_IDEMPOTENCY") == "true" {
 		workerConfig.EnableIdempotency = false
 	}
+	if interval := os.Getenv("ARCANA_WORKER_DLQ_REAPER_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil {
+			workerConfig.DLQReaperInterval = d
+		}
+	}
+	if os.Getenv("ARCANA_WORKER_REQUEUE_STUCK_JOBS") == "true" {
+		workerConfig.RequeueStuckJobs = true
+	}
+	if timeout := os.Getenv("ARCANA_WORKER_JOB_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			workerConfig.JobTimeout = d
+		}
+	}
+	if queues := os.Getenv("ARCANA_WORKER_QUEUES"); queues != "" {
+		workerConfig.Queues = strings.Split(queues, ",")
+	}
 	pool := worker.NewWorkerPool(jobQueue, log, workerConfig)
 	pool.SetLockManager(lockManager)
 	return pool
@@ -128,12 +177,38 @@ func setupScheduler(redisClient *redis.Client, jobQueue *queue.RedisQueue, log *
 	return scheduler.NewSchedulerWithConfig(redisClient, jobQueue, log, schedConfig)
 }
 
-func startMetricsServer(sched *scheduler.Scheduler, lockManager *lock.LockManager, log *zap.Logger) {
+// setupHealthChecker checks Redis, without which no job can run, and the
+// job backlog, which only degrades health
+func setupHealthChecker(redisClient *redis.Client) *health.Checker {
+	checker := health.NewChecker()
+	checker.Register(health.Check{
+		Name:     "redis",
+		Critical: true,
+		Timeout:  2 * time.Second,
+		Check: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		},
+	})
+	checker.Register(health.Check{
+		Name: "workers",
+		Check: func(ctx context.Context) error {
+			if check := jobs.GlobalMetrics.GetHealthCheck(false); check.Status != "healthy" {
+				return fmt.Errorf("%d jobs pending", check.JobsPending)
+			}
+			return nil
+		},
+	})
+	return checker
+}
+
+func startMetricsServer(pool *worker.WorkerPool, checker *health.Checker, gatherer prometheus.Gatherer, log *zap.Logger) {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/metrics", jobs.GlobalMetrics.PrometheusHandler())
-	mux.HandleFunc("/health", handleHealth(sched, lockManager))
-	mux.HandleFunc("/ready", handleReady())
-	mux.HandleFunc("/running", handleRunning(lockManager))
+	mux.HandleFunc("/metrics", handleMetrics(gatherer, log))
+	mux.HandleFunc("/health", health.Handler(checker))
+	mux.HandleFunc("/health/live", health.LiveHandler())
+	mux.HandleFunc("/health/ready", health.ReadyHandler(checker))
+	mux.HandleFunc("/ready", health.ReadyHandler(checker))
+	mux.HandleFunc("/running", handleRunning(pool))
 
 	metricsPort := os.Getenv("METRICS_PORT")
 	if metricsPort == "" {
@@ -145,30 +220,28 @@ func startMetricsServer(sched *scheduler.Scheduler, lockManager *lock.LockManage
 	}
 }
 
-func handleHealth(sched *scheduler.Scheduler, lockManager *lock.LockManager) http.HandlerFunc {
+// handleMetrics serves the job metrics followed by those of gatherer, all in
+// the Prometheus text format
+func handleMetrics(gatherer prometheus.Gatherer, log *zap.Logger) http.HandlerFunc {
+	jobsMetrics := jobs.GlobalMetrics.PrometheusHandler()
 	return func(w http.ResponseWriter, r *http.Request) {
-		health := jobs.GlobalMetrics.GetHealthCheck(sched.IsLeader())
-		w.Header().Set("Content-Type", "application/json")
-		if health.Status == "healthy" {
-			w.WriteHeader(http.StatusOK)
-		} else {
-			w.WriteHeader(http.StatusServiceUnavailable)
-		}
-		fmt.Fprintf(w, `{"status":"%s","workers_active":%d,"jobs_pending":%d,"is_leader":%t,"worker_id":"%s"}`,
-			health.Status, health.WorkersActive, health.JobsPending, health.IsLeader, lockManager.GetWorkerID())
-	}
-}
+		jobsMetrics(w, r)
 
-func handleReady() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ready"}`))
+		families, err := gatherer.Gather()
+		if err != nil {
+			log.Warn("Failed to gather metrics", zap.Error(err))
+		}
+		for _, family := range families {
+			if _, err := expfmt.MetricFamilyToText(w, family); err != nil {
+				return
+			}
+		}
 	}
 }
 
-func handleRunning(lockManager *lock.LockManager) http.HandlerFunc {
+func handleRunning(pool *worker.WorkerPool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		runningJobs, err := lockManager.GetRunningJobs(r.Context())
+		runningJobs, err := pool.GetRunningJobs(r.Context())
 		w.Header().Set("Content-Type", "application/json")
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -185,7 +258,8 @@ func handleRunning(lockManager *lock.LockManager) http.HandlerFunc {
 			fmt.Fprintf(w, `"%s":"%s"`, jobID, workerInfo)
 			first = false
 		}
-		w.Write([]byte("}}"))
+		stuckJobs, _ := json.Marshal(pool.StuckJobs())
+		fmt.Fprintf(w, `},"stuck_jobs":%s}`, stuckJobs)
 	}
 }
 
@@ -206,34 +280,14 @@ func gracefulShutdown(pool *worker.WorkerPool, sched *scheduler.Scheduler, lockM
 	log.Info("Worker shutdown complete")
 }
 
-func registerHandlers(registry *handler.Registry, log *zap.Logger) {
+func registerHandlers(registry *handler.Registry, cfg *config.Config, breakers *resilience.CircuitBreakerRegistry, log *zap.Logger) {
 	// Register all job handlers
-	handler.Register(registry, "email", func(ctx context.Context, payload handler.EmailJobPayload) error {
-		log.Info("Processing email job",
-			zap.Strings("to", payload.To),
-			zap.String("subject", payload.Subject),
-		)
-		// Implement email sending logic
-		return nil
-	})
-
-	handler.Register(registry, "webhook", func(ctx context.Context, payload handler.WebhookJobPayload) error {
-		log.Info("Processing webhook job",
-			zap.String("url", payload.URL),
-			zap.String("method", payload.Method),
-		)
-		// Implement webhook logic
-		return nil
-	})
+	handler.Register(registry, "email", handler.NewEmailHandler(setupEmailSender(cfg, log), mustLoadEmailTemplates(cfg, log)))
 
-	handler.Register(registry, "cleanup", func(ctx context.Context, payload handler.CleanupJobPayload) error {
-		log.Info("Processing cleanup job",
-			zap.String("type", payload.Type),
-			zap.Int("older_than_days", payload.OlderThan),
-		)
-		// Implement cleanup logic
-		return nil
-	})
+	handler.Register(registry, "webhook", handler.NewWebhookHandler(handler.WebhookConfig{
+		Secret:  cfg.Webhook.Secret,
+		Timeout: cfg.Webhook.Timeout,
+	}, breakers))
 
 	handler.Register(registry, "notification", func(ctx context.Context, payload handler.NotificationJobPayload) error {
 		log.Info("Processing notification job",
@@ -244,25 +298,35 @@ func registerHandlers(registry *handler.Registry, log *zap.Logger) {
 		return nil
 	})
 
-	handler.Register(registry, "report", func(ctx context.Context, payload handler.ReportJobPayload) error {
-		log.Info("Processing report job",
-			zap.String("report_type", payload.ReportType),
-			zap.String("format", payload.Format),
-		)
-		// Implement report generation logic
-		return nil
-	})
+	// Report, sync and cleanup jobs query the database, which this worker
+	// does not connect to; the API server's workers run them. Bind this
+	// worker to named queues (ARCANA_WORKER_QUEUES) those jobs are not
+	// enqueued on.
 
-	handler.Register(registry, "sync", func(ctx context.Context, payload handler.SyncJobPayload) error {
-		log.Info("Processing sync job",
-			zap.String("source", payload.Source),
-			zap.String("destination", payload.Destination),
-		)
-		// Implement sync logic
-		return nil
+	log.Info("Registered job handlers")
+}
+
+func setupEmailSender(cfg *config.Config, log *zap.Logger) handler.EmailSender {
+	if cfg.SMTP.Host == "" {
+		log.Warn("No SMTP host configured, emails will be logged instead of sent")
+		return handler.NewLogEmailSender(log)
+	}
+	return handler.NewSMTPSender(handler.SMTPConfig{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+		Timeout:  cfg.SMTP.Timeout,
 	})
+}
 
-	log.Info("Registered job handlers")
+func mustLoadEmailTemplates(cfg *config.Config, log *zap.Logger) *handler.EmailTemplates {
+	templates, err := handler.LoadEmailTemplates(cfg.SMTP.TemplatesDir)
+	if err != nil {
+		log.Fatal("Failed to load email templates", zap.Error(err))
+	}
+	return templates
 }
 
 func registerScheduledJobs(sched *scheduler.Scheduler, log *zap.Logger) {
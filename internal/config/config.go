@@ -43,6 +43,9 @@ const (
 	DriverMySQL    DatabaseDriver = "mysql"
 	DriverPostgres DatabaseDriver = "postgres"
 	DriverMongoDB  DatabaseDriver = "mongodb"
+	DriverSQLite   DatabaseDriver = "sqlite"
+	// DriverCassandra also serves ScyllaDB, which speaks the same protocol
+	DriverCassandra DatabaseDriver = "cassandra"
 )
 
 // Config holds all application configuration
@@ -52,10 +55,18 @@ type Config struct {
 	Database   DatabaseConfig   `mapstructure:"database"`
 	Redis      RedisConfig      `mapstructure:"redis"`
 	JWT        JWTConfig        `mapstructure:"jwt"`
+	TOTP       TOTPConfig       `mapstructure:"totp"`
+	Password   PasswordConfig   `mapstructure:"password"`
+	RBAC       RBACConfig       `mapstructure:"rbac"`
 	Deployment DeploymentConfig `mapstructure:"deployment"`
 	Plugin     PluginConfig     `mapstructure:"plugin"`
 	SSR        SSRConfig        `mapstructure:"ssr"`
 	GRPC       GRPCConfig       `mapstructure:"grpc"`
+	Jobs       JobsConfig       `mapstructure:"jobs"`
+	SMTP       SMTPConfig       `mapstructure:"smtp"`
+	Webhook    WebhookConfig    `mapstructure:"webhook"`
+	Audit      AuditConfig      `mapstructure:"audit"`
+	Reports    ReportsConfig    `mapstructure:"reports"`
 }
 
 // AppConfig holds application-level settings
@@ -68,11 +79,45 @@ type AppConfig struct {
 
 // ServerConfig holds HTTP server settings
 type ServerConfig struct {
-	Host         string        `mapstructure:"host"`
-	Port         int           `mapstructure:"port"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	Host           string        `mapstructure:"host"`
+	Port           int           `mapstructure:"port"`
+	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout    time.Duration `mapstructure:"idle_timeout"`
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	MaxBodySize    int64         `mapstructure:"max_body_size"`
+	// ShutdownTimeout bounds how long in-flight requests may take to
+	// complete once the server stops
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// AdminIPFilter restricts admin routes by network
+	AdminIPFilter IPFilterConfig `mapstructure:"admin_ip_filter"`
+	// RequestLog controls what the request log records
+	RequestLog RequestLogConfig `mapstructure:"request_log"`
+}
+
+// RequestLogConfig holds request logging settings. Bodies are only logged for
+// error responses, or for admin requests sending DebugHeader.
+type RequestLogConfig struct {
+	CaptureBodies bool `mapstructure:"capture_bodies"`
+	// MaxBodySize is the most bytes of each body logged; zero keeps the default
+	MaxBodySize int `mapstructure:"max_body_size"`
+	// RedactFields replaces the default list of fields never logged
+	RedactFields []string `mapstructure:"redact_fields"`
+	DebugHeader  string   `mapstructure:"debug_header"`
+}
+
+// IPFilterConfig holds CIDR (or single address) allow and deny lists
+type IPFilterConfig struct {
+	// Allow lists the networks allowed in; empty allows every network not denied
+	Allow []string `mapstructure:"allow"`
+	Deny  []string `mapstructure:"deny"`
+	// TrustedProxies lists the proxies whose X-Forwarded-For is believed
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// IsEnabled reports whether any network restriction is configured
+func (c IPFilterConfig) IsEnabled() bool {
+	return len(c.Allow) > 0 || len(c.Deny) > 0
 }
 
 // GRPCConfig holds gRPC server settings
@@ -97,9 +142,46 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	// Replicas lists read-replica DSNs for SQL drivers. When set, reads are
+	// routed to a replica and writes to the primary given by DSN().
+	Replicas []string `mapstructure:"replicas"`
+	// PoolMetricsInterval is how often connection pool stats are sampled
+	PoolMetricsInterval time.Duration `mapstructure:"pool_metrics_interval"`
+	// BatchSize is the number of rows a batch create inserts per statement
+	// on SQL drivers
+	BatchSize int `mapstructure:"batch_size"`
 	// MongoDB-specific settings
 	AuthSource string `mapstructure:"auth_source"`
 	ReplicaSet string `mapstructure:"replica_set"`
+	// Cassandra-specific settings. Name is the keyspace.
+	Cassandra CassandraConfig `mapstructure:"cassandra"`
+	// Cache serves DAO lookups by ID and unique key from a cache
+	Cache DAOCacheConfig `mapstructure:"cache"`
+}
+
+// DAOCacheConfig holds the settings of the DAO lookup cache
+type DAOCacheConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Store is memory (an LRU per instance) or redis (shared by all instances)
+	Store string `mapstructure:"store"`
+	// Entities lists the cached entities: users, plugins and api_keys
+	Entities []string      `mapstructure:"entities"`
+	TTL      time.Duration `mapstructure:"ttl"`
+	// MaxEntries bounds the memory store
+	MaxEntries int `mapstructure:"max_entries"`
+	// CacheNotFound also caches lookups that found nothing
+	CacheNotFound bool `mapstructure:"cache_not_found"`
+}
+
+// CassandraConfig holds the settings of the Cassandra/ScyllaDB driver
+type CassandraConfig struct {
+	// Hosts lists the contact points; Host is used when empty
+	Hosts []string `mapstructure:"hosts"`
+	// Consistency is the consistency level of reads and writes, e.g. LOCAL_QUORUM
+	Consistency string `mapstructure:"consistency"`
+	// ReplicationFactor is used when the keyspace does not exist yet
+	ReplicationFactor int           `mapstructure:"replication_factor"`
+	Timeout           time.Duration `mapstructure:"timeout"`
 }
 
 // RedisConfig holds Redis connection settings
@@ -116,6 +198,51 @@ type JWTConfig struct {
 	AccessTokenDuration  time.Duration `mapstructure:"access_token_duration"`
 	RefreshTokenDuration time.Duration `mapstructure:"refresh_token_duration"`
 	Issuer               string        `mapstructure:"issuer"`
+	// Algorithm is the signing algorithm: HS256 (default), RS256 or ES256
+	Algorithm string `mapstructure:"algorithm"`
+	// PrivateKeyFile is the PEM private key signing RS256/ES256 tokens
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	// PublicKeyFile is the PEM public key verifying RS256/ES256 tokens on
+	// services that do not issue them
+	PublicKeyFile string `mapstructure:"public_key_file"`
+	// JWKSURL is fetched for verification keys when no key file is configured
+	JWKSURL string `mapstructure:"jwks_url"`
+	// RevocationEnabled makes logout revoke access tokens immediately using a
+	// Redis denylist, at the cost of a Redis read per authenticated request
+	RevocationEnabled bool `mapstructure:"revocation_enabled"`
+}
+
+// IsSymmetric returns true if tokens are signed with the shared secret
+func (c *JWTConfig) IsSymmetric() bool {
+	return c.Algorithm == "" || strings.EqualFold(c.Algorithm, "HS256")
+}
+
+// TOTPConfig holds two-factor authentication settings
+type TOTPConfig struct {
+	// Issuer is the account issuer shown in authenticator apps
+	Issuer string `mapstructure:"issuer"`
+	// EncryptionKey encrypts TOTP secrets at rest. If empty the JWT secret is
+	// used, which is only allowed with HS256 signing.
+	EncryptionKey string `mapstructure:"encryption_key"`
+}
+
+// PasswordConfig holds password hashing settings. Changing them takes effect
+// for existing users at their next login, when their hash is upgraded.
+type PasswordConfig struct {
+	// Algorithm is bcrypt or argon2id
+	Algorithm  string `mapstructure:"algorithm"`
+	BcryptCost int    `mapstructure:"bcrypt_cost"`
+	// Argon2Memory is the Argon2id memory in KiB
+	Argon2Memory      uint32 `mapstructure:"argon2_memory"`
+	Argon2Iterations  uint32 `mapstructure:"argon2_iterations"`
+	Argon2Parallelism uint8  `mapstructure:"argon2_parallelism"`
+}
+
+// RBACConfig holds role-based access control settings
+type RBACConfig struct {
+	// RolePermissions replaces the default permissions of the listed roles,
+	// e.g. {"ADMIN": ["*"], "USER": ["plugins:install"]}
+	RolePermissions map[string][]string `mapstructure:"role_permissions"`
 }
 
 // DeploymentConfig holds deployment-specific settings
@@ -131,6 +258,16 @@ type PluginConfig struct {
 	PluginsDirectory string `mapstructure:"plugins_directory"`
 	AutoLoad         bool   `mapstructure:"auto_load"`
 	HotReload        bool   `mapstructure:"hot_reload"`
+	MaxUploadSize    int64  `mapstructure:"max_upload_size"`
+	// DownloadTimeout bounds each attempt to download a plugin from a URL;
+	// downloads are capped at MaxUploadSize
+	DownloadTimeout time.Duration `mapstructure:"download_timeout"`
+	// AllowedHosts restricts plugin downloads to these hosts; "*.example.com"
+	// matches subdomains. Empty allows any host.
+	AllowedHosts []string `mapstructure:"allowed_hosts"`
+	// SigningPublicKey is a base64 Ed25519 public key. When set, plugins
+	// downloaded from a URL must carry a valid signature.
+	SigningPublicKey string `mapstructure:"signing_public_key"`
 }
 
 // SSRConfig holds server-side rendering settings
@@ -142,6 +279,83 @@ type SSRConfig struct {
 	CacheTTL     int    `mapstructure:"cache_ttl"`
 }
 
+// JobsConfig holds job queue settings
+type JobsConfig struct {
+	// CompressPayloads gzips queued jobs of at least CompressThreshold bytes
+	// in Redis
+	CompressPayloads  bool `mapstructure:"compress_payloads"`
+	CompressThreshold int  `mapstructure:"compress_threshold"`
+	// PriorityWeights sets the dequeue share of each priority by name (low,
+	// normal, high, critical); empty keeps the 8:4:2:1 default
+	PriorityWeights map[string]int `mapstructure:"priority_weights"`
+	// StrictPriority always dequeues higher priorities first
+	StrictPriority bool `mapstructure:"strict_priority"`
+	// WorkerQueues binds the in-process worker pool to these named queues;
+	// empty consumes the default queue
+	WorkerQueues []string `mapstructure:"worker_queues"`
+	// JobTimeout bounds every handler run by the in-process worker pool,
+	// overriding the timeout carried on each job; 0 keeps the job's own
+	JobTimeout time.Duration `mapstructure:"job_timeout"`
+}
+
+// SMTPConfig holds the mail server used by email jobs
+type SMTPConfig struct {
+	// Host is the SMTP server; when empty emails are logged instead of sent
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// From is the sender address, e.g. "Arcana Cloud <noreply@example.com>"
+	From    string        `mapstructure:"from"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	// TemplatesDir holds the HTML email templates, one <name>.html per template
+	TemplatesDir string `mapstructure:"templates_dir"`
+}
+
+// WebhookConfig holds the delivery settings of webhook jobs
+type WebhookConfig struct {
+	// Secret signs request bodies in the X-Signature header; unsigned when empty
+	Secret  string        `mapstructure:"secret"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// AuditConfig holds audit logging settings
+type AuditConfig struct {
+	// FailOnError fails audited operations whose audit record cannot be
+	// stored; by default the failure is only logged. Operations that cannot
+	// be undone are recorded before they run, so they do not run at all.
+	FailOnError bool `mapstructure:"fail_on_error"`
+	// RetentionMinDays is the fewest days of audit logs an old_audit_logs
+	// cleanup job may keep
+	RetentionMinDays int `mapstructure:"retention_min_days"`
+}
+
+// ReportsConfig holds where generated reports are stored
+type ReportsConfig struct {
+	// Storage is "local" or "s3"
+	Storage  string          `mapstructure:"storage"`
+	LocalDir string          `mapstructure:"local_dir"`
+	S3       ReportsS3Config `mapstructure:"s3"`
+}
+
+// ReportsS3Config holds the S3 bucket reports are stored in
+type ReportsS3Config struct {
+	// Endpoint is the service URL; empty uses AWS for the region
+	Endpoint string `mapstructure:"endpoint"`
+	Region   string `mapstructure:"region"`
+	Bucket   string `mapstructure:"bucket"`
+	// AccessKeyID and SecretAccessKey, with SessionToken for temporary keys;
+	// empty uses the default AWS credential chain
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	SessionToken    string `mapstructure:"session_token"`
+	Prefix          string `mapstructure:"prefix"`
+	// PathStyle addresses the bucket in the URL path, as MinIO expects
+	PathStyle bool `mapstructure:"path_style"`
+	// Timeout bounds a whole request, including streaming the object
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
 // Load reads configuration from file and environment variables
 func Load() (*Config, error) {
 	v := viper.New()
@@ -194,6 +408,15 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.read_timeout", 30*time.Second)
 	v.SetDefault("server.write_timeout", 30*time.Second)
 	v.SetDefault("server.idle_timeout", 60*time.Second)
+	v.SetDefault("server.request_timeout", 25*time.Second)
+	v.SetDefault("server.max_body_size", 4<<20)
+	v.SetDefault("server.shutdown_timeout", 15*time.Second)
+	v.SetDefault("server.admin_ip_filter.allow", []string{})
+	v.SetDefault("server.admin_ip_filter.deny", []string{})
+	v.SetDefault("server.admin_ip_filter.trusted_proxies", []string{})
+	v.SetDefault("server.request_log.capture_bodies", false)
+	v.SetDefault("server.request_log.max_body_size", 4096)
+	v.SetDefault("server.request_log.debug_header", "X-Debug-Log")
 
 	// gRPC defaults
 	v.SetDefault("grpc.host", "0.0.0.0")
@@ -211,6 +434,18 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.max_open_conns", 25)
 	v.SetDefault("database.max_idle_conns", 10)
 	v.SetDefault("database.conn_max_lifetime", 5*time.Minute)
+	v.SetDefault("database.pool_metrics_interval", 15*time.Second)
+	v.SetDefault("database.batch_size", 100)
+	v.SetDefault("database.replicas", []string{})
+	v.SetDefault("database.cassandra.consistency", "LOCAL_QUORUM")
+	v.SetDefault("database.cassandra.replication_factor", 1)
+	v.SetDefault("database.cassandra.timeout", 5*time.Second)
+	v.SetDefault("database.cache.enabled", false)
+	v.SetDefault("database.cache.store", "memory")
+	v.SetDefault("database.cache.entities", []string{"users", "plugins", "api_keys"})
+	v.SetDefault("database.cache.ttl", 5*time.Minute)
+	v.SetDefault("database.cache.max_entries", 10000)
+	v.SetDefault("database.cache.cache_not_found", false)
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
@@ -223,6 +458,19 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("jwt.access_token_duration", time.Hour)
 	v.SetDefault("jwt.refresh_token_duration", 30*24*time.Hour)
 	v.SetDefault("jwt.issuer", "arcana-cloud")
+	v.SetDefault("jwt.algorithm", "HS256")
+	v.SetDefault("jwt.revocation_enabled", false)
+
+	// TOTP defaults
+	v.SetDefault("totp.issuer", "Arcana Cloud")
+	v.SetDefault("totp.encryption_key", os.Getenv("TOTP_ENCRYPTION_KEY"))
+
+	// Password hashing defaults
+	v.SetDefault("password.algorithm", "bcrypt")
+	v.SetDefault("password.bcrypt_cost", 12)
+	v.SetDefault("password.argon2_memory", 64*1024)
+	v.SetDefault("password.argon2_iterations", 3)
+	v.SetDefault("password.argon2_parallelism", 2)
 
 	// Deployment defaults
 	v.SetDefault("deployment.mode", DeploymentMonolithic)
@@ -234,6 +482,10 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("plugin.plugins_directory", "./plugins")
 	v.SetDefault("plugin.auto_load", true)
 	v.SetDefault("plugin.hot_reload", true)
+	v.SetDefault("plugin.max_upload_size", 50<<20)
+	v.SetDefault("plugin.download_timeout", 60*time.Second)
+	v.SetDefault("plugin.allowed_hosts", []string{})
+	v.SetDefault("plugin.signing_public_key", "")
 
 	// SSR defaults
 	v.SetDefault("ssr.enabled", true)
@@ -241,13 +493,53 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("ssr.angular_path", "./arcana-web/angular-app")
 	v.SetDefault("ssr.cache_enabled", true)
 	v.SetDefault("ssr.cache_ttl", 3600)
+
+	// Jobs defaults
+	v.SetDefault("jobs.compress_payloads", false)
+	v.SetDefault("jobs.compress_threshold", 1024)
+	v.SetDefault("jobs.strict_priority", false)
+	v.SetDefault("jobs.job_timeout", 0)
+
+	// SMTP defaults
+	v.SetDefault("smtp.host", "")
+	v.SetDefault("smtp.port", 587)
+	v.SetDefault("smtp.username", "")
+	v.SetDefault("smtp.password", "")
+	v.SetDefault("smtp.from", "Arcana Cloud <noreply@localhost>")
+	v.SetDefault("smtp.timeout", 30*time.Second)
+	v.SetDefault("smtp.templates_dir", "./config/templates/email")
+
+	// Webhook defaults
+	v.SetDefault("webhook.secret", "")
+	v.SetDefault("webhook.timeout", 30*time.Second)
+
+	// Audit defaults
+	v.SetDefault("audit.fail_on_error", false)
+	v.SetDefault("audit.retention_min_days", 90)
+
+	// Reports defaults
+	v.SetDefault("reports.storage", "local")
+	v.SetDefault("reports.local_dir", "./data/reports")
+	v.SetDefault("reports.s3.endpoint", "")
+	v.SetDefault("reports.s3.region", "us-east-1")
+	v.SetDefault("reports.s3.bucket", "")
+	v.SetDefault("reports.s3.access_key_id", "")
+	v.SetDefault("reports.s3.secret_access_key", "")
+	v.SetDefault("reports.s3.session_token", "")
+	v.SetDefault("reports.s3.prefix", "")
+	v.SetDefault("reports.s3.path_style", false)
+	v.SetDefault("reports.s3.timeout", 5*time.Minute)
 }
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
-	if c.JWT.Secret == "" {
+	if c.JWT.IsSymmetric() && c.JWT.Secret == "" {
 		return fmt.Errorf("JWT secret is required")
 	}
+	// TOTP secrets only fall back to the JWT secret with HS256
+	if !c.JWT.IsSymmetric() && c.TOTP.EncryptionKey == "" {
+		return fmt.Errorf("TOTP encryption key is required with asymmetric JWT signing")
+	}
 	if c.Database.Name == "" {
 		return fmt.Errorf("database name is required")
 	}
@@ -255,6 +547,8 @@ func (c *Config) Validate() error {
 }
 
 // DSN returns the database connection string for SQL databases.
+// For SQLite the database name is used as the file path; ":memory:"
+// selects an in-memory database.
 func (c *DatabaseConfig) DSN() string {
 	switch c.Driver {
 	case string(DriverMySQL):
@@ -263,6 +557,8 @@ func (c *DatabaseConfig) DSN() string {
 	case string(DriverPostgres):
 		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 			c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
+	case string(DriverSQLite):
+		return c.Name
 	default:
 		return ""
 	}
@@ -299,9 +595,9 @@ func (c *DatabaseConfig) IsMongoDB() bool {
 	return c.Driver == string(DriverMongoDB)
 }
 
-// IsSQL returns true if a SQL driver (MySQL or PostgreSQL) is configured.
+// IsSQL returns true if a SQL driver (MySQL, PostgreSQL or SQLite) is configured.
 func (c *DatabaseConfig) IsSQL() bool {
-	return c.Driver == string(DriverMySQL) || c.Driver == string(DriverPostgres)
+	return c.Driver == string(DriverMySQL) || c.Driver == string(DriverPostgres) || c.Driver == string(DriverSQLite)
 }
 
 // IsMySQL returns true if MySQL driver is configured.
@@ -314,6 +610,32 @@ func (c *DatabaseConfig) IsPostgres() bool {
 	return c.Driver == string(DriverPostgres)
 }
 
+// IsSQLite returns true if SQLite driver is configured.
+func (c *DatabaseConfig) IsSQLite() bool {
+	return c.Driver == string(DriverSQLite)
+}
+
+// IsCassandra returns true if the Cassandra/ScyllaDB driver is configured.
+func (c *DatabaseConfig) IsCassandra() bool {
+	return c.Driver == string(DriverCassandra)
+}
+
+// CassandraHosts returns the Cassandra contact points as host:port.
+func (c *DatabaseConfig) CassandraHosts() []string {
+	hosts := c.Cassandra.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{c.Host}
+	}
+	addrs := make([]string, len(hosts))
+	for i, host := range hosts {
+		addrs[i] = host
+		if !strings.Contains(host, ":") && c.Port != 0 {
+			addrs[i] = fmt.Sprintf("%s:%d", host, c.Port)
+		}
+	}
+	return addrs
+}
+
 // IsControllerLayer checks if this instance should run the controller layer
 func (c *DeploymentConfig) IsControllerLayer() bool {
 	return c.Layer == LayerAll || c.Layer == LayerController
@@ -111,7 +111,9 @@ func TestDatabaseConfig_IsSQL(t *testing.T) {
 	}{
 		{"mysql", true},
 		{"postgres", true},
+		{"sqlite", true},
 		{"mongodb", false},
+		{"cassandra", false},
 		{"", false},
 	}
 	for _, tt := range tests {
@@ -174,3 +176,63 @@ func TestDefaultSchedulerConfig(t *testing.T) {
 		t.Error("DefaultSchedulerConfig should have Enabled=true")
 	}
 }
+
+func TestDatabaseConfig_IsSQLite(t *testing.T) {
+	tests := []struct {
+		driver   string
+		expected bool
+	}{
+		{"sqlite", true},
+		{"mysql", false},
+		{"postgres", false},
+		{"mongodb", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		cfg := DatabaseConfig{Driver: tt.driver}
+		if got := cfg.IsSQLite(); got != tt.expected {
+			t.Errorf("IsSQLite() with driver=%v = %v, want %v", tt.driver, got, tt.expected)
+		}
+	}
+}
+
+func TestDatabaseConfig_IsCassandra(t *testing.T) {
+	tests := []struct {
+		driver   string
+		expected bool
+	}{
+		{"cassandra", true},
+		{"mongodb", false},
+		{"mysql", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		cfg := DatabaseConfig{Driver: tt.driver}
+		if got := cfg.IsCassandra(); got != tt.expected {
+			t.Errorf("IsCassandra() with driver=%v = %v, want %v", tt.driver, got, tt.expected)
+		}
+	}
+}
+
+func TestDatabaseConfig_CassandraHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      DatabaseConfig
+		expected []string
+	}{
+		{"falls back to host", DatabaseConfig{Host: "localhost", Port: 9042}, []string{"localhost:9042"}},
+		{"appends port", DatabaseConfig{Port: 9042, Cassandra: CassandraConfig{Hosts: []string{"a", "b:9043"}}}, []string{"a:9042", "b:9043"}},
+		{"no port", DatabaseConfig{Cassandra: CassandraConfig{Hosts: []string{"a"}}}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		got := tt.cfg.CassandraHosts()
+		if len(got) != len(tt.expected) {
+			t.Fatalf("%s: CassandraHosts() = %v, want %v", tt.name, got, tt.expected)
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("%s: CassandraHosts() = %v, want %v", tt.name, got, tt.expected)
+			}
+		}
+	}
+}
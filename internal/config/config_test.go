@@ -90,6 +90,24 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "JWT secret is required",
 		},
+		{
+			name: "asymmetric JWT without secret",
+			config: Config{
+				JWT:      JWTConfig{Algorithm: "RS256", PrivateKeyFile: "jwt.key"},
+				TOTP:     TOTPConfig{EncryptionKey: "totp-key"},
+				Database: DatabaseConfig{Name: "test-db"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "asymmetric JWT without TOTP encryption key",
+			config: Config{
+				JWT:      JWTConfig{Algorithm: "ES256", PrivateKeyFile: "jwt.key"},
+				Database: DatabaseConfig{Name: "test-db"},
+			},
+			wantErr: true,
+			errMsg:  "TOTP encryption key is required with asymmetric JWT signing",
+		},
 		{
 			name: "missing database name",
 			config: Config{
@@ -146,9 +164,17 @@ func TestDatabaseConfig_DSN(t *testing.T) {
 			expected: "host=localhost port=5432 user=postgres password=password dbname=testdb sslmode=disable",
 		},
 		{
-			name: "unknown driver returns empty",
+			name: "sqlite uses name as path",
 			config: DatabaseConfig{
 				Driver: "sqlite",
+				Name:   ":memory:",
+			},
+			expected: ":memory:",
+		},
+		{
+			name: "unknown driver returns empty",
+			config: DatabaseConfig{
+				Driver: "oracle",
 			},
 			expected: "",
 		},
@@ -0,0 +1,98 @@
+package configserver
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// cipherPrefix marks an encrypted property value, as in Spring Cloud Config
+const cipherPrefix = "{cipher}"
+
+// DefaultCipherSalt is the hex salt Spring Cloud Config uses for symmetric
+// keys unless encrypt.salt is set
+const DefaultCipherSalt = "deadbeef"
+
+// Spring Security's Encryptors.text derives an AES-256 key from the password
+// with PBKDF2-HMAC-SHA1 over 1024 iterations
+const (
+	cipherKeyIterations = 1024
+	cipherKeyLength     = 32
+)
+
+// errMalformedCipherText is returned when a {cipher} value cannot be decoded
+var errMalformedCipherText = errors.New("malformed cipher text")
+
+// isCipherValue reports whether v is an encrypted {cipher} value
+func isCipherValue(v string) bool {
+	return strings.HasPrefix(v, cipherPrefix)
+}
+
+// newCipherBlock derives the AES block cipher for a symmetric key and hex
+// salt the way Spring Cloud Config does, so that values encrypted by its
+// /encrypt endpoint with the same encrypt.key and encrypt.salt decrypt here.
+// An empty salt means DefaultCipherSalt.
+func newCipherBlock(key, salt string) (cipher.Block, error) {
+	if salt == "" {
+		salt = DefaultCipherSalt
+	}
+	saltBytes, err := hex.DecodeString(salt)
+	if err != nil {
+		return nil, errors.New("cipher salt must be hex encoded")
+	}
+	derived, err := pbkdf2.Key(sha1.New, key, saltBytes, cipherKeyIterations, cipherKeyLength)
+	if err != nil {
+		return nil, err
+	}
+	return aes.NewCipher(derived)
+}
+
+// EncryptValue encrypts plaintext with the symmetric key and hex salt and
+// returns a {cipher} value in Spring Cloud Config's format: the hex encoding
+// of a random IV followed by the AES-CBC cipher text with PKCS#7 padding.
+// ConfigClient decrypts it when DecryptionKey and DecryptionSalt match.
+func EncryptValue(key, salt, plaintext string) (string, error) {
+	block, err := newCipherBlock(key, salt)
+	if err != nil {
+		return "", err
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append([]byte(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	data := make([]byte, aes.BlockSize+len(padded))
+	iv := data[:aes.BlockSize]
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data[aes.BlockSize:], padded)
+	return cipherPrefix + hex.EncodeToString(data), nil
+}
+
+// decryptLocal decrypts the hex cipher text of a {cipher} value (without the
+// prefix) with the symmetric key and hex salt
+func decryptLocal(key, salt, cipherText string) (string, error) {
+	block, err := newCipherBlock(key, salt)
+	if err != nil {
+		return "", err
+	}
+	data, err := hex.DecodeString(cipherText)
+	if err != nil || len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return "", errMalformedCipherText
+	}
+
+	iv, plaintext := data[:aes.BlockSize], data[aes.BlockSize:]
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, plaintext)
+
+	// A wrong key almost always leaves invalid padding
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return "", errors.New("cipher text does not decrypt with this key")
+	}
+	return string(plaintext[:len(plaintext)-padding]), nil
+}
@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,11 +22,22 @@ type ConfigClientConfig struct {
 	ServerURL       string        `mapstructure:"server_url"`
 	Application     string        `mapstructure:"application"`
 	Profile         string        `mapstructure:"profile"`
+	Label           string        `mapstructure:"label"`
 	FailFast        bool          `mapstructure:"fail_fast"`
 	RetryCount      int           `mapstructure:"retry_count"`
 	RetryInterval   time.Duration `mapstructure:"retry_interval"`
 	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
 	Timeout         time.Duration `mapstructure:"timeout"`
+	// DecryptionKey decrypts {cipher} values locally, like Spring Cloud
+	// Config's symmetric encrypt.key. When empty they are sent to the config
+	// server's /decrypt endpoint instead.
+	DecryptionKey string `mapstructure:"decryption_key"`
+	// DecryptionSalt is the hex salt used with DecryptionKey, like Spring
+	// Cloud Config's encrypt.salt; DefaultCipherSalt when empty.
+	DecryptionSalt string `mapstructure:"decryption_salt"`
+	// RefreshSecret authorizes on-demand refreshes through
+	// NewRefreshHandler. When empty the endpoint rejects every request.
+	RefreshSecret string `mapstructure:"refresh_secret"`
 }
 
 // DefaultConfigClientConfig returns default configuration
@@ -64,8 +79,17 @@ type ConfigClient struct {
 	listeners  []func(map[string]interface{})
 }
 
+// labelPattern matches labels that are safe as a URL path segment. As in
+// Spring Cloud Config, a branch name containing "/" is written with "(_)",
+// e.g. "feature(_)login".
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9._~()-]+$`)
+
 // NewConfigClient creates a new configuration client
 func NewConfigClient(config *ConfigClientConfig, logger *zap.Logger) (*ConfigClient, error) {
+	if config.Label != "" && !labelPattern.MatchString(config.Label) {
+		return nil, fmt.Errorf("invalid config label %q: must be URL-safe", config.Label)
+	}
+
 	client := &ConfigClient{
 		config: config,
 		httpClient: &http.Client{
@@ -118,9 +142,19 @@ func (c *ConfigClient) Stop() {
 	close(c.stopCh)
 }
 
+// configURL returns the config endpoint for the application and profile,
+// with the label (git branch or tag) as the last segment when set
+func (c *ConfigClient) configURL() string {
+	url := fmt.Sprintf("%s/config/%s/%s", c.config.ServerURL, c.config.Application, c.config.Profile)
+	if c.config.Label != "" {
+		url += "/" + c.config.Label
+	}
+	return url
+}
+
 // fetchConfig fetches configuration from the server
 func (c *ConfigClient) fetchConfig() error {
-	url := fmt.Sprintf("%s/config/%s/%s", c.config.ServerURL, c.config.Application, c.config.Profile)
+	url := c.configURL()
 
 	var lastErr error
 	for i := 0; i <= c.config.RetryCount; i++ {
@@ -155,10 +189,13 @@ func (c *ConfigClient) fetchConfigOnce(url string) error {
 	}
 
 	newConfig := mergePropertySources(configResp.PropertySources)
-	c.updateCache(newConfig)
+	if err := c.updateCache(newConfig); err != nil {
+		return err
+	}
 	c.logger.Info("Configuration loaded",
 		zap.String("application", c.config.Application),
 		zap.String("profile", c.config.Profile),
+		zap.String("label", c.config.Label),
 		zap.Int("properties", len(newConfig)),
 	)
 	return nil
@@ -175,8 +212,14 @@ func mergePropertySources(sources []PropertySource) map[string]interface{} {
 	return merged
 }
 
-// updateCache replaces the config cache and notifies listeners if changed
-func (c *ConfigClient) updateCache(newConfig map[string]interface{}) {
+// updateCache decrypts {cipher} values, replaces the config cache and
+// notifies listeners if changed. A value that fails to decrypt is kept as is,
+// unless FailFast is set, in which case the cache is left untouched.
+func (c *ConfigClient) updateCache(newConfig map[string]interface{}) error {
+	if err := c.decryptValues(newConfig, ""); err != nil {
+		return err
+	}
+
 	c.mutex.Lock()
 	oldConfig := c.cache
 	c.cache = newConfig
@@ -185,6 +228,61 @@ func (c *ConfigClient) updateCache(newConfig map[string]interface{}) {
 	if !configEqual(oldConfig, newConfig) {
 		c.notifyListeners(newConfig)
 	}
+	return nil
+}
+
+// decryptValues replaces {cipher} values in config, including those in
+// nested maps, with their plaintext
+func (c *ConfigClient) decryptValues(config map[string]interface{}, prefix string) error {
+	for k, v := range config {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			if err := c.decryptValues(value, prefix+k+"."); err != nil {
+				return err
+			}
+		case string:
+			if !isCipherValue(value) {
+				continue
+			}
+			plaintext, err := c.decrypt(strings.TrimPrefix(value, cipherPrefix))
+			if err != nil {
+				if c.config.FailFast {
+					return fmt.Errorf("failed to decrypt %s: %w", prefix+k, err)
+				}
+				c.logger.Warn("Failed to decrypt config value, keeping raw value",
+					zap.String("key", prefix+k),
+					zap.Error(err),
+				)
+				continue
+			}
+			config[k] = plaintext
+		}
+	}
+	return nil
+}
+
+// decrypt decrypts cipher text with DecryptionKey if set, otherwise via the
+// config server's /decrypt endpoint
+func (c *ConfigClient) decrypt(cipherText string) (string, error) {
+	if c.config.DecryptionKey != "" {
+		return decryptLocal(c.config.DecryptionKey, c.config.DecryptionSalt, cipherText)
+	}
+
+	url := fmt.Sprintf("%s/decrypt", c.config.ServerURL)
+	resp, err := c.httpClient.Post(url, "text/plain", strings.NewReader(cipherText))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("decrypt failed with %d: %s", resp.StatusCode, string(body))
+	}
+	return string(body), nil
 }
 
 // Refresh refreshes configuration from the server
@@ -192,13 +290,33 @@ func (c *ConfigClient) Refresh() error {
 	return c.fetchConfig()
 }
 
-// Get returns a configuration value
+// Get returns a configuration value. A dotted key such as
+// "spring.datasource.url" also resolves through nested maps; a literal key
+// containing the dots takes precedence.
 func (c *ConfigClient) Get(key string) (interface{}, bool) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	value, ok := c.cache[key]
-	return value, ok
+	return lookupPath(c.cache, key)
+}
+
+// lookupPath resolves key in config, first as a literal key and then by
+// splitting it at each dot, longest prefix first, and descending into the
+// nested map found under that prefix
+func lookupPath(config map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := config[key]; ok {
+		return value, true
+	}
+	for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+		nested, ok := config[key[:i]].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, ok := lookupPath(nested, key[i+1:]); ok {
+			return value, true
+		}
+	}
+	return nil, false
 }
 
 // GetString returns a string configuration value
@@ -242,6 +360,88 @@ func (c *ConfigClient) GetBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// GetFloat returns a float64 configuration value
+func (c *ConfigClient) GetFloat(key string, defaultValue float64) float64 {
+	value, ok := c.Get(key)
+	if !ok {
+		return defaultValue
+	}
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return defaultValue
+}
+
+// GetDuration returns a duration configuration value. Strings are parsed as
+// Go durations ("1m30s") or, failing that, as seconds ("90"); numbers are
+// taken as seconds.
+func (c *ConfigClient) GetDuration(key string, defaultValue time.Duration) time.Duration {
+	value, ok := c.Get(key)
+	if !ok {
+		return defaultValue
+	}
+	switch v := value.(type) {
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+			return secondsToDuration(seconds)
+		}
+	case float64:
+		return secondsToDuration(v)
+	case int:
+		return time.Duration(v) * time.Second
+	case int64:
+		return time.Duration(v) * time.Second
+	}
+	return defaultValue
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// GetStringSlice returns a string slice configuration value. Lists must hold
+// only strings; a string is split on commas with surrounding spaces trimmed.
+func (c *ConfigClient) GetStringSlice(key string, defaultValue []string) []string {
+	value, ok := c.Get(key)
+	if !ok {
+		return defaultValue
+	}
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, len(v))
+		for i, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return defaultValue
+			}
+			result[i] = str
+		}
+		return result
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return []string{}
+		}
+		parts := strings.Split(v, ",")
+		for i, part := range parts {
+			parts[i] = strings.TrimSpace(part)
+		}
+		return parts
+	}
+	return defaultValue
+}
+
 // GetAll returns all configuration values
 func (c *ConfigClient) GetAll() map[string]interface{} {
 	c.mutex.RLock()
@@ -296,13 +496,38 @@ func (c *ConfigClient) RefreshConfig(ctx context.Context) error {
 	return c.fetchConfig()
 }
 
+// Ping checks that the config server is reachable through its /health
+// endpoint. A disabled client never contacts the server, so it is always healthy.
+func (c *ConfigClient) Ping(ctx context.Context) error {
+	if !c.config.Enabled {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.ServerURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("config server returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // configEqual compares two config maps for equality
 func configEqual(a, b map[string]interface{}) bool {
 	if len(a) != len(b) {
 		return false
 	}
 	for k, v := range a {
-		if bv, ok := b[k]; !ok || v != bv {
+		// Values may be nested maps or lists, which == cannot compare
+		if bv, ok := b[k]; !ok || !reflect.DeepEqual(v, bv) {
 			return false
 		}
 	}
@@ -3,8 +3,11 @@ package configserver
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -153,6 +156,59 @@ func TestConfigClient_Get(t *testing.T) {
 	}
 }
 
+func TestConfigClient_Get_DotPath(t *testing.T) {
+	client := newDisabledClient(t)
+	client.cache = mergePropertySources([]PropertySource{
+		{Name: "flat", Source: map[string]interface{}{
+			"server.port":           8080,
+			"spring.datasource.url": "jdbc:flat",
+		}},
+		{Name: "nested", Source: map[string]interface{}{
+			"spring": map[string]interface{}{
+				"datasource": map[string]interface{}{
+					"url":      "jdbc:nested",
+					"username": "arcana",
+				},
+				"profiles": "dev",
+			},
+			"app.cache": map[string]interface{}{
+				"ttl": "5m",
+			},
+		}},
+	})
+
+	tests := []struct {
+		key    string
+		want   interface{}
+		wantOK bool
+	}{
+		{"server.port", 8080, true},
+		{"spring.datasource.url", "jdbc:flat", true}, // literal dotted key wins
+		{"spring.datasource.username", "arcana", true},
+		{"spring.profiles", "dev", true},
+		{"app.cache.ttl", "5m", true}, // dotted key holding a nested map
+		{"spring.datasource.password", nil, false},
+		{"spring.missing.url", nil, false},     // missing intermediate key
+		{"spring.profiles.active", nil, false}, // intermediate value is not a map
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok := client.Get(tt.key)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Get(%q) = %v, %v; want %v, %v", tt.key, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got := client.GetString("spring.datasource.username", ""); got != "arcana" {
+		t.Errorf("GetString() through nested maps = %v, want arcana", got)
+	}
+	if nested, ok := client.Get("spring.datasource"); !ok || reflect.TypeOf(nested).Kind() != reflect.Map {
+		t.Errorf("Get(spring.datasource) = %v, %v; want the nested map", nested, ok)
+	}
+}
+
 func TestConfigClient_GetString(t *testing.T) {
 	client := newDisabledClient(t)
 
@@ -216,6 +272,97 @@ func TestConfigClient_GetBool(t *testing.T) {
 	}
 }
 
+func TestConfigClient_GetFloat(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  float64
+	}{
+		{"float64", 3.14, 3.14},
+		{"float32", float32(0.5), 0.5},
+		{"int", 42, 42},
+		{"int64", int64(7), 7},
+		{"numeric string is a mismatch", "3.14", -1},
+		{"bool is a mismatch", true, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newDisabledClient(t)
+			client.cache["key"] = tt.value
+			if got := client.GetFloat("key", -1); got != tt.want {
+				t.Errorf("GetFloat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := newDisabledClient(t).GetFloat("missing", 1.5); got != 1.5 {
+		t.Errorf("GetFloat(missing) = %v, want 1.5", got)
+	}
+}
+
+func TestConfigClient_GetDuration(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  time.Duration
+	}{
+		{"go duration string", "1m30s", 90 * time.Second},
+		{"millisecond string", "250ms", 250 * time.Millisecond},
+		{"numeric string is seconds", "30", 30 * time.Second},
+		{"fractional numeric string", "1.5", 1500 * time.Millisecond},
+		{"float64 is seconds", float64(2), 2 * time.Second},
+		{"int is seconds", 10, 10 * time.Second},
+		{"int64 is seconds", int64(3), 3 * time.Second},
+		{"unparsable string", "soon", time.Minute},
+		{"empty string", "", time.Minute},
+		{"bool is a mismatch", true, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newDisabledClient(t)
+			client.cache["key"] = tt.value
+			if got := client.GetDuration("key", time.Minute); got != tt.want {
+				t.Errorf("GetDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := newDisabledClient(t).GetDuration("missing", time.Hour); got != time.Hour {
+		t.Errorf("GetDuration(missing) = %v, want 1h", got)
+	}
+}
+
+func TestConfigClient_GetStringSlice(t *testing.T) {
+	defaultValue := []string{"default"}
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{"interface slice", []interface{}{"a", "b"}, []string{"a", "b"}},
+		{"string slice", []string{"a"}, []string{"a"}},
+		{"empty interface slice", []interface{}{}, []string{}},
+		{"mixed interface slice is a mismatch", []interface{}{"a", 1}, defaultValue},
+		{"comma-separated string", "a, b ,c", []string{"a", "b", "c"}},
+		{"single value string", "a", []string{"a"}},
+		{"blank string", "  ", []string{}},
+		{"number is a mismatch", 42, defaultValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newDisabledClient(t)
+			client.cache["key"] = tt.value
+			if got := client.GetStringSlice("key", defaultValue); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStringSlice() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	if got := newDisabledClient(t).GetStringSlice("missing", defaultValue); !reflect.DeepEqual(got, defaultValue) {
+		t.Errorf("GetStringSlice(missing) = %v, want %v", got, defaultValue)
+	}
+}
+
 func TestConfigClient_GetAll(t *testing.T) {
 	client := newDisabledClient(t)
 
@@ -329,6 +476,16 @@ func TestConfigEqual(t *testing.T) {
 	if configEqual(a, d) {
 		t.Error("configEqual(a, d) should be false for different lengths")
 	}
+
+	nested := map[string]interface{}{"db": map[string]interface{}{"host": "a"}, "hosts": []interface{}{"x"}}
+	sameNested := map[string]interface{}{"db": map[string]interface{}{"host": "a"}, "hosts": []interface{}{"x"}}
+	otherNested := map[string]interface{}{"db": map[string]interface{}{"host": "b"}, "hosts": []interface{}{"x"}}
+	if !configEqual(nested, sameNested) {
+		t.Error("configEqual() should be true for identical nested maps")
+	}
+	if configEqual(nested, otherNested) {
+		t.Error("configEqual() should be false for different nested values")
+	}
 }
 
 func TestConfigClient_FetchConfig_ServerError(t *testing.T) {
@@ -456,6 +613,153 @@ func TestConfigClient_UpdateCache_NoChange(t *testing.T) {
 	}
 }
 
+// newCipherServer serves one property source and decrypts {cipher} values
+// through /decrypt, failing with decryptStatus when it is not 200
+func newCipherServer(t *testing.T, source map[string]interface{}, decryptStatus int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ConfigResponse{
+			Name:            "test-app",
+			PropertySources: []PropertySource{{Name: "test", Source: source}},
+		})
+	})
+	mux.HandleFunc("/decrypt", func(w http.ResponseWriter, r *http.Request) {
+		if decryptStatus != http.StatusOK {
+			http.Error(w, "cannot decrypt", decryptStatus)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "c2VjcmV0" {
+			http.Error(w, "unexpected cipher text", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("s3cret"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newCipherClientConfig(serverURL string) *ConfigClientConfig {
+	config := DefaultConfigClientConfig()
+	config.Enabled = true
+	config.ServerURL = serverURL
+	config.RetryCount = 0
+	return config
+}
+
+func TestConfigClient_Decrypt_ViaServer(t *testing.T) {
+	server := newCipherServer(t, map[string]interface{}{
+		"db.password": "{cipher}c2VjcmV0",
+		"db.user":     "arcana",
+	}, http.StatusOK)
+
+	client, err := NewConfigClient(newCipherClientConfig(server.URL), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfigClient() error = %v", err)
+	}
+
+	if got := client.GetString("db.password", ""); got != "s3cret" {
+		t.Errorf("db.password = %v, want s3cret", got)
+	}
+	if got := client.GetString("db.user", ""); got != "arcana" {
+		t.Errorf("db.user = %v, want arcana", got)
+	}
+}
+
+func TestConfigClient_Decrypt_LocalKey(t *testing.T) {
+	encrypted, err := EncryptValue("local-key", "", "s3cret")
+	if err != nil {
+		t.Fatalf("EncryptValue() error = %v", err)
+	}
+	// /decrypt would fail, so a plaintext result proves local decryption
+	server := newCipherServer(t, map[string]interface{}{"db.password": encrypted}, http.StatusInternalServerError)
+
+	config := newCipherClientConfig(server.URL)
+	config.DecryptionKey = "local-key"
+	client, err := NewConfigClient(config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfigClient() error = %v", err)
+	}
+
+	if got := client.GetString("db.password", ""); got != "s3cret" {
+		t.Errorf("db.password = %v, want s3cret", got)
+	}
+}
+
+func TestConfigClient_Decrypt_FailureKeepsRawValue(t *testing.T) {
+	server := newCipherServer(t, map[string]interface{}{"db.password": "{cipher}c2VjcmV0"}, http.StatusNotImplemented)
+
+	client, err := NewConfigClient(newCipherClientConfig(server.URL), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfigClient() error = %v", err)
+	}
+
+	if got := client.GetString("db.password", ""); got != "{cipher}c2VjcmV0" {
+		t.Errorf("db.password = %v, want the raw {cipher} value", got)
+	}
+}
+
+func TestConfigClient_Decrypt_FailFast(t *testing.T) {
+	server := newCipherServer(t, map[string]interface{}{"db.password": "{cipher}c2VjcmV0"}, http.StatusNotImplemented)
+
+	config := newCipherClientConfig(server.URL)
+	config.FailFast = true
+	if _, err := NewConfigClient(config, zap.NewNop()); err == nil {
+		t.Error("NewConfigClient() should fail when decryption fails with FailFast=true")
+	}
+}
+
+func TestConfigClient_Decrypt_NestedValue(t *testing.T) {
+	server := newCipherServer(t, map[string]interface{}{
+		"db": map[string]interface{}{"password": "{cipher}c2VjcmV0"},
+	}, http.StatusOK)
+
+	client, err := NewConfigClient(newCipherClientConfig(server.URL), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfigClient() error = %v", err)
+	}
+
+	if got := client.GetString("db.password", ""); got != "s3cret" {
+		t.Errorf("db.password = %v, want s3cret", got)
+	}
+}
+
+func TestDecryptLocal(t *testing.T) {
+	encrypted, err := EncryptValue("key", "cafe", "plain")
+	if err != nil {
+		t.Fatalf("EncryptValue() error = %v", err)
+	}
+	cipherText := strings.TrimPrefix(encrypted, cipherPrefix)
+
+	if got, err := decryptLocal("key", "cafe", cipherText); err != nil || got != "plain" {
+		t.Errorf("decryptLocal() = %v, %v; want plain, nil", got, err)
+	}
+	if _, err := decryptLocal("key", "cafe", "not hex!"); err == nil {
+		t.Error("decryptLocal() with malformed input should fail")
+	}
+	if _, err := decryptLocal("key", "not hex", cipherText); err == nil {
+		t.Error("decryptLocal() with a malformed salt should fail")
+	}
+}
+
+func TestDecryptLocal_SpringCloudConfigValue(t *testing.T) {
+	// Encrypted as Spring Cloud Config does with encrypt.key=my-secret-key
+	// and the default salt: hex IV, then AES-256-CBC with a PBKDF2 key
+	const cipherText = "00112233445566778899aabbccddeeff2962496fb86555094364ba0d573e5f11"
+
+	if got, err := decryptLocal("my-secret-key", "", cipherText); err != nil || got != "s3cret" {
+		t.Errorf("decryptLocal() = %v, %v; want s3cret, nil", got, err)
+	}
+	if got, err := decryptLocal("my-secret-key", DefaultCipherSalt, cipherText); err != nil || got != "s3cret" {
+		t.Errorf("decryptLocal() with the default salt = %v, %v; want s3cret, nil", got, err)
+	}
+	if _, err := decryptLocal("wrong-key", "", cipherText); err == nil {
+		t.Error("decryptLocal() with the wrong key should fail")
+	}
+}
+
 func TestConfigClient_Refresh(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := ConfigResponse{
@@ -504,6 +808,56 @@ func TestConfigClient_Refresh(t *testing.T) {
 	}
 }
 
+func TestConfigClient_Label(t *testing.T) {
+	paths := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		json.NewEncoder(w).Encode(ConfigResponse{Name: "app"})
+	}))
+	defer server.Close()
+
+	config := DefaultConfigClientConfig()
+	config.Enabled = true
+	config.ServerURL = server.URL
+	config.Application = "app"
+	config.Profile = "prod"
+	config.Label = "release(_)1.2"
+	config.RetryCount = 0
+
+	client, err := NewConfigClient(config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfigClient() error = %v", err)
+	}
+	if err := client.Refresh(); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	for _, call := range []string{"initial fetch", "Refresh()"} {
+		if got := <-paths; got != "/config/app/prod/release(_)1.2" {
+			t.Errorf("%s path = %v, want /config/app/prod/release(_)1.2", call, got)
+		}
+	}
+}
+
+func TestConfigClient_Label_Empty(t *testing.T) {
+	client := newDisabledClient(t)
+	client.config.ServerURL = "http://config"
+
+	if got := client.configURL(); got != "http://config/config/application/default" {
+		t.Errorf("configURL() = %v, want no label segment", got)
+	}
+}
+
+func TestNewConfigClient_InvalidLabel(t *testing.T) {
+	for _, label := range []string{"feature/login", "v1 beta", "main?x=1", "a#b"} {
+		config := DefaultConfigClientConfig()
+		config.Label = label
+		if _, err := NewConfigClient(config, zap.NewNop()); err == nil {
+			t.Errorf("NewConfigClient() with label %q should fail", label)
+		}
+	}
+}
+
 func TestConfigClient_RefreshConfig(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost && r.URL.Path == "/refresh" {
@@ -614,3 +968,36 @@ func TestConfigResponse_Struct(t *testing.T) {
 		t.Errorf("PropertySources length = %v, want 1", len(cr.PropertySources))
 	}
 }
+
+func TestConfigClient_Ping(t *testing.T) {
+	if err := newDisabledClient(t).Ping(context.Background()); err != nil {
+		t.Errorf("Ping() on a disabled client error = %v, want nil", err)
+	}
+
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" && !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(ConfigResponse{})
+	}))
+	defer server.Close()
+
+	config := DefaultConfigClientConfig()
+	config.Enabled = true
+	config.ServerURL = server.URL
+	config.RetryCount = 0
+	client, err := NewConfigClient(config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfigClient() error = %v", err)
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v, want nil", err)
+	}
+	healthy = false
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("Ping() should fail when the server is unhealthy")
+	}
+}
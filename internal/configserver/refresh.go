@@ -0,0 +1,100 @@
+package configserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// RefreshSecretHeader carries the shared secret that authorizes an
+// on-demand refresh through NewRefreshHandler
+const RefreshSecretHeader = "X-Config-Refresh-Secret"
+
+// RefreshResult reports what an on-demand refresh changed
+type RefreshResult struct {
+	Changed  bool     `json:"changed"`
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// RefreshWithChanges refreshes configuration from the server and reports
+// which top-level keys were added, removed or modified
+func (c *ConfigClient) RefreshWithChanges() (*RefreshResult, error) {
+	before := c.GetAll()
+	if err := c.Refresh(); err != nil {
+		return nil, err
+	}
+	return diffConfig(before, c.GetAll()), nil
+}
+
+// diffConfig compares two config snapshots, listing keys in sorted order
+func diffConfig(before, after map[string]interface{}) *RefreshResult {
+	result := &RefreshResult{
+		Changed:  !configEqual(before, after),
+		Added:    []string{},
+		Removed:  []string{},
+		Modified: []string{},
+	}
+	for k, v := range after {
+		old, ok := before[k]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, k)
+		case !reflect.DeepEqual(old, v):
+			result.Modified = append(result.Modified, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			result.Removed = append(result.Removed, k)
+		}
+	}
+	slices.Sort(result.Added)
+	slices.Sort(result.Removed)
+	slices.Sort(result.Modified)
+	return result
+}
+
+// NewRefreshHandler returns a handler for POST /actuator/refresh that
+// refreshes the client on demand, e.g. from a CI pipeline after pushing
+// config. Callers must send the client's RefreshSecret in
+// RefreshSecretHeader; with no secret configured every request is rejected.
+func NewRefreshHandler(client *ConfigClient) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, msgMethodNotAllowed, http.StatusMethodNotAllowed)
+			return
+		}
+
+		secret := client.config.RefreshSecret
+		provided := r.Header.Get(RefreshSecretHeader)
+		if secret == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(secret)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		result, err := client.RefreshWithChanges()
+		if err != nil {
+			client.logger.Warn("On-demand config refresh failed", zap.Error(err))
+			http.Error(w, "Refresh failed: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		client.logger.Info("Config refreshed on demand",
+			zap.Bool("changed", result.Changed),
+			zap.Strings("added", result.Added),
+			zap.Strings("removed", result.Removed),
+			zap.Strings("modified", result.Modified),
+		)
+
+		w.Header().Set(headerContentType, contentTypeJSON)
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			client.logger.Error(msgFailedToEncode, zap.Error(err))
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package configserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// newChangingClient returns a client whose server serves initial on the first
+// fetch and updated on every later one
+func newChangingClient(t *testing.T, initial, updated map[string]interface{}) *ConfigClient {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		source := updated
+		if calls.Add(1) == 1 {
+			source = initial
+		}
+		json.NewEncoder(w).Encode(ConfigResponse{
+			Name:            "app",
+			PropertySources: []PropertySource{{Name: "app", Source: source}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	config := DefaultConfigClientConfig()
+	config.Enabled = true
+	config.ServerURL = server.URL
+	config.RetryCount = 0
+	config.RefreshSecret = "s3cret"
+	client, err := NewConfigClient(config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewConfigClient() error = %v", err)
+	}
+	return client
+}
+
+func TestDiffConfig(t *testing.T) {
+	before := map[string]interface{}{
+		"same":    "a",
+		"changed": "old",
+		"gone":    1,
+		"nested":  map[string]interface{}{"k": "v"},
+	}
+	after := map[string]interface{}{
+		"same":    "a",
+		"changed": "new",
+		"new":     true,
+		"nested":  map[string]interface{}{"k": "v2"},
+	}
+
+	got := diffConfig(before, after)
+	want := &RefreshResult{
+		Changed:  true,
+		Added:    []string{"new"},
+		Removed:  []string{"gone"},
+		Modified: []string{"changed", "nested"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffConfig() = %+v, want %+v", got, want)
+	}
+
+	unchanged := diffConfig(before, before)
+	if unchanged.Changed || len(unchanged.Added)+len(unchanged.Removed)+len(unchanged.Modified) != 0 {
+		t.Errorf("diffConfig() of identical configs = %+v, want no changes", unchanged)
+	}
+}
+
+func TestRefreshHandler(t *testing.T) {
+	client := newChangingClient(t,
+		map[string]interface{}{"keep": "1", "drop": "2", "edit": "3"},
+		map[string]interface{}{"keep": "1", "edit": "4", "add": "5"},
+	)
+	handler := NewRefreshHandler(client)
+
+	req := httptest.NewRequest(http.MethodPost, "/actuator/refresh", nil)
+	req.Header.Set(RefreshSecretHeader, "s3cret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %v, want 200; body %s", w.Code, w.Body.String())
+	}
+	var result RefreshResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := RefreshResult{Changed: true, Added: []string{"add"}, Removed: []string{"drop"}, Modified: []string{"edit"}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+	if got := client.GetString("add", ""); got != "5" {
+		t.Errorf("add = %v, want 5 after refresh", got)
+	}
+
+	// A second refresh finds nothing new
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	result = RefreshResult{}
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.Changed {
+		t.Errorf("second refresh result = %+v, want unchanged", result)
+	}
+}
+
+func TestRefreshHandler_Unauthorized(t *testing.T) {
+	client := newChangingClient(t, map[string]interface{}{"k": "v"}, map[string]interface{}{"k": "v2"})
+
+	tests := []struct {
+		name       string
+		configured string
+		provided   string
+		setHeader  bool
+	}{
+		{"missing header", "s3cret", "", false},
+		{"wrong secret", "s3cret", "guess", true},
+		{"no secret configured", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client.config.RefreshSecret = tt.configured
+			req := httptest.NewRequest(http.MethodPost, "/actuator/refresh", nil)
+			if tt.setHeader {
+				req.Header.Set(RefreshSecretHeader, tt.provided)
+			}
+			w := httptest.NewRecorder()
+			NewRefreshHandler(client).ServeHTTP(w, req)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %v, want 401", w.Code)
+			}
+			if got := client.GetString("k", ""); got != "v" {
+				t.Errorf("k = %v, want v (no refresh)", got)
+			}
+		})
+	}
+}
+
+func TestRefreshHandler_MethodNotAllowed(t *testing.T) {
+	client := newDisabledClient(t)
+	client.config.RefreshSecret = "s3cret"
+
+	req := httptest.NewRequest(http.MethodGet, "/actuator/refresh", nil)
+	req.Header.Set(RefreshSecretHeader, "s3cret")
+	w := httptest.NewRecorder()
+	NewRefreshHandler(client).ServeHTTP(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %v, want 405", w.Code)
+	}
+}
+
+func TestRefreshHandler_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newDisabledClient(t)
+	client.config.ServerURL = server.URL
+	client.config.RetryCount = 0
+	client.config.RefreshSecret = "s3cret"
+
+	req := httptest.NewRequest(http.MethodPost, "/actuator/refresh", nil)
+	req.Header.Set(RefreshSecretHeader, "s3cret")
+	w := httptest.NewRecorder()
+	NewRefreshHandler(client).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("status = %v, want 502", w.Code)
+	}
+}
@@ -86,7 +86,15 @@ func (r *Resolver) Login(p graphql.ResolveParams) (interface{}, error) {
 		Password:        input["password"].(string),
 	}
 
-	return r.authService.Login(p.Context, req)
+	resp, err := r.authService.Login(p.Context, req)
+	if err != nil {
+		return nil, err
+	}
+	// The second factor can only be completed over REST
+	if resp.TwoFactorRequired {
+		return nil, errors.New("two-factor authentication required")
+	}
+	return resp, nil
 }
 
 // RefreshToken handles token refresh
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.uber.org/zap"
@@ -102,6 +103,9 @@ func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRe
 	}
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrConcurrentModification) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
 		s.logger.Error("failed to update user", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, "failed to update user: %v", err)
 	}
@@ -83,6 +83,26 @@ func (m *mockUserRepository) List(ctx context.Context, page, size int) ([]*entit
 	return []*entity.User{newTestUser()}, 1, nil
 }
 
+func (m *mockUserRepository) ListIncludingDeleted(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *mockUserRepository) ListAfter(ctx context.Context, cursor string, limit int) ([]*entity.User, string, error) {
+	return nil, "", nil
+}
+
+func (m *mockUserRepository) GetByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error) {
+	return nil, nil
+}
+
+func (m *mockUserRepository) Restore(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (m *mockUserRepository) Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error) {
+	return nil, 0, nil
+}
+
 func (m *mockUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	if m.existsByUsernameFn != nil {
 		return m.existsByUsernameFn(ctx, username)
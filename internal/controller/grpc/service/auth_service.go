@@ -67,6 +67,10 @@ func (s *AuthServiceServer) Login(ctx context.Context, req *pb.LoginRequest) (*p
 		s.logger.Error("failed to login", zap.Error(err))
 		return nil, s.mapError(err)
 	}
+	// The second factor can only be completed over HTTP
+	if authResp.TwoFactorRequired {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor authentication required")
+	}
 
 	return s.toProtoAuthResponse(authResp), nil
 }
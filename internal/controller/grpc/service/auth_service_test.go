@@ -97,6 +97,42 @@ func (m *mockAuthService) LogoutAll(ctx context.Context, userID uint) error {
 	return nil
 }
 
+func (m *mockAuthService) RequestPasswordReset(ctx context.Context, email string) (*domainservice.PasswordReset, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	return nil
+}
+
+func (m *mockAuthService) SendVerification(ctx context.Context, userID uint) (*domainservice.EmailVerification, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	return nil
+}
+
+func (m *mockAuthService) EnableTOTP(ctx context.Context, userID uint) (*domainservice.TOTPSetup, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) ConfirmTOTP(ctx context.Context, userID uint, code string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) VerifyTOTP(ctx context.Context, challengeID, code string) (*response.AuthResponse, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) ListSessions(ctx context.Context, userID uint) ([]*response.SessionResponse, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) RevokeSession(ctx context.Context, userID, sessionID uint) error {
+	return nil
+}
+
 func TestNewAuthServiceServer(t *testing.T) {
 	logger := newTestLogger()
 	jwtProvider := newTestJWT()
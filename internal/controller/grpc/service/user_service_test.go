@@ -63,6 +63,22 @@ func (m *mockUserService) List(ctx context.Context, page, size int) (*response.P
 	}, nil
 }
 
+func (m *mockUserService) ListIncludingDeleted(ctx context.Context, page, size int) (*response.PagedResponse[response.UserResponse], error) {
+	return nil, nil
+}
+
+func (m *mockUserService) ListByCursor(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.UserResponse], error) {
+	return nil, nil
+}
+
+func (m *mockUserService) Restore(ctx context.Context, id uint) (*response.UserResponse, error) {
+	return nil, nil
+}
+
+func (m *mockUserService) Search(ctx context.Context, req *request.UserSearchRequest) (*response.PagedResponse[response.UserResponse], error) {
+	return nil, nil
+}
+
 func (m *mockUserService) Update(ctx context.Context, id uint, req *request.UpdateProfileRequest) (*response.UserResponse, error) {
 	if m.updateFn != nil {
 		return m.updateFn(ctx, id, req)
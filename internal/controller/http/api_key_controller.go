@@ -0,0 +1,128 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+)
+
+// APIKeyController handles API key management endpoints
+type APIKeyController struct {
+	apiKeyService   service.APIKeyService
+	securityService *security.SecurityService
+	authMiddleware  *middleware.AuthMiddleware
+}
+
+// NewAPIKeyController creates a new APIKeyController instance
+func NewAPIKeyController(
+	apiKeyService service.APIKeyService,
+	securityService *security.SecurityService,
+	authMiddleware *middleware.AuthMiddleware,
+) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService:   apiKeyService,
+		securityService: securityService,
+		authMiddleware:  authMiddleware,
+	}
+}
+
+// RegisterRoutes registers the API key routes. Keys are managed by admins
+// signed in with a token; API keys cannot manage keys.
+func (c *APIKeyController) RegisterRoutes(router *gin.RouterGroup) {
+	apiKeys := router.Group("/api-keys")
+	apiKeys.Use(c.authMiddleware.Authenticate(), c.authMiddleware.RequireAdmin())
+	{
+		apiKeys.POST("", c.Create)
+		apiKeys.GET("", c.List)
+		apiKeys.DELETE("/:id", c.Revoke)
+	}
+}
+
+// Create creates a new API key
+// @Summary Create an API key
+// @Description The key is returned only in this response
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.CreateAPIKeyRequest true "API key request"
+// @Success 201 {object} response.ApiResponse[response.APIKeyCreatedResponse]
+// @Router /api/v1/api-keys [post]
+func (c *APIKeyController) Create(ctx *gin.Context) {
+	var req request.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+
+	userID := c.securityService.GetCurrentUserID(ctx)
+	apiKey, err := c.apiKeyService.Create(ctx.Request.Context(), userID, &req)
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to create API key"))
+		return
+	}
+
+	respond(ctx, http.StatusCreated, response.NewSuccess(apiKey, "API key created; store it now, it will not be shown again"))
+}
+
+// List retrieves API keys with pagination. Only key prefixes are returned.
+// @Summary List API keys
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10)
+// @Success 200 {object} response.ApiResponse[response.PagedResponse[response.APIKeyResponse]]
+// @Router /api/v1/api-keys [get]
+func (c *APIKeyController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(ctx.DefaultQuery("size", "10"))
+
+	apiKeys, err := c.apiKeyService.List(ctx.Request.Context(), page, size)
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to fetch API keys"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(apiKeys))
+}
+
+// Revoke revokes an API key
+// @Summary Revoke an API key
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 200 {object} response.ApiResponse[any]
+// @Router /api/v1/api-keys/{id} [delete]
+func (c *APIKeyController) Revoke(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid API key ID"))
+		return
+	}
+
+	if err := c.apiKeyService.Revoke(ctx.Request.Context(), uint(id)); err != nil {
+		switch err {
+		case service.ErrAPIKeyNotFound:
+			respond(ctx, http.StatusNotFound, response.NewError[any]("API key not found"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to revoke API key"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "API key revoked"))
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+)
+
+const msgAuditFailed = "failed to record audit log"
+
+// auditRecorder records the audited actions of the controller embedding it.
+// Without an audit service nothing is recorded.
+type auditRecorder struct {
+	auditService service.AuditService
+}
+
+// SetAuditService sets the service recording security-relevant actions
+func (a *auditRecorder) SetAuditService(auditService service.AuditService) {
+	a.auditService = auditService
+}
+
+// audit records action on target by the authenticated caller. opErr is the
+// action's error, if it failed.
+func (a *auditRecorder) audit(ctx *gin.Context, action, target string, opErr error) error {
+	actorID, actor := auditActor(ctx)
+	return a.auditAs(ctx, actorID, actor, action, target, opErr)
+}
+
+// auditAs records action on target by the given actor, for actions taken
+// before the caller is authenticated
+func (a *auditRecorder) auditAs(ctx *gin.Context, actorID uint, actor, action, target string, opErr error) error {
+	if opErr != nil {
+		return a.record(ctx, actorID, actor, action, target, entity.AuditOutcomeFailure, opErr.Error())
+	}
+	return a.record(ctx, actorID, actor, action, target, entity.AuditOutcomeSuccess, "")
+}
+
+// auditAttempt records that the authenticated caller is about to take an
+// action that cannot be undone, responding with an error when the record
+// cannot be stored and the audit service is configured to fail. It reports
+// whether the handler may go on with the action, whose outcome is then
+// recorded with audit.
+func (a *auditRecorder) auditAttempt(ctx *gin.Context, action, target string) bool {
+	actorID, actor := auditActor(ctx)
+	return a.auditStored(ctx, a.record(ctx, actorID, actor, action, target, entity.AuditOutcomeAttempted, ""))
+}
+
+// auditSuccessAs records a successful action taken before the caller was
+// authenticated, responding with an error when the record cannot be stored
+// and the audit service is configured to fail. It reports whether the
+// handler may go on to respond; if not, the handler must undo the action.
+func (a *auditRecorder) auditSuccessAs(ctx *gin.Context, actorID uint, actor, action, target string) bool {
+	return a.auditStored(ctx, a.auditAs(ctx, actorID, actor, action, target, nil))
+}
+
+func (a *auditRecorder) auditStored(ctx *gin.Context, err error) bool {
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any](msgAuditFailed))
+		return false
+	}
+	return true
+}
+
+func (a *auditRecorder) record(ctx *gin.Context, actorID uint, actor, action, target string, outcome entity.AuditOutcome, detail string) error {
+	if a.auditService == nil {
+		return nil
+	}
+
+	return a.auditService.Record(ctx.Request.Context(), &entity.AuditLog{
+		ActorID:   actorID,
+		Actor:     actor,
+		Action:    action,
+		Target:    target,
+		IPAddress: ctx.ClientIP(),
+		RequestID: middleware.GetRequestID(ctx),
+		Outcome:   outcome,
+		Detail:    detail,
+	})
+}
+
+// auditActor returns the authenticated caller
+func auditActor(ctx *gin.Context) (uint, string) {
+	if claims, ok := ctx.Get(security.ContextKeyClaims); ok {
+		if userClaims, ok := claims.(*security.UserClaims); ok {
+			return userClaims.UserID, userClaims.Username
+		}
+	}
+	return 0, ""
+}
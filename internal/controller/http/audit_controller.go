@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+)
+
+// AuditController handles audit log endpoints
+type AuditController struct {
+	auditService   service.AuditService
+	authMiddleware *middleware.AuthMiddleware
+	ipFilter       gin.HandlerFunc
+}
+
+// NewAuditController creates a new AuditController instance
+func NewAuditController(auditService service.AuditService, authMiddleware *middleware.AuthMiddleware) *AuditController {
+	return &AuditController{
+		auditService:   auditService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// SetIPFilter restricts the audit log routes to the networks the filter allows
+func (c *AuditController) SetIPFilter(filter gin.HandlerFunc) {
+	c.ipFilter = filter
+}
+
+// RegisterRoutes registers the audit log routes
+func (c *AuditController) RegisterRoutes(router *gin.RouterGroup) {
+	admin := router.Group("/admin")
+	if c.ipFilter != nil {
+		admin.Use(c.ipFilter)
+	}
+	admin.Use(c.authMiddleware.Authenticate())
+	{
+		admin.GET("/audit", c.authMiddleware.RequirePermission(security.PermissionAuditRead), c.Query)
+	}
+}
+
+// Query retrieves audit logs matching filters
+// @Summary Query the audit log
+// @Description Filters combine with AND; records are returned newest first
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query int false "Acting user ID"
+// @Param actor query string false "Actor username, or the login attempted"
+// @Param action query string false "Action" Enums(auth.login, auth.logout, auth.logout_all, plugin.install, plugin.uninstall, jobs.dlq_purge)
+// @Param from query string false "Recorded at or after (RFC 3339)"
+// @Param to query string false "Recorded before (RFC 3339)"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size, at most 100" default(20)
+// @Success 200 {object} response.ApiResponse[response.PagedResponse[response.AuditLogResponse]]
+// @Failure 400 {object} response.ApiResponse[any]
+// @Failure 403 {object} response.ApiResponse[any]
+// @Router /api/v1/admin/audit [get]
+func (c *AuditController) Query(ctx *gin.Context) {
+	var req request.AuditQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+
+	logs, err := c.auditService.Query(ctx.Request.Context(), &req)
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to query audit log"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(logs))
+}
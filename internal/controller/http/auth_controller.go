@@ -1,33 +1,56 @@
 package http
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs/handler"
+	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
 const msgValidationFailed = "validation failed"
 
+// auditTarget2FA marks logins completed with a two-factor challenge
+const auditTarget2FA = "2fa"
+
 // AuthController handles authentication endpoints
 type AuthController struct {
+	auditRecorder
 	authService     service.AuthService
 	securityService *security.SecurityService
+	authMiddleware  *middleware.AuthMiddleware
+	jobService      jobs.Service
 }
 
 // NewAuthController creates a new AuthController instance
-func NewAuthController(authService service.AuthService, securityService *security.SecurityService) *AuthController {
+func NewAuthController(
+	authService service.AuthService,
+	securityService *security.SecurityService,
+	authMiddleware *middleware.AuthMiddleware,
+) *AuthController {
 	return &AuthController{
 		authService:     authService,
 		securityService: securityService,
+		authMiddleware:  authMiddleware,
 	}
 }
 
+// SetJobService sets the job service used to send password reset and
+// verification emails. Without it tokens are issued but never delivered.
+func (c *AuthController) SetJobService(jobService jobs.Service) {
+	c.jobService = jobService
+}
+
 // RegisterRoutes registers the auth routes
 func (c *AuthController) RegisterRoutes(router *gin.RouterGroup) {
 	auth := router.Group("/auth")
@@ -35,8 +58,17 @@ func (c *AuthController) RegisterRoutes(router *gin.RouterGroup) {
 		auth.POST("/register", c.Register)
 		auth.POST("/login", c.Login)
 		auth.POST("/refresh", c.RefreshToken)
-		auth.POST("/logout", c.Logout)
-		auth.POST("/logout-all", c.LogoutAll)
+		auth.POST("/logout", c.authMiddleware.OptionalAuth(), c.Logout)
+		auth.POST("/logout-all", c.authMiddleware.Authenticate(), c.LogoutAll)
+		auth.POST("/password-reset/request", c.RequestPasswordReset)
+		auth.POST("/password-reset/confirm", c.ConfirmPasswordReset)
+		auth.POST("/verify/send", c.authMiddleware.Authenticate(), c.SendVerification)
+		auth.GET("/verify/confirm", c.VerifyEmail)
+		auth.POST("/2fa/enable", c.authMiddleware.Authenticate(), c.EnableTOTP)
+		auth.POST("/2fa/confirm", c.authMiddleware.Authenticate(), c.ConfirmTOTP)
+		auth.POST("/2fa/verify", c.VerifyTOTP)
+		auth.GET("/sessions", c.authMiddleware.Authenticate(), c.ListSessions)
+		auth.DELETE("/sessions/:id", c.authMiddleware.Authenticate(), c.RevokeSession)
 	}
 }
 
@@ -53,26 +85,30 @@ func (c *AuthController) RegisterRoutes(router *gin.RouterGroup) {
 func (c *AuthController) Register(ctx *gin.Context) {
 	var req request.RegisterRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
 		return
 	}
 
-	authResp, err := c.authService.Register(ctx.Request.Context(), &req)
+	authResp, err := c.authService.Register(clientContext(ctx), &req)
 	if err != nil {
 		switch err {
 		case service.ErrUserAlreadyExists:
-			ctx.JSON(http.StatusConflict, response.NewError[any]("user already exists"))
+			respond(ctx, http.StatusConflict, response.NewError[any]("user already exists"))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("registration failed"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("registration failed"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, response.NewSuccess(authResp, "User registered successfully"))
+	respond(ctx, http.StatusCreated, response.NewSuccess(authResp, "User registered successfully"))
 }
 
 // Login handles user login
 // @Summary Login with username/email and password
+// @Description If two-factor authentication is enabled no tokens are issued; complete the returned challenge at /api/v1/auth/2fa/verify
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -84,24 +120,39 @@ func (c *AuthController) Register(ctx *gin.Context) {
 func (c *AuthController) Login(ctx *gin.Context) {
 	var req request.LoginRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
 		return
 	}
 
-	authResp, err := c.authService.Login(ctx.Request.Context(), &req)
+	authResp, err := c.authService.Login(clientContext(ctx), &req)
 	if err != nil {
+		_ = c.auditAs(ctx, 0, req.UsernameOrEmail, entity.AuditActionLogin, "", err)
 		switch err {
 		case service.ErrInvalidCredentials:
-			ctx.JSON(http.StatusUnauthorized, response.NewError[any]("invalid credentials"))
+			respond(ctx, http.StatusUnauthorized, response.NewError[any]("invalid credentials"))
 		case service.ErrUserInactive:
-			ctx.JSON(http.StatusUnauthorized, response.NewError[any]("account is inactive"))
+			respond(ctx, http.StatusUnauthorized, response.NewError[any]("account is inactive"))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("login failed"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("login failed"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess(authResp, "Login successful"))
+	// A two-factor login is audited once the challenge is completed
+	if authResp.TwoFactorRequired {
+		respond(ctx, http.StatusOK, response.NewSuccess(authResp, "Two-factor authentication required"))
+		return
+	}
+
+	if !c.auditSuccessAs(ctx, authResp.User.ID, authResp.User.Username, entity.AuditActionLogin, "") {
+		c.revokeIssuedTokens(ctx, authResp)
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess(authResp, "Login successful"))
 }
 
 // RefreshToken handles token refresh
@@ -117,22 +168,25 @@ func (c *AuthController) Login(ctx *gin.Context) {
 func (c *AuthController) RefreshToken(ctx *gin.Context) {
 	var req request.RefreshTokenRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
 		return
 	}
 
-	authResp, err := c.authService.RefreshToken(ctx.Request.Context(), &req)
+	authResp, err := c.authService.RefreshToken(clientContext(ctx), &req)
 	if err != nil {
 		switch err {
 		case service.ErrInvalidToken:
-			ctx.JSON(http.StatusUnauthorized, response.NewError[any]("invalid or expired refresh token"))
+			respond(ctx, http.StatusUnauthorized, response.NewError[any]("invalid or expired refresh token"))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("token refresh failed"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("token refresh failed"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess(authResp, "Token refreshed successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess(authResp, "Token refreshed successfully"))
 }
 
 // Logout handles user logout
@@ -148,9 +202,10 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 	if bearerToken != "" && strings.HasPrefix(bearerToken, "Bearer ") {
 		token := strings.TrimPrefix(bearerToken, "Bearer ")
 		_ = c.authService.Logout(ctx.Request.Context(), token)
+		_ = c.audit(ctx, entity.AuditActionLogout, "", nil)
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "Logged out successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Logged out successfully"))
 }
 
 // LogoutAll handles logout from all sessions
@@ -164,8 +219,379 @@ func (c *AuthController) Logout(ctx *gin.Context) {
 func (c *AuthController) LogoutAll(ctx *gin.Context) {
 	userID := c.securityService.GetCurrentUserID(ctx)
 	if userID > 0 {
-		_ = c.authService.LogoutAll(ctx.Request.Context(), userID)
+		err := c.authService.LogoutAll(ctx.Request.Context(), userID)
+		_ = c.audit(ctx, entity.AuditActionLogoutAll, "", err)
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "All sessions logged out successfully"))
+}
+
+// RequestPasswordReset handles password reset requests
+// @Summary Request a password reset email
+// @Description Always succeeds so that the response does not reveal whether the email is registered
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body request.PasswordResetRequest true "Password reset request"
+// @Success 200 {object} response.ApiResponse[any]
+// @Failure 400 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/password-reset/request [post]
+func (c *AuthController) RequestPasswordReset(ctx *gin.Context) {
+	var req request.PasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+
+	reset, err := c.authService.RequestPasswordReset(ctx.Request.Context(), req.Email)
+	if err == nil && reset != nil {
+		_ = c.sendEmail(ctx.Request.Context(), handler.EmailJobPayload{
+			To:           []string{reset.Email},
+			Subject:      "Reset your password",
+			TemplateName: "password_reset",
+			TemplateData: map[string]any{
+				"token":      reset.Token,
+				"expires_at": reset.ExpiresAt,
+			},
+		})
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "If the email is registered, a password reset link has been sent"))
+}
+
+// ConfirmPasswordReset handles setting a new password with a reset token
+// @Summary Reset password using a reset token
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body request.PasswordResetConfirmRequest true "Password reset confirmation"
+// @Success 200 {object} response.ApiResponse[any]
+// @Failure 400 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/password-reset/confirm [post]
+func (c *AuthController) ConfirmPasswordReset(ctx *gin.Context) {
+	var req request.PasswordResetConfirmRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+
+	err := c.authService.ResetPassword(ctx.Request.Context(), req.Token, req.NewPassword)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidToken:
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid or expired reset token"))
+		case service.ErrUserInactive:
+			respond(ctx, http.StatusUnauthorized, response.NewError[any]("account is inactive"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("password reset failed"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Password reset successfully"))
+}
+
+// SendVerification handles sending an email verification link
+// @Summary Send an email verification link to the current user
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.ApiResponse[any]
+// @Failure 401 {object} response.ApiResponse[any]
+// @Failure 409 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/verify/send [post]
+func (c *AuthController) SendVerification(ctx *gin.Context) {
+	userID := c.securityService.GetCurrentUserID(ctx)
+	if userID == 0 {
+		respond(ctx, http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
+		return
+	}
+
+	verification, err := c.authService.SendVerification(ctx.Request.Context(), userID)
+	if err != nil {
+		switch err {
+		case service.ErrAlreadyVerified:
+			respond(ctx, http.StatusConflict, response.NewError[any]("email already verified"))
+		case service.ErrUserNotFound:
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgUserNotFound))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to send verification email"))
+		}
+		return
+	}
+
+	err = c.sendEmail(ctx.Request.Context(), handler.EmailJobPayload{
+		To:           []string{verification.Email},
+		Subject:      "Verify your email address",
+		TemplateName: "email_verification",
+		TemplateData: map[string]any{
+			"token":      verification.Token,
+			"expires_at": verification.ExpiresAt,
+		},
+	})
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to send verification email"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Verification email sent"))
+}
+
+// VerifyEmail handles email verification links
+// @Summary Verify email address using a verification token
+// @Tags Authentication
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} response.ApiResponse[any]
+// @Failure 400 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/verify/confirm [get]
+func (c *AuthController) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("verification token required"))
+		return
+	}
+
+	if err := c.authService.VerifyEmail(ctx.Request.Context(), token); err != nil {
+		switch err {
+		case service.ErrInvalidToken:
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid or expired verification token"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("email verification failed"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Email verified successfully"))
+}
+
+// EnableTOTP starts two-factor enrollment
+// @Summary Generate a TOTP secret for the current user
+// @Description Two-factor authentication takes effect once confirmed at /api/v1/auth/2fa/confirm
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.ApiResponse[response.TOTPSetupResponse]
+// @Failure 401 {object} response.ApiResponse[any]
+// @Failure 409 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/2fa/enable [post]
+func (c *AuthController) EnableTOTP(ctx *gin.Context) {
+	userID := c.securityService.GetCurrentUserID(ctx)
+	if userID == 0 {
+		respond(ctx, http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
+		return
+	}
+
+	setup, err := c.authService.EnableTOTP(ctx.Request.Context(), userID)
+	if err != nil {
+		switch err {
+		case service.ErrTOTPAlreadyEnabled:
+			respond(ctx, http.StatusConflict, response.NewError[any]("two-factor authentication already enabled"))
+		case service.ErrUserNotFound:
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgUserNotFound))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to enable two-factor authentication"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess(response.TOTPSetupResponse{
+		Secret:     setup.Secret,
+		OTPAuthURL: setup.URL,
+	}, "Scan the code with your authenticator app and confirm"))
+}
+
+// ConfirmTOTP activates two-factor authentication
+// @Summary Confirm two-factor enrollment with a TOTP code
+// @Description Returns single-use recovery codes, which are shown only once
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.TOTPConfirmRequest true "TOTP code"
+// @Success 200 {object} response.ApiResponse[response.TOTPRecoveryCodesResponse]
+// @Failure 400 {object} response.ApiResponse[any]
+// @Failure 401 {object} response.ApiResponse[any]
+// @Failure 409 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/2fa/confirm [post]
+func (c *AuthController) ConfirmTOTP(ctx *gin.Context) {
+	userID := c.securityService.GetCurrentUserID(ctx)
+	if userID == 0 {
+		respond(ctx, http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
+		return
+	}
+
+	var req request.TOTPConfirmRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+
+	recoveryCodes, err := c.authService.ConfirmTOTP(ctx.Request.Context(), userID, req.Code)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidTOTPCode:
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid two-factor authentication code"))
+		case service.ErrTOTPNotPending:
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("two-factor authentication setup not started"))
+		case service.ErrTOTPAlreadyEnabled:
+			respond(ctx, http.StatusConflict, response.NewError[any]("two-factor authentication already enabled"))
+		case service.ErrUserNotFound:
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgUserNotFound))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to enable two-factor authentication"))
+		}
+		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "All sessions logged out successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess(response.TOTPRecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, "Two-factor authentication enabled"))
+}
+
+// VerifyTOTP completes a two-factor login
+// @Summary Complete a two-factor login challenge
+// @Description Accepts a TOTP code or a single-use recovery code
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body request.TOTPVerifyRequest true "Challenge and code"
+// @Success 200 {object} response.ApiResponse[response.AuthResponse]
+// @Failure 400 {object} response.ApiResponse[any]
+// @Failure 401 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/2fa/verify [post]
+func (c *AuthController) VerifyTOTP(ctx *gin.Context) {
+	var req request.TOTPVerifyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+
+	authResp, err := c.authService.VerifyTOTP(clientContext(ctx), req.ChallengeID, req.Code)
+	if err != nil {
+		_ = c.auditAs(ctx, 0, "", entity.AuditActionLogin, auditTarget2FA, err)
+		switch err {
+		case service.ErrInvalidTOTPCode:
+			respond(ctx, http.StatusUnauthorized, response.NewError[any]("invalid two-factor authentication code"))
+		case service.ErrInvalidToken:
+			respond(ctx, http.StatusUnauthorized, response.NewError[any]("invalid or expired challenge"))
+		case service.ErrUserInactive:
+			respond(ctx, http.StatusUnauthorized, response.NewError[any]("account is inactive"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("two-factor verification failed"))
+		}
+		return
+	}
+
+	if !c.auditSuccessAs(ctx, authResp.User.ID, authResp.User.Username, entity.AuditActionLogin, auditTarget2FA) {
+		c.revokeIssuedTokens(ctx, authResp)
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess(authResp, "Login successful"))
+}
+
+// revokeIssuedTokens revokes the tokens of a login that could not be audited.
+// They were never handed out, so the login leaves no usable session.
+func (c *AuthController) revokeIssuedTokens(ctx *gin.Context, authResp *response.AuthResponse) {
+	reqCtx := context.WithoutCancel(ctx.Request.Context())
+	_ = c.authService.Logout(reqCtx, authResp.RefreshToken)
+	_ = c.authService.Logout(reqCtx, authResp.AccessToken)
+}
+
+// ListSessions lists the current user's sessions
+// @Summary List active sessions
+// @Description Each session is a signed-in device; the one making the request is marked current
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.ApiResponse[[]response.SessionResponse]
+// @Failure 401 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/sessions [get]
+func (c *AuthController) ListSessions(ctx *gin.Context) {
+	claims := c.securityService.GetCurrentClaims(ctx)
+	if claims == nil || claims.UserID == 0 {
+		respond(ctx, http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
+		return
+	}
+
+	sessions, err := c.authService.ListSessions(ctx.Request.Context(), claims.UserID)
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to fetch sessions"))
+		return
+	}
+	for _, session := range sessions {
+		session.Current = claims.SessionID != 0 && session.ID == claims.SessionID
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(sessions))
+}
+
+// RevokeSession signs out one of the current user's sessions
+// @Summary Revoke a session
+// @Description The session can no longer be refreshed; its current access token stays valid until it expires
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} response.ApiResponse[any]
+// @Failure 400 {object} response.ApiResponse[any]
+// @Failure 401 {object} response.ApiResponse[any]
+// @Failure 404 {object} response.ApiResponse[any]
+// @Router /api/v1/auth/sessions/{id} [delete]
+func (c *AuthController) RevokeSession(ctx *gin.Context) {
+	userID := c.securityService.GetCurrentUserID(ctx)
+	if userID == 0 {
+		respond(ctx, http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid session ID"))
+		return
+	}
+
+	if err := c.authService.RevokeSession(ctx.Request.Context(), userID, uint(sessionID)); err != nil {
+		switch err {
+		case service.ErrSessionNotFound:
+			respond(ctx, http.StatusNotFound, response.NewError[any]("session not found"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to revoke session"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Session revoked"))
+}
+
+// clientContext returns the request context carrying the client's details,
+// which are recorded on any session it starts
+func clientContext(ctx *gin.Context) context.Context {
+	return service.ContextWithClientInfo(ctx.Request.Context(), service.ClientInfo{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	})
+}
+
+// sendEmail enqueues an email job
+func (c *AuthController) sendEmail(ctx context.Context, email handler.EmailJobPayload) error {
+	if c.jobService == nil {
+		return nil
+	}
+	_, err := c.jobService.Enqueue(ctx, "email", email, jobs.WithPriority(jobs.PriorityHigh))
+	return err
 }
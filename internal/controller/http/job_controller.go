@@ -2,27 +2,65 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/scheduler"
 	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
-const msgJobIDRequired = "job ID required"
+const (
+	msgJobIDRequired        = "job ID required"
+	msgJobTypeRequired      = "job type required"
+	msgScheduledJobNotFound = "scheduled job not found"
+
+	// auditTargetDLQ is the audit target of dead letter queue operations
+	auditTargetDLQ = "dlq"
+
+	// maxBatchSize caps the number of jobs accepted by a single batch enqueue
+	maxBatchSize = 10000
+
+	// API key scopes for the job endpoints
+	scopeJobsRead  = "jobs:read"
+	scopeJobsWrite = "jobs:write"
+)
+
+var (
+	errInvalidScheduledAt = errors.New("invalid scheduled_at format, use RFC3339")
+	errInvalidPayload     = errors.New("invalid payload JSON")
+	errUniqueForNoKey     = errors.New("unique_for_seconds requires unique_key")
+	errInvalidQueue       = errors.New("invalid queue name, use 1-64 lowercase letters, digits, '-' or '_'")
+
+	// queueNamePattern restricts queue names to characters safe in Redis keys
+	queueNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+	// restrictedJobTypes maps job types that destroy data to the permission
+	// needed to enqueue them by hand. The scheduler enqueues them directly.
+	restrictedJobTypes = map[string]string{
+		"cleanup": security.PermissionJobsRunCleanup,
+	}
+)
 
 // JobController handles job management endpoints
 type JobController struct {
-	jobService     jobs.Service
-	scheduler      *scheduler.Scheduler
-	authMiddleware *middleware.AuthMiddleware
+	auditRecorder
+	jobService       jobs.Service
+	scheduler        *scheduler.Scheduler
+	authMiddleware   *middleware.AuthMiddleware
+	idempotencyStore middleware.IdempotencyStore
+	adminIPFilter    gin.HandlerFunc
 }
 
 // NewJobController creates a new JobController instance
@@ -38,6 +76,17 @@ func NewJobController(
 	}
 }
 
+// SetIdempotencyStore enables Idempotency-Key support on the enqueue endpoints
+func (c *JobController) SetIdempotencyStore(store middleware.IdempotencyStore) {
+	c.idempotencyStore = store
+}
+
+// SetAdminIPFilter restricts the queue, worker and DLQ purge routes to the
+// networks the filter allows
+func (c *JobController) SetAdminIPFilter(filter gin.HandlerFunc) {
+	c.adminIPFilter = filter
+}
+
 // RegisterRoutes registers the job routes
 func (c *JobController) RegisterRoutes(router *gin.RouterGroup) {
 	jobRoutes := router.Group("/jobs")
@@ -46,73 +95,251 @@ func (c *JobController) RegisterRoutes(router *gin.RouterGroup) {
 		jobRoutes.GET("/queues", c.GetQueueStats)
 		jobRoutes.GET("/dashboard", c.GetDashboard)
 
-		// Protected endpoints
+		// Protected endpoints. Service callers may use an API key with the
+		// jobs:read or jobs:write scope.
 		protected := jobRoutes.Group("")
-		protected.Use(c.authMiddleware.Authenticate())
+		protected.Use(c.authMiddleware.AuthenticateOrAPIKey())
 		{
+			read := c.authMiddleware.RequireScope(scopeJobsRead)
+			write := c.authMiddleware.RequireScope(scopeJobsWrite)
+
 			// Job management
-			protected.POST("", c.EnqueueJob)
-			protected.GET("/:id", c.GetJob)
-			protected.DELETE("/:id", c.CancelJob)
-			protected.POST("/:id/retry", c.RetryJob)
+			protected.POST("", c.idempotent(write, c.EnqueueJob)...)
+			protected.POST("/batch", c.idempotent(write, c.EnqueueBatch)...)
+			protected.GET("/:id", read, c.GetJob)
+			protected.GET("/:id/result", read, c.GetJobResult)
+			protected.GET("/:id/progress", read, c.GetJobProgress)
+			protected.DELETE("/:id", write, c.CancelJob)
+			protected.POST("/:id/retry", write, c.RetryJob)
+
+			// Admin operations, restricted by network when configured
+			admin := protected.Group("")
+			if c.adminIPFilter != nil {
+				admin.Use(c.adminIPFilter)
+			}
+
+			// Queue control
+			admin.POST("/queues/:type/pause", c.authMiddleware.RequirePermission(security.PermissionJobsManageQueues), c.PauseQueue)
+			admin.POST("/queues/:type/resume", c.authMiddleware.RequirePermission(security.PermissionJobsManageQueues), c.ResumeQueue)
+			admin.PUT("/workers/concurrency", c.authMiddleware.RequirePermission(security.PermissionJobsManageWorkers), c.SetWorkerConcurrency)
 
 			// DLQ management
-			protected.GET("/dlq", c.GetDLQJobs)
-			protected.POST("/dlq/:id/retry", c.RetryDLQJob)
-			protected.DELETE("/dlq", c.authMiddleware.RequireAdmin(), c.PurgeDLQ)
+			protected.GET("/dlq", read, c.GetDLQJobs)
+			protected.POST("/dlq/:id/retry", write, c.RetryDLQJob)
+			admin.DELETE("/dlq", c.authMiddleware.RequirePermission(security.PermissionJobsPurgeDLQ), c.PurgeDLQ)
 
 			// Scheduled jobs
-			protected.GET("/scheduled", c.GetScheduledJobs)
+			protected.GET("/scheduled", read, c.GetScheduledJobs)
+			protected.GET("/scheduler/status", read, c.GetSchedulerStatus)
+			admin.POST("/scheduled/:name/enable", c.authMiddleware.RequirePermission(security.PermissionJobsManageSchedules), c.EnableScheduledJob)
+			admin.POST("/scheduled/:name/disable", c.authMiddleware.RequirePermission(security.PermissionJobsManageSchedules), c.DisableScheduledJob)
+			admin.POST("/scheduled/:name/trigger", c.authMiddleware.RequirePermission(security.PermissionJobsManageSchedules), c.TriggerScheduledJob)
 		}
 	}
 }
 
+// idempotent puts the idempotency middleware between the scope check and an
+// enqueue handler when a store is configured
+func (c *JobController) idempotent(scope, handler gin.HandlerFunc) []gin.HandlerFunc {
+	if c.idempotencyStore == nil {
+		return []gin.HandlerFunc{scope, handler}
+	}
+	return []gin.HandlerFunc{scope, middleware.Idempotency(c.idempotencyStore), handler}
+}
+
 // EnqueueJob adds a new job to the queue
 // @Summary Enqueue a new job
+// @Description Pass include_backlog=true to also get the depth of the queue and priority the job joined and an estimated wait; it costs extra Redis calls and is skipped for scheduled jobs
 // @Tags Jobs
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body request.EnqueueJobRequest true "Job request"
+// @Param include_backlog query bool false "Report queue depth and estimated wait" default(false)
 // @Success 201 {object} response.ApiResponse[response.JobEnqueueResponse]
 // @Router /api/v1/jobs [post]
 func (c *JobController) EnqueueJob(ctx *gin.Context) {
+	includeBacklog, err := strconv.ParseBool(ctx.DefaultQuery("include_backlog", "false"))
+	if err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("include_backlog must be true or false"))
+		return
+	}
+
 	var req request.EnqueueJobRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
 		return
 	}
 
-	// Build options
-	var opts []jobs.JobOption
+	enqueueReq, err := toEnqueueRequest(req)
+	if err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](err.Error()))
+		return
+	}
+	if err := c.checkJobTypePermission(ctx, req.Type); err != nil {
+		respond(ctx, http.StatusForbidden, response.NewError[any](err.Error()))
+		return
+	}
 
-	// Parse priority
-	switch strings.ToLower(req.Priority) {
-	case "low":
-		opts = append(opts, jobs.WithPriority(jobs.PriorityLow))
-	case "high":
-		opts = append(opts, jobs.WithPriority(jobs.PriorityHigh))
-	case "critical":
-		opts = append(opts, jobs.WithPriority(jobs.PriorityCritical))
-	default:
-		opts = append(opts, jobs.WithPriority(jobs.PriorityNormal))
+	jobID, err := c.jobService.Enqueue(ctx.Request.Context(), enqueueReq.Type, enqueueReq.Payload, enqueueReq.Options...)
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to enqueue job"))
+		return
+	}
+
+	resp := response.JobEnqueueResponse{
+		JobID:   jobID,
+		Message: "Job enqueued successfully",
+	}
+	if includeBacklog && req.ScheduledAt == "" && req.DelaySeconds <= 0 {
+		c.addBacklog(ctx, &resp, req)
+	}
+	respond(ctx, http.StatusCreated, response.NewSuccess(resp, "Job enqueued"))
+}
+
+// addBacklog reports the backlog an enqueued job joined. The job is queued
+// either way, so a failed lookup only leaves the backlog out.
+func (c *JobController) addBacklog(ctx *gin.Context, resp *response.JobEnqueueResponse, req request.EnqueueJobRequest) {
+	queue := req.Queue
+	if queue == "" {
+		queue = jobs.DefaultQueue
+	}
+	backlog, err := c.jobService.GetQueueBacklog(ctx.Request.Context(), queue, parsePriority(req.Priority))
+	if err != nil {
+		return
+	}
+
+	resp.QueueDepth = &backlog.Depth
+	if backlog.ProcessingRate > 0 {
+		wait := backlog.EstimatedWait.Seconds()
+		resp.EstimatedWaitSeconds = &wait
+	}
+}
+
+// EnqueueBatch adds multiple jobs to the queue in one request
+// @Summary Enqueue a batch of jobs
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body []request.EnqueueJobRequest true "Job requests"
+// @Success 201 {object} response.ApiResponse[response.BatchEnqueueResponse]
+// @Success 207 {object} response.ApiResponse[response.BatchEnqueueResponse]
+// @Router /api/v1/jobs/batch [post]
+func (c *JobController) EnqueueBatch(ctx *gin.Context) {
+	var reqs []request.EnqueueJobRequest
+	if err := ctx.ShouldBindJSON(&reqs); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+	if len(reqs) == 0 {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("batch must contain at least one job"))
+		return
+	}
+	if len(reqs) > maxBatchSize {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("batch exceeds maximum of "+strconv.Itoa(maxBatchSize)+" jobs"))
+		return
+	}
+
+	items := make([]response.BatchEnqueueItem, len(reqs))
+	batch := make([]jobs.EnqueueRequest, 0, len(reqs))
+	indexes := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		items[i].Index = i
+		enqueueReq, err := toEnqueueRequest(req)
+		if err == nil {
+			err = c.checkJobTypePermission(ctx, req.Type)
+		}
+		if err != nil {
+			items[i].Error = err.Error()
+			continue
+		}
+		batch = append(batch, enqueueReq)
+		indexes = append(indexes, i)
+	}
+
+	if len(batch) > 0 {
+		results, err := c.jobService.EnqueueBatch(ctx.Request.Context(), batch)
+		var batchErr *jobs.BatchEnqueueError
+		if err != nil && !errors.As(err, &batchErr) {
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to enqueue jobs"))
+			return
+		}
+		if batchErr != nil {
+			for _, f := range batchErr.Failures {
+				items[indexes[f.Index]].Error = f.Err.Error()
+			}
+		}
+		for j, job := range results {
+			if job != nil {
+				items[indexes[j]].JobID = job.ID
+			}
+		}
+	}
+
+	resp := response.BatchEnqueueResponse{Jobs: items}
+	for _, item := range items {
+		if item.Error != "" {
+			resp.Failed++
+		} else {
+			resp.Enqueued++
+		}
+	}
+
+	status := http.StatusCreated
+	if resp.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	respond(ctx, status, response.NewSuccess(resp, "Batch enqueued"))
+}
+
+// checkJobTypePermission fails if the caller lacks the permission a
+// restricted job type needs
+func (c *JobController) checkJobTypePermission(ctx *gin.Context, jobType string) error {
+	permission, restricted := restrictedJobTypes[jobType]
+	if !restricted || c.authMiddleware.HasPermission(ctx, permission) {
+		return nil
 	}
+	return fmt.Errorf("missing permission %s to enqueue %s jobs", permission, jobType)
+}
+
+// toEnqueueRequest converts an enqueue DTO into a service request
+func toEnqueueRequest(req request.EnqueueJobRequest) (jobs.EnqueueRequest, error) {
+	opts := []jobs.JobOption{jobs.WithPriority(parsePriority(req.Priority))}
 
 	// Handle scheduling
 	if req.ScheduledAt != "" {
 		scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledAt)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, response.NewError[any]("invalid scheduled_at format, use RFC3339"))
-			return
+			return jobs.EnqueueRequest{}, errInvalidScheduledAt
 		}
 		opts = append(opts, jobs.WithScheduledAt(scheduledAt))
 	} else if req.DelaySeconds > 0 {
 		opts = append(opts, jobs.WithDelay(time.Duration(req.DelaySeconds)*time.Second))
 	}
 
+	if req.Queue != "" {
+		if !queueNamePattern.MatchString(req.Queue) {
+			return jobs.EnqueueRequest{}, errInvalidQueue
+		}
+		opts = append(opts, jobs.WithQueue(req.Queue))
+	}
+
 	if req.UniqueKey != "" {
 		opts = append(opts, jobs.WithUniqueKey(req.UniqueKey))
 	}
+	if req.UniqueForSeconds > 0 {
+		if req.UniqueKey == "" {
+			return jobs.EnqueueRequest{}, errUniqueForNoKey
+		}
+		opts = append(opts, jobs.WithUniqueFor(time.Duration(req.UniqueForSeconds)*time.Second))
+	}
 
 	if len(req.Tags) > 0 {
 		opts = append(opts, jobs.WithTags(req.Tags...))
@@ -121,20 +348,24 @@ func (c *JobController) EnqueueJob(ctx *gin.Context) {
 	// Unmarshal payload to verify it's valid JSON
 	var payload any
 	if err := json.Unmarshal(req.Payload, &payload); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any]("invalid payload JSON"))
-		return
+		return jobs.EnqueueRequest{}, errInvalidPayload
 	}
 
-	jobID, err := c.jobService.Enqueue(ctx.Request.Context(), req.Type, payload, opts...)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to enqueue job"))
-		return
-	}
+	return jobs.EnqueueRequest{Type: req.Type, Payload: payload, Options: opts}, nil
+}
 
-	ctx.JSON(http.StatusCreated, response.NewSuccess(response.JobEnqueueResponse{
-		JobID:   jobID,
-		Message: "Job enqueued successfully",
-	}, "Job enqueued"))
+// parsePriority converts a request priority name, defaulting to normal
+func parsePriority(name string) jobs.Priority {
+	switch strings.ToLower(name) {
+	case "low":
+		return jobs.PriorityLow
+	case "high":
+		return jobs.PriorityHigh
+	case "critical":
+		return jobs.PriorityCritical
+	default:
+		return jobs.PriorityNormal
+	}
 }
 
 // GetJob retrieves a job by ID
@@ -149,17 +380,82 @@ func (c *JobController) EnqueueJob(ctx *gin.Context) {
 func (c *JobController) GetJob(ctx *gin.Context) {
 	jobID := ctx.Param("id")
 	if jobID == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
 		return
 	}
 
 	job, err := c.jobService.GetJob(ctx.Request.Context(), jobID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, response.NewError[any]("job not found"))
+		respond(ctx, http.StatusNotFound, response.NewError[any]("job not found"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(c.toJobResponse(job)))
+}
+
+// GetJobResult retrieves the stored result of a completed job
+// @Summary Get job result
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} response.ApiResponse[json.RawMessage]
+// @Failure 404 {object} response.ApiResponse[any]
+// @Router /api/v1/jobs/{id}/result [get]
+func (c *JobController) GetJobResult(ctx *gin.Context) {
+	jobID := ctx.Param("id")
+	if jobID == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
+		return
+	}
+
+	result, err := c.jobService.GetJobResult(ctx.Request.Context(), jobID)
+	if errors.Is(err, jobs.ErrResultNotFound) {
+		respond(ctx, http.StatusNotFound, response.NewError[any]("job result not available: job has not completed or result has expired"))
+		return
+	}
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get job result"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(result))
+}
+
+// GetJobProgress retrieves the latest progress reported by a job
+// @Summary Get job progress
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Job ID"
+// @Success 200 {object} response.ApiResponse[response.JobProgressResponse]
+// @Failure 404 {object} response.ApiResponse[any]
+// @Router /api/v1/jobs/{id}/progress [get]
+func (c *JobController) GetJobProgress(ctx *gin.Context) {
+	jobID := ctx.Param("id")
+	if jobID == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
+		return
+	}
+
+	progress, err := c.jobService.GetJobProgress(ctx.Request.Context(), jobID)
+	if errors.Is(err, jobs.ErrProgressNotFound) {
+		respond(ctx, http.StatusNotFound, response.NewError[any]("job progress not available: job has not reported progress or it has expired"))
+		return
+	}
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get job progress"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(c.toJobResponse(job)))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(response.JobProgressResponse{
+		JobID:     jobID,
+		Percent:   progress.Percent,
+		Message:   progress.Message,
+		UpdatedAt: progress.UpdatedAt,
+	}))
 }
 
 // CancelJob cancels a pending job
@@ -174,16 +470,16 @@ func (c *JobController) GetJob(ctx *gin.Context) {
 func (c *JobController) CancelJob(ctx *gin.Context) {
 	jobID := ctx.Param("id")
 	if jobID == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
 		return
 	}
 
 	if err := c.jobService.CancelJob(ctx.Request.Context(), jobID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to cancel job"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to cancel job"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "Job cancelled"))
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Job cancelled"))
 }
 
 // RetryJob retries a failed job
@@ -198,16 +494,16 @@ func (c *JobController) CancelJob(ctx *gin.Context) {
 func (c *JobController) RetryJob(ctx *gin.Context) {
 	jobID := ctx.Param("id")
 	if jobID == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
 		return
 	}
 
 	if err := c.jobService.RetryJob(ctx.Request.Context(), jobID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to retry job"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to retry job"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "Job retry initiated"))
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Job retry initiated"))
 }
 
 // GetQueueStats returns queue statistics
@@ -220,23 +516,27 @@ func (c *JobController) RetryJob(ctx *gin.Context) {
 func (c *JobController) GetQueueStats(ctx *gin.Context) {
 	stats, err := c.jobService.GetQueueStats(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to get queue stats"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get queue stats"))
 		return
 	}
 
 	resp := response.QueueStatsResponse{
-		Pending:    stats.Pending,
-		Scheduled:  stats.Scheduled,
-		Completed:  stats.Completed,
-		Failed:     stats.Failed,
-		Dead:       stats.Dead,
-		QueueSizes: stats.QueueSizes,
+		Pending:     stats.Pending,
+		Scheduled:   stats.Scheduled,
+		Completed:   stats.Completed,
+		Failed:      stats.Failed,
+		Dead:        stats.Dead,
+		QueueSizes:  stats.QueueSizes,
+		QueueDepths: stats.QueueDepths,
+		PausedTypes: stats.PausedTypes,
 		WorkerStats: response.WorkerStatsResponse{
-			Running:       stats.WorkerStats.Running,
-			ActiveWorkers: stats.WorkerStats.ActiveWorkers,
-			Concurrency:   stats.WorkerStats.Concurrency,
-			ProcessedJobs: stats.WorkerStats.ProcessedJobs,
-			FailedJobs:    stats.WorkerStats.FailedJobs,
+			Running:            stats.WorkerStats.Running,
+			ActiveWorkers:      stats.WorkerStats.ActiveWorkers,
+			Concurrency:        stats.WorkerStats.Concurrency,
+			CurrentConcurrency: stats.WorkerStats.CurrentConcurrency,
+			ProcessedJobs:      stats.WorkerStats.ProcessedJobs,
+			FailedJobs:         stats.WorkerStats.FailedJobs,
+			ThrottledJobs:      stats.WorkerStats.ThrottledJobs,
 		},
 		SchedulerStats: response.SchedulerStatsResponse{
 			IsLeader:          stats.SchedulerStats.IsLeader,
@@ -245,7 +545,79 @@ func (c *JobController) GetQueueStats(ctx *gin.Context) {
 		},
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(resp))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(resp))
+}
+
+// PauseQueue stops processing jobs of a type
+// @Summary Pause a job type
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Job type"
+// @Success 200 {object} response.ApiResponse[any]
+// @Router /api/v1/jobs/queues/{type}/pause [post]
+func (c *JobController) PauseQueue(ctx *gin.Context) {
+	jobType := ctx.Param("type")
+	if jobType == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgJobTypeRequired))
+		return
+	}
+
+	if err := c.jobService.PauseJobType(ctx.Request.Context(), jobType); err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to pause job type"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Job type paused"))
+}
+
+// ResumeQueue resumes processing jobs of a paused type
+// @Summary Resume a job type
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Job type"
+// @Success 200 {object} response.ApiResponse[any]
+// @Router /api/v1/jobs/queues/{type}/resume [post]
+func (c *JobController) ResumeQueue(ctx *gin.Context) {
+	jobType := ctx.Param("type")
+	if jobType == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgJobTypeRequired))
+		return
+	}
+
+	if err := c.jobService.ResumeJobType(ctx.Request.Context(), jobType); err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to resume job type"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Job type resumed"))
+}
+
+// SetWorkerConcurrency scales this instance's worker pool at runtime
+// @Summary Set worker concurrency
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.SetWorkerConcurrencyRequest true "Concurrency request"
+// @Success 200 {object} response.ApiResponse[any]
+// @Router /api/v1/jobs/workers/concurrency [put]
+func (c *JobController) SetWorkerConcurrency(ctx *gin.Context) {
+	var req request.SetWorkerConcurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+
+	if err := c.jobService.SetWorkerConcurrency(ctx.Request.Context(), req.Concurrency); err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to set worker concurrency"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Worker concurrency updated"))
 }
 
 // GetDashboard returns a comprehensive dashboard view
@@ -266,6 +638,8 @@ func (c *JobController) GetDashboard(ctx *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Limit" default(100)
+// @Param type query string false "Only jobs of this type"
+// @Param error query string false "Only jobs whose last error contains this text"
 // @Success 200 {object} response.ApiResponse[[]response.JobResponse]
 // @Router /api/v1/jobs/dlq [get]
 func (c *JobController) GetDLQJobs(ctx *gin.Context) {
@@ -274,9 +648,14 @@ func (c *JobController) GetDLQJobs(ctx *gin.Context) {
 		limit = 100
 	}
 
-	dlqJobs, err := c.jobService.GetDLQJobs(ctx.Request.Context(), limit)
+	filter := jobs.DLQFilter{
+		Type:          ctx.Query("type"),
+		ErrorContains: ctx.Query("error"),
+	}
+
+	dlqJobs, err := c.jobService.FindDLQJobs(ctx.Request.Context(), filter, limit)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to get DLQ jobs"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get DLQ jobs"))
 		return
 	}
 
@@ -285,7 +664,7 @@ func (c *JobController) GetDLQJobs(ctx *gin.Context) {
 		resp[i] = *c.toJobResponse(job)
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(resp))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(resp))
 }
 
 // RetryDLQJob retries a job from the DLQ
@@ -300,16 +679,16 @@ func (c *JobController) GetDLQJobs(ctx *gin.Context) {
 func (c *JobController) RetryDLQJob(ctx *gin.Context) {
 	jobID := ctx.Param("id")
 	if jobID == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgJobIDRequired))
 		return
 	}
 
 	if err := c.jobService.RetryDLQJob(ctx.Request.Context(), jobID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to retry DLQ job"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to retry DLQ job"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "DLQ job retry initiated"))
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "DLQ job retry initiated"))
 }
 
 // PurgeDLQ removes all jobs from the DLQ
@@ -321,12 +700,18 @@ func (c *JobController) RetryDLQJob(ctx *gin.Context) {
 // @Success 200 {object} response.ApiResponse[any]
 // @Router /api/v1/jobs/dlq [delete]
 func (c *JobController) PurgeDLQ(ctx *gin.Context) {
-	if err := c.jobService.PurgeDLQ(ctx.Request.Context()); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to purge DLQ"))
+	if !c.auditAttempt(ctx, entity.AuditActionDLQPurge, auditTargetDLQ) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "DLQ purged"))
+	err := c.jobService.PurgeDLQ(ctx.Request.Context())
+	_ = c.audit(ctx, entity.AuditActionDLQPurge, auditTargetDLQ, err)
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to purge DLQ"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "DLQ purged"))
 }
 
 // GetScheduledJobs returns all scheduled cron jobs
@@ -339,25 +724,153 @@ func (c *JobController) PurgeDLQ(ctx *gin.Context) {
 // @Router /api/v1/jobs/scheduled [get]
 func (c *JobController) GetScheduledJobs(ctx *gin.Context) {
 	if c.scheduler == nil {
-		ctx.JSON(http.StatusOK, response.NewSuccessWithData([]response.ScheduledJobResponse{}))
+		respond(ctx, http.StatusOK, response.NewSuccessWithData([]response.ScheduledJobResponse{}))
 		return
 	}
 
-	scheduledJobs := c.scheduler.ListJobs()
+	scheduledJobs, err := c.scheduler.ListJobsWithState(ctx.Request.Context())
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get scheduled jobs"))
+		return
+	}
 	resp := make([]response.ScheduledJobResponse, len(scheduledJobs))
 
 	for i, job := range scheduledJobs {
-		nextRun, _ := c.scheduler.GetNextRun(job.Name)
 		resp[i] = response.ScheduledJobResponse{
-			Name:     job.Name,
-			Schedule: job.Schedule,
-			JobType:  job.JobType,
-			NextRun:  nextRun,
-			Priority: job.Priority,
+			Name:            job.Name,
+			Schedule:        job.Schedule,
+			JobType:         job.JobType,
+			NextRun:         job.NextRun,
+			Priority:        job.Priority,
+			Timezone:        job.Timezone,
+			Enabled:         job.Enabled,
+			LastTriggeredAt: job.LastTriggeredAt,
 		}
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(resp))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(resp))
+}
+
+// GetSchedulerStatus returns the scheduler leader election state, to debug
+// split-brain or flapping leadership across replicas
+// @Summary Get scheduler leadership status
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.ApiResponse[response.SchedulerStatusResponse]
+// @Router /api/v1/jobs/scheduler/status [get]
+func (c *JobController) GetSchedulerStatus(ctx *gin.Context) {
+	if c.scheduler == nil {
+		respond(ctx, http.StatusNotFound, response.NewError[any]("scheduler not running"))
+		return
+	}
+
+	status, err := c.scheduler.Status(ctx.Request.Context())
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get scheduler status"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(response.SchedulerStatusResponse{
+		InstanceID:     status.InstanceID,
+		IsLeader:       status.IsLeader,
+		LeaderID:       status.LeaderID,
+		LeaseExpiresAt: status.LeaseExpiresAt,
+		LastElectionAt: status.LastElectionAt,
+	}))
+}
+
+// EnableScheduledJob lets a disabled scheduled job run on its schedule again
+// @Summary Enable a scheduled job
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Scheduled job name"
+// @Success 200 {object} response.ApiResponse[any]
+// @Router /api/v1/jobs/scheduled/{name}/enable [post]
+func (c *JobController) EnableScheduledJob(ctx *gin.Context) {
+	if c.scheduler == nil {
+		respond(ctx, http.StatusNotFound, response.NewError[any](msgScheduledJobNotFound))
+		return
+	}
+
+	err := c.scheduler.EnableJob(ctx.Request.Context(), ctx.Param("name"))
+	if errors.Is(err, scheduler.ErrScheduledJobNotFound) {
+		respond(ctx, http.StatusNotFound, response.NewError[any](msgScheduledJobNotFound))
+		return
+	}
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to enable scheduled job"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Scheduled job enabled"))
+}
+
+// DisableScheduledJob stops a scheduled job from running on its schedule on
+// every instance until it is enabled again
+// @Summary Disable a scheduled job
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Scheduled job name"
+// @Success 200 {object} response.ApiResponse[any]
+// @Router /api/v1/jobs/scheduled/{name}/disable [post]
+func (c *JobController) DisableScheduledJob(ctx *gin.Context) {
+	if c.scheduler == nil {
+		respond(ctx, http.StatusNotFound, response.NewError[any](msgScheduledJobNotFound))
+		return
+	}
+
+	err := c.scheduler.DisableJob(ctx.Request.Context(), ctx.Param("name"))
+	if errors.Is(err, scheduler.ErrScheduledJobNotFound) {
+		respond(ctx, http.StatusNotFound, response.NewError[any](msgScheduledJobNotFound))
+		return
+	}
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to disable scheduled job"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Scheduled job disabled"))
+}
+
+// TriggerScheduledJob enqueues a scheduled job immediately, regardless of
+// its schedule
+// @Summary Trigger a scheduled job now
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Scheduled job name"
+// @Success 201 {object} response.ApiResponse[response.JobEnqueueResponse]
+// @Router /api/v1/jobs/scheduled/{name}/trigger [post]
+func (c *JobController) TriggerScheduledJob(ctx *gin.Context) {
+	if c.scheduler == nil {
+		respond(ctx, http.StatusNotFound, response.NewError[any](msgScheduledJobNotFound))
+		return
+	}
+
+	jobID, err := c.scheduler.TriggerNow(ctx.Request.Context(), ctx.Param("name"))
+	switch {
+	case errors.Is(err, scheduler.ErrScheduledJobNotFound):
+		respond(ctx, http.StatusNotFound, response.NewError[any](msgScheduledJobNotFound))
+		return
+	case errors.Is(err, scheduler.ErrSingletonJobRunning):
+		respond(ctx, http.StatusConflict, response.NewError[any]("scheduled job is already running"))
+		return
+	case err != nil:
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to trigger scheduled job"))
+		return
+	}
+
+	respond(ctx, http.StatusCreated, response.NewSuccess(response.JobEnqueueResponse{
+		JobID:   jobID,
+		Message: "Scheduled job triggered",
+	}, "Job enqueued"))
 }
 
 func (c *JobController) toJobResponse(job *jobs.JobPayload) *response.JobResponse {
@@ -365,6 +878,7 @@ func (c *JobController) toJobResponse(job *jobs.JobPayload) *response.JobRespons
 		ID:            job.ID,
 		Type:          job.Type,
 		Priority:      job.Priority.String(),
+		Queue:         job.QueueName(),
 		Status:        string(job.Status),
 		Attempts:      job.Attempts,
 		MaxRetries:    job.MaxRetries,
@@ -373,6 +887,8 @@ func (c *JobController) toJobResponse(job *jobs.JobPayload) *response.JobRespons
 		StartedAt:     job.StartedAt,
 		CompletedAt:   job.CompletedAt,
 		LastError:     job.LastError,
+		LastStack:     job.LastStack,
+		FailedAt:      job.FailedAt,
 		CorrelationID: job.CorrelationID,
 		Tags:          job.Tags,
 	}
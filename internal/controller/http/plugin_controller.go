@@ -1,26 +1,36 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
 	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
 const (
 	msgPluginKeyRequired = "plugin key is required"
 	msgPluginNotFound    = "plugin not found"
+	msgPluginHookFailed  = "plugin lifecycle hook failed; the plugin is now in the ERROR state"
 )
 
+// DefaultMaxPluginUploadSize is the default request body limit for plugin uploads
+const DefaultMaxPluginUploadSize int64 = 50 << 20
+
 // PluginController handles plugin management endpoints
 type PluginController struct {
+	auditRecorder
 	pluginService  service.PluginService
 	authMiddleware *middleware.AuthMiddleware
+	maxUploadSize  int64
 }
 
 // NewPluginController creates a new PluginController instance
@@ -28,9 +38,16 @@ func NewPluginController(pluginService service.PluginService, authMiddleware *mi
 	return &PluginController{
 		pluginService:  pluginService,
 		authMiddleware: authMiddleware,
+		maxUploadSize:  DefaultMaxPluginUploadSize,
 	}
 }
 
+// SetMaxUploadSize sets the request body limit for plugin uploads, replacing
+// the server-wide limit on that route; 0 removes the limit
+func (c *PluginController) SetMaxUploadSize(maxBytes int64) {
+	c.maxUploadSize = maxBytes
+}
+
 // RegisterRoutes registers the plugin routes
 func (c *PluginController) RegisterRoutes(router *gin.RouterGroup) {
 	plugins := router.Group("/plugins")
@@ -46,35 +63,63 @@ func (c *PluginController) RegisterRoutes(router *gin.RouterGroup) {
 		{
 			protected.GET("", c.List)
 			protected.GET("/:key", c.GetByKey)
-			protected.POST("/install", c.authMiddleware.RequireAdmin(), c.Install)
-			protected.POST("/:key/enable", c.authMiddleware.RequireAdmin(), c.Enable)
-			protected.POST("/:key/disable", c.authMiddleware.RequireAdmin(), c.Disable)
-			protected.DELETE("/:key", c.authMiddleware.RequireAdmin(), c.Uninstall)
+			protected.GET("/:key/history", c.GetHistory)
+			protected.POST("/install", c.authMiddleware.RequirePermission(security.PermissionPluginsInstall), middleware.MaxBodySize(c.maxUploadSize), c.Install)
+			protected.POST("/install-url", c.authMiddleware.RequirePermission(security.PermissionPluginsInstall), c.InstallFromURL)
+			protected.POST("/:key/upgrade", c.authMiddleware.RequirePermission(security.PermissionPluginsInstall), middleware.MaxBodySize(c.maxUploadSize), c.Upgrade)
+			protected.POST("/:key/rollback", c.authMiddleware.RequirePermission(security.PermissionPluginsManage), c.Rollback)
+			protected.PUT("/:key/config", c.authMiddleware.RequirePermission(security.PermissionPluginsManage), c.UpdateConfig)
+			protected.POST("/:key/config/validate", c.authMiddleware.RequirePermission(security.PermissionPluginsManage), c.ValidateConfig)
+			protected.POST("/:key/enable", c.authMiddleware.RequirePermission(security.PermissionPluginsManage), c.Enable)
+			protected.POST("/:key/disable", c.authMiddleware.RequirePermission(security.PermissionPluginsManage), c.Disable)
+			protected.DELETE("/:key", c.authMiddleware.RequirePermission(security.PermissionPluginsUninstall), c.Uninstall)
 		}
 	}
 }
 
 // List retrieves all plugins
 // @Summary List all plugins
+// @Description Pass cursor (empty for the first page) for cursor paging in ID order; it takes precedence over page
 // @Tags Plugins
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param size query int false "Page size" default(10)
+// @Param cursor query string false "Cursor from page_info.next_cursor"
 // @Success 200 {object} response.ApiResponse[response.PagedResponse[response.PluginResponse]]
+// @Success 200 {object} response.ApiResponse[response.CursorPagedResponse[response.PluginResponse]]
 // @Router /api/v1/plugins [get]
 func (c *PluginController) List(ctx *gin.Context) {
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(ctx.DefaultQuery("size", "10"))
+	if cursor, ok := ctx.GetQuery("cursor"); ok {
+		c.listByCursor(ctx, cursor, size)
+		return
+	}
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 
 	plugins, err := c.pluginService.List(ctx.Request.Context(), page, size)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to fetch plugins"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to fetch plugins"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(plugins))
+}
+
+func (c *PluginController) listByCursor(ctx *gin.Context, cursor string, size int) {
+	plugins, err := c.pluginService.ListByCursor(ctx.Request.Context(), cursor, size)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCursor:
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid cursor"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to fetch plugins"))
+		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(plugins))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(plugins))
 }
 
 // GetByKey retrieves a plugin by its key
@@ -89,7 +134,7 @@ func (c *PluginController) List(ctx *gin.Context) {
 func (c *PluginController) GetByKey(ctx *gin.Context) {
 	key := ctx.Param("key")
 	if key == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
 		return
 	}
 
@@ -97,14 +142,45 @@ func (c *PluginController) GetByKey(ctx *gin.Context) {
 	if err != nil {
 		switch err {
 		case service.ErrPluginNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to fetch plugin"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(plugin))
+}
+
+// GetHistory retrieves a plugin's state transitions
+// @Summary Get plugin state history
+// @Description Lists the plugin's state transitions, oldest first
+// @Tags Plugins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Plugin key"
+// @Success 200 {object} response.ApiResponse[[]response.PluginStateTransitionResponse]
+// @Router /api/v1/plugins/{key}/history [get]
+func (c *PluginController) GetHistory(ctx *gin.Context) {
+	key := ctx.Param("key")
+	if key == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		return
+	}
+
+	history, err := c.pluginService.GetHistory(ctx.Request.Context(), key)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPluginNotFound):
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to fetch plugin"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to fetch plugin history"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(plugin))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(history))
 }
 
 // Install uploads and installs a new plugin
@@ -119,13 +195,18 @@ func (c *PluginController) GetByKey(ctx *gin.Context) {
 // @Param type formData string true "Plugin type"
 // @Param description formData string false "Plugin description"
 // @Param author formData string false "Plugin author"
+// @Param config formData string false "Plugin config as a JSON object"
+// @Param config_schema formData string false "JSON Schema the plugin config must match"
 // @Success 201 {object} response.ApiResponse[response.PluginResponse]
 // @Router /api/v1/plugins/install [post]
 func (c *PluginController) Install(ctx *gin.Context) {
 	// Parse multipart form
 	file, _, err := ctx.Request.FormFile("file")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any]("plugin file is required"))
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("plugin file is required"))
 		return
 	}
 	defer file.Close()
@@ -139,22 +220,303 @@ func (c *PluginController) Install(ctx *gin.Context) {
 	}
 
 	if req.Name == "" || req.Version == "" || req.Type == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any]("name, version, and type are required"))
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("name, version, and type are required"))
+		return
+	}
+
+	if !bindJSONFormField(ctx, "config", &req.Config) || !bindJSONFormField(ctx, "config_schema", &req.ConfigSchema) {
+		return
+	}
+
+	if !c.auditAttempt(ctx, entity.AuditActionPluginInstall, req.Name) {
 		return
 	}
 
 	plugin, err := c.pluginService.Install(ctx.Request.Context(), req, file)
+	if err != nil {
+		_ = c.audit(ctx, entity.AuditActionPluginInstall, req.Name, err)
+		writeInstallError(ctx, err)
+		return
+	}
+	_ = c.audit(ctx, entity.AuditActionPluginInstall, plugin.Key, nil)
+
+	respond(ctx, http.StatusCreated, response.NewSuccess(plugin, "Plugin installed successfully"))
+}
+
+// InstallFromURL downloads and installs a new plugin
+// @Summary Install a plugin from a URL
+// @Description Downloads the plugin, verifying the optional SHA-256 checksum and the Ed25519 signature when a signing key is configured
+// @Tags Plugins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.InstallPluginFromURLRequest true "Plugin URL and metadata"
+// @Success 201 {object} response.ApiResponse[response.PluginResponse]
+// @Router /api/v1/plugins/install-url [post]
+func (c *PluginController) InstallFromURL(ctx *gin.Context) {
+	var req request.InstallPluginFromURLRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+
+	if !c.auditAttempt(ctx, entity.AuditActionPluginInstall, req.URL) {
+		return
+	}
+
+	plugin, err := c.pluginService.InstallFromURL(ctx.Request.Context(), &req)
+	if err != nil {
+		_ = c.audit(ctx, entity.AuditActionPluginInstall, req.URL, err)
+		switch {
+		case errors.Is(err, service.ErrPluginInvalidURL),
+			errors.Is(err, service.ErrPluginHostNotAllowed),
+			errors.Is(err, service.ErrPluginVerificationFailed):
+			respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any]("plugin download rejected", err.Error()))
+		case errors.Is(err, service.ErrPluginTooLarge):
+			respond(ctx, http.StatusRequestEntityTooLarge, response.NewErrorWithDetails[any]("plugin too large", err.Error()))
+		case errors.Is(err, service.ErrPluginDownloadFailed):
+			respond(ctx, http.StatusBadGateway, response.NewErrorWithDetails[any]("failed to download plugin", err.Error()))
+		default:
+			writeInstallError(ctx, err)
+		}
+		return
+	}
+	_ = c.audit(ctx, entity.AuditActionPluginInstall, plugin.Key, nil)
+
+	respond(ctx, http.StatusCreated, response.NewSuccess(plugin, "Plugin installed successfully"))
+}
+
+// writeInstallError reports a failed plugin install
+func writeInstallError(ctx *gin.Context, err error) {
+	if writeConfigValidationError(ctx, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrPluginAlreadyExists):
+		respond(ctx, http.StatusConflict, response.NewError[any]("plugin already exists"))
+	case errors.Is(err, service.ErrPluginInvalidConfigSchema):
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any]("invalid plugin config schema", err.Error()))
+	default:
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to install plugin"))
+	}
+}
+
+// Upgrade uploads a new version of a plugin and switches to it
+// @Summary Upgrade a plugin
+// @Description Installs the new binary alongside the current one and keeps the plugin's config; the previous version stays available for rollback
+// @Tags Plugins
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Plugin key"
+// @Param file formData file true "Plugin file"
+// @Param version formData string true "Plugin version"
+// @Param description formData string false "Plugin description"
+// @Param author formData string false "Plugin author"
+// @Success 200 {object} response.ApiResponse[response.PluginResponse]
+// @Router /api/v1/plugins/{key}/upgrade [post]
+func (c *PluginController) Upgrade(ctx *gin.Context) {
+	key := ctx.Param("key")
+	if key == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		return
+	}
+
+	file, _, err := ctx.Request.FormFile("file")
+	if err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("plugin file is required"))
+		return
+	}
+	defer file.Close()
+
+	req := &request.UpgradePluginRequest{
+		Version:     ctx.PostForm("version"),
+		Description: ctx.PostForm("description"),
+		Author:      ctx.PostForm("author"),
+	}
+
+	if req.Version == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("version is required"))
+		return
+	}
+
+	plugin, err := c.pluginService.Upgrade(ctx.Request.Context(), key, req, file)
+	if err != nil {
+		switch err {
+		case service.ErrPluginNotFound:
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+		case service.ErrPluginInvalidState:
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("plugin cannot be upgraded in current state"))
+		case service.ErrPluginVersionExists:
+			respond(ctx, http.StatusConflict, response.NewError[any]("plugin version already installed"))
+		case service.ErrConcurrentModification:
+			respond(ctx, http.StatusConflict, response.NewError[any]("plugin was modified concurrently"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to upgrade plugin"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess(plugin, "Plugin upgraded successfully"))
+}
+
+// Rollback reverts a plugin to the version before its last upgrade
+// @Summary Roll back a plugin upgrade
+// @Tags Plugins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Plugin key"
+// @Success 200 {object} response.ApiResponse[response.PluginResponse]
+// @Router /api/v1/plugins/{key}/rollback [post]
+func (c *PluginController) Rollback(ctx *gin.Context) {
+	key := ctx.Param("key")
+	if key == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		return
+	}
+
+	plugin, err := c.pluginService.Rollback(ctx.Request.Context(), key)
 	if err != nil {
 		switch err {
-		case service.ErrPluginAlreadyExists:
-			ctx.JSON(http.StatusConflict, response.NewError[any]("plugin already exists"))
+		case service.ErrPluginNotFound:
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+		case service.ErrPluginNoPreviousVersion:
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("plugin has no previous version"))
+		case service.ErrConcurrentModification:
+			respond(ctx, http.StatusConflict, response.NewError[any]("plugin was modified concurrently"))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to install plugin"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to roll back plugin"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, response.NewSuccess(plugin, "Plugin installed successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess(plugin, "Plugin rolled back successfully"))
+}
+
+// UpdateConfig replaces a plugin's config
+// @Summary Update plugin config
+// @Description Validates the config against the plugin's schema, saves it and reloads the plugin if it is enabled
+// @Tags Plugins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Plugin key"
+// @Param request body request.UpdatePluginConfigRequest true "New config"
+// @Success 200 {object} response.ApiResponse[response.PluginResponse]
+// @Router /api/v1/plugins/{key}/config [put]
+func (c *PluginController) UpdateConfig(ctx *gin.Context) {
+	key := ctx.Param("key")
+	if key == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		return
+	}
+
+	var req request.UpdatePluginConfigRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid plugin config"))
+		return
+	}
+
+	plugin, err := c.pluginService.UpdateConfig(ctx.Request.Context(), key, req.Config)
+	if err != nil {
+		if writeConfigValidationError(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrPluginNotFound):
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+		case errors.Is(err, service.ErrPluginInvalidConfig):
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid plugin config"))
+		case errors.Is(err, service.ErrConcurrentModification):
+			respond(ctx, http.StatusConflict, response.NewError[any]("plugin was modified concurrently"))
+		case errors.Is(err, service.ErrPluginLoadFailed):
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("plugin config saved but reload failed"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to update plugin config"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess(plugin, "Plugin config updated successfully"))
+}
+
+// ValidateConfig checks a config against the plugin's schema without saving it
+// @Summary Validate plugin config
+// @Tags Plugins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Plugin key"
+// @Param request body request.UpdatePluginConfigRequest true "Config to validate"
+// @Success 200 {object} response.ApiResponse[any]
+// @Router /api/v1/plugins/{key}/config/validate [post]
+func (c *PluginController) ValidateConfig(ctx *gin.Context) {
+	key := ctx.Param("key")
+	if key == "" {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		return
+	}
+
+	var req request.UpdatePluginConfigRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid plugin config"))
+		return
+	}
+
+	if err := c.pluginService.ValidateConfig(ctx.Request.Context(), key, req.Config); err != nil {
+		if writeConfigValidationError(ctx, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrPluginNotFound):
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+		case errors.Is(err, service.ErrPluginInvalidConfig):
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid plugin config"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to validate plugin config"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Plugin config is valid"))
+}
+
+// writeConfigValidationError reports the fields of a config that failed
+// schema validation, returning false for any other error
+func writeConfigValidationError(ctx *gin.Context, err error) bool {
+	var validationErr *service.ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any]("invalid plugin config", validationErr.Fields))
+	return true
+}
+
+// bindJSONFormField decodes an optional JSON-encoded multipart form field,
+// writing a 400 and returning false if it is malformed
+func bindJSONFormField(ctx *gin.Context, name string, dest any) bool {
+	value := ctx.PostForm(name)
+	if value == "" {
+		return true
+	}
+	if err := json.Unmarshal([]byte(value), dest); err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewError[any](name+" must be a JSON object"))
+		return false
+	}
+	return true
 }
 
 // Enable enables a plugin
@@ -169,24 +531,28 @@ func (c *PluginController) Install(ctx *gin.Context) {
 func (c *PluginController) Enable(ctx *gin.Context) {
 	key := ctx.Param("key")
 	if key == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
 		return
 	}
 
 	plugin, err := c.pluginService.Enable(ctx.Request.Context(), key)
 	if err != nil {
-		switch err {
-		case service.ErrPluginNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgPluginNotFound))
-		case service.ErrPluginInvalidState:
-			ctx.JSON(http.StatusBadRequest, response.NewError[any]("plugin cannot be enabled in current state"))
+		switch {
+		case errors.Is(err, service.ErrPluginNotFound):
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+		case errors.Is(err, service.ErrPluginInvalidState):
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("plugin cannot be enabled in current state"))
+		case errors.Is(err, service.ErrPluginRouteConflict):
+			respond(ctx, http.StatusConflict, response.NewError[any]("plugin routes conflict with another plugin"))
+		case errors.Is(err, service.ErrPluginHookFailed):
+			respond(ctx, http.StatusInternalServerError, response.NewError[any](msgPluginHookFailed))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to enable plugin"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to enable plugin"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess(plugin, "Plugin enabled successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess(plugin, "Plugin enabled successfully"))
 }
 
 // Disable disables a plugin
@@ -201,24 +567,26 @@ func (c *PluginController) Enable(ctx *gin.Context) {
 func (c *PluginController) Disable(ctx *gin.Context) {
 	key := ctx.Param("key")
 	if key == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
 		return
 	}
 
 	plugin, err := c.pluginService.Disable(ctx.Request.Context(), key)
 	if err != nil {
-		switch err {
-		case service.ErrPluginNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgPluginNotFound))
-		case service.ErrPluginInvalidState:
-			ctx.JSON(http.StatusBadRequest, response.NewError[any]("plugin cannot be disabled in current state"))
+		switch {
+		case errors.Is(err, service.ErrPluginNotFound):
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+		case errors.Is(err, service.ErrPluginInvalidState):
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("plugin cannot be disabled in current state"))
+		case errors.Is(err, service.ErrPluginHookFailed):
+			respond(ctx, http.StatusInternalServerError, response.NewError[any](msgPluginHookFailed))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to disable plugin"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to disable plugin"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess(plugin, "Plugin disabled successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess(plugin, "Plugin disabled successfully"))
 }
 
 // Uninstall removes a plugin
@@ -233,21 +601,29 @@ func (c *PluginController) Disable(ctx *gin.Context) {
 func (c *PluginController) Uninstall(ctx *gin.Context) {
 	key := ctx.Param("key")
 	if key == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
+		respond(ctx, http.StatusBadRequest, response.NewError[any](msgPluginKeyRequired))
 		return
 	}
 
-	if err := c.pluginService.Uninstall(ctx.Request.Context(), key); err != nil {
-		switch err {
-		case service.ErrPluginNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+	if !c.auditAttempt(ctx, entity.AuditActionPluginUninstall, key) {
+		return
+	}
+
+	err := c.pluginService.Uninstall(ctx.Request.Context(), key)
+	_ = c.audit(ctx, entity.AuditActionPluginUninstall, key, err)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPluginNotFound):
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgPluginNotFound))
+		case errors.Is(err, service.ErrPluginHookFailed):
+			respond(ctx, http.StatusInternalServerError, response.NewError[any](msgPluginHookFailed))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to uninstall plugin"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to uninstall plugin"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "Plugin uninstalled successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Plugin uninstalled successfully"))
 }
 
 // GetHealth returns the plugin system health status
@@ -260,11 +636,11 @@ func (c *PluginController) Uninstall(ctx *gin.Context) {
 func (c *PluginController) GetHealth(ctx *gin.Context) {
 	health, err := c.pluginService.GetHealth(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to get health status"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get health status"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(health))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(health))
 }
 
 // GetReadiness returns the Kubernetes readiness probe response
@@ -275,7 +651,7 @@ func (c *PluginController) GetHealth(ctx *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Router /api/v1/plugins/health/ready [get]
 func (c *PluginController) GetReadiness(ctx *gin.Context) {
-	ctx.JSON(http.StatusOK, gin.H{"status": "ready"})
+	respond(ctx, http.StatusOK, gin.H{"status": "ready"})
 }
 
 // GetLiveness returns the Kubernetes liveness probe response
@@ -286,5 +662,5 @@ func (c *PluginController) GetReadiness(ctx *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Router /api/v1/plugins/health/live [get]
 func (c *PluginController) GetLiveness(ctx *gin.Context) {
-	ctx.JSON(http.StatusOK, gin.H{"status": "alive"})
+	respond(ctx, http.StatusOK, gin.H{"status": "alive"})
 }
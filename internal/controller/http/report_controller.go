@@ -0,0 +1,104 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs/handler"
+	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+	"github.com/jrjohn/arcana-cloud-go/internal/storage"
+)
+
+// reportJobType is the job type generating reports
+const reportJobType = "report"
+
+const msgReportNotFound = "report not available: it has not been generated or has expired"
+
+// ReportController serves the artifacts of report jobs
+type ReportController struct {
+	jobService     jobs.Service
+	store          storage.Store
+	authMiddleware *middleware.AuthMiddleware
+}
+
+// NewReportController creates a new ReportController instance
+func NewReportController(jobService jobs.Service, store storage.Store, authMiddleware *middleware.AuthMiddleware) *ReportController {
+	return &ReportController{
+		jobService:     jobService,
+		store:          store,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// RegisterRoutes registers the report routes
+func (c *ReportController) RegisterRoutes(router *gin.RouterGroup) {
+	reports := router.Group("/reports")
+	reports.Use(c.authMiddleware.Authenticate())
+	{
+		reports.GET("/:id/download", c.authMiddleware.RequirePermission(security.PermissionReportsRead), c.Download)
+	}
+}
+
+// Download streams the artifact of a completed report job
+// @Summary Download a report
+// @Description The report ID is the ID of the report job; the job result links here once it completes
+// @Tags Reports
+// @Produce text/csv
+// @Produce application/json
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Report job ID"
+// @Success 200 {file} file
+// @Failure 403 {object} response.ApiResponse[any]
+// @Failure 404 {object} response.ApiResponse[any]
+// @Router /api/v1/reports/{id}/download [get]
+func (c *ReportController) Download(ctx *gin.Context) {
+	reportID := ctx.Param("id")
+
+	job, err := c.jobService.GetJob(ctx.Request.Context(), reportID)
+	if err != nil || job.Type != reportJobType {
+		respond(ctx, http.StatusNotFound, response.NewError[any]("report not found"))
+		return
+	}
+
+	data, err := c.jobService.GetJobResult(ctx.Request.Context(), reportID)
+	if errors.Is(err, jobs.ErrResultNotFound) {
+		respond(ctx, http.StatusNotFound, response.NewError[any](msgReportNotFound))
+		return
+	}
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get report"))
+		return
+	}
+	var result handler.ReportResult
+	if err := json.Unmarshal(data, &result); err != nil || result.Key == "" {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get report"))
+		return
+	}
+
+	object, err := c.store.Open(ctx.Request.Context(), result.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		respond(ctx, http.StatusNotFound, response.NewError[any](msgReportNotFound))
+		return
+	}
+	if err != nil {
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to open report"))
+		return
+	}
+	defer object.Body.Close()
+
+	contentType := result.ContentType
+	if contentType == "" {
+		contentType = object.ContentType
+	}
+	ctx.DataFromReader(http.StatusOK, object.Size, contentType, object.Body, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": result.FileName}),
+	})
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+)
+
+// respond writes a response in the format the request negotiated, see
+// middleware.Respond. Handlers call it instead of ctx.JSON.
+func respond(ctx *gin.Context, status int, payload any) {
+	middleware.Respond(ctx, status, payload)
+}
@@ -56,12 +56,15 @@ func (c *SSRController) RegisterRoutes(router *gin.RouterGroup) {
 func (c *SSRController) RenderReact(ctx *gin.Context) {
 	component := ctx.Param("component")
 	if component == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any]("component name is required"))
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("component name is required"))
 		return
 	}
 
 	var req request.RenderRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
 		// Allow empty body
 		req = request.RenderRequest{}
 	}
@@ -71,11 +74,11 @@ func (c *SSRController) RenderReact(ctx *gin.Context) {
 	if err != nil {
 		switch err {
 		case service.ErrSSREngineNotReady:
-			ctx.JSON(http.StatusServiceUnavailable, response.NewError[any]("SSR engine is not ready"))
+			respond(ctx, http.StatusServiceUnavailable, response.NewError[any]("SSR engine is not ready"))
 		case service.ErrComponentNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any]("component not found"))
+			respond(ctx, http.StatusNotFound, response.NewError[any]("component not found"))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("SSR rendering failed"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("SSR rendering failed"))
 		}
 		return
 	}
@@ -89,7 +92,7 @@ func (c *SSRController) RenderReact(ctx *gin.Context) {
 		Cached:     result.Cached,
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(resp))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(resp))
 }
 
 // RenderAngular renders an Angular component server-side
@@ -105,12 +108,15 @@ func (c *SSRController) RenderReact(ctx *gin.Context) {
 func (c *SSRController) RenderAngular(ctx *gin.Context) {
 	component := ctx.Param("component")
 	if component == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any]("component name is required"))
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("component name is required"))
 		return
 	}
 
 	var req request.RenderRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
 		req = request.RenderRequest{}
 	}
 	req.Component = component
@@ -119,11 +125,11 @@ func (c *SSRController) RenderAngular(ctx *gin.Context) {
 	if err != nil {
 		switch err {
 		case service.ErrSSREngineNotReady:
-			ctx.JSON(http.StatusServiceUnavailable, response.NewError[any]("SSR engine is not ready"))
+			respond(ctx, http.StatusServiceUnavailable, response.NewError[any]("SSR engine is not ready"))
 		case service.ErrComponentNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any]("component not found"))
+			respond(ctx, http.StatusNotFound, response.NewError[any]("component not found"))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("SSR rendering failed"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("SSR rendering failed"))
 		}
 		return
 	}
@@ -137,7 +143,7 @@ func (c *SSRController) RenderAngular(ctx *gin.Context) {
 		Cached:     result.Cached,
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(resp))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(resp))
 }
 
 // GetStatus returns the SSR engine status
@@ -150,7 +156,7 @@ func (c *SSRController) RenderAngular(ctx *gin.Context) {
 func (c *SSRController) GetStatus(ctx *gin.Context) {
 	status, err := c.ssrService.GetStatus(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to get SSR status"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to get SSR status"))
 		return
 	}
 
@@ -163,7 +169,7 @@ func (c *SSRController) GetStatus(ctx *gin.Context) {
 		Stats:        status.Stats,
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(resp))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(resp))
 }
 
 // ClearCache clears the SSR render cache
@@ -176,9 +182,9 @@ func (c *SSRController) GetStatus(ctx *gin.Context) {
 // @Router /api/v1/ssr/cache/clear [post]
 func (c *SSRController) ClearCache(ctx *gin.Context) {
 	if err := c.ssrService.ClearCache(ctx.Request.Context()); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to clear cache"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to clear cache"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "Cache cleared successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Cache cleared successfully"))
 }
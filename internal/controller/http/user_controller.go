@@ -17,6 +17,8 @@ const (
 	msgNotAuthenticated = "not authenticated"
 	msgUserNotFound     = "user not found"
 	msgFailedFetchUser  = "failed to fetch user"
+
+	msgConcurrentModification = "user was modified concurrently; reload and retry"
 )
 
 // UserController handles user management endpoints
@@ -45,36 +47,125 @@ func (c *UserController) RegisterRoutes(router *gin.RouterGroup) {
 	users.Use(c.authMiddleware.Authenticate())
 	{
 		users.GET("", c.authMiddleware.RequireAdmin(), c.List)
+		users.GET("/search", c.authMiddleware.RequireAdmin(), c.Search)
 		users.GET("/me", c.GetCurrentUser)
 		users.PUT("/me", c.UpdateCurrentUser)
 		users.PUT("/me/password", c.ChangePassword)
 		users.GET("/:id", c.GetByID)
 		users.GET("/username/:username", c.GetByUsername)
 		users.DELETE("/:id", c.authMiddleware.RequireAdmin(), c.Delete)
+		users.POST("/:id/restore", c.authMiddleware.RequireAdmin(), c.Restore)
+	}
+}
+
+// userData shapes a user for the API version of the request; v2 returns
+// response.UserResponseV2
+func userData(ctx *gin.Context, user *response.UserResponse) any {
+	if middleware.GetAPIVersion(ctx) >= 2 {
+		return response.NewUserResponseV2(user)
 	}
+	return user
 }
 
 // List retrieves all users with pagination
 // @Summary List all users
+// @Description Pass cursor (empty for the first page) for cursor paging in ID order; it takes precedence over page. include_deleted also lists soft-deleted users and cannot be combined with cursor
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param size query int false "Page size" default(10)
+// @Param cursor query string false "Cursor from page_info.next_cursor"
+// @Param include_deleted query bool false "Include soft-deleted users" default(false)
 // @Success 200 {object} response.ApiResponse[response.PagedResponse[response.UserResponse]]
+// @Success 200 {object} response.ApiResponse[response.CursorPagedResponse[response.UserResponse]]
+// @Failure 400 {object} response.ApiResponse[any]
 // @Router /api/v1/users [get]
 func (c *UserController) List(ctx *gin.Context) {
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(ctx.DefaultQuery("size", "10"))
+	includeDeleted, err := strconv.ParseBool(ctx.DefaultQuery("include_deleted", "false"))
+	if err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("include_deleted must be true or false"))
+		return
+	}
+	if cursor, ok := ctx.GetQuery("cursor"); ok {
+		if includeDeleted {
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("include_deleted cannot be combined with cursor"))
+			return
+		}
+		c.listByCursor(ctx, cursor, size)
+		return
+	}
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 
-	users, err := c.userService.List(ctx.Request.Context(), page, size)
+	list := c.userService.List
+	if includeDeleted {
+		list = c.userService.ListIncludingDeleted
+	}
+	users, err := list(ctx.Request.Context(), page, size)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to fetch users"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to fetch users"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(users))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(users))
+}
+
+func (c *UserController) listByCursor(ctx *gin.Context, cursor string, size int) {
+	users, err := c.userService.ListByCursor(ctx.Request.Context(), cursor, size)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCursor:
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid cursor"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to fetch users"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(users))
+}
+
+// Search retrieves users matching filters
+// @Summary Search users
+// @Description Filters combine with AND; q matches usernames and emails by prefix
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Role" Enums(USER, ADMIN)
+// @Param is_active query bool false "Active users only (true) or inactive only (false)"
+// @Param is_verified query bool false "Verified users only (true) or unverified only (false)"
+// @Param created_after query string false "Created at or after (RFC 3339)"
+// @Param created_before query string false "Created before (RFC 3339)"
+// @Param q query string false "Username or email prefix"
+// @Param sort query string false "Sort field" Enums(id, username, email, created_at) default(id)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size, at most 100" default(10)
+// @Success 200 {object} response.ApiResponse[response.PagedResponse[response.UserResponse]]
+// @Failure 400 {object} response.ApiResponse[any]
+// @Failure 501 {object} response.ApiResponse[any]
+// @Router /api/v1/users/search [get]
+func (c *UserController) Search(ctx *gin.Context) {
+	var req request.UserSearchRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		return
+	}
+	users, err := c.userService.Search(ctx.Request.Context(), &req)
+	if err != nil {
+		switch err {
+		case service.ErrSearchUnsupported:
+			respond(ctx, http.StatusNotImplemented, response.NewError[any](err.Error()))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to search users"))
+		}
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(users))
 }
 
 // GetCurrentUser retrieves the current authenticated user
@@ -84,11 +175,12 @@ func (c *UserController) List(ctx *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} response.ApiResponse[response.UserResponse]
+// @Success 200 {object} response.ApiResponse[response.UserResponseV2] "API v2"
 // @Router /api/v1/users/me [get]
 func (c *UserController) GetCurrentUser(ctx *gin.Context) {
 	userID := c.securityService.GetCurrentUserID(ctx)
 	if userID == 0 {
-		ctx.JSON(http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
+		respond(ctx, http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
 		return
 	}
 
@@ -96,35 +188,41 @@ func (c *UserController) GetCurrentUser(ctx *gin.Context) {
 	if err != nil {
 		switch err {
 		case service.ErrUserNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgUserNotFound))
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgUserNotFound))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any](msgFailedFetchUser))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any](msgFailedFetchUser))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(user))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(userData(ctx, user)))
 }
 
 // UpdateCurrentUser updates the current user's profile
 // @Summary Update current user
+// @Description Send the lock_version last read to reject the update if the profile changed since
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body request.UpdateProfileRequest true "Update request"
 // @Success 200 {object} response.ApiResponse[response.UserResponse]
+// @Success 200 {object} response.ApiResponse[response.UserResponseV2] "API v2"
+// @Failure 409 {object} response.ApiResponse[any]
 // @Router /api/v1/users/me [put]
 func (c *UserController) UpdateCurrentUser(ctx *gin.Context) {
 	userID := c.securityService.GetCurrentUserID(ctx)
 	if userID == 0 {
-		ctx.JSON(http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
+		respond(ctx, http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
 		return
 	}
 
 	var req request.UpdateProfileRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
 		return
 	}
 
@@ -132,16 +230,18 @@ func (c *UserController) UpdateCurrentUser(ctx *gin.Context) {
 	if err != nil {
 		switch err {
 		case service.ErrUserNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgUserNotFound))
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgUserNotFound))
 		case service.ErrUserAlreadyExists:
-			ctx.JSON(http.StatusConflict, response.NewError[any]("email already in use"))
+			respond(ctx, http.StatusConflict, response.NewError[any]("email already in use"))
+		case service.ErrConcurrentModification:
+			respond(ctx, http.StatusConflict, response.NewError[any](msgConcurrentModification))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to update user"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to update user"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess(user, "Profile updated successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess(userData(ctx, user), "Profile updated successfully"))
 }
 
 // ChangePassword changes the current user's password
@@ -156,13 +256,16 @@ func (c *UserController) UpdateCurrentUser(ctx *gin.Context) {
 func (c *UserController) ChangePassword(ctx *gin.Context) {
 	userID := c.securityService.GetCurrentUserID(ctx)
 	if userID == 0 {
-		ctx.JSON(http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
+		respond(ctx, http.StatusUnauthorized, response.NewError[any](msgNotAuthenticated))
 		return
 	}
 
 	var req request.ChangePasswordRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
+		if middleware.BodyTooLarge(ctx, err) {
+			return
+		}
+		respond(ctx, http.StatusBadRequest, response.NewErrorWithDetails[any](msgValidationFailed, err.Error()))
 		return
 	}
 
@@ -170,16 +273,18 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 	if err != nil {
 		switch err {
 		case service.ErrUserNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgUserNotFound))
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgUserNotFound))
 		case service.ErrInvalidCredentials:
-			ctx.JSON(http.StatusBadRequest, response.NewError[any]("current password is incorrect"))
+			respond(ctx, http.StatusBadRequest, response.NewError[any]("current password is incorrect"))
+		case service.ErrConcurrentModification:
+			respond(ctx, http.StatusConflict, response.NewError[any](msgConcurrentModification))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to change password"))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to change password"))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "Password changed successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "Password changed successfully"))
 }
 
 // GetByID retrieves a user by ID
@@ -190,12 +295,13 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "User ID"
 // @Success 200 {object} response.ApiResponse[response.UserResponse]
+// @Success 200 {object} response.ApiResponse[response.UserResponseV2] "API v2"
 // @Router /api/v1/users/{id} [get]
 func (c *UserController) GetByID(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any]("invalid user ID"))
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid user ID"))
 		return
 	}
 
@@ -203,14 +309,14 @@ func (c *UserController) GetByID(ctx *gin.Context) {
 	if err != nil {
 		switch err {
 		case service.ErrUserNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgUserNotFound))
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgUserNotFound))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any](msgFailedFetchUser))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any](msgFailedFetchUser))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(user))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(userData(ctx, user)))
 }
 
 // GetByUsername retrieves a user by username
@@ -221,11 +327,12 @@ func (c *UserController) GetByID(ctx *gin.Context) {
 // @Security BearerAuth
 // @Param username path string true "Username"
 // @Success 200 {object} response.ApiResponse[response.UserResponse]
+// @Success 200 {object} response.ApiResponse[response.UserResponseV2] "API v2"
 // @Router /api/v1/users/username/{username} [get]
 func (c *UserController) GetByUsername(ctx *gin.Context) {
 	username := ctx.Param("username")
 	if username == "" {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any]("username is required"))
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("username is required"))
 		return
 	}
 
@@ -233,14 +340,14 @@ func (c *UserController) GetByUsername(ctx *gin.Context) {
 	if err != nil {
 		switch err {
 		case service.ErrUserNotFound:
-			ctx.JSON(http.StatusNotFound, response.NewError[any](msgUserNotFound))
+			respond(ctx, http.StatusNotFound, response.NewError[any](msgUserNotFound))
 		default:
-			ctx.JSON(http.StatusInternalServerError, response.NewError[any](msgFailedFetchUser))
+			respond(ctx, http.StatusInternalServerError, response.NewError[any](msgFailedFetchUser))
 		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccessWithData(user))
+	respond(ctx, http.StatusOK, response.NewSuccessWithData(userData(ctx, user)))
 }
 
 // Delete removes a user
@@ -256,14 +363,49 @@ func (c *UserController) Delete(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.NewError[any]("invalid user ID"))
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid user ID"))
 		return
 	}
 
 	if err := c.userService.Delete(ctx.Request.Context(), uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.NewError[any]("failed to delete user"))
+		respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to delete user"))
+		return
+	}
+
+	respond(ctx, http.StatusOK, response.NewSuccess[any](nil, "User deleted successfully"))
+}
+
+// Restore undoes the deletion of a user
+// @Summary Restore deleted user
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} response.ApiResponse[response.UserResponse]
+// @Success 200 {object} response.ApiResponse[response.UserResponseV2] "API v2"
+// @Failure 404 {object} response.ApiResponse[any]
+// @Failure 409 {object} response.ApiResponse[any]
+// @Router /api/v1/users/{id}/restore [post]
+func (c *UserController) Restore(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		respond(ctx, http.StatusBadRequest, response.NewError[any]("invalid user ID"))
+		return
+	}
+
+	user, err := c.userService.Restore(ctx.Request.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case service.ErrUserNotFound:
+			respond(ctx, http.StatusNotFound, response.NewError[any]("user not found"))
+		case service.ErrUserNotDeleted:
+			respond(ctx, http.StatusConflict, response.NewError[any]("user is not deleted"))
+		default:
+			respond(ctx, http.StatusInternalServerError, response.NewError[any]("failed to restore user"))
+		}
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.NewSuccess[any](nil, "User deleted successfully"))
+	respond(ctx, http.StatusOK, response.NewSuccess(userData(ctx, user), "User restored successfully"))
 }
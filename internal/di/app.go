@@ -1,6 +1,8 @@
 package di
 
 import (
+	"time"
+
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
@@ -9,10 +11,19 @@ import (
 
 const bannerSeparator = "==========================================="
 
-// AppModule aggregates all application modules
+// StopTimeout bounds the app's shutdown: draining in-flight HTTP requests,
+// for at most server.shutdown_timeout, then stopping the WebSocket hub,
+// workers, plugins and connections. Pass it to fx.StopTimeout.
+const StopTimeout = 45 * time.Second
+
+// AppModule aggregates all application modules. HTTP requests and job
+// execution are traced when the app is also given a trace.TracerProvider,
+// e.g. fx.Supply(fx.Annotate(tp, fx.As(new(trace.TracerProvider)))). Trace
+// context travels as W3C traceparent headers and job metadata.
 var AppModule = fx.Options(
 	ConfigModule,
 	LoggerModule,
+	ObservabilityModule,
 	DatabaseModule,
 	DAOModule,          // DAO layer (between Database and Repository)
 	RepositoryModule,   // Repository layer (delegates to DAO)
@@ -22,6 +33,7 @@ var AppModule = fx.Options(
 	ControllerModule,
 	PluginModule,
 	JobsModule,         // Job worker system
+	HealthModule,       // Subsystem health checks
 	HTTPServerModule,
 	GRPCServerModule,
 	GRPCLayeredModule,  // Layered gRPC architecture
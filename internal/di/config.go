@@ -16,9 +16,17 @@ var ConfigModule = fx.Module("config",
 		provideDatabaseConfig,
 		provideRedisConfig,
 		provideJWTConfig,
+		provideTOTPConfig,
+		providePasswordConfig,
+		provideRBACConfig,
 		provideDeploymentConfig,
 		providePluginConfig,
 		provideSSRConfig,
+		provideJobsConfig,
+		provideSMTPConfig,
+		provideWebhookConfig,
+		provideAuditConfig,
+		provideReportsConfig,
 	),
 )
 
@@ -46,6 +54,18 @@ func provideJWTConfig(cfg *config.Config) *config.JWTConfig {
 	return &cfg.JWT
 }
 
+func provideTOTPConfig(cfg *config.Config) *config.TOTPConfig {
+	return &cfg.TOTP
+}
+
+func providePasswordConfig(cfg *config.Config) *config.PasswordConfig {
+	return &cfg.Password
+}
+
+func provideRBACConfig(cfg *config.Config) *config.RBACConfig {
+	return &cfg.RBAC
+}
+
 func provideDeploymentConfig(cfg *config.Config) *config.DeploymentConfig {
 	return &cfg.Deployment
 }
@@ -57,3 +77,23 @@ func providePluginConfig(cfg *config.Config) *config.PluginConfig {
 func provideSSRConfig(cfg *config.Config) *config.SSRConfig {
 	return &cfg.SSR
 }
+
+func provideJobsConfig(cfg *config.Config) *config.JobsConfig {
+	return &cfg.Jobs
+}
+
+func provideSMTPConfig(cfg *config.Config) *config.SMTPConfig {
+	return &cfg.SMTP
+}
+
+func provideWebhookConfig(cfg *config.Config) *config.WebhookConfig {
+	return &cfg.Webhook
+}
+
+func provideAuditConfig(cfg *config.Config) *config.AuditConfig {
+	return &cfg.Audit
+}
+
+func provideReportsConfig(cfg *config.Config) *config.ReportsConfig {
+	return &cfg.Reports
+}
@@ -1,10 +1,13 @@
 package di
 
 import (
+	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/config"
 	httpctrl "github.com/jrjohn/arcana-cloud-go/internal/controller/http"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
 	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
@@ -14,16 +17,24 @@ var ControllerModule = fx.Module("controller",
 	fx.Provide(
 		provideAuthController,
 		provideUserController,
+		provideAPIKeyController,
 		providePluginController,
 		provideSSRController,
+		provideAuditController,
 	),
 )
 
 func provideAuthController(
 	authService service.AuthService,
 	securityService *security.SecurityService,
+	authMiddleware *middleware.AuthMiddleware,
+	jobService jobs.Service,
+	auditService service.AuditService,
 ) *httpctrl.AuthController {
-	return httpctrl.NewAuthController(authService, securityService)
+	controller := httpctrl.NewAuthController(authService, securityService, authMiddleware)
+	controller.SetJobService(jobService)
+	controller.SetAuditService(auditService)
+	return controller
 }
 
 func provideUserController(
@@ -34,11 +45,24 @@ func provideUserController(
 	return httpctrl.NewUserController(userService, securityService, authMiddleware)
 }
 
+func provideAPIKeyController(
+	apiKeyService service.APIKeyService,
+	securityService *security.SecurityService,
+	authMiddleware *middleware.AuthMiddleware,
+) *httpctrl.APIKeyController {
+	return httpctrl.NewAPIKeyController(apiKeyService, securityService, authMiddleware)
+}
+
 func providePluginController(
 	pluginService service.PluginService,
 	authMiddleware *middleware.AuthMiddleware,
+	cfg *config.PluginConfig,
+	auditService service.AuditService,
 ) *httpctrl.PluginController {
-	return httpctrl.NewPluginController(pluginService, authMiddleware)
+	controller := httpctrl.NewPluginController(pluginService, authMiddleware)
+	controller.SetMaxUploadSize(cfg.MaxUploadSize)
+	controller.SetAuditService(auditService)
+	return controller
 }
 
 func provideSSRController(
@@ -47,3 +71,15 @@ func provideSSRController(
 ) *httpctrl.SSRController {
 	return httpctrl.NewSSRController(ssrService, authMiddleware)
 }
+
+func provideAuditController(
+	auditService service.AuditService,
+	authMiddleware *middleware.AuthMiddleware,
+	ipFilter adminIPFilter,
+) *httpctrl.AuditController {
+	controller := httpctrl.NewAuditController(auditService, authMiddleware)
+	if ipFilter != nil {
+		controller.SetIPFilter(gin.HandlerFunc(ipFilter))
+	}
+	return controller
+}
@@ -1,27 +1,72 @@
 package di
 
 import (
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	daocache "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/cache"
+	cassandradao "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/cassandra"
 	gormdao "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/gorm"
 	mongodao "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo"
+	"github.com/jrjohn/arcana-cloud-go/internal/observability"
 )
 
 // DAOModule provides DAO dependencies based on database driver configuration.
-// It automatically selects the appropriate DAO implementation (GORM or MongoDB)
-// based on the configured database driver.
+// It automatically selects the appropriate DAO implementation (GORM, MongoDB,
+// or Cassandra) based on the configured database driver, and wraps the DAOs
+// selected in database.cache with the lookup cache.
 var DAOModule = fx.Module("dao",
 	fx.Provide(
+		provideDAOCache,
 		provideMongoIDCounter,
+		provideCassandraIDCounter,
+		provideTxManagerDAO,
 		provideUserDAO,
 		provideRefreshTokenDAO,
+		providePasswordResetTokenDAO,
+		provideAPIKeyDAO,
 		providePluginDAO,
 		providePluginExtensionDAO,
+		providePluginStateTransitionDAO,
+		provideAuditLogDAO,
 	),
 )
 
+// provideDAOCache creates the DAO lookup cache and registers its metrics on
+// the /metrics registry. Nothing is cached unless database.cache is enabled.
+func provideDAOCache(cfg *config.DatabaseConfig, client *redis.Client, metrics *observability.MetricsProvider) (*daocache.Caches, error) {
+	if !cfg.Cache.Enabled {
+		return daocache.New(nil, daocache.Options{})
+	}
+
+	var store daocache.Store
+	switch cfg.Cache.Store {
+	case "", "memory":
+		store = daocache.NewMemoryStore(cfg.Cache.MaxEntries)
+	case "redis":
+		store = daocache.NewRedisStore(client, "arcana:dao:cache:")
+	default:
+		return nil, fmt.Errorf("unsupported DAO cache store: %s", cfg.Cache.Store)
+	}
+
+	caches, err := daocache.New(store, daocache.Options{
+		Entities:      cfg.Cache.Entities,
+		TTL:           cfg.Cache.TTL,
+		CacheNotFound: cfg.Cache.CacheNotFound,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := metrics.Register(observability.NewDAOCacheCollector(caches.Metrics())); err != nil {
+		return nil, fmt.Errorf("failed to register DAO cache metrics: %w", err)
+	}
+	return caches, nil
+}
+
 // provideMongoIDCounter creates an ID counter for MongoDB.
 // Returns nil if SQL database is configured.
 func provideMongoIDCounter(mongoDB *MongoDatabase) *mongodao.IDCounter {
@@ -31,17 +76,48 @@ func provideMongoIDCounter(mongoDB *MongoDatabase) *mongodao.IDCounter {
 	return mongodao.NewIDCounter(mongoDB.DB)
 }
 
+// provideCassandraIDCounter creates an ID counter for Cassandra.
+// Returns nil if Cassandra is not configured.
+func provideCassandraIDCounter(cassandraDB *CassandraDatabase) *cassandradao.IDCounter {
+	if cassandraDB.Session == nil {
+		return nil
+	}
+	return cassandradao.NewIDCounter(cassandraDB.Session)
+}
+
+// provideTxManagerDAO creates a TxManager based on the configured database driver.
+func provideTxManagerDAO(
+	cfg *config.DatabaseConfig,
+	sqlDB *SQLDatabase,
+	mongoDB *MongoDatabase,
+	caches *daocache.Caches,
+) dao.TxManager {
+	if cfg.IsMongoDB() {
+		return caches.TxManager(mongodao.NewTxManager(mongoDB.Client))
+	}
+	if cfg.IsCassandra() {
+		return caches.TxManager(cassandradao.NewTxManager())
+	}
+	return caches.TxManager(gormdao.NewTxManager(sqlDB.DB))
+}
+
 // provideUserDAO creates a UserDAO based on the configured database driver.
 func provideUserDAO(
 	cfg *config.DatabaseConfig,
 	sqlDB *SQLDatabase,
 	mongoDB *MongoDatabase,
 	idCounter *mongodao.IDCounter,
+	cassandraDB *CassandraDatabase,
+	cassandraIDCounter *cassandradao.IDCounter,
+	caches *daocache.Caches,
 ) dao.UserDAO {
 	if cfg.IsMongoDB() {
-		return mongodao.NewUserDAO(mongoDB.DB, idCounter)
+		return caches.Users(mongodao.NewUserDAO(mongoDB.DB, idCounter))
+	}
+	if cfg.IsCassandra() {
+		return caches.Users(cassandradao.NewUserDAO(cassandraDB.Session, cassandraIDCounter))
 	}
-	return gormdao.NewUserDAO(sqlDB.DB)
+	return caches.Users(gormdao.NewUserDAO(sqlDB.DB))
 }
 
 // provideRefreshTokenDAO creates a RefreshTokenDAO based on the configured database driver.
@@ -50,25 +126,73 @@ func provideRefreshTokenDAO(
 	sqlDB *SQLDatabase,
 	mongoDB *MongoDatabase,
 	idCounter *mongodao.IDCounter,
+	cassandraDB *CassandraDatabase,
+	cassandraIDCounter *cassandradao.IDCounter,
 	userDAO dao.UserDAO,
 ) dao.RefreshTokenDAO {
 	if cfg.IsMongoDB() {
 		return mongodao.NewRefreshTokenDAO(mongoDB.DB, idCounter, userDAO)
 	}
+	if cfg.IsCassandra() {
+		return cassandradao.NewRefreshTokenDAO(cassandraDB.Session, cassandraIDCounter, userDAO)
+	}
 	return gormdao.NewRefreshTokenDAO(sqlDB.DB)
 }
 
+// providePasswordResetTokenDAO creates a PasswordResetTokenDAO based on the configured database driver.
+func providePasswordResetTokenDAO(
+	cfg *config.DatabaseConfig,
+	sqlDB *SQLDatabase,
+	mongoDB *MongoDatabase,
+	idCounter *mongodao.IDCounter,
+	cassandraDB *CassandraDatabase,
+	cassandraIDCounter *cassandradao.IDCounter,
+) dao.PasswordResetTokenDAO {
+	if cfg.IsMongoDB() {
+		return mongodao.NewPasswordResetTokenDAO(mongoDB.DB, idCounter)
+	}
+	if cfg.IsCassandra() {
+		return cassandradao.NewPasswordResetTokenDAO(cassandraDB.Session, cassandraIDCounter)
+	}
+	return gormdao.NewPasswordResetTokenDAO(sqlDB.DB)
+}
+
+// provideAPIKeyDAO creates an APIKeyDAO based on the configured database driver.
+func provideAPIKeyDAO(
+	cfg *config.DatabaseConfig,
+	sqlDB *SQLDatabase,
+	mongoDB *MongoDatabase,
+	idCounter *mongodao.IDCounter,
+	cassandraDB *CassandraDatabase,
+	cassandraIDCounter *cassandradao.IDCounter,
+	caches *daocache.Caches,
+) dao.APIKeyDAO {
+	if cfg.IsMongoDB() {
+		return caches.APIKeys(mongodao.NewAPIKeyDAO(mongoDB.DB, idCounter))
+	}
+	if cfg.IsCassandra() {
+		return caches.APIKeys(cassandradao.NewAPIKeyDAO(cassandraDB.Session, cassandraIDCounter))
+	}
+	return caches.APIKeys(gormdao.NewAPIKeyDAO(sqlDB.DB))
+}
+
 // providePluginDAO creates a PluginDAO based on the configured database driver.
 func providePluginDAO(
 	cfg *config.DatabaseConfig,
 	sqlDB *SQLDatabase,
 	mongoDB *MongoDatabase,
 	idCounter *mongodao.IDCounter,
+	cassandraDB *CassandraDatabase,
+	cassandraIDCounter *cassandradao.IDCounter,
+	caches *daocache.Caches,
 ) dao.PluginDAO {
 	if cfg.IsMongoDB() {
-		return mongodao.NewPluginDAO(mongoDB.DB, idCounter)
+		return caches.Plugins(mongodao.NewPluginDAO(mongoDB.DB, idCounter))
 	}
-	return gormdao.NewPluginDAO(sqlDB.DB)
+	if cfg.IsCassandra() {
+		return caches.Plugins(cassandradao.NewPluginDAO(cassandraDB.Session, cassandraIDCounter))
+	}
+	return caches.Plugins(gormdao.NewPluginDAO(sqlDB.DB))
 }
 
 // providePluginExtensionDAO creates a PluginExtensionDAO based on the configured database driver.
@@ -77,9 +201,50 @@ func providePluginExtensionDAO(
 	sqlDB *SQLDatabase,
 	mongoDB *MongoDatabase,
 	idCounter *mongodao.IDCounter,
+	cassandraDB *CassandraDatabase,
+	cassandraIDCounter *cassandradao.IDCounter,
 ) dao.PluginExtensionDAO {
 	if cfg.IsMongoDB() {
 		return mongodao.NewPluginExtensionDAO(mongoDB.DB, idCounter)
 	}
+	if cfg.IsCassandra() {
+		return cassandradao.NewPluginExtensionDAO(cassandraDB.Session, cassandraIDCounter)
+	}
 	return gormdao.NewPluginExtensionDAO(sqlDB.DB)
 }
+
+// providePluginStateTransitionDAO creates a PluginStateTransitionDAO based on the configured database driver.
+func providePluginStateTransitionDAO(
+	cfg *config.DatabaseConfig,
+	sqlDB *SQLDatabase,
+	mongoDB *MongoDatabase,
+	idCounter *mongodao.IDCounter,
+	cassandraDB *CassandraDatabase,
+	cassandraIDCounter *cassandradao.IDCounter,
+) dao.PluginStateTransitionDAO {
+	if cfg.IsMongoDB() {
+		return mongodao.NewPluginStateTransitionDAO(mongoDB.DB, idCounter)
+	}
+	if cfg.IsCassandra() {
+		return cassandradao.NewPluginStateTransitionDAO(cassandraDB.Session, cassandraIDCounter)
+	}
+	return gormdao.NewPluginStateTransitionDAO(sqlDB.DB)
+}
+
+// provideAuditLogDAO creates an AuditLogDAO based on the configured database driver.
+func provideAuditLogDAO(
+	cfg *config.DatabaseConfig,
+	sqlDB *SQLDatabase,
+	mongoDB *MongoDatabase,
+	idCounter *mongodao.IDCounter,
+	cassandraDB *CassandraDatabase,
+	cassandraIDCounter *cassandradao.IDCounter,
+) dao.AuditLogDAO {
+	if cfg.IsMongoDB() {
+		return mongodao.NewAuditLogDAO(mongoDB.DB, idCounter)
+	}
+	if cfg.IsCassandra() {
+		return cassandradao.NewAuditLogDAO(cassandraDB.Session, cassandraIDCounter)
+	}
+	return gormdao.NewAuditLogDAO(sqlDB.DB)
+}
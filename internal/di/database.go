@@ -3,7 +3,9 @@ package di
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/gocql/gocql"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -11,50 +13,89 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
+	cassandradao "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/cassandra"
+	gormdao "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/gorm"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/observability"
 )
 
-// SQLDatabase wraps *gorm.DB for SQL databases (MySQL, PostgreSQL).
-// DB may be nil if MongoDB is configured.
+// SQLDatabase wraps *gorm.DB for SQL databases (MySQL, PostgreSQL, SQLite).
+// DB may be nil if another database is configured.
 type SQLDatabase struct {
 	DB *gorm.DB
 }
 
 // MongoDatabase wraps *mongo.Database for MongoDB.
-// DB may be nil if a SQL database is configured.
+// DB may be nil if another database is configured.
 type MongoDatabase struct {
 	DB     *mongo.Database
 	Client *mongo.Client
 }
 
+// CassandraDatabase wraps *gocql.Session for Cassandra and ScyllaDB.
+// Session may be nil if another database is configured.
+type CassandraDatabase struct {
+	Session *gocql.Session
+}
+
+// PoolMetricsInterval is how often connection pool stats are sampled for
+// /metrics. It defaults to database.pool_metrics_interval and can be
+// overridden with fx.Replace(di.PoolMetricsInterval(...)).
+type PoolMetricsInterval time.Duration
+
 // DatabaseModule provides database dependencies based on config
 var DatabaseModule = fx.Module("database",
 	fx.Provide(
+		providePoolMetricsInterval,
+		provideDBPoolMetrics,
 		provideSQLDatabase,
 		provideMongoDatabase,
+		provideCassandraDatabase,
 	),
 	fx.Invoke(runMigrations),
 )
 
+func providePoolMetricsInterval(cfg *config.DatabaseConfig) PoolMetricsInterval {
+	return PoolMetricsInterval(cfg.PoolMetricsInterval)
+}
+
+// provideDBPoolMetrics creates the pool stats sampler and registers its
+// gauges on the /metrics registry.
+func provideDBPoolMetrics(lc fx.Lifecycle, interval PoolMetricsInterval, registry *observability.MetricsProvider, logger *zap.Logger) (*observability.DBPoolMetrics, error) {
+	metrics := observability.NewDBPoolMetrics(time.Duration(interval), logger)
+	if err := registry.Register(metrics.Collectors()...); err != nil {
+		return nil, fmt.Errorf("failed to register DB pool metrics: %w", err)
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			metrics.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			metrics.Stop()
+			return nil
+		},
+	})
+
+	return metrics, nil
+}
+
 // provideSQLDatabase creates a GORM database connection for SQL databases.
-func provideSQLDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap.Logger) (*SQLDatabase, error) {
-	// Return nil DB if MongoDB is configured
-	if cfg.IsMongoDB() {
-		logger.Info("MongoDB configured, skipping SQL database")
+func provideSQLDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, poolMetrics *observability.DBPoolMetrics, logger *zap.Logger) (*SQLDatabase, error) {
+	// Return nil DB if another database is configured
+	if !cfg.IsSQL() {
+		logger.Info("Non-SQL database configured, skipping SQL database", zap.String("driver", cfg.Driver))
 		return &SQLDatabase{DB: nil}, nil
 	}
 
-	var dialector gorm.Dialector
-	switch cfg.Driver {
-	case string(config.DriverMySQL):
-		dialector = mysql.Open(cfg.DSN())
-	case string(config.DriverPostgres):
-		dialector = postgres.Open(cfg.DSN())
-	default:
-		return nil, fmt.Errorf("unsupported SQL driver: %s", cfg.Driver)
+	dialector, err := openDialector(cfg.Driver, cfg.DSN())
+	if err != nil {
+		return nil, err
 	}
 
 	logger.Info("Connecting to SQL database",
@@ -63,7 +104,7 @@ func provideSQLDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap
 		zap.Int("port", cfg.Port),
 	)
 
-	db, err := gorm.Open(dialector, &gorm.Config{})
+	db, err := gorm.Open(dialector, &gorm.Config{CreateBatchSize: cfg.BatchSize})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -76,6 +117,27 @@ func provideSQLDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	if cfg.IsSQLite() {
+		// SQLite allows a single writer, and each connection to ":memory:"
+		// opens a separate database, so pin the pool to one connection.
+		sqlDB.SetMaxOpenConns(1)
+	}
+	poolMetrics.Add(cfg.Name, observability.SQLPoolStats(sqlDB))
+
+	if len(cfg.Replicas) > 0 {
+		replicas := make([]gorm.Dialector, 0, len(cfg.Replicas))
+		for _, dsn := range cfg.Replicas {
+			replica, err := openDialector(cfg.Driver, dsn)
+			if err != nil {
+				return nil, err
+			}
+			replicas = append(replicas, replica)
+		}
+		if err := gormdao.RegisterReplicas(db, replicas...); err != nil {
+			return nil, fmt.Errorf("failed to register read replicas: %w", err)
+		}
+		logger.Info("Routing SQL reads to replicas", zap.Int("replicas", len(replicas)))
+	}
 
 	// Register lifecycle hooks
 	lc.Append(fx.Hook{
@@ -88,11 +150,25 @@ func provideSQLDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap
 	return &SQLDatabase{DB: db}, nil
 }
 
+// openDialector returns the GORM dialector for a SQL driver and DSN.
+func openDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case string(config.DriverMySQL):
+		return mysql.Open(dsn), nil
+	case string(config.DriverPostgres):
+		return postgres.Open(dsn), nil
+	case string(config.DriverSQLite):
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported SQL driver: %s", driver)
+	}
+}
+
 // provideMongoDatabase creates a MongoDB database connection.
-func provideMongoDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap.Logger) (*MongoDatabase, error) {
-	// Return nil DB if SQL is configured
+func provideMongoDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, poolMetrics *observability.DBPoolMetrics, logger *zap.Logger) (*MongoDatabase, error) {
+	// Return nil DB if another database is configured
 	if !cfg.IsMongoDB() {
-		logger.Info("SQL database configured, skipping MongoDB")
+		logger.Info("Non-MongoDB database configured, skipping MongoDB", zap.String("driver", cfg.Driver))
 		return &MongoDatabase{DB: nil, Client: nil}, nil
 	}
 
@@ -102,11 +178,13 @@ func provideMongoDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *z
 		zap.String("database", cfg.Name),
 	)
 
-	clientOpts := options.Client().ApplyURI(cfg.MongoURI())
+	poolMonitor := observability.NewMongoPoolMonitor()
+	clientOpts := options.Client().ApplyURI(cfg.MongoURI()).SetPoolMonitor(poolMonitor.PoolMonitor())
 	client, err := mongo.Connect(clientOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
+	poolMetrics.Add(cfg.Name, poolMonitor.Stats)
 
 	// Ping to verify connection
 	if err := client.Ping(context.Background(), nil); err != nil {
@@ -126,15 +204,74 @@ func provideMongoDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *z
 	return &MongoDatabase{DB: db, Client: client}, nil
 }
 
+// provideCassandraDatabase creates a Cassandra/ScyllaDB session bound to the
+// configured keyspace, creating the keyspace first if needed.
+func provideCassandraDatabase(lc fx.Lifecycle, cfg *config.DatabaseConfig, logger *zap.Logger) (*CassandraDatabase, error) {
+	if !cfg.IsCassandra() {
+		return &CassandraDatabase{Session: nil}, nil
+	}
+
+	hosts := cfg.CassandraHosts()
+	logger.Info("Connecting to Cassandra",
+		zap.Strings("hosts", hosts),
+		zap.String("keyspace", cfg.Name),
+	)
+
+	consistency, err := gocql.ParseConsistencyWrapper(cfg.Cassandra.Consistency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Cassandra consistency: %w", err)
+	}
+
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Consistency = consistency
+	cluster.Timeout = cfg.Cassandra.Timeout
+	cluster.ConnectTimeout = cfg.Cassandra.Timeout
+	if cfg.User != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{Username: cfg.User, Password: cfg.Password}
+	}
+
+	// The keyspace must exist before a session can be bound to it
+	bootstrap, err := cluster.CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Cassandra: %w", err)
+	}
+	err = cassandradao.CreateKeyspace(context.Background(), bootstrap, cfg.Name, cfg.Cassandra.ReplicationFactor)
+	bootstrap.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cassandra keyspace: %w", err)
+	}
+
+	cluster.Keyspace = cfg.Name
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Cassandra: %w", err)
+	}
+
+	// Register lifecycle hooks
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			logger.Info("Closing Cassandra session")
+			session.Close()
+			return nil
+		},
+	})
+
+	return &CassandraDatabase{Session: session}, nil
+}
+
 // runMigrations runs database migrations based on the configured driver.
-func runMigrations(sqlDB *SQLDatabase, mongoDB *MongoDatabase, cfg *config.DatabaseConfig, logger *zap.Logger) error {
+func runMigrations(sqlDB *SQLDatabase, mongoDB *MongoDatabase, cassandraDB *CassandraDatabase, cfg *config.DatabaseConfig, logger *zap.Logger) error {
 	if sqlDB.DB != nil {
 		logger.Info("Running SQL database migrations")
 		return sqlDB.DB.AutoMigrate(
 			&entity.User{},
 			&entity.RefreshToken{},
+			&entity.PasswordResetToken{},
+			&entity.APIKey{},
 			&entity.Plugin{},
 			&entity.PluginExtension{},
+			&entity.PluginStateTransition{},
+			&entity.AuditLog{},
 		)
 	}
 
@@ -143,6 +280,11 @@ func runMigrations(sqlDB *SQLDatabase, mongoDB *MongoDatabase, cfg *config.Datab
 		return createMongoIndexes(mongoDB.DB, logger)
 	}
 
+	if cassandraDB.Session != nil {
+		logger.Info("Creating Cassandra tables")
+		return cassandradao.CreateSchema(context.Background(), cassandraDB.Session)
+	}
+
 	return nil
 }
 
@@ -164,6 +306,12 @@ func createMongoIndexes(db *mongo.Database, logger *zap.Logger) error {
 		{
 			Keys: bson.D{{Key: "numeric_id", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "role", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+		},
 	}
 	if _, err := usersCollection.Indexes().CreateMany(ctx, userIndexes); err != nil {
 		logger.Error("Failed to create user indexes", zap.Error(err))
@@ -208,6 +356,41 @@ func createMongoIndexes(db *mongo.Database, logger *zap.Logger) error {
 		return err
 	}
 
+	// Password reset tokens collection indexes
+	resetTokensCollection := db.Collection("password_reset_tokens")
+	resetTokenIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "numeric_id", Value: 1}},
+		},
+	}
+	if _, err := resetTokensCollection.Indexes().CreateMany(ctx, resetTokenIndexes); err != nil {
+		logger.Error("Failed to create password reset token indexes", zap.Error(err))
+		return err
+	}
+
+	// API keys collection indexes
+	apiKeysCollection := db.Collection("api_keys")
+	apiKeyIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "numeric_id", Value: 1}},
+		},
+	}
+	if _, err := apiKeysCollection.Indexes().CreateMany(ctx, apiKeyIndexes); err != nil {
+		logger.Error("Failed to create API key indexes", zap.Error(err))
+		return err
+	}
+
 	// Plugin extensions collection indexes
 	extensionsCollection := db.Collection("plugin_extensions")
 	extensionIndexes := []mongo.IndexModel{
@@ -223,6 +406,42 @@ func createMongoIndexes(db *mongo.Database, logger *zap.Logger) error {
 		return err
 	}
 
+	// Plugin state transitions collection indexes
+	transitionsCollection := db.Collection("plugin_state_transitions")
+	transitionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "plugin_id", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "numeric_id", Value: 1}},
+		},
+	}
+	if _, err := transitionsCollection.Indexes().CreateMany(ctx, transitionIndexes); err != nil {
+		logger.Error("Failed to create plugin state transition indexes", zap.Error(err))
+		return err
+	}
+
+	// Audit logs collection indexes
+	auditLogsCollection := db.Collection("audit_logs")
+	auditLogIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "numeric_id", Value: 1}},
+		},
+	}
+	if _, err := auditLogsCollection.Indexes().CreateMany(ctx, auditLogIndexes); err != nil {
+		logger.Error("Failed to create audit log indexes", zap.Error(err))
+		return err
+	}
+
 	// Counters collection for auto-increment IDs
 	countersCollection := db.Collection("counters")
 	counterIndexes := []mongo.IndexModel{
@@ -0,0 +1,102 @@
+package di
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/fx"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/configserver"
+	"github.com/jrjohn/arcana-cloud-go/internal/health"
+	"github.com/jrjohn/arcana-cloud-go/internal/plugin/manager"
+)
+
+// HealthModule provides the health checker behind /health, /health/live and
+// /health/ready
+var HealthModule = fx.Module("health",
+	fx.Provide(provideHealthChecker),
+)
+
+// dependencyCheckTimeout bounds the checks of the services the app depends on
+const dependencyCheckTimeout = 2 * time.Second
+
+// healthCheckerParams holds the components with health checks. The config
+// client is optional; supply a *configserver.ConfigClient to the app to check
+// the config server too.
+type healthCheckerParams struct {
+	fx.In
+
+	Redis             *redis.Client
+	SQLDatabase       *SQLDatabase
+	MongoDatabase     *MongoDatabase
+	CassandraDatabase *CassandraDatabase
+	PluginManager     *manager.Manager
+	ConfigClient      *configserver.ConfigClient `optional:"true"`
+}
+
+// provideHealthChecker registers a check per subsystem. Redis and the
+// database are critical; the config server and plugins only degrade health,
+// as the app keeps serving with its last config and without a failing plugin.
+func provideHealthChecker(p healthCheckerParams) *health.Checker {
+	checker := health.NewChecker()
+
+	checker.Register(health.Check{
+		Name:     "redis",
+		Critical: true,
+		Timeout:  dependencyCheckTimeout,
+		Check: func(ctx context.Context) error {
+			return p.Redis.Ping(ctx).Err()
+		},
+	})
+
+	if p.SQLDatabase.DB != nil {
+		checker.Register(health.Check{
+			Name:     "database",
+			Critical: true,
+			Timeout:  dependencyCheckTimeout,
+			Check: func(ctx context.Context) error {
+				sqlDB, err := p.SQLDatabase.DB.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.PingContext(ctx)
+			},
+		})
+	}
+	if p.MongoDatabase.Client != nil {
+		checker.Register(health.Check{
+			Name:     "database",
+			Critical: true,
+			Timeout:  dependencyCheckTimeout,
+			Check: func(ctx context.Context) error {
+				return p.MongoDatabase.Client.Ping(ctx, nil)
+			},
+		})
+	}
+	if p.CassandraDatabase.Session != nil {
+		checker.Register(health.Check{
+			Name:     "database",
+			Critical: true,
+			Timeout:  dependencyCheckTimeout,
+			Check: func(ctx context.Context) error {
+				return p.CassandraDatabase.Session.Query(`SELECT release_version FROM system.local`).WithContext(ctx).Exec()
+			},
+		})
+	}
+
+	if p.ConfigClient != nil {
+		checker.Register(health.Check{
+			Name:    "config_server",
+			Timeout: dependencyCheckTimeout,
+			Check:   p.ConfigClient.Ping,
+		})
+	}
+
+	checker.Register(health.Check{
+		Name:  "plugins",
+		Check: p.PluginManager.CheckHealth,
+	})
+
+	return checker
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
 	httpctrl "github.com/jrjohn/arcana-cloud-go/internal/controller/http"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/handler"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/lock"
@@ -17,6 +21,9 @@ import (
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/scheduler"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/worker"
 	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/observability"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+	"github.com/jrjohn/arcana-cloud-go/internal/storage"
 )
 
 // JobsModule provides job worker system dependencies
@@ -29,7 +36,15 @@ var JobsModule = fx.Module("jobs",
 		provideScheduler,
 		provideJobService,
 		provideHandlerRegistry,
+		provideEmailSender,
+		provideEmailTemplates,
+		provideResilienceMetrics,
+		provideCircuitBreakerRegistry,
 		provideJobController,
+		provideReportStore,
+		provideReportController,
+		provideSyncAdapters,
+		provideCleanupTasks,
 	),
 	fx.Invoke(
 		registerDefaultHandlers,
@@ -72,8 +87,21 @@ func provideRedisClient(lc fx.Lifecycle, cfg *config.RedisConfig, logger *zap.Lo
 	return client, nil
 }
 
-func provideJobQueue(client *redis.Client) *queue.RedisQueue {
-	return queue.NewRedisQueue(client)
+func provideJobQueue(client *redis.Client, cfg *config.JobsConfig) (*queue.RedisQueue, error) {
+	queueConfig := queue.DefaultQueueConfig()
+	queueConfig.Compress = cfg.CompressPayloads
+	if cfg.CompressThreshold > 0 {
+		queueConfig.CompressThreshold = cfg.CompressThreshold
+	}
+	queueConfig.StrictPriority = cfg.StrictPriority
+	if len(cfg.PriorityWeights) > 0 {
+		weights, err := queue.ParsePriorityWeights(cfg.PriorityWeights)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jobs.priority_weights: %w", err)
+		}
+		queueConfig.PriorityWeights = weights
+	}
+	return queue.NewRedisQueueWithConfig(client, queueConfig), nil
 }
 
 func provideLockManager(client *redis.Client, logger *zap.Logger) *lock.LockManager {
@@ -87,10 +115,27 @@ func provideLockManager(client *redis.Client, logger *zap.Logger) *lock.LockMana
 	return lm
 }
 
-func provideWorkerPool(q *queue.RedisQueue, lm *lock.LockManager, logger *zap.Logger) *worker.WorkerPool {
+// workerPoolParams holds the worker pool dependencies. The tracer provider is
+// optional; when supplied, job execution continues the enqueuing request's trace.
+type workerPoolParams struct {
+	fx.In
+
+	Queue          *queue.RedisQueue
+	LockManager    *lock.LockManager
+	JobsConfig     *config.JobsConfig
+	Logger         *zap.Logger
+	TracerProvider trace.TracerProvider `optional:"true"`
+}
+
+func provideWorkerPool(p workerPoolParams) *worker.WorkerPool {
 	config := worker.DefaultWorkerPoolConfig()
-	pool := worker.NewWorkerPool(q, logger, config)
-	pool.SetLockManager(lm)
+	config.Queues = p.JobsConfig.WorkerQueues
+	config.JobTimeout = p.JobsConfig.JobTimeout
+	pool := worker.NewWorkerPool(p.Queue, p.Logger, config)
+	pool.SetLockManager(p.LockManager)
+	if p.TracerProvider != nil {
+		pool.SetTracerProvider(p.TracerProvider)
+	}
 	return pool
 }
 
@@ -103,49 +148,137 @@ func provideJobService(q *queue.RedisQueue, pool *worker.WorkerPool, sched *sche
 }
 
 func provideHandlerRegistry(pool *worker.WorkerPool, logger *zap.Logger) *handler.Registry {
-	return handler.NewRegistry(pool, logger)
+	registry := handler.NewRegistry(pool, logger)
+	registry.Use(handler.RecoverMiddleware)
+	return registry
 }
 
 func provideJobController(
 	jobService jobs.Service,
 	sched *scheduler.Scheduler,
 	authMiddleware *middleware.AuthMiddleware,
+	client *redis.Client,
+	auditService service.AuditService,
+	ipFilter adminIPFilter,
 ) *httpctrl.JobController {
-	return httpctrl.NewJobController(jobService, sched, authMiddleware)
+	controller := httpctrl.NewJobController(jobService, sched, authMiddleware)
+	controller.SetIdempotencyStore(middleware.NewRedisIdempotencyStore(client))
+	controller.SetAuditService(auditService)
+	if ipFilter != nil {
+		controller.SetAdminIPFilter(gin.HandlerFunc(ipFilter))
+	}
+	return controller
+}
+
+// provideReportStore stores generated reports in the configured backend
+func provideReportStore(cfg *config.ReportsConfig, logger *zap.Logger) (storage.Store, error) {
+	switch cfg.Storage {
+	case "", "local":
+		logger.Info("Storing reports locally", zap.String("dir", cfg.LocalDir))
+		return storage.NewLocalStore(cfg.LocalDir)
+	case "s3":
+		logger.Info("Storing reports in S3", zap.String("bucket", cfg.S3.Bucket))
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			SessionToken:    cfg.S3.SessionToken,
+			Prefix:          cfg.S3.Prefix,
+			PathStyle:       cfg.S3.PathStyle,
+			Timeout:         cfg.S3.Timeout,
+		})
+	default:
+		return nil, fmt.Errorf("unknown reports.storage %q: use local or s3", cfg.Storage)
+	}
+}
+
+func provideReportController(jobService jobs.Service, store storage.Store, authMiddleware *middleware.AuthMiddleware) *httpctrl.ReportController {
+	return httpctrl.NewReportController(jobService, store, authMiddleware)
+}
+
+// provideSyncAdapters registers the built-in sync adapters. Decorate the
+// registry to add adapters for other source and destination pairs.
+func provideSyncAdapters(userRepo repository.UserRepository, auditLogRepo repository.AuditLogRepository, client *redis.Client) *handler.SyncAdapterRegistry {
+	adapters := handler.NewSyncAdapterRegistry()
+	adapters.Register(handler.SyncSourceDatabase, handler.SyncDestinationCache,
+		handler.NewDatabaseCacheSyncAdapter(userRepo, auditLogRepo, handler.NewRedisSyncCache(client)))
+	return adapters
+}
+
+// provideCleanupTasks provides the built-in cleanup tasks
+func provideCleanupTasks(
+	cfg *config.AuditConfig,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	pluginRepo repository.PluginRepository,
+	auditLogRepo repository.AuditLogRepository,
+) handler.CleanupTasks {
+	return handler.DefaultCleanupTasks(refreshTokenRepo, resetTokenRepo, pluginRepo, auditLogRepo, cfg.RetentionMinDays)
+}
+
+// provideEmailSender sends email through the configured SMTP server, or logs
+// it when no server is configured
+func provideEmailSender(cfg *config.SMTPConfig, logger *zap.Logger) handler.EmailSender {
+	if cfg.Host == "" {
+		return handler.NewLogEmailSender(logger)
+	}
+	return handler.NewSMTPSender(handler.SMTPConfig{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+		Timeout:  cfg.Timeout,
+	})
+}
+
+func provideEmailTemplates(cfg *config.SMTPConfig) (*handler.EmailTemplates, error) {
+	return handler.LoadEmailTemplates(cfg.TemplatesDir)
+}
+
+// provideResilienceMetrics creates the circuit breaker and rate limiter
+// collector and registers it on the /metrics registry.
+func provideResilienceMetrics(metrics *observability.MetricsProvider) (*observability.ResilienceCollector, error) {
+	collector := observability.NewResilienceCollector()
+	if err := metrics.Register(collector); err != nil {
+		return nil, fmt.Errorf("failed to register resilience metrics: %w", err)
+	}
+	return collector, nil
+}
+
+func provideCircuitBreakerRegistry(metrics *observability.ResilienceCollector, logger *zap.Logger) *resilience.CircuitBreakerRegistry {
+	breakers := resilience.NewCircuitBreakerRegistry(logger)
+	metrics.AddCircuitBreakers(breakers)
+	return breakers
 }
 
 // registerDefaultHandlers registers the default job handlers
-func registerDefaultHandlers(registry *handler.Registry, logger *zap.Logger) {
+func registerDefaultHandlers(
+	registry *handler.Registry,
+	sender handler.EmailSender,
+	templates *handler.EmailTemplates,
+	webhookCfg *config.WebhookConfig,
+	breakers *resilience.CircuitBreakerRegistry,
+	reportStore storage.Store,
+	userRepo repository.UserRepository,
+	auditLogRepo repository.AuditLogRepository,
+	syncAdapters *handler.SyncAdapterRegistry,
+	cleanupTasks handler.CleanupTasks,
+	logger *zap.Logger,
+) {
 	// Register email job handler
-	handler.Register(registry, "email", func(ctx context.Context, payload handler.EmailJobPayload) error {
-		logger.Info("Processing email job",
-			zap.Strings("to", payload.To),
-			zap.String("subject", payload.Subject),
-		)
-		// Note: Email sending is handled by external mail service integration
-		return nil
-	})
+	handler.Register(registry, "email", handler.NewEmailHandler(sender, templates))
 
 	// Register webhook job handler
-	handler.Register(registry, "webhook", func(ctx context.Context, payload handler.WebhookJobPayload) error {
-		logger.Info("Processing webhook job",
-			zap.String("url", payload.URL),
-			zap.String("method", payload.Method),
-		)
-		// Note: Webhook delivery is handled by external HTTP client
-		return nil
-	})
+	handler.Register(registry, "webhook", handler.NewWebhookHandler(handler.WebhookConfig{
+		Secret:  webhookCfg.Secret,
+		Timeout: webhookCfg.Timeout,
+	}, breakers))
 
-	// Register cleanup job handler
-	handler.Register(registry, "cleanup", func(ctx context.Context, payload handler.CleanupJobPayload) error {
-		logger.Info("Processing cleanup job",
-			zap.String("type", payload.Type),
-			zap.Int("older_than_days", payload.OlderThan),
-			zap.Bool("dry_run", payload.DryRun),
-		)
-		// Note: Cleanup logic delegates to repository layer
-		return nil
-	})
+	// Register cleanup job handler; its result counts the rows removed
+	handler.RegisterWithResult(registry, "cleanup", handler.NewCleanupHandler(cleanupTasks, logger))
 
 	// Register notification job handler
 	handler.Register(registry, "notification", func(ctx context.Context, payload handler.NotificationJobPayload) error {
@@ -158,26 +291,14 @@ func registerDefaultHandlers(registry *handler.Registry, logger *zap.Logger) {
 		return nil
 	})
 
-	// Register report job handler
-	handler.Register(registry, "report", func(ctx context.Context, payload handler.ReportJobPayload) error {
-		logger.Info("Processing report job",
-			zap.String("report_type", payload.ReportType),
-			zap.String("format", payload.Format),
-		)
-		// Note: Report generation is handled by report service
-		return nil
-	})
+	// Register report job handler; its result links to the stored report
+	handler.RegisterWithResult(registry, "report", handler.NewReportHandler(map[string]handler.ReportSource{
+		handler.ReportTypeUsers:     handler.UserReportSource(userRepo),
+		handler.ReportTypeAuditLogs: handler.AuditLogReportSource(auditLogRepo),
+	}, reportStore))
 
-	// Register sync job handler
-	handler.Register(registry, "sync", func(ctx context.Context, payload handler.SyncJobPayload) error {
-		logger.Info("Processing sync job",
-			zap.String("source", payload.Source),
-			zap.String("destination", payload.Destination),
-			zap.String("entity_type", payload.EntityType),
-		)
-		// Note: Sync logic is handled by synchronization service
-		return nil
-	})
+	// Register sync job handler; its result counts the records synced
+	handler.RegisterWithResult(registry, "sync", handler.NewSyncHandler(syncAdapters, logger))
 
 	logger.Info("Registered default job handlers")
 }
@@ -190,7 +311,7 @@ func registerDefaultScheduledJobs(sched *scheduler.Scheduler, logger *zap.Logger
 		Schedule: scheduler.DailyMidnight,
 		JobType:  "cleanup",
 		Payload: handler.CleanupJobPayload{
-			Type:      "expired_tokens",
+			Type:      handler.CleanupExpiredTokens,
 			OlderThan: 30,
 			DryRun:    false,
 		},
@@ -201,15 +322,31 @@ func registerDefaultScheduledJobs(sched *scheduler.Scheduler, logger *zap.Logger
 		logger.Warn("Failed to register daily-token-cleanup job", zap.Error(err))
 	}
 
+	// Register plugin purge job - frees the keys of plugins deleted a month ago
+	if err := sched.RegisterJob(scheduler.ScheduledJob{
+		Name:     "weekly-plugin-purge",
+		Schedule: scheduler.WeeklyMonday,
+		JobType:  "cleanup",
+		Payload: handler.CleanupJobPayload{
+			Type:      handler.CleanupOrphanedPlugins,
+			OlderThan: 30,
+		},
+		Priority:  jobs.PriorityLow,
+		Tags:      []string{"maintenance", "cleanup"},
+		Singleton: true,
+	}); err != nil {
+		logger.Warn("Failed to register weekly-plugin-purge job", zap.Error(err))
+	}
+
 	// Register hourly stats job
 	if err := sched.RegisterJob(scheduler.ScheduledJob{
 		Name:     "hourly-stats-sync",
 		Schedule: scheduler.EveryHour,
 		JobType:  "sync",
 		Payload: handler.SyncJobPayload{
-			Source:      "database",
-			Destination: "cache",
-			EntityType:  "stats",
+			Source:      handler.SyncSourceDatabase,
+			Destination: handler.SyncDestinationCache,
+			EntityType:  handler.SyncEntityStats,
 			FullSync:    false,
 		},
 		Priority:  jobs.PriorityNormal,
@@ -229,6 +366,7 @@ func startJobWorkers(lc fx.Lifecycle, pool *worker.WorkerPool, sched *scheduler.
 			logger.Info("Starting job worker pool",
 				zap.String("worker_id", lm.GetWorkerID()),
 			)
+			pool.SetLeaderChecker(sched)
 			if err := pool.Start(ctx); err != nil {
 				return fmt.Errorf("failed to start worker pool: %w", err)
 			}
@@ -1,8 +1,12 @@
 package di
 
 import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/config"
 	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
@@ -10,11 +14,35 @@ import (
 // MiddlewareModule provides middleware dependencies
 var MiddlewareModule = fx.Module("middleware",
 	fx.Provide(provideAuthMiddleware),
+	fx.Provide(provideAdminIPFilter),
 )
 
+// adminIPFilter restricts admin routes by network; nil when unrestricted
+type adminIPFilter gin.HandlerFunc
+
 func provideAuthMiddleware(
 	jwtProvider *security.JWTProvider,
 	securityService *security.SecurityService,
+	tokenDenylist security.TokenDenylist,
 ) *middleware.AuthMiddleware {
-	return middleware.NewAuthMiddleware(jwtProvider, securityService)
+	authMiddleware := middleware.NewAuthMiddleware(jwtProvider, securityService)
+	if tokenDenylist != nil {
+		authMiddleware.SetTokenDenylist(tokenDenylist)
+	}
+	return authMiddleware
+}
+
+func provideAdminIPFilter(cfg *config.ServerConfig) (adminIPFilter, error) {
+	if !cfg.AdminIPFilter.IsEnabled() {
+		return nil, nil
+	}
+	filter, err := middleware.IPFilter(middleware.IPFilterConfig{
+		Allow:          cfg.AdminIPFilter.Allow,
+		Deny:           cfg.AdminIPFilter.Deny,
+		TrustedProxies: cfg.AdminIPFilter.TrustedProxies,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid server.admin_ip_filter: %w", err)
+	}
+	return adminIPFilter(filter), nil
 }
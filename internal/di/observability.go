@@ -0,0 +1,73 @@
+package di
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/config"
+	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/observability"
+)
+
+// ObservabilityModule provides the metrics registry served on /metrics, on
+// which the other modules register their collectors, and installs the W3C
+// trace propagator when the app is traced
+var ObservabilityModule = fx.Module("observability",
+	fx.Provide(
+		provideMetricsProvider,
+		provideHTTPMetrics,
+	),
+	fx.Invoke(installTracePropagator),
+)
+
+// tracePropagatorParams holds the optional tracer provider supplied to the app
+type tracePropagatorParams struct {
+	fx.In
+
+	TracerProvider trace.TracerProvider `optional:"true"`
+}
+
+// installTracePropagator sets the global propagator to W3C trace context and
+// baggage when a tracer provider is supplied, so the gRPC interceptors carry
+// the trace between layers. Without one the otel default, a no-op, is kept.
+func installTracePropagator(p tracePropagatorParams) {
+	if p.TracerProvider == nil {
+		return
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+func provideMetricsProvider(lc fx.Lifecycle, cfg *config.AppConfig, logger *zap.Logger) (*observability.MetricsProvider, error) {
+	metricsCfg := observability.DefaultMetricsConfig()
+	metricsCfg.ServiceName = cfg.Name
+	metrics, err := observability.NewMetricsProvider(metricsCfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics provider: %w", err)
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return metrics.Shutdown(ctx)
+		},
+	})
+	return metrics, nil
+}
+
+// provideHTTPMetrics registers the HTTP request metrics recorded by
+// middleware.Metrics on the /metrics registry.
+func provideHTTPMetrics(metrics *observability.MetricsProvider) (*observability.HTTPMetrics, error) {
+	httpMetrics := middleware.GlobalHTTPMetrics
+	if err := metrics.Register(httpMetrics.Collectors()...); err != nil {
+		return nil, fmt.Errorf("failed to register HTTP metrics: %w", err)
+	}
+	return httpMetrics, nil
+}
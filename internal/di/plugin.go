@@ -7,19 +7,27 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	"github.com/jrjohn/arcana-cloud-go/internal/plugin/manager"
+	pluginrouter "github.com/jrjohn/arcana-cloud-go/internal/plugin/router"
 )
 
 // PluginModule provides plugin system dependencies
 var PluginModule = fx.Module("plugin",
 	fx.Provide(providePluginManager),
+	fx.Provide(provideExtensionRouter),
 	fx.Invoke(initializePluginManager),
+	fx.Invoke(restorePluginRoutes),
 )
 
 func providePluginManager(cfg *config.PluginConfig, logger *zap.Logger) *manager.Manager {
 	return manager.NewManager(cfg.PluginsDirectory, logger)
 }
 
+func provideExtensionRouter(pm *manager.Manager, logger *zap.Logger) *pluginrouter.ExtensionRouter {
+	return pluginrouter.NewExtensionRouter(pm, logger)
+}
+
 func initializePluginManager(lc fx.Lifecycle, pm *manager.Manager, cfg *config.PluginConfig, logger *zap.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -37,3 +45,16 @@ func initializePluginManager(lc fx.Lifecycle, pm *manager.Manager, cfg *config.P
 		},
 	})
 }
+
+// restorePluginRoutes routes the REST extensions of plugins enabled before
+// the restart. A plugin that cannot be routed does not stop the app.
+func restorePluginRoutes(lc fx.Lifecycle, pluginService service.PluginService, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := pluginService.RestoreRoutes(ctx); err != nil {
+				logger.Warn("Failed to restore plugin routes", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
@@ -12,13 +12,23 @@ import (
 // Repositories now delegate to the DAO layer for database operations.
 var RepositoryModule = fx.Module("repository",
 	fx.Provide(
+		provideTxManager,
 		provideUserRepository,
 		provideRefreshTokenRepository,
+		providePasswordResetTokenRepository,
+		provideAPIKeyRepository,
 		providePluginRepository,
 		providePluginExtensionRepository,
+		providePluginStateTransitionRepository,
+		provideAuditLogRepository,
 	),
 )
 
+// provideTxManager creates a TxManager that delegates to the DAO TxManager.
+func provideTxManager(txDAO dao.TxManager) repository.TxManager {
+	return impl.NewTxManager(txDAO)
+}
+
 // provideUserRepository creates a UserRepository that delegates to UserDAO.
 func provideUserRepository(userDAO dao.UserDAO) repository.UserRepository {
 	return impl.NewUserRepository(userDAO)
@@ -29,6 +39,16 @@ func provideRefreshTokenRepository(refreshTokenDAO dao.RefreshTokenDAO) reposito
 	return impl.NewRefreshTokenRepository(refreshTokenDAO)
 }
 
+// providePasswordResetTokenRepository creates a PasswordResetTokenRepository that delegates to PasswordResetTokenDAO.
+func providePasswordResetTokenRepository(resetTokenDAO dao.PasswordResetTokenDAO) repository.PasswordResetTokenRepository {
+	return impl.NewPasswordResetTokenRepository(resetTokenDAO)
+}
+
+// provideAPIKeyRepository creates an APIKeyRepository that delegates to APIKeyDAO.
+func provideAPIKeyRepository(apiKeyDAO dao.APIKeyDAO) repository.APIKeyRepository {
+	return impl.NewAPIKeyRepository(apiKeyDAO)
+}
+
 // providePluginRepository creates a PluginRepository that delegates to PluginDAO.
 func providePluginRepository(pluginDAO dao.PluginDAO) repository.PluginRepository {
 	return impl.NewPluginRepository(pluginDAO)
@@ -38,3 +58,13 @@ func providePluginRepository(pluginDAO dao.PluginDAO) repository.PluginRepositor
 func providePluginExtensionRepository(extensionDAO dao.PluginExtensionDAO) repository.PluginExtensionRepository {
 	return impl.NewPluginExtensionRepository(extensionDAO)
 }
+
+// providePluginStateTransitionRepository creates a PluginStateTransitionRepository that delegates to PluginStateTransitionDAO.
+func providePluginStateTransitionRepository(transitionDAO dao.PluginStateTransitionDAO) repository.PluginStateTransitionRepository {
+	return impl.NewPluginStateTransitionRepository(transitionDAO)
+}
+
+// provideAuditLogRepository creates an AuditLogRepository that delegates to AuditLogDAO.
+func provideAuditLogRepository(auditLogDAO dao.AuditLogDAO) repository.AuditLogRepository {
+	return impl.NewAuditLogRepository(auditLogDAO)
+}
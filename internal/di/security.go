@@ -1,9 +1,11 @@
 package di
 
 import (
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
@@ -13,17 +15,52 @@ var SecurityModule = fx.Module("security",
 		provideJWTProvider,
 		providePasswordHasher,
 		provideSecurityService,
+		provideTOTPProvider,
+		provideTokenDenylist,
+		provideChallengeStore,
 	),
 )
 
-func provideJWTProvider(cfg *config.JWTConfig) *security.JWTProvider {
-	return security.NewJWTProvider(cfg)
+func provideJWTProvider(cfg *config.JWTConfig, rbacCfg *config.RBACConfig) (*security.JWTProvider, error) {
+	jwtProvider, err := security.LoadJWTProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	jwtProvider.SetRolePermissions(security.NewRolePermissions(rbacCfg.RolePermissions))
+	return jwtProvider, nil
 }
 
-func providePasswordHasher() *security.PasswordHasher {
-	return security.NewPasswordHasher()
+func providePasswordHasher(cfg *config.PasswordConfig) (*security.PasswordHasher, error) {
+	return security.NewPasswordHasherWithConfig(security.HasherConfig{
+		Algorithm:         cfg.Algorithm,
+		BcryptCost:        cfg.BcryptCost,
+		Argon2Memory:      cfg.Argon2Memory,
+		Argon2Iterations:  cfg.Argon2Iterations,
+		Argon2Parallelism: cfg.Argon2Parallelism,
+	})
 }
 
-func provideSecurityService(jwtProvider *security.JWTProvider) *security.SecurityService {
-	return security.NewSecurityService(jwtProvider)
+func provideSecurityService(
+	jwtProvider *security.JWTProvider,
+	apiKeyRepo repository.APIKeyRepository,
+) *security.SecurityService {
+	securityService := security.NewSecurityService(jwtProvider)
+	securityService.SetAPIKeyRepository(apiKeyRepo)
+	return securityService
+}
+
+func provideTOTPProvider(cfg *config.TOTPConfig, jwtCfg *config.JWTConfig) *security.TOTPProvider {
+	return security.NewTOTPProvider(cfg, jwtCfg.Secret)
+}
+
+// provideTokenDenylist returns nil unless access token revocation is enabled
+func provideTokenDenylist(cfg *config.JWTConfig, client *redis.Client) security.TokenDenylist {
+	if !cfg.RevocationEnabled {
+		return nil
+	}
+	return security.NewRedisTokenDenylist(client)
+}
+
+func provideChallengeStore(client *redis.Client) security.TwoFactorChallengeStore {
+	return security.NewRedisChallengeStore(client)
 }
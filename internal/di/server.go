@@ -6,13 +6,21 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
+	"github.com/jrjohn/arcana-cloud-go/internal/configserver"
+	"github.com/jrjohn/arcana-cloud-go/internal/health"
+	"github.com/jrjohn/arcana-cloud-go/internal/httpserver"
 	httpctrl "github.com/jrjohn/arcana-cloud-go/internal/controller/http"
 	grpcctrl "github.com/jrjohn/arcana-cloud-go/internal/controller/grpc"
 	"github.com/jrjohn/arcana-cloud-go/internal/middleware"
+	"github.com/jrjohn/arcana-cloud-go/internal/observability"
+	pluginrouter "github.com/jrjohn/arcana-cloud-go/internal/plugin/router"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+	"github.com/jrjohn/arcana-cloud-go/internal/websocket"
 )
 
 // HTTPServerModule provides HTTP server dependencies
@@ -20,6 +28,8 @@ var HTTPServerModule = fx.Module("http_server",
 	fx.Provide(provideGinEngine),
 	fx.Provide(provideHTTPServer),
 	fx.Invoke(registerHTTPRoutes),
+	fx.Invoke(registerConfigRefreshRoute),
+	fx.Invoke(startWebSocketHub), // before startHTTPServer so it stops after the server
 	fx.Invoke(startHTTPServer),
 )
 
@@ -29,7 +39,20 @@ var GRPCServerModule = fx.Module("grpc_server",
 	fx.Invoke(startGRPCServer),
 )
 
-func provideGinEngine(cfg *config.AppConfig, logger *zap.Logger) *gin.Engine {
+// ginEngineParams holds the gin engine dependencies. The tracer provider is
+// optional; supply a trace.TracerProvider to the app to trace HTTP requests.
+type ginEngineParams struct {
+	fx.In
+
+	AppConfig      *config.AppConfig
+	ServerConfig   *config.ServerConfig
+	Logger         *zap.Logger
+	HTTPMetrics    *observability.HTTPMetrics // middleware.Metrics, registered for /metrics
+	TracerProvider trace.TracerProvider `optional:"true"`
+}
+
+func provideGinEngine(p ginEngineParams) *gin.Engine {
+	cfg, serverCfg, logger := p.AppConfig, p.ServerConfig, p.Logger
 	if !cfg.Debug {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -39,12 +62,34 @@ func provideGinEngine(cfg *config.AppConfig, logger *zap.Logger) *gin.Engine {
 	// Global middleware
 	router.Use(middleware.Recovery(logger))
 	router.Use(middleware.RequestID())
-	router.Use(middleware.Logger(logger))
+	router.Use(middleware.LoggerWithConfig(logger, loggerConfig(serverCfg.RequestLog)))
+	router.Use(middleware.Metrics())
+	if p.TracerProvider != nil {
+		router.Use(middleware.Tracing(p.TracerProvider.Tracer(cfg.Name)))
+	}
 	router.Use(middleware.CORS(middleware.DefaultCORSConfig()))
+	router.Use(middleware.ContentNegotiation())
+	router.Use(middleware.GzipWithConfig(middleware.DefaultGzipConfig()))
+	router.Use(middleware.Timeout(serverCfg.RequestTimeout))
+	router.Use(middleware.MaxBodySize(serverCfg.MaxBodySize))
 
 	return router
 }
 
+// loggerConfig applies the request log settings over the middleware defaults
+func loggerConfig(cfg config.RequestLogConfig) middleware.LoggerConfig {
+	loggerCfg := middleware.DefaultLoggerConfig()
+	loggerCfg.CaptureBodies = cfg.CaptureBodies
+	loggerCfg.DebugHeader = cfg.DebugHeader
+	if cfg.MaxBodySize > 0 {
+		loggerCfg.MaxBodySize = cfg.MaxBodySize
+	}
+	if len(cfg.RedactFields) > 0 {
+		loggerCfg.RedactFields = cfg.RedactFields
+	}
+	return loggerCfg
+}
+
 func provideHTTPServer(cfg *config.ServerConfig, router *gin.Engine) *http.Server {
 	return &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
@@ -65,46 +110,116 @@ type Controllers struct {
 
 	Auth   *httpctrl.AuthController
 	User   *httpctrl.UserController
+	APIKey *httpctrl.APIKeyController
 	Plugin *httpctrl.PluginController
 	SSR    *httpctrl.SSRController
 	Job    *httpctrl.JobController
+	Audit  *httpctrl.AuditController
+	Report *httpctrl.ReportController
 }
 
-func registerHTTPRoutes(router *gin.Engine, controllers Controllers) {
-	// Health endpoints
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-	})
-	router.GET("/ready", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+// configRefreshParams holds the optional config client; supply a
+// *configserver.ConfigClient to the app to expose POST /actuator/refresh.
+type configRefreshParams struct {
+	fx.In
+
+	Router       *gin.Engine
+	ConfigClient *configserver.ConfigClient `optional:"true"`
+}
+
+// registerConfigRefreshRoute lets trusted callers refresh the config client
+// on demand instead of waiting for its RefreshInterval
+func registerConfigRefreshRoute(p configRefreshParams) {
+	if p.ConfigClient == nil {
+		return
+	}
+	p.Router.POST("/actuator/refresh", gin.WrapH(configserver.NewRefreshHandler(p.ConfigClient)))
+}
+
+// webSocketParams holds the optional WebSocket handler; supply a
+// *websocket.Handler to the app to serve WebSocket connections under /api/v1.
+type webSocketParams struct {
+	fx.In
+
+	Lifecycle     fx.Lifecycle
+	Router        *gin.Engine
+	Logger        *zap.Logger
+	TokenDenylist security.TokenDenylist
+	Handler       *websocket.Handler `optional:"true"`
+}
+
+// startWebSocketHub registers the WebSocket routes and runs the hub for the
+// app's lifetime. Handshakes check the token denylist like the HTTP API. On
+// stop the hub notifies and drains its clients; hijacked WebSocket
+// connections are not closed by http.Server.Shutdown.
+func startWebSocketHub(p webSocketParams) {
+	if p.Handler == nil {
+		return
+	}
+	if p.TokenDenylist != nil {
+		p.Handler.SetTokenDenylist(p.TokenDenylist)
+	}
+	p.Handler.RegisterRoutes(p.Router.Group("/api/v1"))
+
+	hub := p.Handler.GetHub()
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			p.Logger.Info("Starting WebSocket hub")
+			go hub.Run()
+			p.Handler.StartHeartbeat()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			p.Logger.Info("Stopping WebSocket hub")
+			return hub.Shutdown(ctx)
+		},
 	})
+}
 
-	// API routes
-	api := router.Group("/api/v1")
+func registerHTTPRoutes(router *gin.Engine, controllers Controllers, checker *health.Checker, metrics *observability.MetricsProvider, jwtProvider *security.JWTProvider, extensionRouter *pluginrouter.ExtensionRouter) {
+	// Health endpoints; /ready is kept for probes predating /health/ready
+	router.GET("/health", gin.WrapF(health.Handler(checker)))
+	router.GET("/health/live", gin.WrapF(health.LiveHandler()))
+	router.GET("/health/ready", gin.WrapF(health.ReadyHandler(checker)))
+	router.GET("/ready", gin.WrapF(health.ReadyHandler(checker)))
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Public keys for verifying RS256/ES256 tokens
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jwtProvider.JWKS())
+	})
 
-	controllers.Auth.RegisterRoutes(api)
-	controllers.User.RegisterRoutes(api)
-	controllers.Plugin.RegisterRoutes(api)
-	controllers.SSR.RegisterRoutes(api)
-	controllers.Job.RegisterRoutes(api)
+	// API routes, under /api/v1, /api/v2 and /api choosing the version by
+	// header; controllers branch on middleware.GetAPIVersion where they differ
+	middleware.RegisterAPIVersions(router, middleware.DefaultAPIVersionConfig(), func(api *gin.RouterGroup) {
+		controllers.Auth.RegisterRoutes(api)
+		controllers.User.RegisterRoutes(api)
+		controllers.APIKey.RegisterRoutes(api)
+		controllers.Plugin.RegisterRoutes(api)
+		controllers.SSR.RegisterRoutes(api)
+		controllers.Job.RegisterRoutes(api)
+		controllers.Audit.RegisterRoutes(api)
+		controllers.Report.RegisterRoutes(api)
+
+		// REST extensions of enabled plugins
+		extensionRouter.RegisterRoutes(api)
+	})
 }
 
-func startHTTPServer(lc fx.Lifecycle, server *http.Server, cfg *config.DeploymentConfig, logger *zap.Logger) {
+// startHTTPServer serves HTTP for the app's lifetime. It is invoked after
+// every other lifecycle hook but the gRPC server's, so on stop in-flight
+// requests drain before the WebSocket hub, workers, plugins and database
+// connections they may use are stopped.
+func startHTTPServer(lc fx.Lifecycle, server *http.Server, cfg *config.ServerConfig, logger *zap.Logger) {
 	// Always start HTTP server for health endpoints
 	// In layered mode, non-controller layers only serve /health and /ready
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			logger.Info("Starting HTTP server", zap.String("address", server.Addr))
-			go func() {
-				if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-					logger.Error("HTTP server error", zap.Error(err))
-				}
-			}()
-			return nil
+			return httpserver.Start(server, logger)
 		},
 		OnStop: func(ctx context.Context) error {
-			logger.Info("Stopping HTTP server")
-			return server.Shutdown(ctx)
+			return httpserver.Shutdown(ctx, server, cfg.ShutdownTimeout, logger)
 		},
 	})
 }
@@ -1,14 +1,20 @@
 package di
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
 	"time"
 
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	serviceimpl "github.com/jrjohn/arcana-cloud-go/internal/domain/service/impl"
+	"github.com/jrjohn/arcana-cloud-go/internal/plugin/manager"
+	pluginrouter "github.com/jrjohn/arcana-cloud-go/internal/plugin/router"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
@@ -17,18 +23,25 @@ var ServiceModule = fx.Module("service",
 	fx.Provide(
 		provideAuthService,
 		provideUserService,
+		provideAPIKeyService,
 		providePluginService,
 		provideSSRService,
+		provideAuditService,
 	),
 )
 
 func provideAuthService(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	txManager repository.TxManager,
 	jwtProvider *security.JWTProvider,
 	passwordHasher *security.PasswordHasher,
+	totpProvider *security.TOTPProvider,
+	tokenDenylist security.TokenDenylist,
+	challengeStore security.TwoFactorChallengeStore,
 ) service.AuthService {
-	return serviceimpl.NewAuthService(userRepo, refreshTokenRepo, jwtProvider, passwordHasher)
+	return serviceimpl.NewAuthService(userRepo, refreshTokenRepo, resetTokenRepo, txManager, jwtProvider, passwordHasher, totpProvider, tokenDenylist, challengeStore)
 }
 
 func provideUserService(
@@ -38,12 +51,47 @@ func provideUserService(
 	return serviceimpl.NewUserService(userRepo, passwordHasher)
 }
 
+func provideAPIKeyService(apiKeyRepo repository.APIKeyRepository) service.APIKeyService {
+	return serviceimpl.NewAPIKeyService(apiKeyRepo)
+}
+
+func provideAuditService(
+	auditRepo repository.AuditLogRepository,
+	cfg *config.AuditConfig,
+	logger *zap.Logger,
+) service.AuditService {
+	return serviceimpl.NewAuditService(auditRepo, logger, cfg.FailOnError)
+}
+
 func providePluginService(
 	pluginRepo repository.PluginRepository,
 	extensionRepo repository.PluginExtensionRepository,
+	transitionRepo repository.PluginStateTransitionRepository,
+	txManager repository.TxManager,
 	cfg *config.PluginConfig,
-) service.PluginService {
-	return serviceimpl.NewPluginService(pluginRepo, extensionRepo, cfg.PluginsDirectory)
+	pluginManager *manager.Manager,
+	extensionRouter *pluginrouter.ExtensionRouter,
+) (service.PluginService, error) {
+	var publicKey ed25519.PublicKey
+	if cfg.SigningPublicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(cfg.SigningPublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, errors.New("plugin.signing_public_key must be a base64 Ed25519 public key")
+		}
+		publicKey = key
+	}
+
+	return serviceimpl.NewPluginServiceWithOptions(pluginRepo, extensionRepo, transitionRepo, txManager, cfg.PluginsDirectory, serviceimpl.PluginServiceOptions{
+		Reloader: pluginManager,
+		Routes:   extensionRouter,
+		Hooks:    pluginManager,
+		Download: serviceimpl.PluginDownloadConfig{
+			MaxSize:      cfg.MaxUploadSize,
+			Timeout:      cfg.DownloadTimeout,
+			AllowedHosts: cfg.AllowedHosts,
+			PublicKey:    publicKey,
+		},
+	}), nil
 }
 
 func provideSSRService(cfg *config.SSRConfig) service.SSRService {
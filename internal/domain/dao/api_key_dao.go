@@ -0,0 +1,20 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// APIKeyDAO extends BaseDAO with API key-specific data access operations.
+type APIKeyDAO interface {
+	BaseDAO[entity.APIKey, uint]
+
+	// FindByKeyHash retrieves an API key by the hash of its value.
+	// Returns nil, nil if the key is not found.
+	FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+
+	// Revoke marks an API key as revoked if it has not been revoked yet.
+	// Returns false if the key does not exist or was already revoked.
+	Revoke(ctx context.Context, id uint) (bool, error)
+}
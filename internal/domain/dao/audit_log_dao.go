@@ -0,0 +1,24 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// AuditLogDAO extends BaseDAO with audit log query operations.
+type AuditLogDAO interface {
+	BaseDAO[entity.AuditLog, uint]
+
+	// Query retrieves a page of audit logs matching the query, newest first.
+	// The query must be normalized.
+	Query(ctx context.Context, query *entity.AuditQuery) ([]*entity.AuditLog, int64, error)
+
+	// DeleteBefore removes the audit logs recorded before the given time,
+	// enforcing retention, and returns how many were removed.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// CountBefore counts the audit logs DeleteBefore would remove.
+	CountBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -1,23 +1,44 @@
 // Package dao defines data access object interfaces for database abstraction.
 // The DAO layer provides a clean separation between repository business logic
-// and database-specific implementations (MySQL, PostgreSQL, MongoDB).
+// and database-specific implementations (MySQL, PostgreSQL, MongoDB,
+// Cassandra).
 package dao
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrConcurrentModification is returned by Update on a versioned entity that
+// was changed by someone else since it was read.
+var ErrConcurrentModification = errors.New("concurrent modification")
+
+// ErrUnsupported is returned by operations a database backend cannot serve
+// efficiently, such as user search on Cassandra.
+var ErrUnsupported = errors.New("not supported by this database")
+
+// DefaultBatchSize is the number of rows CreateBatch inserts per statement on
+// SQL databases unless configured otherwise.
+const DefaultBatchSize = 100
+
 // BaseDAO defines common CRUD operations for all DAOs.
 // T is the entity type, ID is the identifier type (uint for SQL, string for MongoDB).
 type BaseDAO[T any, ID comparable] interface {
 	// Create inserts a new entity into the database.
 	Create(ctx context.Context, entity *T) error
 
+	// CreateBatch inserts entities in as few round trips as possible and
+	// sets their generated IDs.
+	CreateBatch(ctx context.Context, entities []*T) error
+
 	// FindByID retrieves an entity by its primary key.
 	// Returns nil, nil if the entity is not found.
 	FindByID(ctx context.Context, id ID) (*T, error)
 
 	// Update modifies an existing entity in the database.
+	// Entities with a LockVersion are only updated if the stored version
+	// matches; the version is then incremented, and ErrConcurrentModification
+	// is returned on a mismatch.
 	Update(ctx context.Context, entity *T) error
 
 	// Delete removes an entity by its ID.
@@ -34,6 +55,11 @@ type BaseDAO[T any, ID comparable] interface {
 
 	// ExistsBy checks if an entity exists by a field value.
 	ExistsBy(ctx context.Context, field string, value any) (bool, error)
+
+	// FindBy retrieves the entities matching query, which may only use the
+	// entity's queryable fields. Returns an error wrapping ErrInvalidQuery
+	// for any other field.
+	FindBy(ctx context.Context, query Query) ([]*T, error)
 }
 
 // QueryOption represents optional query parameters for advanced queries.
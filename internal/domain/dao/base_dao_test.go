@@ -105,3 +105,31 @@ func TestQueryOption(t *testing.T) {
 func ptr(s string) *string {
 	return &s
 }
+
+func TestCursor_RoundTrip(t *testing.T) {
+	id, err := DecodeCursor(EncodeCursor(42))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), id)
+
+	id, err = DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Zero(t, id)
+
+	for _, cursor := range []string{"%%%", EncodeCursor(0), "YWJj"} {
+		_, err := DecodeCursor(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
+
+func TestNewCursorResult(t *testing.T) {
+	idOf := func(s *string) uint { return uint(len(*s)) }
+	items := []*string{ptr("a"), ptr("bb"), ptr("ccc")}
+
+	result := NewCursorResult(items, 2, idOf)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, EncodeCursor(2), result.NextCursor)
+
+	result = NewCursorResult(items, 3, idOf)
+	assert.Len(t, result.Items, 3)
+	assert.Empty(t, result.NextCursor)
+}
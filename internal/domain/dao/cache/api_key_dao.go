@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// apiKeyDAO caches API keys found by ID and key hash.
+type apiKeyDAO struct {
+	dao.APIKeyDAO
+	cache *entityCache[entity.APIKey]
+}
+
+// APIKeys decorates an APIKeyDAO with the cache, unless API keys are not
+// cached.
+func (c *Caches) APIKeys(inner dao.APIKeyDAO) dao.APIKeyDAO {
+	if !c.Caching(EntityAPIKeys) {
+		return inner
+	}
+	return &apiKeyDAO{
+		APIKeyDAO: inner,
+		cache:     newEntityCache(c, EntityAPIKeys, func(k *entity.APIKey) uint { return k.ID }),
+	}
+}
+
+// Create inserts an API key and drops the cached not-found results it ends.
+func (d *apiKeyDAO) Create(ctx context.Context, key *entity.APIKey) error {
+	if err := d.APIKeyDAO.Create(ctx, key); err != nil {
+		return err
+	}
+	d.cache.invalidate(ctx, d.keys(key)...)
+	return nil
+}
+
+// CreateBatch inserts API keys and drops the cached not-found results they
+// end.
+func (d *apiKeyDAO) CreateBatch(ctx context.Context, keys []*entity.APIKey) error {
+	if err := d.APIKeyDAO.CreateBatch(ctx, keys); err != nil {
+		return err
+	}
+	var cacheKeys []string
+	for _, key := range keys {
+		cacheKeys = append(cacheKeys, d.keys(key)...)
+	}
+	d.cache.invalidate(ctx, cacheKeys...)
+	return nil
+}
+
+// FindByID retrieves an API key by ID, from the cache if possible.
+func (d *apiKeyDAO) FindByID(ctx context.Context, id uint) (*entity.APIKey, error) {
+	return d.cache.findByID(ctx, id, d.APIKeyDAO.FindByID)
+}
+
+// FindByKeyHash retrieves an API key by the hash of its value, from the
+// cache if possible.
+func (d *apiKeyDAO) FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	return d.cache.findBy(ctx, "key_hash", keyHash,
+		func(k *entity.APIKey) bool { return k.KeyHash == keyHash }, d.APIKeyDAO.FindByKeyHash)
+}
+
+// Update saves an API key and drops its cached entries.
+func (d *apiKeyDAO) Update(ctx context.Context, key *entity.APIKey) error {
+	err := d.APIKeyDAO.Update(ctx, key)
+	d.cache.invalidate(ctx, d.keys(key)...)
+	return err
+}
+
+// Delete soft-deletes an API key and drops its cached entry.
+func (d *apiKeyDAO) Delete(ctx context.Context, id uint) error {
+	err := d.APIKeyDAO.Delete(ctx, id)
+	d.cache.invalidate(ctx, d.cache.idKey(id))
+	return err
+}
+
+// Revoke revokes an API key and drops its cached entry.
+func (d *apiKeyDAO) Revoke(ctx context.Context, id uint) (bool, error) {
+	revoked, err := d.APIKeyDAO.Revoke(ctx, id)
+	d.cache.invalidate(ctx, d.cache.idKey(id))
+	return revoked, err
+}
+
+// keys returns the cache keys of an API key.
+func (d *apiKeyDAO) keys(key *entity.APIKey) []string {
+	return []string{d.cache.idKey(key.ID), d.cache.lookupKey("key_hash", key.KeyHash)}
+}
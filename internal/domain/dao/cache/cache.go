@@ -0,0 +1,372 @@
+// Package cache provides DAO decorators serving lookups by ID and by unique
+// key from a Store, so repeated reads of the same entity skip the database.
+//
+// Entries are written on a miss and dropped whenever the decorated DAO
+// changes the entity, and otherwise live for the configured TTL. Lookups by
+// key are cached as the ID of the entity found, so changing an entity only
+// has to drop its ID entry; a key entry pointing at an entity that no longer
+// has that key is treated as a miss.
+//
+// Reads made in a transaction, or with dao.ForcePrimary, bypass the cache.
+// Entries dropped in a transaction are dropped again once it ends, since
+// other callers may have cached the old values before the commit.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+)
+
+// Names of the entities that can be cached.
+const (
+	EntityUsers   = "users"
+	EntityPlugins = "plugins"
+	EntityAPIKeys = "api_keys"
+)
+
+// DefaultTTL is how long entries live when no TTL is configured.
+const DefaultTTL = 5 * time.Minute
+
+// Options configures the decorators.
+type Options struct {
+	// Entities lists the entities to cache; DAOs of other entities are
+	// returned undecorated.
+	Entities []string
+
+	// TTL is how long entries live unless the entity changes first.
+	TTL time.Duration
+
+	// CacheNotFound also caches lookups that found nothing. Creating the
+	// entity through a decorated DAO drops them; creating it by other means
+	// leaves it unseen until the TTL passes.
+	CacheNotFound bool
+}
+
+// Caches creates the caching decorators, all sharing one Store and Metrics.
+type Caches struct {
+	store    Store
+	options  Options
+	entities map[string]bool
+	metrics  *Metrics
+}
+
+// New creates Caches storing entries in store. Returns an error if options
+// name an entity that cannot be cached.
+func New(store Store, options Options) (*Caches, error) {
+	if options.TTL <= 0 {
+		options.TTL = DefaultTTL
+	}
+
+	entities := make(map[string]bool, len(options.Entities))
+	for _, name := range options.Entities {
+		switch name {
+		case EntityUsers, EntityPlugins, EntityAPIKeys:
+			entities[name] = true
+		default:
+			return nil, fmt.Errorf("unsupported DAO cache entity %q", name)
+		}
+	}
+
+	return &Caches{
+		store:    store,
+		options:  options,
+		entities: entities,
+		metrics:  newMetrics(options.Entities),
+	}, nil
+}
+
+// Metrics returns the hit and miss counts of the decorators.
+func (c *Caches) Metrics() *Metrics {
+	return c.metrics
+}
+
+// Caching reports whether the DAO of the given entity is decorated.
+func (c *Caches) Caching(entity string) bool {
+	return c.entities[entity]
+}
+
+// TxManager decorates a TxManager so the decorated DAOs know when they run
+// in a transaction. Every DAO decorated by c must be used through it. Returns
+// inner if no entity is cached.
+func (c *Caches) TxManager(inner dao.TxManager) dao.TxManager {
+	if len(c.entities) == 0 {
+		return inner
+	}
+	return &txManager{TxManager: inner, store: c.store, metrics: c.metrics}
+}
+
+// Stats are the counts of one entity's cache.
+type Stats struct {
+	Hits   int64
+	Misses int64
+	// Errors counts store failures; the lookup then falls through to the
+	// DAO, and a failed drop leaves the entry until its TTL passes.
+	Errors int64
+}
+
+// Metrics counts cache hits and misses per entity.
+type Metrics struct {
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+func newMetrics(entities []string) *Metrics {
+	m := &Metrics{stats: make(map[string]*Stats, len(entities))}
+	for _, entity := range entities {
+		m.stats[entity] = &Stats{}
+	}
+	return m
+}
+
+// Snapshot returns the counts of every cached entity.
+func (m *Metrics) Snapshot() map[string]Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]Stats, len(m.stats))
+	for entity, stats := range m.stats {
+		snapshot[entity] = *stats
+	}
+	return snapshot
+}
+
+func (m *Metrics) add(entity string, update func(*Stats)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.stats[entity]
+	if !ok {
+		stats = &Stats{}
+		m.stats[entity] = stats
+	}
+	update(stats)
+}
+
+func (m *Metrics) hit(entity string)     { m.add(entity, func(s *Stats) { s.Hits++ }) }
+func (m *Metrics) miss(entity string)    { m.add(entity, func(s *Stats) { s.Misses++ }) }
+func (m *Metrics) failure(entity string) { m.add(entity, func(s *Stats) { s.Errors++ }) }
+
+// entityCache caches the entities of one DAO. Entities are stored gob
+// encoded, so callers never share a cached value, and fields hidden from
+// JSON such as password hashes survive. An empty value marks a lookup that
+// found nothing.
+type entityCache[T any] struct {
+	name    string
+	store   Store
+	options Options
+	metrics *Metrics
+	id      func(*T) uint
+}
+
+func newEntityCache[T any](c *Caches, name string, id func(*T) uint) *entityCache[T] {
+	return &entityCache[T]{
+		name:    name,
+		store:   c.store,
+		options: c.options,
+		metrics: c.metrics,
+		id:      id,
+	}
+}
+
+// idKey returns the key of the entity with the given ID.
+func (c *entityCache[T]) idKey(id uint) string {
+	return c.name + ":id:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// lookupKey returns the key holding the ID of the entity whose field has
+// the given value.
+func (c *entityCache[T]) lookupKey(field, value string) string {
+	return c.name + ":" + field + ":" + value
+}
+
+// findByID returns the entity with the given ID, loading it on a miss.
+func (c *entityCache[T]) findByID(ctx context.Context, id uint, load func(context.Context, uint) (*T, error)) (*T, error) {
+	if bypass(ctx) {
+		return load(ctx, id)
+	}
+
+	key := c.idKey(id)
+	if value, ok := c.get(ctx, key); ok {
+		if entity, ok := c.decode(value); ok {
+			c.metrics.hit(c.name)
+			return entity, nil
+		}
+	}
+	c.metrics.miss(c.name)
+
+	entity, err := load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.put(ctx, key, entity)
+	return entity, nil
+}
+
+// findBy returns the entity whose field has the given value, loading it on a
+// miss. has reports whether a cached entity still has that value.
+func (c *entityCache[T]) findBy(
+	ctx context.Context,
+	field, value string,
+	has func(*T) bool,
+	load func(context.Context, string) (*T, error),
+) (*T, error) {
+	if bypass(ctx) {
+		return load(ctx, value)
+	}
+
+	key := c.lookupKey(field, value)
+	if raw, ok := c.get(ctx, key); ok {
+		if len(raw) == 0 {
+			if c.options.CacheNotFound {
+				c.metrics.hit(c.name)
+				return nil, nil
+			}
+		} else if id, err := strconv.ParseUint(string(raw), 10, 64); err == nil {
+			if cached, ok := c.get(ctx, c.idKey(uint(id))); ok {
+				if entity, ok := c.decode(cached); ok && entity != nil && has(entity) {
+					c.metrics.hit(c.name)
+					return entity, nil
+				}
+			}
+		}
+	}
+	c.metrics.miss(c.name)
+
+	entity, err := load(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		if c.options.CacheNotFound {
+			c.set(ctx, key, []byte{})
+		}
+		return nil, nil
+	}
+
+	id := c.id(entity)
+	c.put(ctx, c.idKey(id), entity)
+	c.set(ctx, key, []byte(strconv.FormatUint(uint64(id), 10)))
+	return entity, nil
+}
+
+// invalidate drops the given keys, and once more when the transaction of
+// ctx ends.
+func (c *entityCache[T]) invalidate(ctx context.Context, keys ...string) {
+	if tx, ok := ctx.Value(txContextKey{}).(*pendingInvalidations); ok {
+		tx.add(c.name, keys)
+	}
+	if err := c.store.Delete(ctx, keys...); err != nil {
+		c.metrics.failure(c.name)
+	}
+}
+
+// get reads a raw value, counting store failures.
+func (c *entityCache[T]) get(ctx context.Context, key string) ([]byte, bool) {
+	value, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.metrics.failure(c.name)
+		return nil, false
+	}
+	return value, ok
+}
+
+// set writes a raw value, counting store failures.
+func (c *entityCache[T]) set(ctx context.Context, key string, value []byte) {
+	if err := c.store.Set(ctx, key, value, c.options.TTL); err != nil {
+		c.metrics.failure(c.name)
+	}
+}
+
+// put caches entity under key, or that nothing was found if entity is nil
+// and not-found results are cached.
+func (c *entityCache[T]) put(ctx context.Context, key string, entity *T) {
+	if entity == nil {
+		if c.options.CacheNotFound {
+			c.set(ctx, key, []byte{})
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entity); err != nil {
+		c.metrics.failure(c.name)
+		return
+	}
+	c.set(ctx, key, buf.Bytes())
+}
+
+// decode returns the entity in value, or nil if value marks a lookup that
+// found nothing. Returns false if value cannot be used.
+func (c *entityCache[T]) decode(value []byte) (*T, bool) {
+	if len(value) == 0 {
+		return nil, c.options.CacheNotFound
+	}
+
+	entity := new(T)
+	if err := gob.NewDecoder(bytes.NewReader(value)).Decode(entity); err != nil {
+		c.metrics.failure(c.name)
+		return nil, false
+	}
+	return entity, true
+}
+
+// txContextKey marks a context running in a transaction started through a
+// decorated TxManager.
+type txContextKey struct{}
+
+// pendingInvalidations collects the keys dropped in a transaction, by
+// entity.
+type pendingInvalidations struct {
+	mu   sync.Mutex
+	keys map[string][]string
+}
+
+func (p *pendingInvalidations) add(entity string, keys []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys[entity] = append(p.keys[entity], keys...)
+}
+
+// bypass reports whether reads with ctx must skip the cache: values read in
+// a transaction may never be committed, and ForcePrimary asks for the
+// latest values.
+func bypass(ctx context.Context) bool {
+	_, inTx := ctx.Value(txContextKey{}).(*pendingInvalidations)
+	return inTx || dao.IsPrimaryForced(ctx)
+}
+
+// txManager marks the contexts of its transactions, and drops the keys
+// invalidated in a transaction once more after it ends.
+type txManager struct {
+	dao.TxManager
+	store   Store
+	metrics *Metrics
+}
+
+// WithTransaction runs fn in a transaction of the decorated TxManager.
+func (m *txManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*pendingInvalidations); ok {
+		return m.TxManager.WithTransaction(ctx, fn)
+	}
+
+	pending := &pendingInvalidations{keys: make(map[string][]string)}
+	err := m.TxManager.WithTransaction(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, txContextKey{}, pending))
+	})
+
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	for entity, keys := range pending.keys {
+		if deleteErr := m.store.Delete(context.WithoutCancel(ctx), keys...); deleteErr != nil {
+			m.metrics.failure(entity)
+		}
+	}
+	return err
+}
@@ -0,0 +1,284 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	gormdao "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/gorm"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
+)
+
+// countingUserDAO counts the lookups reaching the database
+type countingUserDAO struct {
+	dao.UserDAO
+	lookups int
+}
+
+func (d *countingUserDAO) FindByID(ctx context.Context, id uint) (*entity.User, error) {
+	d.lookups++
+	return d.UserDAO.FindByID(ctx, id)
+}
+
+func (d *countingUserDAO) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	d.lookups++
+	return d.UserDAO.FindByUsername(ctx, username)
+}
+
+func (d *countingUserDAO) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	d.lookups++
+	return d.UserDAO.FindByEmail(ctx, email)
+}
+
+type userFixture struct {
+	inner  *countingUserDAO
+	users  dao.UserDAO
+	tx     dao.TxManager
+	caches *Caches
+}
+
+func setupUsers(t *testing.T, cacheNotFound bool) *userFixture {
+	db := testutil.NewTestSQLiteDB(t)
+	caches, err := New(NewMemoryStore(100), Options{
+		Entities:      []string{EntityUsers},
+		TTL:           time.Minute,
+		CacheNotFound: cacheNotFound,
+	})
+	require.NoError(t, err)
+
+	inner := &countingUserDAO{UserDAO: gormdao.NewUserDAO(db)}
+	return &userFixture{
+		inner:  inner,
+		users:  caches.Users(inner),
+		tx:     caches.TxManager(gormdao.NewTxManager(db)),
+		caches: caches,
+	}
+}
+
+func createUser(t *testing.T, users dao.UserDAO, username string) *entity.User {
+	user := &entity.User{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: "hashed",
+		Role:     entity.RoleUser,
+		IsActive: true,
+	}
+	require.NoError(t, users.Create(context.Background(), user))
+	return user
+}
+
+func TestNew_RejectsUnknownEntity(t *testing.T) {
+	_, err := New(NewMemoryStore(0), Options{Entities: []string{"audit_logs"}})
+	assert.Error(t, err)
+}
+
+func TestCaches_LeavesUncachedEntitiesUndecorated(t *testing.T) {
+	caches, err := New(NewMemoryStore(0), Options{Entities: []string{EntityPlugins}})
+	require.NoError(t, err)
+
+	inner := gormdao.NewUserDAO(testutil.NewTestSQLiteDB(t))
+	assert.Same(t, inner, caches.Users(inner))
+}
+
+func TestUserCache_FindByID(t *testing.T) {
+	f := setupUsers(t, false)
+	ctx := context.Background()
+	user := createUser(t, f.users, "alice")
+
+	first, err := f.users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	second, err := f.users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, f.inner.lookups)
+	assert.Equal(t, "alice", second.Username)
+	// Hidden fields survive the cache
+	assert.Equal(t, "hashed", second.Password)
+	// Callers do not share cached values
+	assert.NotSame(t, first, second)
+	assert.Equal(t, Stats{Hits: 1, Misses: 1}, f.caches.Metrics().Snapshot()[EntityUsers])
+}
+
+func TestUserCache_UpdateInvalidates(t *testing.T) {
+	f := setupUsers(t, false)
+	ctx := context.Background()
+	user := createUser(t, f.users, "alice")
+
+	_, err := f.users.FindByUsername(ctx, "alice")
+	require.NoError(t, err)
+
+	user.Username = "alicia"
+	require.NoError(t, f.users.Update(ctx, user))
+
+	found, err := f.users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alicia", found.Username)
+
+	// The cached lookup by the old username no longer matches the user
+	found, err = f.users.FindByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestUserCache_DeleteInvalidates(t *testing.T) {
+	f := setupUsers(t, false)
+	ctx := context.Background()
+	user := createUser(t, f.users, "alice")
+
+	_, err := f.users.FindByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	require.NoError(t, f.users.Delete(ctx, user.ID))
+
+	found, err := f.users.FindByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestUserCache_NotFoundNotCachedByDefault(t *testing.T) {
+	f := setupUsers(t, false)
+	ctx := context.Background()
+
+	for range 2 {
+		found, err := f.users.FindByUsername(ctx, "ghost")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	}
+	assert.Equal(t, 2, f.inner.lookups)
+}
+
+func TestUserCache_NotFoundCachedWhenEnabled(t *testing.T) {
+	f := setupUsers(t, true)
+	ctx := context.Background()
+
+	for range 2 {
+		found, err := f.users.FindByUsername(ctx, "ghost")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	}
+	assert.Equal(t, 1, f.inner.lookups)
+
+	// Creating the user drops the cached not-found result
+	createUser(t, f.users, "ghost")
+	found, err := f.users.FindByUsername(ctx, "ghost")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+}
+
+func TestUserCache_RestoreDropsNotFound(t *testing.T) {
+	f := setupUsers(t, true)
+	ctx := context.Background()
+	user := createUser(t, f.users, "alice")
+
+	require.NoError(t, f.users.Delete(ctx, user.ID))
+	found, err := f.users.FindByUsername(ctx, "alice")
+	require.NoError(t, err)
+	require.Nil(t, found)
+
+	require.NoError(t, f.users.Restore(ctx, user.ID))
+	found, err = f.users.FindByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.NotNil(t, found)
+}
+
+func TestUserCache_ForcePrimaryBypasses(t *testing.T) {
+	f := setupUsers(t, false)
+	user := createUser(t, f.users, "alice")
+	ctx := dao.ForcePrimary(context.Background())
+
+	for range 2 {
+		_, err := f.users.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, f.inner.lookups)
+}
+
+func TestUserCache_Transaction(t *testing.T) {
+	f := setupUsers(t, false)
+	ctx := context.Background()
+	user := createUser(t, f.users, "alice")
+
+	_, err := f.users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	key := "users:id:" + strconv.FormatUint(uint64(user.ID), 10)
+	committed, ok, err := f.caches.store.Get(ctx, key)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	errRollback := errors.New("rollback")
+	err = f.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		user.FirstName = "Uncommitted"
+		require.NoError(t, f.users.Update(ctx, user))
+
+		// Reads in the transaction see its writes and are not cached
+		found, err := f.users.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Uncommitted", found.FirstName)
+
+		// Another caller caches the committed user meanwhile
+		return errors.Join(f.caches.store.Set(ctx, key, committed, time.Minute), errRollback)
+	})
+	require.ErrorIs(t, err, errRollback)
+
+	// The entry cached during the transaction was dropped when it ended
+	_, ok, err = f.caches.store.Get(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	found, err := f.users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, found.FirstName)
+}
+
+func TestPluginCache_UpdateStateInvalidates(t *testing.T) {
+	db := testutil.NewTestSQLiteDB(t)
+	caches, err := New(NewMemoryStore(0), Options{Entities: []string{EntityPlugins}})
+	require.NoError(t, err)
+	plugins := caches.Plugins(gormdao.NewPluginDAO(db))
+	ctx := context.Background()
+
+	plugin := &entity.Plugin{Key: "p", Name: "P", Version: "1.0.0", Type: entity.PluginTypeService, State: entity.PluginStateInstalled}
+	require.NoError(t, plugins.Create(ctx, plugin))
+
+	_, err = plugins.FindByKey(ctx, "p")
+	require.NoError(t, err)
+	require.NoError(t, plugins.UpdateState(ctx, plugin.ID, entity.PluginStateEnabled))
+
+	found, err := plugins.FindByKey(ctx, "p")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PluginStateEnabled, found.State)
+
+	require.NoError(t, plugins.DeleteByKey(ctx, "p"))
+	found, err = plugins.FindByID(ctx, plugin.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestAPIKeyCache_RevokeInvalidates(t *testing.T) {
+	db := testutil.NewTestSQLiteDB(t)
+	caches, err := New(NewMemoryStore(0), Options{Entities: []string{EntityAPIKeys}})
+	require.NoError(t, err)
+	keys := caches.APIKeys(gormdao.NewAPIKeyDAO(db))
+	ctx := context.Background()
+
+	key := &entity.APIKey{Name: "ci", Prefix: "ak_1", KeyHash: "hash", CreatedBy: 1}
+	require.NoError(t, keys.Create(ctx, key))
+
+	found, err := keys.FindByKeyHash(ctx, "hash")
+	require.NoError(t, err)
+	require.Nil(t, found.RevokedAt)
+
+	revoked, err := keys.Revoke(ctx, key.ID)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	found, err = keys.FindByKeyHash(ctx, "hash")
+	require.NoError(t, err)
+	assert.NotNil(t, found.RevokedAt)
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// pluginDAO caches plugins found by ID and key.
+type pluginDAO struct {
+	dao.PluginDAO
+	cache *entityCache[entity.Plugin]
+}
+
+// Plugins decorates a PluginDAO with the cache, unless plugins are not
+// cached.
+func (c *Caches) Plugins(inner dao.PluginDAO) dao.PluginDAO {
+	if !c.Caching(EntityPlugins) {
+		return inner
+	}
+	return &pluginDAO{
+		PluginDAO: inner,
+		cache:     newEntityCache(c, EntityPlugins, func(p *entity.Plugin) uint { return p.ID }),
+	}
+}
+
+// Create inserts a plugin and drops the cached not-found results it ends.
+func (d *pluginDAO) Create(ctx context.Context, plugin *entity.Plugin) error {
+	if err := d.PluginDAO.Create(ctx, plugin); err != nil {
+		return err
+	}
+	d.cache.invalidate(ctx, d.keys(plugin)...)
+	return nil
+}
+
+// CreateBatch inserts plugins and drops the cached not-found results they
+// end.
+func (d *pluginDAO) CreateBatch(ctx context.Context, plugins []*entity.Plugin) error {
+	if err := d.PluginDAO.CreateBatch(ctx, plugins); err != nil {
+		return err
+	}
+	var keys []string
+	for _, plugin := range plugins {
+		keys = append(keys, d.keys(plugin)...)
+	}
+	d.cache.invalidate(ctx, keys...)
+	return nil
+}
+
+// FindByID retrieves a plugin by ID, from the cache if possible.
+func (d *pluginDAO) FindByID(ctx context.Context, id uint) (*entity.Plugin, error) {
+	return d.cache.findByID(ctx, id, d.PluginDAO.FindByID)
+}
+
+// FindByKey retrieves a plugin by key, from the cache if possible.
+func (d *pluginDAO) FindByKey(ctx context.Context, key string) (*entity.Plugin, error) {
+	return d.cache.findBy(ctx, "key", key,
+		func(p *entity.Plugin) bool { return p.Key == key }, d.PluginDAO.FindByKey)
+}
+
+// Update saves a plugin and drops its cached entries.
+func (d *pluginDAO) Update(ctx context.Context, plugin *entity.Plugin) error {
+	err := d.PluginDAO.Update(ctx, plugin)
+	d.cache.invalidate(ctx, d.keys(plugin)...)
+	return err
+}
+
+// Delete soft-deletes a plugin and drops its cached entry.
+func (d *pluginDAO) Delete(ctx context.Context, id uint) error {
+	err := d.PluginDAO.Delete(ctx, id)
+	d.cache.invalidate(ctx, d.cache.idKey(id))
+	return err
+}
+
+// DeleteByKey soft-deletes a plugin by key and drops its cached entries.
+func (d *pluginDAO) DeleteByKey(ctx context.Context, key string) error {
+	plugin, err := d.PluginDAO.FindByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	err = d.PluginDAO.DeleteByKey(ctx, key)
+	keys := []string{d.cache.lookupKey("key", key)}
+	if plugin != nil {
+		keys = append(keys, d.cache.idKey(plugin.ID))
+	}
+	d.cache.invalidate(ctx, keys...)
+	return err
+}
+
+// UpdateState updates the state of a plugin and drops its cached entry.
+func (d *pluginDAO) UpdateState(ctx context.Context, id uint, state entity.PluginState) error {
+	err := d.PluginDAO.UpdateState(ctx, id, state)
+	d.cache.invalidate(ctx, d.cache.idKey(id))
+	return err
+}
+
+// keys returns the cache keys of a plugin.
+func (d *pluginDAO) keys(plugin *entity.Plugin) []string {
+	return []string{d.cache.idKey(plugin.ID), d.cache.lookupKey("key", plugin.Key)}
+}
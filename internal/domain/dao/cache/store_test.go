@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_SetGetDelete(t *testing.T) {
+	store := NewMemoryStore(0)
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Minute))
+
+	value, ok, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	require.NoError(t, store.Delete(ctx, "a", "missing"))
+	_, ok, err = store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	store := NewMemoryStore(0)
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Zero(t, store.Len())
+}
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewMemoryStore(2)
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, store.Set(ctx, "b", []byte("2"), time.Minute))
+	// Reading a makes b the least recently used
+	_, _, _ = store.Get(ctx, "a")
+	require.NoError(t, store.Set(ctx, "c", []byte("3"), time.Minute))
+
+	assert.Equal(t, 2, store.Len())
+	_, ok, _ := store.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok, _ = store.Get(ctx, "a")
+	assert.True(t, ok)
+	_, ok, _ = store.Get(ctx, "c")
+	assert.True(t, ok)
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// userDAO caches users found by ID, username and email.
+type userDAO struct {
+	dao.UserDAO
+	cache *entityCache[entity.User]
+}
+
+// Users decorates a UserDAO with the cache, unless users are not cached.
+func (c *Caches) Users(inner dao.UserDAO) dao.UserDAO {
+	if !c.Caching(EntityUsers) {
+		return inner
+	}
+	return &userDAO{
+		UserDAO: inner,
+		cache:   newEntityCache(c, EntityUsers, func(u *entity.User) uint { return u.ID }),
+	}
+}
+
+// Create inserts a user and drops the cached not-found results it ends.
+func (d *userDAO) Create(ctx context.Context, user *entity.User) error {
+	if err := d.UserDAO.Create(ctx, user); err != nil {
+		return err
+	}
+	d.cache.invalidate(ctx, d.keys(user)...)
+	return nil
+}
+
+// CreateBatch inserts users and drops the cached not-found results they end.
+func (d *userDAO) CreateBatch(ctx context.Context, users []*entity.User) error {
+	if err := d.UserDAO.CreateBatch(ctx, users); err != nil {
+		return err
+	}
+	var keys []string
+	for _, user := range users {
+		keys = append(keys, d.keys(user)...)
+	}
+	d.cache.invalidate(ctx, keys...)
+	return nil
+}
+
+// FindByID retrieves a user by ID, from the cache if possible.
+func (d *userDAO) FindByID(ctx context.Context, id uint) (*entity.User, error) {
+	return d.cache.findByID(ctx, id, d.UserDAO.FindByID)
+}
+
+// FindByUsername retrieves a user by username, from the cache if possible.
+func (d *userDAO) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	return d.cache.findBy(ctx, "username", username,
+		func(u *entity.User) bool { return u.Username == username }, d.UserDAO.FindByUsername)
+}
+
+// FindByEmail retrieves a user by email, from the cache if possible.
+func (d *userDAO) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return d.cache.findBy(ctx, "email", email,
+		func(u *entity.User) bool { return u.Email == email }, d.UserDAO.FindByEmail)
+}
+
+// FindByUsernameOrEmail retrieves a user by username, or else by email, from
+// the cache if possible.
+func (d *userDAO) FindByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (*entity.User, error) {
+	user, err := d.FindByUsername(ctx, usernameOrEmail)
+	if err != nil || user != nil {
+		return user, err
+	}
+	return d.FindByEmail(ctx, usernameOrEmail)
+}
+
+// Update saves a user and drops its cached entries.
+func (d *userDAO) Update(ctx context.Context, user *entity.User) error {
+	err := d.UserDAO.Update(ctx, user)
+	d.cache.invalidate(ctx, d.keys(user)...)
+	return err
+}
+
+// Delete soft-deletes a user and drops its cached entry.
+func (d *userDAO) Delete(ctx context.Context, id uint) error {
+	err := d.UserDAO.Delete(ctx, id)
+	d.cache.invalidate(ctx, d.cache.idKey(id))
+	return err
+}
+
+// Restore undeletes a user and drops its cached entries, including the
+// not-found results cached while it was deleted.
+func (d *userDAO) Restore(ctx context.Context, id uint) error {
+	if err := d.UserDAO.Restore(ctx, id); err != nil {
+		d.cache.invalidate(ctx, d.cache.idKey(id))
+		return err
+	}
+
+	keys := []string{d.cache.idKey(id)}
+	if d.cache.options.CacheNotFound {
+		user, err := d.UserDAO.FindByID(ctx, id)
+		if err != nil {
+			d.cache.invalidate(ctx, keys...)
+			return err
+		}
+		if user != nil {
+			keys = d.keys(user)
+		}
+	}
+	d.cache.invalidate(ctx, keys...)
+	return nil
+}
+
+// keys returns the cache keys of a user.
+func (d *userDAO) keys(user *entity.User) []string {
+	return []string{
+		d.cache.idKey(user.ID),
+		d.cache.lookupKey("username", user.Username),
+		d.cache.lookupKey("email", user.Email),
+	}
+}
@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+// ErrInvalidCursor is returned when a cursor token cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorResult is a page of entities in ascending ID order.
+// NextCursor is empty on the last page.
+type CursorResult[T any] struct {
+	Items      []*T
+	NextCursor string
+}
+
+// EncodeCursor returns the opaque token for resuming after the given ID.
+func EncodeCursor(afterID uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(afterID), 10)))
+}
+
+// DecodeCursor returns the ID encoded in a token from EncodeCursor.
+// An empty token decodes to 0, the start of the collection.
+func DecodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 0)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
+
+// NewCursorResult trims a page fetched with limit+1 rows to limit and sets
+// NextCursor when the extra row shows that more entities follow.
+func NewCursorResult[T any](items []*T, limit int, idOf func(*T) uint) *CursorResult[T] {
+	result := &CursorResult[T]{Items: items}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		result.NextCursor = EncodeCursor(idOf(result.Items[limit-1]))
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package cassandra
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+var apiKeyHashLookup = uniqueLookup[entity.APIKey]{
+	table:  "api_keys_by_hash",
+	column: "key_hash",
+	value:  func(k *entity.APIKey) string { return k.KeyHash },
+}
+
+// apiKeysTable stores API keys, found by hash through a lookup table.
+var apiKeysTable = &table[entity.APIKey]{
+	name: entity.APIKey{}.TableName(),
+	columns: []string{
+		"id", "name", "prefix", "key_hash", "scopes", "created_by", "expires_at",
+		"revoked_at", "created_at", "deleted_at",
+	},
+	fields: func(k *entity.APIKey) []any {
+		return []any{
+			&k.ID, &k.Name, &k.Prefix, &k.KeyHash, &k.Scopes, &k.CreatedBy, &k.ExpiresAt,
+			&k.RevokedAt, &k.CreatedAt, deletedAt{&k.DeletedAt},
+		}
+	},
+	id:        func(k *entity.APIKey) *uint { return &k.ID },
+	created:   func(k *entity.APIKey, now time.Time) { k.CreatedAt = now },
+	deletedAt: func(k *entity.APIKey) *gorm.DeletedAt { return &k.DeletedAt },
+	queryValues: func(k *entity.APIKey) map[string]any {
+		return map[string]any{
+			"id":       k.ID,
+			"key_hash": k.KeyHash,
+		}
+	},
+	queryFields: dao.APIKeyQueryFields,
+	uniques:     []uniqueLookup[entity.APIKey]{apiKeyHashLookup},
+}
+
+// apiKeyDAO implements dao.APIKeyDAO using Cassandra.
+type apiKeyDAO struct {
+	*baseCassandraDAO[entity.APIKey]
+}
+
+// NewAPIKeyDAO creates a new Cassandra-based APIKeyDAO.
+func NewAPIKeyDAO(session *gocql.Session, idCounter *IDCounter) dao.APIKeyDAO {
+	return &apiKeyDAO{
+		baseCassandraDAO: newBaseCassandraDAO(session, idCounter, apiKeysTable),
+	}
+}
+
+// FindByKeyHash retrieves an API key by the hash of its value.
+func (d *apiKeyDAO) FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	return d.findByUnique(ctx, apiKeyHashLookup, keyHash)
+}
+
+// Revoke marks an API key as revoked if it has not been revoked yet.
+func (d *apiKeyDAO) Revoke(ctx context.Context, id uint) (bool, error) {
+	key, err := d.get(ctx, id)
+	if err != nil || key == nil {
+		return false, err
+	}
+	// Conditioning on the creator keeps a key deleted meanwhile from being
+	// written back as a partial row
+	return d.setConditionally(ctx, id, "revoked_at = ?", "revoked_at = null AND deleted_at = null AND created_by = ?",
+		time.Now(), id, key.CreatedBy)
+}
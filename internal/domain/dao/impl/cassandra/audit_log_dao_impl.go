@@ -0,0 +1,104 @@
+package cassandra
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// auditLogsTable stores audit logs. Records are deleted for good.
+var auditLogsTable = &table[entity.AuditLog]{
+	name: entity.AuditLog{}.TableName(),
+	columns: []string{
+		"id", "actor_id", "actor", "action", "target", "ip_address", "request_id",
+		"outcome", "detail", "created_at",
+	},
+	fields: func(l *entity.AuditLog) []any {
+		return []any{
+			&l.ID, &l.ActorID, &l.Actor, &l.Action, &l.Target, &l.IPAddress, &l.RequestID,
+			&l.Outcome, &l.Detail, &l.CreatedAt,
+		}
+	},
+	id:      func(l *entity.AuditLog) *uint { return &l.ID },
+	created: func(l *entity.AuditLog, now time.Time) { l.CreatedAt = now },
+	queryValues: func(l *entity.AuditLog) map[string]any {
+		return map[string]any{
+			"id":         l.ID,
+			"actor_id":   l.ActorID,
+			"action":     l.Action,
+			"created_at": l.CreatedAt,
+		}
+	},
+	queryFields: dao.AuditLogQueryFields,
+}
+
+// auditLogDAO implements dao.AuditLogDAO using Cassandra.
+type auditLogDAO struct {
+	*baseCassandraDAO[entity.AuditLog]
+}
+
+// NewAuditLogDAO creates a new Cassandra-based AuditLogDAO.
+func NewAuditLogDAO(session *gocql.Session, idCounter *IDCounter) dao.AuditLogDAO {
+	return &auditLogDAO{
+		baseCassandraDAO: newBaseCassandraDAO(session, idCounter, auditLogsTable),
+	}
+}
+
+// FindAll retrieves audit logs with pagination, newest first.
+func (d *auditLogDAO) FindAll(ctx context.Context, page, size int) ([]*entity.AuditLog, int64, error) {
+	return d.Query(ctx, &entity.AuditQuery{Page: page, Size: size})
+}
+
+// Query retrieves a page of audit logs matching the query, newest first.
+// The logs are filtered and sorted in memory.
+func (d *auditLogDAO) Query(ctx context.Context, query *entity.AuditQuery) ([]*entity.AuditLog, int64, error) {
+	logs, err := d.scan(ctx, func(l *entity.AuditLog) bool {
+		switch {
+		case query.ActorID != nil && l.ActorID != *query.ActorID:
+			return false
+		case query.Actor != "" && l.Actor != query.Actor:
+			return false
+		case query.Action != "" && l.Action != query.Action:
+			return false
+		case query.From != nil && l.CreatedAt.Before(*query.From):
+			return false
+		case query.To != nil && !l.CreatedAt.Before(*query.To):
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortByQuery(logs, []dao.SortField{
+		{Field: "created_at", Desc: true},
+		{Field: "id", Desc: true},
+	}, auditLogsTable.queryValues)
+	return paginate(logs, query.Offset(), query.Size), int64(len(logs)), nil
+}
+
+// DeleteBefore removes the audit logs recorded before the given time.
+func (d *auditLogDAO) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	logs, err := d.scan(ctx, auditLogRecordedBefore(before))
+	if err != nil {
+		return 0, err
+	}
+	if err := d.deleteForGood(ctx, logs); err != nil {
+		return 0, err
+	}
+	return int64(len(logs)), nil
+}
+
+// CountBefore counts the audit logs recorded before the given time.
+func (d *auditLogDAO) CountBefore(ctx context.Context, before time.Time) (int64, error) {
+	return d.count(ctx, auditLogRecordedBefore(before))
+}
+
+func auditLogRecordedBefore(before time.Time) func(*entity.AuditLog) bool {
+	return func(l *entity.AuditLog) bool { return l.CreatedAt.Before(before) }
+}
@@ -0,0 +1,694 @@
+// Package cassandra provides DAO implementations for Apache Cassandra and
+// ScyllaDB.
+//
+// Each entity is stored in a table keyed by its numeric ID. Wide-column
+// stores only serve lookups by primary key, so the other lookups the DAOs
+// need go through lookup tables: unique values (username, email, plugin key,
+// token) map to the ID owning them and are claimed with lightweight
+// transactions, which also enforces their uniqueness, and parent IDs (a
+// user's refresh tokens, a plugin's extensions) map to the IDs of their
+// children. Listings page through a table in partition token order, which
+// is stable but follows neither IDs nor creation times, and user search is
+// not supported. Counting and queries no lookup table serves read the whole
+// table, so this backend suits write-heavy workloads that find entities by
+// key.
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+)
+
+// ErrDuplicateKey is returned when creating or updating an entity would take
+// a unique value, such as a username, owned by another entity.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// maxBatchEntities caps the entities written by one logged batch, keeping
+// batches under Cassandra's default size warning.
+const maxBatchEntities = 25
+
+// scanPageSize is the number of rows fetched per round trip when paging
+// through a table.
+const scanPageSize = 500
+
+// IDCounter allocates auto-incrementing IDs for Cassandra tables.
+// This provides SQL-like uint IDs for compatibility with the domain entities.
+type IDCounter struct {
+	session *gocql.Session
+}
+
+// NewIDCounter creates a new IDCounter storing its counters in the
+// id_counters table.
+func NewIDCounter(session *gocql.Session) *IDCounter {
+	return &IDCounter{session: session}
+}
+
+// NextID returns the next available ID for a given table.
+func (c *IDCounter) NextID(ctx context.Context, table string) (uint, error) {
+	return c.NextIDs(ctx, table, 1)
+}
+
+// NextIDs reserves n consecutive IDs for a given table and returns the first
+// of them. Counter columns cannot be incremented and read atomically, so the
+// last ID is advanced with a compare-and-set, retried while other writers
+// win the race.
+func (c *IDCounter) NextIDs(ctx context.Context, table string, n int) (uint, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		var last int64
+		err := c.session.Query(`SELECT last_id FROM id_counters WHERE name = ?`, table).
+			WithContext(ctx).
+			// A serial read sees the value of the last compare-and-set
+			Consistency(gocql.Consistency(gocql.Serial)).
+			Scan(&last)
+		var applied bool
+		switch {
+		case errors.Is(err, gocql.ErrNotFound):
+			applied, err = c.session.Query(`INSERT INTO id_counters (name, last_id) VALUES (?, ?) IF NOT EXISTS`, table, n).
+				WithContext(ctx).
+				MapScanCAS(map[string]any{})
+		case err == nil:
+			applied, err = c.session.Query(`UPDATE id_counters SET last_id = ? WHERE name = ? IF last_id = ?`, last+int64(n), table, last).
+				WithContext(ctx).
+				MapScanCAS(map[string]any{})
+		}
+		if err != nil {
+			return 0, err
+		}
+		if applied {
+			return uint(last) + 1, nil
+		}
+	}
+}
+
+// table describes how the entities of type T are stored.
+type table[T any] struct {
+	name string
+	// columns lists the stored columns; the first is the ID
+	columns []string
+	// fields returns the entity fields stored in columns, in order, as
+	// pointers that rows are scanned into and bound from
+	fields func(*T) []any
+	id     func(*T) *uint
+	// created stamps the timestamps of a new entity
+	created func(*T, time.Time)
+	// deletedAt returns the soft-delete marker; nil for entities deleted
+	// for good
+	deletedAt func(*T) *gorm.DeletedAt
+	// queryValues returns the values of the queryable fields
+	queryValues func(*T) map[string]any
+	queryFields dao.QueryFields
+	uniques     []uniqueLookup[T]
+	indexes     []indexLookup[T]
+}
+
+// baseCassandraDAO provides common Cassandra operations for all entity DAOs.
+// It implements the generic BaseDAO interface.
+type baseCassandraDAO[T any] struct {
+	session   *gocql.Session
+	idCounter *IDCounter
+	table     *table[T]
+}
+
+// newBaseCassandraDAO creates a new base Cassandra DAO storing entities as
+// described by table.
+func newBaseCassandraDAO[T any](session *gocql.Session, idCounter *IDCounter, table *table[T]) *baseCassandraDAO[T] {
+	return &baseCassandraDAO[T]{
+		session:   session,
+		idCounter: idCounter,
+		table:     table,
+	}
+}
+
+// Create numbers the entity, claims its unique values, and inserts it with
+// its lookup entries.
+func (d *baseCassandraDAO[T]) Create(ctx context.Context, entity *T) error {
+	id, err := d.idCounter.NextID(ctx, d.table.name)
+	if err != nil {
+		return err
+	}
+	*d.table.id(entity) = id
+	d.table.created(entity, time.Now())
+
+	if err := d.claimAll(ctx, entity); err != nil {
+		return err
+	}
+	batch := d.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	d.addInsert(batch, entity)
+	if err := d.session.ExecuteBatch(batch); err != nil {
+		d.releaseAll(ctx, entity)
+		return err
+	}
+	return nil
+}
+
+// CreateBatch numbers entities from one block of IDs and claims all their
+// unique values before inserting them with logged batches. A taken value
+// fails the call before any entity is written.
+func (d *baseCassandraDAO[T]) CreateBatch(ctx context.Context, entities []*T) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	firstID, err := d.idCounter.NextIDs(ctx, d.table.name, len(entities))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, entity := range entities {
+		*d.table.id(entity) = firstID + uint(i)
+		d.table.created(entity, now)
+		if err := d.claimAll(ctx, entity); err != nil {
+			for _, claimed := range entities[:i] {
+				d.releaseAll(ctx, claimed)
+			}
+			return err
+		}
+	}
+
+	for start := 0; start < len(entities); start += maxBatchEntities {
+		end := min(start+maxBatchEntities, len(entities))
+		batch := d.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		for _, entity := range entities[start:end] {
+			d.addInsert(batch, entity)
+		}
+		if err := d.session.ExecuteBatch(batch); err != nil {
+			for _, entity := range entities[start:] {
+				d.releaseAll(ctx, entity)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// FindByID retrieves an entity by its ID, excluding soft-deleted entities.
+// Returns nil, nil if the entity is not found.
+func (d *baseCassandraDAO[T]) FindByID(ctx context.Context, id uint) (*T, error) {
+	entity, err := d.get(ctx, id)
+	if err != nil || entity == nil || !d.live(entity) {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Update overwrites an entity, moving its lookup entries if it changed the
+// values they index. Updating a missing entity does nothing.
+func (d *baseCassandraDAO[T]) Update(ctx context.Context, entity *T) error {
+	return d.update(ctx, entity, nil, d.insertRow)
+}
+
+// Delete soft-deletes an entity by its ID, or removes entities that have no
+// soft-delete marker for good.
+func (d *baseCassandraDAO[T]) Delete(ctx context.Context, id uint) error {
+	if d.table.deletedAt == nil {
+		entity, err := d.get(ctx, id)
+		if err != nil || entity == nil {
+			return err
+		}
+		return d.deleteForGood(ctx, []*T{entity})
+	}
+	return d.setIfExists(ctx, id, "deleted_at", time.Now())
+}
+
+// FindAll retrieves entities with pagination in partition token order.
+// Returns the entities, total count, and any error.
+func (d *baseCassandraDAO[T]) FindAll(ctx context.Context, page, size int) ([]*T, int64, error) {
+	return d.window(ctx, d.live, (page-1)*size, size)
+}
+
+// Count returns the number of entities, excluding soft-deleted ones.
+func (d *baseCassandraDAO[T]) Count(ctx context.Context) (int64, error) {
+	return d.count(ctx, d.live)
+}
+
+// ExistsBy checks if an entity exists by the value of a queryable field.
+func (d *baseCassandraDAO[T]) ExistsBy(ctx context.Context, field string, value any) (bool, error) {
+	entities, err := d.FindBy(ctx, dao.Query{Where: []dao.Condition{dao.Eq(field, value)}, Limit: 1})
+	return len(entities) > 0, err
+}
+
+// FindBy retrieves the entities matching query, excluding soft-deleted
+// entities. Entities are read through a lookup table when a condition pins
+// the ID, a unique value, or a parent ID, and from the whole table otherwise,
+// then filtered and ordered in memory.
+func (d *baseCassandraDAO[T]) FindBy(ctx context.Context, query dao.Query) ([]*T, error) {
+	if err := query.Normalize(d.table.queryFields); err != nil {
+		return nil, err
+	}
+
+	candidates, err := d.candidates(ctx, query.Where)
+	if err != nil {
+		return nil, err
+	}
+	var entities []*T
+	for _, entity := range candidates {
+		if d.live(entity) && matchesAll(d.table.queryValues(entity), query.Where) {
+			entities = append(entities, entity)
+		}
+	}
+	sortByQuery(entities, query.OrderBy, d.table.queryValues)
+	return paginate(entities, query.Offset, query.Limit), nil
+}
+
+// candidates returns the entities conditions may match.
+func (d *baseCassandraDAO[T]) candidates(ctx context.Context, conditions []dao.Condition) ([]*T, error) {
+	for _, condition := range conditions {
+		var values []any
+		switch condition.Op {
+		case dao.OpEq:
+			values = []any{condition.Value}
+		case dao.OpIn:
+			values = condition.Values()
+		default:
+			continue
+		}
+
+		if condition.Field == "id" {
+			return d.getMany(ctx, integerValues(values))
+		}
+		for _, lookup := range d.table.uniques {
+			if lookup.column != condition.Field {
+				continue
+			}
+			var ids []uint
+			for _, value := range stringValues(values) {
+				id, err := lookup.find(ctx, d.session, value)
+				if err != nil {
+					return nil, err
+				}
+				if id != 0 {
+					ids = append(ids, id)
+				}
+			}
+			return d.getMany(ctx, ids)
+		}
+		for _, index := range d.table.indexes {
+			if index.column != condition.Field {
+				continue
+			}
+			var ids []uint
+			for _, parentID := range integerValues(values) {
+				children, err := index.ids(ctx, d.session, parentID)
+				if err != nil {
+					return nil, err
+				}
+				ids = append(ids, children...)
+			}
+			return d.getMany(ctx, ids)
+		}
+	}
+	return d.scan(ctx, nil)
+}
+
+// live reports whether an entity is not soft-deleted.
+func (d *baseCassandraDAO[T]) live(entity *T) bool {
+	return d.table.deletedAt == nil || !d.table.deletedAt(entity).Valid
+}
+
+// columnList returns the quoted columns, joined for a statement.
+func (d *baseCassandraDAO[T]) columnList() string {
+	return strings.Join(quoteAll(d.table.columns), ", ")
+}
+
+// get retrieves an entity by its ID, including soft-deleted entities.
+// Returns nil, nil if the entity is not found.
+func (d *baseCassandraDAO[T]) get(ctx context.Context, id uint) (*T, error) {
+	entity := new(T)
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE id = ?`, d.columnList(), d.table.name)
+	err := d.session.Query(stmt, id).WithContext(ctx).Scan(d.table.fields(entity)...)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// getMany retrieves the entities with the given IDs in ascending ID order,
+// including soft-deleted entities.
+func (d *baseCassandraDAO[T]) getMany(ctx context.Context, ids []uint) ([]*T, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE id IN ?`, d.columnList(), d.table.name)
+	entities, err := d.collect(d.session.Query(stmt, ids).WithContext(ctx).Iter(), nil)
+	if err != nil {
+		return nil, err
+	}
+	d.sortByID(entities, false)
+	return entities, nil
+}
+
+// findByUnique retrieves the live entity owning a unique value.
+// Returns nil, nil if no entity owns it.
+func (d *baseCassandraDAO[T]) findByUnique(ctx context.Context, lookup uniqueLookup[T], value string) (*T, error) {
+	id, err := lookup.find(ctx, d.session, value)
+	if err != nil || id == 0 {
+		return nil, err
+	}
+	entity, err := d.FindByID(ctx, id)
+	// The entry may outlive a write that failed halfway
+	if err != nil || entity == nil || lookup.value(entity) != value {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// findByParent retrieves the children of a parent through an index lookup,
+// including soft-deleted children.
+func (d *baseCassandraDAO[T]) findByParent(ctx context.Context, index indexLookup[T], parentID uint) ([]*T, error) {
+	ids, err := index.ids(ctx, d.session, parentID)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := d.getMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	children := entities[:0]
+	for _, entity := range entities {
+		if index.value(entity) == parentID {
+			children = append(children, entity)
+		}
+	}
+	return children, nil
+}
+
+// scan reads the whole table and returns the entities keep accepts, or all
+// of them if keep is nil.
+func (d *baseCassandraDAO[T]) scan(ctx context.Context, keep func(*T) bool) ([]*T, error) {
+	stmt := fmt.Sprintf(`SELECT %s FROM %s`, d.columnList(), d.table.name)
+	return d.collect(d.session.Query(stmt).WithContext(ctx).Iter(), keep)
+}
+
+// count reads the whole table and counts the entities keep accepts.
+func (d *baseCassandraDAO[T]) count(ctx context.Context, keep func(*T) bool) (int64, error) {
+	_, total, err := d.window(ctx, keep, 0, -1)
+	return total, err
+}
+
+// window pages through the table in partition token order and returns the
+// entities keep accepts from offset on, at most limit of them if limit is
+// positive and none if it is negative, with the number keep accepts in all.
+// Only the returned entities are held in memory.
+func (d *baseCassandraDAO[T]) window(ctx context.Context, keep func(*T) bool, offset, limit int) ([]*T, int64, error) {
+	stmt := fmt.Sprintf(`SELECT %s FROM %s`, d.columnList(), d.table.name)
+	iter := d.session.Query(stmt).WithContext(ctx).PageSize(scanPageSize).Iter()
+
+	entities := []*T{}
+	var total int64
+	entity := new(T)
+	for iter.Scan(d.table.fields(entity)...) {
+		if keep != nil && !keep(entity) {
+			continue
+		}
+		if total >= int64(offset) && limit >= 0 && (limit == 0 || len(entities) < limit) {
+			entities = append(entities, entity)
+			entity = new(T)
+		}
+		total++
+	}
+	if err := iter.Close(); err != nil {
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+// after returns up to n entities keep accepts, following afterID in
+// partition token order, or from the start of the table if afterID is 0.
+// Cassandra serves this from the token range, without reading the entities
+// before it.
+func (d *baseCassandraDAO[T]) after(ctx context.Context, afterID uint, n int, keep func(*T) bool) ([]*T, error) {
+	stmt := fmt.Sprintf(`SELECT %s FROM %s`, d.columnList(), d.table.name)
+	var args []any
+	if afterID != 0 {
+		stmt += ` WHERE token(id) > token(?)`
+		args = append(args, afterID)
+	}
+	iter := d.session.Query(stmt, args...).WithContext(ctx).PageSize(min(max(n, 1), scanPageSize)).Iter()
+
+	entities := []*T{}
+	entity := new(T)
+	for len(entities) < n && iter.Scan(d.table.fields(entity)...) {
+		if keep == nil || keep(entity) {
+			entities = append(entities, entity)
+			entity = new(T)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// collect scans the rows of iter into entities.
+func (d *baseCassandraDAO[T]) collect(iter *gocql.Iter, keep func(*T) bool) ([]*T, error) {
+	var entities []*T
+	entity := new(T)
+	for iter.Scan(d.table.fields(entity)...) {
+		if keep == nil || keep(entity) {
+			entities = append(entities, entity)
+		}
+		entity = new(T)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// sortByID orders entities by ID.
+func (d *baseCassandraDAO[T]) sortByID(entities []*T, desc bool) {
+	sort.Slice(entities, func(i, j int) bool {
+		if desc {
+			return *d.table.id(entities[i]) > *d.table.id(entities[j])
+		}
+		return *d.table.id(entities[i]) < *d.table.id(entities[j])
+	})
+}
+
+// addInsert adds the statements writing an entity and its index entries to
+// a batch.
+func (d *baseCassandraDAO[T]) addInsert(batch *gocql.Batch, entity *T) {
+	batch.Query(d.insertStatement(), d.table.fields(entity)...)
+	for _, index := range d.table.indexes {
+		index.addInsert(batch, index.value(entity), *d.table.id(entity))
+	}
+}
+
+// insertRow writes the row of an entity, without its index entries.
+func (d *baseCassandraDAO[T]) insertRow(ctx context.Context, entity *T) error {
+	return d.session.Query(d.insertStatement(), d.table.fields(entity)...).WithContext(ctx).Exec()
+}
+
+func (d *baseCassandraDAO[T]) insertStatement() string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(d.table.columns)), ", ")
+	return fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`, d.table.name, d.columnList(), placeholders)
+}
+
+// update writes the row of an existing entity with write, then moves its
+// index entries. Unique values the entity changed are claimed first and
+// released again if the write fails; the values it no longer owns are
+// released after it. Returns missing if the entity does not exist.
+func (d *baseCassandraDAO[T]) update(ctx context.Context, entity *T, missing error, write func(context.Context, *T) error) error {
+	id := *d.table.id(entity)
+	current, err := d.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return missing
+	}
+
+	var moved []uniqueLookup[T]
+	for _, lookup := range d.table.uniques {
+		if lookup.value(entity) == lookup.value(current) {
+			continue
+		}
+		if err := lookup.claim(ctx, d.session, lookup.value(entity), id); err != nil {
+			for _, done := range moved {
+				done.release(ctx, d.session, done.value(entity), id)
+			}
+			return err
+		}
+		moved = append(moved, lookup)
+	}
+
+	if err := write(ctx, entity); err != nil {
+		for _, lookup := range moved {
+			lookup.release(ctx, d.session, lookup.value(entity), id)
+		}
+		return err
+	}
+
+	batch := d.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	for _, index := range d.table.indexes {
+		if parentID, old := index.value(entity), index.value(current); parentID != old {
+			index.addDelete(batch, old, id)
+			index.addInsert(batch, parentID, id)
+		}
+	}
+	if batch.Size() > 0 {
+		if err := d.session.ExecuteBatch(batch); err != nil {
+			return err
+		}
+	}
+	for _, lookup := range moved {
+		lookup.release(ctx, d.session, lookup.value(current), id)
+	}
+	return nil
+}
+
+// updateVersioned overwrites an existing entity if its lock version still
+// equals version. Returns dao.ErrConcurrentModification otherwise.
+func (d *baseCassandraDAO[T]) updateVersioned(ctx context.Context, entity *T, version uint) error {
+	assignments := make([]string, 0, len(d.table.columns)-1)
+	for _, column := range d.table.columns[1:] {
+		assignments = append(assignments, quote(column)+" = ?")
+	}
+	stmt := fmt.Sprintf(`UPDATE %s SET %s WHERE id = ? IF lock_version = ?`, d.table.name, strings.Join(assignments, ", "))
+
+	return d.update(ctx, entity, dao.ErrConcurrentModification, func(ctx context.Context, entity *T) error {
+		fields := d.table.fields(entity)
+		args := append(append([]any{}, fields[1:]...), fields[0], version)
+		applied, err := d.session.Query(stmt, args...).WithContext(ctx).MapScanCAS(map[string]any{})
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return dao.ErrConcurrentModification
+		}
+		return nil
+	})
+}
+
+// setIfExists sets a column of the entity with the given ID, unless it does
+// not exist.
+func (d *baseCassandraDAO[T]) setIfExists(ctx context.Context, id uint, column string, value any) error {
+	stmt := fmt.Sprintf(`UPDATE %s SET %s = ? WHERE id = ? IF EXISTS`, d.table.name, quote(column))
+	_, err := d.session.Query(stmt, value, id).WithContext(ctx).MapScanCAS(map[string]any{})
+	return err
+}
+
+// setConditionally sets columns of the entity with the given ID if the
+// conditions of the IF clause hold, and reports whether they did.
+func (d *baseCassandraDAO[T]) setConditionally(ctx context.Context, id uint, set string, condition string, args ...any) (bool, error) {
+	stmt := fmt.Sprintf(`UPDATE %s SET %s WHERE id = ? IF %s`, d.table.name, set, condition)
+	return d.session.Query(stmt, args...).WithContext(ctx).MapScanCAS(map[string]any{})
+}
+
+// deleteForGood removes entities with their lookup entries.
+func (d *baseCassandraDAO[T]) deleteForGood(ctx context.Context, entities []*T) error {
+	for start := 0; start < len(entities); start += maxBatchEntities {
+		end := min(start+maxBatchEntities, len(entities))
+		batch := d.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		for _, entity := range entities[start:end] {
+			id := *d.table.id(entity)
+			batch.Query(fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, d.table.name), id)
+			for _, index := range d.table.indexes {
+				index.addDelete(batch, index.value(entity), id)
+			}
+		}
+		if err := d.session.ExecuteBatch(batch); err != nil {
+			return err
+		}
+		for _, entity := range entities[start:end] {
+			d.releaseAll(ctx, entity)
+		}
+	}
+	return nil
+}
+
+// claimAll claims the unique values of a new entity, releasing the ones
+// already claimed if one is taken.
+func (d *baseCassandraDAO[T]) claimAll(ctx context.Context, entity *T) error {
+	id := *d.table.id(entity)
+	for i, lookup := range d.table.uniques {
+		if err := lookup.claim(ctx, d.session, lookup.value(entity), id); err != nil {
+			for _, claimed := range d.table.uniques[:i] {
+				claimed.release(ctx, d.session, claimed.value(entity), id)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseAll releases the unique values of an entity. Errors are ignored: a
+// value left claimed by a missing entity is ignored by lookups, and taken
+// over by the next entity claiming it.
+func (d *baseCassandraDAO[T]) releaseAll(ctx context.Context, entity *T) {
+	id := *d.table.id(entity)
+	for _, lookup := range d.table.uniques {
+		lookup.release(ctx, d.session, lookup.value(entity), id)
+	}
+}
+
+// paginate returns the items of the page starting at offset.
+func paginate[T any](items []*T, offset, limit int) []*T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []*T{}
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
+
+// quote quotes a column name, as some (e.g. token) are reserved words.
+func quote(column string) string {
+	return `"` + column + `"`
+}
+
+func quoteAll(columns []string) []string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quote(column)
+	}
+	return quoted
+}
+
+// deletedAt binds a soft-delete marker to a nullable timestamp column.
+type deletedAt struct {
+	*gorm.DeletedAt
+}
+
+// MarshalCQL writes null for an entity that is not deleted.
+func (d deletedAt) MarshalCQL(info gocql.TypeInfo) ([]byte, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	return gocql.Marshal(info, d.Time)
+}
+
+// UnmarshalCQL reads null as an entity that is not deleted.
+func (d deletedAt) UnmarshalCQL(info gocql.TypeInfo, data []byte) error {
+	if len(data) == 0 {
+		*d.DeletedAt = gorm.DeletedAt{}
+		return nil
+	}
+	var deleted time.Time
+	if err := gocql.Unmarshal(info, data, &deleted); err != nil {
+		return err
+	}
+	*d.DeletedAt = gorm.DeletedAt{Time: deleted, Valid: true}
+	return nil
+}
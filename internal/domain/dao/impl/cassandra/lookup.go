@@ -0,0 +1,97 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// uniqueLookup maps the values of a unique field to the IDs of the entities
+// owning them, in a table keyed by the value. Soft-deleted entities keep
+// their values, as with a unique index in SQL.
+type uniqueLookup[T any] struct {
+	table  string
+	column string
+	value  func(*T) string
+}
+
+// claim makes id the owner of value with a lightweight transaction.
+// Returns an error wrapping ErrDuplicateKey if another entity owns it.
+// Empty values are not unique and never claimed.
+func (l uniqueLookup[T]) claim(ctx context.Context, session *gocql.Session, value string, id uint) error {
+	if value == "" {
+		return nil
+	}
+	stmt := fmt.Sprintf(`INSERT INTO %s (%s, id) VALUES (?, ?) IF NOT EXISTS`, l.table, quote(l.column))
+	existing := map[string]any{}
+	applied, err := session.Query(stmt, value, id).WithContext(ctx).MapScanCAS(existing)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		if owner, _ := existing["id"].(int64); uint(owner) == id {
+			return nil
+		}
+		return fmt.Errorf("%w: %s %q is taken", ErrDuplicateKey, l.column, value)
+	}
+	return nil
+}
+
+// release frees value if id still owns it.
+func (l uniqueLookup[T]) release(ctx context.Context, session *gocql.Session, value string, id uint) {
+	if value == "" {
+		return
+	}
+	stmt := fmt.Sprintf(`DELETE FROM %s WHERE %s = ? IF id = ?`, l.table, quote(l.column))
+	_, _ = session.Query(stmt, value, id).WithContext(ctx).MapScanCAS(map[string]any{})
+}
+
+// find returns the ID owning value, or 0 if no entity owns it.
+func (l uniqueLookup[T]) find(ctx context.Context, session *gocql.Session, value string) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+	var id uint
+	stmt := fmt.Sprintf(`SELECT id FROM %s WHERE %s = ?`, l.table, quote(l.column))
+	err := session.Query(stmt, value).WithContext(ctx).Scan(&id)
+	if errors.Is(err, gocql.ErrNotFound) {
+		return 0, nil
+	}
+	return id, err
+}
+
+// indexLookup maps a parent ID to the IDs of its children, in a table
+// partitioned by the parent ID.
+type indexLookup[T any] struct {
+	table  string
+	column string
+	value  func(*T) uint
+}
+
+// addInsert adds the statement indexing a child under its parent to a batch.
+func (l indexLookup[T]) addInsert(batch *gocql.Batch, parentID, id uint) {
+	batch.Query(fmt.Sprintf(`INSERT INTO %s (%s, id) VALUES (?, ?)`, l.table, quote(l.column)), parentID, id)
+}
+
+// addDelete adds the statement removing a child from its parent to a batch.
+func (l indexLookup[T]) addDelete(batch *gocql.Batch, parentID, id uint) {
+	batch.Query(fmt.Sprintf(`DELETE FROM %s WHERE %s = ? AND id = ?`, l.table, quote(l.column)), parentID, id)
+}
+
+// ids returns the IDs of the children of a parent in ascending order.
+func (l indexLookup[T]) ids(ctx context.Context, session *gocql.Session, parentID uint) ([]uint, error) {
+	stmt := fmt.Sprintf(`SELECT id FROM %s WHERE %s = ?`, l.table, quote(l.column))
+	iter := session.Query(stmt, parentID).WithContext(ctx).Iter()
+
+	var ids []uint
+	var id uint
+	for iter.Scan(&id) {
+		ids = append(ids, id)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
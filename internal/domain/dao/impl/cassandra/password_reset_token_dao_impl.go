@@ -0,0 +1,115 @@
+package cassandra
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+var (
+	resetTokenHashLookup = uniqueLookup[entity.PasswordResetToken]{
+		table:  "password_reset_tokens_by_hash",
+		column: "token_hash",
+		value:  func(t *entity.PasswordResetToken) string { return t.TokenHash },
+	}
+	resetTokensByUser = indexLookup[entity.PasswordResetToken]{
+		table:  "password_reset_tokens_by_user",
+		column: "user_id",
+		value:  func(t *entity.PasswordResetToken) uint { return t.UserID },
+	}
+)
+
+// passwordResetTokensTable stores reset tokens, found by hash through a
+// lookup table and by user through an index table.
+var passwordResetTokensTable = &table[entity.PasswordResetToken]{
+	name:    entity.PasswordResetToken{}.TableName(),
+	columns: []string{"id", "user_id", "token_hash", "expires_at", "used_at", "created_at", "deleted_at"},
+	fields: func(t *entity.PasswordResetToken) []any {
+		return []any{&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &t.UsedAt, &t.CreatedAt, deletedAt{&t.DeletedAt}}
+	},
+	id:        func(t *entity.PasswordResetToken) *uint { return &t.ID },
+	created:   func(t *entity.PasswordResetToken, now time.Time) { t.CreatedAt = now },
+	deletedAt: func(t *entity.PasswordResetToken) *gorm.DeletedAt { return &t.DeletedAt },
+	queryValues: func(t *entity.PasswordResetToken) map[string]any {
+		return map[string]any{
+			"id":         t.ID,
+			"user_id":    t.UserID,
+			"token_hash": t.TokenHash,
+		}
+	},
+	queryFields: dao.PasswordResetTokenQueryFields,
+	uniques:     []uniqueLookup[entity.PasswordResetToken]{resetTokenHashLookup},
+	indexes:     []indexLookup[entity.PasswordResetToken]{resetTokensByUser},
+}
+
+// passwordResetTokenDAO implements dao.PasswordResetTokenDAO using Cassandra.
+type passwordResetTokenDAO struct {
+	*baseCassandraDAO[entity.PasswordResetToken]
+}
+
+// NewPasswordResetTokenDAO creates a new Cassandra-based PasswordResetTokenDAO.
+func NewPasswordResetTokenDAO(session *gocql.Session, idCounter *IDCounter) dao.PasswordResetTokenDAO {
+	return &passwordResetTokenDAO{
+		baseCassandraDAO: newBaseCassandraDAO(session, idCounter, passwordResetTokensTable),
+	}
+}
+
+// FindByTokenHash retrieves a reset token by the hash of its value.
+func (d *passwordResetTokenDAO) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	return d.findByUnique(ctx, resetTokenHashLookup, tokenHash)
+}
+
+// MarkUsed marks a reset token as used if it has not been used yet.
+func (d *passwordResetTokenDAO) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	token, err := d.get(ctx, id)
+	if err != nil || token == nil {
+		return false, err
+	}
+	// Conditioning on the user keeps a token deleted meanwhile from being
+	// written back as a partial row
+	return d.setConditionally(ctx, id, "used_at = ?", "used_at = null AND user_id = ?", time.Now(), id, token.UserID)
+}
+
+// InvalidateAllByUserID marks all unused reset tokens for a user as used.
+func (d *passwordResetTokenDAO) InvalidateAllByUserID(ctx context.Context, userID uint) error {
+	tokens, err := d.findByParent(ctx, resetTokensByUser, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, token := range tokens {
+		if token.UsedAt != nil {
+			continue
+		}
+		if _, err := d.setConditionally(ctx, token.ID, "used_at = ?", "used_at = null AND user_id = ?", now, token.ID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteExpired permanently removes tokens that expired before the given time.
+func (d *passwordResetTokenDAO) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	tokens, err := d.scan(ctx, resetTokenExpiredBefore(before))
+	if err != nil {
+		return 0, err
+	}
+	if err := d.deleteForGood(ctx, tokens); err != nil {
+		return 0, err
+	}
+	return int64(len(tokens)), nil
+}
+
+// CountExpired counts the tokens that expired before the given time.
+func (d *passwordResetTokenDAO) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	return d.count(ctx, resetTokenExpiredBefore(before))
+}
+
+func resetTokenExpiredBefore(before time.Time) func(*entity.PasswordResetToken) bool {
+	return func(t *entity.PasswordResetToken) bool { return t.ExpiresAt.Before(before) }
+}
@@ -0,0 +1,197 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+var pluginKeyLookup = uniqueLookup[entity.Plugin]{
+	table:  "plugins_by_key",
+	column: "key",
+	value:  func(p *entity.Plugin) string { return p.Key },
+}
+
+// pluginsTable stores plugins, found by key through a lookup table and by
+// state through a secondary index.
+var pluginsTable = &table[entity.Plugin]{
+	name: entity.Plugin{}.TableName(),
+	columns: []string{
+		"id", "key", "name", "description", "version", "author", "type", "state",
+		"config", "config_schema", "checksum", "path", "version_history", "installed_at",
+		"enabled_at", "lock_version", "created_at", "updated_at", "deleted_at",
+	},
+	fields: func(p *entity.Plugin) []any {
+		return []any{
+			&p.ID, &p.Key, &p.Name, &p.Description, &p.Version, &p.Author, &p.Type, &p.State,
+			&p.Config, &p.ConfigSchema, &p.Checksum, &p.Path, &p.VersionHistory, &p.InstalledAt,
+			&p.EnabledAt, &p.LockVersion, &p.CreatedAt, &p.UpdatedAt, deletedAt{&p.DeletedAt},
+		}
+	},
+	id: func(p *entity.Plugin) *uint { return &p.ID },
+	created: func(p *entity.Plugin, now time.Time) {
+		p.CreatedAt = now
+		p.UpdatedAt = now
+	},
+	deletedAt: func(p *entity.Plugin) *gorm.DeletedAt { return &p.DeletedAt },
+	queryValues: func(p *entity.Plugin) map[string]any {
+		return map[string]any{
+			"id":    p.ID,
+			"key":   p.Key,
+			"state": p.State,
+		}
+	},
+	queryFields: dao.PluginQueryFields,
+	uniques:     []uniqueLookup[entity.Plugin]{pluginKeyLookup},
+}
+
+// pluginDAO implements dao.PluginDAO using Cassandra.
+type pluginDAO struct {
+	*baseCassandraDAO[entity.Plugin]
+	extensions  *baseCassandraDAO[entity.PluginExtension]
+	transitions *baseCassandraDAO[entity.PluginStateTransition]
+}
+
+// NewPluginDAO creates a new Cassandra-based PluginDAO.
+func NewPluginDAO(session *gocql.Session, idCounter *IDCounter) dao.PluginDAO {
+	return &pluginDAO{
+		baseCassandraDAO: newBaseCassandraDAO(session, idCounter, pluginsTable),
+		extensions:       newBaseCassandraDAO(session, idCounter, pluginExtensionsTable),
+		transitions:      newBaseCassandraDAO(session, idCounter, pluginStateTransitionsTable),
+	}
+}
+
+// Update saves the plugin if it has not changed since it was read, bumping
+// its lock version. Returns dao.ErrConcurrentModification otherwise.
+func (d *pluginDAO) Update(ctx context.Context, plugin *entity.Plugin) error {
+	current := plugin.LockVersion
+	plugin.LockVersion = current + 1
+	plugin.UpdatedAt = time.Now()
+
+	if err := d.updateVersioned(ctx, plugin, current); err != nil {
+		plugin.LockVersion = current
+		return err
+	}
+	return nil
+}
+
+// FindAllCursor retrieves up to limit plugins after afterID in partition
+// token order.
+func (d *pluginDAO) FindAllCursor(ctx context.Context, afterID uint, limit int) (*dao.CursorResult[entity.Plugin], error) {
+	plugins, err := d.after(ctx, afterID, limit+1, d.live)
+	if err != nil {
+		return nil, err
+	}
+	return dao.NewCursorResult(plugins, limit, func(p *entity.Plugin) uint { return p.ID }), nil
+}
+
+// FindByKey retrieves a plugin by its unique key.
+func (d *pluginDAO) FindByKey(ctx context.Context, key string) (*entity.Plugin, error) {
+	return d.findByUnique(ctx, pluginKeyLookup, key)
+}
+
+// DeleteByKey soft-deletes a plugin by its key.
+func (d *pluginDAO) DeleteByKey(ctx context.Context, key string) error {
+	id, err := pluginKeyLookup.find(ctx, d.session, key)
+	if err != nil || id == 0 {
+		return err
+	}
+	return d.setIfExists(ctx, id, "deleted_at", time.Now())
+}
+
+// FindByState retrieves all plugins with a specific state, sorted by name.
+func (d *pluginDAO) FindByState(ctx context.Context, state entity.PluginState) ([]*entity.Plugin, error) {
+	stmt := fmt.Sprintf(`SELECT %s FROM %s WHERE state = ?`, d.columnList(), d.table.name)
+	plugins, err := d.collect(d.session.Query(stmt, state).WithContext(ctx).Iter(), d.live)
+	if err != nil {
+		return nil, err
+	}
+	if plugins == nil {
+		plugins = []*entity.Plugin{}
+	}
+	sort.SliceStable(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, nil
+}
+
+// FindEnabled retrieves all enabled plugins.
+func (d *pluginDAO) FindEnabled(ctx context.Context) ([]*entity.Plugin, error) {
+	return d.FindByState(ctx, entity.PluginStateEnabled)
+}
+
+// ExistsByKey checks if a plugin with the given key exists.
+func (d *pluginDAO) ExistsByKey(ctx context.Context, key string) (bool, error) {
+	plugin, err := d.FindByKey(ctx, key)
+	return plugin != nil, err
+}
+
+// UpdateState updates the state of a plugin and bumps its lock version,
+// retrying while concurrent writers bump it first.
+func (d *pluginDAO) UpdateState(ctx context.Context, id uint, state entity.PluginState) error {
+	for {
+		plugin, err := d.get(ctx, id)
+		if err != nil || plugin == nil {
+			return err
+		}
+
+		now := time.Now()
+		set := "state = ?, updated_at = ?, lock_version = ?"
+		args := []any{state, now, plugin.LockVersion + 1}
+		// Set enabled_at when enabling the plugin
+		if state == entity.PluginStateEnabled {
+			set += ", enabled_at = ?"
+			args = append(args, now)
+		}
+		args = append(args, id, plugin.LockVersion)
+
+		applied, err := d.setConditionally(ctx, id, set, "lock_version = ?", args...)
+		if err != nil || applied {
+			return err
+		}
+	}
+}
+
+// PurgeDeleted permanently removes plugins soft-deleted before the given
+// time, with their extensions and state history.
+func (d *pluginDAO) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	plugins, err := d.scan(ctx, pluginDeletedBefore(before))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, plugin := range plugins {
+		extensions, err := d.extensions.findByParent(ctx, extensionsByPlugin, plugin.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := d.extensions.deleteForGood(ctx, extensions); err != nil {
+			return 0, err
+		}
+		transitions, err := d.transitions.findByParent(ctx, transitionsByPlugin, plugin.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := d.transitions.deleteForGood(ctx, transitions); err != nil {
+			return 0, err
+		}
+	}
+	if err := d.deleteForGood(ctx, plugins); err != nil {
+		return 0, err
+	}
+	return int64(len(plugins)), nil
+}
+
+// CountDeleted counts the plugins soft-deleted before the given time.
+func (d *pluginDAO) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return d.count(ctx, pluginDeletedBefore(before))
+}
+
+func pluginDeletedBefore(before time.Time) func(*entity.Plugin) bool {
+	return func(p *entity.Plugin) bool { return p.DeletedAt.Valid && p.DeletedAt.Time.Before(before) }
+}
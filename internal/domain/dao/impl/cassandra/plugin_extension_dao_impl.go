@@ -0,0 +1,87 @@
+package cassandra
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+var extensionsByPlugin = indexLookup[entity.PluginExtension]{
+	table:  "plugin_extensions_by_plugin",
+	column: "plugin_id",
+	value:  func(e *entity.PluginExtension) uint { return e.PluginID },
+}
+
+// pluginExtensionsTable stores plugin extensions, found by plugin through an
+// index table.
+var pluginExtensionsTable = &table[entity.PluginExtension]{
+	name:    entity.PluginExtension{}.TableName(),
+	columns: []string{"id", "plugin_id", "name", "type", "path", "handler", "config", "created_at", "deleted_at"},
+	fields: func(e *entity.PluginExtension) []any {
+		return []any{&e.ID, &e.PluginID, &e.Name, &e.Type, &e.Path, &e.Handler, &e.Config, &e.CreatedAt, deletedAt{&e.DeletedAt}}
+	},
+	id:        func(e *entity.PluginExtension) *uint { return &e.ID },
+	created:   func(e *entity.PluginExtension, now time.Time) { e.CreatedAt = now },
+	deletedAt: func(e *entity.PluginExtension) *gorm.DeletedAt { return &e.DeletedAt },
+	queryValues: func(e *entity.PluginExtension) map[string]any {
+		return map[string]any{
+			"id":        e.ID,
+			"plugin_id": e.PluginID,
+		}
+	},
+	queryFields: dao.PluginExtensionQueryFields,
+	indexes:     []indexLookup[entity.PluginExtension]{extensionsByPlugin},
+}
+
+// pluginExtensionDAO implements dao.PluginExtensionDAO using Cassandra.
+type pluginExtensionDAO struct {
+	*baseCassandraDAO[entity.PluginExtension]
+}
+
+// NewPluginExtensionDAO creates a new Cassandra-based PluginExtensionDAO.
+func NewPluginExtensionDAO(session *gocql.Session, idCounter *IDCounter) dao.PluginExtensionDAO {
+	return &pluginExtensionDAO{
+		baseCassandraDAO: newBaseCassandraDAO(session, idCounter, pluginExtensionsTable),
+	}
+}
+
+// FindByPluginID retrieves all extensions for a plugin, sorted by name.
+func (d *pluginExtensionDAO) FindByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginExtension, error) {
+	extensions, err := d.findByParent(ctx, extensionsByPlugin, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	live := []*entity.PluginExtension{}
+	for _, ext := range extensions {
+		if d.live(ext) {
+			live = append(live, ext)
+		}
+	}
+	sort.SliceStable(live, func(i, j int) bool { return live[i].Name < live[j].Name })
+	return live, nil
+}
+
+// DeleteByPluginID soft-deletes all extensions for a plugin.
+func (d *pluginExtensionDAO) DeleteByPluginID(ctx context.Context, pluginID uint) error {
+	extensions, err := d.findByParent(ctx, extensionsByPlugin, pluginID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, ext := range extensions {
+		if !d.live(ext) {
+			continue
+		}
+		if err := d.setIfExists(ctx, ext.ID, "deleted_at", now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package cassandra
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+var transitionsByPlugin = indexLookup[entity.PluginStateTransition]{
+	table:  "plugin_state_transitions_by_plugin",
+	column: "plugin_id",
+	value:  func(t *entity.PluginStateTransition) uint { return t.PluginID },
+}
+
+// pluginStateTransitionsTable stores plugin state history, found by plugin
+// through an index table. Records are deleted for good.
+var pluginStateTransitionsTable = &table[entity.PluginStateTransition]{
+	name:    entity.PluginStateTransition{}.TableName(),
+	columns: []string{"id", "plugin_id", "from_state", "to_state", "reason", "created_at"},
+	fields: func(t *entity.PluginStateTransition) []any {
+		return []any{&t.ID, &t.PluginID, &t.FromState, &t.ToState, &t.Reason, &t.CreatedAt}
+	},
+	id:      func(t *entity.PluginStateTransition) *uint { return &t.ID },
+	created: func(t *entity.PluginStateTransition, now time.Time) { t.CreatedAt = now },
+	queryValues: func(t *entity.PluginStateTransition) map[string]any {
+		return map[string]any{
+			"id":         t.ID,
+			"plugin_id":  t.PluginID,
+			"created_at": t.CreatedAt,
+		}
+	},
+	queryFields: dao.PluginStateTransitionQueryFields,
+	indexes:     []indexLookup[entity.PluginStateTransition]{transitionsByPlugin},
+}
+
+// pluginStateTransitionDAO implements dao.PluginStateTransitionDAO using Cassandra.
+type pluginStateTransitionDAO struct {
+	*baseCassandraDAO[entity.PluginStateTransition]
+}
+
+// NewPluginStateTransitionDAO creates a new Cassandra-based PluginStateTransitionDAO.
+func NewPluginStateTransitionDAO(session *gocql.Session, idCounter *IDCounter) dao.PluginStateTransitionDAO {
+	return &pluginStateTransitionDAO{
+		baseCassandraDAO: newBaseCassandraDAO(session, idCounter, pluginStateTransitionsTable),
+	}
+}
+
+// FindByPluginID retrieves the state transitions of a plugin, oldest first.
+func (d *pluginStateTransitionDAO) FindByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginStateTransition, error) {
+	transitions, err := d.findByParent(ctx, transitionsByPlugin, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	sortByQuery(transitions, []dao.SortField{{Field: "created_at"}}, pluginStateTransitionsTable.queryValues)
+	return transitions, nil
+}
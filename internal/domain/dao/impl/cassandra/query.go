@@ -0,0 +1,175 @@
+package cassandra
+
+import (
+	"cmp"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+)
+
+// matchesAll reports whether the queryable values of an entity satisfy all
+// of the validated conditions.
+func matchesAll(values map[string]any, conditions []dao.Condition) bool {
+	for _, condition := range conditions {
+		if !matches(values, condition) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the queryable values of an entity satisfy a
+// validated condition. Values that cannot be compared never match.
+func matches(values map[string]any, condition dao.Condition) bool {
+	switch {
+	case condition.And != nil:
+		return matchesAll(values, condition.And)
+	case condition.Or != nil:
+		for _, c := range condition.Or {
+			if matches(values, c) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value := values[condition.Field]
+	if condition.Op == dao.OpIn {
+		for _, candidate := range condition.Values() {
+			if order, ok := compare(value, candidate); ok && order == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	order, ok := compare(value, condition.Value)
+	if !ok {
+		return false
+	}
+	switch condition.Op {
+	case dao.OpEq:
+		return order == 0
+	case dao.OpNe:
+		return order != 0
+	case dao.OpGt:
+		return order > 0
+	case dao.OpGte:
+		return order >= 0
+	case dao.OpLt:
+		return order < 0
+	case dao.OpLte:
+		return order <= 0
+	}
+	return false
+}
+
+// compare orders two field values: strings (including named string types
+// such as entity.UserRole), integers of any size and sign, booleans, and
+// times. ok is false for values of different kinds.
+func compare(a, b any) (order int, ok bool) {
+	if at, isTime := a.(time.Time); isTime {
+		bt, isTime := b.(time.Time)
+		if !isTime {
+			return 0, false
+		}
+		return at.Compare(bt), true
+	}
+
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !av.IsValid() || !bv.IsValid() {
+		return 0, false
+	}
+	switch {
+	case av.Kind() == reflect.String && bv.Kind() == reflect.String:
+		return strings.Compare(av.String(), bv.String()), true
+	case isInteger(av) && isInteger(bv):
+		return compareIntegers(av, bv), true
+	case av.Kind() == reflect.Bool && bv.Kind() == reflect.Bool:
+		if av.Bool() == bv.Bool() {
+			return 0, true
+		}
+		if av.Bool() {
+			return 1, true
+		}
+		return -1, true
+	}
+	return 0, false
+}
+
+func isInteger(v reflect.Value) bool {
+	return v.CanInt() || v.CanUint()
+}
+
+// compareIntegers orders integers without converting them to a common type
+// that could overflow.
+func compareIntegers(a, b reflect.Value) int {
+	aNegative, bNegative := a.CanInt() && a.Int() < 0, b.CanInt() && b.Int() < 0
+	switch {
+	case aNegative && bNegative:
+		return cmp.Compare(a.Int(), b.Int())
+	case aNegative:
+		return -1
+	case bNegative:
+		return 1
+	}
+	return cmp.Compare(magnitude(a), magnitude(b))
+}
+
+// magnitude returns a non-negative integer as a uint64.
+func magnitude(v reflect.Value) uint64 {
+	if v.CanInt() {
+		return uint64(v.Int())
+	}
+	return v.Uint()
+}
+
+// sortByQuery orders entities by the fields of a query, then by ID.
+func sortByQuery[T any](entities []*T, orderBy []dao.SortField, queryValues func(*T) map[string]any) {
+	fields := append(append([]dao.SortField{}, orderBy...), dao.SortField{Field: "id"})
+	values := make(map[*T]map[string]any, len(entities))
+	for _, entity := range entities {
+		values[entity] = queryValues(entity)
+	}
+
+	sort.SliceStable(entities, func(i, j int) bool {
+		for _, field := range fields {
+			order, _ := compare(values[entities[i]][field.Field], values[entities[j]][field.Field])
+			if order == 0 {
+				continue
+			}
+			if field.Desc {
+				return order > 0
+			}
+			return order < 0
+		}
+		return false
+	})
+}
+
+// integerValues returns the non-negative integers among values as IDs.
+func integerValues(values []any) []uint {
+	ids := make([]uint, 0, len(values))
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		if !v.IsValid() || !isInteger(v) || (v.CanInt() && v.Int() < 0) {
+			continue
+		}
+		ids = append(ids, uint(magnitude(v)))
+	}
+	return ids
+}
+
+// stringValues returns the strings among values.
+func stringValues(values []any) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if v := reflect.ValueOf(value); v.IsValid() && v.Kind() == reflect.String {
+			strs = append(strs, v.String())
+		}
+	}
+	return strs
+}
@@ -0,0 +1,98 @@
+package cassandra
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+func TestCompare(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		a, b  any
+		order int
+		ok    bool
+	}{
+		{"equal strings", "alice", "alice", 0, true},
+		{"named string type", entity.RoleAdmin, "ADMIN", 0, true},
+		{"string order", "alice", "bob", -1, true},
+		{"uint and int", uint(5), 3, 1, true},
+		{"negative int", -1, uint(0), -1, true},
+		{"large uint", uint64(1 << 63), int64(1), 1, true},
+		{"bools", true, false, 1, true},
+		{"times", now, now.Add(time.Second), -1, true},
+		{"string and int", "1", 1, 0, false},
+		{"time and string", now, "now", 0, false},
+		{"nil", nil, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, ok := compare(tt.a, tt.b)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.order, order)
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	values := map[string]any{
+		"id":   uint(7),
+		"role": entity.RoleAdmin,
+	}
+
+	tests := []struct {
+		name      string
+		condition dao.Condition
+		expected  bool
+	}{
+		{"eq", dao.Eq("role", "ADMIN"), true},
+		{"ne", dao.Ne("role", "ADMIN"), false},
+		{"gt", dao.Gt("id", 5), true},
+		{"lte", dao.Lte("id", 6), false},
+		{"in", dao.In("id", 1, 7), true},
+		{"in slice", dao.Condition{Field: "id", Op: dao.OpIn, Value: []uint{1, 2}}, false},
+		{"or", dao.Or(dao.Eq("role", "USER"), dao.Lt("id", 10)), true},
+		{"and", dao.And(dao.Eq("role", "ADMIN"), dao.Gte("id", 10)), false},
+		{"mismatched kind", dao.Eq("id", "7"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matches(values, tt.condition))
+		})
+	}
+}
+
+func TestSortByQuery(t *testing.T) {
+	users := []*entity.User{
+		{ID: 1, Username: "carol", Role: entity.RoleUser},
+		{ID: 2, Username: "alice", Role: entity.RoleAdmin},
+		{ID: 3, Username: "bob", Role: entity.RoleUser},
+		{ID: 4, Username: "dave", Role: entity.RoleAdmin},
+	}
+
+	sortByQuery(users, []dao.SortField{{Field: "role", Desc: true}}, usersTable.queryValues)
+
+	var ids []uint
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	// USER before ADMIN, ties broken by ID
+	assert.Equal(t, []uint{1, 3, 2, 4}, ids)
+}
+
+func TestPaginate(t *testing.T) {
+	items := []*int{new(int), new(int), new(int)}
+
+	assert.Len(t, paginate(items, 0, 2), 2)
+	assert.Len(t, paginate(items, 2, 2), 1)
+	assert.Empty(t, paginate(items, 3, 2))
+	assert.Len(t, paginate(items, -1, 0), 3)
+}
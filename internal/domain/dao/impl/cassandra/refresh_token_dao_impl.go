@@ -0,0 +1,173 @@
+package cassandra
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+var (
+	refreshTokenLookup = uniqueLookup[entity.RefreshToken]{
+		table:  "refresh_tokens_by_token",
+		column: "token",
+		value:  func(t *entity.RefreshToken) string { return t.Token },
+	}
+	refreshTokensByUser = indexLookup[entity.RefreshToken]{
+		table:  "refresh_tokens_by_user",
+		column: "user_id",
+		value:  func(t *entity.RefreshToken) uint { return t.UserID },
+	}
+)
+
+// refreshTokensTable stores refresh tokens, found by value through a lookup
+// table and by user through an index table.
+var refreshTokensTable = &table[entity.RefreshToken]{
+	name: entity.RefreshToken{}.TableName(),
+	columns: []string{
+		"id", "user_id", "token", "expires_at", "revoked", "device", "user_agent",
+		"ip_address", "created_at", "deleted_at",
+	},
+	fields: func(t *entity.RefreshToken) []any {
+		return []any{
+			&t.ID, &t.UserID, &t.Token, &t.ExpiresAt, &t.Revoked, &t.Device, &t.UserAgent,
+			&t.IPAddress, &t.CreatedAt, deletedAt{&t.DeletedAt},
+		}
+	},
+	id:        func(t *entity.RefreshToken) *uint { return &t.ID },
+	created:   func(t *entity.RefreshToken, now time.Time) { t.CreatedAt = now },
+	deletedAt: func(t *entity.RefreshToken) *gorm.DeletedAt { return &t.DeletedAt },
+	queryValues: func(t *entity.RefreshToken) map[string]any {
+		return map[string]any{
+			"id":      t.ID,
+			"user_id": t.UserID,
+			"token":   t.Token,
+		}
+	},
+	queryFields: dao.RefreshTokenQueryFields,
+	uniques:     []uniqueLookup[entity.RefreshToken]{refreshTokenLookup},
+	indexes:     []indexLookup[entity.RefreshToken]{refreshTokensByUser},
+}
+
+// refreshTokenDAO implements dao.RefreshTokenDAO using Cassandra.
+type refreshTokenDAO struct {
+	*baseCassandraDAO[entity.RefreshToken]
+	userDAO dao.UserDAO
+}
+
+// NewRefreshTokenDAO creates a new Cassandra-based RefreshTokenDAO.
+func NewRefreshTokenDAO(session *gocql.Session, idCounter *IDCounter, userDAO dao.UserDAO) dao.RefreshTokenDAO {
+	return &refreshTokenDAO{
+		baseCassandraDAO: newBaseCassandraDAO(session, idCounter, refreshTokensTable),
+		userDAO:          userDAO,
+	}
+}
+
+// FindByID retrieves a refresh token by its ID, with its user.
+func (d *refreshTokenDAO) FindByID(ctx context.Context, id uint) (*entity.RefreshToken, error) {
+	token, err := d.baseCassandraDAO.FindByID(ctx, id)
+	if err != nil || token == nil {
+		return nil, err
+	}
+	d.loadUser(ctx, token)
+	return token, nil
+}
+
+// FindByToken retrieves a refresh token by its value, with its user.
+// Only returns non-revoked tokens.
+func (d *refreshTokenDAO) FindByToken(ctx context.Context, value string) (*entity.RefreshToken, error) {
+	token, err := d.findByUnique(ctx, refreshTokenLookup, value)
+	if err != nil || token == nil || token.Revoked {
+		return nil, err
+	}
+	d.loadUser(ctx, token)
+	return token, nil
+}
+
+// loadUser loads the user a refresh token belongs to.
+func (d *refreshTokenDAO) loadUser(ctx context.Context, token *entity.RefreshToken) {
+	if d.userDAO == nil {
+		return
+	}
+	user, err := d.userDAO.FindByID(ctx, token.UserID)
+	if err == nil && user != nil {
+		token.User = *user
+	}
+}
+
+// RevokeByToken revokes a specific refresh token.
+func (d *refreshTokenDAO) RevokeByToken(ctx context.Context, value string) error {
+	id, err := refreshTokenLookup.find(ctx, d.session, value)
+	if err != nil || id == 0 {
+		return err
+	}
+	return d.setIfExists(ctx, id, "revoked", true)
+}
+
+// FindActiveByUserID retrieves the user's non-revoked, unexpired refresh tokens, newest first.
+func (d *refreshTokenDAO) FindActiveByUserID(ctx context.Context, userID uint) ([]*entity.RefreshToken, error) {
+	tokens, err := d.findByParent(ctx, refreshTokensByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	active := []*entity.RefreshToken{}
+	for _, token := range tokens {
+		if d.live(token) && !token.Revoked && token.ExpiresAt.After(now) {
+			active = append(active, token)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].CreatedAt.After(active[j].CreatedAt)
+	})
+	return active, nil
+}
+
+// RevokeByID revokes a refresh token if it belongs to the user.
+func (d *refreshTokenDAO) RevokeByID(ctx context.Context, userID, id uint) (bool, error) {
+	return d.setConditionally(ctx, id, "revoked = true", "user_id = ? AND revoked = false AND deleted_at = null", id, userID)
+}
+
+// RevokeAllByUserID revokes all refresh tokens for a specific user.
+func (d *refreshTokenDAO) RevokeAllByUserID(ctx context.Context, userID uint) error {
+	tokens, err := d.findByParent(ctx, refreshTokensByUser, userID)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if token.Revoked {
+			continue
+		}
+		if err := d.setIfExists(ctx, token.ID, "revoked", true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteExpired permanently removes tokens that expired before the given time.
+func (d *refreshTokenDAO) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	tokens, err := d.scan(ctx, refreshTokenExpiredBefore(before))
+	if err != nil {
+		return 0, err
+	}
+	if err := d.deleteForGood(ctx, tokens); err != nil {
+		return 0, err
+	}
+	return int64(len(tokens)), nil
+}
+
+// CountExpired counts the tokens that expired before the given time.
+func (d *refreshTokenDAO) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	return d.count(ctx, refreshTokenExpiredBefore(before))
+}
+
+func refreshTokenExpiredBefore(before time.Time) func(*entity.RefreshToken) bool {
+	return func(t *entity.RefreshToken) bool { return t.ExpiresAt.Before(before) }
+}
@@ -0,0 +1,161 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// schema lists the statements creating the tables and lookup tables the
+// DAOs use. Each statement is idempotent.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS id_counters (
+		name text PRIMARY KEY,
+		last_id bigint
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS users (
+		id bigint PRIMARY KEY,
+		username text,
+		email text,
+		password text,
+		first_name text,
+		last_name text,
+		role text,
+		is_active boolean,
+		is_verified boolean,
+		totp_secret text,
+		totp_enabled boolean,
+		totp_recovery_codes text,
+		lock_version bigint,
+		created_at timestamp,
+		updated_at timestamp,
+		deleted_at timestamp
+	)`,
+	`CREATE TABLE IF NOT EXISTS users_by_username (username text PRIMARY KEY, id bigint)`,
+	`CREATE TABLE IF NOT EXISTS users_by_email (email text PRIMARY KEY, id bigint)`,
+
+	`CREATE TABLE IF NOT EXISTS refresh_tokens (
+		id bigint PRIMARY KEY,
+		user_id bigint,
+		"token" text,
+		expires_at timestamp,
+		revoked boolean,
+		device text,
+		user_agent text,
+		ip_address text,
+		created_at timestamp,
+		deleted_at timestamp
+	)`,
+	`CREATE TABLE IF NOT EXISTS refresh_tokens_by_token ("token" text PRIMARY KEY, id bigint)`,
+	`CREATE TABLE IF NOT EXISTS refresh_tokens_by_user (user_id bigint, id bigint, PRIMARY KEY (user_id, id))`,
+
+	`CREATE TABLE IF NOT EXISTS password_reset_tokens (
+		id bigint PRIMARY KEY,
+		user_id bigint,
+		token_hash text,
+		expires_at timestamp,
+		used_at timestamp,
+		created_at timestamp,
+		deleted_at timestamp
+	)`,
+	`CREATE TABLE IF NOT EXISTS password_reset_tokens_by_hash (token_hash text PRIMARY KEY, id bigint)`,
+	`CREATE TABLE IF NOT EXISTS password_reset_tokens_by_user (user_id bigint, id bigint, PRIMARY KEY (user_id, id))`,
+
+	`CREATE TABLE IF NOT EXISTS api_keys (
+		id bigint PRIMARY KEY,
+		name text,
+		prefix text,
+		key_hash text,
+		scopes text,
+		created_by bigint,
+		expires_at timestamp,
+		revoked_at timestamp,
+		created_at timestamp,
+		deleted_at timestamp
+	)`,
+	`CREATE TABLE IF NOT EXISTS api_keys_by_hash (key_hash text PRIMARY KEY, id bigint)`,
+
+	`CREATE TABLE IF NOT EXISTS plugins (
+		id bigint PRIMARY KEY,
+		"key" text,
+		name text,
+		description text,
+		version text,
+		author text,
+		type text,
+		state text,
+		config text,
+		config_schema text,
+		checksum text,
+		path text,
+		version_history text,
+		installed_at timestamp,
+		enabled_at timestamp,
+		lock_version bigint,
+		created_at timestamp,
+		updated_at timestamp,
+		deleted_at timestamp
+	)`,
+	`CREATE TABLE IF NOT EXISTS plugins_by_key ("key" text PRIMARY KEY, id bigint)`,
+	// Few distinct states, so a secondary index serves FindByState
+	`CREATE INDEX IF NOT EXISTS plugins_state_idx ON plugins (state)`,
+
+	`CREATE TABLE IF NOT EXISTS plugin_extensions (
+		id bigint PRIMARY KEY,
+		plugin_id bigint,
+		name text,
+		type text,
+		path text,
+		handler text,
+		config text,
+		created_at timestamp,
+		deleted_at timestamp
+	)`,
+	`CREATE TABLE IF NOT EXISTS plugin_extensions_by_plugin (plugin_id bigint, id bigint, PRIMARY KEY (plugin_id, id))`,
+
+	`CREATE TABLE IF NOT EXISTS plugin_state_transitions (
+		id bigint PRIMARY KEY,
+		plugin_id bigint,
+		from_state text,
+		to_state text,
+		reason text,
+		created_at timestamp
+	)`,
+	`CREATE TABLE IF NOT EXISTS plugin_state_transitions_by_plugin (plugin_id bigint, id bigint, PRIMARY KEY (plugin_id, id))`,
+
+	`CREATE TABLE IF NOT EXISTS audit_logs (
+		id bigint PRIMARY KEY,
+		actor_id bigint,
+		actor text,
+		action text,
+		target text,
+		ip_address text,
+		request_id text,
+		outcome text,
+		detail text,
+		created_at timestamp
+	)`,
+}
+
+// CreateKeyspace creates a keyspace with simple replication, unless it
+// exists. The session must not be bound to a keyspace.
+func CreateKeyspace(ctx context.Context, session *gocql.Session, keyspace string, replicationFactor int) error {
+	stmt := fmt.Sprintf(
+		`CREATE KEYSPACE IF NOT EXISTS %s WITH replication = {'class': 'SimpleStrategy', 'replication_factor': %d}`,
+		quote(keyspace), replicationFactor,
+	)
+	return session.Query(stmt).WithContext(ctx).Exec()
+}
+
+// CreateSchema creates the tables the DAOs use in the keyspace of the
+// session, unless they exist.
+func CreateSchema(ctx context.Context, session *gocql.Session) error {
+	for _, stmt := range schema {
+		if err := session.Query(stmt).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to create Cassandra schema: %w", err)
+		}
+	}
+	return nil
+}
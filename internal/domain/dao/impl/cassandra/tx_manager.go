@@ -0,0 +1,25 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+)
+
+// txManager implements dao.TxManager for Cassandra, which has no
+// multi-statement transactions.
+type txManager struct{}
+
+// NewTxManager creates a new Cassandra-based TxManager. Units of work run
+// without a transaction; each DAO call is atomic on its own, and uniqueness
+// is still enforced by the lookup tables. A unit of work that fails halfway
+// keeps the writes made before the failure: registration that fails to
+// issue tokens leaves the new account behind, and the user can sign in to it.
+func NewTxManager() dao.TxManager {
+	return txManager{}
+}
+
+// WithTransaction runs fn directly.
+func (txManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,159 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+var (
+	usernameLookup = uniqueLookup[entity.User]{
+		table:  "users_by_username",
+		column: "username",
+		value:  func(u *entity.User) string { return u.Username },
+	}
+	emailLookup = uniqueLookup[entity.User]{
+		table:  "users_by_email",
+		column: "email",
+		value:  func(u *entity.User) string { return u.Email },
+	}
+)
+
+// usersTable stores users, found by username and email through lookup tables.
+var usersTable = &table[entity.User]{
+	name: entity.User{}.TableName(),
+	columns: []string{
+		"id", "username", "email", "password", "first_name", "last_name", "role",
+		"is_active", "is_verified", "totp_secret", "totp_enabled", "totp_recovery_codes",
+		"lock_version", "created_at", "updated_at", "deleted_at",
+	},
+	fields: func(u *entity.User) []any {
+		return []any{
+			&u.ID, &u.Username, &u.Email, &u.Password, &u.FirstName, &u.LastName, &u.Role,
+			&u.IsActive, &u.IsVerified, &u.TOTPSecret, &u.TOTPEnabled, &u.TOTPRecoveryCodes,
+			&u.LockVersion, &u.CreatedAt, &u.UpdatedAt, deletedAt{&u.DeletedAt},
+		}
+	},
+	id: func(u *entity.User) *uint { return &u.ID },
+	created: func(u *entity.User, now time.Time) {
+		u.CreatedAt = now
+		u.UpdatedAt = now
+	},
+	deletedAt: func(u *entity.User) *gorm.DeletedAt { return &u.DeletedAt },
+	queryValues: func(u *entity.User) map[string]any {
+		return map[string]any{
+			"id":         u.ID,
+			"username":   u.Username,
+			"email":      u.Email,
+			"role":       u.Role,
+			"created_at": u.CreatedAt,
+		}
+	},
+	queryFields: dao.UserQueryFields,
+	uniques:     []uniqueLookup[entity.User]{usernameLookup, emailLookup},
+}
+
+// userDAO implements dao.UserDAO using Cassandra.
+type userDAO struct {
+	*baseCassandraDAO[entity.User]
+}
+
+// NewUserDAO creates a new Cassandra-based UserDAO.
+func NewUserDAO(session *gocql.Session, idCounter *IDCounter) dao.UserDAO {
+	return &userDAO{
+		baseCassandraDAO: newBaseCassandraDAO(session, idCounter, usersTable),
+	}
+}
+
+// Update saves the user if it has not changed since it was read, bumping its
+// lock version. Returns dao.ErrConcurrentModification otherwise.
+func (d *userDAO) Update(ctx context.Context, user *entity.User) error {
+	current := user.LockVersion
+	user.LockVersion = current + 1
+	user.UpdatedAt = time.Now()
+
+	if err := d.updateVersioned(ctx, user, current); err != nil {
+		user.LockVersion = current
+		return err
+	}
+	return nil
+}
+
+// FindByIDIncludingDeleted retrieves a user by ID, including soft-deleted users.
+func (d *userDAO) FindByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error) {
+	return d.get(ctx, id)
+}
+
+// Restore clears the deleted_at marker on a soft-deleted user.
+func (d *userDAO) Restore(ctx context.Context, id uint) error {
+	_, err := d.setConditionally(ctx, id, "deleted_at = null, updated_at = ?", "EXISTS", time.Now(), id)
+	return err
+}
+
+// FindAll retrieves users with pagination in partition token order.
+func (d *userDAO) FindAll(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
+	return d.FindAllWithOptions(ctx, page, size, false)
+}
+
+// FindAllWithOptions retrieves users with pagination in partition token
+// order, including soft-deleted users if includeDeleted is set.
+func (d *userDAO) FindAllWithOptions(ctx context.Context, page, size int, includeDeleted bool) ([]*entity.User, int64, error) {
+	keep := d.live
+	if includeDeleted {
+		keep = nil
+	}
+	return d.window(ctx, keep, (page-1)*size, size)
+}
+
+// FindAllCursor retrieves up to limit users after afterID in partition token
+// order. The order is stable across pages but does not follow IDs.
+func (d *userDAO) FindAllCursor(ctx context.Context, afterID uint, limit int) (*dao.CursorResult[entity.User], error) {
+	users, err := d.after(ctx, afterID, limit+1, d.live)
+	if err != nil {
+		return nil, err
+	}
+	return dao.NewCursorResult(users, limit, func(u *entity.User) uint { return u.ID }), nil
+}
+
+// Search is not supported: the users table is keyed by ID alone, so
+// filtering and sorting by other fields would read the whole table.
+func (d *userDAO) Search(_ context.Context, _ *entity.UserQuery) ([]*entity.User, int64, error) {
+	return nil, 0, fmt.Errorf("%w: user search on cassandra", dao.ErrUnsupported)
+}
+
+// FindByUsername retrieves a user by their username.
+func (d *userDAO) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
+	return d.findByUnique(ctx, usernameLookup, username)
+}
+
+// FindByEmail retrieves a user by their email.
+func (d *userDAO) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return d.findByUnique(ctx, emailLookup, email)
+}
+
+// FindByUsernameOrEmail retrieves a user by username or email.
+func (d *userDAO) FindByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (*entity.User, error) {
+	user, err := d.FindByUsername(ctx, usernameOrEmail)
+	if err != nil || user != nil {
+		return user, err
+	}
+	return d.FindByEmail(ctx, usernameOrEmail)
+}
+
+// ExistsByUsername checks if a user with the given username exists.
+func (d *userDAO) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	user, err := d.FindByUsername(ctx, username)
+	return user != nil, err
+}
+
+// ExistsByEmail checks if a user with the given email exists.
+func (d *userDAO) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	user, err := d.FindByEmail(ctx, email)
+	return user != nil, err
+}
@@ -0,0 +1,59 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// apiKeyDAO implements dao.APIKeyDAO using GORM for SQL databases.
+type apiKeyDAO struct {
+	*baseGormDAO[entity.APIKey]
+}
+
+// NewAPIKeyDAO creates a new GORM-based APIKeyDAO.
+func NewAPIKeyDAO(db *gorm.DB) dao.APIKeyDAO {
+	return &apiKeyDAO{
+		baseGormDAO: newBaseGormDAO[entity.APIKey](db, dao.APIKeyQueryFields),
+	}
+}
+
+// FindByKeyHash retrieves an API key by the hash of its value.
+func (d *apiKeyDAO) FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	return d.findByField(ctx, "key_hash", keyHash)
+}
+
+// Revoke marks an API key as revoked if it has not been revoked yet.
+func (d *apiKeyDAO) Revoke(ctx context.Context, id uint) (bool, error) {
+	result := d.conn(ctx).
+		Model(&entity.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FindAll retrieves API keys with pagination, ordered by created_at descending.
+func (d *apiKeyDAO) FindAll(ctx context.Context, page, size int) ([]*entity.APIKey, int64, error) {
+	var keys []*entity.APIKey
+	var total int64
+	offset := (page - 1) * size
+
+	if err := d.conn(ctx).Model(&entity.APIKey{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := d.conn(ctx).
+		Offset(offset).
+		Limit(size).
+		Order("created_at DESC").
+		Find(&keys).Error
+
+	return keys, total, err
+}
@@ -0,0 +1,87 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// auditLogDAO implements dao.AuditLogDAO using GORM for SQL databases.
+type auditLogDAO struct {
+	*baseGormDAO[entity.AuditLog]
+}
+
+// NewAuditLogDAO creates a new GORM-based AuditLogDAO.
+func NewAuditLogDAO(db *gorm.DB) dao.AuditLogDAO {
+	return &auditLogDAO{
+		baseGormDAO: newBaseGormDAO[entity.AuditLog](db, dao.AuditLogQueryFields),
+	}
+}
+
+// Query retrieves a page of audit logs matching the query, newest first.
+func (d *auditLogDAO) Query(ctx context.Context, query *entity.AuditQuery) ([]*entity.AuditLog, int64, error) {
+	filter := auditQueryScope(query)
+
+	var total int64
+	if err := d.conn(ctx).Model(&entity.AuditLog{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []*entity.AuditLog
+	err := d.conn(ctx).
+		Scopes(filter).
+		Order("created_at DESC, id DESC").
+		Offset(query.Offset()).
+		Limit(query.Size).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+// DeleteBefore removes the audit logs recorded before the given time.
+func (d *auditLogDAO) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := d.conn(ctx).
+		Where("created_at < ?", before).
+		Delete(&entity.AuditLog{})
+	return result.RowsAffected, result.Error
+}
+
+// CountBefore counts the audit logs recorded before the given time.
+func (d *auditLogDAO) CountBefore(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := d.conn(ctx).
+		Model(&entity.AuditLog{}).
+		Where("created_at < ?", before).
+		Count(&count).Error
+	return count, err
+}
+
+// auditQueryScope applies the filters of an audit query
+func auditQueryScope(query *entity.AuditQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.ActorID != nil {
+			db = db.Where("actor_id = ?", *query.ActorID)
+		}
+		if query.Actor != "" {
+			db = db.Where("actor = ?", query.Actor)
+		}
+		if query.Action != "" {
+			db = db.Where("action = ?", query.Action)
+		}
+		if query.From != nil {
+			db = db.Where("created_at >= ?", *query.From)
+		}
+		if query.To != nil {
+			db = db.Where("created_at < ?", *query.To)
+		}
+		return db
+	}
+}
+
+// FindAll retrieves audit logs with pagination, ordered by created_at descending.
+func (d *auditLogDAO) FindAll(ctx context.Context, page, size int) ([]*entity.AuditLog, int64, error) {
+	return d.Query(ctx, &entity.AuditQuery{Page: page, Size: size})
+}
@@ -6,29 +6,49 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 )
 
 // baseGormDAO provides common GORM operations for all entity DAOs.
 // It implements the generic BaseDAO interface for SQL databases.
 type baseGormDAO[T any] struct {
-	db *gorm.DB
+	db          *gorm.DB
+	queryFields dao.QueryFields
 }
 
-// newBaseGormDAO creates a new base GORM DAO instance.
-func newBaseGormDAO[T any](db *gorm.DB) *baseGormDAO[T] {
-	return &baseGormDAO[T]{db: db}
+// newBaseGormDAO creates a new base GORM DAO instance whose FindBy may query
+// queryFields.
+func newBaseGormDAO[T any](db *gorm.DB, queryFields dao.QueryFields) *baseGormDAO[T] {
+	return &baseGormDAO[T]{db: db, queryFields: queryFields}
 }
 
 // Create inserts a new entity into the database.
 func (d *baseGormDAO[T]) Create(ctx context.Context, entity *T) error {
-	return d.db.WithContext(ctx).Create(entity).Error
+	return d.conn(ctx).Create(entity).Error
+}
+
+// CreateBatch inserts entities with multi-row INSERTs of the database's
+// CreateBatchSize rows, or dao.DefaultBatchSize when it is not set. The
+// batches run in one transaction, so either all entities are created or none.
+func (d *baseGormDAO[T]) CreateBatch(ctx context.Context, entities []*T) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	db := d.conn(ctx)
+	batchSize := db.CreateBatchSize
+	if batchSize <= 0 {
+		batchSize = dao.DefaultBatchSize
+	}
+	return db.CreateInBatches(entities, batchSize).Error
 }
 
 // FindByID retrieves an entity by its primary key.
 // Returns nil, nil if the entity is not found.
 func (d *baseGormDAO[T]) FindByID(ctx context.Context, id uint) (*T, error) {
 	var entity T
-	err := d.db.WithContext(ctx).First(&entity, id).Error
+	err := d.conn(ctx).First(&entity, id).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -40,13 +60,13 @@ func (d *baseGormDAO[T]) FindByID(ctx context.Context, id uint) (*T, error) {
 
 // Update modifies an existing entity in the database.
 func (d *baseGormDAO[T]) Update(ctx context.Context, entity *T) error {
-	return d.db.WithContext(ctx).Save(entity).Error
+	return d.conn(ctx).Save(entity).Error
 }
 
 // Delete performs a soft delete on an entity by its ID.
 func (d *baseGormDAO[T]) Delete(ctx context.Context, id uint) error {
 	var entity T
-	return d.db.WithContext(ctx).Delete(&entity, id).Error
+	return d.conn(ctx).Delete(&entity, id).Error
 }
 
 // FindAll retrieves entities with pagination.
@@ -57,11 +77,11 @@ func (d *baseGormDAO[T]) FindAll(ctx context.Context, page, size int) ([]*T, int
 	offset := (page - 1) * size
 
 	var model T
-	if err := d.db.WithContext(ctx).Model(&model).Count(&total).Error; err != nil {
+	if err := d.conn(ctx).Model(&model).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := d.db.WithContext(ctx).
+	err := d.conn(ctx).
 		Offset(offset).
 		Limit(size).
 		Find(&entities).Error
@@ -73,7 +93,7 @@ func (d *baseGormDAO[T]) FindAll(ctx context.Context, page, size int) ([]*T, int
 func (d *baseGormDAO[T]) Count(ctx context.Context) (int64, error) {
 	var count int64
 	var model T
-	err := d.db.WithContext(ctx).Model(&model).Count(&count).Error
+	err := d.conn(ctx).Model(&model).Count(&count).Error
 	return count, err
 }
 
@@ -81,13 +101,110 @@ func (d *baseGormDAO[T]) Count(ctx context.Context) (int64, error) {
 func (d *baseGormDAO[T]) ExistsBy(ctx context.Context, field string, value any) (bool, error) {
 	var count int64
 	var model T
-	err := d.db.WithContext(ctx).
+	err := d.conn(ctx).
 		Model(&model).
 		Where(field+" = ?", value).
 		Count(&count).Error
 	return count > 0, err
 }
 
+// FindBy retrieves the entities matching query, translated to WHERE clauses
+// on the whitelisted columns.
+func (d *baseGormDAO[T]) FindBy(ctx context.Context, query dao.Query) ([]*T, error) {
+	if err := query.Normalize(d.queryFields); err != nil {
+		return nil, err
+	}
+
+	db := d.conn(ctx)
+	for _, condition := range query.Where {
+		db = db.Where(gormCondition(condition))
+	}
+	for _, sort := range query.OrderBy {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Field}, Desc: sort.Desc})
+	}
+
+	var entities []*T
+	err := db.Order("id ASC").
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Find(&entities).Error
+	return entities, err
+}
+
+// gormCondition translates a validated condition to a clause expression.
+// Columns are quoted by GORM and values bound as parameters.
+func gormCondition(condition dao.Condition) clause.Expression {
+	switch {
+	case condition.And != nil:
+		return clause.And(gormConditions(condition.And)...)
+	case condition.Or != nil:
+		return clause.Or(gormConditions(condition.Or)...)
+	}
+
+	column := clause.Column{Name: condition.Field}
+	switch condition.Op {
+	case dao.OpNe:
+		return clause.Neq{Column: column, Value: condition.Value}
+	case dao.OpGt:
+		return clause.Gt{Column: column, Value: condition.Value}
+	case dao.OpGte:
+		return clause.Gte{Column: column, Value: condition.Value}
+	case dao.OpLt:
+		return clause.Lt{Column: column, Value: condition.Value}
+	case dao.OpLte:
+		return clause.Lte{Column: column, Value: condition.Value}
+	case dao.OpIn:
+		return clause.IN{Column: column, Values: condition.Values()}
+	default:
+		return clause.Eq{Column: column, Value: condition.Value}
+	}
+}
+
+func gormConditions(conditions []dao.Condition) []clause.Expression {
+	exprs := make([]clause.Expression, len(conditions))
+	for i, condition := range conditions {
+		exprs[i] = gormCondition(condition)
+	}
+	return exprs
+}
+
+// updateVersioned saves all fields of entity, whose lock version is held in
+// *version, only if the stored row still has that version. On success the
+// version is incremented; otherwise it is left unchanged and
+// dao.ErrConcurrentModification is returned.
+func updateVersioned(db *gorm.DB, entity any, version *uint) error {
+	current := *version
+	*version = current + 1
+	result := db.Model(entity).Where("lock_version = ?", current).Select("*").Updates(entity)
+	if result.Error != nil {
+		*version = current
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		*version = current
+		return dao.ErrConcurrentModification
+	}
+	return nil
+}
+
+// findAfterID retrieves up to limit+1 entities with IDs greater than afterID,
+// ordered by ID. The extra row tells the caller whether another page follows.
+func (d *baseGormDAO[T]) findAfterID(ctx context.Context, afterID uint, limit int) ([]*T, error) {
+	var entities []*T
+	err := d.conn(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit + 1).
+		Find(&entities).Error
+	return entities, err
+}
+
+// conn returns the database handle for ctx: the transaction started by
+// TxManager.WithTransaction if there is one, otherwise the shared connection.
+func (d *baseGormDAO[T]) conn(ctx context.Context) *gorm.DB {
+	return dbFromContext(ctx, d.db)
+}
+
 // getDB returns the underlying GORM database instance.
 // This is used by entity-specific DAOs to access the database for custom queries.
 func (d *baseGormDAO[T]) getDB() *gorm.DB {
@@ -98,7 +215,7 @@ func (d *baseGormDAO[T]) getDB() *gorm.DB {
 // This is a helper method for entity-specific DAOs.
 func (d *baseGormDAO[T]) findByField(ctx context.Context, field string, value any) (*T, error) {
 	var entity T
-	err := d.db.WithContext(ctx).Where(field+" = ?", value).First(&entity).Error
+	err := d.conn(ctx).Where(field+" = ?", value).First(&entity).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -112,7 +229,7 @@ func (d *baseGormDAO[T]) findByField(ctx context.Context, field string, value an
 // This is a helper method for entity-specific DAOs.
 func (d *baseGormDAO[T]) findAllByField(ctx context.Context, field string, value any) ([]*T, error) {
 	var entities []*T
-	err := d.db.WithContext(ctx).Where(field+" = ?", value).Find(&entities).Error
+	err := d.conn(ctx).Where(field+" = ?", value).Find(&entities).Error
 	if err != nil {
 		return nil, err
 	}
@@ -123,5 +240,5 @@ func (d *baseGormDAO[T]) findAllByField(ctx context.Context, field string, value
 // This is a helper method for entity-specific DAOs.
 func (d *baseGormDAO[T]) deleteByField(ctx context.Context, field string, value any) error {
 	var model T
-	return d.db.WithContext(ctx).Where(field+" = ?", value).Delete(&model).Error
+	return d.conn(ctx).Where(field+" = ?", value).Delete(&model).Error
 }
@@ -0,0 +1,66 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// passwordResetTokenDAO implements dao.PasswordResetTokenDAO using GORM for SQL databases.
+type passwordResetTokenDAO struct {
+	*baseGormDAO[entity.PasswordResetToken]
+}
+
+// NewPasswordResetTokenDAO creates a new GORM-based PasswordResetTokenDAO.
+func NewPasswordResetTokenDAO(db *gorm.DB) dao.PasswordResetTokenDAO {
+	return &passwordResetTokenDAO{
+		baseGormDAO: newBaseGormDAO[entity.PasswordResetToken](db, dao.PasswordResetTokenQueryFields),
+	}
+}
+
+// FindByTokenHash retrieves a reset token by the hash of its value.
+func (d *passwordResetTokenDAO) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	return d.findByField(ctx, "token_hash", tokenHash)
+}
+
+// MarkUsed marks a reset token as used if it has not been used yet.
+func (d *passwordResetTokenDAO) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	result := d.conn(ctx).
+		Model(&entity.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateAllByUserID marks all unused reset tokens for a user as used.
+func (d *passwordResetTokenDAO) InvalidateAllByUserID(ctx context.Context, userID uint) error {
+	return d.conn(ctx).
+		Model(&entity.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
+
+// DeleteExpired permanently removes tokens that expired before the given time.
+func (d *passwordResetTokenDAO) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := d.conn(ctx).Unscoped().
+		Where("expires_at < ?", before).
+		Delete(&entity.PasswordResetToken{})
+	return result.RowsAffected, result.Error
+}
+
+// CountExpired counts the tokens that expired before the given time.
+func (d *passwordResetTokenDAO) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := d.conn(ctx).Unscoped().
+		Model(&entity.PasswordResetToken{}).
+		Where("expires_at < ?", before).
+		Count(&count).Error
+	return count, err
+}
@@ -19,14 +19,14 @@ type pluginDAO struct {
 // NewPluginDAO creates a new GORM-based PluginDAO.
 func NewPluginDAO(db *gorm.DB) dao.PluginDAO {
 	return &pluginDAO{
-		baseGormDAO: newBaseGormDAO[entity.Plugin](db),
+		baseGormDAO: newBaseGormDAO[entity.Plugin](db, dao.PluginQueryFields),
 	}
 }
 
 // FindByKey retrieves a plugin by its unique key identifier.
 func (d *pluginDAO) FindByKey(ctx context.Context, key string) (*entity.Plugin, error) {
 	var plugin entity.Plugin
-	err := d.getDB().WithContext(ctx).Where(map[string]any{"key": key}).First(&plugin).Error
+	err := d.conn(ctx).Where(map[string]any{"key": key}).First(&plugin).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -36,9 +36,15 @@ func (d *pluginDAO) FindByKey(ctx context.Context, key string) (*entity.Plugin,
 	return &plugin, nil
 }
 
+// Update saves the plugin if it has not changed since it was read, bumping its
+// lock version. Returns dao.ErrConcurrentModification otherwise.
+func (d *pluginDAO) Update(ctx context.Context, plugin *entity.Plugin) error {
+	return updateVersioned(d.conn(ctx), plugin, &plugin.LockVersion)
+}
+
 // DeleteByKey soft-deletes a plugin by its key.
 func (d *pluginDAO) DeleteByKey(ctx context.Context, key string) error {
-	return d.getDB().WithContext(ctx).
+	return d.conn(ctx).
 		Where(map[string]any{"key": key}).
 		Delete(&entity.Plugin{}).Error
 }
@@ -46,7 +52,7 @@ func (d *pluginDAO) DeleteByKey(ctx context.Context, key string) error {
 // FindByState retrieves all plugins with a specific state.
 func (d *pluginDAO) FindByState(ctx context.Context, state entity.PluginState) ([]*entity.Plugin, error) {
 	var plugins []*entity.Plugin
-	err := d.getDB().WithContext(ctx).
+	err := d.conn(ctx).
 		Where("state = ?", state).
 		Order("name ASC").
 		Find(&plugins).Error
@@ -64,7 +70,7 @@ func (d *pluginDAO) FindEnabled(ctx context.Context) ([]*entity.Plugin, error) {
 // ExistsByKey checks if a plugin with the given key exists.
 func (d *pluginDAO) ExistsByKey(ctx context.Context, key string) (bool, error) {
 	var count int64
-	err := d.getDB().WithContext(ctx).Model(&entity.Plugin{}).Where(map[string]any{"key": key}).Count(&count).Error
+	err := d.conn(ctx).Model(&entity.Plugin{}).Where(map[string]any{"key": key}).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
@@ -75,8 +81,9 @@ func (d *pluginDAO) ExistsByKey(ctx context.Context, key string) (bool, error) {
 // When enabling a plugin, it also sets the enabled_at timestamp.
 func (d *pluginDAO) UpdateState(ctx context.Context, id uint, state entity.PluginState) error {
 	updates := map[string]any{
-		"state":      state,
-		"updated_at": time.Now(),
+		"state":        state,
+		"updated_at":   time.Now(),
+		"lock_version": gorm.Expr("lock_version + 1"),
 	}
 
 	// Set enabled_at when enabling the plugin
@@ -85,7 +92,7 @@ func (d *pluginDAO) UpdateState(ctx context.Context, id uint, state entity.Plugi
 		updates["enabled_at"] = &now
 	}
 
-	return d.getDB().WithContext(ctx).
+	return d.conn(ctx).
 		Model(&entity.Plugin{}).
 		Where("id = ?", id).
 		Updates(updates).Error
@@ -97,11 +104,11 @@ func (d *pluginDAO) FindAll(ctx context.Context, page, size int) ([]*entity.Plug
 	var total int64
 	offset := (page - 1) * size
 
-	if err := d.getDB().WithContext(ctx).Model(&entity.Plugin{}).Count(&total).Error; err != nil {
+	if err := d.conn(ctx).Model(&entity.Plugin{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := d.getDB().WithContext(ctx).
+	err := d.conn(ctx).
 		Offset(offset).
 		Limit(size).
 		Order("installed_at DESC").
@@ -109,3 +116,41 @@ func (d *pluginDAO) FindAll(ctx context.Context, page, size int) ([]*entity.Plug
 
 	return plugins, total, err
 }
+
+// FindAllCursor retrieves up to limit plugins after afterID, ordered by ID ascending.
+func (d *pluginDAO) FindAllCursor(ctx context.Context, afterID uint, limit int) (*dao.CursorResult[entity.Plugin], error) {
+	plugins, err := d.findAfterID(ctx, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return dao.NewCursorResult(plugins, limit, func(e *entity.Plugin) uint { return e.ID }), nil
+}
+
+// PurgeDeleted permanently removes the plugins soft-deleted before the given
+// time, with their extensions and state history, in one transaction.
+func (d *pluginDAO) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := d.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := tx.Unscoped().Model(&entity.Plugin{}).Select("id").Where("deleted_at < ?", before)
+		if err := tx.Unscoped().Where("plugin_id IN (?)", ids).Delete(&entity.PluginExtension{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("plugin_id IN (?)", ids).Delete(&entity.PluginStateTransition{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("deleted_at < ?", before).Delete(&entity.Plugin{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+// CountDeleted counts the plugins soft-deleted before the given time.
+func (d *pluginDAO) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := d.conn(ctx).Unscoped().
+		Model(&entity.Plugin{}).
+		Where("deleted_at < ?", before).
+		Count(&count).Error
+	return count, err
+}
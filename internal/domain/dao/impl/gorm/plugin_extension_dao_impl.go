@@ -17,14 +17,14 @@ type pluginExtensionDAO struct {
 // NewPluginExtensionDAO creates a new GORM-based PluginExtensionDAO.
 func NewPluginExtensionDAO(db *gorm.DB) dao.PluginExtensionDAO {
 	return &pluginExtensionDAO{
-		baseGormDAO: newBaseGormDAO[entity.PluginExtension](db),
+		baseGormDAO: newBaseGormDAO[entity.PluginExtension](db, dao.PluginExtensionQueryFields),
 	}
 }
 
 // FindByPluginID retrieves all extensions belonging to a specific plugin.
 func (d *pluginExtensionDAO) FindByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginExtension, error) {
 	var extensions []*entity.PluginExtension
-	err := d.getDB().WithContext(ctx).
+	err := d.conn(ctx).
 		Where("plugin_id = ?", pluginID).
 		Find(&extensions).Error
 	if err != nil {
@@ -35,7 +35,7 @@ func (d *pluginExtensionDAO) FindByPluginID(ctx context.Context, pluginID uint)
 
 // DeleteByPluginID deletes all extensions belonging to a specific plugin.
 func (d *pluginExtensionDAO) DeleteByPluginID(ctx context.Context, pluginID uint) error {
-	return d.getDB().WithContext(ctx).
+	return d.conn(ctx).
 		Where("plugin_id = ?", pluginID).
 		Delete(&entity.PluginExtension{}).Error
 }
@@ -46,11 +46,11 @@ func (d *pluginExtensionDAO) FindAll(ctx context.Context, page, size int) ([]*en
 	var total int64
 	offset := (page - 1) * size
 
-	if err := d.getDB().WithContext(ctx).Model(&entity.PluginExtension{}).Count(&total).Error; err != nil {
+	if err := d.conn(ctx).Model(&entity.PluginExtension{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := d.getDB().WithContext(ctx).
+	err := d.conn(ctx).
 		Preload("Plugin").
 		Offset(offset).
 		Limit(size).
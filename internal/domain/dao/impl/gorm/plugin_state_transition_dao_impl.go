@@ -0,0 +1,54 @@
+package gorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// pluginStateTransitionDAO implements dao.PluginStateTransitionDAO using GORM for SQL databases.
+type pluginStateTransitionDAO struct {
+	*baseGormDAO[entity.PluginStateTransition]
+}
+
+// NewPluginStateTransitionDAO creates a new GORM-based PluginStateTransitionDAO.
+func NewPluginStateTransitionDAO(db *gorm.DB) dao.PluginStateTransitionDAO {
+	return &pluginStateTransitionDAO{
+		baseGormDAO: newBaseGormDAO[entity.PluginStateTransition](db, dao.PluginStateTransitionQueryFields),
+	}
+}
+
+// FindByPluginID retrieves the state transitions of a plugin, oldest first.
+func (d *pluginStateTransitionDAO) FindByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginStateTransition, error) {
+	var transitions []*entity.PluginStateTransition
+	err := d.conn(ctx).
+		Where("plugin_id = ?", pluginID).
+		Order("created_at ASC, id ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// FindAll retrieves state transitions with pagination, ordered by created_at descending.
+func (d *pluginStateTransitionDAO) FindAll(ctx context.Context, page, size int) ([]*entity.PluginStateTransition, int64, error) {
+	var transitions []*entity.PluginStateTransition
+	var total int64
+	offset := (page - 1) * size
+
+	if err := d.conn(ctx).Model(&entity.PluginStateTransition{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := d.conn(ctx).
+		Offset(offset).
+		Limit(size).
+		Order("created_at DESC").
+		Find(&transitions).Error
+
+	return transitions, total, err
+}
@@ -19,7 +19,7 @@ type refreshTokenDAO struct {
 // NewRefreshTokenDAO creates a new GORM-based RefreshTokenDAO.
 func NewRefreshTokenDAO(db *gorm.DB) dao.RefreshTokenDAO {
 	return &refreshTokenDAO{
-		baseGormDAO: newBaseGormDAO[entity.RefreshToken](db),
+		baseGormDAO: newBaseGormDAO[entity.RefreshToken](db, dao.RefreshTokenQueryFields),
 	}
 }
 
@@ -27,7 +27,7 @@ func NewRefreshTokenDAO(db *gorm.DB) dao.RefreshTokenDAO {
 // Only returns non-revoked tokens with preloaded User data.
 func (d *refreshTokenDAO) FindByToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
 	var refreshToken entity.RefreshToken
-	err := d.getDB().WithContext(ctx).
+	err := d.conn(ctx).
 		Preload("User").
 		Where("token = ? AND revoked = ?", token, false).
 		First(&refreshToken).Error
@@ -42,25 +42,55 @@ func (d *refreshTokenDAO) FindByToken(ctx context.Context, token string) (*entit
 
 // RevokeByToken revokes a specific refresh token.
 func (d *refreshTokenDAO) RevokeByToken(ctx context.Context, token string) error {
-	return d.getDB().WithContext(ctx).
+	return d.conn(ctx).
 		Model(&entity.RefreshToken{}).
 		Where("token = ?", token).
 		Update("revoked", true).Error
 }
 
+// FindActiveByUserID retrieves the user's non-revoked, unexpired refresh tokens, newest first.
+func (d *refreshTokenDAO) FindActiveByUserID(ctx context.Context, userID uint) ([]*entity.RefreshToken, error) {
+	var tokens []*entity.RefreshToken
+	err := d.conn(ctx).
+		Where("user_id = ? AND revoked = ? AND expires_at > ?", userID, false, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// RevokeByID revokes a refresh token if it belongs to the user.
+func (d *refreshTokenDAO) RevokeByID(ctx context.Context, userID, id uint) (bool, error) {
+	result := d.conn(ctx).
+		Model(&entity.RefreshToken{}).
+		Where("id = ? AND user_id = ? AND revoked = ?", id, userID, false).
+		Update("revoked", true)
+	return result.RowsAffected == 1, result.Error
+}
+
 // RevokeAllByUserID revokes all refresh tokens for a specific user.
 func (d *refreshTokenDAO) RevokeAllByUserID(ctx context.Context, userID uint) error {
-	return d.getDB().WithContext(ctx).
+	return d.conn(ctx).
 		Model(&entity.RefreshToken{}).
 		Where("user_id = ?", userID).
 		Update("revoked", true).Error
 }
 
-// DeleteExpired removes all expired tokens from the database.
-func (d *refreshTokenDAO) DeleteExpired(ctx context.Context) error {
-	return d.getDB().WithContext(ctx).
-		Where("expires_at < ?", time.Now()).
-		Delete(&entity.RefreshToken{}).Error
+// DeleteExpired permanently removes tokens that expired before the given time.
+func (d *refreshTokenDAO) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := d.conn(ctx).Unscoped().
+		Where("expires_at < ?", before).
+		Delete(&entity.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
+
+// CountExpired counts the tokens that expired before the given time.
+func (d *refreshTokenDAO) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := d.conn(ctx).Unscoped().
+		Model(&entity.RefreshToken{}).
+		Where("expires_at < ?", before).
+		Count(&count).Error
+	return count, err
 }
 
 // FindAll retrieves refresh tokens with pagination, ordered by created_at descending.
@@ -69,11 +99,11 @@ func (d *refreshTokenDAO) FindAll(ctx context.Context, page, size int) ([]*entit
 	var total int64
 	offset := (page - 1) * size
 
-	if err := d.getDB().WithContext(ctx).Model(&entity.RefreshToken{}).Count(&total).Error; err != nil {
+	if err := d.conn(ctx).Model(&entity.RefreshToken{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := d.getDB().WithContext(ctx).
+	err := d.conn(ctx).
 		Preload("User").
 		Offset(offset).
 		Limit(size).
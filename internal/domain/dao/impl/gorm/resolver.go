@@ -0,0 +1,20 @@
+package gorm
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// RegisterReplicas routes reads on db to the given replicas, picked at
+// random per query, while writes and transactions stay on the primary.
+// Reads made with a dao.ForcePrimary context also stay on the primary.
+// It is a no-op when no replicas are given.
+func RegisterReplicas(db *gorm.DB, replicas ...gorm.Dialector) error {
+	if len(replicas) == 0 {
+		return nil
+	}
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}))
+}
@@ -0,0 +1,47 @@
+package gorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+)
+
+// txContextKey carries the transactional *gorm.DB through a context.
+type txContextKey struct{}
+
+// txManager implements dao.TxManager using GORM transactions.
+type txManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager creates a new GORM-based TxManager.
+func NewTxManager(db *gorm.DB) dao.TxManager {
+	return &txManager{db: db}
+}
+
+// WithTransaction runs fn inside db.Transaction. The transaction is carried
+// by the context passed to fn, where the DAOs pick it up.
+func (m *txManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// dbFromContext returns the transaction carried by ctx, or db if there is
+// none, bound to ctx. Outside a transaction, reads are pinned to the primary
+// when ctx was marked with dao.ForcePrimary.
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	if dao.IsPrimaryForced(ctx) {
+		return db.WithContext(ctx).Clauses(dbresolver.Write)
+	}
+	return db.WithContext(ctx)
+}
@@ -3,8 +3,10 @@ package gorm
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
@@ -18,14 +20,42 @@ type userDAO struct {
 // NewUserDAO creates a new GORM-based UserDAO.
 func NewUserDAO(db *gorm.DB) dao.UserDAO {
 	return &userDAO{
-		baseGormDAO: newBaseGormDAO[entity.User](db),
+		baseGormDAO: newBaseGormDAO[entity.User](db, dao.UserQueryFields),
 	}
 }
 
+// Update saves the user if it has not changed since it was read, bumping its
+// lock version. Returns dao.ErrConcurrentModification otherwise.
+func (d *userDAO) Update(ctx context.Context, user *entity.User) error {
+	return updateVersioned(d.conn(ctx), user, &user.LockVersion)
+}
+
+// FindByIDIncludingDeleted retrieves a user by ID, including soft-deleted users.
+func (d *userDAO) FindByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error) {
+	var user entity.User
+	err := d.conn(ctx).Unscoped().First(&user, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Restore clears deleted_at on a soft-deleted user.
+func (d *userDAO) Restore(ctx context.Context, id uint) error {
+	return d.conn(ctx).
+		Unscoped().
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
 // FindByUsername retrieves a user by their unique username.
 func (d *userDAO) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var user entity.User
-	err := d.getDB().WithContext(ctx).Where("username = ?", username).First(&user).Error
+	err := d.conn(ctx).Where("username = ?", username).First(&user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -38,7 +68,7 @@ func (d *userDAO) FindByUsername(ctx context.Context, username string) (*entity.
 // FindByEmail retrieves a user by their unique email address.
 func (d *userDAO) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	err := d.getDB().WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := d.conn(ctx).Where("email = ?", email).First(&user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -51,7 +81,7 @@ func (d *userDAO) FindByEmail(ctx context.Context, email string) (*entity.User,
 // FindByUsernameOrEmail retrieves a user by username or email.
 func (d *userDAO) FindByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (*entity.User, error) {
 	var user entity.User
-	err := d.getDB().WithContext(ctx).
+	err := d.conn(ctx).
 		Where("username = ? OR email = ?", usernameOrEmail, usernameOrEmail).
 		First(&user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -73,17 +103,91 @@ func (d *userDAO) ExistsByEmail(ctx context.Context, email string) (bool, error)
 	return d.ExistsBy(ctx, "email", email)
 }
 
+// Search retrieves a page of users matching the query.
+func (d *userDAO) Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error) {
+	filter := userQueryScope(query)
+
+	var total int64
+	if err := d.conn(ctx).Model(&entity.User{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// The sort field is whitelisted by UserQuery.Normalize; id breaks ties so
+	// pages do not overlap
+	order := clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: clause.Column{Name: query.SortBy}, Desc: query.SortDesc},
+	}}
+	if query.SortBy != entity.UserSortID {
+		order.Columns = append(order.Columns, clause.OrderByColumn{Column: clause.Column{Name: entity.UserSortID}, Desc: query.SortDesc})
+	}
+
+	var users []*entity.User
+	err := d.conn(ctx).
+		Scopes(filter).
+		Order(order).
+		Offset(query.Offset()).
+		Limit(query.Size).
+		Find(&users).Error
+	return users, total, err
+}
+
+// userQueryScope applies the filters of a user query
+func userQueryScope(query *entity.UserQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.Role != nil {
+			db = db.Where("role = ?", *query.Role)
+		}
+		if query.IsActive != nil {
+			db = db.Where("is_active = ?", *query.IsActive)
+		}
+		if query.IsVerified != nil {
+			db = db.Where("is_verified = ?", *query.IsVerified)
+		}
+		if query.CreatedAfter != nil {
+			db = db.Where("created_at >= ?", *query.CreatedAfter)
+		}
+		if query.CreatedBefore != nil {
+			db = db.Where("created_at < ?", *query.CreatedBefore)
+		}
+		if query.Text != "" {
+			// A prefix match can use the username and email indexes
+			prefix := escapeLike(query.Text) + "%"
+			db = db.Where("(username LIKE ? ESCAPE '!' OR email LIKE ? ESCAPE '!')", prefix, prefix)
+		}
+		return db
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in s, using ! as the escape character
+// since backslash handling differs between databases
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
 // FindAll retrieves users with pagination, ordered by ID descending.
 func (d *userDAO) FindAll(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
+	return d.FindAllWithOptions(ctx, page, size, false)
+}
+
+// FindAllWithOptions retrieves users with pagination, ordered by ID
+// descending, including soft-deleted users if includeDeleted is set.
+func (d *userDAO) FindAllWithOptions(ctx context.Context, page, size int, includeDeleted bool) ([]*entity.User, int64, error) {
 	var users []*entity.User
 	var total int64
 	offset := (page - 1) * size
 
-	if err := d.getDB().WithContext(ctx).Model(&entity.User{}).Count(&total).Error; err != nil {
+	scope := func() *gorm.DB {
+		if includeDeleted {
+			return d.conn(ctx).Unscoped()
+		}
+		return d.conn(ctx)
+	}
+
+	if err := scope().Model(&entity.User{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := d.getDB().WithContext(ctx).
+	err := scope().
 		Offset(offset).
 		Limit(size).
 		Order("id DESC").
@@ -91,3 +195,12 @@ func (d *userDAO) FindAll(ctx context.Context, page, size int) ([]*entity.User,
 
 	return users, total, err
 }
+
+// FindAllCursor retrieves up to limit users after afterID, ordered by ID ascending.
+func (d *userDAO) FindAllCursor(ctx context.Context, afterID uint, limit int) (*dao.CursorResult[entity.User], error) {
+	users, err := d.findAfterID(ctx, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return dao.NewCursorResult(users, limit, func(e *entity.User) uint { return e.ID }), nil
+}
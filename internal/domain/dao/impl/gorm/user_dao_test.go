@@ -2,6 +2,9 @@ package gorm
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 )
 
@@ -17,7 +21,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.Plugin{}, &entity.PluginExtension{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.PasswordResetToken{}, &entity.APIKey{}, &entity.Plugin{}, &entity.PluginExtension{}, &entity.PluginStateTransition{}, &entity.AuditLog{})
 	require.NoError(t, err)
 
 	return db
@@ -177,6 +181,45 @@ func TestUserDAO_Update(t *testing.T) {
 	assert.Equal(t, "Updated", found.FirstName)
 }
 
+func TestUserDAO_Update_OptimisticLock(t *testing.T) {
+	db := setupTestDB(t)
+	userDAO := NewUserDAO(db)
+	ctx := context.Background()
+
+	user := &entity.User{
+		Username: "lockuser",
+		Email:    "lock@example.com",
+		Password: "hashedpassword",
+		Role:     entity.RoleUser,
+	}
+	require.NoError(t, userDAO.Create(ctx, user))
+	assert.Equal(t, uint(0), user.LockVersion)
+
+	first, err := userDAO.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	second, err := userDAO.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+
+	first.FirstName = "First"
+	require.NoError(t, userDAO.Update(ctx, first))
+	assert.Equal(t, uint(1), first.LockVersion)
+
+	second.FirstName = "Second"
+	err = userDAO.Update(ctx, second)
+	assert.ErrorIs(t, err, dao.ErrConcurrentModification)
+	assert.Equal(t, uint(0), second.LockVersion)
+
+	found, err := userDAO.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "First", found.FirstName)
+	assert.Equal(t, uint(1), found.LockVersion)
+
+	// The winner can keep updating with its fresh version
+	first.LastName = "Again"
+	require.NoError(t, userDAO.Update(ctx, first))
+	assert.Equal(t, uint(2), first.LockVersion)
+}
+
 func TestUserDAO_Delete(t *testing.T) {
 	db := setupTestDB(t)
 	dao := NewUserDAO(db)
@@ -201,6 +244,136 @@ func TestUserDAO_Delete(t *testing.T) {
 	assert.Nil(t, found)
 }
 
+func TestUserDAO_Restore(t *testing.T) {
+	db := setupTestDB(t)
+	userDAO := NewUserDAO(db)
+	ctx := context.Background()
+
+	user := &entity.User{
+		Username: "restoreuser",
+		Email:    "restore@example.com",
+		Password: "hashedpassword",
+		Role:     entity.RoleUser,
+	}
+	require.NoError(t, userDAO.Create(ctx, user))
+	require.NoError(t, userDAO.Delete(ctx, user.ID))
+
+	deleted, err := userDAO.FindByIDIncludingDeleted(ctx, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, deleted)
+	assert.True(t, deleted.DeletedAt.Valid)
+
+	require.NoError(t, userDAO.Restore(ctx, user.ID))
+
+	found, err := userDAO.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.False(t, found.DeletedAt.Valid)
+
+	missing, err := userDAO.FindByIDIncludingDeleted(ctx, 9999)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestTxManager_WithTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	txManager := NewTxManager(db)
+	userDAO := NewUserDAO(db)
+	tokenDAO := NewRefreshTokenDAO(db)
+	ctx := context.Background()
+
+	newUser := func(name string) *entity.User {
+		return &entity.User{Username: name, Email: name + "@example.com", Password: "hashedpassword", Role: entity.RoleUser}
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		user := newUser("committed")
+		err := txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := userDAO.Create(ctx, user); err != nil {
+				return err
+			}
+			return tokenDAO.Create(ctx, &entity.RefreshToken{UserID: user.ID, Token: "committed-token", ExpiresAt: time.Now().Add(time.Hour)})
+		})
+		require.NoError(t, err)
+
+		found, err := userDAO.FindByUsername(ctx, "committed")
+		require.NoError(t, err)
+		assert.NotNil(t, found)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		errFailed := errors.New("token failed")
+		err := txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := userDAO.Create(ctx, newUser("rolledback")); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+
+		found, err := userDAO.FindByUsername(ctx, "rolledback")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("nested call joins outer transaction", func(t *testing.T) {
+		errFailed := errors.New("outer failed")
+		err := txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			err := txManager.WithTransaction(ctx, func(ctx context.Context) error {
+				return userDAO.Create(ctx, newUser("nested"))
+			})
+			require.NoError(t, err)
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+
+		found, err := userDAO.FindByUsername(ctx, "nested")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+	})
+}
+
+func TestRegisterReplicas(t *testing.T) {
+	dir := t.TempDir()
+	openMigrated := func(name string) *gorm.DB {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(dir, name)), &gorm.Config{})
+		require.NoError(t, err)
+		require.NoError(t, db.AutoMigrate(&entity.User{}))
+		return db
+	}
+	primary := openMigrated("primary.db")
+	openMigrated("replica.db")
+
+	require.NoError(t, RegisterReplicas(primary, sqlite.Open(filepath.Join(dir, "replica.db"))))
+	userDAO := NewUserDAO(primary)
+	txManager := NewTxManager(primary)
+	ctx := context.Background()
+
+	user := &entity.User{Username: "replicated", Email: "replicated@example.com", Password: "hashedpassword", Role: entity.RoleUser}
+	require.NoError(t, userDAO.Create(ctx, user))
+
+	// The empty replica serves plain reads
+	found, err := userDAO.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	found, err = userDAO.FindByID(dao.ForcePrimary(ctx), user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "replicated", found.Username)
+
+	err = txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		found, err = userDAO.FindByID(ctx, user.ID)
+		return err
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, found, "reads inside a transaction should use the primary")
+}
+
+func TestRegisterReplicas_NoReplicas(t *testing.T) {
+	assert.NoError(t, RegisterReplicas(setupTestDB(t)))
+}
+
 func TestUserDAO_FindAll(t *testing.T) {
 	db := setupTestDB(t)
 	dao := NewUserDAO(db)
@@ -232,6 +405,171 @@ func TestUserDAO_FindAll(t *testing.T) {
 	assert.Equal(t, int64(15), total)
 }
 
+func TestUserDAO_FindAllWithOptions(t *testing.T) {
+	db := setupTestDB(t)
+	userDAO := NewUserDAO(db)
+	ctx := context.Background()
+
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		user := &entity.User{
+			Username: "withopts" + string(rune('a'+i)),
+			Email:    "withopts" + string(rune('a'+i)) + "@example.com",
+			Password: "hashedpassword",
+			Role:     entity.RoleUser,
+		}
+		require.NoError(t, userDAO.Create(ctx, user))
+		ids = append(ids, user.ID)
+	}
+	require.NoError(t, userDAO.Delete(ctx, ids[2]))
+
+	users, total, err := userDAO.FindAllWithOptions(ctx, 1, 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, users, 2)
+	for _, user := range users {
+		assert.NotEqual(t, ids[2], user.ID)
+	}
+
+	users, total, err = userDAO.FindAllWithOptions(ctx, 1, 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, users, 3)
+	assert.Equal(t, ids[2], users[0].ID, "newest first")
+	assert.True(t, users[0].DeletedAt.Valid)
+
+	// The count covers deleted users even when the page does not
+	users, total, err = userDAO.FindAllWithOptions(ctx, 2, 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, users, 1)
+}
+
+func TestUserDAO_FindAllCursor(t *testing.T) {
+	db := setupTestDB(t)
+	userDAO := NewUserDAO(db)
+	ctx := context.Background()
+
+	var ids []uint
+	for i := 0; i < 7; i++ {
+		user := &entity.User{
+			Username: "cursor" + string(rune('a'+i)),
+			Email:    "cursor" + string(rune('a'+i)) + "@example.com",
+			Password: "hashedpassword",
+			Role:     entity.RoleUser,
+		}
+		require.NoError(t, userDAO.Create(ctx, user))
+		ids = append(ids, user.ID)
+	}
+
+	first, err := userDAO.FindAllCursor(ctx, 0, 3)
+	require.NoError(t, err)
+	require.Len(t, first.Items, 3)
+	assert.Equal(t, ids[0], first.Items[0].ID)
+	assert.Equal(t, ids[2], first.Items[2].ID)
+	require.NotEmpty(t, first.NextCursor)
+
+	// Deleting rows already served must not shift the next page
+	require.NoError(t, userDAO.Delete(ctx, ids[0]))
+	require.NoError(t, userDAO.Delete(ctx, ids[1]))
+
+	afterID, err := dao.DecodeCursor(first.NextCursor)
+	require.NoError(t, err)
+	second, err := userDAO.FindAllCursor(ctx, afterID, 3)
+	require.NoError(t, err)
+	require.Len(t, second.Items, 3)
+	assert.Equal(t, ids[3], second.Items[0].ID)
+	assert.Equal(t, ids[5], second.Items[2].ID)
+
+	afterID, err = dao.DecodeCursor(second.NextCursor)
+	require.NoError(t, err)
+	last, err := userDAO.FindAllCursor(ctx, afterID, 3)
+	require.NoError(t, err)
+	require.Len(t, last.Items, 1)
+	assert.Equal(t, ids[6], last.Items[0].ID)
+	assert.Empty(t, last.NextCursor)
+}
+
+func TestUserDAO_Search(t *testing.T) {
+	db := setupTestDB(t)
+	dao := NewUserDAO(db)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := []struct {
+		username string
+		role     entity.UserRole
+		active   bool
+		verified bool
+	}{
+		{"alice", entity.RoleAdmin, true, true},
+		{"albert", entity.RoleUser, true, false},
+		{"al_x", entity.RoleUser, false, true},
+		{"bob", entity.RoleUser, true, true},
+		{"carol", entity.RoleUser, true, false},
+	}
+	for i, u := range seed {
+		user := &entity.User{
+			Username:   u.username,
+			Email:      u.username + "@example.com",
+			Password:   "hashedpassword",
+			Role:       u.role,
+			IsActive:   true,
+			IsVerified: u.verified,
+			CreatedAt:  base.AddDate(0, 0, i),
+		}
+		require.NoError(t, dao.Create(ctx, user))
+		if !u.active {
+			// is_active defaults to true on insert
+			user.IsActive = false
+			require.NoError(t, dao.Update(ctx, user))
+		}
+	}
+
+	usernames := func(users []*entity.User) []string {
+		names := make([]string, len(users))
+		for i, u := range users {
+			names[i] = u.Username
+		}
+		return names
+	}
+	ptr := func(b bool) *bool { return &b }
+	role := entity.RoleUser
+	after := base.AddDate(0, 0, 1)
+	before := base.AddDate(0, 0, 4)
+
+	tests := []struct {
+		name      string
+		query     entity.UserQuery
+		want      []string
+		wantTotal int64
+	}{
+		{"default newest first", entity.UserQuery{}, []string{"carol", "bob", "al_x", "albert", "alice"}, 5},
+		{"role", entity.UserQuery{Role: &role, SortBy: entity.UserSortUsername}, []string{"al_x", "albert", "bob", "carol"}, 4},
+		{"inactive", entity.UserQuery{IsActive: ptr(false)}, []string{"al_x"}, 1},
+		{"verified", entity.UserQuery{IsVerified: ptr(true), SortBy: entity.UserSortID}, []string{"alice", "al_x", "bob"}, 3},
+		{"created range", entity.UserQuery{CreatedAfter: &after, CreatedBefore: &before, SortBy: entity.UserSortCreatedAt}, []string{"albert", "al_x", "bob"}, 3},
+		{"text prefix", entity.UserQuery{Text: "al", SortBy: entity.UserSortUsername}, []string{"al_x", "albert", "alice"}, 3},
+		{"text wildcard is literal", entity.UserQuery{Text: "al_"}, []string{"al_x"}, 1},
+		{"text does not match infix", entity.UserQuery{Text: "lice"}, []string{}, 0},
+		{"email prefix", entity.UserQuery{Text: "bob@"}, []string{"bob"}, 1},
+		{"sort descending", entity.UserQuery{SortBy: entity.UserSortEmail, SortDesc: true, Size: 2}, []string{"carol", "bob"}, 5},
+		{"second page", entity.UserQuery{SortBy: entity.UserSortEmail, Page: 2, Size: 2}, []string{"alice", "bob"}, 5},
+		{"unknown sort field", entity.UserQuery{SortBy: "password"}, []string{"carol", "bob", "al_x", "albert", "alice"}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query
+			query.Normalize()
+			users, total, err := dao.Search(ctx, &query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, usernames(users))
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
 func TestUserDAO_Count(t *testing.T) {
 	db := setupTestDB(t)
 	dao := NewUserDAO(db)
@@ -334,7 +672,7 @@ func TestUserDAO_ExistsBy(t *testing.T) {
 
 func TestBaseGormDAO_Helpers(t *testing.T) {
 	db := setupTestDB(t)
-	baseDAO := newBaseGormDAO[entity.User](db)
+	baseDAO := newBaseGormDAO[entity.User](db, dao.UserQueryFields)
 	ctx := context.Background()
 
 	// Test getDB
@@ -439,8 +777,23 @@ func TestRefreshTokenDAO_Operations(t *testing.T) {
 	err = dao.Create(ctx, expiredToken)
 	require.NoError(t, err)
 
-	err = dao.DeleteExpired(ctx)
+	count, err := dao.CountExpired(ctx, time.Now())
 	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// A cutoff before the expiry keeps the token
+	deleted, err := dao.DeleteExpired(ctx, time.Now().Add(-2*time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	deleted, err = dao.DeleteExpired(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	// Removed for good, not soft-deleted
+	var remaining int64
+	require.NoError(t, db.Unscoped().Model(&entity.RefreshToken{}).Where("token = ?", "expired-token").Count(&remaining).Error)
+	assert.Zero(t, remaining)
 
 	// Test FindAll
 	tokens, total, err := dao.FindAll(ctx, 1, 10)
@@ -449,6 +802,172 @@ func TestRefreshTokenDAO_Operations(t *testing.T) {
 	assert.GreaterOrEqual(t, total, int64(0))
 }
 
+func TestRefreshTokenDAO_Sessions(t *testing.T) {
+	db := setupTestDB(t)
+	dao := NewRefreshTokenDAO(db)
+	ctx := context.Background()
+
+	active := &entity.RefreshToken{
+		UserID:    1,
+		Token:     "session-active",
+		ExpiresAt: time.Now().Add(time.Hour),
+		Device:    "iPhone",
+		UserAgent: "Mozilla/5.0 (iPhone)",
+		IPAddress: "203.0.113.7",
+	}
+	require.NoError(t, dao.Create(ctx, active))
+	require.NoError(t, dao.Create(ctx, &entity.RefreshToken{UserID: 1, Token: "session-expired", ExpiresAt: time.Now().Add(-time.Hour)}))
+	require.NoError(t, dao.Create(ctx, &entity.RefreshToken{UserID: 1, Token: "session-revoked", ExpiresAt: time.Now().Add(time.Hour), Revoked: true}))
+	other := &entity.RefreshToken{UserID: 2, Token: "session-other", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, dao.Create(ctx, other))
+
+	sessions, err := dao.FindActiveByUserID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, active.ID, sessions[0].ID)
+	assert.Equal(t, "iPhone", sessions[0].Device)
+	assert.Equal(t, "203.0.113.7", sessions[0].IPAddress)
+
+	// Another user's token is not revoked
+	revoked, err := dao.RevokeByID(ctx, 1, other.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = dao.RevokeByID(ctx, 1, active.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = dao.RevokeByID(ctx, 1, active.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	sessions, err = dao.FindActiveByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestPasswordResetTokenDAO_Operations(t *testing.T) {
+	db := setupTestDB(t)
+	dao := NewPasswordResetTokenDAO(db)
+	ctx := context.Background()
+
+	token := &entity.PasswordResetToken{
+		UserID:    1,
+		TokenHash: "reset-hash-123",
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+	}
+	err := dao.Create(ctx, token)
+	require.NoError(t, err)
+	assert.NotZero(t, token.ID)
+
+	// Find by hash
+	found, err := dao.FindByTokenHash(ctx, "reset-hash-123")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.True(t, found.IsValid())
+
+	notFound, err := dao.FindByTokenHash(ctx, "unknown-hash")
+	assert.NoError(t, err)
+	assert.Nil(t, notFound)
+
+	// A token can only be used once
+	used, err := dao.MarkUsed(ctx, token.ID)
+	assert.NoError(t, err)
+	assert.True(t, used)
+
+	used, err = dao.MarkUsed(ctx, token.ID)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	found, err = dao.FindByTokenHash(ctx, "reset-hash-123")
+	require.NoError(t, err)
+	assert.NotNil(t, found.UsedAt)
+	assert.False(t, found.IsValid())
+
+	// Invalidate outstanding tokens for a user
+	token2 := &entity.PasswordResetToken{
+		UserID:    1,
+		TokenHash: "reset-hash-456",
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+	}
+	require.NoError(t, dao.Create(ctx, token2))
+	require.NoError(t, dao.InvalidateAllByUserID(ctx, 1))
+
+	used, err = dao.MarkUsed(ctx, token2.ID)
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	// Delete expired
+	expired := &entity.PasswordResetToken{
+		UserID:    1,
+		TokenHash: "expired-hash",
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}
+	require.NoError(t, dao.Create(ctx, expired))
+	count, err := dao.CountExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	deleted, err := dao.DeleteExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	gone, err := dao.FindByTokenHash(ctx, "expired-hash")
+	assert.NoError(t, err)
+	assert.Nil(t, gone)
+}
+
+func TestAPIKeyDAO_Operations(t *testing.T) {
+	db := setupTestDB(t)
+	dao := NewAPIKeyDAO(db)
+	ctx := context.Background()
+
+	key := &entity.APIKey{
+		Name:      "billing",
+		Prefix:    "ak_abcdefgh",
+		KeyHash:   "key-hash-123",
+		CreatedBy: 1,
+	}
+	key.SetScopes([]string{"jobs:read"})
+	require.NoError(t, dao.Create(ctx, key))
+	assert.NotZero(t, key.ID)
+
+	// Find by hash
+	found, err := dao.FindByKeyHash(ctx, "key-hash-123")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.True(t, found.IsValid())
+	assert.True(t, found.HasScope("jobs:read"))
+
+	notFound, err := dao.FindByKeyHash(ctx, "unknown-hash")
+	assert.NoError(t, err)
+	assert.Nil(t, notFound)
+
+	// Newest keys are listed first
+	newer := &entity.APIKey{Name: "reports", Prefix: "ak_ijklmnop", KeyHash: "key-hash-456", CreatedBy: 1}
+	require.NoError(t, dao.Create(ctx, newer))
+	require.NoError(t, db.Model(newer).Update("created_at", time.Now().Add(time.Minute)).Error)
+
+	keys, total, err := dao.FindAll(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "reports", keys[0].Name)
+
+	// A key can only be revoked once
+	revoked, err := dao.Revoke(ctx, key.ID)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = dao.Revoke(ctx, key.ID)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	found, err = dao.FindByKeyHash(ctx, "key-hash-123")
+	require.NoError(t, err)
+	assert.NotNil(t, found.RevokedAt)
+	assert.False(t, found.IsValid())
+}
+
 func TestPluginDAO_Operations(t *testing.T) {
 	db := setupTestDB(t)
 	dao := NewPluginDAO(db)
@@ -505,7 +1024,9 @@ func TestPluginDAO_Operations(t *testing.T) {
 	assert.Len(t, plugins, 1)
 	assert.Equal(t, int64(1), total)
 
-	// Update
+	// Update; the state change above bumped the lock version, so re-read first
+	plugin, err = dao.FindByID(ctx, plugin.ID)
+	require.NoError(t, err)
 	plugin.Name = "Updated Plugin"
 	err = dao.Update(ctx, plugin)
 	assert.NoError(t, err)
@@ -606,3 +1127,288 @@ func TestPluginExtensionDAO_Operations(t *testing.T) {
 	err = dao.Delete(ctx, extension2.ID)
 	assert.NoError(t, err)
 }
+
+func TestPluginStateTransitionDAO_Operations(t *testing.T) {
+	db := setupTestDB(t)
+	pluginDAO := NewPluginDAO(db)
+	dao := NewPluginStateTransitionDAO(db)
+	txManager := NewTxManager(db)
+	ctx := context.Background()
+
+	plugin := &entity.Plugin{
+		Key:         "history-test-plugin",
+		Name:        "History Test Plugin",
+		Version:     "1.0.0",
+		Type:        entity.PluginTypeService,
+		State:       entity.PluginStateInstalled,
+		InstalledAt: time.Now(),
+	}
+	require.NoError(t, pluginDAO.Create(ctx, plugin))
+
+	require.NoError(t, dao.Create(ctx, &entity.PluginStateTransition{PluginID: plugin.ID, ToState: entity.PluginStateInstalled}))
+	require.NoError(t, dao.Create(ctx, &entity.PluginStateTransition{PluginID: plugin.ID, FromState: entity.PluginStateInstalled, ToState: entity.PluginStateEnabled}))
+
+	// A transition rolled back with its state change leaves no trace
+	err := txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := pluginDAO.UpdateState(ctx, plugin.ID, entity.PluginStateError); err != nil {
+			return err
+		}
+		if err := dao.Create(ctx, &entity.PluginStateTransition{PluginID: plugin.ID, FromState: entity.PluginStateEnabled, ToState: entity.PluginStateError}); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+
+	transitions, err := dao.FindByPluginID(ctx, plugin.ID)
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, entity.PluginStateInstalled, transitions[0].ToState)
+	assert.Equal(t, entity.PluginStateEnabled, transitions[1].ToState)
+
+	found, err := pluginDAO.FindByID(ctx, plugin.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PluginStateInstalled, found.State)
+
+	all, total, err := dao.FindAll(ctx, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestAuditLogDAO_Query(t *testing.T) {
+	db := setupTestDB(t)
+	dao := NewAuditLogDAO(db)
+	ctx := context.Background()
+
+	for _, log := range []*entity.AuditLog{
+		{ActorID: 1, Actor: "alice", Action: entity.AuditActionLogin, Outcome: entity.AuditOutcomeSuccess},
+		{ActorID: 2, Actor: "bob", Action: entity.AuditActionLogin, Outcome: entity.AuditOutcomeSuccess},
+		{ActorID: 1, Actor: "alice", Action: entity.AuditActionPluginInstall, Target: "hello-plugin", Outcome: entity.AuditOutcomeSuccess},
+	} {
+		require.NoError(t, dao.Create(ctx, log))
+	}
+
+	actorID := uint(1)
+	logs, total, err := dao.Query(ctx, &entity.AuditQuery{ActorID: &actorID, Page: 1, Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, logs, 2)
+	assert.Equal(t, entity.AuditActionPluginInstall, logs[0].Action, "newest first")
+
+	logs, total, err = dao.Query(ctx, &entity.AuditQuery{Action: entity.AuditActionLogin, Actor: "bob", Page: 1, Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, uint(2), logs[0].ActorID)
+
+	future := time.Now().Add(time.Hour)
+	_, total, err = dao.Query(ctx, &entity.AuditQuery{From: &future, Page: 1, Size: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	_, total, err = dao.Query(ctx, &entity.AuditQuery{To: &future, Page: 1, Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	logs, total, err = dao.FindAll(ctx, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, logs, 1)
+}
+
+func TestAuditLogDAO_DeleteBefore(t *testing.T) {
+	db := setupTestDB(t)
+	dao := NewAuditLogDAO(db)
+	ctx := context.Background()
+
+	old := &entity.AuditLog{Actor: "alice", Action: entity.AuditActionLogin, Outcome: entity.AuditOutcomeSuccess}
+	recent := &entity.AuditLog{Actor: "bob", Action: entity.AuditActionLogin, Outcome: entity.AuditOutcomeSuccess}
+	require.NoError(t, dao.Create(ctx, old))
+	require.NoError(t, dao.Create(ctx, recent))
+	require.NoError(t, db.Model(old).Update("created_at", time.Now().AddDate(0, 0, -100)).Error)
+
+	cutoff := time.Now().AddDate(0, 0, -90)
+	count, err := dao.CountBefore(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	deleted, err := dao.DeleteBefore(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	logs, total, err := dao.FindAll(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "bob", logs[0].Actor)
+}
+
+func TestPluginDAO_PurgeDeleted(t *testing.T) {
+	db := setupTestDB(t)
+	dao := NewPluginDAO(db)
+	extensions := NewPluginExtensionDAO(db)
+	transitions := NewPluginStateTransitionDAO(db)
+	ctx := context.Background()
+
+	create := func(key string) *entity.Plugin {
+		plugin := &entity.Plugin{Key: key, Name: key, Version: "1.0.0", Type: entity.PluginTypeService}
+		require.NoError(t, dao.Create(ctx, plugin))
+		require.NoError(t, extensions.Create(ctx, &entity.PluginExtension{PluginID: plugin.ID, Name: "ext", Type: entity.PluginTypeService}))
+		require.NoError(t, transitions.Create(ctx, &entity.PluginStateTransition{PluginID: plugin.ID, ToState: entity.PluginStateInstalled}))
+		return plugin
+	}
+	orphaned := create("orphaned")
+	recentlyDeleted := create("recently-deleted")
+	installed := create("installed")
+
+	require.NoError(t, dao.DeleteByKey(ctx, "orphaned"))
+	require.NoError(t, dao.DeleteByKey(ctx, "recently-deleted"))
+	require.NoError(t, db.Unscoped().Model(orphaned).Update("deleted_at", time.Now().AddDate(0, 0, -10)).Error)
+
+	cutoff := time.Now().AddDate(0, 0, -7)
+	count, err := dao.CountDeleted(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	purged, err := dao.PurgeDeleted(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var remaining []uint
+	require.NoError(t, db.Unscoped().Model(&entity.Plugin{}).Order("id").Pluck("id", &remaining).Error)
+	assert.Equal(t, []uint{recentlyDeleted.ID, installed.ID}, remaining)
+
+	var orphanRows int64
+	require.NoError(t, db.Unscoped().Model(&entity.PluginExtension{}).Where("plugin_id = ?", orphaned.ID).Count(&orphanRows).Error)
+	assert.Zero(t, orphanRows, "extensions of the purged plugin")
+	require.NoError(t, db.Model(&entity.PluginStateTransition{}).Where("plugin_id = ?", orphaned.ID).Count(&orphanRows).Error)
+	assert.Zero(t, orphanRows, "state history of the purged plugin")
+
+	history, err := transitions.FindByPluginID(ctx, installed.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	// The key of a purged plugin can be installed again
+	require.NoError(t, dao.Create(ctx, &entity.Plugin{Key: "orphaned", Name: "orphaned", Version: "2.0.0", Type: entity.PluginTypeService}))
+}
+
+func TestBaseGormDAO_FindBy(t *testing.T) {
+	db := setupTestDB(t)
+	userDAO := NewUserDAO(db)
+	ctx := context.Background()
+
+	for _, u := range []struct {
+		username string
+		role     entity.UserRole
+	}{
+		{"alice", entity.RoleAdmin},
+		{"bob", entity.RoleUser},
+		{"carol", entity.RoleUser},
+		{"dave", entity.RoleUser},
+	} {
+		require.NoError(t, userDAO.Create(ctx, &entity.User{
+			Username: u.username,
+			Email:    u.username + "@example.com",
+			Password: "hashedpassword",
+			Role:     u.role,
+		}))
+	}
+	dave, err := userDAO.FindByUsername(ctx, "dave")
+	require.NoError(t, err)
+	require.NoError(t, userDAO.Delete(ctx, dave.ID))
+
+	usernames := func(users []*entity.User) []string {
+		names := make([]string, len(users))
+		for i, u := range users {
+			names[i] = u.Username
+		}
+		return names
+	}
+
+	t.Run("AND of conditions", func(t *testing.T) {
+		users, err := userDAO.FindBy(ctx, dao.Query{
+			Where: []dao.Condition{dao.Eq("role", entity.RoleUser), dao.Ne("username", "bob")},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"carol"}, usernames(users))
+	})
+
+	t.Run("OR group", func(t *testing.T) {
+		users, err := userDAO.FindBy(ctx, dao.Query{
+			Where: []dao.Condition{dao.Or(dao.Eq("role", entity.RoleAdmin), dao.Eq("username", "carol"))},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "carol"}, usernames(users))
+	})
+
+	t.Run("IN with order, limit and offset", func(t *testing.T) {
+		users, err := userDAO.FindBy(ctx, dao.Query{
+			Where:   []dao.Condition{dao.In("username", "alice", "bob", "carol", "dave")},
+			OrderBy: []dao.SortField{{Field: "username", Desc: true}},
+			Limit:   2,
+			Offset:  1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bob", "alice"}, usernames(users), "dave is soft-deleted")
+	})
+
+	t.Run("range", func(t *testing.T) {
+		users, err := userDAO.FindBy(ctx, dao.Query{
+			Where: []dao.Condition{dao.Gt("id", 1), dao.Lte("created_at", time.Now().Add(time.Minute))},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bob", "carol"}, usernames(users))
+	})
+
+	t.Run("rejects fields not whitelisted", func(t *testing.T) {
+		for _, query := range []dao.Query{
+			{Where: []dao.Condition{dao.Eq("password", "x")}},
+			{Where: []dao.Condition{dao.Eq("username = '' OR 1=1 --", "x")}},
+			{Where: []dao.Condition{dao.Or(dao.Eq("is_active", true))}},
+			{OrderBy: []dao.SortField{{Field: "first_name"}}},
+		} {
+			_, err := userDAO.FindBy(ctx, query)
+			assert.ErrorIs(t, err, dao.ErrInvalidQuery)
+		}
+	})
+}
+
+func TestBaseGormDAO_CreateBatch(t *testing.T) {
+	db := setupTestDB(t)
+	db.CreateBatchSize = 2
+	userDAO := NewUserDAO(db)
+	ctx := context.Background()
+
+	users := make([]*entity.User, 5)
+	for i := range users {
+		users[i] = &entity.User{
+			Username: fmt.Sprintf("batch%d", i),
+			Email:    fmt.Sprintf("batch%d@example.com", i),
+			Password: "hashedpassword",
+			Role:     entity.RoleUser,
+		}
+	}
+	require.NoError(t, userDAO.CreateBatch(ctx, users))
+
+	for _, user := range users {
+		require.NotZero(t, user.ID)
+		found, err := userDAO.FindByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, user.Username, found.Username)
+	}
+	count, err := userDAO.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+
+	assert.NoError(t, userDAO.CreateBatch(ctx, nil))
+
+	// A failing batch creates none of its rows
+	duplicate := []*entity.User{
+		{Username: "fresh", Email: "fresh@example.com", Password: "hashedpassword"},
+		{Username: "fresh2", Email: "fresh2@example.com", Password: "hashedpassword"},
+		{Username: "batch0", Email: "other@example.com", Password: "hashedpassword"},
+	}
+	assert.Error(t, userDAO.CreateBatch(ctx, duplicate))
+	exists, err := userDAO.ExistsByUsername(ctx, "fresh")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
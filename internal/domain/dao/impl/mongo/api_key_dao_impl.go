@@ -0,0 +1,144 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/document"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/mapper"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// apiKeyDAO implements dao.APIKeyDAO using MongoDB.
+type apiKeyDAO struct {
+	*baseMongoDAO[entity.APIKey, document.APIKeyDocument]
+	mapper *mapper.APIKeyMapper
+}
+
+// NewAPIKeyDAO creates a new MongoDB-based APIKeyDAO.
+func NewAPIKeyDAO(db *mongo.Database, idCounter *IDCounter) dao.APIKeyDAO {
+	m := mapper.NewAPIKeyMapper()
+	return &apiKeyDAO{
+		baseMongoDAO: newBaseMongoDAO[entity.APIKey, document.APIKeyDocument](
+			db,
+			document.APIKeyDocument{}.CollectionName(),
+			idCounter,
+			dao.APIKeyQueryFields,
+			m.ToEntity,
+		),
+		mapper: m,
+	}
+}
+
+// Create inserts a new API key into MongoDB.
+func (d *apiKeyDAO) Create(ctx context.Context, key *entity.APIKey) error {
+	// Generate numeric ID for compatibility
+	id, err := d.nextID(ctx)
+	if err != nil {
+		return err
+	}
+	key.ID = id
+	key.CreatedAt = time.Now()
+
+	doc := d.mapper.ToDocument(key)
+	return d.insertOne(ctx, doc)
+}
+
+// CreateBatch inserts API keys into MongoDB at once, numbered from a single
+// block of IDs.
+func (d *apiKeyDAO) CreateBatch(ctx context.Context, keys []*entity.APIKey) error {
+	now := time.Now()
+	return d.createBatch(ctx, keys, func(key *entity.APIKey, id uint) *document.APIKeyDocument {
+		key.ID = id
+		key.CreatedAt = now
+		return d.mapper.ToDocument(key)
+	})
+}
+
+// FindByID retrieves an API key by its numeric ID.
+func (d *apiKeyDAO) FindByID(ctx context.Context, id uint) (*entity.APIKey, error) {
+	return d.findOne(ctx, withNotDeleted(bson.M{"numeric_id": id}))
+}
+
+// Update modifies an existing API key in MongoDB.
+func (d *apiKeyDAO) Update(ctx context.Context, key *entity.APIKey) error {
+	doc := d.mapper.ToDocument(key)
+
+	filter := bson.M{"numeric_id": key.ID}
+	update := bson.M{"$set": doc}
+	return d.updateOne(ctx, filter, update)
+}
+
+// Delete performs a soft delete on an API key.
+func (d *apiKeyDAO) Delete(ctx context.Context, id uint) error {
+	now := time.Now()
+	filter := bson.M{"numeric_id": id}
+	update := bson.M{"$set": bson.M{"deleted_at": now}}
+	return d.updateOne(ctx, filter, update)
+}
+
+// FindAll retrieves API keys with pagination.
+func (d *apiKeyDAO) FindAll(ctx context.Context, page, size int) ([]*entity.APIKey, int64, error) {
+	filter := notDeletedFilter()
+
+	total, err := d.count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((page - 1) * size)
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(int64(size)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var docs []*document.APIKeyDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, 0, err
+	}
+
+	return d.mapper.ToEntities(docs), total, nil
+}
+
+// Count returns the total number of API keys.
+func (d *apiKeyDAO) Count(ctx context.Context) (int64, error) {
+	return d.count(ctx, notDeletedFilter())
+}
+
+// ExistsBy checks if an API key exists by a field value.
+func (d *apiKeyDAO) ExistsBy(ctx context.Context, field string, value any) (bool, error) {
+	return d.existsBy(ctx, field, value)
+}
+
+// FindByKeyHash retrieves an API key by the hash of its value.
+func (d *apiKeyDAO) FindByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	return d.findOne(ctx, withNotDeleted(bson.M{"key_hash": keyHash}))
+}
+
+// Revoke marks an API key as revoked if it has not been revoked yet.
+func (d *apiKeyDAO) Revoke(ctx context.Context, id uint) (bool, error) {
+	filter := withNotDeleted(bson.M{"numeric_id": id, "revoked_at": nil})
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+	result, err := d.getCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (d *apiKeyDAO) findOne(ctx context.Context, filter bson.M) (*entity.APIKey, error) {
+	var doc document.APIKeyDocument
+	err := d.findOneByFilter(ctx, filter, &doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d.mapper.ToEntity(&doc), nil
+}
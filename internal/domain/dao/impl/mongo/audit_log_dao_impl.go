@@ -0,0 +1,156 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/document"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/mapper"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// auditLogDAO implements dao.AuditLogDAO using MongoDB.
+type auditLogDAO struct {
+	*baseMongoDAO[entity.AuditLog, document.AuditLogDocument]
+	mapper *mapper.AuditLogMapper
+}
+
+// NewAuditLogDAO creates a new MongoDB-based AuditLogDAO.
+func NewAuditLogDAO(db *mongo.Database, idCounter *IDCounter) dao.AuditLogDAO {
+	m := mapper.NewAuditLogMapper()
+	return &auditLogDAO{
+		baseMongoDAO: newBaseMongoDAO[entity.AuditLog, document.AuditLogDocument](
+			db,
+			document.AuditLogDocument{}.CollectionName(),
+			idCounter,
+			dao.AuditLogQueryFields,
+			m.ToEntity,
+		),
+		mapper: m,
+	}
+}
+
+// Create inserts a new audit log into MongoDB.
+func (d *auditLogDAO) Create(ctx context.Context, log *entity.AuditLog) error {
+	// Generate numeric ID for compatibility
+	id, err := d.nextID(ctx)
+	if err != nil {
+		return err
+	}
+	log.ID = id
+	log.CreatedAt = time.Now()
+
+	doc := d.mapper.ToDocument(log)
+	return d.insertOne(ctx, doc)
+}
+
+// CreateBatch inserts audit logs into MongoDB at once, numbered from a single
+// block of IDs.
+func (d *auditLogDAO) CreateBatch(ctx context.Context, logs []*entity.AuditLog) error {
+	now := time.Now()
+	return d.createBatch(ctx, logs, func(log *entity.AuditLog, id uint) *document.AuditLogDocument {
+		log.ID = id
+		log.CreatedAt = now
+		return d.mapper.ToDocument(log)
+	})
+}
+
+// FindByID retrieves an audit log by its numeric ID.
+func (d *auditLogDAO) FindByID(ctx context.Context, id uint) (*entity.AuditLog, error) {
+	var doc document.AuditLogDocument
+	err := d.findOneByFilter(ctx, bson.M{"numeric_id": id}, &doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d.mapper.ToEntity(&doc), nil
+}
+
+// Update modifies an existing audit log in MongoDB.
+func (d *auditLogDAO) Update(ctx context.Context, log *entity.AuditLog) error {
+	doc := d.mapper.ToDocument(log)
+
+	filter := bson.M{"numeric_id": log.ID}
+	update := bson.M{"$set": doc}
+	return d.updateOne(ctx, filter, update)
+}
+
+// Delete removes an audit log. Audit logs are history, so there is nothing
+// to soft-delete.
+func (d *auditLogDAO) Delete(ctx context.Context, id uint) error {
+	_, err := d.deleteMany(ctx, bson.M{"numeric_id": id})
+	return err
+}
+
+// DeleteBefore removes the audit logs recorded before the given time.
+func (d *auditLogDAO) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return d.deleteMany(ctx, bson.M{"created_at": bson.M{"$lt": before}})
+}
+
+// CountBefore counts the audit logs recorded before the given time.
+func (d *auditLogDAO) CountBefore(ctx context.Context, before time.Time) (int64, error) {
+	return d.count(ctx, bson.M{"created_at": bson.M{"$lt": before}})
+}
+
+// FindAll retrieves audit logs with pagination, newest first.
+func (d *auditLogDAO) FindAll(ctx context.Context, page, size int) ([]*entity.AuditLog, int64, error) {
+	return d.Query(ctx, &entity.AuditQuery{Page: page, Size: size})
+}
+
+// Count returns the total number of audit logs.
+func (d *auditLogDAO) Count(ctx context.Context) (int64, error) {
+	return d.count(ctx, bson.M{})
+}
+
+// ExistsBy checks if an audit log exists by a field value.
+func (d *auditLogDAO) ExistsBy(ctx context.Context, field string, value any) (bool, error) {
+	return d.existsBy(ctx, field, value)
+}
+
+// Query retrieves a page of audit logs matching the query, newest first.
+func (d *auditLogDAO) Query(ctx context.Context, query *entity.AuditQuery) ([]*entity.AuditLog, int64, error) {
+	filter := bson.M{}
+	if query.ActorID != nil {
+		filter["actor_id"] = *query.ActorID
+	}
+	if query.Actor != "" {
+		filter["actor"] = query.Actor
+	}
+	if query.Action != "" {
+		filter["action"] = query.Action
+	}
+	if query.From != nil || query.To != nil {
+		createdAt := bson.M{}
+		if query.From != nil {
+			createdAt["$gte"] = *query.From
+		}
+		if query.To != nil {
+			createdAt["$lt"] = *query.To
+		}
+		filter["created_at"] = createdAt
+	}
+
+	total, err := d.count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSkip(int64(query.Offset())).
+		SetLimit(int64(query.Size)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "numeric_id", Value: -1}})
+
+	var docs []*document.AuditLogDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, 0, err
+	}
+
+	return d.mapper.ToEntities(docs), total, nil
+}
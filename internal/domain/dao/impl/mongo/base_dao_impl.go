@@ -8,6 +8,8 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 )
 
 // IDCounter manages auto-incrementing IDs for MongoDB documents.
@@ -32,11 +34,17 @@ func NewIDCounter(db *mongo.Database) *IDCounter {
 
 // NextID returns the next available ID for a given collection.
 func (c *IDCounter) NextID(ctx context.Context, collectionName string) (uint, error) {
+	return c.NextIDs(ctx, collectionName, 1)
+}
+
+// NextIDs reserves n consecutive IDs for a given collection with a single
+// update and returns the first of them.
+func (c *IDCounter) NextIDs(ctx context.Context, collectionName string, n int) (uint, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	filter := bson.M{"_id": collectionName}
-	update := bson.M{"$inc": bson.M{"value": 1}}
+	update := bson.M{"$inc": bson.M{"value": n}}
 	opts := options.FindOneAndUpdate().
 		SetUpsert(true).
 		SetReturnDocument(options.After)
@@ -47,20 +55,25 @@ func (c *IDCounter) NextID(ctx context.Context, collectionName string) (uint, er
 		return 0, err
 	}
 
-	return counter.Value, nil
+	return counter.Value - uint(n) + 1, nil
 }
 
 // baseMongoDAO provides common MongoDB operations for all entity DAOs.
 type baseMongoDAO[T any, D any] struct {
-	collection *mongo.Collection
-	idCounter  *IDCounter
+	collection  *mongo.Collection
+	idCounter   *IDCounter
+	queryFields dao.QueryFields
+	toEntity    func(*D) *T
 }
 
-// newBaseMongoDAO creates a new base MongoDB DAO instance.
-func newBaseMongoDAO[T any, D any](db *mongo.Database, collectionName string, idCounter *IDCounter) *baseMongoDAO[T, D] {
+// newBaseMongoDAO creates a new base MongoDB DAO instance. FindBy may query
+// queryFields and maps the documents it finds with toEntity.
+func newBaseMongoDAO[T any, D any](db *mongo.Database, collectionName string, idCounter *IDCounter, queryFields dao.QueryFields, toEntity func(*D) *T) *baseMongoDAO[T, D] {
 	return &baseMongoDAO[T, D]{
-		collection: db.Collection(collectionName),
-		idCounter:  idCounter,
+		collection:  db.Collection(collectionName),
+		idCounter:   idCounter,
+		queryFields: queryFields,
+		toEntity:    toEntity,
 	}
 }
 
@@ -102,6 +115,81 @@ func (d *baseMongoDAO[T, D]) existsBy(ctx context.Context, field string, value a
 	return count > 0, err
 }
 
+// FindBy retrieves the documents matching query, translated to a filter on
+// the whitelisted fields, excluding soft-deleted documents.
+func (d *baseMongoDAO[T, D]) FindBy(ctx context.Context, query dao.Query) ([]*T, error) {
+	if err := query.Normalize(d.queryFields); err != nil {
+		return nil, err
+	}
+
+	filter := notDeletedFilter()
+	if len(query.Where) > 0 {
+		filter["$and"] = mongoConditions(query.Where)
+	}
+
+	sort := bson.D{}
+	sortedByID := false
+	for _, field := range query.OrderBy {
+		direction := 1
+		if field.Desc {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: mongoField(field.Field), Value: direction})
+		sortedByID = sortedByID || field.Field == "id"
+	}
+	if !sortedByID {
+		sort = append(sort, bson.E{Key: "numeric_id", Value: 1})
+	}
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit))
+
+	var docs []D
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+	entities := make([]*T, len(docs))
+	for i := range docs {
+		entities[i] = d.toEntity(&docs[i])
+	}
+	return entities, nil
+}
+
+// mongoField returns the document field storing a queryable field. Fields
+// share their SQL column names, except the ID.
+func mongoField(field string) string {
+	if field == "id" {
+		return "numeric_id"
+	}
+	return field
+}
+
+// mongoCondition translates a validated condition to a filter.
+func mongoCondition(condition dao.Condition) bson.M {
+	switch {
+	case condition.And != nil:
+		return bson.M{"$and": mongoConditions(condition.And)}
+	case condition.Or != nil:
+		return bson.M{"$or": mongoConditions(condition.Or)}
+	}
+
+	// Operators are named after their MongoDB counterparts
+	var value any = condition.Value
+	if condition.Op == dao.OpIn {
+		value = condition.Values()
+	}
+	return bson.M{mongoField(condition.Field): bson.M{"$" + string(condition.Op): value}}
+}
+
+func mongoConditions(conditions []dao.Condition) bson.A {
+	filters := make(bson.A, len(conditions))
+	for i, condition := range conditions {
+		filters[i] = mongoCondition(condition)
+	}
+	return filters
+}
+
 // findOneByFilter finds a single document matching the filter.
 func (d *baseMongoDAO[T, D]) findOneByFilter(ctx context.Context, filter bson.M, result any) error {
 	return d.collection.FindOne(ctx, filter).Decode(result)
@@ -123,20 +211,64 @@ func (d *baseMongoDAO[T, D]) insertOne(ctx context.Context, doc any) error {
 	return err
 }
 
+// createBatch numbers entities from one block of IDs and inserts them with a
+// single InsertMany. prepare sets an entity's ID and timestamps and maps it to
+// its document. The insert is ordered, so on error the documents before the
+// failing one are kept unless ctx carries a transaction.
+func (d *baseMongoDAO[T, D]) createBatch(ctx context.Context, entities []*T, prepare func(entity *T, id uint) *D) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	firstID, err := d.idCounter.NextIDs(ctx, d.collection.Name(), len(entities))
+	if err != nil {
+		return err
+	}
+
+	docs := make([]any, len(entities))
+	for i, entity := range entities {
+		docs[i] = prepare(entity, firstID+uint(i))
+	}
+	_, err = d.collection.InsertMany(ctx, docs)
+	return err
+}
+
 // updateOne updates a single document matching the filter.
 func (d *baseMongoDAO[T, D]) updateOne(ctx context.Context, filter bson.M, update bson.M) error {
 	_, err := d.collection.UpdateOne(ctx, filter, update)
 	return err
 }
 
+// updateVersioned replaces the fields of the document with the given numeric
+// ID if its lock version still equals version. Documents written before
+// versioning have no lock_version and match version 0.
+func (d *baseMongoDAO[T, D]) updateVersioned(ctx context.Context, id, version uint, doc *D) error {
+	var versionFilter any = version
+	if version == 0 {
+		versionFilter = bson.M{"$in": bson.A{0, nil}}
+	}
+	filter := bson.M{"numeric_id": id, "lock_version": versionFilter}
+	result, err := d.collection.UpdateOne(ctx, filter, bson.M{"$set": doc})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return dao.ErrConcurrentModification
+	}
+	return nil
+}
+
 // updateMany updates all documents matching the filter.
 func (d *baseMongoDAO[T, D]) updateMany(ctx context.Context, filter bson.M, update bson.M) error {
 	_, err := d.collection.UpdateMany(ctx, filter, update)
 	return err
 }
 
-// deleteMany deletes all documents matching the filter.
-func (d *baseMongoDAO[T, D]) deleteMany(ctx context.Context, filter bson.M) error {
-	_, err := d.collection.DeleteMany(ctx, filter)
-	return err
+// deleteMany deletes all documents matching the filter and returns how many
+// were deleted.
+func (d *baseMongoDAO[T, D]) deleteMany(ctx context.Context, filter bson.M) (int64, error) {
+	result, err := d.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
 }
@@ -0,0 +1,32 @@
+package document
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// APIKeyDocument represents an API key in MongoDB.
+type APIKeyDocument struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	NumericID uint          `bson:"numeric_id"` // For compatibility with SQL-based IDs
+	Name      string        `bson:"name"`
+	Prefix    string        `bson:"prefix"`
+	KeyHash   string        `bson:"key_hash"`
+	Scopes    []string      `bson:"scopes"`
+	CreatedBy uint          `bson:"created_by"` // References UserDocument.NumericID
+	ExpiresAt *time.Time    `bson:"expires_at"`
+	RevokedAt *time.Time    `bson:"revoked_at"`
+	CreatedAt time.Time     `bson:"created_at"`
+	DeletedAt *time.Time    `bson:"deleted_at,omitempty"`
+}
+
+// CollectionName returns the MongoDB collection name for API keys.
+func (APIKeyDocument) CollectionName() string {
+	return "api_keys"
+}
+
+// IsDeleted returns true if the document has been soft-deleted.
+func (d *APIKeyDocument) IsDeleted() bool {
+	return d.DeletedAt != nil
+}
@@ -0,0 +1,27 @@
+package document
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AuditLogDocument represents an audit log record in MongoDB.
+type AuditLogDocument struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	NumericID uint          `bson:"numeric_id"` // For compatibility with SQL-based IDs
+	ActorID   uint          `bson:"actor_id"`
+	Actor     string        `bson:"actor,omitempty"`
+	Action    string        `bson:"action"`
+	Target    string        `bson:"target,omitempty"`
+	IPAddress string        `bson:"ip_address,omitempty"`
+	RequestID string        `bson:"request_id,omitempty"`
+	Outcome   string        `bson:"outcome"`
+	Detail    string        `bson:"detail,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+}
+
+// CollectionName returns the MongoDB collection name for audit logs.
+func (AuditLogDocument) CollectionName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,29 @@
+package document
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PasswordResetTokenDocument represents a password reset token in MongoDB.
+type PasswordResetTokenDocument struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	NumericID uint          `bson:"numeric_id"` // For compatibility with SQL-based IDs
+	UserID    uint          `bson:"user_id"`    // References UserDocument.NumericID
+	TokenHash string        `bson:"token_hash"`
+	ExpiresAt time.Time     `bson:"expires_at"`
+	UsedAt    *time.Time    `bson:"used_at"`
+	CreatedAt time.Time     `bson:"created_at"`
+	DeletedAt *time.Time    `bson:"deleted_at,omitempty"`
+}
+
+// CollectionName returns the MongoDB collection name for password reset tokens.
+func (PasswordResetTokenDocument) CollectionName() string {
+	return "password_reset_tokens"
+}
+
+// IsDeleted returns true if the document has been soft-deleted.
+func (d *PasswordResetTokenDocument) IsDeleted() bool {
+	return d.DeletedAt != nil
+}
@@ -8,23 +8,27 @@ import (
 
 // PluginDocument represents a plugin in MongoDB.
 type PluginDocument struct {
-	ID          bson.ObjectID `bson:"_id,omitempty"`
-	NumericID   uint          `bson:"numeric_id"` // For compatibility with SQL-based IDs
-	Key         string        `bson:"key"`
-	Name        string        `bson:"name"`
-	Description string        `bson:"description,omitempty"`
-	Version     string        `bson:"version"`
-	Author      string        `bson:"author,omitempty"`
-	Type        string        `bson:"type"`
-	State       string        `bson:"state"`
-	Config      string        `bson:"config,omitempty"`
-	Checksum    string        `bson:"checksum,omitempty"`
-	Path        string        `bson:"path,omitempty"`
-	InstalledAt time.Time     `bson:"installed_at"`
-	EnabledAt   *time.Time    `bson:"enabled_at,omitempty"`
-	CreatedAt   time.Time     `bson:"created_at"`
-	UpdatedAt   time.Time     `bson:"updated_at"`
-	DeletedAt   *time.Time    `bson:"deleted_at,omitempty"`
+	ID           bson.ObjectID `bson:"_id,omitempty"`
+	NumericID    uint          `bson:"numeric_id"` // For compatibility with SQL-based IDs
+	Key          string        `bson:"key"`
+	Name         string        `bson:"name"`
+	Description  string        `bson:"description,omitempty"`
+	Version      string        `bson:"version"`
+	Author       string        `bson:"author,omitempty"`
+	Type         string        `bson:"type"`
+	State        string        `bson:"state"`
+	Config       string        `bson:"config,omitempty"`
+	ConfigSchema string        `bson:"config_schema,omitempty"`
+	Checksum     string        `bson:"checksum,omitempty"`
+	Path         string        `bson:"path,omitempty"`
+	// VersionHistory is a JSON list of entity.PluginVersion
+	VersionHistory string     `bson:"version_history,omitempty"`
+	InstalledAt    time.Time  `bson:"installed_at"`
+	EnabledAt      *time.Time `bson:"enabled_at,omitempty"`
+	LockVersion    uint       `bson:"lock_version"`
+	CreatedAt      time.Time  `bson:"created_at"`
+	UpdatedAt      time.Time  `bson:"updated_at"`
+	DeletedAt      *time.Time `bson:"deleted_at,omitempty"`
 }
 
 // CollectionName returns the MongoDB collection name for plugins.
@@ -0,0 +1,23 @@
+package document
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PluginStateTransitionDocument represents a plugin state change in MongoDB.
+type PluginStateTransitionDocument struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	NumericID uint          `bson:"numeric_id"` // For compatibility with SQL-based IDs
+	PluginID  uint          `bson:"plugin_id"`  // References PluginDocument.NumericID
+	FromState string        `bson:"from_state,omitempty"`
+	ToState   string        `bson:"to_state"`
+	Reason    string        `bson:"reason,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+}
+
+// CollectionName returns the MongoDB collection name for plugin state transitions.
+func (PluginStateTransitionDocument) CollectionName() string {
+	return "plugin_state_transitions"
+}
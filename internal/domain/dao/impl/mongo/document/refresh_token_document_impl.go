@@ -14,6 +14,9 @@ type RefreshTokenDocument struct {
 	Token     string        `bson:"token"`
 	ExpiresAt time.Time     `bson:"expires_at"`
 	Revoked   bool          `bson:"revoked"`
+	Device    string        `bson:"device,omitempty"`
+	UserAgent string        `bson:"user_agent,omitempty"`
+	IPAddress string        `bson:"ip_address,omitempty"`
 	CreatedAt time.Time     `bson:"created_at"`
 	DeletedAt *time.Time    `bson:"deleted_at,omitempty"`
 }
@@ -11,19 +11,23 @@ import (
 
 // UserDocument represents a user in MongoDB.
 type UserDocument struct {
-	ID         bson.ObjectID `bson:"_id,omitempty"`
-	NumericID  uint          `bson:"numeric_id"` // For compatibility with SQL-based IDs
-	Username   string        `bson:"username"`
-	Email      string        `bson:"email"`
-	Password   string        `bson:"password"`
-	FirstName  string        `bson:"first_name,omitempty"`
-	LastName   string        `bson:"last_name,omitempty"`
-	Role       string        `bson:"role"`
-	IsActive   bool          `bson:"is_active"`
-	IsVerified bool          `bson:"is_verified"`
-	CreatedAt  time.Time     `bson:"created_at"`
-	UpdatedAt  time.Time     `bson:"updated_at"`
-	DeletedAt  *time.Time    `bson:"deleted_at,omitempty"`
+	ID                bson.ObjectID `bson:"_id,omitempty"`
+	NumericID         uint          `bson:"numeric_id"` // For compatibility with SQL-based IDs
+	Username          string        `bson:"username"`
+	Email             string        `bson:"email"`
+	Password          string        `bson:"password"`
+	FirstName         string        `bson:"first_name,omitempty"`
+	LastName          string        `bson:"last_name,omitempty"`
+	Role              string        `bson:"role"`
+	IsActive          bool          `bson:"is_active"`
+	IsVerified        bool          `bson:"is_verified"`
+	TOTPSecret        string        `bson:"totp_secret"`
+	TOTPEnabled       bool          `bson:"totp_enabled"`
+	TOTPRecoveryCodes string        `bson:"totp_recovery_codes"`
+	LockVersion       uint          `bson:"lock_version"`
+	CreatedAt         time.Time     `bson:"created_at"`
+	UpdatedAt         time.Time     `bson:"updated_at"`
+	DeletedAt         *time.Time    `bson:"deleted_at,omitempty"`
 }
 
 // CollectionName returns the MongoDB collection name for users.
@@ -0,0 +1,92 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/document"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// APIKeyMapper converts between APIKey entity and APIKeyDocument.
+type APIKeyMapper struct{}
+
+// NewAPIKeyMapper creates a new APIKeyMapper instance.
+func NewAPIKeyMapper() *APIKeyMapper {
+	return &APIKeyMapper{}
+}
+
+// ToDocument converts an APIKey entity to an APIKeyDocument.
+func (m *APIKeyMapper) ToDocument(key *entity.APIKey) *document.APIKeyDocument {
+	if key == nil {
+		return nil
+	}
+
+	doc := &document.APIKeyDocument{
+		NumericID: key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		Scopes:    key.ScopeList(),
+		CreatedBy: key.CreatedBy,
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+		CreatedAt: key.CreatedAt,
+	}
+
+	if key.DeletedAt.Valid {
+		doc.DeletedAt = &key.DeletedAt.Time
+	}
+
+	return doc
+}
+
+// ToEntity converts an APIKeyDocument to an APIKey entity.
+func (m *APIKeyMapper) ToEntity(doc *document.APIKeyDocument) *entity.APIKey {
+	if doc == nil {
+		return nil
+	}
+
+	key := &entity.APIKey{
+		ID:        doc.NumericID,
+		Name:      doc.Name,
+		Prefix:    doc.Prefix,
+		KeyHash:   doc.KeyHash,
+		CreatedBy: doc.CreatedBy,
+		ExpiresAt: doc.ExpiresAt,
+		RevokedAt: doc.RevokedAt,
+		CreatedAt: doc.CreatedAt,
+	}
+	key.SetScopes(doc.Scopes)
+
+	if doc.DeletedAt != nil {
+		key.DeletedAt = gorm.DeletedAt{Time: *doc.DeletedAt, Valid: true}
+	}
+
+	return key
+}
+
+// ToEntities converts a slice of APIKeyDocument to a slice of APIKey entities.
+func (m *APIKeyMapper) ToEntities(docs []*document.APIKeyDocument) []*entity.APIKey {
+	if docs == nil {
+		return nil
+	}
+
+	keys := make([]*entity.APIKey, len(docs))
+	for i, doc := range docs {
+		keys[i] = m.ToEntity(doc)
+	}
+	return keys
+}
+
+// ToDocuments converts a slice of APIKey entities to a slice of APIKeyDocument.
+func (m *APIKeyMapper) ToDocuments(keys []*entity.APIKey) []*document.APIKeyDocument {
+	if keys == nil {
+		return nil
+	}
+
+	docs := make([]*document.APIKeyDocument, len(keys))
+	for i, key := range keys {
+		docs[i] = m.ToDocument(key)
+	}
+	return docs
+}
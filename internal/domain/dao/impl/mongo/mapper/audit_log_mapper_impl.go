@@ -0,0 +1,67 @@
+package mapper
+
+import (
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/document"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// AuditLogMapper converts between AuditLog entity and AuditLogDocument.
+type AuditLogMapper struct{}
+
+// NewAuditLogMapper creates a new AuditLogMapper instance.
+func NewAuditLogMapper() *AuditLogMapper {
+	return &AuditLogMapper{}
+}
+
+// ToDocument converts an AuditLog entity to an AuditLogDocument.
+func (m *AuditLogMapper) ToDocument(log *entity.AuditLog) *document.AuditLogDocument {
+	if log == nil {
+		return nil
+	}
+
+	return &document.AuditLogDocument{
+		NumericID: log.ID,
+		ActorID:   log.ActorID,
+		Actor:     log.Actor,
+		Action:    log.Action,
+		Target:    log.Target,
+		IPAddress: log.IPAddress,
+		RequestID: log.RequestID,
+		Outcome:   string(log.Outcome),
+		Detail:    log.Detail,
+		CreatedAt: log.CreatedAt,
+	}
+}
+
+// ToEntity converts an AuditLogDocument to an AuditLog entity.
+func (m *AuditLogMapper) ToEntity(doc *document.AuditLogDocument) *entity.AuditLog {
+	if doc == nil {
+		return nil
+	}
+
+	return &entity.AuditLog{
+		ID:        doc.NumericID,
+		ActorID:   doc.ActorID,
+		Actor:     doc.Actor,
+		Action:    doc.Action,
+		Target:    doc.Target,
+		IPAddress: doc.IPAddress,
+		RequestID: doc.RequestID,
+		Outcome:   entity.AuditOutcome(doc.Outcome),
+		Detail:    doc.Detail,
+		CreatedAt: doc.CreatedAt,
+	}
+}
+
+// ToEntities converts a slice of AuditLogDocument to a slice of AuditLog entities.
+func (m *AuditLogMapper) ToEntities(docs []*document.AuditLogDocument) []*entity.AuditLog {
+	if docs == nil {
+		return nil
+	}
+
+	logs := make([]*entity.AuditLog, len(docs))
+	for i, doc := range docs {
+		logs[i] = m.ToEntity(doc)
+	}
+	return logs
+}
@@ -77,6 +77,28 @@ func TestUserMapper_ToDocument(t *testing.T) {
 	})
 }
 
+func TestUserMapper_TOTPRoundTrip(t *testing.T) {
+	mapper := NewUserMapper()
+	user := &entity.User{
+		ID:                1,
+		Username:          "testuser",
+		Email:             testEmail,
+		TOTPSecret:        "encrypted-secret",
+		TOTPEnabled:       true,
+		TOTPRecoveryCodes: "hash1,hash2",
+	}
+
+	doc := mapper.ToDocument(user)
+	assert.Equal(t, "encrypted-secret", doc.TOTPSecret)
+	assert.True(t, doc.TOTPEnabled)
+	assert.Equal(t, "hash1,hash2", doc.TOTPRecoveryCodes)
+
+	back := mapper.ToEntity(doc)
+	assert.Equal(t, user.TOTPSecret, back.TOTPSecret)
+	assert.Equal(t, user.TOTPEnabled, back.TOTPEnabled)
+	assert.Equal(t, user.TOTPRecoveryCodes, back.TOTPRecoveryCodes)
+}
+
 func TestUserMapper_ToEntity(t *testing.T) {
 	mapper := NewUserMapper()
 
@@ -186,6 +208,9 @@ func TestRefreshTokenMapper(t *testing.T) {
 			Token:     testToken,
 			ExpiresAt: now.Add(24 * time.Hour),
 			Revoked:   false,
+			Device:    "iPhone",
+			UserAgent: "Mozilla/5.0 (iPhone)",
+			IPAddress: "203.0.113.7",
 			CreatedAt: now,
 		}
 
@@ -195,6 +220,9 @@ func TestRefreshTokenMapper(t *testing.T) {
 		assert.Equal(t, uint(10), doc.UserID)
 		assert.Equal(t, testToken, doc.Token)
 		assert.False(t, doc.Revoked)
+		assert.Equal(t, "iPhone", doc.Device)
+		assert.Equal(t, "Mozilla/5.0 (iPhone)", doc.UserAgent)
+		assert.Equal(t, "203.0.113.7", mapper.ToEntity(doc).IPAddress)
 	})
 
 	t.Run("ToEntity nil", func(t *testing.T) {
@@ -239,6 +267,121 @@ func TestRefreshTokenMapper(t *testing.T) {
 	})
 }
 
+func TestPasswordResetTokenMapper(t *testing.T) {
+	mapper := NewPasswordResetTokenMapper()
+
+	t.Run("ToDocument nil", func(t *testing.T) {
+		assert.Nil(t, mapper.ToDocument(nil))
+	})
+
+	t.Run("ToDocument valid", func(t *testing.T) {
+		now := time.Now()
+		token := &entity.PasswordResetToken{
+			ID:        1,
+			UserID:    10,
+			TokenHash: testToken,
+			ExpiresAt: now.Add(30 * time.Minute),
+			UsedAt:    &now,
+			CreatedAt: now,
+			DeletedAt: gorm.DeletedAt{Time: now, Valid: true},
+		}
+
+		doc := mapper.ToDocument(token)
+		assert.NotNil(t, doc)
+		assert.Equal(t, uint(1), doc.NumericID)
+		assert.Equal(t, uint(10), doc.UserID)
+		assert.Equal(t, testToken, doc.TokenHash)
+		assert.Equal(t, &now, doc.UsedAt)
+		assert.NotNil(t, doc.DeletedAt)
+	})
+
+	t.Run("ToEntity nil", func(t *testing.T) {
+		assert.Nil(t, mapper.ToEntity(nil))
+	})
+
+	t.Run("ToEntity valid", func(t *testing.T) {
+		now := time.Now()
+		doc := &document.PasswordResetTokenDocument{
+			NumericID: 1,
+			UserID:    10,
+			TokenHash: testToken,
+			ExpiresAt: now.Add(30 * time.Minute),
+			CreatedAt: now,
+		}
+
+		token := mapper.ToEntity(doc)
+		assert.NotNil(t, token)
+		assert.Equal(t, uint(1), token.ID)
+		assert.Equal(t, uint(10), token.UserID)
+		assert.Equal(t, testToken, token.TokenHash)
+		assert.Nil(t, token.UsedAt)
+		assert.True(t, token.IsValid())
+	})
+
+	t.Run("ToEntities", func(t *testing.T) {
+		docs := []*document.PasswordResetTokenDocument{
+			{NumericID: 1, TokenHash: "hash1"},
+			{NumericID: 2, TokenHash: "hash2"},
+		}
+		assert.Len(t, mapper.ToEntities(docs), 2)
+		assert.Nil(t, mapper.ToEntities(nil))
+	})
+
+	t.Run("ToDocuments", func(t *testing.T) {
+		tokens := []*entity.PasswordResetToken{
+			{ID: 1, TokenHash: "hash1"},
+			{ID: 2, TokenHash: "hash2"},
+		}
+		assert.Len(t, mapper.ToDocuments(tokens), 2)
+		assert.Nil(t, mapper.ToDocuments(nil))
+	})
+}
+
+func TestAPIKeyMapper(t *testing.T) {
+	mapper := NewAPIKeyMapper()
+
+	t.Run("ToDocument nil", func(t *testing.T) {
+		assert.Nil(t, mapper.ToDocument(nil))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		now := time.Now()
+		key := &entity.APIKey{
+			ID:        1,
+			Name:      "billing",
+			Prefix:    "ak_abcdefgh",
+			KeyHash:   "hash",
+			CreatedBy: 10,
+			ExpiresAt: &now,
+			CreatedAt: now,
+			DeletedAt: gorm.DeletedAt{Time: now, Valid: true},
+		}
+		key.SetScopes([]string{"jobs:read", "jobs:write"})
+
+		doc := mapper.ToDocument(key)
+		assert.Equal(t, uint(1), doc.NumericID)
+		assert.Equal(t, []string{"jobs:read", "jobs:write"}, doc.Scopes)
+		assert.NotNil(t, doc.DeletedAt)
+
+		back := mapper.ToEntity(doc)
+		assert.Equal(t, key.Scopes, back.Scopes)
+		assert.Equal(t, key.KeyHash, back.KeyHash)
+		assert.Equal(t, key.CreatedBy, back.CreatedBy)
+		assert.True(t, back.DeletedAt.Valid)
+	})
+
+	t.Run("ToEntity nil", func(t *testing.T) {
+		assert.Nil(t, mapper.ToEntity(nil))
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		assert.Len(t, mapper.ToEntities([]*document.APIKeyDocument{{NumericID: 1}, {NumericID: 2}}), 2)
+		assert.Nil(t, mapper.ToEntities(nil))
+		assert.Len(t, mapper.ToDocuments([]*entity.APIKey{{ID: 1}, {ID: 2}}), 2)
+		assert.Nil(t, mapper.ToDocuments(nil))
+	})
+}
+
 func TestPluginMapper(t *testing.T) {
 	mapper := NewPluginMapper()
 
@@ -406,3 +549,76 @@ func TestPluginExtensionMapper(t *testing.T) {
 		assert.Len(t, docs, 2)
 	})
 }
+
+func TestPluginStateTransitionMapper(t *testing.T) {
+	mapper := NewPluginStateTransitionMapper()
+
+	t.Run("ToDocument nil", func(t *testing.T) {
+		assert.Nil(t, mapper.ToDocument(nil))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		transition := &entity.PluginStateTransition{
+			ID:        3,
+			PluginID:  7,
+			FromState: entity.PluginStateEnabled,
+			ToState:   entity.PluginStateError,
+			Reason:    "OnDisable hook failed",
+			CreatedAt: time.Now(),
+		}
+
+		doc := mapper.ToDocument(transition)
+		assert.Equal(t, uint(3), doc.NumericID)
+		assert.Equal(t, "ENABLED", doc.FromState)
+		assert.Equal(t, "ERROR", doc.ToState)
+
+		assert.Equal(t, transition, mapper.ToEntity(doc))
+	})
+
+	t.Run("ToEntity nil", func(t *testing.T) {
+		assert.Nil(t, mapper.ToEntity(nil))
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		assert.Len(t, mapper.ToEntities([]*document.PluginStateTransitionDocument{{NumericID: 1}, {NumericID: 2}}), 2)
+		assert.Nil(t, mapper.ToEntities(nil))
+	})
+}
+
+func TestAuditLogMapper(t *testing.T) {
+	mapper := NewAuditLogMapper()
+
+	t.Run("ToDocument nil", func(t *testing.T) {
+		assert.Nil(t, mapper.ToDocument(nil))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		log := &entity.AuditLog{
+			ID:        5,
+			ActorID:   1,
+			Actor:     "admin",
+			Action:    entity.AuditActionPluginUninstall,
+			Target:    "hello-plugin",
+			IPAddress: "10.0.0.1",
+			RequestID: "req-1",
+			Outcome:   entity.AuditOutcomeFailure,
+			Detail:    "plugin not found",
+			CreatedAt: time.Now(),
+		}
+
+		doc := mapper.ToDocument(log)
+		assert.Equal(t, uint(5), doc.NumericID)
+		assert.Equal(t, "FAILURE", doc.Outcome)
+
+		assert.Equal(t, log, mapper.ToEntity(doc))
+	})
+
+	t.Run("ToEntity nil", func(t *testing.T) {
+		assert.Nil(t, mapper.ToEntity(nil))
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		assert.Len(t, mapper.ToEntities([]*document.AuditLogDocument{{NumericID: 1}, {NumericID: 2}}), 2)
+		assert.Nil(t, mapper.ToEntities(nil))
+	})
+}
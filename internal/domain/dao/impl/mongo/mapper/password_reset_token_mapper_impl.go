@@ -0,0 +1,86 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/document"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// PasswordResetTokenMapper converts between PasswordResetToken entity and PasswordResetTokenDocument.
+type PasswordResetTokenMapper struct{}
+
+// NewPasswordResetTokenMapper creates a new PasswordResetTokenMapper instance.
+func NewPasswordResetTokenMapper() *PasswordResetTokenMapper {
+	return &PasswordResetTokenMapper{}
+}
+
+// ToDocument converts a PasswordResetToken entity to a PasswordResetTokenDocument.
+func (m *PasswordResetTokenMapper) ToDocument(token *entity.PasswordResetToken) *document.PasswordResetTokenDocument {
+	if token == nil {
+		return nil
+	}
+
+	doc := &document.PasswordResetTokenDocument{
+		NumericID: token.ID,
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		UsedAt:    token.UsedAt,
+		CreatedAt: token.CreatedAt,
+	}
+
+	if token.DeletedAt.Valid {
+		doc.DeletedAt = &token.DeletedAt.Time
+	}
+
+	return doc
+}
+
+// ToEntity converts a PasswordResetTokenDocument to a PasswordResetToken entity.
+func (m *PasswordResetTokenMapper) ToEntity(doc *document.PasswordResetTokenDocument) *entity.PasswordResetToken {
+	if doc == nil {
+		return nil
+	}
+
+	token := &entity.PasswordResetToken{
+		ID:        doc.NumericID,
+		UserID:    doc.UserID,
+		TokenHash: doc.TokenHash,
+		ExpiresAt: doc.ExpiresAt,
+		UsedAt:    doc.UsedAt,
+		CreatedAt: doc.CreatedAt,
+	}
+
+	if doc.DeletedAt != nil {
+		token.DeletedAt = gorm.DeletedAt{Time: *doc.DeletedAt, Valid: true}
+	}
+
+	return token
+}
+
+// ToEntities converts a slice of PasswordResetTokenDocument to a slice of PasswordResetToken entities.
+func (m *PasswordResetTokenMapper) ToEntities(docs []*document.PasswordResetTokenDocument) []*entity.PasswordResetToken {
+	if docs == nil {
+		return nil
+	}
+
+	tokens := make([]*entity.PasswordResetToken, len(docs))
+	for i, doc := range docs {
+		tokens[i] = m.ToEntity(doc)
+	}
+	return tokens
+}
+
+// ToDocuments converts a slice of PasswordResetToken entities to a slice of PasswordResetTokenDocument.
+func (m *PasswordResetTokenMapper) ToDocuments(tokens []*entity.PasswordResetToken) []*document.PasswordResetTokenDocument {
+	if tokens == nil {
+		return nil
+	}
+
+	docs := make([]*document.PasswordResetTokenDocument, len(tokens))
+	for i, token := range tokens {
+		docs[i] = m.ToDocument(token)
+	}
+	return docs
+}
@@ -22,21 +22,24 @@ func (m *PluginMapper) ToDocument(plugin *entity.Plugin) *document.PluginDocumen
 	}
 
 	doc := &document.PluginDocument{
-		NumericID:   plugin.ID,
-		Key:         plugin.Key,
-		Name:        plugin.Name,
-		Description: plugin.Description,
-		Version:     plugin.Version,
-		Author:      plugin.Author,
-		Type:        string(plugin.Type),
-		State:       string(plugin.State),
-		Config:      plugin.Config,
-		Checksum:    plugin.Checksum,
-		Path:        plugin.Path,
-		InstalledAt: plugin.InstalledAt,
-		EnabledAt:   plugin.EnabledAt,
-		CreatedAt:   plugin.CreatedAt,
-		UpdatedAt:   plugin.UpdatedAt,
+		NumericID:      plugin.ID,
+		Key:            plugin.Key,
+		Name:           plugin.Name,
+		Description:    plugin.Description,
+		Version:        plugin.Version,
+		Author:         plugin.Author,
+		Type:           string(plugin.Type),
+		State:          string(plugin.State),
+		Config:         plugin.Config,
+		ConfigSchema:   plugin.ConfigSchema,
+		Checksum:       plugin.Checksum,
+		Path:           plugin.Path,
+		VersionHistory: plugin.VersionHistory,
+		InstalledAt:    plugin.InstalledAt,
+		EnabledAt:      plugin.EnabledAt,
+		LockVersion:    plugin.LockVersion,
+		CreatedAt:      plugin.CreatedAt,
+		UpdatedAt:      plugin.UpdatedAt,
 	}
 
 	if plugin.DeletedAt.Valid {
@@ -53,21 +56,24 @@ func (m *PluginMapper) ToEntity(doc *document.PluginDocument) *entity.Plugin {
 	}
 
 	plugin := &entity.Plugin{
-		ID:          doc.NumericID,
-		Key:         doc.Key,
-		Name:        doc.Name,
-		Description: doc.Description,
-		Version:     doc.Version,
-		Author:      doc.Author,
-		Type:        entity.PluginType(doc.Type),
-		State:       entity.PluginState(doc.State),
-		Config:      doc.Config,
-		Checksum:    doc.Checksum,
-		Path:        doc.Path,
-		InstalledAt: doc.InstalledAt,
-		EnabledAt:   doc.EnabledAt,
-		CreatedAt:   doc.CreatedAt,
-		UpdatedAt:   doc.UpdatedAt,
+		ID:             doc.NumericID,
+		Key:            doc.Key,
+		Name:           doc.Name,
+		Description:    doc.Description,
+		Version:        doc.Version,
+		Author:         doc.Author,
+		Type:           entity.PluginType(doc.Type),
+		State:          entity.PluginState(doc.State),
+		Config:         doc.Config,
+		ConfigSchema:   doc.ConfigSchema,
+		Checksum:       doc.Checksum,
+		Path:           doc.Path,
+		VersionHistory: doc.VersionHistory,
+		InstalledAt:    doc.InstalledAt,
+		EnabledAt:      doc.EnabledAt,
+		LockVersion:    doc.LockVersion,
+		CreatedAt:      doc.CreatedAt,
+		UpdatedAt:      doc.UpdatedAt,
 	}
 
 	if doc.DeletedAt != nil {
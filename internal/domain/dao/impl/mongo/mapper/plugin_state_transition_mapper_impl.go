@@ -0,0 +1,59 @@
+package mapper
+
+import (
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/document"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// PluginStateTransitionMapper converts between PluginStateTransition entity and PluginStateTransitionDocument.
+type PluginStateTransitionMapper struct{}
+
+// NewPluginStateTransitionMapper creates a new PluginStateTransitionMapper instance.
+func NewPluginStateTransitionMapper() *PluginStateTransitionMapper {
+	return &PluginStateTransitionMapper{}
+}
+
+// ToDocument converts a PluginStateTransition entity to a PluginStateTransitionDocument.
+func (m *PluginStateTransitionMapper) ToDocument(transition *entity.PluginStateTransition) *document.PluginStateTransitionDocument {
+	if transition == nil {
+		return nil
+	}
+
+	return &document.PluginStateTransitionDocument{
+		NumericID: transition.ID,
+		PluginID:  transition.PluginID,
+		FromState: string(transition.FromState),
+		ToState:   string(transition.ToState),
+		Reason:    transition.Reason,
+		CreatedAt: transition.CreatedAt,
+	}
+}
+
+// ToEntity converts a PluginStateTransitionDocument to a PluginStateTransition entity.
+func (m *PluginStateTransitionMapper) ToEntity(doc *document.PluginStateTransitionDocument) *entity.PluginStateTransition {
+	if doc == nil {
+		return nil
+	}
+
+	return &entity.PluginStateTransition{
+		ID:        doc.NumericID,
+		PluginID:  doc.PluginID,
+		FromState: entity.PluginState(doc.FromState),
+		ToState:   entity.PluginState(doc.ToState),
+		Reason:    doc.Reason,
+		CreatedAt: doc.CreatedAt,
+	}
+}
+
+// ToEntities converts a slice of PluginStateTransitionDocument to a slice of PluginStateTransition entities.
+func (m *PluginStateTransitionMapper) ToEntities(docs []*document.PluginStateTransitionDocument) []*entity.PluginStateTransition {
+	if docs == nil {
+		return nil
+	}
+
+	transitions := make([]*entity.PluginStateTransition, len(docs))
+	for i, doc := range docs {
+		transitions[i] = m.ToEntity(doc)
+	}
+	return transitions
+}
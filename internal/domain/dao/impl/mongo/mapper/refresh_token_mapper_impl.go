@@ -27,6 +27,9 @@ func (m *RefreshTokenMapper) ToDocument(token *entity.RefreshToken) *document.Re
 		Token:     token.Token,
 		ExpiresAt: token.ExpiresAt,
 		Revoked:   token.Revoked,
+		Device:    token.Device,
+		UserAgent: token.UserAgent,
+		IPAddress: token.IPAddress,
 		CreatedAt: token.CreatedAt,
 	}
 
@@ -50,6 +53,9 @@ func (m *RefreshTokenMapper) ToEntity(doc *document.RefreshTokenDocument) *entit
 		Token:     doc.Token,
 		ExpiresAt: doc.ExpiresAt,
 		Revoked:   doc.Revoked,
+		Device:    doc.Device,
+		UserAgent: doc.UserAgent,
+		IPAddress: doc.IPAddress,
 		CreatedAt: doc.CreatedAt,
 	}
 
@@ -23,17 +23,21 @@ func (m *UserMapper) ToDocument(user *entity.User) *document.UserDocument {
 	}
 
 	doc := &document.UserDocument{
-		NumericID:  user.ID,
-		Username:   user.Username,
-		Email:      user.Email,
-		Password:   user.Password,
-		FirstName:  user.FirstName,
-		LastName:   user.LastName,
-		Role:       string(user.Role),
-		IsActive:   user.IsActive,
-		IsVerified: user.IsVerified,
-		CreatedAt:  user.CreatedAt,
-		UpdatedAt:  user.UpdatedAt,
+		NumericID:         user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		Password:          user.Password,
+		FirstName:         user.FirstName,
+		LastName:          user.LastName,
+		Role:              string(user.Role),
+		IsActive:          user.IsActive,
+		IsVerified:        user.IsVerified,
+		TOTPSecret:        user.TOTPSecret,
+		TOTPEnabled:       user.TOTPEnabled,
+		TOTPRecoveryCodes: user.TOTPRecoveryCodes,
+		LockVersion:       user.LockVersion,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
 	}
 
 	if user.DeletedAt.Valid {
@@ -50,17 +54,21 @@ func (m *UserMapper) ToEntity(doc *document.UserDocument) *entity.User {
 	}
 
 	user := &entity.User{
-		ID:         doc.NumericID,
-		Username:   doc.Username,
-		Email:      doc.Email,
-		Password:   doc.Password,
-		FirstName:  doc.FirstName,
-		LastName:   doc.LastName,
-		Role:       entity.UserRole(doc.Role),
-		IsActive:   doc.IsActive,
-		IsVerified: doc.IsVerified,
-		CreatedAt:  doc.CreatedAt,
-		UpdatedAt:  doc.UpdatedAt,
+		ID:                doc.NumericID,
+		Username:          doc.Username,
+		Email:             doc.Email,
+		Password:          doc.Password,
+		FirstName:         doc.FirstName,
+		LastName:          doc.LastName,
+		Role:              entity.UserRole(doc.Role),
+		IsActive:          doc.IsActive,
+		IsVerified:        doc.IsVerified,
+		TOTPSecret:        doc.TOTPSecret,
+		TOTPEnabled:       doc.TOTPEnabled,
+		TOTPRecoveryCodes: doc.TOTPRecoveryCodes,
+		LockVersion:       doc.LockVersion,
+		CreatedAt:         doc.CreatedAt,
+		UpdatedAt:         doc.UpdatedAt,
 	}
 
 	if doc.DeletedAt != nil {
@@ -0,0 +1,161 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/document"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/mapper"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// passwordResetTokenDAO implements dao.PasswordResetTokenDAO using MongoDB.
+type passwordResetTokenDAO struct {
+	*baseMongoDAO[entity.PasswordResetToken, document.PasswordResetTokenDocument]
+	mapper *mapper.PasswordResetTokenMapper
+}
+
+// NewPasswordResetTokenDAO creates a new MongoDB-based PasswordResetTokenDAO.
+func NewPasswordResetTokenDAO(db *mongo.Database, idCounter *IDCounter) dao.PasswordResetTokenDAO {
+	m := mapper.NewPasswordResetTokenMapper()
+	return &passwordResetTokenDAO{
+		baseMongoDAO: newBaseMongoDAO[entity.PasswordResetToken, document.PasswordResetTokenDocument](
+			db,
+			document.PasswordResetTokenDocument{}.CollectionName(),
+			idCounter,
+			dao.PasswordResetTokenQueryFields,
+			m.ToEntity,
+		),
+		mapper: m,
+	}
+}
+
+// Create inserts a new reset token into MongoDB.
+func (d *passwordResetTokenDAO) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	// Generate numeric ID for compatibility
+	id, err := d.nextID(ctx)
+	if err != nil {
+		return err
+	}
+	token.ID = id
+	token.CreatedAt = time.Now()
+
+	doc := d.mapper.ToDocument(token)
+	return d.insertOne(ctx, doc)
+}
+
+// CreateBatch inserts reset tokens into MongoDB at once, numbered from a single
+// block of IDs.
+func (d *passwordResetTokenDAO) CreateBatch(ctx context.Context, tokens []*entity.PasswordResetToken) error {
+	now := time.Now()
+	return d.createBatch(ctx, tokens, func(token *entity.PasswordResetToken, id uint) *document.PasswordResetTokenDocument {
+		token.ID = id
+		token.CreatedAt = now
+		return d.mapper.ToDocument(token)
+	})
+}
+
+// FindByID retrieves a reset token by its numeric ID.
+func (d *passwordResetTokenDAO) FindByID(ctx context.Context, id uint) (*entity.PasswordResetToken, error) {
+	return d.findOne(ctx, withNotDeleted(bson.M{"numeric_id": id}))
+}
+
+// Update modifies an existing reset token in MongoDB.
+func (d *passwordResetTokenDAO) Update(ctx context.Context, token *entity.PasswordResetToken) error {
+	doc := d.mapper.ToDocument(token)
+
+	filter := bson.M{"numeric_id": token.ID}
+	update := bson.M{"$set": doc}
+	return d.updateOne(ctx, filter, update)
+}
+
+// Delete performs a soft delete on a reset token.
+func (d *passwordResetTokenDAO) Delete(ctx context.Context, id uint) error {
+	now := time.Now()
+	filter := bson.M{"numeric_id": id}
+	update := bson.M{"$set": bson.M{"deleted_at": now}}
+	return d.updateOne(ctx, filter, update)
+}
+
+// FindAll retrieves reset tokens with pagination.
+func (d *passwordResetTokenDAO) FindAll(ctx context.Context, page, size int) ([]*entity.PasswordResetToken, int64, error) {
+	filter := notDeletedFilter()
+
+	total, err := d.count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((page - 1) * size)
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(int64(size)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var docs []*document.PasswordResetTokenDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, 0, err
+	}
+
+	return d.mapper.ToEntities(docs), total, nil
+}
+
+// Count returns the total number of reset tokens.
+func (d *passwordResetTokenDAO) Count(ctx context.Context) (int64, error) {
+	return d.count(ctx, notDeletedFilter())
+}
+
+// ExistsBy checks if a reset token exists by a field value.
+func (d *passwordResetTokenDAO) ExistsBy(ctx context.Context, field string, value any) (bool, error) {
+	return d.existsBy(ctx, field, value)
+}
+
+// FindByTokenHash retrieves a reset token by the hash of its value.
+func (d *passwordResetTokenDAO) FindByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	return d.findOne(ctx, withNotDeleted(bson.M{"token_hash": tokenHash}))
+}
+
+// MarkUsed marks a reset token as used if it has not been used yet.
+func (d *passwordResetTokenDAO) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	filter := bson.M{"numeric_id": id, "used_at": nil}
+	update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+	result, err := d.getCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// InvalidateAllByUserID marks all unused reset tokens for a user as used.
+func (d *passwordResetTokenDAO) InvalidateAllByUserID(ctx context.Context, userID uint) error {
+	filter := bson.M{"user_id": userID, "used_at": nil}
+	update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+	return d.updateMany(ctx, filter, update)
+}
+
+// DeleteExpired permanently removes tokens that expired before the given time.
+func (d *passwordResetTokenDAO) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return d.deleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
+}
+
+// CountExpired counts the tokens that expired before the given time.
+func (d *passwordResetTokenDAO) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	return d.count(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
+}
+
+func (d *passwordResetTokenDAO) findOne(ctx context.Context, filter bson.M) (*entity.PasswordResetToken, error) {
+	var doc document.PasswordResetTokenDocument
+	err := d.findOneByFilter(ctx, filter, &doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d.mapper.ToEntity(&doc), nil
+}
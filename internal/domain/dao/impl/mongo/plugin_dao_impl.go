@@ -22,13 +22,16 @@ type pluginDAO struct {
 
 // NewPluginDAO creates a new MongoDB-based PluginDAO.
 func NewPluginDAO(db *mongo.Database, idCounter *IDCounter) dao.PluginDAO {
+	m := mapper.NewPluginMapper()
 	return &pluginDAO{
 		baseMongoDAO: newBaseMongoDAO[entity.Plugin, document.PluginDocument](
 			db,
 			document.PluginDocument{}.CollectionName(),
 			idCounter,
+			dao.PluginQueryFields,
+			m.ToEntity,
 		),
-		mapper: mapper.NewPluginMapper(),
+		mapper: m,
 	}
 }
 
@@ -47,6 +50,18 @@ func (d *pluginDAO) Create(ctx context.Context, plugin *entity.Plugin) error {
 	return d.insertOne(ctx, doc)
 }
 
+// CreateBatch inserts plugins into MongoDB at once, numbered from a single
+// block of IDs.
+func (d *pluginDAO) CreateBatch(ctx context.Context, plugins []*entity.Plugin) error {
+	now := time.Now()
+	return d.createBatch(ctx, plugins, func(plugin *entity.Plugin, id uint) *document.PluginDocument {
+		plugin.ID = id
+		plugin.CreatedAt = now
+		plugin.UpdatedAt = now
+		return d.mapper.ToDocument(plugin)
+	})
+}
+
 // FindByID retrieves a plugin by its numeric ID.
 func (d *pluginDAO) FindByID(ctx context.Context, id uint) (*entity.Plugin, error) {
 	filter := withNotDeleted(bson.M{"numeric_id": id})
@@ -62,14 +77,19 @@ func (d *pluginDAO) FindByID(ctx context.Context, id uint) (*entity.Plugin, erro
 	return d.mapper.ToEntity(&doc), nil
 }
 
-// Update modifies an existing plugin in MongoDB.
+// Update modifies an existing plugin in MongoDB if it has not changed since it
+// was read, bumping its lock version.
 func (d *pluginDAO) Update(ctx context.Context, plugin *entity.Plugin) error {
+	current := plugin.LockVersion
+	plugin.LockVersion = current + 1
 	plugin.UpdatedAt = time.Now()
 	doc := d.mapper.ToDocument(plugin)
 
-	filter := bson.M{"numeric_id": plugin.ID}
-	update := bson.M{"$set": doc}
-	return d.updateOne(ctx, filter, update)
+	if err := d.updateVersioned(ctx, plugin.ID, current, doc); err != nil {
+		plugin.LockVersion = current
+		return err
+	}
+	return nil
 }
 
 // Delete performs a soft delete on a plugin.
@@ -103,6 +123,21 @@ func (d *pluginDAO) FindAll(ctx context.Context, page, size int) ([]*entity.Plug
 	return d.mapper.ToEntities(docs), total, nil
 }
 
+// FindAllCursor retrieves up to limit plugins after afterID, ordered by ID ascending.
+func (d *pluginDAO) FindAllCursor(ctx context.Context, afterID uint, limit int) (*dao.CursorResult[entity.Plugin], error) {
+	filter := withNotDeleted(bson.M{"numeric_id": bson.M{"$gt": afterID}})
+	opts := options.Find().
+		SetLimit(int64(limit + 1)).
+		SetSort(bson.D{{Key: "numeric_id", Value: 1}})
+
+	var docs []*document.PluginDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+
+	return dao.NewCursorResult(d.mapper.ToEntities(docs), limit, func(e *entity.Plugin) uint { return e.ID }), nil
+}
+
 // Count returns the total number of plugins.
 func (d *pluginDAO) Count(ctx context.Context) (int64, error) {
 	return d.count(ctx, notDeletedFilter())
@@ -174,6 +209,37 @@ func (d *pluginDAO) UpdateState(ctx context.Context, id uint, state entity.Plugi
 	}
 
 	filter := bson.M{"numeric_id": id}
-	update := bson.M{"$set": updates}
+	update := bson.M{"$set": updates, "$inc": bson.M{"lock_version": 1}}
 	return d.updateOne(ctx, filter, update)
 }
+
+// PurgeDeleted permanently removes the plugins soft-deleted before the given
+// time, with their extensions and state history. Children are removed first,
+// so an interrupted purge leaves no orphans and is finished by the next one.
+func (d *pluginDAO) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var ids []uint
+	filter := bson.M{"deleted_at": bson.M{"$lt": before}}
+	if err := d.collection.Distinct(ctx, "numeric_id", filter).Decode(&ids); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	db := d.collection.Database()
+	children := bson.M{"plugin_id": bson.M{"$in": ids}}
+	for _, collection := range []string{
+		document.PluginExtensionDocument{}.CollectionName(),
+		document.PluginStateTransitionDocument{}.CollectionName(),
+	} {
+		if _, err := db.Collection(collection).DeleteMany(ctx, children); err != nil {
+			return 0, err
+		}
+	}
+	return d.deleteMany(ctx, bson.M{"numeric_id": bson.M{"$in": ids}})
+}
+
+// CountDeleted counts the plugins soft-deleted before the given time.
+func (d *pluginDAO) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return d.count(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+}
@@ -22,13 +22,16 @@ type pluginExtensionDAO struct {
 
 // NewPluginExtensionDAO creates a new MongoDB-based PluginExtensionDAO.
 func NewPluginExtensionDAO(db *mongo.Database, idCounter *IDCounter) dao.PluginExtensionDAO {
+	m := mapper.NewPluginExtensionMapper()
 	return &pluginExtensionDAO{
 		baseMongoDAO: newBaseMongoDAO[entity.PluginExtension, document.PluginExtensionDocument](
 			db,
 			document.PluginExtensionDocument{}.CollectionName(),
 			idCounter,
+			dao.PluginExtensionQueryFields,
+			m.ToEntity,
 		),
-		mapper: mapper.NewPluginExtensionMapper(),
+		mapper: m,
 	}
 }
 
@@ -46,6 +49,17 @@ func (d *pluginExtensionDAO) Create(ctx context.Context, ext *entity.PluginExten
 	return d.insertOne(ctx, doc)
 }
 
+// CreateBatch inserts plugin extensions into MongoDB at once, numbered from a single
+// block of IDs.
+func (d *pluginExtensionDAO) CreateBatch(ctx context.Context, exts []*entity.PluginExtension) error {
+	now := time.Now()
+	return d.createBatch(ctx, exts, func(ext *entity.PluginExtension, id uint) *document.PluginExtensionDocument {
+		ext.ID = id
+		ext.CreatedAt = now
+		return d.mapper.ToDocument(ext)
+	})
+}
+
 // FindByID retrieves a plugin extension by its numeric ID.
 func (d *pluginExtensionDAO) FindByID(ctx context.Context, id uint) (*entity.PluginExtension, error) {
 	filter := withNotDeleted(bson.M{"numeric_id": id})
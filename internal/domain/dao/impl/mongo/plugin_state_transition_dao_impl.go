@@ -0,0 +1,137 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/document"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/mongo/mapper"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// pluginStateTransitionDAO implements dao.PluginStateTransitionDAO using MongoDB.
+type pluginStateTransitionDAO struct {
+	*baseMongoDAO[entity.PluginStateTransition, document.PluginStateTransitionDocument]
+	mapper *mapper.PluginStateTransitionMapper
+}
+
+// NewPluginStateTransitionDAO creates a new MongoDB-based PluginStateTransitionDAO.
+func NewPluginStateTransitionDAO(db *mongo.Database, idCounter *IDCounter) dao.PluginStateTransitionDAO {
+	m := mapper.NewPluginStateTransitionMapper()
+	return &pluginStateTransitionDAO{
+		baseMongoDAO: newBaseMongoDAO[entity.PluginStateTransition, document.PluginStateTransitionDocument](
+			db,
+			document.PluginStateTransitionDocument{}.CollectionName(),
+			idCounter,
+			dao.PluginStateTransitionQueryFields,
+			m.ToEntity,
+		),
+		mapper: m,
+	}
+}
+
+// Create inserts a new state transition into MongoDB.
+func (d *pluginStateTransitionDAO) Create(ctx context.Context, transition *entity.PluginStateTransition) error {
+	// Generate numeric ID for compatibility
+	id, err := d.nextID(ctx)
+	if err != nil {
+		return err
+	}
+	transition.ID = id
+	transition.CreatedAt = time.Now()
+
+	doc := d.mapper.ToDocument(transition)
+	return d.insertOne(ctx, doc)
+}
+
+// CreateBatch inserts state transitions into MongoDB at once, numbered from a single
+// block of IDs.
+func (d *pluginStateTransitionDAO) CreateBatch(ctx context.Context, transitions []*entity.PluginStateTransition) error {
+	now := time.Now()
+	return d.createBatch(ctx, transitions, func(transition *entity.PluginStateTransition, id uint) *document.PluginStateTransitionDocument {
+		transition.ID = id
+		transition.CreatedAt = now
+		return d.mapper.ToDocument(transition)
+	})
+}
+
+// FindByID retrieves a state transition by its numeric ID.
+func (d *pluginStateTransitionDAO) FindByID(ctx context.Context, id uint) (*entity.PluginStateTransition, error) {
+	var doc document.PluginStateTransitionDocument
+	err := d.findOneByFilter(ctx, bson.M{"numeric_id": id}, &doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d.mapper.ToEntity(&doc), nil
+}
+
+// Update modifies an existing state transition in MongoDB.
+func (d *pluginStateTransitionDAO) Update(ctx context.Context, transition *entity.PluginStateTransition) error {
+	doc := d.mapper.ToDocument(transition)
+
+	filter := bson.M{"numeric_id": transition.ID}
+	update := bson.M{"$set": doc}
+	return d.updateOne(ctx, filter, update)
+}
+
+// Delete removes a state transition. Transitions are history, so there is
+// nothing to soft-delete.
+func (d *pluginStateTransitionDAO) Delete(ctx context.Context, id uint) error {
+	_, err := d.deleteMany(ctx, bson.M{"numeric_id": id})
+	return err
+}
+
+// FindAll retrieves state transitions with pagination.
+func (d *pluginStateTransitionDAO) FindAll(ctx context.Context, page, size int) ([]*entity.PluginStateTransition, int64, error) {
+	filter := bson.M{}
+
+	total, err := d.count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((page - 1) * size)
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(int64(size)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var docs []*document.PluginStateTransitionDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, 0, err
+	}
+
+	return d.mapper.ToEntities(docs), total, nil
+}
+
+// Count returns the total number of state transitions.
+func (d *pluginStateTransitionDAO) Count(ctx context.Context) (int64, error) {
+	return d.count(ctx, bson.M{})
+}
+
+// ExistsBy checks if a state transition exists by a field value.
+func (d *pluginStateTransitionDAO) ExistsBy(ctx context.Context, field string, value any) (bool, error) {
+	return d.existsBy(ctx, field, value)
+}
+
+// FindByPluginID retrieves the state transitions of a plugin, oldest first.
+func (d *pluginStateTransitionDAO) FindByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginStateTransition, error) {
+	filter := bson.M{"plugin_id": pluginID}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "numeric_id", Value: 1}})
+
+	var docs []*document.PluginStateTransitionDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+
+	return d.mapper.ToEntities(docs), nil
+}
@@ -23,13 +23,16 @@ type refreshTokenDAO struct {
 
 // NewRefreshTokenDAO creates a new MongoDB-based RefreshTokenDAO.
 func NewRefreshTokenDAO(db *mongo.Database, idCounter *IDCounter, userDAO dao.UserDAO) dao.RefreshTokenDAO {
+	m := mapper.NewRefreshTokenMapper()
 	return &refreshTokenDAO{
 		baseMongoDAO: newBaseMongoDAO[entity.RefreshToken, document.RefreshTokenDocument](
 			db,
 			document.RefreshTokenDocument{}.CollectionName(),
 			idCounter,
+			dao.RefreshTokenQueryFields,
+			m.ToEntity,
 		),
-		mapper:  mapper.NewRefreshTokenMapper(),
+		mapper:  m,
 		userDAO: userDAO,
 	}
 }
@@ -48,6 +51,17 @@ func (d *refreshTokenDAO) Create(ctx context.Context, token *entity.RefreshToken
 	return d.insertOne(ctx, doc)
 }
 
+// CreateBatch inserts refresh tokens into MongoDB at once, numbered from a single
+// block of IDs.
+func (d *refreshTokenDAO) CreateBatch(ctx context.Context, tokens []*entity.RefreshToken) error {
+	now := time.Now()
+	return d.createBatch(ctx, tokens, func(token *entity.RefreshToken, id uint) *document.RefreshTokenDocument {
+		token.ID = id
+		token.CreatedAt = now
+		return d.mapper.ToDocument(token)
+	})
+}
+
 // FindByID retrieves a refresh token by its numeric ID.
 func (d *refreshTokenDAO) FindByID(ctx context.Context, id uint) (*entity.RefreshToken, error) {
 	filter := withNotDeleted(bson.M{"numeric_id": id})
@@ -162,6 +176,33 @@ func (d *refreshTokenDAO) RevokeByToken(ctx context.Context, token string) error
 	return d.updateOne(ctx, filter, update)
 }
 
+// FindActiveByUserID retrieves the user's non-revoked, unexpired refresh tokens, newest first.
+func (d *refreshTokenDAO) FindActiveByUserID(ctx context.Context, userID uint) ([]*entity.RefreshToken, error) {
+	filter := withNotDeleted(bson.M{
+		"user_id":    userID,
+		"revoked":    false,
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	var docs []*document.RefreshTokenDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+	return d.mapper.ToEntities(docs), nil
+}
+
+// RevokeByID revokes a refresh token if it belongs to the user.
+func (d *refreshTokenDAO) RevokeByID(ctx context.Context, userID, id uint) (bool, error) {
+	filter := withNotDeleted(bson.M{"numeric_id": id, "user_id": userID, "revoked": false})
+	update := bson.M{"$set": bson.M{"revoked": true}}
+	result, err := d.getCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
 // RevokeAllByUserID revokes all refresh tokens for a specific user.
 func (d *refreshTokenDAO) RevokeAllByUserID(ctx context.Context, userID uint) error {
 	filter := bson.M{"user_id": userID}
@@ -169,9 +210,12 @@ func (d *refreshTokenDAO) RevokeAllByUserID(ctx context.Context, userID uint) er
 	return d.updateMany(ctx, filter, update)
 }
 
-// DeleteExpired removes all expired tokens from the database.
-func (d *refreshTokenDAO) DeleteExpired(ctx context.Context) error {
-	now := time.Now()
-	filter := bson.M{"expires_at": bson.M{"$lt": now}}
-	return d.deleteMany(ctx, filter)
+// DeleteExpired permanently removes tokens that expired before the given time.
+func (d *refreshTokenDAO) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return d.deleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
+}
+
+// CountExpired counts the tokens that expired before the given time.
+func (d *refreshTokenDAO) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	return d.count(ctx, bson.M{"expires_at": bson.M{"$lt": before}})
 }
@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+)
+
+// txManager implements dao.TxManager using MongoDB sessions.
+type txManager struct {
+	client *mongo.Client
+
+	mu        sync.Mutex
+	checked   bool
+	supported bool
+}
+
+// NewTxManager creates a new MongoDB-based TxManager. Transactions need a
+// replica set or sharded cluster; on a standalone server (as used in local
+// development) units of work run without one.
+func NewTxManager(client *mongo.Client) dao.TxManager {
+	return &txManager{client: client}
+}
+
+// WithTransaction runs fn in a session transaction. The session is carried by
+// the context passed to fn, so collection operations using it join the
+// transaction. The driver retries fn on transient transaction errors.
+func (m *txManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil || !m.transactionsSupported(ctx) {
+		return fn(ctx)
+	}
+
+	session, err := m.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// transactionsSupported reports whether the deployment is a replica set or
+// sharded cluster. The answer is cached once the server has been reached.
+func (m *txManager) transactionsSupported(ctx context.Context) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.checked {
+		return m.supported
+	}
+
+	var hello bson.M
+	if err := m.client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		// Attempt the transaction and let it surface the connection error
+		return true
+	}
+	_, replicaSet := hello["setName"]
+	m.supported = replicaSet || hello["msg"] == "isdbgrid"
+	m.checked = true
+	return m.supported
+}
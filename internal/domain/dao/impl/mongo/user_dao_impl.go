@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -22,13 +23,16 @@ type userDAO struct {
 
 // NewUserDAO creates a new MongoDB-based UserDAO.
 func NewUserDAO(db *mongo.Database, idCounter *IDCounter) dao.UserDAO {
+	m := mapper.NewUserMapper()
 	return &userDAO{
 		baseMongoDAO: newBaseMongoDAO[entity.User, document.UserDocument](
 			db,
 			document.UserDocument{}.CollectionName(),
 			idCounter,
+			dao.UserQueryFields,
+			m.ToEntity,
 		),
-		mapper: mapper.NewUserMapper(),
+		mapper: m,
 	}
 }
 
@@ -47,6 +51,18 @@ func (d *userDAO) Create(ctx context.Context, user *entity.User) error {
 	return d.insertOne(ctx, doc)
 }
 
+// CreateBatch inserts users into MongoDB at once, numbered from a single
+// block of IDs.
+func (d *userDAO) CreateBatch(ctx context.Context, users []*entity.User) error {
+	now := time.Now()
+	return d.createBatch(ctx, users, func(user *entity.User, id uint) *document.UserDocument {
+		user.ID = id
+		user.CreatedAt = now
+		user.UpdatedAt = now
+		return d.mapper.ToDocument(user)
+	})
+}
+
 // FindByID retrieves a user by their numeric ID.
 func (d *userDAO) FindByID(ctx context.Context, id uint) (*entity.User, error) {
 	filter := withNotDeleted(bson.M{"numeric_id": id})
@@ -62,14 +78,39 @@ func (d *userDAO) FindByID(ctx context.Context, id uint) (*entity.User, error) {
 	return d.mapper.ToEntity(&doc), nil
 }
 
-// Update modifies an existing user in MongoDB.
+// FindByIDIncludingDeleted retrieves a user by numeric ID, including soft-deleted users.
+func (d *userDAO) FindByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error) {
+	var doc document.UserDocument
+	err := d.findOneByFilter(ctx, bson.M{"numeric_id": id}, &doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d.mapper.ToEntity(&doc), nil
+}
+
+// Restore clears the deleted_at marker on a soft-deleted user.
+func (d *userDAO) Restore(ctx context.Context, id uint) error {
+	filter := bson.M{"numeric_id": id}
+	update := bson.M{"$set": bson.M{"deleted_at": nil, "updated_at": time.Now()}}
+	return d.updateOne(ctx, filter, update)
+}
+
+// Update modifies an existing user in MongoDB if it has not changed since it
+// was read, bumping its lock version.
 func (d *userDAO) Update(ctx context.Context, user *entity.User) error {
+	current := user.LockVersion
+	user.LockVersion = current + 1
 	user.UpdatedAt = time.Now()
 	doc := d.mapper.ToDocument(user)
 
-	filter := bson.M{"numeric_id": user.ID}
-	update := bson.M{"$set": doc}
-	return d.updateOne(ctx, filter, update)
+	if err := d.updateVersioned(ctx, user.ID, current, doc); err != nil {
+		user.LockVersion = current
+		return err
+	}
+	return nil
 }
 
 // Delete performs a soft delete on a user.
@@ -82,7 +123,16 @@ func (d *userDAO) Delete(ctx context.Context, id uint) error {
 
 // FindAll retrieves users with pagination.
 func (d *userDAO) FindAll(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
+	return d.FindAllWithOptions(ctx, page, size, false)
+}
+
+// FindAllWithOptions retrieves users with pagination, including soft-deleted
+// users if includeDeleted is set.
+func (d *userDAO) FindAllWithOptions(ctx context.Context, page, size int, includeDeleted bool) ([]*entity.User, int64, error) {
 	filter := notDeletedFilter()
+	if includeDeleted {
+		filter = bson.M{}
+	}
 
 	total, err := d.count(ctx, filter)
 	if err != nil {
@@ -103,6 +153,83 @@ func (d *userDAO) FindAll(ctx context.Context, page, size int) ([]*entity.User,
 	return d.mapper.ToEntities(docs), total, nil
 }
 
+// FindAllCursor retrieves up to limit users after afterID, ordered by ID ascending.
+func (d *userDAO) FindAllCursor(ctx context.Context, afterID uint, limit int) (*dao.CursorResult[entity.User], error) {
+	filter := withNotDeleted(bson.M{"numeric_id": bson.M{"$gt": afterID}})
+	opts := options.Find().
+		SetLimit(int64(limit + 1)).
+		SetSort(bson.D{{Key: "numeric_id", Value: 1}})
+
+	var docs []*document.UserDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+
+	return dao.NewCursorResult(d.mapper.ToEntities(docs), limit, func(e *entity.User) uint { return e.ID }), nil
+}
+
+// Search retrieves a page of users matching the query.
+func (d *userDAO) Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error) {
+	filter := notDeletedFilter()
+	if query.Role != nil {
+		filter["role"] = string(*query.Role)
+	}
+	if query.IsActive != nil {
+		filter["is_active"] = *query.IsActive
+	}
+	if query.IsVerified != nil {
+		filter["is_verified"] = *query.IsVerified
+	}
+	if query.CreatedAfter != nil || query.CreatedBefore != nil {
+		createdAt := bson.M{}
+		if query.CreatedAfter != nil {
+			createdAt["$gte"] = *query.CreatedAfter
+		}
+		if query.CreatedBefore != nil {
+			createdAt["$lt"] = *query.CreatedBefore
+		}
+		filter["created_at"] = createdAt
+	}
+	if query.Text != "" {
+		// An anchored, case-sensitive regex can use the username and email indexes
+		prefix := bson.M{"$regex": "^" + regexp.QuoteMeta(query.Text)}
+		filter["$or"] = bson.A{bson.M{"username": prefix}, bson.M{"email": prefix}}
+	}
+
+	total, err := d.count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	direction := 1
+	if query.SortDesc {
+		direction = -1
+	}
+	sort := bson.D{{Key: userSortKey(query.SortBy), Value: direction}}
+	if query.SortBy != entity.UserSortID {
+		sort = append(sort, bson.E{Key: "numeric_id", Value: direction})
+	}
+	opts := options.Find().
+		SetSkip(int64(query.Offset())).
+		SetLimit(int64(query.Size)).
+		SetSort(sort)
+
+	var docs []*document.UserDocument
+	if err := d.findManyByFilter(ctx, filter, opts, &docs); err != nil {
+		return nil, 0, err
+	}
+
+	return d.mapper.ToEntities(docs), total, nil
+}
+
+// userSortKey maps a user sort field to its document key
+func userSortKey(field string) string {
+	if field == entity.UserSortID {
+		return "numeric_id"
+	}
+	return field
+}
+
 // Count returns the total number of users.
 func (d *userDAO) Count(ctx context.Context) (int64, error) {
 	return d.count(ctx, notDeletedFilter())
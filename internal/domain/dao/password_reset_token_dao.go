@@ -0,0 +1,32 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// PasswordResetTokenDAO extends BaseDAO with password reset token-specific data access operations.
+type PasswordResetTokenDAO interface {
+	BaseDAO[entity.PasswordResetToken, uint]
+
+	// FindByTokenHash retrieves a reset token by the hash of its value.
+	// Returns nil, nil if the token is not found.
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+
+	// MarkUsed marks a reset token as used if it has not been used yet.
+	// Returns false if the token was already used, so each token can be
+	// consumed only once even under concurrent requests.
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+
+	// InvalidateAllByUserID marks all unused reset tokens for a user as used.
+	InvalidateAllByUserID(ctx context.Context, userID uint) error
+
+	// DeleteExpired permanently removes tokens that expired before the given
+	// time and returns how many were removed.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+
+	// CountExpired counts the tokens DeleteExpired would remove.
+	CountExpired(ctx context.Context, before time.Time) (int64, error)
+}
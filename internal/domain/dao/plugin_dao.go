@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 )
@@ -24,10 +25,23 @@ type PluginDAO interface {
 	// This is a convenience method equivalent to FindByState(ctx, PluginStateEnabled).
 	FindEnabled(ctx context.Context) ([]*entity.Plugin, error)
 
+	// FindAllCursor retrieves up to limit plugins with IDs greater than afterID,
+	// ordered by ID. Unlike offset paging, rows deleted between pages cannot
+	// shift later rows out of view.
+	FindAllCursor(ctx context.Context, afterID uint, limit int) (*CursorResult[entity.Plugin], error)
+
 	// ExistsByKey checks if a plugin with the given key exists.
 	ExistsByKey(ctx context.Context, key string) (bool, error)
 
 	// UpdateState updates the state of a plugin by its ID.
 	// This also updates the enabled_at timestamp when enabling a plugin.
 	UpdateState(ctx context.Context, id uint, state entity.PluginState) error
+
+	// PurgeDeleted permanently removes the plugins soft-deleted before the
+	// given time, with their extensions and state history, and returns how
+	// many plugins were removed. Soft-deleted plugins keep their key taken.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+
+	// CountDeleted counts the plugins PurgeDeleted would remove.
+	CountDeleted(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,15 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// PluginStateTransitionDAO extends BaseDAO with plugin state history operations.
+type PluginStateTransitionDAO interface {
+	BaseDAO[entity.PluginStateTransition, uint]
+
+	// FindByPluginID retrieves the state transitions of a plugin, oldest first.
+	FindByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginStateTransition, error)
+}
@@ -0,0 +1,218 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidQuery is returned by FindBy for a query on a field that is not
+// queryable, or with a malformed condition.
+var ErrInvalidQuery = errors.New("invalid query")
+
+const (
+	// DefaultQueryLimit is the number of entities FindBy returns without a limit
+	DefaultQueryLimit = 100
+	// MaxQueryLimit caps the number of entities FindBy returns
+	MaxQueryLimit = 1000
+)
+
+// Operator compares a field with the value of a Condition.
+type Operator string
+
+// Comparison operators.
+const (
+	OpEq  Operator = "eq"
+	OpNe  Operator = "ne"
+	OpGt  Operator = "gt"
+	OpGte Operator = "gte"
+	OpLt  Operator = "lt"
+	OpLte Operator = "lte"
+	// OpIn matches any of the values of a slice.
+	OpIn Operator = "in"
+)
+
+// Condition filters a Query. It either compares Field with Value using Op,
+// or joins the conditions of And or Or.
+type Condition struct {
+	Field string
+	Op    Operator
+	Value any
+
+	And []Condition
+	Or  []Condition
+}
+
+// Eq matches entities whose field equals value.
+func Eq(field string, value any) Condition {
+	return Condition{Field: field, Op: OpEq, Value: value}
+}
+
+// Ne matches entities whose field differs from value.
+func Ne(field string, value any) Condition {
+	return Condition{Field: field, Op: OpNe, Value: value}
+}
+
+// Gt matches entities whose field is greater than value.
+func Gt(field string, value any) Condition {
+	return Condition{Field: field, Op: OpGt, Value: value}
+}
+
+// Gte matches entities whose field is greater than or equal to value.
+func Gte(field string, value any) Condition {
+	return Condition{Field: field, Op: OpGte, Value: value}
+}
+
+// Lt matches entities whose field is less than value.
+func Lt(field string, value any) Condition {
+	return Condition{Field: field, Op: OpLt, Value: value}
+}
+
+// Lte matches entities whose field is less than or equal to value.
+func Lte(field string, value any) Condition {
+	return Condition{Field: field, Op: OpLte, Value: value}
+}
+
+// In matches entities whose field equals one of values.
+func In(field string, values ...any) Condition {
+	return Condition{Field: field, Op: OpIn, Value: values}
+}
+
+// And matches entities matching all of conditions.
+func And(conditions ...Condition) Condition {
+	return Condition{And: conditions}
+}
+
+// Or matches entities matching any of conditions.
+func Or(conditions ...Condition) Condition {
+	return Condition{Or: conditions}
+}
+
+// Values returns the values of an OpIn condition.
+func (c Condition) Values() []any {
+	if values, ok := c.Value.([]any); ok {
+		return values
+	}
+	v := reflect.ValueOf(c.Value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil
+	}
+	values := make([]any, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
+
+// SortField orders Query results by a field.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Query is an ad-hoc lookup run by FindBy. Its Where conditions are joined
+// with AND; results are ordered by OrderBy, then by ID.
+type Query struct {
+	Where   []Condition
+	OrderBy []SortField
+	Limit   int
+	Offset  int
+}
+
+// Normalize defaults and caps Limit and checks that the query only uses
+// fields, the queryable fields of the entity. It returns an error wrapping
+// ErrInvalidQuery otherwise.
+func (q *Query) Normalize(fields QueryFields) error {
+	if q.Limit < 1 {
+		q.Limit = DefaultQueryLimit
+	}
+	if q.Limit > MaxQueryLimit {
+		q.Limit = MaxQueryLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	for _, condition := range q.Where {
+		if err := condition.validate(fields); err != nil {
+			return err
+		}
+	}
+	for _, sort := range q.OrderBy {
+		if !fields.Has(sort.Field) {
+			return fmt.Errorf("%w: cannot order by %q", ErrInvalidQuery, sort.Field)
+		}
+	}
+	return nil
+}
+
+func (c Condition) validate(fields QueryFields) error {
+	isGroup := c.And != nil || c.Or != nil
+	switch {
+	case isGroup && (c.Field != "" || c.Op != ""):
+		return fmt.Errorf("%w: a condition compares a field or groups conditions, not both", ErrInvalidQuery)
+	case c.And != nil && c.Or != nil:
+		return fmt.Errorf("%w: a condition groups conditions with AND or OR, not both", ErrInvalidQuery)
+	case isGroup:
+		group := c.And
+		if c.Or != nil {
+			group = c.Or
+		}
+		if len(group) == 0 {
+			return fmt.Errorf("%w: empty condition group", ErrInvalidQuery)
+		}
+		for _, condition := range group {
+			if err := condition.validate(fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !fields.Has(c.Field) {
+		return fmt.Errorf("%w: cannot filter on %q", ErrInvalidQuery, c.Field)
+	}
+	switch c.Op {
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte:
+		return nil
+	case OpIn:
+		if c.Values() == nil {
+			return fmt.Errorf("%w: %q in needs a slice of values", ErrInvalidQuery, c.Field)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidQuery, c.Op)
+	}
+}
+
+// QueryFields whitelists the fields of an entity a Query may filter and
+// order on. Only indexed fields are listed, so FindBy never scans a table.
+// Fields are named after their SQL columns; "id" is the entity ID.
+type QueryFields map[string]struct{}
+
+// NewQueryFields creates a whitelist of the given fields.
+func NewQueryFields(fields ...string) QueryFields {
+	set := make(QueryFields, len(fields))
+	for _, field := range fields {
+		set[field] = struct{}{}
+	}
+	return set
+}
+
+// Has reports whether field is queryable.
+func (f QueryFields) Has(field string) bool {
+	_, ok := f[field]
+	return ok
+}
+
+// Queryable fields of each entity.
+var (
+	UserQueryFields                  = NewQueryFields("id", "username", "email", "role", "created_at")
+	RefreshTokenQueryFields          = NewQueryFields("id", "user_id", "token")
+	PasswordResetTokenQueryFields    = NewQueryFields("id", "user_id", "token_hash")
+	APIKeyQueryFields                = NewQueryFields("id", "key_hash")
+	AuditLogQueryFields              = NewQueryFields("id", "actor_id", "action", "created_at")
+	PluginQueryFields                = NewQueryFields("id", "key", "state")
+	PluginExtensionQueryFields       = NewQueryFields("id", "plugin_id")
+	PluginStateTransitionQueryFields = NewQueryFields("id", "plugin_id", "created_at")
+)
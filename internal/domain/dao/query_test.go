@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery_NormalizeLimits(t *testing.T) {
+	tests := []struct {
+		name           string
+		limit, offset  int
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{"defaults", 0, 0, DefaultQueryLimit, 0},
+		{"kept", 20, 40, 20, 40},
+		{"capped", MaxQueryLimit + 1, 0, MaxQueryLimit, 0},
+		{"negative", -5, -1, DefaultQueryLimit, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := Query{Limit: tt.limit, Offset: tt.offset}
+			assert.NoError(t, q.Normalize(UserQueryFields))
+			assert.Equal(t, tt.expectedLimit, q.Limit)
+			assert.Equal(t, tt.expectedOffset, q.Offset)
+		})
+	}
+}
+
+func TestQuery_NormalizeValidatesConditions(t *testing.T) {
+	fields := NewQueryFields("id", "role")
+
+	valid := []Query{
+		{Where: []Condition{Eq("role", "ADMIN"), Gte("id", 10)}},
+		{Where: []Condition{Or(Eq("role", "ADMIN"), And(Ne("role", "USER"), Lt("id", 5)))}},
+		{Where: []Condition{In("id", 1, 2, 3)}},
+		{Where: []Condition{{Field: "id", Op: OpIn, Value: []uint{1, 2}}}},
+		{OrderBy: []SortField{{Field: "role", Desc: true}}},
+	}
+	for _, q := range valid {
+		assert.NoError(t, q.Normalize(fields), "%+v", q)
+	}
+
+	invalid := []Query{
+		{Where: []Condition{Eq("email", "a@example.com")}},
+		{Where: []Condition{Or(Eq("role", "ADMIN"), Eq("password", "x"))}},
+		{Where: []Condition{{Field: "role", Op: "like", Value: "%"}}},
+		{Where: []Condition{{Field: "id", Op: OpIn, Value: 1}}},
+		{Where: []Condition{Or()}},
+		{Where: []Condition{{Field: "role", Op: OpEq, Value: "x", Or: []Condition{Eq("id", 1)}}}},
+		{Where: []Condition{{And: []Condition{Eq("id", 1)}, Or: []Condition{Eq("id", 2)}}}},
+		{OrderBy: []SortField{{Field: "created_at"}}},
+	}
+	for _, q := range invalid {
+		assert.ErrorIs(t, q.Normalize(fields), ErrInvalidQuery, "%+v", q)
+	}
+}
+
+func TestCondition_Values(t *testing.T) {
+	assert.Equal(t, []any{1, 2}, In("id", 1, 2).Values())
+	assert.Equal(t, []any{"a", "b"}, Condition{Value: []string{"a", "b"}}.Values())
+	assert.Nil(t, Condition{Value: "a"}.Values())
+}
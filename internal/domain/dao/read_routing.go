@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"context"
+)
+
+// primaryContextKey marks a context whose reads must hit the primary database.
+type primaryContextKey struct{}
+
+// ForcePrimary returns a context whose DAO reads are served by the primary
+// database instead of a read replica. Use it for read-after-write
+// consistency, e.g. reading a user right after creating it.
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// IsPrimaryForced reports whether ForcePrimary was applied to ctx.
+func IsPrimaryForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryContextKey{}).(bool)
+	return forced
+}
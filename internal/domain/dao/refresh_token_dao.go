@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 )
@@ -18,11 +19,23 @@ type RefreshTokenDAO interface {
 	// RevokeByToken revokes a specific refresh token by setting its revoked flag.
 	RevokeByToken(ctx context.Context, token string) error
 
+	// FindActiveByUserID retrieves the user's non-revoked, unexpired refresh
+	// tokens, newest first.
+	FindActiveByUserID(ctx context.Context, userID uint) ([]*entity.RefreshToken, error)
+
+	// RevokeByID revokes a refresh token if it belongs to the user.
+	// Returns false if no such non-revoked token exists.
+	RevokeByID(ctx context.Context, userID, id uint) (bool, error)
+
 	// RevokeAllByUserID revokes all refresh tokens for a specific user.
 	// This is useful for logout-from-all-devices functionality.
 	RevokeAllByUserID(ctx context.Context, userID uint) error
 
-	// DeleteExpired removes all expired tokens from the database.
+	// DeleteExpired permanently removes tokens that expired before the given
+	// time and returns how many were removed.
 	// This is typically called by a cleanup job.
-	DeleteExpired(ctx context.Context) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+
+	// CountExpired counts the tokens DeleteExpired would remove.
+	CountExpired(ctx context.Context, before time.Time) (int64, error)
 }
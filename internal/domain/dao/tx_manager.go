@@ -0,0 +1,14 @@
+package dao
+
+import (
+	"context"
+)
+
+// TxManager runs units of work spanning several DAOs in one database transaction.
+type TxManager interface {
+	// WithTransaction runs fn in a transaction, committing if fn returns nil
+	// and rolling back otherwise. DAO calls made with the context passed to fn
+	// take part in the transaction. A call made inside an existing transaction
+	// joins it instead of starting a new one.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -10,6 +10,13 @@ import (
 type UserDAO interface {
 	BaseDAO[entity.User, uint]
 
+	// FindByIDIncludingDeleted retrieves a user by ID even if soft-deleted.
+	// Returns nil, nil if the user is not found.
+	FindByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error)
+
+	// Restore clears the soft-delete marker of a user.
+	Restore(ctx context.Context, id uint) error
+
 	// FindByUsername retrieves a user by their unique username.
 	// Returns nil, nil if the user is not found.
 	FindByUsername(ctx context.Context, username string) (*entity.User, error)
@@ -23,6 +30,21 @@ type UserDAO interface {
 	// Returns nil, nil if the user is not found.
 	FindByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (*entity.User, error)
 
+	// Search retrieves a page of users matching the query, which must be
+	// normalized. Returns the users and the total number of matches, or an
+	// error wrapping ErrUnsupported on backends that cannot filter users.
+	Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error)
+
+	// FindAllWithOptions retrieves users with pagination like FindAll, and
+	// with includeDeleted also the soft-deleted ones. The total counts the
+	// same users.
+	FindAllWithOptions(ctx context.Context, page, size int, includeDeleted bool) ([]*entity.User, int64, error)
+
+	// FindAllCursor retrieves up to limit users with IDs greater than afterID,
+	// ordered by ID. Unlike offset paging, rows deleted between pages cannot
+	// shift later rows out of view.
+	FindAllCursor(ctx context.Context, afterID uint, limit int) (*CursorResult[entity.User], error)
+
 	// ExistsByUsername checks if a user with the given username exists.
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPIKey_TableName(t *testing.T) {
+	if got := (APIKey{}).TableName(); got != "api_keys" {
+		t.Errorf("APIKey.TableName() = %v, want api_keys", got)
+	}
+}
+
+func TestAPIKey_Scopes(t *testing.T) {
+	key := &APIKey{}
+	if key.ScopeList() != nil {
+		t.Errorf("ScopeList() = %v, want nil", key.ScopeList())
+	}
+
+	key.SetScopes([]string{"jobs:read", "jobs:write"})
+	if len(key.ScopeList()) != 2 {
+		t.Errorf("ScopeList() = %v, want 2 scopes", key.ScopeList())
+	}
+	if !key.HasScope("jobs:write") {
+		t.Error("HasScope(jobs:write) = false, want true")
+	}
+	if key.HasScope("jobs") {
+		t.Error("HasScope(jobs) = true, want false")
+	}
+}
+
+func TestAPIKey_IsValid(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		revokedAt *time.Time
+		expected  bool
+	}{
+		{name: "no expiry", expected: true},
+		{name: "not yet expired", expiresAt: &future, expected: true},
+		{name: "expired", expiresAt: &past, expected: false},
+		{name: "revoked", revokedAt: &past, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &APIKey{ExpiresAt: tt.expiresAt, RevokedAt: tt.revokedAt}
+			if got := key.IsValid(); got != tt.expected {
+				t.Errorf("APIKey.IsValid() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package entity
+
+import "time"
+
+// Audited actions
+const (
+	AuditActionLogin           = "auth.login"
+	AuditActionLogout          = "auth.logout"
+	AuditActionLogoutAll       = "auth.logout_all"
+	AuditActionPluginInstall   = "plugin.install"
+	AuditActionPluginUninstall = "plugin.uninstall"
+	AuditActionDLQPurge        = "jobs.dlq_purge"
+)
+
+// AuditOutcome is the result of an audited action
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "SUCCESS"
+	AuditOutcomeFailure AuditOutcome = "FAILURE"
+	// AuditOutcomeAttempted is recorded before an action that cannot be
+	// undone, so that failing on audit errors stops the action. The outcome
+	// follows in a second record.
+	AuditOutcomeAttempted AuditOutcome = "ATTEMPTED"
+)
+
+// AuditLog records a security-relevant action. Records are append-only.
+type AuditLog struct {
+	ID uint `gorm:"primaryKey;autoIncrement" json:"id"`
+	// ActorID is the user who acted, 0 when unauthenticated (e.g. a failed login)
+	ActorID uint `gorm:"index" json:"actor_id"`
+	// Actor names the actor: the username, or the login attempted
+	Actor     string       `gorm:"index;size:100" json:"actor"`
+	Action    string       `gorm:"index;size:50;not null" json:"action"`
+	Target    string       `gorm:"size:255" json:"target,omitempty"`
+	IPAddress string       `gorm:"size:45" json:"ip_address,omitempty"`
+	RequestID string       `gorm:"size:64" json:"request_id,omitempty"`
+	Outcome   AuditOutcome `gorm:"size:20;not null" json:"outcome"`
+	// Detail explains a failure
+	Detail    string    `gorm:"size:1000" json:"detail,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName specifies the table name for AuditLog
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+const (
+	// DefaultAuditQuerySize is the page size used when none is given
+	DefaultAuditQuerySize = 20
+	// MaxAuditQuerySize caps the page size of an audit log query
+	MaxAuditQuerySize = 100
+)
+
+// AuditQuery filters and paginates audit logs, newest first. Nil and empty
+// filters match every record.
+type AuditQuery struct {
+	ActorID *uint
+	Actor   string
+	Action  string
+	From    *time.Time
+	To      *time.Time
+
+	Page int
+	Size int
+}
+
+// Normalize replaces out-of-range paging with the defaults
+func (q *AuditQuery) Normalize() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Size < 1 {
+		q.Size = DefaultAuditQuerySize
+	}
+	if q.Size > MaxAuditQuerySize {
+		q.Size = MaxAuditQuerySize
+	}
+}
+
+// Offset returns the number of records before the requested page
+func (q *AuditQuery) Offset() int {
+	return (q.Page - 1) * q.Size
+}
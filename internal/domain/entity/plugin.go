@@ -38,17 +38,30 @@ type Plugin struct {
 	Version     string         `gorm:"size:50;not null" json:"version"`
 	Author      string         `gorm:"size:200" json:"author,omitempty"`
 	Type        PluginType     `gorm:"size:50;not null" json:"type"`
-	State       PluginState    `gorm:"size:20;not null;default:INSTALLED" json:"state"`
+	State       PluginState    `gorm:"size:20;not null;default:INSTALLED;index" json:"state"`
 	Config      string         `gorm:"type:text" json:"config,omitempty"`
+	ConfigSchema string        `gorm:"column:config_schema;type:text" json:"config_schema,omitempty"`
 	Checksum    string         `gorm:"size:128" json:"checksum,omitempty"`
 	Path        string         `gorm:"size:500" json:"path,omitempty"`
+	VersionHistory string      `gorm:"column:version_history;type:text" json:"-"`
 	InstalledAt time.Time      `gorm:"column:installed_at" json:"installed_at"`
 	EnabledAt   *time.Time     `gorm:"column:enabled_at" json:"enabled_at,omitempty"`
+	LockVersion uint           `gorm:"column:lock_version;not null;default:0" json:"lock_version"`
 	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// PluginVersion is a previously installed version of a plugin whose binary
+// is kept on disk for rollback. Plugin.VersionHistory holds them as a JSON
+// list, oldest first.
+type PluginVersion struct {
+	Version     string    `json:"version"`
+	Checksum    string    `json:"checksum"`
+	Path        string    `json:"path"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
 // TableName specifies the table name for Plugin
 func (Plugin) TableName() string {
 	return "plugins"
@@ -78,3 +91,20 @@ type PluginExtension struct {
 func (PluginExtension) TableName() string {
 	return "plugin_extensions"
 }
+
+// PluginStateTransition records a change of a plugin's state. Transitions are
+// written in the same transaction as the state change, so a plugin's history
+// always ends at its current state.
+type PluginStateTransition struct {
+	ID        uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	PluginID  uint        `gorm:"index;not null" json:"plugin_id"`
+	FromState PluginState `gorm:"size:20" json:"from_state"`
+	ToState   PluginState `gorm:"size:20;not null" json:"to_state"`
+	Reason    string      `gorm:"size:1000" json:"reason,omitempty"`
+	CreatedAt time.Time   `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName specifies the table name for PluginStateTransition
+func (PluginStateTransition) TableName() string {
+	return "plugin_state_transitions"
+}
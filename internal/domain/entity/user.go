@@ -12,22 +12,30 @@ type UserRole string
 const (
 	RoleUser  UserRole = "USER"
 	RoleAdmin UserRole = "ADMIN"
+
+	// RoleService is the role of callers authenticated with an API key. It is
+	// never assigned to users.
+	RoleService UserRole = "SERVICE"
 )
 
 // User represents a user entity in the system
 type User struct {
-	ID         uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	Username   string         `gorm:"uniqueIndex;size:50;not null" json:"username"`
-	Email      string         `gorm:"uniqueIndex;size:100;not null" json:"email"`
-	Password   string         `gorm:"not null" json:"-"`
-	FirstName  string         `gorm:"column:first_name;size:50" json:"first_name,omitempty"`
-	LastName   string         `gorm:"column:last_name;size:50" json:"last_name,omitempty"`
-	Role       UserRole       `gorm:"size:20;not null;default:USER" json:"role"`
-	IsActive   bool           `gorm:"column:is_active;default:true" json:"is_active"`
-	IsVerified bool           `gorm:"column:is_verified;default:false" json:"is_verified"`
-	CreatedAt  time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt  time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Username          string         `gorm:"uniqueIndex;size:50;not null" json:"username"`
+	Email             string         `gorm:"uniqueIndex;size:100;not null" json:"email"`
+	Password          string         `gorm:"not null" json:"-"`
+	FirstName         string         `gorm:"column:first_name;size:50" json:"first_name,omitempty"`
+	LastName          string         `gorm:"column:last_name;size:50" json:"last_name,omitempty"`
+	Role              UserRole       `gorm:"size:20;not null;default:USER;index" json:"role"`
+	IsActive          bool           `gorm:"column:is_active;default:true" json:"is_active"`
+	IsVerified        bool           `gorm:"column:is_verified;default:false" json:"is_verified"`
+	TOTPSecret        string         `gorm:"column:totp_secret;size:255" json:"-"`
+	TOTPEnabled       bool           `gorm:"column:totp_enabled;default:false" json:"totp_enabled"`
+	TOTPRecoveryCodes string         `gorm:"column:totp_recovery_codes;type:text" json:"-"`
+	LockVersion       uint           `gorm:"column:lock_version;not null;default:0" json:"lock_version"`
+	CreatedAt         time.Time      `gorm:"column:created_at;autoCreateTime;index" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName specifies the table name for User
@@ -35,13 +43,23 @@ func (User) TableName() string {
 	return "users"
 }
 
+// TOTPPending reports whether the user has started but not confirmed TOTP
+// enrollment
+func (u *User) TOTPPending() bool {
+	return u.TOTPSecret != "" && !u.TOTPEnabled
+}
+
 // RefreshToken represents a refresh token for JWT authentication
 type RefreshToken struct {
-	ID        uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID    uint           `gorm:"index;not null" json:"user_id"`
-	Token     string         `gorm:"uniqueIndex;size:500;not null" json:"token"`
-	ExpiresAt time.Time      `gorm:"not null" json:"expires_at"`
-	Revoked   bool           `gorm:"default:false" json:"revoked"`
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	Token     string    `gorm:"uniqueIndex;size:500;not null" json:"token"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	Revoked   bool      `gorm:"default:false" json:"revoked"`
+	// Client details captured when the token was issued
+	Device    string         `gorm:"size:100" json:"device,omitempty"`
+	UserAgent string         `gorm:"size:500" json:"user_agent,omitempty"`
+	IPAddress string         `gorm:"size:45" json:"ip_address,omitempty"`
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
@@ -62,3 +80,30 @@ func (rt *RefreshToken) IsExpired() bool {
 func (rt *RefreshToken) IsValid() bool {
 	return !rt.Revoked && !rt.IsExpired()
 }
+
+// PasswordResetToken is a single-use token allowing a user to set a new
+// password. Only the SHA-256 hash of the token is stored.
+type PasswordResetToken struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint           `gorm:"index;not null" json:"user_id"`
+	TokenHash string         `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	ExpiresAt time.Time      `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time     `json:"used_at,omitempty"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for PasswordResetToken
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+// IsExpired checks if the reset token is expired
+func (t *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsValid checks if the reset token is unused and not expired
+func (t *PasswordResetToken) IsValid() bool {
+	return t.UsedAt == nil && !t.IsExpired()
+}
@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// Sortable user fields. Sorting is restricted to indexed columns.
+const (
+	UserSortID        = "id"
+	UserSortUsername  = "username"
+	UserSortEmail     = "email"
+	UserSortCreatedAt = "created_at"
+)
+
+const (
+	// DefaultUserQuerySize is the page size used when none is given
+	DefaultUserQuerySize = 10
+	// MaxUserQuerySize caps the page size of a user search
+	MaxUserQuerySize = 100
+)
+
+// UserQuery filters, sorts and paginates users. Nil and empty filters match
+// every user.
+type UserQuery struct {
+	Role          *UserRole
+	IsActive      *bool
+	IsVerified    *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Text matches users whose username or email starts with it, so the
+	// unique indexes on both columns can serve the search
+	Text string
+
+	SortBy   string
+	SortDesc bool
+	Page     int
+	Size     int
+}
+
+// IsUserSortField reports whether users can be sorted by field
+func IsUserSortField(field string) bool {
+	switch field {
+	case UserSortID, UserSortUsername, UserSortEmail, UserSortCreatedAt:
+		return true
+	}
+	return false
+}
+
+// Normalize replaces an unknown sort field and out-of-range paging with the
+// defaults, newest users first
+func (q *UserQuery) Normalize() {
+	if !IsUserSortField(q.SortBy) {
+		q.SortBy = UserSortID
+		q.SortDesc = true
+	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Size < 1 {
+		q.Size = DefaultUserQuerySize
+	}
+	if q.Size > MaxUserQuerySize {
+		q.Size = MaxUserQuerySize
+	}
+}
+
+// Offset returns the number of users before the requested page
+func (q *UserQuery) Offset() int {
+	return (q.Page - 1) * q.Size
+}
@@ -197,6 +197,51 @@ func TestRefreshToken_Struct(t *testing.T) {
 	}
 }
 
+func TestPasswordResetToken_TableName(t *testing.T) {
+	if got := (PasswordResetToken{}).TableName(); got != "password_reset_tokens" {
+		t.Errorf("PasswordResetToken.TableName() = %v, want password_reset_tokens", got)
+	}
+}
+
+func TestPasswordResetToken_IsValid(t *testing.T) {
+	usedAt := time.Now()
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		usedAt    *time.Time
+		expected  bool
+	}{
+		{
+			name:      "valid token",
+			expiresAt: time.Now().Add(time.Hour),
+			expected:  true,
+		},
+		{
+			name:      "used token",
+			expiresAt: time.Now().Add(time.Hour),
+			usedAt:    &usedAt,
+			expected:  false,
+		},
+		{
+			name:      "expired token",
+			expiresAt: time.Now().Add(-time.Hour),
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &PasswordResetToken{
+				ExpiresAt: tt.expiresAt,
+				UsedAt:    tt.usedAt,
+			}
+			if got := token.IsValid(); got != tt.expected {
+				t.Errorf("PasswordResetToken.IsValid() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestUser_AdminRole(t *testing.T) {
 	admin := User{
 		ID:       1,
@@ -234,6 +279,33 @@ func TestRefreshToken_EdgeCases(t *testing.T) {
 	}
 }
 
+func TestUserQuery_Normalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		query UserQuery
+		want  UserQuery
+	}{
+		{"defaults", UserQuery{}, UserQuery{SortBy: UserSortID, SortDesc: true, Page: 1, Size: DefaultUserQuerySize}},
+		{"kept", UserQuery{SortBy: UserSortEmail, Page: 3, Size: 50}, UserQuery{SortBy: UserSortEmail, Page: 3, Size: 50}},
+		{"size capped", UserQuery{SortBy: UserSortUsername, Page: 1, Size: 1000}, UserQuery{SortBy: UserSortUsername, Page: 1, Size: MaxUserQuerySize}},
+		{"unknown sort field", UserQuery{SortBy: "password", Page: 1, Size: 10}, UserQuery{SortBy: UserSortID, SortDesc: true, Page: 1, Size: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Normalize()
+			if tt.query != tt.want {
+				t.Errorf("Normalize() = %+v, want %+v", tt.query, tt.want)
+			}
+		})
+	}
+
+	q := UserQuery{Page: 3, Size: 20}
+	if q.Offset() != 40 {
+		t.Errorf("Offset() = %d, want 40", q.Offset())
+	}
+}
+
 // Benchmarks
 func BenchmarkRefreshToken_IsExpired(b *testing.B) {
 	rt := &RefreshToken{ExpiresAt: time.Now().Add(time.Hour)}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// AuditLogRepository defines the interface for audit log operations
+type AuditLogRepository interface {
+	// Create records an audit log
+	Create(ctx context.Context, log *entity.AuditLog) error
+
+	// Query retrieves a page of audit logs matching the normalized query,
+	// newest first
+	Query(ctx context.Context, query *entity.AuditQuery) ([]*entity.AuditLog, int64, error)
+
+	// DeleteBefore removes the audit logs recorded before the given time and
+	// returns how many were removed
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// CountBefore counts the audit logs DeleteBefore would remove
+	CountBefore(ctx context.Context, before time.Time) (int64, error)
+}
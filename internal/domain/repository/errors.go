@@ -0,0 +1,14 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrInvalidCursor is returned when a pagination cursor is malformed
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrConcurrentModification is returned when updating an entity that was
+	// changed by someone else since it was read
+	ErrConcurrentModification = errors.New("concurrent modification")
+	// ErrUnsupported is returned for an operation the configured database
+	// cannot serve
+	ErrUnsupported = errors.New("not supported by this database")
+)
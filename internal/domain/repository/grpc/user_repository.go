@@ -60,6 +60,12 @@ func (r *UserRepositoryGRPC) GetByID(ctx context.Context, id uint) (*entity.User
 	return r.toEntity(resp), nil
 }
 
+// GetByIDIncludingDeleted is not available in layered mode; the user
+// service API never returns deleted users
+func (r *UserRepositoryGRPC) GetByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error) {
+	return nil, status.Error(codes.Unimplemented, "deleted user lookup is not supported over gRPC")
+}
+
 // GetByUsername retrieves a user by username
 func (r *UserRepositoryGRPC) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	resp, err := r.client.GetUserByUsername(ctx, &pb.GetUserByUsernameRequest{Username: username})
@@ -113,6 +119,9 @@ func (r *UserRepositoryGRPC) Update(ctx context.Context, user *entity.User) erro
 
 	resp, err := r.client.UpdateUser(ctx, req)
 	if err != nil {
+		if status.Code(err) == codes.Aborted {
+			return repository.ErrConcurrentModification
+		}
 		return err
 	}
 
@@ -126,6 +135,12 @@ func (r *UserRepositoryGRPC) Delete(ctx context.Context, id uint) error {
 	return err
 }
 
+// Restore is not available in layered mode; the user service API has no
+// restore call yet
+func (r *UserRepositoryGRPC) Restore(ctx context.Context, id uint) error {
+	return status.Error(codes.Unimplemented, "user restore is not supported over gRPC")
+}
+
 // List retrieves users with pagination
 func (r *UserRepositoryGRPC) List(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
 	resp, err := r.client.ListUsers(ctx, &pb.ListUsersRequest{
@@ -144,6 +159,24 @@ func (r *UserRepositoryGRPC) List(ctx context.Context, page, size int) ([]*entit
 	return users, resp.PageInfo.TotalItems, nil
 }
 
+// ListIncludingDeleted is not available in layered mode; the user service
+// API never returns deleted users
+func (r *UserRepositoryGRPC) ListIncludingDeleted(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
+	return nil, 0, status.Error(codes.Unimplemented, "listing deleted users is not supported over gRPC")
+}
+
+// ListAfter is not available in layered mode; the user service API only
+// supports offset paging
+func (r *UserRepositoryGRPC) ListAfter(ctx context.Context, cursor string, limit int) ([]*entity.User, string, error) {
+	return nil, "", status.Error(codes.Unimplemented, "cursor paging is not supported over gRPC")
+}
+
+// Search is not available in layered mode; the user service API has no search
+// call yet
+func (r *UserRepositoryGRPC) Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error) {
+	return nil, 0, status.Error(codes.Unimplemented, "user search is not supported over gRPC")
+}
+
 // ExistsByUsername checks if a username exists
 func (r *UserRepositoryGRPC) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	resp, err := r.client.ExistsByUsername(ctx, &pb.ExistsByUsernameRequest{Username: username})
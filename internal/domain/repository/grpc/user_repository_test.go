@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// TestUserRepositoryGRPC_Unsupported checks that calls the user service API
+// has no equivalent for fail with Unimplemented without reaching the server
+func TestUserRepositoryGRPC_Unsupported(t *testing.T) {
+	repo := &UserRepositoryGRPC{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GetByIDIncludingDeleted", func() error {
+			_, err := repo.GetByIDIncludingDeleted(ctx, 1)
+			return err
+		}},
+		{"ListIncludingDeleted", func() error {
+			_, _, err := repo.ListIncludingDeleted(ctx, 1, 10)
+			return err
+		}},
+		{"ListAfter", func() error {
+			_, _, err := repo.ListAfter(ctx, "", 10)
+			return err
+		}},
+		{"Search", func() error {
+			_, _, err := repo.Search(ctx, &entity.UserQuery{})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != codes.Unimplemented {
+				t.Errorf("%s() code = %v, want %v", tt.name, code, codes.Unimplemented)
+			}
+		})
+	}
+}
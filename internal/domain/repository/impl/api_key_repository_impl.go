@@ -0,0 +1,44 @@
+package impl
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+)
+
+// apiKeyRepository implements repository.APIKeyRepository by delegating to APIKeyDAO.
+type apiKeyRepository struct {
+	dao dao.APIKeyDAO
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository instance.
+func NewAPIKeyRepository(apiKeyDAO dao.APIKeyDAO) repository.APIKeyRepository {
+	return &apiKeyRepository{dao: apiKeyDAO}
+}
+
+// Create inserts a new API key.
+func (r *apiKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	return r.dao.Create(ctx, key)
+}
+
+// GetByID retrieves an API key by its ID.
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uint) (*entity.APIKey, error) {
+	return r.dao.FindByID(ctx, id)
+}
+
+// GetByKeyHash retrieves an API key by the hash of its value.
+func (r *apiKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	return r.dao.FindByKeyHash(ctx, keyHash)
+}
+
+// List retrieves API keys with pagination.
+func (r *apiKeyRepository) List(ctx context.Context, page, size int) ([]*entity.APIKey, int64, error) {
+	return r.dao.FindAll(ctx, page, size)
+}
+
+// Revoke revokes an API key, returning false if it was already revoked.
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uint) (bool, error) {
+	return r.dao.Revoke(ctx, id)
+}
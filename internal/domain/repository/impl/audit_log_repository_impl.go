@@ -0,0 +1,40 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+)
+
+// auditLogRepository implements repository.AuditLogRepository by delegating to AuditLogDAO.
+type auditLogRepository struct {
+	dao dao.AuditLogDAO
+}
+
+// NewAuditLogRepository creates a new AuditLogRepository instance.
+func NewAuditLogRepository(auditLogDAO dao.AuditLogDAO) repository.AuditLogRepository {
+	return &auditLogRepository{dao: auditLogDAO}
+}
+
+// Create records an audit log.
+func (r *auditLogRepository) Create(ctx context.Context, log *entity.AuditLog) error {
+	return r.dao.Create(ctx, log)
+}
+
+// Query retrieves a page of audit logs matching the query, newest first.
+func (r *auditLogRepository) Query(ctx context.Context, query *entity.AuditQuery) ([]*entity.AuditLog, int64, error) {
+	return r.dao.Query(ctx, query)
+}
+
+// DeleteBefore removes the audit logs recorded before the given time.
+func (r *auditLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.DeleteBefore(ctx, before)
+}
+
+// CountBefore counts the audit logs recorded before the given time.
+func (r *auditLogRepository) CountBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.CountBefore(ctx, before)
+}
@@ -0,0 +1,50 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+)
+
+// passwordResetTokenRepository implements repository.PasswordResetTokenRepository by delegating to PasswordResetTokenDAO.
+type passwordResetTokenRepository struct {
+	dao dao.PasswordResetTokenDAO
+}
+
+// NewPasswordResetTokenRepository creates a new PasswordResetTokenRepository instance.
+func NewPasswordResetTokenRepository(resetTokenDAO dao.PasswordResetTokenDAO) repository.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{dao: resetTokenDAO}
+}
+
+// Create inserts a new reset token.
+func (r *passwordResetTokenRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	return r.dao.Create(ctx, token)
+}
+
+// GetByTokenHash retrieves a reset token by the hash of its value.
+func (r *passwordResetTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	return r.dao.FindByTokenHash(ctx, tokenHash)
+}
+
+// MarkUsed consumes a reset token, returning false if it was already used.
+func (r *passwordResetTokenRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	return r.dao.MarkUsed(ctx, id)
+}
+
+// InvalidateAllByUserID invalidates all outstanding reset tokens for a user.
+func (r *passwordResetTokenRepository) InvalidateAllByUserID(ctx context.Context, userID uint) error {
+	return r.dao.InvalidateAllByUserID(ctx, userID)
+}
+
+// DeleteExpired permanently removes tokens that expired before the given time.
+func (r *passwordResetTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.DeleteExpired(ctx, before)
+}
+
+// CountExpired counts the tokens that expired before the given time.
+func (r *passwordResetTokenRepository) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.CountExpired(ctx, before)
+}
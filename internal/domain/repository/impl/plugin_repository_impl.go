@@ -2,6 +2,8 @@ package impl
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
@@ -35,7 +37,11 @@ func (r *pluginRepository) GetByKey(ctx context.Context, key string) (*entity.Pl
 
 // Update modifies an existing plugin.
 func (r *pluginRepository) Update(ctx context.Context, plugin *entity.Plugin) error {
-	return r.dao.Update(ctx, plugin)
+	err := r.dao.Update(ctx, plugin)
+	if errors.Is(err, dao.ErrConcurrentModification) {
+		return repository.ErrConcurrentModification
+	}
+	return err
 }
 
 // Delete removes a plugin by ID.
@@ -53,6 +59,19 @@ func (r *pluginRepository) List(ctx context.Context, page, size int) ([]*entity.
 	return r.dao.FindAll(ctx, page, size)
 }
 
+// ListAfter retrieves up to limit plugins after the cursor position.
+func (r *pluginRepository) ListAfter(ctx context.Context, cursor string, limit int) ([]*entity.Plugin, string, error) {
+	afterID, err := dao.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", repository.ErrInvalidCursor
+	}
+	result, err := r.dao.FindAllCursor(ctx, afterID, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return result.Items, result.NextCursor, nil
+}
+
 // ListByState retrieves all plugins with a specific state.
 func (r *pluginRepository) ListByState(ctx context.Context, state entity.PluginState) ([]*entity.Plugin, error) {
 	return r.dao.FindByState(ctx, state)
@@ -72,3 +91,13 @@ func (r *pluginRepository) ExistsByKey(ctx context.Context, key string) (bool, e
 func (r *pluginRepository) UpdateState(ctx context.Context, id uint, state entity.PluginState) error {
 	return r.dao.UpdateState(ctx, id, state)
 }
+
+// PurgeDeleted permanently removes the plugins soft-deleted before the given time.
+func (r *pluginRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.PurgeDeleted(ctx, before)
+}
+
+// CountDeleted counts the plugins soft-deleted before the given time.
+func (r *pluginRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.CountDeleted(ctx, before)
+}
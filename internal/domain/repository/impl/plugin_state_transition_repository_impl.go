@@ -0,0 +1,29 @@
+package impl
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+)
+
+// pluginStateTransitionRepository implements repository.PluginStateTransitionRepository by delegating to PluginStateTransitionDAO.
+type pluginStateTransitionRepository struct {
+	dao dao.PluginStateTransitionDAO
+}
+
+// NewPluginStateTransitionRepository creates a new PluginStateTransitionRepository instance.
+func NewPluginStateTransitionRepository(transitionDAO dao.PluginStateTransitionDAO) repository.PluginStateTransitionRepository {
+	return &pluginStateTransitionRepository{dao: transitionDAO}
+}
+
+// Create records a plugin state transition.
+func (r *pluginStateTransitionRepository) Create(ctx context.Context, transition *entity.PluginStateTransition) error {
+	return r.dao.Create(ctx, transition)
+}
+
+// ListByPluginID retrieves the state transitions of a plugin, oldest first.
+func (r *pluginStateTransitionRepository) ListByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginStateTransition, error) {
+	return r.dao.FindByPluginID(ctx, pluginID)
+}
@@ -2,6 +2,7 @@ package impl
 
 import (
 	"context"
+	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
@@ -33,12 +34,27 @@ func (r *refreshTokenRepository) RevokeByToken(ctx context.Context, token string
 	return r.dao.RevokeByToken(ctx, token)
 }
 
+// ListActiveByUserID retrieves a user's non-revoked, unexpired refresh tokens.
+func (r *refreshTokenRepository) ListActiveByUserID(ctx context.Context, userID uint) ([]*entity.RefreshToken, error) {
+	return r.dao.FindActiveByUserID(ctx, userID)
+}
+
+// RevokeByID revokes a refresh token owned by the user.
+func (r *refreshTokenRepository) RevokeByID(ctx context.Context, userID, id uint) (bool, error) {
+	return r.dao.RevokeByID(ctx, userID, id)
+}
+
 // RevokeAllByUserID revokes all refresh tokens for a specific user.
 func (r *refreshTokenRepository) RevokeAllByUserID(ctx context.Context, userID uint) error {
 	return r.dao.RevokeAllByUserID(ctx, userID)
 }
 
-// DeleteExpired removes all expired tokens from the database.
-func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
-	return r.dao.DeleteExpired(ctx)
+// DeleteExpired permanently removes tokens that expired before the given time.
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.DeleteExpired(ctx, before)
+}
+
+// CountExpired counts the tokens that expired before the given time.
+func (r *refreshTokenRepository) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.CountExpired(ctx, before)
 }
@@ -8,7 +8,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
 )
 
 // MockUserDAO is a mock implementation of dao.UserDAO
@@ -21,6 +23,11 @@ func (m *MockUserDAO) Create(ctx context.Context, user *entity.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserDAO) CreateBatch(ctx context.Context, users []*entity.User) error {
+	args := m.Called(ctx, users)
+	return args.Error(0)
+}
+
 func (m *MockUserDAO) FindByID(ctx context.Context, id uint) (*entity.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -44,6 +51,11 @@ func (m *MockUserDAO) FindAll(ctx context.Context, page, size int) ([]*entity.Us
 	return args.Get(0).([]*entity.User), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockUserDAO) FindAllWithOptions(ctx context.Context, page, size int, includeDeleted bool) ([]*entity.User, int64, error) {
+	args := m.Called(ctx, page, size, includeDeleted)
+	return args.Get(0).([]*entity.User), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockUserDAO) Count(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
@@ -54,6 +66,14 @@ func (m *MockUserDAO) ExistsBy(ctx context.Context, field string, value any) (bo
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserDAO) FindBy(ctx context.Context, query dao.Query) ([]*entity.User, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.User), args.Error(1)
+}
+
 func (m *MockUserDAO) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	args := m.Called(ctx, username)
 	if args.Get(0) == nil {
@@ -78,6 +98,35 @@ func (m *MockUserDAO) FindByUsernameOrEmail(ctx context.Context, usernameOrEmail
 	return args.Get(0).(*entity.User), args.Error(1)
 }
 
+func (m *MockUserDAO) FindByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.User), args.Error(1)
+}
+
+func (m *MockUserDAO) Restore(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserDAO) Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*entity.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserDAO) FindAllCursor(ctx context.Context, afterID uint, limit int) (*dao.CursorResult[entity.User], error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dao.CursorResult[entity.User]), args.Error(1)
+}
+
 func (m *MockUserDAO) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	args := m.Called(ctx, username)
 	return args.Bool(0), args.Error(1)
@@ -98,6 +147,11 @@ func (m *MockRefreshTokenDAO) Create(ctx context.Context, token *entity.RefreshT
 	return args.Error(0)
 }
 
+func (m *MockRefreshTokenDAO) CreateBatch(ctx context.Context, tokens []*entity.RefreshToken) error {
+	args := m.Called(ctx, tokens)
+	return args.Error(0)
+}
+
 func (m *MockRefreshTokenDAO) FindByID(ctx context.Context, id uint) (*entity.RefreshToken, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -131,6 +185,14 @@ func (m *MockRefreshTokenDAO) ExistsBy(ctx context.Context, field string, value
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockRefreshTokenDAO) FindBy(ctx context.Context, query dao.Query) ([]*entity.RefreshToken, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.RefreshToken), args.Error(1)
+}
+
 func (m *MockRefreshTokenDAO) FindByToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
 	args := m.Called(ctx, token)
 	if args.Get(0) == nil {
@@ -144,14 +206,32 @@ func (m *MockRefreshTokenDAO) RevokeByToken(ctx context.Context, token string) e
 	return args.Error(0)
 }
 
+func (m *MockRefreshTokenDAO) FindActiveByUserID(ctx context.Context, userID uint) ([]*entity.RefreshToken, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenDAO) RevokeByID(ctx context.Context, userID, id uint) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockRefreshTokenDAO) RevokeAllByUserID(ctx context.Context, userID uint) error {
 	args := m.Called(ctx, userID)
 	return args.Error(0)
 }
 
-func (m *MockRefreshTokenDAO) DeleteExpired(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
+func (m *MockRefreshTokenDAO) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRefreshTokenDAO) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 // MockPluginDAO is a mock implementation of dao.PluginDAO
@@ -164,6 +244,11 @@ func (m *MockPluginDAO) Create(ctx context.Context, plugin *entity.Plugin) error
 	return args.Error(0)
 }
 
+func (m *MockPluginDAO) CreateBatch(ctx context.Context, plugins []*entity.Plugin) error {
+	args := m.Called(ctx, plugins)
+	return args.Error(0)
+}
+
 func (m *MockPluginDAO) FindByID(ctx context.Context, id uint) (*entity.Plugin, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -187,6 +272,14 @@ func (m *MockPluginDAO) FindAll(ctx context.Context, page, size int) ([]*entity.
 	return args.Get(0).([]*entity.Plugin), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockPluginDAO) FindAllCursor(ctx context.Context, afterID uint, limit int) (*dao.CursorResult[entity.Plugin], error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dao.CursorResult[entity.Plugin]), args.Error(1)
+}
+
 func (m *MockPluginDAO) Count(ctx context.Context) (int64, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(int64), args.Error(1)
@@ -197,6 +290,14 @@ func (m *MockPluginDAO) ExistsBy(ctx context.Context, field string, value any) (
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockPluginDAO) FindBy(ctx context.Context, query dao.Query) ([]*entity.Plugin, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Plugin), args.Error(1)
+}
+
 func (m *MockPluginDAO) FindByKey(ctx context.Context, key string) (*entity.Plugin, error) {
 	args := m.Called(ctx, key)
 	if args.Get(0) == nil {
@@ -230,6 +331,16 @@ func (m *MockPluginDAO) UpdateState(ctx context.Context, id uint, state entity.P
 	return args.Error(0)
 }
 
+func (m *MockPluginDAO) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPluginDAO) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockPluginExtensionDAO is a mock implementation of dao.PluginExtensionDAO
 type MockPluginExtensionDAO struct {
 	mock.Mock
@@ -240,6 +351,11 @@ func (m *MockPluginExtensionDAO) Create(ctx context.Context, ext *entity.PluginE
 	return args.Error(0)
 }
 
+func (m *MockPluginExtensionDAO) CreateBatch(ctx context.Context, exts []*entity.PluginExtension) error {
+	args := m.Called(ctx, exts)
+	return args.Error(0)
+}
+
 func (m *MockPluginExtensionDAO) FindByID(ctx context.Context, id uint) (*entity.PluginExtension, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -273,6 +389,14 @@ func (m *MockPluginExtensionDAO) ExistsBy(ctx context.Context, field string, val
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockPluginExtensionDAO) FindBy(ctx context.Context, query dao.Query) ([]*entity.PluginExtension, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.PluginExtension), args.Error(1)
+}
+
 func (m *MockPluginExtensionDAO) FindByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginExtension, error) {
 	args := m.Called(ctx, pluginID)
 	return args.Get(0).([]*entity.PluginExtension), args.Error(1)
@@ -363,6 +487,18 @@ func TestUserRepository(t *testing.T) {
 		mockDAO.AssertExpectations(t)
 	})
 
+	t.Run("Update concurrent modification", func(t *testing.T) {
+		mockDAO := new(MockUserDAO)
+		repo := NewUserRepository(mockDAO)
+
+		user := &entity.User{ID: 1, Username: "updated"}
+		mockDAO.On("Update", ctx, user).Return(dao.ErrConcurrentModification)
+
+		err := repo.Update(ctx, user)
+		assert.ErrorIs(t, err, repository.ErrConcurrentModification)
+		mockDAO.AssertExpectations(t)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		mockDAO := new(MockUserDAO)
 		repo := NewUserRepository(mockDAO)
@@ -374,6 +510,21 @@ func TestUserRepository(t *testing.T) {
 		mockDAO.AssertExpectations(t)
 	})
 
+	t.Run("Restore", func(t *testing.T) {
+		mockDAO := new(MockUserDAO)
+		repo := NewUserRepository(mockDAO)
+
+		deleted := &entity.User{ID: 1}
+		mockDAO.On("FindByIDIncludingDeleted", ctx, uint(1)).Return(deleted, nil)
+		mockDAO.On("Restore", ctx, uint(1)).Return(nil)
+
+		user, err := repo.GetByIDIncludingDeleted(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, deleted, user)
+		assert.NoError(t, repo.Restore(ctx, 1))
+		mockDAO.AssertExpectations(t)
+	})
+
 	t.Run("List", func(t *testing.T) {
 		mockDAO := new(MockUserDAO)
 		repo := NewUserRepository(mockDAO)
@@ -388,6 +539,61 @@ func TestUserRepository(t *testing.T) {
 		mockDAO.AssertExpectations(t)
 	})
 
+	t.Run("ListIncludingDeleted", func(t *testing.T) {
+		mockDAO := new(MockUserDAO)
+		repo := NewUserRepository(mockDAO)
+
+		expectedUsers := []*entity.User{{ID: 2}, {ID: 1}}
+		mockDAO.On("FindAllWithOptions", ctx, 1, 10, true).Return(expectedUsers, int64(2), nil)
+
+		users, total, err := repo.ListIncludingDeleted(ctx, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUsers, users)
+		assert.Equal(t, int64(2), total)
+		mockDAO.AssertExpectations(t)
+	})
+
+	t.Run("ListAfter", func(t *testing.T) {
+		mockDAO := new(MockUserDAO)
+		repo := NewUserRepository(mockDAO)
+
+		expectedUsers := []*entity.User{{ID: 6}, {ID: 7}}
+		mockDAO.On("FindAllCursor", ctx, uint(5), 2).Return(&dao.CursorResult[entity.User]{
+			Items:      expectedUsers,
+			NextCursor: dao.EncodeCursor(7),
+		}, nil)
+
+		users, next, err := repo.ListAfter(ctx, dao.EncodeCursor(5), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUsers, users)
+		assert.Equal(t, dao.EncodeCursor(7), next)
+		mockDAO.AssertExpectations(t)
+	})
+
+	t.Run("ListAfter invalid cursor", func(t *testing.T) {
+		mockDAO := new(MockUserDAO)
+		repo := NewUserRepository(mockDAO)
+
+		_, _, err := repo.ListAfter(ctx, "not-a-cursor!", 2)
+		assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+		mockDAO.AssertNotCalled(t, "FindAllCursor", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		mockDAO := new(MockUserDAO)
+		repo := NewUserRepository(mockDAO)
+
+		query := &entity.UserQuery{Text: "al", Page: 1, Size: 10}
+		expectedUsers := []*entity.User{{ID: 1}}
+		mockDAO.On("Search", ctx, query).Return(expectedUsers, int64(1), nil)
+
+		users, total, err := repo.Search(ctx, query)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUsers, users)
+		assert.Equal(t, int64(1), total)
+		mockDAO.AssertExpectations(t)
+	})
+
 	t.Run("ExistsByUsername", func(t *testing.T) {
 		mockDAO := new(MockUserDAO)
 		repo := NewUserRepository(mockDAO)
@@ -453,6 +659,31 @@ func TestRefreshTokenRepository(t *testing.T) {
 		mockDAO.AssertExpectations(t)
 	})
 
+	t.Run("ListActiveByUserID", func(t *testing.T) {
+		mockDAO := new(MockRefreshTokenDAO)
+		repo := NewRefreshTokenRepository(mockDAO)
+
+		tokens := []*entity.RefreshToken{{ID: 1, UserID: 1}}
+		mockDAO.On("FindActiveByUserID", ctx, uint(1)).Return(tokens, nil)
+
+		result, err := repo.ListActiveByUserID(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, tokens, result)
+		mockDAO.AssertExpectations(t)
+	})
+
+	t.Run("RevokeByID", func(t *testing.T) {
+		mockDAO := new(MockRefreshTokenDAO)
+		repo := NewRefreshTokenRepository(mockDAO)
+
+		mockDAO.On("RevokeByID", ctx, uint(1), uint(2)).Return(true, nil)
+
+		revoked, err := repo.RevokeByID(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		mockDAO.AssertExpectations(t)
+	})
+
 	t.Run("RevokeAllByUserID", func(t *testing.T) {
 		mockDAO := new(MockRefreshTokenDAO)
 		repo := NewRefreshTokenRepository(mockDAO)
@@ -468,10 +699,12 @@ func TestRefreshTokenRepository(t *testing.T) {
 		mockDAO := new(MockRefreshTokenDAO)
 		repo := NewRefreshTokenRepository(mockDAO)
 
-		mockDAO.On("DeleteExpired", ctx).Return(nil)
+		before := time.Now()
+		mockDAO.On("DeleteExpired", ctx, before).Return(int64(3), nil)
 
-		err := repo.DeleteExpired(ctx)
+		deleted, err := repo.DeleteExpired(ctx, before)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
 		mockDAO.AssertExpectations(t)
 	})
 }
@@ -530,6 +763,18 @@ func TestPluginRepository(t *testing.T) {
 		mockDAO.AssertExpectations(t)
 	})
 
+	t.Run("Update concurrent modification", func(t *testing.T) {
+		mockDAO := new(MockPluginDAO)
+		repo := NewPluginRepository(mockDAO)
+
+		plugin := &entity.Plugin{ID: 1, Key: "updated"}
+		mockDAO.On("Update", ctx, plugin).Return(dao.ErrConcurrentModification)
+
+		err := repo.Update(ctx, plugin)
+		assert.ErrorIs(t, err, repository.ErrConcurrentModification)
+		mockDAO.AssertExpectations(t)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		mockDAO := new(MockPluginDAO)
 		repo := NewPluginRepository(mockDAO)
@@ -566,6 +811,22 @@ func TestPluginRepository(t *testing.T) {
 		mockDAO.AssertExpectations(t)
 	})
 
+	t.Run("ListAfter", func(t *testing.T) {
+		mockDAO := new(MockPluginDAO)
+		repo := NewPluginRepository(mockDAO)
+
+		expectedPlugins := []*entity.Plugin{{ID: 1}, {ID: 2}}
+		mockDAO.On("FindAllCursor", ctx, uint(0), 10).Return(&dao.CursorResult[entity.Plugin]{
+			Items: expectedPlugins,
+		}, nil)
+
+		plugins, next, err := repo.ListAfter(ctx, "", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPlugins, plugins)
+		assert.Empty(t, next)
+		mockDAO.AssertExpectations(t)
+	})
+
 	t.Run("ListByState", func(t *testing.T) {
 		mockDAO := new(MockPluginDAO)
 		repo := NewPluginRepository(mockDAO)
@@ -614,6 +875,19 @@ func TestPluginRepository(t *testing.T) {
 		assert.NoError(t, err)
 		mockDAO.AssertExpectations(t)
 	})
+
+	t.Run("PurgeDeleted", func(t *testing.T) {
+		mockDAO := new(MockPluginDAO)
+		repo := NewPluginRepository(mockDAO)
+
+		before := time.Now()
+		mockDAO.On("PurgeDeleted", ctx, before).Return(int64(2), nil)
+
+		purged, err := repo.PurgeDeleted(ctx, before)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+		mockDAO.AssertExpectations(t)
+	})
 }
 
 // Tests for PluginExtensionRepository
@@ -656,3 +930,33 @@ func TestPluginExtensionRepository(t *testing.T) {
 		mockDAO.AssertExpectations(t)
 	})
 }
+
+// MockTxManagerDAO is a mock implementation of dao.TxManager
+type MockTxManagerDAO struct {
+	mock.Mock
+}
+
+func (m *MockTxManagerDAO) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx, fn)
+	if args.Error(0) != nil {
+		return args.Error(0)
+	}
+	return fn(ctx)
+}
+
+func TestTxManager_WithTransaction(t *testing.T) {
+	ctx := context.Background()
+	mockDAO := new(MockTxManagerDAO)
+	txManager := NewTxManager(mockDAO)
+
+	mockDAO.On("WithTransaction", ctx, mock.Anything).Return(nil)
+
+	called := false
+	err := txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+	mockDAO.AssertExpectations(t)
+}
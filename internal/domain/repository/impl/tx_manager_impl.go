@@ -0,0 +1,23 @@
+package impl
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+)
+
+// txManager implements repository.TxManager by delegating to dao.TxManager.
+type txManager struct {
+	dao dao.TxManager
+}
+
+// NewTxManager creates a new TxManager instance.
+func NewTxManager(txDAO dao.TxManager) repository.TxManager {
+	return &txManager{dao: txDAO}
+}
+
+// WithTransaction runs fn in a database transaction.
+func (m *txManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.dao.WithTransaction(ctx, fn)
+}
@@ -5,6 +5,7 @@ package impl
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
@@ -31,6 +32,11 @@ func (r *userRepository) GetByID(ctx context.Context, id uint) (*entity.User, er
 	return r.dao.FindByID(ctx, id)
 }
 
+// GetByIDIncludingDeleted retrieves a user by ID, including soft-deleted users.
+func (r *userRepository) GetByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error) {
+	return r.dao.FindByIDIncludingDeleted(ctx, id)
+}
+
 // GetByUsername retrieves a user by their username.
 func (r *userRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
 	return r.dao.FindByUsername(ctx, username)
@@ -48,7 +54,11 @@ func (r *userRepository) GetByUsernameOrEmail(ctx context.Context, usernameOrEma
 
 // Update modifies an existing user.
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	return r.dao.Update(ctx, user)
+	err := r.dao.Update(ctx, user)
+	if errors.Is(err, dao.ErrConcurrentModification) {
+		return repository.ErrConcurrentModification
+	}
+	return err
 }
 
 // Delete removes a user by ID.
@@ -56,11 +66,43 @@ func (r *userRepository) Delete(ctx context.Context, id uint) error {
 	return r.dao.Delete(ctx, id)
 }
 
+// Restore undoes a soft delete of a user.
+func (r *userRepository) Restore(ctx context.Context, id uint) error {
+	return r.dao.Restore(ctx, id)
+}
+
 // List retrieves users with pagination.
 func (r *userRepository) List(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
 	return r.dao.FindAll(ctx, page, size)
 }
 
+// ListIncludingDeleted retrieves a page of users, soft-deleted ones included.
+func (r *userRepository) ListIncludingDeleted(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
+	return r.dao.FindAllWithOptions(ctx, page, size, true)
+}
+
+// ListAfter retrieves up to limit users after the cursor position.
+func (r *userRepository) ListAfter(ctx context.Context, cursor string, limit int) ([]*entity.User, string, error) {
+	afterID, err := dao.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", repository.ErrInvalidCursor
+	}
+	result, err := r.dao.FindAllCursor(ctx, afterID, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return result.Items, result.NextCursor, nil
+}
+
+// Search retrieves a page of users matching a normalized query.
+func (r *userRepository) Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error) {
+	users, total, err := r.dao.Search(ctx, query)
+	if errors.Is(err, dao.ErrUnsupported) {
+		return nil, 0, repository.ErrUnsupported
+	}
+	return users, total, err
+}
+
 // ExistsByUsername checks if a user with the given username exists.
 func (r *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	return r.dao.ExistsByUsername(ctx, username)
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 )
@@ -17,7 +18,8 @@ type PluginRepository interface {
 	// GetByKey retrieves a plugin by its unique key
 	GetByKey(ctx context.Context, key string) (*entity.Plugin, error)
 
-	// Update updates an existing plugin
+	// Update updates an existing plugin. Returns ErrConcurrentModification
+	// if its LockVersion no longer matches the stored one.
 	Update(ctx context.Context, plugin *entity.Plugin) error
 
 	// Delete soft-deletes a plugin by ID
@@ -29,6 +31,11 @@ type PluginRepository interface {
 	// List retrieves all plugins with pagination
 	List(ctx context.Context, page, size int) ([]*entity.Plugin, int64, error)
 
+	// ListAfter retrieves up to limit plugins ordered by ID, starting after the
+	// position encoded in cursor (empty for the first page). Returns the next
+	// cursor, empty on the last page, or ErrInvalidCursor.
+	ListAfter(ctx context.Context, cursor string, limit int) ([]*entity.Plugin, string, error)
+
 	// ListByState retrieves plugins by state
 	ListByState(ctx context.Context, state entity.PluginState) ([]*entity.Plugin, error)
 
@@ -40,6 +47,14 @@ type PluginRepository interface {
 
 	// UpdateState updates a plugin's state
 	UpdateState(ctx context.Context, id uint, state entity.PluginState) error
+
+	// PurgeDeleted permanently removes the plugins soft-deleted before the
+	// given time, with their extensions and state history, and returns how
+	// many plugins were removed
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+
+	// CountDeleted counts the plugins PurgeDeleted would remove
+	CountDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 // PluginExtensionRepository defines the interface for plugin extension operations
@@ -53,3 +68,13 @@ type PluginExtensionRepository interface {
 	// DeleteByPluginID deletes all extensions for a plugin
 	DeleteByPluginID(ctx context.Context, pluginID uint) error
 }
+
+// PluginStateTransitionRepository defines the interface for plugin state history
+type PluginStateTransitionRepository interface {
+	// Create records a state transition. Call it in the transaction that
+	// changes the state.
+	Create(ctx context.Context, transition *entity.PluginStateTransition) error
+
+	// ListByPluginID retrieves the state transitions of a plugin, oldest first
+	ListByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginStateTransition, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+)
+
+// When read replicas are configured, repository methods that only read may
+// be served by a replica and can lag behind recent writes:
+//
+//   - GetBy*, GetByIDIncludingDeleted
+//   - Exists*
+//   - List, ListAfter, ListByState, ListEnabled, ListActiveByUserID, Search
+//
+// All other methods write and always go to the primary, as does every call
+// made inside TxManager.WithTransaction.
+
+// ForcePrimary returns a context whose repository reads are served by the
+// primary database. Use it when a read must observe a write made just
+// before it, or when an entity is read in order to update it.
+func ForcePrimary(ctx context.Context) context.Context {
+	return dao.ForcePrimary(ctx)
+}
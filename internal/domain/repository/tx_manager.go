@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+)
+
+// TxManager makes a unit of work spanning several repositories atomic
+type TxManager interface {
+	// WithTransaction runs fn in a transaction that commits if fn returns nil
+	// and rolls back otherwise. Repository calls must use the context passed
+	// to fn to take part. Nested calls join the outer transaction.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 )
@@ -14,6 +15,9 @@ type UserRepository interface {
 	// GetByID retrieves a user by ID
 	GetByID(ctx context.Context, id uint) (*entity.User, error)
 
+	// GetByIDIncludingDeleted retrieves a user by ID even if soft-deleted
+	GetByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error)
+
 	// GetByUsername retrieves a user by username
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 
@@ -23,15 +27,31 @@ type UserRepository interface {
 	// GetByUsernameOrEmail retrieves a user by username or email
 	GetByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (*entity.User, error)
 
-	// Update updates an existing user
+	// Update updates an existing user. Returns ErrConcurrentModification
+	// if its LockVersion no longer matches the stored one.
 	Update(ctx context.Context, user *entity.User) error
 
 	// Delete soft-deletes a user by ID
 	Delete(ctx context.Context, id uint) error
 
+	// Restore undoes a soft delete
+	Restore(ctx context.Context, id uint) error
+
 	// List retrieves users with pagination
 	List(ctx context.Context, page, size int) ([]*entity.User, int64, error)
 
+	// ListIncludingDeleted retrieves users with pagination, soft-deleted ones
+	// included
+	ListIncludingDeleted(ctx context.Context, page, size int) ([]*entity.User, int64, error)
+
+	// ListAfter retrieves up to limit users ordered by ID, starting after the
+	// position encoded in cursor (empty for the first page). Returns the next
+	// cursor, empty on the last page, or ErrInvalidCursor.
+	ListAfter(ctx context.Context, cursor string, limit int) ([]*entity.User, string, error)
+
+	// Search retrieves a page of users matching a normalized query
+	Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error)
+
 	// ExistsByUsername checks if a username exists
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 
@@ -50,9 +70,60 @@ type RefreshTokenRepository interface {
 	// RevokeByToken revokes a specific refresh token
 	RevokeByToken(ctx context.Context, token string) error
 
+	// ListActiveByUserID retrieves a user's non-revoked, unexpired refresh tokens
+	ListActiveByUserID(ctx context.Context, userID uint) ([]*entity.RefreshToken, error)
+
+	// RevokeByID revokes a refresh token owned by the user, returning false if
+	// there is no such active token
+	RevokeByID(ctx context.Context, userID, id uint) (bool, error)
+
 	// RevokeAllByUserID revokes all refresh tokens for a user
 	RevokeAllByUserID(ctx context.Context, userID uint) error
 
-	// DeleteExpired removes all expired tokens
-	DeleteExpired(ctx context.Context) error
+	// DeleteExpired permanently removes tokens that expired before the given
+	// time and returns how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+
+	// CountExpired counts the tokens DeleteExpired would remove
+	CountExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PasswordResetTokenRepository defines the interface for password reset token operations
+type PasswordResetTokenRepository interface {
+	// Create creates a new reset token
+	Create(ctx context.Context, token *entity.PasswordResetToken) error
+
+	// GetByTokenHash retrieves a reset token by the hash of its value
+	GetByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+
+	// MarkUsed consumes a reset token, returning false if it was already used
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+
+	// InvalidateAllByUserID invalidates all outstanding reset tokens for a user
+	InvalidateAllByUserID(ctx context.Context, userID uint) error
+
+	// DeleteExpired permanently removes tokens that expired before the given
+	// time and returns how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+
+	// CountExpired counts the tokens DeleteExpired would remove
+	CountExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// APIKeyRepository defines the interface for API key operations
+type APIKeyRepository interface {
+	// Create creates a new API key
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// GetByID retrieves an API key by ID
+	GetByID(ctx context.Context, id uint) (*entity.APIKey, error)
+
+	// GetByKeyHash retrieves an API key by the hash of its value
+	GetByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+
+	// List retrieves API keys with pagination, newest first
+	List(ctx context.Context, page, size int) ([]*entity.APIKey, int64, error)
+
+	// Revoke revokes an API key, returning false if it was already revoked
+	Revoke(ctx context.Context, id uint) (bool, error)
 }
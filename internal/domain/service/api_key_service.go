@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+)
+
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// APIKeyService defines the interface for managing API keys
+type APIKeyService interface {
+	// Create generates a new API key. The raw key is returned only here; just
+	// its hash is stored.
+	Create(ctx context.Context, createdBy uint, req *request.CreateAPIKeyRequest) (*response.APIKeyCreatedResponse, error)
+
+	// List retrieves API keys with pagination
+	List(ctx context.Context, page, size int) (*response.PagedResponse[response.APIKeyResponse], error)
+
+	// Revoke revokes an API key. Revoking a revoked key succeeds.
+	Revoke(ctx context.Context, id uint) error
+}
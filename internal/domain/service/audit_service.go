@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+)
+
+// AuditService records security-relevant actions and queries the audit trail
+type AuditService interface {
+	// Record stores an audit record. A record that cannot be stored is
+	// logged; the error is only returned when the service is configured to
+	// fail on audit errors, so callers can abort the audited operation.
+	Record(ctx context.Context, log *entity.AuditLog) error
+
+	// Query retrieves audit records matching the filters, newest first
+	Query(ctx context.Context, req *request.AuditQueryRequest) (*response.PagedResponse[response.AuditLogResponse], error)
+}
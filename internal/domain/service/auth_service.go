@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
@@ -14,6 +15,11 @@ var (
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidToken       = errors.New("invalid or expired token")
 	ErrUserInactive       = errors.New("user account is inactive")
+	ErrAlreadyVerified    = errors.New("email already verified")
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrTOTPNotPending     = errors.New("two-factor authentication setup not started")
+	ErrInvalidTOTPCode    = errors.New("invalid two-factor authentication code")
+	ErrSessionNotFound    = errors.New("session not found")
 )
 
 // AuthService defines the interface for authentication operations
@@ -21,7 +27,9 @@ type AuthService interface {
 	// Register creates a new user account
 	Register(ctx context.Context, req *request.RegisterRequest) (*response.AuthResponse, error)
 
-	// Login authenticates a user and returns tokens
+	// Login authenticates a user and returns tokens. If the user has enabled
+	// two-factor authentication, no tokens are issued; the response instead
+	// carries a challenge to be completed with VerifyTOTP.
 	Login(ctx context.Context, req *request.LoginRequest) (*response.AuthResponse, error)
 
 	// RefreshToken generates new tokens using a refresh token
@@ -32,4 +40,84 @@ type AuthService interface {
 
 	// LogoutAll invalidates all tokens for a user
 	LogoutAll(ctx context.Context, userID uint) error
+
+	// RequestPasswordReset issues a single-use password reset token for the
+	// active user with the given email. It returns nil if there is no such
+	// user; callers must not reveal this to the client.
+	RequestPasswordReset(ctx context.Context, email string) (*PasswordReset, error)
+
+	// ResetPassword consumes a reset token, sets the new password and
+	// invalidates all of the user's refresh tokens
+	ResetPassword(ctx context.Context, token, newPassword string) error
+
+	// SendVerification issues a signed token verifying the user's current
+	// email address
+	SendVerification(ctx context.Context, userID uint) (*EmailVerification, error)
+
+	// VerifyEmail marks the user's email as verified using a verification token
+	VerifyEmail(ctx context.Context, token string) error
+
+	// EnableTOTP starts two-factor enrollment by generating a new TOTP secret.
+	// It takes effect once confirmed with ConfirmTOTP.
+	EnableTOTP(ctx context.Context, userID uint) (*TOTPSetup, error)
+
+	// ConfirmTOTP activates two-factor authentication using a code from the
+	// user's authenticator app and returns single-use recovery codes
+	ConfirmTOTP(ctx context.Context, userID uint, code string) ([]string, error)
+
+	// VerifyTOTP completes a two-factor login challenge with a TOTP code or a
+	// recovery code and returns tokens
+	VerifyTOTP(ctx context.Context, challengeID, code string) (*response.AuthResponse, error)
+
+	// ListSessions returns the user's active sessions, newest first. Current
+	// is left for the caller to set.
+	ListSessions(ctx context.Context, userID uint) ([]*response.SessionResponse, error)
+
+	// RevokeSession signs out one of the user's sessions. It returns
+	// ErrSessionNotFound if the user has no such active session.
+	RevokeSession(ctx context.Context, userID, sessionID uint) error
+}
+
+// PasswordReset is an issued password reset token to be sent to the user
+type PasswordReset struct {
+	UserID    uint
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// EmailVerification is an issued email verification token to be sent to the user
+type EmailVerification struct {
+	UserID    uint
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TOTPSetup is a newly generated TOTP secret to be added to an authenticator app
+type TOTPSetup struct {
+	Secret string
+	URL    string
+}
+
+// ClientInfo describes the client that started a session
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type contextKey string
+
+const clientInfoContextKey contextKey = "client_info"
+
+// ContextWithClientInfo returns a context carrying the client making the
+// request, recorded on sessions issued while handling it
+func ContextWithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey, info)
+}
+
+// ClientInfoFromContext returns the client making the request, if known
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoContextKey).(ClientInfo)
+	return info
 }
@@ -0,0 +1,110 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+)
+
+// apiKeyService implements service.APIKeyService
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewAPIKeyService creates a new APIKeyService instance
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) service.APIKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, createdBy uint, req *request.CreateAPIKeyRequest) (*response.APIKeyCreatedResponse, error) {
+	key, prefix, err := security.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &entity.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   security.HashAPIKey(key),
+		CreatedBy: createdBy,
+	}
+	apiKey.SetScopes(req.Scopes)
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return &response.APIKeyCreatedResponse{
+		APIKeyResponse: *s.toAPIKeyResponse(apiKey),
+		Key:            key,
+	}, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, page, size int) (*response.PagedResponse[response.APIKeyResponse], error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 100 {
+		size = 10
+	}
+
+	keys, total, err := s.apiKeyRepo.List(ctx, page, size)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		items[i] = *s.toAPIKeyResponse(key)
+	}
+
+	result := response.NewPagedResponse(items, page, size, total)
+	return &result, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, id uint) error {
+	revoked, err := s.apiKeyRepo.Revoke(ctx, id)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return nil
+	}
+
+	// Nothing was revoked: either the key does not exist or it already was
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if apiKey == nil {
+		return service.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *apiKeyService) toAPIKeyResponse(key *entity.APIKey) *response.APIKeyResponse {
+	scopes := key.ScopeList()
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return &response.APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    scopes,
+		CreatedBy: key.CreatedBy,
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+		CreatedAt: key.CreatedAt,
+	}
+}
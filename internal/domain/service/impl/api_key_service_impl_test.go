@@ -0,0 +1,106 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
+)
+
+func setupAPIKeyService(t *testing.T) (service.APIKeyService, *mocks.MockAPIKeyRepository) {
+	apiKeyRepo := mocks.NewMockAPIKeyRepository()
+	return NewAPIKeyService(apiKeyRepo), apiKeyRepo
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	apiKeyService, apiKeyRepo := setupAPIKeyService(t)
+	ctx := context.Background()
+
+	created, err := apiKeyService.Create(ctx, 7, &request.CreateAPIKeyRequest{
+		Name:          "billing",
+		Scopes:        []string{"jobs:read", "jobs:write"},
+		ExpiresInDays: 30,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !strings.HasPrefix(created.Key, created.Prefix) {
+		t.Errorf("Create() key %q does not start with prefix %q", created.Key, created.Prefix)
+	}
+	if created.CreatedBy != 7 || created.ExpiresAt == nil || len(created.Scopes) != 2 {
+		t.Errorf("Create() = %+v", created.APIKeyResponse)
+	}
+
+	// Only the hash of the key is stored
+	stored, _ := apiKeyRepo.GetByID(ctx, created.ID)
+	if stored.KeyHash != security.HashAPIKey(created.Key) {
+		t.Error("Create() should store the key's hash")
+	}
+	if strings.Contains(stored.KeyHash, created.Key) {
+		t.Error("Create() should not store the raw key")
+	}
+}
+
+func TestAPIKeyService_Create_RepoError(t *testing.T) {
+	apiKeyService, apiKeyRepo := setupAPIKeyService(t)
+	apiKeyRepo.CreateErr = errors.New("db down")
+
+	_, err := apiKeyService.Create(context.Background(), 1, &request.CreateAPIKeyRequest{Name: "svc"})
+	if err == nil {
+		t.Error("Create() should return the repository error")
+	}
+}
+
+func TestAPIKeyService_List(t *testing.T) {
+	apiKeyService, _ := setupAPIKeyService(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"a", "b", "c"} {
+		if _, err := apiKeyService.Create(ctx, 1, &request.CreateAPIKeyRequest{Name: name}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	page, err := apiKeyService.List(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page.Items) != 2 || page.PageInfo.TotalItems != 3 {
+		t.Errorf("List() items = %d, total = %d", len(page.Items), page.PageInfo.TotalItems)
+	}
+	if page.Items[0].Name != "c" {
+		t.Errorf("List() first item = %q, want newest key", page.Items[0].Name)
+	}
+	if page.Items[0].Scopes == nil {
+		t.Error("List() scopes should be an empty list, not null")
+	}
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	apiKeyService, apiKeyRepo := setupAPIKeyService(t)
+	ctx := context.Background()
+
+	created, _ := apiKeyService.Create(ctx, 1, &request.CreateAPIKeyRequest{Name: "svc"})
+
+	if err := apiKeyService.Revoke(ctx, created.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	stored, _ := apiKeyRepo.GetByID(ctx, created.ID)
+	if stored.IsValid() {
+		t.Error("Revoke() should invalidate the key")
+	}
+
+	// Revoking again succeeds
+	if err := apiKeyService.Revoke(ctx, created.ID); err != nil {
+		t.Errorf("Revoke() again error = %v", err)
+	}
+
+	if err := apiKeyService.Revoke(ctx, 999); !errors.Is(err, service.ErrAPIKeyNotFound) {
+		t.Errorf("Revoke() unknown key error = %v, want %v", err, service.ErrAPIKeyNotFound)
+	}
+}
@@ -0,0 +1,94 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+)
+
+// auditService implements service.AuditService
+type auditService struct {
+	auditRepo   repository.AuditLogRepository
+	logger      *zap.Logger
+	failOnError bool
+}
+
+// NewAuditService creates a new AuditService instance. Every record is also
+// written to logger; with failOnError a record that cannot be stored fails
+// Record instead of only being logged.
+func NewAuditService(auditRepo repository.AuditLogRepository, logger *zap.Logger, failOnError bool) service.AuditService {
+	return &auditService{
+		auditRepo:   auditRepo,
+		logger:      logger.Named("audit"),
+		failOnError: failOnError,
+	}
+}
+
+func (s *auditService) Record(ctx context.Context, log *entity.AuditLog) error {
+	fields := []zap.Field{
+		zap.Uint("actor_id", log.ActorID),
+		zap.String("actor", log.Actor),
+		zap.String("action", log.Action),
+		zap.String("target", log.Target),
+		zap.String("ip_address", log.IPAddress),
+		zap.String("request_id", log.RequestID),
+		zap.String("outcome", string(log.Outcome)),
+	}
+	if log.Detail != "" {
+		fields = append(fields, zap.String("detail", log.Detail))
+	}
+	s.logger.Info("Audit", fields...)
+
+	// Store the record even if the request was cancelled once the action was done
+	if err := s.auditRepo.Create(context.WithoutCancel(ctx), log); err != nil {
+		s.logger.Error("Failed to store audit record", append(fields, zap.Error(err))...)
+		if s.failOnError {
+			return fmt.Errorf("failed to store audit record: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *auditService) Query(ctx context.Context, req *request.AuditQueryRequest) (*response.PagedResponse[response.AuditLogResponse], error) {
+	query := &entity.AuditQuery{
+		ActorID: req.ActorID,
+		Actor:   req.Actor,
+		Action:  req.Action,
+		From:    req.From,
+		To:      req.To,
+		Page:    req.Page,
+		Size:    req.Size,
+	}
+	query.Normalize()
+
+	logs, total, err := s.auditRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.AuditLogResponse, len(logs))
+	for i, log := range logs {
+		items[i] = response.AuditLogResponse{
+			ID:        log.ID,
+			ActorID:   log.ActorID,
+			Actor:     log.Actor,
+			Action:    log.Action,
+			Target:    log.Target,
+			IPAddress: log.IPAddress,
+			RequestID: log.RequestID,
+			Outcome:   string(log.Outcome),
+			Detail:    log.Detail,
+			CreatedAt: log.CreatedAt,
+		}
+	}
+
+	result := response.NewPagedResponse(items, query.Page, query.Size, total)
+	return &result, nil
+}
@@ -0,0 +1,119 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	auditRepo := mocks.NewMockAuditLogRepository()
+	auditService := NewAuditService(auditRepo, zap.NewNop(), false)
+
+	err := auditService.Record(context.Background(), &entity.AuditLog{
+		ActorID: 7,
+		Actor:   "admin",
+		Action:  entity.AuditActionPluginInstall,
+		Target:  "hello-plugin",
+		Outcome: entity.AuditOutcomeSuccess,
+	})
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	logs := auditRepo.Logs()
+	if len(logs) != 1 || logs[0].Action != entity.AuditActionPluginInstall || logs[0].Target != "hello-plugin" {
+		t.Errorf("stored logs = %+v", logs)
+	}
+}
+
+func TestAuditService_Record_RepoError(t *testing.T) {
+	storeErr := errors.New("database unavailable")
+
+	auditRepo := mocks.NewMockAuditLogRepository()
+	auditRepo.CreateErr = storeErr
+
+	// By default a failed write is only logged
+	lenient := NewAuditService(auditRepo, zap.NewNop(), false)
+	if err := lenient.Record(context.Background(), &entity.AuditLog{Action: entity.AuditActionLogin}); err != nil {
+		t.Errorf("Record() error = %v, want nil", err)
+	}
+
+	strict := NewAuditService(auditRepo, zap.NewNop(), true)
+	if err := strict.Record(context.Background(), &entity.AuditLog{Action: entity.AuditActionLogin}); !errors.Is(err, storeErr) {
+		t.Errorf("Record() error = %v, want %v", err, storeErr)
+	}
+}
+
+func TestAuditService_Record_CancelledContext(t *testing.T) {
+	auditRepo := mocks.NewMockAuditLogRepository()
+	auditService := NewAuditService(auditRepo, zap.NewNop(), true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := auditService.Record(ctx, &entity.AuditLog{Action: entity.AuditActionLogout}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if len(auditRepo.Logs()) != 1 {
+		t.Error("Record() should store the record after the request is cancelled")
+	}
+}
+
+func TestAuditService_Query(t *testing.T) {
+	auditRepo := mocks.NewMockAuditLogRepository()
+	auditService := NewAuditService(auditRepo, zap.NewNop(), false)
+	ctx := context.Background()
+
+	for _, log := range []*entity.AuditLog{
+		{ActorID: 1, Actor: "alice", Action: entity.AuditActionLogin, Outcome: entity.AuditOutcomeSuccess},
+		{ActorID: 2, Actor: "bob", Action: entity.AuditActionLogin, Outcome: entity.AuditOutcomeSuccess},
+		{ActorID: 1, Actor: "alice", Action: entity.AuditActionDLQPurge, Target: "dlq", Outcome: entity.AuditOutcomeSuccess},
+	} {
+		if err := auditService.Record(ctx, log); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	actorID := uint(1)
+	result, err := auditService.Query(ctx, &request.AuditQueryRequest{ActorID: &actorID})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if result.PageInfo.TotalItems != 2 || result.Items[0].Action != entity.AuditActionDLQPurge {
+		t.Errorf("Query(actor_id=1) = %+v, want the DLQ purge then the login", result)
+	}
+
+	result, _ = auditService.Query(ctx, &request.AuditQueryRequest{Action: entity.AuditActionLogin, Actor: "bob"})
+	if result.PageInfo.TotalItems != 1 || result.Items[0].ActorID != 2 {
+		t.Errorf("Query(action=login, actor=bob) = %+v", result)
+	}
+
+	future := time.Now().Add(time.Hour)
+	result, _ = auditService.Query(ctx, &request.AuditQueryRequest{From: &future})
+	if result.PageInfo.TotalItems != 0 {
+		t.Errorf("Query(from=future) total = %d, want 0", result.PageInfo.TotalItems)
+	}
+
+	// Paging defaults apply
+	result, _ = auditService.Query(ctx, &request.AuditQueryRequest{})
+	if result.PageInfo.Page != 1 || result.PageInfo.Size != entity.DefaultAuditQuerySize {
+		t.Errorf("Query() page info = %+v, want the defaults", result.PageInfo)
+	}
+}
+
+func TestAuditService_Query_RepoError(t *testing.T) {
+	auditRepo := mocks.NewMockAuditLogRepository()
+	auditRepo.QueryErr = errors.New("query failed")
+	auditService := NewAuditService(auditRepo, zap.NewNop(), false)
+
+	if _, err := auditService.Query(context.Background(), &request.AuditQueryRequest{}); err == nil {
+		t.Error("Query() should return the repository error")
+	}
+}
@@ -2,6 +2,13 @@ package impl
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
@@ -11,26 +18,53 @@ import (
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
+const (
+	// passwordResetTokenTTL is how long a password reset token can be used
+	passwordResetTokenTTL = 30 * time.Minute
+
+	// totpRecoveryCodeCount is the number of recovery codes issued when
+	// two-factor authentication is enabled
+	totpRecoveryCodeCount = 10
+)
+
 // authService implements service.AuthService
 type authService struct {
 	userRepo         repository.UserRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	resetTokenRepo   repository.PasswordResetTokenRepository
+	txManager        repository.TxManager
 	jwtProvider      *security.JWTProvider
 	passwordHasher   *security.PasswordHasher
+	totpProvider     *security.TOTPProvider
+	tokenDenylist    security.TokenDenylist
+	challengeStore   security.TwoFactorChallengeStore
 }
 
-// NewAuthService creates a new AuthService instance
+// NewAuthService creates a new AuthService instance. tokenDenylist may be nil,
+// in which case logging out only revokes refresh tokens. challengeStore may be
+// nil, in which case two-factor challenges are neither single-use nor limited
+// in attempts until they expire.
 func NewAuthService(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	txManager repository.TxManager,
 	jwtProvider *security.JWTProvider,
 	passwordHasher *security.PasswordHasher,
+	totpProvider *security.TOTPProvider,
+	tokenDenylist security.TokenDenylist,
+	challengeStore security.TwoFactorChallengeStore,
 ) service.AuthService {
 	return &authService{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		resetTokenRepo:   resetTokenRepo,
+		txManager:        txManager,
 		jwtProvider:      jwtProvider,
 		passwordHasher:   passwordHasher,
+		totpProvider:     totpProvider,
+		tokenDenylist:    tokenDenylist,
+		challengeStore:   challengeStore,
 	}
 }
 
@@ -70,15 +104,28 @@ func (s *authService) Register(ctx context.Context, req *request.RegisterRequest
 		IsActive:  true,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	// Create the user and its first session together so a failure to issue
+	// tokens does not leave an account behind
+	var authResponse *response.AuthResponse
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		var err error
+		authResponse, err = s.generateAuthResponse(ctx, user)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	// Generate tokens
-	return s.generateAuthResponse(ctx, user)
+	return authResponse, nil
 }
 
 func (s *authService) Login(ctx context.Context, req *request.LoginRequest) (*response.AuthResponse, error) {
+	// The user may be updated with a rehashed password, and a replica may not
+	// have caught up with a recent password change yet
+	ctx = repository.ForcePrimary(ctx)
+
 	// Find user by username or email
 	user, err := s.userRepo.GetByUsernameOrEmail(ctx, req.UsernameOrEmail)
 	if err != nil {
@@ -97,12 +144,47 @@ func (s *authService) Login(ctx context.Context, req *request.LoginRequest) (*re
 	if !s.passwordHasher.Verify(req.Password, user.Password) {
 		return nil, service.ErrInvalidCredentials
 	}
+	s.upgradePasswordHash(ctx, user, req.Password)
+
+	// Tokens are only issued once the second factor is verified
+	if user.TOTPEnabled {
+		challengeID, _, err := s.jwtProvider.GenerateTwoFactorChallenge(user)
+		if err != nil {
+			return nil, err
+		}
+		return &response.AuthResponse{
+			TwoFactorRequired: true,
+			ChallengeID:       challengeID,
+		}, nil
+	}
 
 	// Generate tokens
 	return s.generateAuthResponse(ctx, user)
 }
 
+// upgradePasswordHash rehashes a verified password whose stored hash was made
+// with other hashing settings. It is best effort: a failure leaves the old
+// hash in place, which still verifies.
+func (s *authService) upgradePasswordHash(ctx context.Context, user *entity.User, password string) {
+	if !s.passwordHasher.NeedsRehash(user.Password) {
+		return
+	}
+	hash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return
+	}
+	previous := user.Password
+	user.Password = hash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		user.Password = previous
+	}
+}
+
 func (s *authService) RefreshToken(ctx context.Context, req *request.RefreshTokenRequest) (*response.AuthResponse, error) {
+	// A lagging replica could accept a token that was just rotated or
+	// revoked, or miss one that was just issued
+	ctx = repository.ForcePrimary(ctx)
+
 	// Validate the refresh token JWT
 	_, err := s.jwtProvider.ValidateRefreshToken(req.RefreshToken)
 	if err != nil {
@@ -142,52 +224,420 @@ func (s *authService) RefreshToken(ctx context.Context, req *request.RefreshToke
 }
 
 func (s *authService) Logout(ctx context.Context, token string) error {
+	// The token may be an access token, which is revoked until it expires
+	if s.tokenDenylist != nil {
+		if claims, err := s.jwtProvider.ValidateAccessToken(token); err == nil {
+			if err := s.tokenDenylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+				return err
+			}
+		}
+	}
 	return s.refreshTokenRepo.RevokeByToken(ctx, token)
 }
 
 func (s *authService) LogoutAll(ctx context.Context, userID uint) error {
-	return s.refreshTokenRepo.RevokeAllByUserID(ctx, userID)
+	return s.revokeAllSessions(ctx, userID)
 }
 
-func (s *authService) generateAuthResponse(ctx context.Context, user *entity.User) (*response.AuthResponse, error) {
-	// Generate access token
-	accessToken, err := s.jwtProvider.GenerateAccessToken(user)
+func (s *authService) RequestPasswordReset(ctx context.Context, email string) (*service.PasswordReset, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
+	if user == nil || !user.IsActive {
+		return nil, nil
+	}
+
+	// Generate a random token; only its hash is stored
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	resetToken := &entity.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashResetToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+	}
+	if err := s.resetTokenRepo.Create(ctx, resetToken); err != nil {
+		return nil, err
+	}
+
+	return &service.PasswordReset{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: resetToken.ExpiresAt,
+	}, nil
+}
+
+func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	ctx = repository.ForcePrimary(ctx)
+	resetToken, err := s.resetTokenRepo.GetByTokenHash(ctx, hashResetToken(token))
+	if err != nil {
+		return err
+	}
+	if resetToken == nil || !resetToken.IsValid() {
+		return service.ErrInvalidToken
+	}
+
+	// Get the user
+	user, err := s.userRepo.GetByID(ctx, resetToken.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return service.ErrInvalidToken
+	}
+	if !user.IsActive {
+		return service.ErrUserInactive
+	}
+
+	// Consume the token; a concurrent reset with the same token loses here
+	used, err := s.resetTokenRepo.MarkUsed(ctx, resetToken.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return service.ErrInvalidToken
+	}
+
+	// Hash and save the new password
+	hashedPassword, err := s.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// Invalidate other outstanding reset tokens and sign out all sessions
+	if err := s.resetTokenRepo.InvalidateAllByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	return s.revokeAllSessions(ctx, user.ID)
+}
+
+func (s *authService) SendVerification(ctx context.Context, userID uint) (*service.EmailVerification, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, service.ErrUserNotFound
+	}
+	if user.IsVerified {
+		return nil, service.ErrAlreadyVerified
+	}
+
+	token, expiresAt, err := s.jwtProvider.GenerateVerificationToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.EmailVerification{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	ctx = repository.ForcePrimary(ctx)
+	claims, err := s.jwtProvider.ValidateVerificationToken(token)
+	if err != nil {
+		return service.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+	// The token only verifies the address it was sent to
+	if user == nil || user.Email != claims.Email {
+		return service.ErrInvalidToken
+	}
+	if user.IsVerified {
+		return nil
+	}
+
+	user.IsVerified = true
+	return s.userRepo.Update(ctx, user)
+}
+
+func (s *authService) EnableTOTP(ctx context.Context, userID uint) (*service.TOTPSetup, error) {
+	ctx = repository.ForcePrimary(ctx)
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, service.ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, service.ErrTOTPAlreadyEnabled
+	}
 
+	// Starting again replaces any unconfirmed secret
+	key, err := s.totpProvider.GenerateKey(user.Email)
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = key.EncryptedSecret
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &service.TOTPSetup{
+		Secret: key.Secret,
+		URL:    key.URL,
+	}, nil
+}
+
+func (s *authService) ConfirmTOTP(ctx context.Context, userID uint, code string) ([]string, error) {
+	// The secret was usually written moments ago by EnableTOTP
+	ctx = repository.ForcePrimary(ctx)
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, service.ErrUserNotFound
+	}
+	if user.TOTPEnabled {
+		return nil, service.ErrTOTPAlreadyEnabled
+	}
+	if !user.TOTPPending() {
+		return nil, service.ErrTOTPNotPending
+	}
+
+	valid, err := s.totpProvider.Validate(user.TOTPSecret, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, service.ErrInvalidTOTPCode
+	}
+
+	// Only hashes of the recovery codes are stored
+	recoveryCodes, err := security.GenerateRecoveryCodes(totpRecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(recoveryCodes))
+	for i, recoveryCode := range recoveryCodes {
+		hashes[i] = hashRecoveryCode(recoveryCode)
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPRecoveryCodes = strings.Join(hashes, ",")
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+func (s *authService) VerifyTOTP(ctx context.Context, challengeID, code string) (*response.AuthResponse, error) {
+	ctx = repository.ForcePrimary(ctx)
+	claims, err := s.jwtProvider.ValidateTwoFactorChallenge(challengeID)
+	if err != nil {
+		return nil, service.ErrInvalidToken
+	}
+	if s.challengeStore != nil {
+		attempts, err := s.challengeStore.RecordAttempt(ctx, claims.ID, claims.ExpiresAt.Time)
+		if err != nil {
+			return nil, err
+		}
+		if attempts > security.MaxTwoFactorAttempts {
+			return nil, service.ErrInvalidToken
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.TOTPEnabled {
+		return nil, service.ErrInvalidToken
+	}
+	if !user.IsActive {
+		return nil, service.ErrUserInactive
+	}
+
+	valid, err := s.totpProvider.Validate(user.TOTPSecret, code)
+	if err != nil {
+		return nil, err
+	}
+	var hashes []string
+	recoveryIndex := -1
+	if !valid {
+		// Fall back to a recovery code, which is used up below
+		hashes = strings.Split(user.TOTPRecoveryCodes, ",")
+		recoveryIndex = slices.Index(hashes, hashRecoveryCode(code))
+		if user.TOTPRecoveryCodes == "" || recoveryIndex < 0 {
+			return nil, service.ErrInvalidTOTPCode
+		}
+	}
+
+	// A challenge opens a single session, however many valid codes it sees
+	if s.challengeStore != nil {
+		consumed, err := s.challengeStore.Consume(ctx, claims.ID, claims.ExpiresAt.Time)
+		if err != nil {
+			return nil, err
+		}
+		if !consumed {
+			return nil, service.ErrInvalidToken
+		}
+	}
+
+	if recoveryIndex >= 0 {
+		user.TOTPRecoveryCodes = strings.Join(slices.Delete(hashes, recoveryIndex, recoveryIndex+1), ",")
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.generateAuthResponse(ctx, user)
+}
+
+func (s *authService) ListSessions(ctx context.Context, userID uint) ([]*response.SessionResponse, error) {
+	tokens, err := s.refreshTokenRepo.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*response.SessionResponse, len(tokens))
+	for i, token := range tokens {
+		sessions[i] = &response.SessionResponse{
+			ID:        token.ID,
+			Device:    token.Device,
+			UserAgent: token.UserAgent,
+			IPAddress: token.IPAddress,
+			CreatedAt: token.CreatedAt,
+			ExpiresAt: token.ExpiresAt,
+		}
+	}
+	return sessions, nil
+}
+
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID uint) error {
+	// Scoped to the user, so another user's session is indistinguishable from
+	// a missing one
+	revoked, err := s.refreshTokenRepo.RevokeByID(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return service.ErrSessionNotFound
+	}
+	return nil
+}
+
+// revokeAllSessions revokes the user's refresh tokens and, if revocation is
+// enabled, every access token issued so far
+func (s *authService) revokeAllSessions(ctx context.Context, userID uint) error {
+	if err := s.refreshTokenRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+	if s.tokenDenylist == nil {
+		return nil
+	}
+	ttl := time.Duration(s.jwtProvider.GetAccessTokenDuration()) * time.Second
+	return s.tokenDenylist.RevokeAllForUser(ctx, userID, ttl)
+}
+
+// hashRecoveryCode returns the hex SHA-256 hash under which a recovery code is
+// stored, ignoring case and surrounding whitespace
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashResetToken returns the hex SHA-256 hash under which a reset token is stored
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *authService) generateAuthResponse(ctx context.Context, user *entity.User) (*response.AuthResponse, error) {
 	// Generate refresh token
 	refreshTokenString, expiresAt, err := s.jwtProvider.GenerateRefreshToken(user)
 	if err != nil {
 		return nil, err
 	}
 
-	// Save refresh token to database
+	// Save refresh token to database; it identifies the session
+	client := service.ClientInfoFromContext(ctx)
 	refreshToken := &entity.RefreshToken{
 		UserID:    user.ID,
 		Token:     refreshTokenString,
 		ExpiresAt: expiresAt,
+		Device:    deviceFromUserAgent(client.UserAgent),
+		UserAgent: truncate(client.UserAgent, 500),
+		IPAddress: truncate(client.IPAddress, 45),
 	}
 	if err := s.refreshTokenRepo.Create(ctx, refreshToken); err != nil {
 		return nil, err
 	}
 
+	// Generate access token
+	accessToken, err := s.jwtProvider.GenerateSessionAccessToken(user, refreshToken.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &response.AuthResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshTokenString,
 		TokenType:    "Bearer",
 		ExpiresIn:    s.jwtProvider.GetAccessTokenDuration(),
 		User: response.UserResponse{
-			ID:         user.ID,
-			Username:   user.Username,
-			Email:      user.Email,
-			FirstName:  user.FirstName,
-			LastName:   user.LastName,
-			Role:       string(user.Role),
-			IsActive:   user.IsActive,
-			IsVerified: user.IsVerified,
-			CreatedAt:  user.CreatedAt,
-			UpdatedAt:  user.UpdatedAt,
+			ID:          user.ID,
+			Username:    user.Username,
+			Email:       user.Email,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			Role:        string(user.Role),
+			IsActive:    user.IsActive,
+			IsVerified:  user.IsVerified,
+			CreatedAt:   user.CreatedAt,
+			UpdatedAt:   user.UpdatedAt,
+			LockVersion: user.LockVersion,
 		},
 	}, nil
 }
+
+// deviceFromUserAgent gives a short description of the client platform
+func deviceFromUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case ua == "":
+		return ""
+	case strings.Contains(ua, "iphone"):
+		return "iPhone"
+	case strings.Contains(ua, "ipad"):
+		return "iPad"
+	case strings.Contains(ua, "android"):
+		return "Android"
+	case strings.Contains(ua, "windows"):
+		return "Windows"
+	case strings.Contains(ua, "mac os"), strings.Contains(ua, "macintosh"):
+		return "macOS"
+	case strings.Contains(ua, "cros"):
+		return "ChromeOS"
+	case strings.Contains(ua, "linux"):
+		return "Linux"
+	default:
+		return "Other"
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
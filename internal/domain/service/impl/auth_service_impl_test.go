@@ -3,20 +3,33 @@ package impl
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
 )
 
 func setupAuthService(t *testing.T) (service.AuthService, *mocks.MockUserRepository, *mocks.MockRefreshTokenRepository) {
+	authService, userRepo, refreshTokenRepo, _ := setupAuthServiceWithResetTokens(t)
+	return authService, userRepo, refreshTokenRepo
+}
+
+func setupAuthServiceWithResetTokens(t *testing.T) (service.AuthService, *mocks.MockUserRepository, *mocks.MockRefreshTokenRepository, *mocks.MockPasswordResetTokenRepository) {
+	return newTestAuthService(t, nil)
+}
+
+func newTestAuthService(t *testing.T, tokenDenylist security.TokenDenylist) (service.AuthService, *mocks.MockUserRepository, *mocks.MockRefreshTokenRepository, *mocks.MockPasswordResetTokenRepository) {
 	userRepo := mocks.NewMockUserRepository()
 	refreshTokenRepo := mocks.NewMockRefreshTokenRepository()
+	resetTokenRepo := mocks.NewMockPasswordResetTokenRepository()
 
 	jwtConfig := &config.JWTConfig{
 		Secret:               "test-secret-key-for-testing-purposes-only",
@@ -26,9 +39,12 @@ func setupAuthService(t *testing.T) (service.AuthService, *mocks.MockUserReposit
 	}
 	jwtProvider := security.NewJWTProvider(jwtConfig)
 	passwordHasher := security.NewPasswordHasher()
+	totpProvider := security.NewTOTPProvider(&config.TOTPConfig{Issuer: "Test"}, jwtConfig.Secret)
 
-	authService := NewAuthService(userRepo, refreshTokenRepo, jwtProvider, passwordHasher)
-	return authService, userRepo, refreshTokenRepo
+	txManager := mocks.NewMockTxManager(userRepo, refreshTokenRepo)
+
+	authService := NewAuthService(userRepo, refreshTokenRepo, resetTokenRepo, txManager, jwtProvider, passwordHasher, totpProvider, tokenDenylist, mocks.NewMockChallengeStore())
+	return authService, userRepo, refreshTokenRepo, resetTokenRepo
 }
 
 func TestNewAuthService(t *testing.T) {
@@ -185,7 +201,7 @@ func TestAuthService_Register_CreateError(t *testing.T) {
 }
 
 func TestAuthService_Register_RefreshTokenCreateError(t *testing.T) {
-	authService, _, refreshTokenRepo := setupAuthService(t)
+	authService, userRepo, refreshTokenRepo := setupAuthService(t)
 	ctx := context.Background()
 
 	expectedErr := errors.New("refresh token create error")
@@ -203,6 +219,11 @@ func TestAuthService_Register_RefreshTokenCreateError(t *testing.T) {
 	if !errors.Is(err, expectedErr) {
 		t.Errorf("Register() error = %v, want %v", err, expectedErr)
 	}
+
+	// The user insert is rolled back with the failed session
+	if exists, _ := userRepo.ExistsByUsername(ctx, "testuser"); exists {
+		t.Error("Register() left the user behind after the refresh token failed")
+	}
 }
 
 func TestAuthService_Login_Success(t *testing.T) {
@@ -349,6 +370,73 @@ func TestAuthService_Login_LookupError(t *testing.T) {
 	}
 }
 
+func TestAuthService_Login_UpgradesOutdatedPasswordHash(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	jwtConfig := &config.JWTConfig{
+		Secret:               "test-secret-key-for-testing-purposes-only",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 24 * time.Hour,
+		Issuer:               "test",
+	}
+	passwordHasher, err := security.NewPasswordHasherWithConfig(security.HasherConfig{
+		Algorithm:         security.HashAlgorithmArgon2id,
+		Argon2Memory:      1024,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewPasswordHasherWithConfig() error = %v", err)
+	}
+	authService := NewAuthService(userRepo, mocks.NewMockRefreshTokenRepository(), mocks.NewMockPasswordResetTokenRepository(), mocks.NewMockTxManager(),
+		security.NewJWTProvider(jwtConfig), passwordHasher, security.NewTOTPProvider(&config.TOTPConfig{Issuer: "Test"}, jwtConfig.Secret), nil, nil)
+	ctx := context.Background()
+
+	bcryptHasher, _ := security.NewPasswordHasherWithConfig(security.HasherConfig{BcryptCost: 4})
+	oldHash, _ := bcryptHasher.Hash("password123")
+	userRepo.AddUser(&entity.User{Username: "testuser", Email: "test@example.com", Password: oldHash, IsActive: true})
+
+	req := &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"}
+	if _, err := authService.Login(ctx, req); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	user, _ := userRepo.GetByUsername(ctx, "testuser")
+	if !strings.HasPrefix(user.Password, "$argon2id$") {
+		t.Fatalf("password hash = %q, want argon2id", user.Password)
+	}
+	if passwordHasher.NeedsRehash(user.Password) {
+		t.Error("upgraded hash still needs rehash")
+	}
+
+	// The upgraded hash keeps working
+	if _, err := authService.Login(ctx, req); err != nil {
+		t.Fatalf("Login() after upgrade error = %v", err)
+	}
+}
+
+func TestAuthService_Login_HashUpgradeFailureIgnored(t *testing.T) {
+	authService, userRepo, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	bcryptHasher, _ := security.NewPasswordHasherWithConfig(security.HasherConfig{BcryptCost: 4})
+	oldHash, _ := bcryptHasher.Hash("password123")
+	userRepo.AddUser(&entity.User{Username: "testuser", Email: "test@example.com", Password: oldHash, IsActive: true})
+	userRepo.UpdateErr = errors.New("database error")
+
+	resp, err := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if resp.AccessToken == "" {
+		t.Error("Login() AccessToken is empty")
+	}
+
+	user, _ := userRepo.GetByUsername(ctx, "testuser")
+	if user.Password != oldHash {
+		t.Error("password hash changed although the update failed")
+	}
+}
+
 func TestAuthService_RefreshToken_Success(t *testing.T) {
 	authService, userRepo, refreshTokenRepo := setupAuthService(t)
 	ctx := context.Background()
@@ -396,6 +484,67 @@ func TestAuthService_RefreshToken_Success(t *testing.T) {
 	}
 }
 
+// primaryCheckingUserRepo records reads that could be served by a replica
+type primaryCheckingUserRepo struct {
+	*mocks.MockUserRepository
+	replicaReads int
+}
+
+func (r *primaryCheckingUserRepo) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	if !dao.IsPrimaryForced(ctx) {
+		r.replicaReads++
+	}
+	return r.MockUserRepository.GetByID(ctx, id)
+}
+
+func (r *primaryCheckingUserRepo) GetByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (*entity.User, error) {
+	if !dao.IsPrimaryForced(ctx) {
+		r.replicaReads++
+	}
+	return r.MockUserRepository.GetByUsernameOrEmail(ctx, usernameOrEmail)
+}
+
+type primaryCheckingRefreshTokenRepo struct {
+	*mocks.MockRefreshTokenRepository
+	replicaReads int
+}
+
+func (r *primaryCheckingRefreshTokenRepo) GetByToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+	if !dao.IsPrimaryForced(ctx) {
+		r.replicaReads++
+	}
+	return r.MockRefreshTokenRepository.GetByToken(ctx, token)
+}
+
+func TestAuthService_LoginAndRefresh_ReadPrimary(t *testing.T) {
+	userRepo := &primaryCheckingUserRepo{MockUserRepository: mocks.NewMockUserRepository()}
+	refreshTokenRepo := &primaryCheckingRefreshTokenRepo{MockRefreshTokenRepository: mocks.NewMockRefreshTokenRepository()}
+	jwtConfig := &config.JWTConfig{
+		Secret:               "test-secret-key-for-testing-purposes-only",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 24 * time.Hour,
+		Issuer:               "test",
+	}
+	authService := NewAuthService(userRepo, refreshTokenRepo, mocks.NewMockPasswordResetTokenRepository(), mocks.NewMockTxManager(),
+		security.NewJWTProvider(jwtConfig), security.NewPasswordHasher(), security.NewTOTPProvider(&config.TOTPConfig{Issuer: "Test"}, jwtConfig.Secret), nil, nil)
+	ctx := context.Background()
+
+	hashedPassword, _ := security.NewPasswordHasher().Hash("password123")
+	userRepo.AddUser(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", Password: hashedPassword, IsActive: true})
+
+	resp, err := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if _, err := authService.RefreshToken(ctx, &request.RefreshTokenRequest{RefreshToken: resp.RefreshToken}); err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	if userRepo.replicaReads != 0 || refreshTokenRepo.replicaReads != 0 {
+		t.Errorf("replica reads = %d users, %d refresh tokens, want none", userRepo.replicaReads, refreshTokenRepo.replicaReads)
+	}
+}
+
 func TestAuthService_RefreshToken_InvalidToken(t *testing.T) {
 	authService, _, _ := setupAuthService(t)
 	ctx := context.Background()
@@ -683,6 +832,115 @@ func TestAuthService_RefreshToken_GetUserError(t *testing.T) {
 	}
 }
 
+func TestAuthService_Sessions(t *testing.T) {
+	authService, userRepo, refreshTokenRepo := setupAuthService(t)
+	ctx := context.Background()
+
+	hashedPassword, _ := security.NewPasswordHasher().Hash("password123")
+	userRepo.AddUser(&entity.User{Username: "alice", Email: "alice@example.com", Password: hashedPassword, IsActive: true})
+	userRepo.AddUser(&entity.User{Username: "bob", Email: "bob@example.com", Password: hashedPassword, IsActive: true})
+
+	phone := service.ContextWithClientInfo(ctx, service.ClientInfo{
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)",
+		IPAddress: "203.0.113.7",
+	})
+	resp, err := authService.Login(phone, &request.LoginRequest{UsernameOrEmail: "alice", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if _, err := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "alice", Password: "password123"}); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	bobResp, err := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "bob", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	alice, _ := userRepo.GetByUsername(ctx, "alice")
+	sessions, err := authService.ListSessions(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("ListSessions() returned %d sessions, want 2", len(sessions))
+	}
+
+	// The access token identifies the session it was issued with
+	claims, err := security.NewJWTProvider(&config.JWTConfig{Secret: "test-secret-key-for-testing-purposes-only"}).ValidateAccessToken(resp.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	var phoneSession *response.SessionResponse
+	for _, session := range sessions {
+		if session.ID == claims.SessionID {
+			phoneSession = session
+		}
+	}
+	if phoneSession == nil {
+		t.Fatalf("no session with the access token's session ID %d", claims.SessionID)
+	}
+	if phoneSession.Device != "iPhone" || phoneSession.IPAddress != "203.0.113.7" || phoneSession.UserAgent == "" {
+		t.Errorf("session = %+v, want client details recorded", phoneSession)
+	}
+
+	// Another user's session looks like a missing one
+	bobClaims, _ := security.NewJWTProvider(&config.JWTConfig{Secret: "test-secret-key-for-testing-purposes-only"}).ValidateAccessToken(bobResp.AccessToken)
+	if err := authService.RevokeSession(ctx, alice.ID, bobClaims.SessionID); !errors.Is(err, service.ErrSessionNotFound) {
+		t.Errorf("RevokeSession() of another user's session error = %v, want ErrSessionNotFound", err)
+	}
+
+	if err := authService.RevokeSession(ctx, alice.ID, phoneSession.ID); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if err := authService.RevokeSession(ctx, alice.ID, phoneSession.ID); !errors.Is(err, service.ErrSessionNotFound) {
+		t.Errorf("RevokeSession() twice error = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := authService.RefreshToken(ctx, &request.RefreshTokenRequest{RefreshToken: resp.RefreshToken}); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("RefreshToken() of revoked session error = %v, want ErrInvalidToken", err)
+	}
+
+	sessions, _ = authService.ListSessions(ctx, alice.ID)
+	if len(sessions) != 1 {
+		t.Errorf("ListSessions() after revoke returned %d sessions, want 1", len(sessions))
+	}
+	if tokens, _ := refreshTokenRepo.ListActiveByUserID(ctx, alice.ID); len(tokens) != 1 {
+		t.Errorf("active refresh tokens = %d, want 1", len(tokens))
+	}
+}
+
+func TestAuthService_Sessions_RepositoryErrors(t *testing.T) {
+	authService, _, refreshTokenRepo := setupAuthService(t)
+	ctx := context.Background()
+	expectedErr := errors.New("database error")
+
+	refreshTokenRepo.ListActiveErr = expectedErr
+	if _, err := authService.ListSessions(ctx, 1); !errors.Is(err, expectedErr) {
+		t.Errorf("ListSessions() error = %v, want %v", err, expectedErr)
+	}
+
+	refreshTokenRepo.RevokeByIDErr = expectedErr
+	if err := authService.RevokeSession(ctx, 1, 1); !errors.Is(err, expectedErr) {
+		t.Errorf("RevokeSession() error = %v, want %v", err, expectedErr)
+	}
+}
+
+func TestDeviceFromUserAgent(t *testing.T) {
+	tests := map[string]string{
+		"": "",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15": "iPhone",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36":                 "Android",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36":                "Windows",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/605.1.15":           "macOS",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0":      "Linux",
+		"curl/8.4.0": "Other",
+	}
+	for userAgent, want := range tests {
+		if got := deviceFromUserAgent(userAgent); got != want {
+			t.Errorf("deviceFromUserAgent(%q) = %q, want %q", userAgent, got, want)
+		}
+	}
+}
+
 func TestAuthService_Logout(t *testing.T) {
 	authService, _, refreshTokenRepo := setupAuthService(t)
 	ctx := context.Background()
@@ -701,6 +959,50 @@ func TestAuthService_Logout(t *testing.T) {
 	}
 }
 
+func TestAuthService_Logout_RevokesAccessToken(t *testing.T) {
+	denylist := mocks.NewMockTokenDenylist()
+	authService, userRepo, _, _ := newTestAuthService(t, denylist)
+	ctx := context.Background()
+
+	hashedPassword, _ := security.NewPasswordHasher().Hash("password123")
+	userRepo.AddUser(&entity.User{Username: "testuser", Email: "test@example.com", Password: hashedPassword, IsActive: true})
+	resp, err := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if err := authService.Logout(ctx, resp.AccessToken); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	revoked := denylist.RevokedTokens()
+	if len(revoked) != 1 {
+		t.Fatalf("revoked %d tokens, want 1", len(revoked))
+	}
+	for _, expiresAt := range revoked {
+		if time.Until(expiresAt) <= 0 {
+			t.Errorf("revocation expires at %v, want the token expiry", expiresAt)
+		}
+	}
+}
+
+func TestAuthService_LogoutAll_RevokesAccessTokens(t *testing.T) {
+	denylist := mocks.NewMockTokenDenylist()
+	authService, _, _, _ := newTestAuthService(t, denylist)
+
+	if err := authService.LogoutAll(context.Background(), 1); err != nil {
+		t.Fatalf("LogoutAll() error = %v", err)
+	}
+	ttl, ok := denylist.UserRevocationTTL(1)
+	if !ok || ttl != 15*time.Minute {
+		t.Errorf("user revocation TTL = %v, %v, want the access token lifetime", ttl, ok)
+	}
+
+	denylist.RevokeErr = errors.New("redis down")
+	if err := authService.LogoutAll(context.Background(), 1); err == nil {
+		t.Error("LogoutAll() should return the denylist error")
+	}
+}
+
 func TestAuthService_Logout_Error(t *testing.T) {
 	authService, _, refreshTokenRepo := setupAuthService(t)
 	ctx := context.Background()
@@ -751,6 +1053,352 @@ func TestAuthService_LogoutAll_Error(t *testing.T) {
 	}
 }
 
+func TestAuthService_RequestPasswordReset_Success(t *testing.T) {
+	authService, userRepo, _, resetTokenRepo := setupAuthServiceWithResetTokens(t)
+	ctx := context.Background()
+
+	userRepo.AddUser(&entity.User{ID: 1, Username: "resetuser", Email: "reset@example.com", IsActive: true})
+
+	reset, err := authService.RequestPasswordReset(ctx, "reset@example.com")
+	if err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	if reset == nil || reset.Token == "" {
+		t.Fatal("RequestPasswordReset() should return a token")
+	}
+	if reset.Email != "reset@example.com" || reset.UserID != 1 {
+		t.Errorf("RequestPasswordReset() = %+v, want the user's email and ID", reset)
+	}
+	if time.Until(reset.ExpiresAt) > passwordResetTokenTTL {
+		t.Errorf("ExpiresAt = %v, want within %v", reset.ExpiresAt, passwordResetTokenTTL)
+	}
+
+	tokens := resetTokenRepo.Tokens()
+	if len(tokens) != 1 {
+		t.Fatalf("stored %d reset tokens, want 1", len(tokens))
+	}
+	if tokens[0].TokenHash == reset.Token || tokens[0].TokenHash != hashResetToken(reset.Token) {
+		t.Error("only the hash of the reset token should be stored")
+	}
+}
+
+func TestAuthService_RequestPasswordReset_UnknownOrInactiveUser(t *testing.T) {
+	authService, userRepo, _, resetTokenRepo := setupAuthServiceWithResetTokens(t)
+	ctx := context.Background()
+
+	userRepo.AddUser(&entity.User{ID: 1, Username: "inactive", Email: "inactive@example.com", IsActive: false})
+
+	for _, email := range []string{"nobody@example.com", "inactive@example.com"} {
+		reset, err := authService.RequestPasswordReset(ctx, email)
+		if err != nil {
+			t.Errorf("RequestPasswordReset(%s) error = %v", email, err)
+		}
+		if reset != nil {
+			t.Errorf("RequestPasswordReset(%s) should not issue a token", email)
+		}
+	}
+	if len(resetTokenRepo.Tokens()) != 0 {
+		t.Error("no reset tokens should be stored")
+	}
+}
+
+func TestAuthService_ResetPassword_Success(t *testing.T) {
+	authService, userRepo, refreshTokenRepo, _ := setupAuthServiceWithResetTokens(t)
+	ctx := context.Background()
+
+	hasher := security.NewPasswordHasher()
+	oldHash, _ := hasher.Hash("oldpassword")
+	userRepo.AddUser(&entity.User{ID: 1, Username: "resetuser", Email: "reset@example.com", Password: oldHash, IsActive: true})
+	refreshTokenRepo.AddToken(&entity.RefreshToken{UserID: 1, Token: "session-token", ExpiresAt: time.Now().Add(time.Hour)})
+
+	first, _ := authService.RequestPasswordReset(ctx, "reset@example.com")
+	reset, _ := authService.RequestPasswordReset(ctx, "reset@example.com")
+
+	if err := authService.ResetPassword(ctx, reset.Token, "newpassword123"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+
+	user, _ := userRepo.GetByID(ctx, 1)
+	if !hasher.Verify("newpassword123", user.Password) {
+		t.Error("ResetPassword() should set the new password")
+	}
+	if rt, _ := refreshTokenRepo.GetByToken(ctx, "session-token"); rt != nil {
+		t.Error("ResetPassword() should revoke all refresh tokens")
+	}
+
+	// The token is single use, and earlier tokens are invalidated
+	if err := authService.ResetPassword(ctx, reset.Token, "anotherpassword"); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("reusing a reset token error = %v, want ErrInvalidToken", err)
+	}
+	if err := authService.ResetPassword(ctx, first.Token, "anotherpassword"); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("older reset token error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthService_ResetPassword_InvalidToken(t *testing.T) {
+	authService, userRepo, _, resetTokenRepo := setupAuthServiceWithResetTokens(t)
+	ctx := context.Background()
+
+	userRepo.AddUser(&entity.User{ID: 1, Username: "resetuser", Email: "reset@example.com", IsActive: true})
+	_ = resetTokenRepo.Create(ctx, &entity.PasswordResetToken{
+		UserID:    1,
+		TokenHash: hashResetToken("expired-token"),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+
+	for _, token := range []string{"unknown-token", "expired-token"} {
+		if err := authService.ResetPassword(ctx, token, "newpassword123"); !errors.Is(err, service.ErrInvalidToken) {
+			t.Errorf("ResetPassword(%s) error = %v, want ErrInvalidToken", token, err)
+		}
+	}
+}
+
+func TestAuthService_ResetPassword_InactiveUser(t *testing.T) {
+	authService, userRepo, _, resetTokenRepo := setupAuthServiceWithResetTokens(t)
+	ctx := context.Background()
+
+	userRepo.AddUser(&entity.User{ID: 1, Username: "inactive", Email: "inactive@example.com", IsActive: false})
+	_ = resetTokenRepo.Create(ctx, &entity.PasswordResetToken{
+		UserID:    1,
+		TokenHash: hashResetToken("reset-token"),
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	if err := authService.ResetPassword(ctx, "reset-token", "newpassword123"); !errors.Is(err, service.ErrUserInactive) {
+		t.Errorf("ResetPassword() error = %v, want ErrUserInactive", err)
+	}
+}
+
+func TestAuthService_EmailVerification(t *testing.T) {
+	authService, userRepo, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	userRepo.AddUser(&entity.User{ID: 1, Username: "newuser", Email: "new@example.com", IsActive: true})
+
+	verification, err := authService.SendVerification(ctx, 1)
+	if err != nil {
+		t.Fatalf("SendVerification() error = %v", err)
+	}
+	if verification.Token == "" || verification.Email != "new@example.com" {
+		t.Errorf("SendVerification() = %+v, want a token for new@example.com", verification)
+	}
+
+	if err := authService.VerifyEmail(ctx, verification.Token); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	user, _ := userRepo.GetByID(ctx, 1)
+	if !user.IsVerified {
+		t.Error("VerifyEmail() should mark the user as verified")
+	}
+
+	// Verifying again is harmless; requesting another link is not needed
+	if err := authService.VerifyEmail(ctx, verification.Token); err != nil {
+		t.Errorf("VerifyEmail() again error = %v", err)
+	}
+	if _, err := authService.SendVerification(ctx, 1); !errors.Is(err, service.ErrAlreadyVerified) {
+		t.Errorf("SendVerification() error = %v, want ErrAlreadyVerified", err)
+	}
+}
+
+func TestAuthService_SendVerification_UserNotFound(t *testing.T) {
+	authService, _, _ := setupAuthService(t)
+
+	if _, err := authService.SendVerification(context.Background(), 99); !errors.Is(err, service.ErrUserNotFound) {
+		t.Errorf("SendVerification() error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestAuthService_VerifyEmail_InvalidToken(t *testing.T) {
+	authService, userRepo, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	userRepo.AddUser(&entity.User{ID: 1, Username: "newuser", Email: "old@example.com", IsActive: true})
+	verification, _ := authService.SendVerification(ctx, 1)
+
+	if err := authService.VerifyEmail(ctx, "not-a-token"); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("VerifyEmail(garbage) error = %v, want ErrInvalidToken", err)
+	}
+
+	// A token sent to a previous address does not verify the new one
+	user, _ := userRepo.GetByID(ctx, 1)
+	user.Email = "new@example.com"
+	if err := authService.VerifyEmail(ctx, verification.Token); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("VerifyEmail(old address) error = %v, want ErrInvalidToken", err)
+	}
+	if user.IsVerified {
+		t.Error("user should remain unverified")
+	}
+}
+
+// enableTOTP enrolls user 1 in two-factor authentication and returns the
+// plain secret and recovery codes
+func enableTOTP(t *testing.T, authService service.AuthService) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	setup, err := authService.EnableTOTP(ctx, 1)
+	if err != nil {
+		t.Fatalf("EnableTOTP() error = %v", err)
+	}
+	code, _ := security.GenerateTOTPCode(setup.Secret, time.Now())
+	recoveryCodes, err := authService.ConfirmTOTP(ctx, 1, code)
+	if err != nil {
+		t.Fatalf("ConfirmTOTP() error = %v", err)
+	}
+	return setup.Secret, recoveryCodes
+}
+
+func TestAuthService_EnableTOTP(t *testing.T) {
+	authService, userRepo, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	if _, err := authService.EnableTOTP(ctx, 1); !errors.Is(err, service.ErrUserNotFound) {
+		t.Errorf("EnableTOTP(unknown user) error = %v, want ErrUserNotFound", err)
+	}
+
+	userRepo.AddUser(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", IsActive: true})
+	if _, err := authService.ConfirmTOTP(ctx, 1, "123456"); !errors.Is(err, service.ErrTOTPNotPending) {
+		t.Errorf("ConfirmTOTP() before EnableTOTP error = %v, want ErrTOTPNotPending", err)
+	}
+
+	setup, err := authService.EnableTOTP(ctx, 1)
+	if err != nil {
+		t.Fatalf("EnableTOTP() error = %v", err)
+	}
+	if setup.Secret == "" || !strings.HasPrefix(setup.URL, "otpauth://totp/") {
+		t.Errorf("EnableTOTP() = %+v", setup)
+	}
+
+	user, _ := userRepo.GetByID(ctx, 1)
+	if user.TOTPEnabled || user.TOTPSecret == "" || user.TOTPSecret == setup.Secret {
+		t.Error("secret should be stored encrypted and not yet enabled")
+	}
+
+	if _, err := authService.ConfirmTOTP(ctx, 1, "000000"); !errors.Is(err, service.ErrInvalidTOTPCode) {
+		t.Errorf("ConfirmTOTP(wrong code) error = %v, want ErrInvalidTOTPCode", err)
+	}
+
+	code, _ := security.GenerateTOTPCode(setup.Secret, time.Now())
+	recoveryCodes, err := authService.ConfirmTOTP(ctx, 1, code)
+	if err != nil {
+		t.Fatalf("ConfirmTOTP() error = %v", err)
+	}
+	if len(recoveryCodes) != totpRecoveryCodeCount {
+		t.Errorf("len(recoveryCodes) = %d, want %d", len(recoveryCodes), totpRecoveryCodeCount)
+	}
+	if !user.TOTPEnabled {
+		t.Error("TOTPEnabled should be true")
+	}
+	if strings.Contains(user.TOTPRecoveryCodes, recoveryCodes[0]) {
+		t.Error("recovery codes must be stored hashed")
+	}
+
+	if _, err := authService.EnableTOTP(ctx, 1); !errors.Is(err, service.ErrTOTPAlreadyEnabled) {
+		t.Errorf("EnableTOTP() when enabled error = %v, want ErrTOTPAlreadyEnabled", err)
+	}
+}
+
+func TestAuthService_Login_TwoFactorChallenge(t *testing.T) {
+	authService, userRepo, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	hashedPassword, _ := security.NewPasswordHasher().Hash("password123")
+	userRepo.AddUser(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", Password: hashedPassword, IsActive: true})
+	secret, _ := enableTOTP(t, authService)
+
+	resp, err := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !resp.TwoFactorRequired || resp.ChallengeID == "" {
+		t.Fatalf("Login() = %+v, want a two-factor challenge", resp)
+	}
+	if resp.AccessToken != "" || resp.RefreshToken != "" {
+		t.Error("no tokens should be issued before the second factor")
+	}
+
+	if _, err := authService.VerifyTOTP(ctx, resp.ChallengeID, "000000"); !errors.Is(err, service.ErrInvalidTOTPCode) {
+		t.Errorf("VerifyTOTP(wrong code) error = %v, want ErrInvalidTOTPCode", err)
+	}
+	if _, err := authService.VerifyTOTP(ctx, "not-a-challenge", "000000"); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("VerifyTOTP(bad challenge) error = %v, want ErrInvalidToken", err)
+	}
+
+	code, _ := security.GenerateTOTPCode(secret, time.Now())
+	authResp, err := authService.VerifyTOTP(ctx, resp.ChallengeID, code)
+	if err != nil {
+		t.Fatalf("VerifyTOTP() error = %v", err)
+	}
+	if authResp.AccessToken == "" || authResp.RefreshToken == "" {
+		t.Error("VerifyTOTP() should issue tokens")
+	}
+}
+
+func TestAuthService_VerifyTOTP_RecoveryCode(t *testing.T) {
+	authService, userRepo, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	hashedPassword, _ := security.NewPasswordHasher().Hash("password123")
+	userRepo.AddUser(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", Password: hashedPassword, IsActive: true})
+	_, recoveryCodes := enableTOTP(t, authService)
+
+	resp, _ := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+
+	// Recovery codes are case-insensitive
+	if _, err := authService.VerifyTOTP(ctx, resp.ChallengeID, strings.ToUpper(recoveryCodes[0])); err != nil {
+		t.Fatalf("VerifyTOTP(recovery code) error = %v", err)
+	}
+
+	// and single-use
+	resp, _ = authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+	if _, err := authService.VerifyTOTP(ctx, resp.ChallengeID, recoveryCodes[0]); !errors.Is(err, service.ErrInvalidTOTPCode) {
+		t.Errorf("VerifyTOTP(used recovery code) error = %v, want ErrInvalidTOTPCode", err)
+	}
+	if _, err := authService.VerifyTOTP(ctx, resp.ChallengeID, recoveryCodes[1]); err != nil {
+		t.Errorf("VerifyTOTP(another recovery code) error = %v", err)
+	}
+}
+
+func TestAuthService_VerifyTOTP_ChallengeSingleUse(t *testing.T) {
+	authService, userRepo, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	hashedPassword, _ := security.NewPasswordHasher().Hash("password123")
+	userRepo.AddUser(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", Password: hashedPassword, IsActive: true})
+	secret, _ := enableTOTP(t, authService)
+
+	resp, _ := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+	code, _ := security.GenerateTOTPCode(secret, time.Now())
+	if _, err := authService.VerifyTOTP(ctx, resp.ChallengeID, code); err != nil {
+		t.Fatalf("VerifyTOTP() error = %v", err)
+	}
+	if _, err := authService.VerifyTOTP(ctx, resp.ChallengeID, code); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("VerifyTOTP(replayed challenge) error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthService_VerifyTOTP_AttemptLimit(t *testing.T) {
+	authService, userRepo, _ := setupAuthService(t)
+	ctx := context.Background()
+
+	hashedPassword, _ := security.NewPasswordHasher().Hash("password123")
+	userRepo.AddUser(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", Password: hashedPassword, IsActive: true})
+	secret, _ := enableTOTP(t, authService)
+
+	resp, _ := authService.Login(ctx, &request.LoginRequest{UsernameOrEmail: "testuser", Password: "password123"})
+	for i := 0; i < security.MaxTwoFactorAttempts; i++ {
+		if _, err := authService.VerifyTOTP(ctx, resp.ChallengeID, "000000"); !errors.Is(err, service.ErrInvalidTOTPCode) {
+			t.Fatalf("VerifyTOTP(wrong code) attempt %d error = %v, want ErrInvalidTOTPCode", i+1, err)
+		}
+	}
+
+	// Once the attempts are used up even the right code is refused
+	code, _ := security.GenerateTOTPCode(secret, time.Now())
+	if _, err := authService.VerifyTOTP(ctx, resp.ChallengeID, code); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("VerifyTOTP(exhausted challenge) error = %v, want ErrInvalidToken", err)
+	}
+}
+
 // Error constants tests
 func TestServiceErrors(t *testing.T) {
 	tests := []struct {
@@ -763,6 +1411,8 @@ func TestServiceErrors(t *testing.T) {
 		{"ErrUserAlreadyExists", service.ErrUserAlreadyExists, "user already exists"},
 		{"ErrInvalidToken", service.ErrInvalidToken, "invalid or expired token"},
 		{"ErrUserInactive", service.ErrUserInactive, "user account is inactive"},
+		{"ErrAlreadyVerified", service.ErrAlreadyVerified, "email already verified"},
+		{"ErrInvalidTOTPCode", service.ErrInvalidTOTPCode, "invalid two-factor authentication code"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,227 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// DefaultPluginDownloadTimeout bounds a download attempt when no timeout is configured
+const DefaultPluginDownloadTimeout = 60 * time.Second
+
+// maxPluginDownloadRedirects caps the redirects followed for one download
+const maxPluginDownloadRedirects = 10
+
+// PluginDownloadConfig configures InstallFromURL
+type PluginDownloadConfig struct {
+	// MaxSize caps the artifact size in bytes; zero means no limit
+	MaxSize int64
+	// Timeout bounds each download attempt; zero uses DefaultPluginDownloadTimeout
+	Timeout time.Duration
+	// AllowedHosts restricts downloads, including redirects, to these hosts.
+	// An entry "*.example.com" matches any subdomain. Empty allows any host.
+	AllowedHosts []string
+	// PublicKey verifies artifact signatures. When set, every download must
+	// carry a valid Ed25519 signature of the artifact.
+	PublicKey ed25519.PublicKey
+	// Retry configures retries of transient failures; nil uses
+	// resilience.DefaultRetryConfig
+	Retry *resilience.RetryConfig
+	// Transport overrides the HTTP transport
+	Transport http.RoundTripper
+}
+
+// pluginDownloader fetches plugin artifacts for InstallFromURL
+type pluginDownloader struct {
+	config PluginDownloadConfig
+	client *http.Client
+}
+
+// transientError marks a download failure worth retrying
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+func newPluginDownloader(config PluginDownloadConfig) *pluginDownloader {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultPluginDownloadTimeout
+	}
+	if config.Retry == nil {
+		config.Retry = resilience.DefaultRetryConfig()
+	}
+
+	d := &pluginDownloader{config: config}
+	d.client = &http.Client{
+		Transport: config.Transport,
+		Timeout:   config.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxPluginDownloadRedirects {
+				return fmt.Errorf("%w: too many redirects", service.ErrPluginDownloadFailed)
+			}
+			return d.checkURL(req.URL)
+		},
+	}
+	return d
+}
+
+func (s *pluginService) InstallFromURL(ctx context.Context, req *request.InstallPluginFromURLRequest) (*response.PluginResponse, error) {
+	data, err := s.downloader.download(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.downloader.verify(data, req.Checksum, req.Signature); err != nil {
+		return nil, err
+	}
+
+	return s.Install(ctx, &req.InstallPluginRequest, bytes.NewReader(data))
+}
+
+// download fetches an artifact, retrying transient failures. The artifact is
+// held in memory, which MaxSize bounds.
+func (d *pluginDownloader) download(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrPluginInvalidURL, err)
+	}
+	if err := d.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	var permanent error
+	err = resilience.Retry(ctx, d.config.Retry, func(ctx context.Context) error {
+		fetched, err := d.fetch(ctx, u.String())
+		var transient *transientError
+		if err != nil && !errors.As(err, &transient) {
+			// Stop retrying: Retry treats every error as retryable
+			permanent = err
+			return nil
+		}
+		data = fetched
+		return err
+	})
+	if permanent != nil {
+		return nil, permanent
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrPluginDownloadFailed, err)
+	}
+	return data, nil
+}
+
+// fetch makes a single download attempt
+func (d *pluginDownloader) fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrPluginInvalidURL, err)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		// A refused redirect is final; anything else may be a network blip
+		if errors.Is(err, service.ErrPluginHostNotAllowed) || errors.Is(err, service.ErrPluginDownloadFailed) {
+			return nil, err
+		}
+		return nil, &transientError{err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, &transientError{err: fmt.Errorf("unexpected status %s", resp.Status)}
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: unexpected status %s", service.ErrPluginDownloadFailed, resp.Status)
+	}
+
+	maxSize := d.config.MaxSize
+	if maxSize > 0 && resp.ContentLength > maxSize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the %d byte limit", service.ErrPluginTooLarge, resp.ContentLength, maxSize)
+	}
+
+	body := io.Reader(resp.Body)
+	if maxSize > 0 {
+		body = io.LimitReader(resp.Body, maxSize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, &transientError{err: err}
+	}
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: exceeds the %d byte limit", service.ErrPluginTooLarge, maxSize)
+	}
+	return data, nil
+}
+
+// checkURL accepts http(s) URLs to allowed hosts
+func (d *pluginDownloader) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: unsupported scheme %q", service.ErrPluginInvalidURL, u.Scheme)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: missing host", service.ErrPluginInvalidURL)
+	}
+	if len(d.config.AllowedHosts) == 0 {
+		return nil
+	}
+
+	for _, allowed := range d.config.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return nil
+			}
+			continue
+		}
+		if host == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", service.ErrPluginHostNotAllowed, host)
+}
+
+// verify checks an artifact against its expected checksum, if given, and
+// its signature when a signing key is configured
+func (d *pluginDownloader) verify(data []byte, checksum, signature string) error {
+	if checksum != "" {
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), checksum) {
+			return fmt.Errorf("%w: checksum mismatch", service.ErrPluginVerificationFailed)
+		}
+	}
+
+	if len(d.config.PublicKey) == 0 {
+		if signature != "" {
+			return fmt.Errorf("%w: no signing key configured", service.ErrPluginVerificationFailed)
+		}
+		return nil
+	}
+
+	if signature == "" {
+		return fmt.Errorf("%w: signature required", service.ErrPluginVerificationFailed)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !ed25519.Verify(d.config.PublicKey, data, sig) {
+		return fmt.Errorf("%w: invalid signature", service.ErrPluginVerificationFailed)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package impl
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
+)
+
+var testArtifact = []byte("plugin artifact")
+
+func testArtifactChecksum() string {
+	sum := sha256.Sum256(testArtifact)
+	return hex.EncodeToString(sum[:])
+}
+
+// setupDownloadService creates a plugin service whose downloads retry quickly
+func setupDownloadService(t *testing.T, download PluginDownloadConfig) (service.PluginService, string) {
+	t.Helper()
+	download.Retry = &resilience.RetryConfig{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		Multiplier:      1,
+	}
+	pluginsDir := t.TempDir()
+	pluginService := NewPluginServiceWithOptions(mocks.NewMockPluginRepository(), mocks.NewMockPluginExtensionRepository(), mocks.NewMockPluginStateTransitionRepository(), mocks.NewMockTxManager(), pluginsDir, PluginServiceOptions{Download: download})
+	return pluginService, pluginsDir
+}
+
+func newURLInstallRequest(url string) *request.InstallPluginFromURLRequest {
+	return &request.InstallPluginFromURLRequest{
+		InstallPluginRequest: request.InstallPluginRequest{
+			Name:    "Remote Plugin",
+			Version: "1.0.0",
+			Type:    "SERVICE",
+		},
+		URL: url,
+	}
+}
+
+func TestPluginService_InstallFromURL_Success(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt hits a transient failure
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(testArtifact)
+	}))
+	defer server.Close()
+
+	pluginService, pluginsDir := setupDownloadService(t, PluginDownloadConfig{MaxSize: 1024})
+	req := newURLInstallRequest(server.URL + "/plugin.so")
+	req.Checksum = testArtifactChecksum()
+
+	resp, err := pluginService.InstallFromURL(context.Background(), req)
+	if err != nil {
+		t.Fatalf("InstallFromURL() error = %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("InstallFromURL() attempts = %d, want 2", attempts.Load())
+	}
+
+	data, err := os.ReadFile(filepath.Join(pluginsDir, resp.Key+".so"))
+	if err != nil || string(data) != string(testArtifact) {
+		t.Errorf("InstallFromURL() file = %q, %v", data, err)
+	}
+}
+
+func TestPluginService_InstallFromURL_NotRetried(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	pluginService, _ := setupDownloadService(t, PluginDownloadConfig{})
+
+	_, err := pluginService.InstallFromURL(context.Background(), newURLInstallRequest(server.URL))
+	if !errors.Is(err, service.ErrPluginDownloadFailed) {
+		t.Errorf("InstallFromURL() error = %v, want %v", err, service.ErrPluginDownloadFailed)
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("InstallFromURL() attempts = %d, want 1", attempts.Load())
+	}
+}
+
+func TestPluginService_InstallFromURL_TooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			// Flushing forces a chunked response with no Content-Length
+			w.Write(testArtifact[:4])
+			w.(http.Flusher).Flush()
+		}
+		w.Write(testArtifact)
+	}))
+	defer server.Close()
+
+	pluginService, pluginsDir := setupDownloadService(t, PluginDownloadConfig{MaxSize: 8})
+
+	for _, path := range []string{"/sized", "/chunked"} {
+		_, err := pluginService.InstallFromURL(context.Background(), newURLInstallRequest(server.URL+path))
+		if !errors.Is(err, service.ErrPluginTooLarge) {
+			t.Errorf("InstallFromURL(%s) error = %v, want %v", path, err, service.ErrPluginTooLarge)
+		}
+	}
+	if entries, _ := os.ReadDir(pluginsDir); len(entries) != 0 {
+		t.Error("InstallFromURL() should not install an oversized plugin")
+	}
+}
+
+func TestPluginService_InstallFromURL_AllowedHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://evil.test/plugin.so", http.StatusFound)
+			return
+		}
+		w.Write(testArtifact)
+	}))
+	defer server.Close()
+
+	pluginService, _ := setupDownloadService(t, PluginDownloadConfig{AllowedHosts: []string{"127.0.0.1", "*.example.com"}})
+	ctx := context.Background()
+
+	if _, err := pluginService.InstallFromURL(ctx, newURLInstallRequest(server.URL)); err != nil {
+		t.Errorf("InstallFromURL() allowed host error = %v", err)
+	}
+
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{"http://localhost:1/plugin.so", service.ErrPluginHostNotAllowed},
+		{"https://example.com.evil.test/plugin.so", service.ErrPluginHostNotAllowed},
+		{server.URL + "/redirect", service.ErrPluginHostNotAllowed},
+		{"ftp://repo.example.com/plugin.so", service.ErrPluginInvalidURL},
+	}
+	for _, tt := range tests {
+		_, err := pluginService.InstallFromURL(ctx, newURLInstallRequest(tt.url))
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("InstallFromURL(%s) error = %v, want %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPluginService_InstallFromURL_Verification(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testArtifact)
+	}))
+	defer server.Close()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	validSignature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, testArtifact))
+	otherSignature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte("other")))
+
+	tests := []struct {
+		name      string
+		publicKey ed25519.PublicKey
+		checksum  string
+		signature string
+		wantErr   error
+	}{
+		{"checksum match", nil, strings.ToUpper(testArtifactChecksum()), "", nil},
+		{"checksum mismatch", nil, strings.Repeat("0", 64), "", service.ErrPluginVerificationFailed},
+		{"signature without key", nil, "", validSignature, service.ErrPluginVerificationFailed},
+		{"valid signature", publicKey, "", validSignature, nil},
+		{"missing signature", publicKey, "", "", service.ErrPluginVerificationFailed},
+		{"wrong signature", publicKey, "", otherSignature, service.ErrPluginVerificationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pluginService, _ := setupDownloadService(t, PluginDownloadConfig{PublicKey: tt.publicKey})
+			req := newURLInstallRequest(server.URL)
+			req.Checksum = tt.checksum
+			req.Signature = tt.signature
+
+			_, err := pluginService.InstallFromURL(context.Background(), req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("InstallFromURL() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,29 +21,91 @@ import (
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/plugin/schema"
 )
 
 // pluginService implements service.PluginService
 type pluginService struct {
-	pluginRepo    repository.PluginRepository
-	extensionRepo repository.PluginExtensionRepository
-	pluginsDir    string
+	pluginRepo     repository.PluginRepository
+	extensionRepo  repository.PluginExtensionRepository
+	transitionRepo repository.PluginStateTransitionRepository
+	txManager      repository.TxManager
+	pluginsDir     string
+	reloader       service.PluginReloader
+	routes         service.PluginRouteRegistrar
+	hooks          service.PluginHookRunner
+	downloader     *pluginDownloader
+
+	// configLocks serializes config updates per plugin key, so reloads are
+	// applied in the order the updates were saved
+	configLocks sync.Map
+}
+
+// PluginServiceOptions holds optional PluginService settings
+type PluginServiceOptions struct {
+	// Reloader reloads enabled plugins when their config changes; nil only
+	// saves the config
+	Reloader service.PluginReloader
+	// Routes exposes the REST extensions of enabled plugins; nil leaves them
+	// unrouted
+	Routes service.PluginRouteRegistrar
+	// Hooks runs plugin lifecycle hooks on enable, disable and uninstall;
+	// nil skips them
+	Hooks service.PluginHookRunner
+	// Download configures InstallFromURL
+	Download PluginDownloadConfig
 }
 
 // NewPluginService creates a new PluginService instance
 func NewPluginService(
 	pluginRepo repository.PluginRepository,
 	extensionRepo repository.PluginExtensionRepository,
+	transitionRepo repository.PluginStateTransitionRepository,
+	txManager repository.TxManager,
+	pluginsDir string,
+) service.PluginService {
+	return NewPluginServiceWithOptions(pluginRepo, extensionRepo, transitionRepo, txManager, pluginsDir, PluginServiceOptions{})
+}
+
+// NewPluginServiceWithOptions creates a PluginService with optional settings
+func NewPluginServiceWithOptions(
+	pluginRepo repository.PluginRepository,
+	extensionRepo repository.PluginExtensionRepository,
+	transitionRepo repository.PluginStateTransitionRepository,
+	txManager repository.TxManager,
 	pluginsDir string,
+	opts PluginServiceOptions,
 ) service.PluginService {
 	return &pluginService{
-		pluginRepo:    pluginRepo,
-		extensionRepo: extensionRepo,
-		pluginsDir:    pluginsDir,
+		pluginRepo:     pluginRepo,
+		extensionRepo:  extensionRepo,
+		transitionRepo: transitionRepo,
+		txManager:      txManager,
+		pluginsDir:     pluginsDir,
+		reloader:       opts.Reloader,
+		routes:         opts.Routes,
+		hooks:          opts.Hooks,
+		downloader:     newPluginDownloader(opts.Download),
 	}
 }
 
 func (s *pluginService) Install(ctx context.Context, req *request.InstallPluginRequest, file io.Reader) (*response.PluginResponse, error) {
+	// Check the schema and config before anything is written
+	schemaJSON := ""
+	if req.ConfigSchema != nil {
+		if err := schema.Check(req.ConfigSchema); err != nil {
+			return nil, fmt.Errorf("%w: %v", service.ErrPluginInvalidConfigSchema, err)
+		}
+		schemaBytes, err := json.Marshal(req.ConfigSchema)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", service.ErrPluginInvalidConfigSchema, err)
+		}
+		schemaJSON = string(schemaBytes)
+		if err := validateConfig(schemaJSON, req.Config); err != nil {
+			return nil, err
+		}
+	}
+
 	// Generate plugin key
 	key := s.generatePluginKey(req.Name)
 
@@ -90,20 +154,31 @@ func (s *pluginService) Install(ctx context.Context, req *request.InstallPluginR
 
 	// Create plugin entity
 	plugin := &entity.Plugin{
-		Key:         key,
-		Name:        req.Name,
-		Description: req.Description,
-		Version:     req.Version,
-		Author:      req.Author,
-		Type:        entity.PluginType(req.Type),
-		State:       entity.PluginStateInstalled,
-		Config:      configJSON,
-		Checksum:    checksum,
-		Path:        pluginPath,
-		InstalledAt: time.Now(),
-	}
-
-	if err := s.pluginRepo.Create(ctx, plugin); err != nil {
+		Key:          key,
+		Name:         req.Name,
+		Description:  req.Description,
+		Version:      req.Version,
+		Author:       req.Author,
+		Type:         entity.PluginType(req.Type),
+		State:        entity.PluginStateInstalled,
+		Config:       configJSON,
+		ConfigSchema: schemaJSON,
+		Checksum:     checksum,
+		Path:         pluginPath,
+		InstalledAt:  time.Now(),
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.pluginRepo.Create(ctx, plugin); err != nil {
+			return err
+		}
+		return s.transitionRepo.Create(ctx, &entity.PluginStateTransition{
+			PluginID: plugin.ID,
+			ToState:  plugin.State,
+			Reason:   "installed version " + plugin.Version,
+		})
+	})
+	if err != nil {
 		os.Remove(pluginPath)
 		return nil, err
 	}
@@ -149,6 +224,17 @@ func (s *pluginService) GetByKey(ctx context.Context, key string) (*response.Plu
 		Config:         config,
 	}
 
+	// List previous versions most recent first; an unreadable history only
+	// hides them
+	history, _ := decodeVersionHistory(plugin.VersionHistory)
+	for i := len(history) - 1; i >= 0; i-- {
+		resp.PreviousVersions = append(resp.PreviousVersions, response.PluginVersionResponse{
+			Version:     history[i].Version,
+			Checksum:    history[i].Checksum,
+			InstalledAt: history[i].InstalledAt,
+		})
+	}
+
 	for _, ext := range extensions {
 		resp.Extensions = append(resp.Extensions, response.PluginExtensionResponse{
 			ID:      ext.ID,
@@ -184,6 +270,28 @@ func (s *pluginService) List(ctx context.Context, page, size int) (*response.Pag
 	return &result, nil
 }
 
+func (s *pluginService) ListByCursor(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.PluginResponse], error) {
+	if size < 1 || size > 100 {
+		size = 10
+	}
+
+	plugins, nextCursor, err := s.pluginRepo.ListAfter(ctx, cursor, size)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, service.ErrInvalidCursor
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.PluginResponse, len(plugins))
+	for i, item := range plugins {
+		items[i] = *s.toPluginResponse(item)
+	}
+
+	result := response.NewCursorPagedResponse(items, size, nextCursor)
+	return &result, nil
+}
+
 func (s *pluginService) Enable(ctx context.Context, key string) (*response.PluginResponse, error) {
 	plugin, err := s.pluginRepo.GetByKey(ctx, key)
 	if err != nil {
@@ -201,11 +309,22 @@ func (s *pluginService) Enable(ctx context.Context, key string) (*response.Plugi
 		return nil, service.ErrPluginInvalidState
 	}
 
-	if err := s.pluginRepo.UpdateState(ctx, plugin.ID, entity.PluginStateEnabled); err != nil {
+	// Claim the routes first so a conflict leaves the plugin disabled
+	if err := s.registerRoutes(ctx, plugin); err != nil {
+		return nil, err
+	}
+
+	if s.hooks != nil {
+		if err := s.hooks.RunEnableHook(ctx, key); err != nil {
+			return nil, s.failHook(ctx, plugin, err)
+		}
+	}
+
+	if err := s.changeState(ctx, plugin, entity.PluginStateEnabled, "enabled"); err != nil {
+		s.unregisterRoutes(key)
 		return nil, err
 	}
 
-	plugin.State = entity.PluginStateEnabled
 	now := time.Now()
 	plugin.EnabledAt = &now
 
@@ -229,14 +348,230 @@ func (s *pluginService) Disable(ctx context.Context, key string) (*response.Plug
 		return nil, service.ErrPluginInvalidState
 	}
 
-	if err := s.pluginRepo.UpdateState(ctx, plugin.ID, entity.PluginStateDisabled); err != nil {
+	if s.hooks != nil {
+		if err := s.hooks.RunDisableHook(ctx, key); err != nil {
+			return nil, s.failHook(ctx, plugin, err)
+		}
+	}
+
+	if err := s.changeState(ctx, plugin, entity.PluginStateDisabled, "disabled"); err != nil {
+		return nil, err
+	}
+	s.unregisterRoutes(key)
+
+	return s.toPluginResponse(plugin), nil
+}
+
+func (s *pluginService) Upgrade(ctx context.Context, key string, req *request.UpgradePluginRequest, file io.Reader) (*response.PluginResponse, error) {
+	plugin, err := s.pluginRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if plugin == nil {
+		return nil, service.ErrPluginNotFound
+	}
+
+	// A plugin that failed to load must be rolled back or reinstalled first
+	if plugin.State == entity.PluginStateError {
+		return nil, service.ErrPluginInvalidState
+	}
+
+	history, err := decodeVersionHistory(plugin.VersionHistory)
+	if err != nil {
+		return nil, err
+	}
+	if req.Version == plugin.Version {
+		return nil, service.ErrPluginVersionExists
+	}
+	for _, v := range history {
+		if v.Version == req.Version {
+			return nil, service.ErrPluginVersionExists
+		}
+	}
+
+	pluginPath, checksum, err := s.saveVersion(key, req.Version, file)
+	if err != nil {
+		return nil, err
+	}
+
+	history = append(history, entity.PluginVersion{
+		Version:     plugin.Version,
+		Checksum:    plugin.Checksum,
+		Path:        plugin.Path,
+		InstalledAt: plugin.InstalledAt,
+	})
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		os.Remove(pluginPath)
+		return nil, fmt.Errorf("failed to marshal version history: %w", err)
+	}
+
+	// Switch the active version in a single versioned update, so a
+	// concurrent upgrade or rollback cannot interleave with this one
+	plugin.Version = req.Version
+	plugin.Checksum = checksum
+	plugin.Path = pluginPath
+	plugin.VersionHistory = string(historyJSON)
+	plugin.InstalledAt = time.Now()
+	if req.Description != "" {
+		plugin.Description = req.Description
+	}
+	if req.Author != "" {
+		plugin.Author = req.Author
+	}
+
+	if err := s.pluginRepo.Update(ctx, plugin); err != nil {
+		os.Remove(pluginPath)
+		return nil, mapUpdateError(err)
+	}
+
+	return s.toPluginResponse(plugin), nil
+}
+
+func (s *pluginService) Rollback(ctx context.Context, key string) (*response.PluginResponse, error) {
+	plugin, err := s.pluginRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if plugin == nil {
+		return nil, service.ErrPluginNotFound
+	}
+
+	history, err := decodeVersionHistory(plugin.VersionHistory)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, service.ErrPluginNoPreviousVersion
+	}
+
+	previous := history[len(history)-1]
+	if _, err := os.Stat(previous.Path); err != nil {
+		return nil, fmt.Errorf("previous plugin file unavailable: %w", err)
+	}
+
+	historyJSON := ""
+	if len(history) > 1 {
+		historyBytes, err := json.Marshal(history[:len(history)-1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal version history: %w", err)
+		}
+		historyJSON = string(historyBytes)
+	}
+
+	abandoned := plugin.Path
+	plugin.Version = previous.Version
+	plugin.Checksum = previous.Checksum
+	plugin.Path = previous.Path
+	plugin.InstalledAt = previous.InstalledAt
+	plugin.VersionHistory = historyJSON
+	// Rolling back is how a plugin that failed after an upgrade recovers
+	fromState := plugin.State
+	if plugin.State == entity.PluginStateError {
+		plugin.State = entity.PluginStateInstalled
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.pluginRepo.Update(ctx, plugin); err != nil {
+			return err
+		}
+		if plugin.State == fromState {
+			return nil
+		}
+		return s.transitionRepo.Create(ctx, &entity.PluginStateTransition{
+			PluginID:  plugin.ID,
+			FromState: fromState,
+			ToState:   plugin.State,
+			Reason:    "rolled back to version " + plugin.Version,
+		})
+	})
+	if err != nil {
+		return nil, mapUpdateError(err)
+	}
+
+	if abandoned != "" && abandoned != plugin.Path {
+		os.Remove(abandoned)
+	}
+
+	return s.toPluginResponse(plugin), nil
+}
+
+func (s *pluginService) ValidateConfig(ctx context.Context, key string, config map[string]any) error {
+	plugin, err := s.pluginRepo.GetByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	if plugin == nil {
+		return service.ErrPluginNotFound
+	}
+
+	return validateConfig(plugin.ConfigSchema, config)
+}
+
+func (s *pluginService) UpdateConfig(ctx context.Context, key string, config map[string]any) (*response.PluginResponse, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrPluginInvalidConfig, err)
+	}
+
+	lock, _ := s.configLocks.LoadOrStore(key, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	// Read from the primary so the lock version is current; the versioned
+	// update still guards against other instances
+	plugin, err := s.pluginRepo.GetByKey(repository.ForcePrimary(ctx), key)
+	if err != nil {
+		return nil, err
+	}
+	if plugin == nil {
+		return nil, service.ErrPluginNotFound
+	}
+
+	if err := validateConfig(plugin.ConfigSchema, config); err != nil {
 		return nil, err
 	}
 
-	plugin.State = entity.PluginStateDisabled
+	plugin.Config = string(configJSON)
+	if err := s.pluginRepo.Update(ctx, plugin); err != nil {
+		return nil, mapUpdateError(err)
+	}
+
+	if plugin.State == entity.PluginStateEnabled && s.reloader != nil {
+		if err := s.reloader.ReloadPlugin(ctx, key, config); err != nil {
+			return nil, fmt.Errorf("%w: %v", service.ErrPluginLoadFailed, err)
+		}
+	}
+
 	return s.toPluginResponse(plugin), nil
 }
 
+func (s *pluginService) GetHistory(ctx context.Context, key string) ([]response.PluginStateTransitionResponse, error) {
+	plugin, err := s.pluginRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if plugin == nil {
+		return nil, service.ErrPluginNotFound
+	}
+
+	transitions, err := s.transitionRepo.ListByPluginID(ctx, plugin.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]response.PluginStateTransitionResponse, len(transitions))
+	for i, transition := range transitions {
+		history[i] = response.PluginStateTransitionResponse{
+			FromState: string(transition.FromState),
+			ToState:   string(transition.ToState),
+			Reason:    transition.Reason,
+			CreatedAt: transition.CreatedAt,
+		}
+	}
+	return history, nil
+}
+
 func (s *pluginService) Uninstall(ctx context.Context, key string) error {
 	plugin, err := s.pluginRepo.GetByKey(ctx, key)
 	if err != nil {
@@ -246,20 +581,109 @@ func (s *pluginService) Uninstall(ctx context.Context, key string) error {
 		return service.ErrPluginNotFound
 	}
 
+	if s.hooks != nil && plugin.State != entity.PluginStateError {
+		if err := s.hooks.RunUninstallHook(ctx, key); err != nil {
+			return s.failHook(ctx, plugin, err)
+		}
+	}
+
+	s.unregisterRoutes(key)
+
 	// Delete extensions
 	if err := s.extensionRepo.DeleteByPluginID(ctx, plugin.ID); err != nil {
 		return err
 	}
 
-	// Delete plugin file
+	// Delete plugin file and the versions kept for rollback
 	if plugin.Path != "" {
 		os.Remove(plugin.Path)
 	}
+	if history, err := decodeVersionHistory(plugin.VersionHistory); err == nil {
+		for _, v := range history {
+			os.Remove(v.Path)
+		}
+	}
+	os.Remove(filepath.Join(s.pluginsDir, key))
 
 	// Delete plugin record
 	return s.pluginRepo.DeleteByKey(ctx, key)
 }
 
+func (s *pluginService) RestoreRoutes(ctx context.Context) error {
+	if s.routes == nil {
+		return nil
+	}
+
+	enabled, err := s.pluginRepo.ListEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, plugin := range enabled {
+		if err := s.registerRoutes(ctx, plugin); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", plugin.Key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// registerRoutes routes a plugin's REST extensions
+func (s *pluginService) registerRoutes(ctx context.Context, plugin *entity.Plugin) error {
+	if s.routes == nil {
+		return nil
+	}
+
+	extensions, err := s.extensionRepo.GetByPluginID(ctx, plugin.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.routes.RegisterExtensions(plugin.Key, extensions); err != nil {
+		return fmt.Errorf("%w: %v", service.ErrPluginRouteConflict, err)
+	}
+	return nil
+}
+
+// failHook moves a plugin whose lifecycle hook failed to the ERROR state,
+// taking down its routes
+func (s *pluginService) failHook(ctx context.Context, plugin *entity.Plugin, hookErr error) error {
+	s.unregisterRoutes(plugin.Key)
+
+	err := fmt.Errorf("%w: %v", service.ErrPluginHookFailed, hookErr)
+	if stateErr := s.changeState(ctx, plugin, entity.PluginStateError, hookErr.Error()); stateErr != nil {
+		return errors.Join(err, stateErr)
+	}
+	return err
+}
+
+// changeState moves a plugin to a new state and records the transition in
+// the same transaction
+func (s *pluginService) changeState(ctx context.Context, plugin *entity.Plugin, state entity.PluginState, reason string) error {
+	transition := &entity.PluginStateTransition{
+		PluginID:  plugin.ID,
+		FromState: plugin.State,
+		ToState:   state,
+		Reason:    reason,
+	}
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.pluginRepo.UpdateState(ctx, plugin.ID, state); err != nil {
+			return err
+		}
+		return s.transitionRepo.Create(ctx, transition)
+	})
+	if err != nil {
+		return err
+	}
+	plugin.State = state
+	return nil
+}
+
+func (s *pluginService) unregisterRoutes(key string) {
+	if s.routes != nil {
+		s.routes.UnregisterExtensions(key)
+	}
+}
+
 func (s *pluginService) GetHealth(ctx context.Context) (*response.PluginHealthResponse, error) {
 	enabled, err := s.pluginRepo.ListByState(ctx, entity.PluginStateEnabled)
 	if err != nil {
@@ -297,6 +721,102 @@ func (s *pluginService) GetHealth(ctx context.Context) (*response.PluginHealthRe
 	}, nil
 }
 
+// saveVersion writes an upgraded plugin binary to <pluginsDir>/<key>/<version>.so,
+// returning its path and checksum. The file is written under a temporary
+// name and renamed into place, so the versioned path only ever holds a
+// complete binary.
+func (s *pluginService) saveVersion(key, version string, file io.Reader) (string, string, error) {
+	versionDir := filepath.Join(s.pluginsDir, key)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create plugin version directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(versionDir, ".upgrade-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create plugin file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmpFile, hash), file)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", "", fmt.Errorf("failed to save plugin file: %w", err)
+	}
+
+	// Distinct versions can sanitize to the same file name; never overwrite
+	// a binary that may be kept for rollback
+	pluginPath := filepath.Join(versionDir, sanitizeVersion(version)+".so")
+	if _, err := os.Stat(pluginPath); err == nil {
+		os.Remove(tmpPath)
+		return "", "", service.ErrPluginVersionExists
+	}
+	if err := os.Rename(tmpPath, pluginPath); err != nil {
+		os.Remove(tmpPath)
+		return "", "", fmt.Errorf("failed to save plugin file: %w", err)
+	}
+
+	return pluginPath, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// sanitizeVersion makes a version string safe to use as a file name
+func sanitizeVersion(version string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '+':
+			return r
+		default:
+			return '_'
+		}
+	}, version)
+}
+
+// validateConfig checks config against a plugin's stored schema, which is
+// empty when the plugin declared none
+func validateConfig(rawSchema string, config map[string]any) error {
+	if rawSchema == "" {
+		return nil
+	}
+	var configSchema map[string]any
+	if err := json.Unmarshal([]byte(rawSchema), &configSchema); err != nil {
+		return fmt.Errorf("failed to parse config schema: %w", err)
+	}
+
+	// Round-trip the config through JSON so it is validated as stored, with
+	// numbers as float64 and lists as []any
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("%w: %v", service.ErrPluginInvalidConfig, err)
+	}
+	var value any
+	if err := json.Unmarshal(configJSON, &value); err != nil {
+		return fmt.Errorf("%w: %v", service.ErrPluginInvalidConfig, err)
+	}
+	if value == nil {
+		value = map[string]any{}
+	}
+
+	if fields := schema.Validate(configSchema, value); len(fields) > 0 {
+		return &service.ConfigValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// decodeVersionHistory parses a plugin's version history, oldest first
+func decodeVersionHistory(raw string) ([]entity.PluginVersion, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var history []entity.PluginVersion
+	if err := json.Unmarshal([]byte(raw), &history); err != nil {
+		return nil, fmt.Errorf("failed to parse version history: %w", err)
+	}
+	return history, nil
+}
+
 func (s *pluginService) generatePluginKey(name string) string {
 	// Generate a key from name + UUID suffix
 	sanitized := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
@@ -316,5 +836,6 @@ func (s *pluginService) toPluginResponse(plugin *entity.Plugin) *response.Plugin
 		State:       string(plugin.State),
 		InstalledAt: plugin.InstalledAt,
 		EnabledAt:   plugin.EnabledAt,
+		LockVersion: plugin.LockVersion,
 	}
 }
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
@@ -24,7 +26,7 @@ func setupPluginService(t *testing.T) (service.PluginService, *mocks.MockPluginR
 		t.Fatalf("Failed to create temp dir: %v", err)
 	}
 
-	pluginService := NewPluginService(pluginRepo, extensionRepo, tempDir)
+	pluginService := NewPluginService(pluginRepo, extensionRepo, mocks.NewMockPluginStateTransitionRepository(), mocks.NewMockTxManager(), tempDir)
 	return pluginService, pluginRepo, extensionRepo, tempDir
 }
 
@@ -392,6 +394,40 @@ func TestPluginService_List_Success(t *testing.T) {
 	}
 }
 
+func TestPluginService_ListByCursor(t *testing.T) {
+	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		pluginRepo.AddPlugin(&entity.Plugin{
+			Key:   "plugin-" + string(rune('a'+i)),
+			Name:  "Plugin " + string(rune('0'+i)),
+			State: entity.PluginStateInstalled,
+		})
+	}
+
+	resp, err := pluginService.ListByCursor(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("ListByCursor() error = %v", err)
+	}
+	if len(resp.Items) != 2 || resp.PageInfo.NextCursor == "" {
+		t.Fatalf("ListByCursor() = %d items, page info %+v", len(resp.Items), resp.PageInfo)
+	}
+
+	resp, err = pluginService.ListByCursor(ctx, resp.PageInfo.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("ListByCursor() error = %v", err)
+	}
+	if len(resp.Items) != 1 || resp.PageInfo.HasNext {
+		t.Errorf("ListByCursor() = %d items, page info %+v", len(resp.Items), resp.PageInfo)
+	}
+
+	if _, err := pluginService.ListByCursor(ctx, "%%", 2); !errors.Is(err, service.ErrInvalidCursor) {
+		t.Errorf("ListByCursor() error = %v, want %v", err, service.ErrInvalidCursor)
+	}
+}
+
 func TestPluginService_List_InvalidPage(t *testing.T) {
 	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
 	defer cleanupPluginService(t, tempDir)
@@ -770,6 +806,347 @@ func TestPluginService_Uninstall_DeleteByKeyError(t *testing.T) {
 	}
 }
 
+func installTestPlugin(t *testing.T, pluginService service.PluginService) string {
+	t.Helper()
+	req := &request.InstallPluginRequest{
+		Name:    "Test Plugin",
+		Version: "1.0.0",
+		Type:    "SERVICE",
+		Config:  map[string]any{"endpoint": "http://localhost"},
+	}
+	resp, err := pluginService.Install(context.Background(), req, bytes.NewReader([]byte("v1 binary")))
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	return resp.Key
+}
+
+func TestPluginService_Upgrade_AndRollback(t *testing.T) {
+	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	key := installTestPlugin(t, pluginService)
+	plugin, _ := pluginRepo.GetByKey(ctx, key)
+	v1Path, v1Checksum := plugin.Path, plugin.Checksum
+
+	resp, err := pluginService.Upgrade(ctx, key, &request.UpgradePluginRequest{Version: "2.0.0"}, bytes.NewReader([]byte("v2 binary")))
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if resp.Version != "2.0.0" {
+		t.Errorf("Upgrade() Version = %v, want 2.0.0", resp.Version)
+	}
+	v2Path := filepath.Join(tempDir, key, "2.0.0.so")
+	if plugin.Path != v2Path {
+		t.Errorf("Upgrade() Path = %v, want %v", plugin.Path, v2Path)
+	}
+	if plugin.Checksum == v1Checksum {
+		t.Error("Upgrade() should record the new checksum")
+	}
+	if _, err := os.Stat(v1Path); err != nil {
+		t.Errorf("Upgrade() should keep the previous version on disk: %v", err)
+	}
+
+	detail, err := pluginService.GetByKey(ctx, key)
+	if err != nil {
+		t.Fatalf("GetByKey() error = %v", err)
+	}
+	if detail.Config["endpoint"] != "http://localhost" {
+		t.Errorf("GetByKey() Config = %v, want the config kept across the upgrade", detail.Config)
+	}
+	if len(detail.PreviousVersions) != 1 || detail.PreviousVersions[0].Version != "1.0.0" || detail.PreviousVersions[0].Checksum != v1Checksum {
+		t.Errorf("GetByKey() PreviousVersions = %+v, want 1.0.0", detail.PreviousVersions)
+	}
+
+	resp, err = pluginService.Rollback(ctx, key)
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if resp.Version != "1.0.0" || plugin.Path != v1Path || plugin.Checksum != v1Checksum {
+		t.Errorf("Rollback() = %v at %v, want 1.0.0 at %v", resp.Version, plugin.Path, v1Path)
+	}
+	if _, err := os.Stat(v2Path); !os.IsNotExist(err) {
+		t.Error("Rollback() should delete the abandoned version")
+	}
+
+	if _, err := pluginService.Rollback(ctx, key); !errors.Is(err, service.ErrPluginNoPreviousVersion) {
+		t.Errorf("Rollback() error = %v, want %v", err, service.ErrPluginNoPreviousVersion)
+	}
+}
+
+func TestPluginService_Upgrade_ErrorState(t *testing.T) {
+	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	pluginRepo.AddPlugin(&entity.Plugin{
+		Key:     "test-plugin",
+		Version: "1.0.0",
+		State:   entity.PluginStateError,
+	})
+
+	_, err := pluginService.Upgrade(ctx, "test-plugin", &request.UpgradePluginRequest{Version: "2.0.0"}, bytes.NewReader([]byte("v2")))
+	if !errors.Is(err, service.ErrPluginInvalidState) {
+		t.Errorf("Upgrade() error = %v, want %v", err, service.ErrPluginInvalidState)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "test-plugin")); !os.IsNotExist(err) {
+		t.Error("Upgrade() should not write a binary for a plugin in ERROR state")
+	}
+}
+
+func TestPluginService_Upgrade_VersionExists(t *testing.T) {
+	pluginService, _, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	key := installTestPlugin(t, pluginService)
+	if _, err := pluginService.Upgrade(ctx, key, &request.UpgradePluginRequest{Version: "2.0.0"}, bytes.NewReader([]byte("v2"))); err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+
+	for _, version := range []string{"2.0.0", "1.0.0"} {
+		_, err := pluginService.Upgrade(ctx, key, &request.UpgradePluginRequest{Version: version}, bytes.NewReader([]byte("again")))
+		if !errors.Is(err, service.ErrPluginVersionExists) {
+			t.Errorf("Upgrade(%s) error = %v, want %v", version, err, service.ErrPluginVersionExists)
+		}
+	}
+}
+
+func TestPluginService_Upgrade_UpdateError(t *testing.T) {
+	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	key := installTestPlugin(t, pluginService)
+	pluginRepo.UpdateErr = repository.ErrConcurrentModification
+
+	_, err := pluginService.Upgrade(ctx, key, &request.UpgradePluginRequest{Version: "2.0.0"}, bytes.NewReader([]byte("v2")))
+	if !errors.Is(err, service.ErrConcurrentModification) {
+		t.Errorf("Upgrade() error = %v, want %v", err, service.ErrConcurrentModification)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, key, "2.0.0.so")); !os.IsNotExist(err) {
+		t.Error("Upgrade() should remove the new binary when the switch fails")
+	}
+}
+
+func TestPluginService_Rollback_RecoversErrorState(t *testing.T) {
+	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	key := installTestPlugin(t, pluginService)
+	if _, err := pluginService.Upgrade(ctx, key, &request.UpgradePluginRequest{Version: "2.0.0"}, bytes.NewReader([]byte("v2"))); err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	plugin, _ := pluginRepo.GetByKey(ctx, key)
+	plugin.State = entity.PluginStateError
+
+	resp, err := pluginService.Rollback(ctx, key)
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if resp.State != string(entity.PluginStateInstalled) {
+		t.Errorf("Rollback() State = %v, want INSTALLED", resp.State)
+	}
+}
+
+func TestPluginService_Uninstall_RemovesPreviousVersions(t *testing.T) {
+	pluginService, _, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	key := installTestPlugin(t, pluginService)
+	if _, err := pluginService.Upgrade(ctx, key, &request.UpgradePluginRequest{Version: "2.0.0"}, bytes.NewReader([]byte("v2"))); err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+
+	if err := pluginService.Uninstall(ctx, key); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 0 {
+		t.Errorf("Uninstall() left %d entries in the plugins directory", len(entries))
+	}
+}
+
+// fakePluginReloader records reloads, failing with err if set
+type fakePluginReloader struct {
+	reloaded []map[string]any
+	err      error
+}
+
+func (r *fakePluginReloader) ReloadPlugin(_ context.Context, _ string, config map[string]any) error {
+	r.reloaded = append(r.reloaded, config)
+	return r.err
+}
+
+func TestPluginService_UpdateConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		state       entity.PluginState
+		reloadErr   error
+		wantReloads int
+		wantErr     error
+	}{
+		{"installed plugin is not reloaded", entity.PluginStateInstalled, nil, 0, nil},
+		{"enabled plugin is reloaded", entity.PluginStateEnabled, nil, 1, nil},
+		{"failed reload keeps the config", entity.PluginStateEnabled, errors.New("init failed"), 1, service.ErrPluginLoadFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pluginRepo := mocks.NewMockPluginRepository()
+			reloader := &fakePluginReloader{err: tt.reloadErr}
+			pluginService := NewPluginServiceWithOptions(pluginRepo, mocks.NewMockPluginExtensionRepository(), mocks.NewMockPluginStateTransitionRepository(), mocks.NewMockTxManager(), t.TempDir(), PluginServiceOptions{Reloader: reloader})
+			ctx := context.Background()
+
+			pluginRepo.AddPlugin(&entity.Plugin{
+				Key:    "test-plugin",
+				State:  tt.state,
+				Config: `{"old":true}`,
+			})
+
+			config := map[string]any{"endpoint": "http://localhost"}
+			_, err := pluginService.UpdateConfig(ctx, "test-plugin", config)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateConfig() error = %v, want %v", err, tt.wantErr)
+			}
+
+			plugin, _ := pluginRepo.GetByKey(ctx, "test-plugin")
+			if plugin.Config != `{"endpoint":"http://localhost"}` {
+				t.Errorf("UpdateConfig() Config = %v", plugin.Config)
+			}
+			if len(reloader.reloaded) != tt.wantReloads {
+				t.Errorf("UpdateConfig() reloads = %d, want %d", len(reloader.reloaded), tt.wantReloads)
+			}
+		})
+	}
+}
+
+func TestPluginService_UpdateConfig_Errors(t *testing.T) {
+	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	if _, err := pluginService.UpdateConfig(ctx, "missing", map[string]any{}); !errors.Is(err, service.ErrPluginNotFound) {
+		t.Errorf("UpdateConfig() error = %v, want %v", err, service.ErrPluginNotFound)
+	}
+
+	pluginRepo.AddPlugin(&entity.Plugin{Key: "test-plugin", State: entity.PluginStateInstalled})
+
+	_, err := pluginService.UpdateConfig(ctx, "test-plugin", map[string]any{"bad": func() {}})
+	if !errors.Is(err, service.ErrPluginInvalidConfig) {
+		t.Errorf("UpdateConfig() error = %v, want %v", err, service.ErrPluginInvalidConfig)
+	}
+
+	pluginRepo.UpdateErr = repository.ErrConcurrentModification
+	if _, err := pluginService.UpdateConfig(ctx, "test-plugin", map[string]any{}); !errors.Is(err, service.ErrConcurrentModification) {
+		t.Errorf("UpdateConfig() error = %v, want %v", err, service.ErrConcurrentModification)
+	}
+}
+
+var testConfigSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"endpoint"},
+	"properties": map[string]any{
+		"endpoint": map[string]any{"type": "string"},
+		"port":     map[string]any{"type": "integer"},
+	},
+}
+
+func TestPluginService_Install_ConfigSchema(t *testing.T) {
+	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	req := &request.InstallPluginRequest{
+		Name:         "Test Plugin",
+		Version:      "1.0.0",
+		Type:         "SERVICE",
+		Config:       map[string]any{"port": "80"},
+		ConfigSchema: testConfigSchema,
+	}
+
+	_, err := pluginService.Install(ctx, req, bytes.NewReader([]byte("data")))
+	var validationErr *service.ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Install() error = %v, want a ConfigValidationError", err)
+	}
+	if !errors.Is(err, service.ErrPluginInvalidConfig) {
+		t.Error("ConfigValidationError should match ErrPluginInvalidConfig")
+	}
+	if len(validationErr.Fields) != 2 || validationErr.Fields[0].Field != "endpoint" || validationErr.Fields[1].Field != "port" {
+		t.Errorf("Install() fields = %v, want endpoint and port", validationErr.Fields)
+	}
+	if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+		t.Error("Install() should not write the plugin file for an invalid config")
+	}
+
+	req.Config = map[string]any{"endpoint": "http://localhost", "port": 80}
+	resp, err := pluginService.Install(ctx, req, bytes.NewReader([]byte("data")))
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	plugin, _ := pluginRepo.GetByKey(ctx, resp.Key)
+	if plugin.ConfigSchema == "" {
+		t.Error("Install() should store the config schema")
+	}
+}
+
+func TestPluginService_Install_InvalidConfigSchema(t *testing.T) {
+	pluginService, _, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+
+	req := &request.InstallPluginRequest{
+		Name:         "Test Plugin",
+		Version:      "1.0.0",
+		Type:         "SERVICE",
+		ConfigSchema: map[string]any{"type": "text"},
+	}
+
+	_, err := pluginService.Install(context.Background(), req, bytes.NewReader([]byte("data")))
+	if !errors.Is(err, service.ErrPluginInvalidConfigSchema) {
+		t.Errorf("Install() error = %v, want %v", err, service.ErrPluginInvalidConfigSchema)
+	}
+}
+
+func TestPluginService_ValidateConfig(t *testing.T) {
+	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
+	defer cleanupPluginService(t, tempDir)
+	ctx := context.Background()
+
+	pluginRepo.AddPlugin(&entity.Plugin{Key: "no-schema", State: entity.PluginStateInstalled})
+	pluginRepo.AddPlugin(&entity.Plugin{
+		Key:          "with-schema",
+		State:        entity.PluginStateInstalled,
+		ConfigSchema: `{"type":"object","required":["endpoint"]}`,
+	})
+
+	if err := pluginService.ValidateConfig(ctx, "no-schema", map[string]any{"anything": 1}); err != nil {
+		t.Errorf("ValidateConfig() without a schema error = %v", err)
+	}
+	if err := pluginService.ValidateConfig(ctx, "with-schema", map[string]any{"endpoint": "x"}); err != nil {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+	if err := pluginService.ValidateConfig(ctx, "with-schema", map[string]any{}); !errors.Is(err, service.ErrPluginInvalidConfig) {
+		t.Errorf("ValidateConfig() error = %v, want %v", err, service.ErrPluginInvalidConfig)
+	}
+	if err := pluginService.ValidateConfig(ctx, "missing", nil); !errors.Is(err, service.ErrPluginNotFound) {
+		t.Errorf("ValidateConfig() error = %v, want %v", err, service.ErrPluginNotFound)
+	}
+
+	// UpdateConfig rejects the config without saving it
+	if _, err := pluginService.UpdateConfig(ctx, "with-schema", map[string]any{}); !errors.Is(err, service.ErrPluginInvalidConfig) {
+		t.Errorf("UpdateConfig() error = %v, want %v", err, service.ErrPluginInvalidConfig)
+	}
+	plugin, _ := pluginRepo.GetByKey(ctx, "with-schema")
+	if plugin.Config != "" {
+		t.Errorf("UpdateConfig() saved an invalid config: %v", plugin.Config)
+	}
+}
+
 func TestPluginService_GetHealth_Success(t *testing.T) {
 	pluginService, pluginRepo, _, tempDir := setupPluginService(t)
 	defer cleanupPluginService(t, tempDir)
@@ -870,3 +1247,329 @@ func TestPluginServiceErrors(t *testing.T) {
 		})
 	}
 }
+
+// fakeRouteRegistrar records routed extension paths per plugin, refusing a
+// path another plugin holds
+type fakeRouteRegistrar struct {
+	routes map[string][]string
+}
+
+func newFakeRouteRegistrar() *fakeRouteRegistrar {
+	return &fakeRouteRegistrar{routes: make(map[string][]string)}
+}
+
+func (r *fakeRouteRegistrar) RegisterExtensions(pluginKey string, extensions []*entity.PluginExtension) error {
+	var paths []string
+	for _, ext := range extensions {
+		for key, owned := range r.routes {
+			if key != pluginKey && slices.Contains(owned, ext.Path) {
+				return errors.New(ext.Path + " is routed by " + key)
+			}
+		}
+		paths = append(paths, ext.Path)
+	}
+	r.routes[pluginKey] = paths
+	return nil
+}
+
+func (r *fakeRouteRegistrar) UnregisterExtensions(pluginKey string) {
+	delete(r.routes, pluginKey)
+}
+
+func setupRoutedPluginService(t *testing.T) (service.PluginService, *mocks.MockPluginRepository, *mocks.MockPluginExtensionRepository, *fakeRouteRegistrar) {
+	t.Helper()
+	pluginRepo := mocks.NewMockPluginRepository()
+	extensionRepo := mocks.NewMockPluginExtensionRepository()
+	routes := newFakeRouteRegistrar()
+	pluginService := NewPluginServiceWithOptions(pluginRepo, extensionRepo, mocks.NewMockPluginStateTransitionRepository(), mocks.NewMockTxManager(), t.TempDir(), PluginServiceOptions{Routes: routes})
+	return pluginService, pluginRepo, extensionRepo, routes
+}
+
+// addRoutedPlugin adds a plugin with one REST extension at path
+func addRoutedPlugin(pluginRepo *mocks.MockPluginRepository, extensionRepo *mocks.MockPluginExtensionRepository, key, path string, state entity.PluginState) {
+	plugin := &entity.Plugin{Key: key, State: state}
+	pluginRepo.AddPlugin(plugin)
+	extensionRepo.AddExtension(&entity.PluginExtension{PluginID: plugin.ID, Type: entity.PluginTypeRestEndpoint, Path: path, Handler: path})
+}
+
+func TestPluginService_Routes_Lifecycle(t *testing.T) {
+	pluginService, pluginRepo, extensionRepo, routes := setupRoutedPluginService(t)
+	ctx := context.Background()
+	addRoutedPlugin(pluginRepo, extensionRepo, "weather", "/weather", entity.PluginStateInstalled)
+
+	if _, err := pluginService.Enable(ctx, "weather"); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	if got := routes.routes["weather"]; !slices.Equal(got, []string{"/weather"}) {
+		t.Errorf("Enable() routes = %v, want [/weather]", got)
+	}
+
+	if _, err := pluginService.Disable(ctx, "weather"); err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	if _, ok := routes.routes["weather"]; ok {
+		t.Error("Disable() should unregister the plugin's routes")
+	}
+
+	if _, err := pluginService.Enable(ctx, "weather"); err != nil {
+		t.Fatalf("Enable() again error = %v", err)
+	}
+	if err := pluginService.Uninstall(ctx, "weather"); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, ok := routes.routes["weather"]; ok {
+		t.Error("Uninstall() should unregister the plugin's routes")
+	}
+}
+
+func TestPluginService_Enable_RouteConflict(t *testing.T) {
+	pluginService, pluginRepo, extensionRepo, routes := setupRoutedPluginService(t)
+	ctx := context.Background()
+	addRoutedPlugin(pluginRepo, extensionRepo, "first", "/shared", entity.PluginStateInstalled)
+	addRoutedPlugin(pluginRepo, extensionRepo, "second", "/shared", entity.PluginStateInstalled)
+
+	if _, err := pluginService.Enable(ctx, "first"); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+
+	_, err := pluginService.Enable(ctx, "second")
+	if !errors.Is(err, service.ErrPluginRouteConflict) {
+		t.Fatalf("Enable() error = %v, want %v", err, service.ErrPluginRouteConflict)
+	}
+	plugin, _ := pluginRepo.GetByKey(ctx, "second")
+	if plugin.State != entity.PluginStateInstalled {
+		t.Errorf("Enable() conflicting plugin State = %v, want INSTALLED", plugin.State)
+	}
+	if _, ok := routes.routes["second"]; ok {
+		t.Error("Enable() should not route a conflicting plugin")
+	}
+}
+
+func TestPluginService_Enable_UpdateStateFailsUnregisters(t *testing.T) {
+	pluginService, pluginRepo, extensionRepo, routes := setupRoutedPluginService(t)
+	ctx := context.Background()
+	addRoutedPlugin(pluginRepo, extensionRepo, "weather", "/weather", entity.PluginStateInstalled)
+	pluginRepo.UpdateStateErr = errors.New("database error")
+
+	if _, err := pluginService.Enable(ctx, "weather"); err == nil {
+		t.Fatal("Enable() should fail")
+	}
+	if _, ok := routes.routes["weather"]; ok {
+		t.Error("Enable() should unregister routes when the state is not saved")
+	}
+}
+
+func TestPluginService_RestoreRoutes(t *testing.T) {
+	pluginService, pluginRepo, extensionRepo, routes := setupRoutedPluginService(t)
+	ctx := context.Background()
+	addRoutedPlugin(pluginRepo, extensionRepo, "weather", "/weather", entity.PluginStateEnabled)
+	addRoutedPlugin(pluginRepo, extensionRepo, "stocks", "/stocks", entity.PluginStateDisabled)
+
+	if err := pluginService.RestoreRoutes(ctx); err != nil {
+		t.Fatalf("RestoreRoutes() error = %v", err)
+	}
+	if len(routes.routes) != 1 || routes.routes["weather"] == nil {
+		t.Errorf("RestoreRoutes() routes = %v, want only weather", routes.routes)
+	}
+
+	// A conflicting plugin is reported without stopping the others
+	addRoutedPlugin(pluginRepo, extensionRepo, "clash", "/stocks", entity.PluginStateEnabled)
+	routes.routes = map[string][]string{"other": {"/stocks"}}
+	err := pluginService.RestoreRoutes(ctx)
+	if !errors.Is(err, service.ErrPluginRouteConflict) {
+		t.Errorf("RestoreRoutes() error = %v, want %v", err, service.ErrPluginRouteConflict)
+	}
+	if routes.routes["weather"] == nil {
+		t.Error("RestoreRoutes() should route the non-conflicting plugin")
+	}
+}
+
+// fakeHookRunner records the lifecycle hooks run, failing the hook named in
+// fail
+type fakeHookRunner struct {
+	hooks []string
+	fail  string
+}
+
+func (r *fakeHookRunner) run(hook string) error {
+	r.hooks = append(r.hooks, hook)
+	if hook == r.fail {
+		return errors.New(hook + " failed")
+	}
+	return nil
+}
+
+func (r *fakeHookRunner) RunEnableHook(_ context.Context, _ string) error {
+	return r.run("OnEnable")
+}
+
+func (r *fakeHookRunner) RunDisableHook(_ context.Context, _ string) error {
+	return r.run("OnDisable")
+}
+
+func (r *fakeHookRunner) RunUninstallHook(_ context.Context, _ string) error {
+	return r.run("OnUninstall")
+}
+
+func TestPluginService_LifecycleHooks(t *testing.T) {
+	pluginRepo := mocks.NewMockPluginRepository()
+	hooks := &fakeHookRunner{}
+	pluginService := NewPluginServiceWithOptions(pluginRepo, mocks.NewMockPluginExtensionRepository(), mocks.NewMockPluginStateTransitionRepository(), mocks.NewMockTxManager(), t.TempDir(), PluginServiceOptions{Hooks: hooks})
+	ctx := context.Background()
+	pluginRepo.AddPlugin(&entity.Plugin{Key: "test-plugin", State: entity.PluginStateInstalled})
+
+	if _, err := pluginService.Enable(ctx, "test-plugin"); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	// Enabling an enabled plugin does not run the hook again
+	if _, err := pluginService.Enable(ctx, "test-plugin"); err != nil {
+		t.Fatalf("Enable() again error = %v", err)
+	}
+	if _, err := pluginService.Disable(ctx, "test-plugin"); err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	if err := pluginService.Uninstall(ctx, "test-plugin"); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+
+	want := []string{"OnEnable", "OnDisable", "OnUninstall"}
+	if !slices.Equal(hooks.hooks, want) {
+		t.Errorf("hooks = %v, want %v", hooks.hooks, want)
+	}
+}
+
+func TestPluginService_LifecycleHooks_Failure(t *testing.T) {
+	tests := []struct {
+		name  string
+		state entity.PluginState
+		call  func(service.PluginService, context.Context) error
+		fail  string
+	}{
+		{"enable", entity.PluginStateInstalled, func(s service.PluginService, ctx context.Context) error {
+			_, err := s.Enable(ctx, "test-plugin")
+			return err
+		}, "OnEnable"},
+		{"disable", entity.PluginStateEnabled, func(s service.PluginService, ctx context.Context) error {
+			_, err := s.Disable(ctx, "test-plugin")
+			return err
+		}, "OnDisable"},
+		{"uninstall", entity.PluginStateEnabled, func(s service.PluginService, ctx context.Context) error {
+			return s.Uninstall(ctx, "test-plugin")
+		}, "OnUninstall"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pluginRepo := mocks.NewMockPluginRepository()
+			extensionRepo := mocks.NewMockPluginExtensionRepository()
+			routes := newFakeRouteRegistrar()
+			pluginService := NewPluginServiceWithOptions(pluginRepo, extensionRepo, mocks.NewMockPluginStateTransitionRepository(), mocks.NewMockTxManager(), t.TempDir(), PluginServiceOptions{
+				Routes: routes,
+				Hooks:  &fakeHookRunner{fail: tt.fail},
+			})
+			ctx := context.Background()
+			addRoutedPlugin(pluginRepo, extensionRepo, "test-plugin", "/test", tt.state)
+			if tt.state == entity.PluginStateEnabled {
+				routes.routes["test-plugin"] = []string{"/test"}
+			}
+
+			err := tt.call(pluginService, ctx)
+			if !errors.Is(err, service.ErrPluginHookFailed) {
+				t.Fatalf("error = %v, want %v", err, service.ErrPluginHookFailed)
+			}
+
+			plugin, _ := pluginRepo.GetByKey(ctx, "test-plugin")
+			if plugin == nil || plugin.State != entity.PluginStateError {
+				t.Fatalf("plugin = %+v, want it kept in the ERROR state", plugin)
+			}
+			if _, ok := routes.routes["test-plugin"]; ok {
+				t.Error("a failed hook should take down the plugin's routes")
+			}
+		})
+	}
+}
+
+func TestPluginService_Uninstall_ErrorStateSkipsHook(t *testing.T) {
+	pluginRepo := mocks.NewMockPluginRepository()
+	hooks := &fakeHookRunner{fail: "OnUninstall"}
+	pluginService := NewPluginServiceWithOptions(pluginRepo, mocks.NewMockPluginExtensionRepository(), mocks.NewMockPluginStateTransitionRepository(), mocks.NewMockTxManager(), t.TempDir(), PluginServiceOptions{Hooks: hooks})
+	ctx := context.Background()
+	pluginRepo.AddPlugin(&entity.Plugin{Key: "test-plugin", State: entity.PluginStateError})
+
+	if err := pluginService.Uninstall(ctx, "test-plugin"); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if len(hooks.hooks) != 0 {
+		t.Errorf("Uninstall() ran hooks %v for a plugin in the ERROR state", hooks.hooks)
+	}
+}
+
+func TestPluginService_GetHistory(t *testing.T) {
+	pluginRepo := mocks.NewMockPluginRepository()
+	transitionRepo := mocks.NewMockPluginStateTransitionRepository()
+	txManager := mocks.NewMockTxManager(pluginRepo, transitionRepo)
+	pluginService := NewPluginServiceWithOptions(pluginRepo, mocks.NewMockPluginExtensionRepository(), transitionRepo, txManager, t.TempDir(), PluginServiceOptions{
+		Hooks: &fakeHookRunner{fail: "OnDisable"},
+	})
+	ctx := context.Background()
+
+	installed, err := pluginService.Install(ctx, &request.InstallPluginRequest{Name: "History Plugin", Version: "1.0.0", Type: "SERVICE"}, bytes.NewReader([]byte("plugin")))
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if _, err := pluginService.Enable(ctx, installed.Key); err != nil {
+		t.Fatalf("Enable() error = %v", err)
+	}
+	if _, err := pluginService.Disable(ctx, installed.Key); !errors.Is(err, service.ErrPluginHookFailed) {
+		t.Fatalf("Disable() error = %v, want %v", err, service.ErrPluginHookFailed)
+	}
+
+	history, err := pluginService.GetHistory(ctx, installed.Key)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+
+	want := []struct{ from, to string }{
+		{"", "INSTALLED"},
+		{"INSTALLED", "ENABLED"},
+		{"ENABLED", "ERROR"},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("GetHistory() = %+v, want %d transitions", history, len(want))
+	}
+	for i, w := range want {
+		if history[i].FromState != w.from || history[i].ToState != w.to {
+			t.Errorf("GetHistory()[%d] = %s -> %s, want %s -> %s", i, history[i].FromState, history[i].ToState, w.from, w.to)
+		}
+	}
+	if history[2].Reason != "OnDisable failed" {
+		t.Errorf("GetHistory()[2].Reason = %q, want the hook error", history[2].Reason)
+	}
+
+	if _, err := pluginService.GetHistory(ctx, "missing"); !errors.Is(err, service.ErrPluginNotFound) {
+		t.Errorf("GetHistory() error = %v, want %v", err, service.ErrPluginNotFound)
+	}
+}
+
+func TestPluginService_StateChange_Transactional(t *testing.T) {
+	pluginRepo := mocks.NewMockPluginRepository()
+	transitionRepo := mocks.NewMockPluginStateTransitionRepository()
+	txManager := mocks.NewMockTxManager(pluginRepo, transitionRepo)
+	pluginService := NewPluginService(pluginRepo, mocks.NewMockPluginExtensionRepository(), transitionRepo, txManager, t.TempDir())
+	ctx := context.Background()
+	pluginRepo.AddPlugin(&entity.Plugin{Key: "test-plugin", State: entity.PluginStateInstalled})
+
+	// A failed history write rolls back the state change
+	transitionRepo.CreateErr = errors.New("database error")
+	if _, err := pluginService.Enable(ctx, "test-plugin"); err == nil {
+		t.Fatal("Enable() should fail when the transition cannot be recorded")
+	}
+
+	plugin, _ := pluginRepo.GetByKey(ctx, "test-plugin")
+	if plugin.State != entity.PluginStateInstalled {
+		t.Errorf("Enable() State = %v, want INSTALLED after rollback", plugin.State)
+	}
+	if txManager.Rollbacks != 1 {
+		t.Errorf("Rollbacks = %d, want 1", txManager.Rollbacks)
+	}
+}
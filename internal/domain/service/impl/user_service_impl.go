@@ -2,6 +2,8 @@ package impl
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
@@ -59,6 +61,16 @@ func (s *userService) GetByEmail(ctx context.Context, email string) (*response.U
 }
 
 func (s *userService) List(ctx context.Context, page, size int) (*response.PagedResponse[response.UserResponse], error) {
+	return s.list(ctx, page, size, s.userRepo.List)
+}
+
+func (s *userService) ListIncludingDeleted(ctx context.Context, page, size int) (*response.PagedResponse[response.UserResponse], error) {
+	return s.list(ctx, page, size, s.userRepo.ListIncludingDeleted)
+}
+
+func (s *userService) list(ctx context.Context, page, size int,
+	fetch func(ctx context.Context, page, size int) ([]*entity.User, int64, error),
+) (*response.PagedResponse[response.UserResponse], error) {
 	if page < 1 {
 		page = 1
 	}
@@ -66,7 +78,7 @@ func (s *userService) List(ctx context.Context, page, size int) (*response.Paged
 		size = 10
 	}
 
-	users, total, err := s.userRepo.List(ctx, page, size)
+	users, total, err := fetch(ctx, page, size)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +92,67 @@ func (s *userService) List(ctx context.Context, page, size int) (*response.Paged
 	return &result, nil
 }
 
+func (s *userService) ListByCursor(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.UserResponse], error) {
+	if size < 1 || size > 100 {
+		size = 10
+	}
+
+	users, nextCursor, err := s.userRepo.ListAfter(ctx, cursor, size)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, service.ErrInvalidCursor
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.UserResponse, len(users))
+	for i, item := range users {
+		items[i] = *s.toUserResponse(item)
+	}
+
+	result := response.NewCursorPagedResponse(items, size, nextCursor)
+	return &result, nil
+}
+
+func (s *userService) Search(ctx context.Context, req *request.UserSearchRequest) (*response.PagedResponse[response.UserResponse], error) {
+	query := &entity.UserQuery{
+		IsActive:      req.IsActive,
+		IsVerified:    req.IsVerified,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Text:          strings.TrimSpace(req.Q),
+		SortBy:        req.Sort,
+		SortDesc:      req.Order == "desc",
+		Page:          req.Page,
+		Size:          req.Size,
+	}
+	if req.Role != "" {
+		role := entity.UserRole(req.Role)
+		query.Role = &role
+	}
+	query.Normalize()
+
+	users, total, err := s.userRepo.Search(ctx, query)
+	if errors.Is(err, repository.ErrUnsupported) {
+		return nil, service.ErrSearchUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]response.UserResponse, len(users))
+	for i, user := range users {
+		items[i] = *s.toUserResponse(user)
+	}
+
+	result := response.NewPagedResponse(items, query.Page, query.Size, total)
+	return &result, nil
+}
+
 func (s *userService) Update(ctx context.Context, id uint, req *request.UpdateProfileRequest) (*response.UserResponse, error) {
+	// Read from the primary: a lagging replica would hand back a stale
+	// lock_version and the update would fail as a conflict
+	ctx = repository.ForcePrimary(ctx)
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -88,6 +160,9 @@ func (s *userService) Update(ctx context.Context, id uint, req *request.UpdatePr
 	if user == nil {
 		return nil, service.ErrUserNotFound
 	}
+	if req.LockVersion != nil && *req.LockVersion != user.LockVersion {
+		return nil, service.ErrConcurrentModification
+	}
 
 	// Update fields
 	if req.FirstName != "" {
@@ -109,13 +184,14 @@ func (s *userService) Update(ctx context.Context, id uint, req *request.UpdatePr
 	}
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
-		return nil, err
+		return nil, mapUpdateError(err)
 	}
 
 	return s.toUserResponse(user), nil
 }
 
 func (s *userService) ChangePassword(ctx context.Context, id uint, req *request.ChangePasswordRequest) error {
+	ctx = repository.ForcePrimary(ctx)
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -136,13 +212,48 @@ func (s *userService) ChangePassword(ctx context.Context, id uint, req *request.
 	}
 
 	user.Password = hashedPassword
-	return s.userRepo.Update(ctx, user)
+	return mapUpdateError(s.userRepo.Update(ctx, user))
+}
+
+// mapUpdateError translates a lost optimistic-locking race into the service error
+func mapUpdateError(err error) error {
+	if errors.Is(err, repository.ErrConcurrentModification) {
+		return service.ErrConcurrentModification
+	}
+	return err
 }
 
 func (s *userService) Delete(ctx context.Context, id uint) error {
 	return s.userRepo.Delete(ctx, id)
 }
 
+func (s *userService) Restore(ctx context.Context, id uint) (*response.UserResponse, error) {
+	ctx = repository.ForcePrimary(ctx)
+	user, err := s.userRepo.GetByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, service.ErrUserNotFound
+	}
+	if !user.DeletedAt.Valid {
+		return nil, service.ErrUserNotDeleted
+	}
+
+	if err := s.userRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if restored == nil {
+		return nil, service.ErrUserNotFound
+	}
+	return s.toUserResponse(restored), nil
+}
+
 func (s *userService) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	return s.userRepo.ExistsByUsername(ctx, username)
 }
@@ -152,16 +263,21 @@ func (s *userService) ExistsByEmail(ctx context.Context, email string) (bool, er
 }
 
 func (s *userService) toUserResponse(user *entity.User) *response.UserResponse {
-	return &response.UserResponse{
-		ID:         user.ID,
-		Username:   user.Username,
-		Email:      user.Email,
-		FirstName:  user.FirstName,
-		LastName:   user.LastName,
-		Role:       string(user.Role),
-		IsActive:   user.IsActive,
-		IsVerified: user.IsVerified,
-		CreatedAt:  user.CreatedAt,
-		UpdatedAt:  user.UpdatedAt,
+	resp := &response.UserResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Role:        string(user.Role),
+		IsActive:    user.IsActive,
+		IsVerified:  user.IsVerified,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		LockVersion: user.LockVersion,
+	}
+	if user.DeletedAt.Valid {
+		resp.DeletedAt = &user.DeletedAt.Time
 	}
+	return resp
 }
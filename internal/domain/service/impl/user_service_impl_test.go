@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/service"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
@@ -170,6 +171,43 @@ func TestUserService_GetByEmail_Error(t *testing.T) {
 	}
 }
 
+func TestUserService_ListIncludingDeleted(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	ctx := context.Background()
+
+	live := &entity.User{Username: "live", Email: "live@example.com", Password: "hash"}
+	gone := &entity.User{Username: "gone", Email: "gone@example.com", Password: "hash"}
+	userRepo.AddUser(live)
+	userRepo.AddUser(gone)
+	if err := userRepo.Delete(ctx, gone.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	resp, err := userService.List(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if resp.PageInfo.TotalItems != 1 || len(resp.Items) != 1 || resp.Items[0].DeletedAt != nil {
+		t.Errorf("List() = %+v, want only the live user", resp)
+	}
+
+	resp, err = userService.ListIncludingDeleted(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("ListIncludingDeleted() error = %v", err)
+	}
+	if resp.PageInfo.TotalItems != 2 || len(resp.Items) != 2 {
+		t.Fatalf("ListIncludingDeleted() = %+v, want both users", resp)
+	}
+	if resp.PageInfo.Page != 1 || resp.PageInfo.Size != 10 {
+		t.Errorf("ListIncludingDeleted() page info = %+v, want defaults", resp.PageInfo)
+	}
+	for _, user := range resp.Items {
+		if (user.ID == gone.ID) != (user.DeletedAt != nil) {
+			t.Errorf("user %d DeletedAt = %v", user.ID, user.DeletedAt)
+		}
+	}
+}
+
 func TestUserService_List_Success(t *testing.T) {
 	userService, userRepo := setupUserService(t)
 	ctx := context.Background()
@@ -199,6 +237,63 @@ func TestUserService_List_Success(t *testing.T) {
 	}
 }
 
+func TestUserService_Search(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	ctx := context.Background()
+
+	userRepo.AddUser(&entity.User{Username: "alice", Email: "alice@example.com", Role: entity.RoleAdmin, IsActive: true})
+	userRepo.AddUser(&entity.User{Username: "albert", Email: "albert@example.com", Role: entity.RoleUser, IsActive: true})
+	userRepo.AddUser(&entity.User{Username: "bob", Email: "bob@example.com", Role: entity.RoleUser, IsActive: false})
+
+	active := true
+	resp, err := userService.Search(ctx, &request.UserSearchRequest{
+		Role:     "USER",
+		IsActive: &active,
+		Q:        " al ",
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if resp.PageInfo.TotalItems != 1 || len(resp.Items) != 1 || resp.Items[0].Username != "albert" {
+		t.Errorf("Search() = %+v, want only albert", resp.Items)
+	}
+
+	resp, err = userService.Search(ctx, &request.UserSearchRequest{Sort: "username", Size: 500})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if resp.PageInfo.Size != entity.MaxUserQuerySize || resp.PageInfo.Page != 1 {
+		t.Errorf("Search() page info = %+v, want page 1 capped at %d", resp.PageInfo, entity.MaxUserQuerySize)
+	}
+	if len(resp.Items) != 3 || resp.Items[0].Username != "albert" || resp.Items[2].Username != "bob" {
+		t.Errorf("Search() = %+v, want sorted by username", resp.Items)
+	}
+
+	resp, _ = userService.Search(ctx, &request.UserSearchRequest{})
+	if len(resp.Items) != 3 || resp.Items[0].Username != "bob" {
+		t.Errorf("Search() without sort = %+v, want newest first", resp.Items)
+	}
+}
+
+func TestUserService_Search_Error(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	expectedErr := errors.New("database error")
+	userRepo.SearchErr = expectedErr
+
+	if _, err := userService.Search(context.Background(), &request.UserSearchRequest{}); !errors.Is(err, expectedErr) {
+		t.Errorf("Search() error = %v, want %v", err, expectedErr)
+	}
+}
+
+func TestUserService_Search_Unsupported(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	userRepo.SearchErr = repository.ErrUnsupported
+
+	if _, err := userService.Search(context.Background(), &request.UserSearchRequest{}); !errors.Is(err, service.ErrSearchUnsupported) {
+		t.Errorf("Search() error = %v, want %v", err, service.ErrSearchUnsupported)
+	}
+}
+
 func TestUserService_List_InvalidPage(t *testing.T) {
 	userService, userRepo := setupUserService(t)
 	ctx := context.Background()
@@ -250,6 +345,46 @@ func TestUserService_List_InvalidSize(t *testing.T) {
 	}
 }
 
+func TestUserService_ListByCursor(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	ctx := context.Background()
+
+	for i := 1; i <= 15; i++ {
+		userRepo.AddUser(&entity.User{
+			Username: "user" + string(rune('a'+i)),
+			Email:    "user" + string(rune('a'+i)) + "@example.com",
+			Password: "hash",
+			IsActive: true,
+		})
+	}
+
+	first, err := userService.ListByCursor(ctx, "", 10)
+	if err != nil {
+		t.Fatalf("ListByCursor() error = %v", err)
+	}
+	if len(first.Items) != 10 || !first.PageInfo.HasNext || first.PageInfo.NextCursor == "" {
+		t.Fatalf("ListByCursor() first page = %d items, page info %+v", len(first.Items), first.PageInfo)
+	}
+	if first.Items[0].ID >= first.Items[9].ID {
+		t.Errorf("ListByCursor() items not in ascending ID order")
+	}
+
+	second, err := userService.ListByCursor(ctx, first.PageInfo.NextCursor, 10)
+	if err != nil {
+		t.Fatalf("ListByCursor() error = %v", err)
+	}
+	if len(second.Items) != 5 || second.PageInfo.HasNext || second.PageInfo.NextCursor != "" {
+		t.Errorf("ListByCursor() last page = %d items, page info %+v", len(second.Items), second.PageInfo)
+	}
+	if second.Items[0].ID <= first.Items[9].ID {
+		t.Errorf("ListByCursor() second page overlaps the first")
+	}
+
+	if _, err := userService.ListByCursor(ctx, "not a cursor", 10); !errors.Is(err, service.ErrInvalidCursor) {
+		t.Errorf("ListByCursor() error = %v, want %v", err, service.ErrInvalidCursor)
+	}
+}
+
 func TestUserService_List_Error(t *testing.T) {
 	userService, userRepo := setupUserService(t)
 	ctx := context.Background()
@@ -431,6 +566,77 @@ func TestUserService_Update_UpdateError(t *testing.T) {
 	}
 }
 
+func TestUserService_Update_StaleLockVersion(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	ctx := context.Background()
+
+	user := &entity.User{
+		Username:    "testuser",
+		Email:       "test@example.com",
+		Password:    "hash",
+		IsActive:    true,
+		LockVersion: 3,
+	}
+	userRepo.AddUser(user)
+
+	stale := uint(2)
+	req := &request.UpdateProfileRequest{
+		FirstName:   "Updated",
+		LockVersion: &stale,
+	}
+
+	_, err := userService.Update(ctx, user.ID, req)
+	if !errors.Is(err, service.ErrConcurrentModification) {
+		t.Errorf("Update() error = %v, want %v", err, service.ErrConcurrentModification)
+	}
+}
+
+func TestUserService_Update_BumpsLockVersion(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	ctx := context.Background()
+
+	user := &entity.User{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "hash",
+		IsActive: true,
+	}
+	userRepo.AddUser(user)
+
+	current := uint(0)
+	req := &request.UpdateProfileRequest{
+		FirstName:   "Updated",
+		LockVersion: &current,
+	}
+
+	resp, err := userService.Update(ctx, user.ID, req)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if resp.LockVersion != 1 {
+		t.Errorf("Update() LockVersion = %v, want 1", resp.LockVersion)
+	}
+}
+
+func TestUserService_Update_RepositoryConcurrentModification(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	ctx := context.Background()
+
+	user := &entity.User{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "hash",
+		IsActive: true,
+	}
+	userRepo.AddUser(user)
+	userRepo.UpdateErr = repository.ErrConcurrentModification
+
+	_, err := userService.Update(ctx, user.ID, &request.UpdateProfileRequest{FirstName: "Updated"})
+	if !errors.Is(err, service.ErrConcurrentModification) {
+		t.Errorf("Update() error = %v, want %v", err, service.ErrConcurrentModification)
+	}
+}
+
 func TestUserService_ChangePassword_Success(t *testing.T) {
 	userService, userRepo := setupUserService(t)
 	ctx := context.Background()
@@ -563,6 +769,59 @@ func TestUserService_Delete_Success(t *testing.T) {
 	}
 }
 
+func TestUserService_Restore(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	ctx := context.Background()
+
+	user := &entity.User{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "hash",
+		IsActive: true,
+	}
+	userRepo.AddUser(user)
+
+	if _, err := userService.Restore(ctx, user.ID); !errors.Is(err, service.ErrUserNotDeleted) {
+		t.Errorf("Restore() error = %v, want %v", err, service.ErrUserNotDeleted)
+	}
+
+	if err := userService.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	resp, err := userService.Restore(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if resp.ID != user.ID || resp.Username != "testuser" {
+		t.Errorf("Restore() = %+v", resp)
+	}
+	if found, _ := userService.GetByID(ctx, user.ID); found == nil {
+		t.Error("GetByID() after Restore() returned nil")
+	}
+
+	if _, err := userService.Restore(ctx, 999); !errors.Is(err, service.ErrUserNotFound) {
+		t.Errorf("Restore() error = %v, want %v", err, service.ErrUserNotFound)
+	}
+}
+
+func TestUserService_Restore_Error(t *testing.T) {
+	userService, userRepo := setupUserService(t)
+	ctx := context.Background()
+
+	user := &entity.User{Username: "testuser", Email: "test@example.com", Password: "hash"}
+	userRepo.AddUser(user)
+	if err := userService.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	expectedErr := errors.New("restore error")
+	userRepo.RestoreErr = expectedErr
+
+	if _, err := userService.Restore(ctx, user.ID); !errors.Is(err, expectedErr) {
+		t.Errorf("Restore() error = %v, want %v", err, expectedErr)
+	}
+}
+
 func TestUserService_Delete_Error(t *testing.T) {
 	userService, userRepo := setupUserService(t)
 	ctx := context.Background()
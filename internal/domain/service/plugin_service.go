@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/plugin/schema"
 )
 
 var (
@@ -14,31 +17,150 @@ var (
 	ErrPluginAlreadyExists = errors.New("plugin already exists")
 	ErrPluginInvalidState  = errors.New("invalid plugin state")
 	ErrPluginLoadFailed    = errors.New("failed to load plugin")
+	// ErrPluginVersionExists is returned when upgrading to a version that is
+	// already current or kept for rollback
+	ErrPluginVersionExists = errors.New("plugin version already installed")
+	// ErrPluginNoPreviousVersion is returned when rolling back a plugin that
+	// was never upgraded
+	ErrPluginNoPreviousVersion = errors.New("plugin has no previous version")
+	// ErrPluginInvalidConfig is returned when a plugin config cannot be
+	// stored as JSON
+	ErrPluginInvalidConfig = errors.New("invalid plugin config")
+	// ErrPluginInvalidConfigSchema is returned when a plugin declares a
+	// malformed config schema
+	ErrPluginInvalidConfigSchema = errors.New("invalid plugin config schema")
+	// ErrPluginInvalidURL is returned for a plugin URL that is not http(s)
+	ErrPluginInvalidURL = errors.New("invalid plugin URL")
+	// ErrPluginHostNotAllowed is returned for a plugin URL, or a redirect,
+	// to a host outside the configured allowlist
+	ErrPluginHostNotAllowed = errors.New("plugin host not allowed")
+	// ErrPluginDownloadFailed is returned when a plugin cannot be downloaded
+	ErrPluginDownloadFailed = errors.New("failed to download plugin")
+	// ErrPluginTooLarge is returned when a downloaded plugin exceeds the size
+	// limit
+	ErrPluginTooLarge = errors.New("plugin too large")
+	// ErrPluginVerificationFailed is returned when a downloaded plugin does
+	// not match its checksum or signature
+	ErrPluginVerificationFailed = errors.New("plugin verification failed")
+	// ErrPluginRouteConflict is returned when enabling a plugin whose REST
+	// extension paths are already routed by another plugin
+	ErrPluginRouteConflict = errors.New("plugin route conflict")
+	// ErrPluginHookFailed is returned when a plugin's lifecycle hook fails;
+	// the plugin is left in the ERROR state
+	ErrPluginHookFailed = errors.New("plugin lifecycle hook failed")
 )
 
+// ConfigValidationError lists the config fields that do not match a plugin's
+// config schema. It matches ErrPluginInvalidConfig with errors.Is.
+type ConfigValidationError struct {
+	Fields []schema.FieldError
+}
+
+func (e *ConfigValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		msgs[i] = field.Error()
+	}
+	return ErrPluginInvalidConfig.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether target is ErrPluginInvalidConfig
+func (e *ConfigValidationError) Is(target error) bool {
+	return target == ErrPluginInvalidConfig
+}
+
+// PluginReloader applies a changed config to a running plugin
+type PluginReloader interface {
+	ReloadPlugin(ctx context.Context, key string, config map[string]any) error
+}
+
+// PluginHookRunner runs the lifecycle hooks of loaded plugins. A plugin that
+// is not loaded or has no hooks succeeds.
+type PluginHookRunner interface {
+	RunEnableHook(ctx context.Context, key string) error
+	RunDisableHook(ctx context.Context, key string) error
+	RunUninstallHook(ctx context.Context, key string) error
+}
+
+// PluginRouteRegistrar exposes the REST extensions of enabled plugins over HTTP
+type PluginRouteRegistrar interface {
+	// RegisterExtensions routes a plugin's REST_ENDPOINT extensions, replacing
+	// its previous routes. Returns an error if another plugin routes one of
+	// the paths.
+	RegisterExtensions(pluginKey string, extensions []*entity.PluginExtension) error
+
+	// UnregisterExtensions removes every route of a plugin
+	UnregisterExtensions(pluginKey string)
+}
+
 // PluginService defines the interface for plugin operations
 type PluginService interface {
-	// Install installs a new plugin
+	// Install installs a new plugin. A declared config schema is checked and
+	// the config validated against it; see ValidateConfig.
 	Install(ctx context.Context, req *request.InstallPluginRequest, file io.Reader) (*response.PluginResponse, error)
 
 	// InstallFromPath installs a plugin from a file path
 	InstallFromPath(ctx context.Context, req *request.InstallPluginRequest, filePath string) (*response.PluginResponse, error)
 
+	// InstallFromURL downloads a plugin, verifies it against the optional
+	// checksum and signature, and installs it like Install
+	InstallFromURL(ctx context.Context, req *request.InstallPluginFromURLRequest) (*response.PluginResponse, error)
+
 	// GetByKey retrieves a plugin by its key
 	GetByKey(ctx context.Context, key string) (*response.PluginDetailResponse, error)
 
 	// List retrieves all plugins with pagination
 	List(ctx context.Context, page, size int) (*response.PagedResponse[response.PluginResponse], error)
 
-	// Enable enables a plugin
+	// ListByCursor retrieves plugins in ID order starting after cursor, which
+	// is empty for the first page. Returns ErrInvalidCursor for a malformed cursor.
+	ListByCursor(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.PluginResponse], error)
+
+	// Enable runs the plugin's OnEnable hook, then enables it and routes its
+	// REST extensions. Returns ErrPluginRouteConflict if another plugin
+	// already routes one of them, or ErrPluginHookFailed if the hook fails.
 	Enable(ctx context.Context, key string) (*response.PluginResponse, error)
 
-	// Disable disables a plugin
+	// Disable runs the plugin's OnDisable hook, then disables it and removes
+	// its REST extension routes. Returns ErrPluginHookFailed if the hook fails.
 	Disable(ctx context.Context, key string) (*response.PluginResponse, error)
 
-	// Uninstall removes a plugin
+	// Upgrade installs a new version of a plugin alongside the current one and
+	// switches to it, keeping the plugin's config. The previous version stays
+	// on disk for Rollback. Returns ErrPluginInvalidState for a plugin in the
+	// ERROR state.
+	Upgrade(ctx context.Context, key string, req *request.UpgradePluginRequest, file io.Reader) (*response.PluginResponse, error)
+
+	// Rollback switches a plugin back to the version before its last upgrade
+	// and removes the abandoned binary. Returns ErrPluginNoPreviousVersion if
+	// there is nothing to roll back to.
+	Rollback(ctx context.Context, key string) (*response.PluginResponse, error)
+
+	// ValidateConfig checks a config against the plugin's declared schema
+	// without saving it. Returns a *ConfigValidationError listing the
+	// offending fields; a plugin without a schema accepts any config.
+	ValidateConfig(ctx context.Context, key string, config map[string]any) error
+
+	// UpdateConfig replaces a plugin's config after validating it against the
+	// plugin's schema. An enabled plugin is reloaded
+	// so the change takes effect immediately; if that fails the new config is
+	// still saved and the error wraps ErrPluginLoadFailed. Returns
+	// ErrConcurrentModification if the plugin changed during the update.
+	UpdateConfig(ctx context.Context, key string, config map[string]any) (*response.PluginResponse, error)
+
+	// GetHistory returns the state transitions of a plugin, oldest first
+	GetHistory(ctx context.Context, key string) ([]response.PluginStateTransitionResponse, error)
+
+	// Uninstall runs the plugin's OnUninstall hook and removes the plugin.
+	// Returns ErrPluginHookFailed if the hook fails. The hook is skipped for
+	// a plugin already in the ERROR state, so a broken plugin can be removed.
 	Uninstall(ctx context.Context, key string) error
 
+	// RestoreRoutes routes the REST extensions of every enabled plugin, as
+	// on startup. Plugins that cannot be routed are skipped and reported in
+	// the returned error.
+	RestoreRoutes(ctx context.Context) error
+
 	// GetHealth returns the plugin system health status
 	GetHealth(ctx context.Context) (*response.PluginHealthResponse, error)
 }
@@ -2,11 +2,25 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/request"
 	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
 )
 
+var (
+	// ErrInvalidCursor is returned when a list cursor is malformed
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrUserNotDeleted is returned when restoring a user that is not deleted
+	ErrUserNotDeleted = errors.New("user is not deleted")
+	// ErrConcurrentModification is returned when a record changed between
+	// being read and written
+	ErrConcurrentModification = errors.New("resource was modified concurrently")
+	// ErrSearchUnsupported is returned when the configured database cannot
+	// search users
+	ErrSearchUnsupported = errors.New("user search is not supported by this database")
+)
+
 // UserService defines the interface for user operations
 type UserService interface {
 	// GetByID retrieves a user by ID
@@ -21,7 +35,20 @@ type UserService interface {
 	// List retrieves users with pagination
 	List(ctx context.Context, page, size int) (*response.PagedResponse[response.UserResponse], error)
 
-	// Update updates a user's profile
+	// ListIncludingDeleted retrieves users with pagination, soft-deleted ones
+	// included
+	ListIncludingDeleted(ctx context.Context, page, size int) (*response.PagedResponse[response.UserResponse], error)
+
+	// ListByCursor retrieves users in ID order starting after cursor, which is
+	// empty for the first page. Returns ErrInvalidCursor for a malformed cursor.
+	ListByCursor(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.UserResponse], error)
+
+	// Search retrieves users matching filters, sorted and paginated. Unknown
+	// sort fields fall back to newest first and the page size is capped.
+	Search(ctx context.Context, req *request.UserSearchRequest) (*response.PagedResponse[response.UserResponse], error)
+
+	// Update updates a user's profile. Returns ErrConcurrentModification if
+	// the request's lock version is stale or another update wins the race.
 	Update(ctx context.Context, id uint, req *request.UpdateProfileRequest) (*response.UserResponse, error)
 
 	// ChangePassword changes a user's password
@@ -30,6 +57,10 @@ type UserService interface {
 	// Delete soft-deletes a user
 	Delete(ctx context.Context, id uint) error
 
+	// Restore undoes the soft delete of a user. Returns ErrUserNotFound if
+	// no such user exists and ErrUserNotDeleted if the user is not deleted.
+	Restore(ctx context.Context, id uint) (*response.UserResponse, error)
+
 	// ExistsByUsername checks if a username exists
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 
@@ -0,0 +1,15 @@
+package request
+
+import "time"
+
+// AuditQueryRequest represents audit log filters and paging, bound from query
+// parameters. Times are RFC 3339; from is inclusive and to exclusive.
+type AuditQueryRequest struct {
+	ActorID *uint      `form:"actor_id"`
+	Actor   string     `form:"actor" binding:"max=100"`
+	Action  string     `form:"action" binding:"max=50"`
+	From    *time.Time `form:"from"`
+	To      *time.Time `form:"to"`
+	Page    int        `form:"page" binding:"omitempty,min=1"`
+	Size    int        `form:"size" binding:"omitempty,min=1,max=100"`
+}
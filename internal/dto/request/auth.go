@@ -1,5 +1,7 @@
 package request
 
+import "time"
+
 // RegisterRequest represents a user registration request
 type RegisterRequest struct {
 	Username  string `json:"username" binding:"required,min=3,max=50"`
@@ -31,4 +33,47 @@ type UpdateProfileRequest struct {
 	FirstName string `json:"first_name,omitempty" binding:"max=50"`
 	LastName  string `json:"last_name,omitempty" binding:"max=50"`
 	Email     string `json:"email,omitempty" binding:"omitempty,email,max=100"`
+	// LockVersion is the lock_version the client last read. If set, the
+	// update is rejected when the profile has changed since.
+	LockVersion *uint `json:"lock_version,omitempty"`
+}
+
+// UserSearchRequest represents user search filters, sorting and paging, bound
+// from query parameters. Dates are RFC 3339.
+type UserSearchRequest struct {
+	Role          string     `form:"role" binding:"omitempty,oneof=USER ADMIN"`
+	IsActive      *bool      `form:"is_active"`
+	IsVerified    *bool      `form:"is_verified"`
+	CreatedAfter  *time.Time `form:"created_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+	// Q matches usernames and emails starting with it
+	Q     string `form:"q" binding:"max=100"`
+	Sort  string `form:"sort" binding:"omitempty,oneof=id username email created_at"`
+	Order string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+	Size  int    `form:"size" binding:"omitempty,min=1,max=100"`
+}
+
+// PasswordResetRequest represents a request for a password reset token
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email,max=100"`
+}
+
+// PasswordResetConfirmRequest represents a password reset with a reset token
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72"`
+}
+
+// TOTPConfirmRequest confirms two-factor enrollment with a code from the
+// authenticator app
+type TOTPConfirmRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// TOTPVerifyRequest completes a two-factor login challenge with a TOTP code or
+// a recovery code
+type TOTPVerifyRequest struct {
+	ChallengeID string `json:"challenge_id" binding:"required"`
+	Code        string `json:"code" binding:"required,max=32"`
 }
@@ -7,12 +7,19 @@ type EnqueueJobRequest struct {
 	Type        string          `json:"type" binding:"required"`
 	Payload     json.RawMessage `json:"payload" binding:"required"`
 	Priority    string          `json:"priority,omitempty"` // low, normal, high, critical
+	Queue       string          `json:"queue,omitempty"`    // Named queue, "default" if empty
 	ScheduledAt string          `json:"scheduled_at,omitempty"` // RFC3339 format
 	DelaySeconds int            `json:"delay_seconds,omitempty"`
 	UniqueKey   string          `json:"unique_key,omitempty"`
+	UniqueForSeconds int        `json:"unique_for_seconds,omitempty"` // Block duplicates of unique_key for this long, even after the job finishes
 	Tags        []string        `json:"tags,omitempty"`
 }
 
+// SetWorkerConcurrencyRequest represents a worker pool scaling request
+type SetWorkerConcurrencyRequest struct {
+	Concurrency int `json:"concurrency" binding:"required,min=1,max=1024"`
+}
+
 // RetryJobRequest represents a job retry request
 type RetryJobRequest struct {
 	ResetAttempts bool `json:"reset_attempts,omitempty"`
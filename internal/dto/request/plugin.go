@@ -8,6 +8,21 @@ type InstallPluginRequest struct {
 	Author      string            `json:"author,omitempty" binding:"max=200"`
 	Type        string            `json:"type" binding:"required"`
 	Config      map[string]any    `json:"config,omitempty"`
+	// ConfigSchema is a JSON Schema the config must match, on install and on
+	// every later config update
+	ConfigSchema map[string]any   `json:"config_schema,omitempty"`
+}
+
+// InstallPluginFromURLRequest represents a request to install a plugin
+// downloaded from a URL
+type InstallPluginFromURLRequest struct {
+	InstallPluginRequest
+	URL string `json:"url" binding:"required,url"`
+	// Checksum is the expected hex SHA-256 of the artifact
+	Checksum string `json:"checksum,omitempty" binding:"omitempty,len=64,hexadecimal"`
+	// Signature is the base64 Ed25519 signature of the artifact, required
+	// when a signing key is configured
+	Signature string `json:"signature,omitempty" binding:"omitempty,base64"`
 }
 
 // UpdatePluginRequest represents a plugin update request
@@ -17,6 +32,19 @@ type UpdatePluginRequest struct {
 	Config      map[string]any `json:"config,omitempty"`
 }
 
+// UpgradePluginRequest represents a plugin upgrade request. Empty
+// description and author keep the current values.
+type UpgradePluginRequest struct {
+	Version     string `json:"version" binding:"required,max=50"`
+	Description string `json:"description,omitempty" binding:"max=1000"`
+	Author      string `json:"author,omitempty" binding:"max=200"`
+}
+
+// UpdatePluginConfigRequest represents a plugin config update request
+type UpdatePluginConfigRequest struct {
+	Config map[string]any `json:"config" binding:"required"`
+}
+
 // PluginActionRequest represents a plugin action request (enable/disable)
 type PluginActionRequest struct {
 	Action string `json:"action" binding:"required,oneof=enable disable"`
@@ -86,3 +86,29 @@ func NewPagedResponse[T any](items []T, page, size int, total int64) PagedRespon
 		},
 	}
 }
+
+// CursorPageInfo contains cursor pagination information
+type CursorPageInfo struct {
+	Size       int    `json:"size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasNext    bool   `json:"has_next"`
+}
+
+// CursorPagedResponse wraps a list response fetched by cursor. Pass
+// NextCursor back as the cursor parameter to fetch the following page.
+type CursorPagedResponse[T any] struct {
+	Items    []T            `json:"items"`
+	PageInfo CursorPageInfo `json:"page_info"`
+}
+
+// NewCursorPagedResponse creates a new cursor paged response
+func NewCursorPagedResponse[T any](items []T, size int, nextCursor string) CursorPagedResponse[T] {
+	return CursorPagedResponse[T]{
+		Items: items,
+		PageInfo: CursorPageInfo{
+			Size:       size,
+			NextCursor: nextCursor,
+			HasNext:    nextCursor != "",
+		},
+	}
+}
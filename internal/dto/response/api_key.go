@@ -0,0 +1,25 @@
+package response
+
+import (
+	"time"
+)
+
+// APIKeyResponse represents an API key in responses. Only the key's prefix is
+// ever returned after creation.
+type APIKeyResponse struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	CreatedBy uint       `json:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// APIKeyCreatedResponse represents a newly created API key. Key is shown only
+// once and cannot be retrieved later.
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,19 @@
+package response
+
+import (
+	"time"
+)
+
+// AuditLogResponse represents an audit log record in responses
+type AuditLogResponse struct {
+	ID        uint      `json:"id"`
+	ActorID   uint      `json:"actor_id"`
+	Actor     string    `json:"actor,omitempty"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
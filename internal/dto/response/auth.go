@@ -1,30 +1,92 @@
 package response
 
 import (
+	"strings"
 	"time"
 )
 
-// AuthResponse represents the authentication response
+// AuthResponse represents the authentication response. When a second factor
+// is required no tokens are issued and it carries TwoFactorRequired and
+// ChallengeID instead.
 type AuthResponse struct {
-	AccessToken  string       `json:"access_token"`
-	RefreshToken string       `json:"refresh_token"`
-	TokenType    string       `json:"token_type"`
-	ExpiresIn    int64        `json:"expires_in"`
-	User         UserResponse `json:"user"`
+	AccessToken       string       `json:"access_token,omitempty"`
+	RefreshToken      string       `json:"refresh_token,omitempty"`
+	TokenType         string       `json:"token_type,omitempty"`
+	ExpiresIn         int64        `json:"expires_in,omitempty"`
+	User              UserResponse `json:"user"`
+	TwoFactorRequired bool         `json:"two_factor_required,omitempty"`
+	ChallengeID       string       `json:"challenge_id,omitempty"`
 }
 
 // UserResponse represents user data in responses
 type UserResponse struct {
-	ID         uint      `json:"id"`
-	Username   string    `json:"username"`
-	Email      string    `json:"email"`
-	FirstName  string    `json:"first_name,omitempty"`
-	LastName   string    `json:"last_name,omitempty"`
-	Role       string    `json:"role"`
-	IsActive   bool      `json:"is_active"`
-	IsVerified bool      `json:"is_verified"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID          uint      `json:"id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	FirstName   string    `json:"first_name,omitempty"`
+	LastName    string    `json:"last_name,omitempty"`
+	Role        string    `json:"role"`
+	IsActive    bool      `json:"is_active"`
+	IsVerified  bool      `json:"is_verified"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	LockVersion uint      `json:"lock_version"`
+	// DeletedAt is set on soft-deleted users, which only admins listing
+	// with include_deleted see
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// UserResponseV2 represents user data in API v2 responses, which group the
+// name and account status
+type UserResponseV2 struct {
+	ID          uint         `json:"id"`
+	Username    string       `json:"username"`
+	Email       string       `json:"email"`
+	Name        UserNameV2   `json:"name"`
+	Role        string       `json:"role"`
+	Status      UserStatusV2 `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	LockVersion uint         `json:"lock_version"`
+}
+
+// UserNameV2 represents a user's name; Display falls back to the username
+type UserNameV2 struct {
+	First   string `json:"first,omitempty"`
+	Last    string `json:"last,omitempty"`
+	Display string `json:"display"`
+}
+
+// UserStatusV2 represents the state of a user's account
+type UserStatusV2 struct {
+	Active   bool `json:"active"`
+	Verified bool `json:"verified"`
+}
+
+// NewUserResponseV2 converts user data to its v2 shape
+func NewUserResponseV2(user *UserResponse) *UserResponseV2 {
+	display := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if display == "" {
+		display = user.Username
+	}
+	return &UserResponseV2{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Name: UserNameV2{
+			First:   user.FirstName,
+			Last:    user.LastName,
+			Display: display,
+		},
+		Role: user.Role,
+		Status: UserStatusV2{
+			Active:   user.IsActive,
+			Verified: user.IsVerified,
+		},
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		LockVersion: user.LockVersion,
+	}
 }
 
 // TokenResponse represents a token-only response
@@ -34,3 +96,27 @@ type TokenResponse struct {
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int64  `json:"expires_in"`
 }
+
+// TOTPSetupResponse represents a new TOTP secret to add to an authenticator app
+type TOTPSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+// TOTPRecoveryCodesResponse represents the single-use recovery codes issued
+// when two-factor authentication is enabled
+type TOTPRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// SessionResponse represents a signed-in session, backed by a refresh token
+type SessionResponse struct {
+	ID        uint      `json:"id"`
+	Device    string    `json:"device,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Current marks the session of the token making the request
+	Current bool `json:"current"`
+}
@@ -9,6 +9,7 @@ type JobResponse struct {
 	ID            string     `json:"id"`
 	Type          string     `json:"type"`
 	Priority      string     `json:"priority"`
+	Queue         string     `json:"queue"`
 	Status        string     `json:"status"`
 	Attempts      int        `json:"attempts"`
 	MaxRetries    int        `json:"max_retries"`
@@ -17,6 +18,8 @@ type JobResponse struct {
 	StartedAt     *time.Time `json:"started_at,omitempty"`
 	CompletedAt   *time.Time `json:"completed_at,omitempty"`
 	LastError     string     `json:"last_error,omitempty"`
+	LastStack     string     `json:"last_stack,omitempty"` // Stack of the panic behind LastError, if it was one
+	FailedAt      *time.Time `json:"failed_at,omitempty"`  // When the job last moved to the DLQ
 	CorrelationID string     `json:"correlation_id,omitempty"`
 	Tags          []string   `json:"tags,omitempty"`
 }
@@ -30,17 +33,21 @@ type QueueStatsResponse struct {
 	Failed         int64             `json:"failed"`
 	Dead           int64             `json:"dead"`
 	QueueSizes     map[string]int64  `json:"queue_sizes"`
+	QueueDepths    map[string]int64  `json:"queue_depths"`
+	PausedTypes    []string          `json:"paused_types"`
 	WorkerStats    WorkerStatsResponse    `json:"worker_stats"`
 	SchedulerStats SchedulerStatsResponse `json:"scheduler_stats"`
 }
 
 // WorkerStatsResponse represents worker pool statistics
 type WorkerStatsResponse struct {
-	Running       bool  `json:"running"`
-	ActiveWorkers int64 `json:"active_workers"`
-	Concurrency   int   `json:"concurrency"`
-	ProcessedJobs int64 `json:"processed_jobs"`
-	FailedJobs    int64 `json:"failed_jobs"`
+	Running       bool             `json:"running"`
+	ActiveWorkers int64            `json:"active_workers"`
+	Concurrency   int              `json:"concurrency"`
+	CurrentConcurrency int         `json:"current_concurrency"` // Workers running now; differs from concurrency while scaling
+	ProcessedJobs int64            `json:"processed_jobs"`
+	FailedJobs    int64            `json:"failed_jobs"`
+	ThrottledJobs map[string]int64 `json:"throttled_jobs,omitempty"`
 }
 
 // SchedulerStatsResponse represents scheduler statistics
@@ -50,17 +57,55 @@ type SchedulerStatsResponse struct {
 	ScheduledJobNames []string `json:"scheduled_job_names"`
 }
 
+// SchedulerStatusResponse represents the scheduler leader election state
+type SchedulerStatusResponse struct {
+	InstanceID     string     `json:"instance_id"` // The instance that answered
+	IsLeader       bool       `json:"is_leader"`
+	LeaderID       string     `json:"leader_id,omitempty"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+	LastElectionAt *time.Time `json:"last_election_at,omitempty"`
+}
+
 // ScheduledJobResponse represents a scheduled job
 type ScheduledJobResponse struct {
 	Name     string    `json:"name"`
 	Schedule string    `json:"schedule"`
 	JobType  string    `json:"job_type"`
-	NextRun  time.Time `json:"next_run"`
+	NextRun  time.Time `json:"next_run"` // UTC
 	Priority string    `json:"priority"`
+	Timezone string    `json:"timezone"`
+	Enabled  bool      `json:"enabled"`
+	// LastTriggeredAt is when the job was last enqueued, on schedule or manually
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+}
+
+// JobProgressResponse represents the latest progress reported by a job
+type JobProgressResponse struct {
+	JobID     string    `json:"job_id"`
+	Percent   int       `json:"percent"`
+	Message   string    `json:"message"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // JobEnqueueResponse represents the response after enqueuing a job
 type JobEnqueueResponse struct {
 	JobID   string `json:"job_id"`
 	Message string `json:"message"`
+	// Backlog at the job's queue and priority, only with include_backlog=true
+	QueueDepth           *int64   `json:"queue_depth,omitempty"`
+	EstimatedWaitSeconds *float64 `json:"estimated_wait_seconds,omitempty"` // Omitted without recent throughput to go by
+}
+
+// BatchEnqueueItem reports the outcome of one job in a batch enqueue
+type BatchEnqueueItem struct {
+	Index int    `json:"index"`
+	JobID string `json:"job_id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// BatchEnqueueResponse represents the response after enqueuing a batch of jobs
+type BatchEnqueueResponse struct {
+	Jobs     []BatchEnqueueItem `json:"jobs"` // In request order
+	Enqueued int                `json:"enqueued"`
+	Failed   int                `json:"failed"`
 }
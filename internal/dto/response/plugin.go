@@ -16,6 +16,7 @@ type PluginResponse struct {
 	State       string     `json:"state"`
 	InstalledAt time.Time  `json:"installed_at"`
 	EnabledAt   *time.Time `json:"enabled_at,omitempty"`
+	LockVersion uint       `json:"lock_version"`
 }
 
 // PluginHealthResponse represents plugin system health status
@@ -36,9 +37,27 @@ type PluginExtensionResponse struct {
 	Handler string `json:"handler,omitempty"`
 }
 
+// PluginVersionResponse represents a previous plugin version kept for rollback
+type PluginVersionResponse struct {
+	Version     string    `json:"version"`
+	Checksum    string    `json:"checksum"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// PluginStateTransitionResponse represents a change of a plugin's state
+type PluginStateTransitionResponse struct {
+	FromState string    `json:"from_state,omitempty"`
+	ToState   string    `json:"to_state"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // PluginDetailResponse represents detailed plugin information
 type PluginDetailResponse struct {
 	PluginResponse
 	Extensions []PluginExtensionResponse `json:"extensions,omitempty"`
 	Config     map[string]any            `json:"config,omitempty"`
+	// PreviousVersions lists the versions Rollback can return to, most
+	// recent first
+	PreviousVersions []PluginVersionResponse `json:"previous_versions,omitempty"`
 }
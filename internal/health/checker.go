@@ -0,0 +1,161 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is the health of a component or of the whole system
+type Status string
+
+const (
+	StatusHealthy   Status = "healthy"
+	StatusDegraded  Status = "degraded"
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// DefaultTimeout bounds checks registered without a timeout of their own
+const DefaultTimeout = 5 * time.Second
+
+// CheckFunc reports a component healthy by returning nil
+type CheckFunc func(ctx context.Context) error
+
+// Check is a component health check
+type Check struct {
+	Name  string
+	Check CheckFunc
+	// Critical checks gate readiness and make the system unhealthy when they
+	// fail; a failing non-critical check only degrades it
+	Critical bool
+	Timeout  time.Duration // DefaultTimeout when zero
+}
+
+// ComponentStatus is the outcome of one check
+type ComponentStatus struct {
+	Status     Status `json:"status"`
+	Critical   bool   `json:"critical"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of a run of checks
+type Report struct {
+	Status     Status                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// Checker runs the registered component checks
+type Checker struct {
+	mu     sync.RWMutex
+	checks []Check
+}
+
+// NewChecker creates an empty checker
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Register adds a check, replacing any check with the same name
+func (c *Checker) Register(check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, existing := range c.checks {
+		if existing.Name == check.Name {
+			c.checks[i] = check
+			return
+		}
+	}
+	c.checks = append(c.checks, check)
+}
+
+// Run runs every check in parallel. The system is unhealthy when a critical
+// check fails and degraded when only non-critical ones do.
+func (c *Checker) Run(ctx context.Context) *Report {
+	return c.run(ctx, false)
+}
+
+// Readiness runs only the critical checks; the report is healthy when the
+// system can serve traffic
+func (c *Checker) Readiness(ctx context.Context) *Report {
+	return c.run(ctx, true)
+}
+
+func (c *Checker) run(ctx context.Context, criticalOnly bool) *Report {
+	c.mu.RLock()
+	checks := make([]Check, 0, len(c.checks))
+	for _, check := range c.checks {
+		if check.Critical || !criticalOnly {
+			checks = append(checks, check)
+		}
+	}
+	c.mu.RUnlock()
+
+	results := make([]ComponentStatus, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusHealthy, Components: make(map[string]ComponentStatus, len(checks))}
+	for i, check := range checks {
+		result := results[i]
+		report.Components[check.Name] = result
+		if result.Status == StatusHealthy {
+			continue
+		}
+		if check.Critical {
+			report.Status = StatusUnhealthy
+		} else if report.Status == StatusHealthy {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// runCheck runs check under its timeout. A check that ignores its context is
+// reported as failed once the timeout passes rather than holding up the run.
+func runCheck(ctx context.Context, check Check) ComponentStatus {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", timeout)
+	}
+
+	status := ComponentStatus{
+		Status:     StatusHealthy,
+		Critical:   check.Critical,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		status.Status = StatusUnhealthy
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func healthy(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("connection refused") }
+
+func TestChecker_Run(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   Status
+	}{
+		{"no checks", nil, StatusHealthy},
+		{"all healthy", []Check{
+			{Name: "redis", Check: healthy, Critical: true},
+			{Name: "plugins", Check: healthy},
+		}, StatusHealthy},
+		{"non-critical failure", []Check{
+			{Name: "redis", Check: healthy, Critical: true},
+			{Name: "plugins", Check: failing},
+		}, StatusDegraded},
+		{"critical failure", []Check{
+			{Name: "redis", Check: failing, Critical: true},
+			{Name: "plugins", Check: failing},
+		}, StatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker()
+			for _, check := range tt.checks {
+				c.Register(check)
+			}
+			report := c.Run(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("Status = %s, want %s", report.Status, tt.want)
+			}
+			if len(report.Components) != len(tt.checks) {
+				t.Errorf("Components = %d, want %d", len(report.Components), len(tt.checks))
+			}
+		})
+	}
+}
+
+func TestChecker_RunsInParallelWithTimeouts(t *testing.T) {
+	c := NewChecker()
+	slow := func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}
+	c.Register(Check{Name: "a", Check: slow})
+	c.Register(Check{Name: "b", Check: slow})
+	// Ignores its context, so only the checker's timeout can end it
+	c.Register(Check{Name: "stuck", Check: func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, Timeout: 50 * time.Millisecond, Critical: true})
+
+	start := time.Now()
+	report := c.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run took %s, want checks run in parallel", elapsed)
+	}
+
+	stuck := report.Components["stuck"]
+	if stuck.Status != StatusUnhealthy || stuck.Error == "" {
+		t.Errorf("stuck = %+v, want unhealthy with a timeout error", stuck)
+	}
+	if report.Components["a"].Status != StatusHealthy {
+		t.Errorf("a = %+v, want healthy", report.Components["a"])
+	}
+}
+
+func TestChecker_RegisterReplaces(t *testing.T) {
+	c := NewChecker()
+	c.Register(Check{Name: "redis", Check: failing, Critical: true})
+	c.Register(Check{Name: "redis", Check: healthy, Critical: true})
+
+	report := c.Run(context.Background())
+	if report.Status != StatusHealthy || len(report.Components) != 1 {
+		t.Errorf("report = %+v, want one healthy component", report)
+	}
+}
+
+func TestChecker_PanickingCheck(t *testing.T) {
+	c := NewChecker()
+	c.Register(Check{Name: "bad", Check: func(context.Context) error { panic("boom") }})
+
+	if got := c.Run(context.Background()).Components["bad"].Status; got != StatusUnhealthy {
+		t.Errorf("Status = %s, want unhealthy", got)
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	c := NewChecker()
+	c.Register(Check{Name: "database", Check: healthy, Critical: true})
+	c.Register(Check{Name: "plugins", Check: failing})
+
+	serve := func(h http.HandlerFunc) (*httptest.ResponseRecorder, map[string]any) {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+		}
+		return w, body
+	}
+
+	w, body := serve(Handler(c))
+	if w.Code != http.StatusOK || body["status"] != string(StatusDegraded) {
+		t.Errorf("/health = %d %v, want 200 degraded", w.Code, body)
+	}
+
+	// The failing check is not critical, so the system is still ready
+	w, body = serve(ReadyHandler(c))
+	if w.Code != http.StatusOK || body["status"] != "ready" {
+		t.Errorf("/health/ready = %d %v, want 200 ready", w.Code, body)
+	}
+
+	c.Register(Check{Name: "database", Check: failing, Critical: true})
+	w, body = serve(Handler(c))
+	if w.Code != http.StatusServiceUnavailable || body["status"] != string(StatusUnhealthy) {
+		t.Errorf("/health = %d %v, want 503 unhealthy", w.Code, body)
+	}
+	w, body = serve(ReadyHandler(c))
+	if w.Code != http.StatusServiceUnavailable || body["status"] != "not_ready" {
+		t.Errorf("/health/ready = %d %v, want 503 not_ready", w.Code, body)
+	}
+
+	w, body = serve(LiveHandler())
+	if w.Code != http.StatusOK || body["status"] != "alive" {
+		t.Errorf("/health/live = %d %v, want 200 alive", w.Code, body)
+	}
+}
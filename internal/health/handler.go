@@ -0,0 +1,49 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler returns the GET /health handler, reporting every component. It
+// responds 503 when a critical component is unhealthy.
+func Handler(c *Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		code := http.StatusOK
+		if report.Status == StatusUnhealthy {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, report)
+	}
+}
+
+// ReadyHandler returns the readiness probe handler. It responds 503 until
+// every critical component is healthy.
+func ReadyHandler(c *Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Readiness(r.Context())
+		if report.Status != StatusHealthy {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]any{
+				"status":     "not_ready",
+				"components": report.Components,
+			})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ready"})
+	}
+}
+
+// LiveHandler returns the liveness probe handler, which only reports that
+// the process is serving requests
+func LiveHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"status": "alive"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
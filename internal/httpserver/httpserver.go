@@ -0,0 +1,59 @@
+// Package httpserver starts and gracefully stops the application's HTTP server.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Start listens on the server's address and serves in the background.
+// Listening happens before Start returns, so an address already in use fails
+// startup instead of being logged after the app has started.
+func Start(server *http.Server, logger *zap.Logger) error {
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+	Serve(server, listener, logger)
+	return nil
+}
+
+// Serve serves connections accepted on listener in the background
+func Serve(server *http.Server, listener net.Listener, logger *zap.Logger) {
+	logger.Info("Starting HTTP server", zap.String("address", listener.Addr().String()))
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("HTTP server error", zap.Error(err))
+		}
+	}()
+}
+
+// Shutdown stops accepting new connections and waits for in-flight requests
+// to complete, for at most timeout (0 waits until ctx is done). Connections
+// still active when the wait ends are closed.
+func Shutdown(ctx context.Context, server *http.Server, timeout time.Duration, logger *zap.Logger) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	logger.Info("Draining HTTP server", zap.Duration("timeout", timeout))
+	start := time.Now()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Warn("HTTP server drain timed out, closing active connections",
+			zap.Duration("waited", time.Since(start)),
+			zap.Error(err),
+		)
+		_ = server.Close()
+		return err
+	}
+
+	logger.Info("HTTP server drained", zap.Duration("waited", time.Since(start)))
+	return nil
+}
@@ -0,0 +1,134 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// slowServer serves a handler that blocks until release is closed
+func slowServer(t *testing.T) (server *http.Server, url string, started, release chan struct{}) {
+	t.Helper()
+
+	started = make(chan struct{}, 1)
+	release = make(chan struct{})
+	server = &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	Serve(server, listener, zap.NewNop())
+	t.Cleanup(func() { _ = server.Close() })
+
+	return server, "http://" + listener.Addr().String(), started, release
+}
+
+type result struct {
+	body string
+	err  error
+}
+
+func get(url string) <-chan result {
+	done := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		done <- result{body: string(body), err: err}
+	}()
+	return done
+}
+
+func TestShutdown_DrainsInFlightRequests(t *testing.T) {
+	server, url, started, release := slowServer(t)
+
+	inFlight := get(url)
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- Shutdown(context.Background(), server, 5*time.Second, zap.NewNop())
+	}()
+
+	// New connections are refused while the in-flight request drains
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", url[len("http://"):], 100*time.Millisecond)
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("server still accepts connections during shutdown")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown() returned %v before the in-flight request finished", err)
+	default:
+	}
+
+	close(release)
+
+	res := <-inFlight
+	if res.err != nil || res.body != "done" {
+		t.Errorf("in-flight request = %q, %v; want it to complete", res.body, res.err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+func TestShutdown_Timeout(t *testing.T) {
+	server, url, started, release := slowServer(t)
+	defer close(release)
+
+	inFlight := get(url)
+	<-started
+
+	err := Shutdown(context.Background(), server, 50*time.Millisecond, zap.NewNop())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The request still active at the deadline has its connection closed
+	select {
+	case res := <-inFlight:
+		if res.err == nil {
+			t.Error("in-flight request should fail once the drain times out")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("in-flight request still open after the drain timed out")
+	}
+}
+
+func TestStart_AddressInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	server := &http.Server{Addr: listener.Addr().String()}
+	if err := Start(server, zap.NewNop()); err == nil {
+		server.Close()
+		t.Error("Start() should fail when the address is in use")
+	}
+}
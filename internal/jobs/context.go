@@ -0,0 +1,34 @@
+package jobs
+
+import "context"
+
+type contextKey string
+
+const jobIDContextKey contextKey = "job_id"
+
+// ContextWithJobID returns a context carrying the ID of the job being executed
+func ContextWithJobID(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, jobIDContextKey, jobID)
+}
+
+// JobIDFromContext returns the ID of the job being executed, if any
+func JobIDFromContext(ctx context.Context) (string, bool) {
+	jobID, ok := ctx.Value(jobIDContextKey).(string)
+	return jobID, ok && jobID != ""
+}
+
+const fencingTokenContextKey contextKey = "fencing_token"
+
+// ContextWithFencingToken returns a context carrying the fencing token of the
+// lock held for the job being executed
+func ContextWithFencingToken(ctx context.Context, token int64) context.Context {
+	return context.WithValue(ctx, fencingTokenContextKey, token)
+}
+
+// FencingTokenFromContext returns the fencing token of the job being executed,
+// if it runs under a distributed lock. Pass it to lock.LockManager.ValidateToken
+// or to downstream resources before performing side effects.
+func FencingTokenFromContext(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(fencingTokenContextKey).(int64)
+	return token, ok
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// Cleanup types of the built-in tasks
+const (
+	// CleanupExpiredTokens removes refresh tokens expired for OlderThan days
+	CleanupExpiredTokens = "expired_tokens"
+	// CleanupExpiredPasswordResets removes password reset tokens expired for
+	// OlderThan days
+	CleanupExpiredPasswordResets = "expired_password_resets"
+	// CleanupOrphanedPlugins purges plugins soft-deleted OlderThan days ago,
+	// with their extensions and state history, freeing their keys
+	CleanupOrphanedPlugins = "orphaned_plugins"
+	// CleanupOldAuditLogs removes audit logs recorded OlderThan days ago
+	CleanupOldAuditLogs = "old_audit_logs"
+)
+
+// DefaultAuditLogMinDays is the fewest days of audit logs a cleanup job may
+// keep unless configured otherwise
+const DefaultAuditLogMinDays = 90
+
+// CleanupResult reports what a cleanup job removed
+type CleanupResult struct {
+	Type          string    `json:"type"`
+	OlderThanDays int       `json:"older_than_days"`
+	Cutoff        time.Time `json:"cutoff"`
+	DryRun        bool      `json:"dry_run"`
+	// Affected counts the rows removed, or on a dry run the rows that would be
+	Affected   int64     `json:"affected"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// CleanupTask removes the records of one cleanup type older than a cutoff
+type CleanupTask struct {
+	// Delete removes the records older than before and returns how many it
+	// removed
+	Delete func(ctx context.Context, before time.Time) (int64, error)
+	// Count counts the records Delete would remove, for dry runs
+	Count func(ctx context.Context, before time.Time) (int64, error)
+	// MinOlderThan is the fewest days a job may keep. Jobs asking for fewer
+	// fail, so a payload missing older_than_days cannot wipe recent records.
+	MinOlderThan int
+}
+
+// CleanupTasks maps cleanup types to the tasks run for them
+type CleanupTasks map[string]CleanupTask
+
+// Types lists the cleanup types, sorted
+func (t CleanupTasks) Types() []string {
+	types := make([]string, 0, len(t))
+	for cleanupType := range t {
+		types = append(types, cleanupType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// DefaultCleanupTasks returns the built-in cleanup tasks. Audit logs must be
+// kept at least auditLogMinDays days (DefaultAuditLogMinDays when not
+// positive) and plugins at least a day; expired tokens may be removed as soon
+// as they expire.
+func DefaultCleanupTasks(
+	refreshTokens repository.RefreshTokenRepository,
+	resetTokens repository.PasswordResetTokenRepository,
+	plugins repository.PluginRepository,
+	auditLogs repository.AuditLogRepository,
+	auditLogMinDays int,
+) CleanupTasks {
+	if auditLogMinDays <= 0 {
+		auditLogMinDays = DefaultAuditLogMinDays
+	}
+	return CleanupTasks{
+		CleanupExpiredTokens: {
+			Delete: refreshTokens.DeleteExpired,
+			Count:  refreshTokens.CountExpired,
+		},
+		CleanupExpiredPasswordResets: {
+			Delete: resetTokens.DeleteExpired,
+			Count:  resetTokens.CountExpired,
+		},
+		CleanupOrphanedPlugins: {
+			Delete:       plugins.PurgeDeleted,
+			Count:        plugins.CountDeleted,
+			MinOlderThan: 1,
+		},
+		CleanupOldAuditLogs: {
+			Delete:       auditLogs.DeleteBefore,
+			Count:        auditLogs.CountBefore,
+			MinOlderThan: auditLogMinDays,
+		},
+	}
+}
+
+// NewCleanupHandler returns the handler for cleanup jobs. It runs the task
+// of the payload's type on the records older than OlderThan days, counting
+// them instead on a dry run, and returns how many were affected. An unknown
+// type or an OlderThan below the task's minimum fails permanently.
+//
+// Deleting records older than a cutoff is idempotent, so a retried or
+// overlapping job removes each record once and reports only the rows it
+// removed itself. Scheduled cleanups are Singleton, so overlaps only come
+// from jobs enqueued by hand.
+func NewCleanupHandler(tasks CleanupTasks, logger *zap.Logger) ResultHandlerFunc[CleanupJobPayload, CleanupResult] {
+	return func(ctx context.Context, payload CleanupJobPayload) (CleanupResult, error) {
+		task, ok := tasks[payload.Type]
+		if !ok {
+			return CleanupResult{}, jobs.Permanent(fmt.Errorf("unknown cleanup type %q (available: %v)",
+				payload.Type, tasks.Types()))
+		}
+		if payload.OlderThan < 0 {
+			return CleanupResult{}, jobs.Permanent(fmt.Errorf("older_than_days must not be negative, got %d", payload.OlderThan))
+		}
+		if payload.OlderThan < task.MinOlderThan {
+			return CleanupResult{}, jobs.Permanent(fmt.Errorf("older_than_days must be at least %d for %s cleanup, got %d",
+				task.MinOlderThan, payload.Type, payload.OlderThan))
+		}
+
+		result := CleanupResult{
+			Type:          payload.Type,
+			OlderThanDays: payload.OlderThan,
+			Cutoff:        time.Now().UTC().AddDate(0, 0, -payload.OlderThan),
+			DryRun:        payload.DryRun,
+		}
+
+		run := task.Delete
+		if payload.DryRun {
+			if task.Count == nil {
+				return CleanupResult{}, jobs.Permanent(fmt.Errorf("%s cleanup does not support dry runs", payload.Type))
+			}
+			run = task.Count
+		}
+
+		affected, err := run(ctx, result.Cutoff)
+		if err != nil {
+			return CleanupResult{}, fmt.Errorf("%s cleanup failed: %w", payload.Type, err)
+		}
+		result.Affected = affected
+		result.FinishedAt = time.Now().UTC()
+
+		logger.Info("Cleanup completed",
+			zap.String("type", payload.Type),
+			zap.Int("older_than_days", payload.OlderThan),
+			zap.Time("cutoff", result.Cutoff),
+			zap.Bool("dry_run", payload.DryRun),
+			zap.Int64("affected", affected),
+		)
+		return result, nil
+	}
+}
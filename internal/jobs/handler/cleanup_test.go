@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
+)
+
+type cleanupRepos struct {
+	refreshTokens *mocks.MockRefreshTokenRepository
+	resetTokens   *mocks.MockPasswordResetTokenRepository
+	plugins       *mocks.MockPluginRepository
+	auditLogs     *mocks.MockAuditLogRepository
+}
+
+func newCleanupRepos() *cleanupRepos {
+	return &cleanupRepos{
+		refreshTokens: mocks.NewMockRefreshTokenRepository(),
+		resetTokens:   mocks.NewMockPasswordResetTokenRepository(),
+		plugins:       mocks.NewMockPluginRepository(),
+		auditLogs:     mocks.NewMockAuditLogRepository(),
+	}
+}
+
+func (r *cleanupRepos) handler() ResultHandlerFunc[CleanupJobPayload, CleanupResult] {
+	return NewCleanupHandler(DefaultCleanupTasks(r.refreshTokens, r.resetTokens, r.plugins, r.auditLogs, 0), zap.NewNop())
+}
+
+func daysAgo(days int) time.Time {
+	return time.Now().AddDate(0, 0, -days)
+}
+
+func TestCleanupHandler_ExpiredTokens(t *testing.T) {
+	repos := newCleanupRepos()
+	repos.refreshTokens.AddToken(&entity.RefreshToken{Token: "long-expired", ExpiresAt: daysAgo(40)})
+	repos.refreshTokens.AddToken(&entity.RefreshToken{Token: "recently-expired", ExpiresAt: daysAgo(5)})
+	repos.refreshTokens.AddToken(&entity.RefreshToken{Token: "active", ExpiresAt: time.Now().Add(time.Hour)})
+
+	result, err := repos.handler()(context.Background(), CleanupJobPayload{Type: CleanupExpiredTokens, OlderThan: 30})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if result.Affected != 1 || result.Type != CleanupExpiredTokens || result.OlderThanDays != 30 || result.DryRun {
+		t.Errorf("result = %+v", result)
+	}
+	if want := daysAgo(30); result.Cutoff.Sub(want).Abs() > time.Minute {
+		t.Errorf("Cutoff = %v, want about %v", result.Cutoff, want)
+	}
+
+	ctx := context.Background()
+	if token, _ := repos.refreshTokens.GetByToken(ctx, "long-expired"); token != nil {
+		t.Error("token expired 40 days ago was kept")
+	}
+	for _, kept := range []string{"recently-expired", "active"} {
+		if token, _ := repos.refreshTokens.GetByToken(ctx, kept); token == nil {
+			t.Errorf("token %q was removed", kept)
+		}
+	}
+}
+
+func TestCleanupHandler_ZeroDaysRemovesAllExpiredTokens(t *testing.T) {
+	repos := newCleanupRepos()
+	repos.refreshTokens.AddToken(&entity.RefreshToken{Token: "expired", ExpiresAt: time.Now().Add(-time.Minute)})
+	repos.refreshTokens.AddToken(&entity.RefreshToken{Token: "active", ExpiresAt: time.Now().Add(time.Hour)})
+
+	result, err := repos.handler()(context.Background(), CleanupJobPayload{Type: CleanupExpiredTokens})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if result.Affected != 1 {
+		t.Errorf("Affected = %d, want 1", result.Affected)
+	}
+}
+
+func TestCleanupHandler_ExpiredPasswordResets(t *testing.T) {
+	repos := newCleanupRepos()
+	ctx := context.Background()
+	for _, expiresAt := range []time.Time{daysAgo(10), daysAgo(8), time.Now().Add(time.Hour)} {
+		if err := repos.resetTokens.Create(ctx, &entity.PasswordResetToken{ExpiresAt: expiresAt}); err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+	}
+
+	result, err := repos.handler()(ctx, CleanupJobPayload{Type: CleanupExpiredPasswordResets, OlderThan: 7})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if result.Affected != 2 {
+		t.Errorf("Affected = %d, want 2", result.Affected)
+	}
+	if n := len(repos.resetTokens.Tokens()); n != 1 {
+		t.Errorf("tokens left = %d, want 1", n)
+	}
+}
+
+func TestCleanupHandler_OrphanedPlugins(t *testing.T) {
+	repos := newCleanupRepos()
+	repos.plugins.AddPlugin(&entity.Plugin{Key: "old", DeletedAt: gorm.DeletedAt{Time: daysAgo(31), Valid: true}})
+	repos.plugins.AddPlugin(&entity.Plugin{Key: "recent", DeletedAt: gorm.DeletedAt{Time: daysAgo(2), Valid: true}})
+	repos.plugins.AddPlugin(&entity.Plugin{Key: "live"})
+
+	result, err := repos.handler()(context.Background(), CleanupJobPayload{Type: CleanupOrphanedPlugins, OlderThan: 30})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if result.Affected != 1 {
+		t.Errorf("Affected = %d, want 1", result.Affected)
+	}
+	ctx := context.Background()
+	if plugin, _ := repos.plugins.GetByKey(ctx, "old"); plugin != nil {
+		t.Error("plugin deleted 31 days ago was kept")
+	}
+	for _, kept := range []string{"recent", "live"} {
+		if plugin, _ := repos.plugins.GetByKey(ctx, kept); plugin == nil {
+			t.Errorf("plugin %q was purged", kept)
+		}
+	}
+}
+
+func TestCleanupHandler_OldAuditLogs(t *testing.T) {
+	repos := newCleanupRepos()
+	ctx := context.Background()
+	for _, age := range []int{100, 91, 10} {
+		log := &entity.AuditLog{Action: "user.login"}
+		if err := repos.auditLogs.Create(ctx, log); err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+		log.CreatedAt = daysAgo(age)
+	}
+
+	result, err := repos.handler()(ctx, CleanupJobPayload{Type: CleanupOldAuditLogs, OlderThan: 90})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if result.Affected != 2 {
+		t.Errorf("Affected = %d, want 2", result.Affected)
+	}
+	if logs := repos.auditLogs.Logs(); len(logs) != 1 || logs[0].CreatedAt.Before(daysAgo(11)) {
+		t.Errorf("logs left = %+v, want the 10 day old one", logs)
+	}
+}
+
+func TestCleanupHandler_AuditLogMinDaysConfigurable(t *testing.T) {
+	repos := newCleanupRepos()
+	tasks := DefaultCleanupTasks(repos.refreshTokens, repos.resetTokens, repos.plugins, repos.auditLogs, 365)
+	cleanup := NewCleanupHandler(tasks, zap.NewNop())
+
+	_, err := cleanup(context.Background(), CleanupJobPayload{Type: CleanupOldAuditLogs, OlderThan: 180})
+	if err == nil || !jobs.IsPermanent(err) {
+		t.Errorf("error = %v, want a permanent error below the configured floor", err)
+	}
+	if _, err := cleanup(context.Background(), CleanupJobPayload{Type: CleanupOldAuditLogs, OlderThan: 365}); err != nil {
+		t.Errorf("handler error = %v", err)
+	}
+}
+
+func TestCleanupHandler_DryRunCountsWithoutDeleting(t *testing.T) {
+	repos := newCleanupRepos()
+	repos.refreshTokens.AddToken(&entity.RefreshToken{Token: "a", ExpiresAt: daysAgo(40)})
+	repos.refreshTokens.AddToken(&entity.RefreshToken{Token: "b", ExpiresAt: daysAgo(35)})
+
+	result, err := repos.handler()(context.Background(), CleanupJobPayload{Type: CleanupExpiredTokens, OlderThan: 30, DryRun: true})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if result.Affected != 2 || !result.DryRun {
+		t.Errorf("result = %+v", result)
+	}
+	for _, token := range []string{"a", "b"} {
+		if found, _ := repos.refreshTokens.GetByToken(context.Background(), token); found == nil {
+			t.Errorf("dry run removed token %q", token)
+		}
+	}
+}
+
+func TestCleanupHandler_RejectsInvalidPayloads(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload CleanupJobPayload
+	}{
+		{"unknown type", CleanupJobPayload{Type: "old_logs", OlderThan: 30}},
+		{"negative days", CleanupJobPayload{Type: CleanupExpiredTokens, OlderThan: -1}},
+		{"audit logs without days", CleanupJobPayload{Type: CleanupOldAuditLogs}},
+		{"audit logs below the retention floor", CleanupJobPayload{Type: CleanupOldAuditLogs, OlderThan: DefaultAuditLogMinDays - 1}},
+		{"plugins without days", CleanupJobPayload{Type: CleanupOrphanedPlugins}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := newCleanupRepos()
+			repos.auditLogs.DeleteBeforeErr = errors.New("must not run")
+			repos.plugins.PurgeDeletedErr = errors.New("must not run")
+
+			_, err := repos.handler()(context.Background(), tt.payload)
+			if err == nil || !jobs.IsPermanent(err) {
+				t.Errorf("error = %v, want a permanent error", err)
+			}
+		})
+	}
+}
+
+func TestCleanupHandler_RepositoryErrorIsRetryable(t *testing.T) {
+	repos := newCleanupRepos()
+	repos.auditLogs.DeleteBeforeErr = errors.New("connection reset")
+
+	_, err := repos.handler()(context.Background(), CleanupJobPayload{Type: CleanupOldAuditLogs, OlderThan: 90})
+	if err == nil || jobs.IsPermanent(err) {
+		t.Errorf("error = %v, want a retryable error", err)
+	}
+}
+
+func TestCleanupHandler_ConcurrentRunsRemoveEachRowOnce(t *testing.T) {
+	repos := newCleanupRepos()
+	for i := 0; i < 50; i++ {
+		repos.refreshTokens.AddToken(&entity.RefreshToken{ExpiresAt: daysAgo(40)})
+	}
+	handle := repos.handler()
+
+	var total atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := handle(context.Background(), CleanupJobPayload{Type: CleanupExpiredTokens, OlderThan: 30})
+			if err != nil {
+				t.Errorf("handler error = %v", err)
+				return
+			}
+			total.Add(result.Affected)
+		}()
+	}
+	wg.Wait()
+
+	if total.Load() != 50 {
+		t.Errorf("rows removed across runs = %d, want 50", total.Load())
+	}
+}
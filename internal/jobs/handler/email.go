@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// ErrInvalidRecipient is returned by an EmailSender for an address that can
+// never receive mail, such as a malformed or rejected one
+var ErrInvalidRecipient = errors.New("invalid email recipient")
+
+// ErrUnknownTemplate is returned when an email names a template that is not loaded
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// EmailMessage is a rendered email ready to send
+type EmailMessage struct {
+	To      []string
+	Subject string
+	Body    string
+	HTML    bool // Body is HTML rather than plain text
+}
+
+// EmailSender delivers email. Implementations return an error wrapping
+// ErrInvalidRecipient for recipients that will never accept the message; any
+// other error is treated as transient.
+type EmailSender interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
+
+// LogEmailSender logs emails instead of sending them, for environments
+// without a mail server
+type LogEmailSender struct {
+	logger *zap.Logger
+}
+
+// NewLogEmailSender creates a LogEmailSender
+func NewLogEmailSender(logger *zap.Logger) *LogEmailSender {
+	return &LogEmailSender{logger: logger}
+}
+
+// Send logs the message
+func (s *LogEmailSender) Send(_ context.Context, msg EmailMessage) error {
+	s.logger.Info("Email not sent, no mail server configured",
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+	)
+	return nil
+}
+
+// EmailTemplates holds the HTML email templates, named after their files
+// without the .html extension
+type EmailTemplates struct {
+	templates *template.Template
+}
+
+// LoadEmailTemplates parses every .html file in dir. A missing or empty
+// directory yields an empty set.
+func LoadEmailTemplates(dir string) (*EmailTemplates, error) {
+	t := template.New("")
+	files, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email templates: %w", err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email template %s: %w", file, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(file), ".html")
+		if _, err := t.New(name).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("failed to parse email template %s: %w", file, err)
+		}
+	}
+	return &EmailTemplates{templates: t}, nil
+}
+
+// Render executes the named template with data
+func (t *EmailTemplates) Render(name string, data any) (string, error) {
+	tmpl := t.templates.Lookup(name)
+	if tmpl == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// NewEmailHandler returns the handler for email jobs. Payloads that can never
+// be delivered (no recipients, an unknown template, a rejected recipient) fail
+// permanently; other send failures are retried.
+func NewEmailHandler(sender EmailSender, templates *EmailTemplates) HandlerFunc[EmailJobPayload] {
+	return func(ctx context.Context, payload EmailJobPayload) error {
+		if len(payload.To) == 0 {
+			return jobs.Permanent(fmt.Errorf("%w: no recipients", ErrInvalidRecipient))
+		}
+
+		msg := EmailMessage{To: payload.To, Subject: payload.Subject, Body: payload.Body}
+		if name := payload.templateName(); name != "" {
+			body, err := templates.Render(name, payload.TemplateData)
+			if err != nil {
+				return jobs.Permanent(err)
+			}
+			msg.Body = body
+			msg.HTML = true
+		}
+
+		if err := sender.Send(ctx, msg); err != nil {
+			if errors.Is(err, ErrInvalidRecipient) {
+				return jobs.Permanent(err)
+			}
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	}
+}
+
+// templateName returns the template to render, honouring the deprecated TemplateID
+func (p EmailJobPayload) templateName() string {
+	if p.TemplateName != "" {
+		return p.TemplateName
+	}
+	return p.TemplateID
+}
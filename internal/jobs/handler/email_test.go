@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// mockEmailSender records sent messages, failing with err if set
+type mockEmailSender struct {
+	sent []EmailMessage
+	err  error
+}
+
+func (m *mockEmailSender) Send(_ context.Context, msg EmailMessage) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func loadTestTemplates(t *testing.T) *EmailTemplates {
+	t.Helper()
+	dir := t.TempDir()
+	content := `<p>Hello {{.name}}, your code is {{.code}}</p>`
+	if err := os.WriteFile(filepath.Join(dir, "welcome.html"), []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	templates, err := LoadEmailTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadEmailTemplates() error = %v", err)
+	}
+	return templates
+}
+
+func TestEmailHandler_Template(t *testing.T) {
+	sender := &mockEmailSender{}
+	handle := NewEmailHandler(sender, loadTestTemplates(t))
+
+	err := handle(context.Background(), EmailJobPayload{
+		To:           []string{"user@example.com"},
+		Subject:      "Welcome",
+		TemplateName: "welcome",
+		TemplateData: map[string]any{"name": "<Ann>", "code": 42},
+	})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(sender.sent))
+	}
+	msg := sender.sent[0]
+	if want := "<p>Hello &lt;Ann&gt;, your code is 42</p>"; msg.Body != want || !msg.HTML {
+		t.Errorf("message = %+v, want HTML body %q", msg, want)
+	}
+}
+
+func TestEmailHandler_PlainBodyAndLegacyTemplateID(t *testing.T) {
+	sender := &mockEmailSender{}
+	handle := NewEmailHandler(sender, loadTestTemplates(t))
+
+	if err := handle(context.Background(), EmailJobPayload{To: []string{"a@example.com"}, Body: "hi"}); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if err := handle(context.Background(), EmailJobPayload{To: []string{"a@example.com"}, TemplateID: "welcome"}); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	if sender.sent[0].Body != "hi" || sender.sent[0].HTML {
+		t.Errorf("plain message = %+v, want text body", sender.sent[0])
+	}
+	if !sender.sent[1].HTML {
+		t.Error("template_id should still select a template")
+	}
+}
+
+func TestEmailHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       EmailJobPayload
+		sendErr       error
+		wantPermanent bool
+	}{
+		{"transient send failure", EmailJobPayload{To: []string{"a@example.com"}}, errors.New("connection refused"), false},
+		{"rejected recipient", EmailJobPayload{To: []string{"a@example.com"}}, ErrInvalidRecipient, true},
+		{"unknown template", EmailJobPayload{To: []string{"a@example.com"}, TemplateName: "missing"}, nil, true},
+		{"no recipients", EmailJobPayload{}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handle := NewEmailHandler(&mockEmailSender{err: tt.sendErr}, loadTestTemplates(t))
+
+			err := handle(context.Background(), tt.payload)
+			if err == nil {
+				t.Fatal("handler error = nil, want an error")
+			}
+			if got := jobs.IsPermanent(err); got != tt.wantPermanent {
+				t.Errorf("IsPermanent(%v) = %v, want %v", err, got, tt.wantPermanent)
+			}
+		})
+	}
+}
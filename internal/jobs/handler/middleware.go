@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// JobHandlerFunc is the untyped form of a handler as seen by middleware
+type JobHandlerFunc func(ctx context.Context, jobType string, payload []byte) error
+
+// HandlerMiddleware wraps a JobHandlerFunc with cross-cutting behavior
+type HandlerMiddleware func(next JobHandlerFunc) JobHandlerFunc
+
+// Use appends middleware to the chain applied to every dispatched job.
+// Middleware runs in registration order (the first one is outermost) and
+// applies to handlers registered both before and after the call.
+func (r *Registry) Use(mw ...HandlerMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, mw...)
+}
+
+// chain wraps the final handler with the currently registered middleware
+func (r *Registry) chain(final JobHandlerFunc) JobHandlerFunc {
+	r.mu.RLock()
+	middleware := make([]HandlerMiddleware, len(r.middleware))
+	copy(middleware, r.middleware)
+	r.mu.RUnlock()
+
+	h := final
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// RecoverMiddleware converts a handler panic into an error so the job goes
+// through the normal retry/DLQ path instead of crashing the worker. The error
+// wraps a jobs.PanicError, so the stack is kept on the job.
+func RecoverMiddleware(next JobHandlerFunc) JobHandlerFunc {
+	return func(ctx context.Context, jobType string, payload []byte) (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("job handler %s panicked: %w", jobType, &jobs.PanicError{Value: rec, Stack: string(debug.Stack())})
+			}
+		}()
+		return next(ctx, jobType, payload)
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+func dispatch(t *testing.T, r *Registry, jobType string, payload []byte) error {
+	t.Helper()
+	h, ok := r.pool.GetHandler(jobType)
+	if !ok {
+		t.Fatalf("no handler registered for %s", jobType)
+	}
+	return h(context.Background(), payload)
+}
+
+func TestRegistry_Use_AppliesInOrder(t *testing.T) {
+	r := newTestRegistry(t)
+
+	var calls []string
+	record := func(name string) HandlerMiddleware {
+		return func(next JobHandlerFunc) JobHandlerFunc {
+			return func(ctx context.Context, jobType string, payload []byte) error {
+				calls = append(calls, name+":"+jobType+":"+string(payload))
+				return next(ctx, jobType, payload)
+			}
+		}
+	}
+
+	type P struct{ N int }
+	r.Use(record("first"))
+	Register(r, "mw-job", func(ctx context.Context, p P) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+	// Middleware added after registration still applies
+	r.Use(record("second"))
+
+	if err := dispatch(t, r, "mw-job", []byte(`{"N":1}`)); err != nil {
+		t.Fatalf("dispatch error = %v", err)
+	}
+
+	want := []string{`first:mw-job:{"N":1}`, `second:mw-job:{"N":1}`, "handler"}
+	if strings.Join(calls, "|") != strings.Join(want, "|") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRecoverMiddleware_ConvertsPanic(t *testing.T) {
+	r := newTestRegistry(t)
+	r.Use(RecoverMiddleware)
+
+	type P struct{}
+	Register(r, "panicky", func(ctx context.Context, p P) error {
+		panic("boom")
+	})
+
+	err := dispatch(t, r, "panicky", []byte(`{}`))
+	if err == nil {
+		t.Fatal("expected error from panicking handler")
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("error = %v, want panic value included", err)
+	}
+
+	var panicErr *jobs.PanicError
+	if !errors.As(err, &panicErr) || !strings.Contains(panicErr.Stack, "goroutine") {
+		t.Errorf("error = %v, want a PanicError with the stack", err)
+	}
+}
+
+func TestRecoverMiddleware_PassesThrough(t *testing.T) {
+	h := RecoverMiddleware(func(ctx context.Context, jobType string, payload []byte) error {
+		return nil
+	})
+	if err := h(context.Background(), "ok", nil); err != nil {
+		t.Errorf("error = %v, want nil", err)
+	}
+}
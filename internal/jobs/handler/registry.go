@@ -5,32 +5,54 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/worker"
 )
 
 // HandlerFunc is a typed handler function
 type HandlerFunc[T any] func(ctx context.Context, payload T) error
 
+// ResultHandlerFunc is a typed handler function that produces a result
+type ResultHandlerFunc[P, R any] func(ctx context.Context, payload P) (R, error)
+
+// RetryPolicy configures retries for a job type; see worker.RetryPolicy
+type RetryPolicy = worker.RetryPolicy
+
 // Registry manages job handler registration
 type Registry struct {
-	pool   *worker.WorkerPool
-	logger *zap.Logger
-	mu     sync.RWMutex
-	types  map[string]string // jobType -> Go type name for documentation
+	pool       *worker.WorkerPool
+	logger     *zap.Logger
+	mu         sync.RWMutex
+	types      map[string]string // jobType -> Go type name for documentation
+	middleware []HandlerMiddleware
+
+	// Result storage
+	results   jobs.ResultStore
+	resultTTL time.Duration
 }
 
 // NewRegistry creates a new handler registry
 func NewRegistry(pool *worker.WorkerPool, logger *zap.Logger) *Registry {
 	return &Registry{
-		pool:   pool,
-		logger: logger,
-		types:  make(map[string]string),
+		pool:      pool,
+		logger:    logger,
+		types:     make(map[string]string),
+		results:   pool.Queue(),
+		resultTTL: jobs.DefaultResultTTL,
 	}
 }
 
+// SetResultTTL sets how long results of RegisterWithResult handlers are kept
+func (r *Registry) SetResultTTL(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resultTTL = ttl
+}
+
 // Register registers a typed handler for a job type
 func Register[T any](r *Registry, jobType string, handler HandlerFunc[T]) {
 	r.mu.Lock()
@@ -41,7 +63,7 @@ func Register[T any](r *Registry, jobType string, handler HandlerFunc[T]) {
 	r.types[jobType] = fmt.Sprintf("%T", zero)
 
 	// Create wrapper that deserializes payload
-	wrappedHandler := func(ctx context.Context, data []byte) error {
+	typedHandler := func(ctx context.Context, _ string, data []byte) error {
 		var payload T
 		if err := json.Unmarshal(data, &payload); err != nil {
 			return fmt.Errorf("failed to unmarshal payload: %w", err)
@@ -49,6 +71,11 @@ func Register[T any](r *Registry, jobType string, handler HandlerFunc[T]) {
 		return handler(ctx, payload)
 	}
 
+	// Middleware chain is resolved per dispatch so later Use calls apply too
+	wrappedHandler := func(ctx context.Context, data []byte) error {
+		return r.chain(typedHandler)(ctx, jobType, data)
+	}
+
 	r.pool.RegisterHandler(jobType, wrappedHandler)
 	r.logger.Info("Registered typed job handler",
 		zap.String("job_type", jobType),
@@ -56,6 +83,50 @@ func Register[T any](r *Registry, jobType string, handler HandlerFunc[T]) {
 	)
 }
 
+// RegisterWithResult registers a typed handler whose result is JSON-encoded and
+// stored by job ID, retrievable until the registry's result TTL expires
+func RegisterWithResult[P, R any](r *Registry, jobType string, handler ResultHandlerFunc[P, R]) {
+	Register(r, jobType, func(ctx context.Context, payload P) error {
+		result, err := handler(ctx, payload)
+		if err != nil {
+			return err
+		}
+		return r.saveResult(ctx, jobType, result)
+	})
+}
+
+// RegisterRetryPolicy sets the retry policy used when handlers of jobType fail.
+// Job types without a policy keep the retry settings carried on the job itself.
+func RegisterRetryPolicy(r *Registry, jobType string, policy RetryPolicy) {
+	r.pool.SetRetryPolicy(jobType, policy)
+}
+
+// RegisterTimeout sets how long handlers of jobType may run before their
+// context is cancelled and the job fails as timed out
+func RegisterTimeout(r *Registry, jobType string, timeout time.Duration) {
+	r.pool.SetTimeout(jobType, timeout)
+}
+
+// saveResult stores a handler result for the job executing in ctx
+func (r *Registry) saveResult(ctx context.Context, jobType string, result any) error {
+	jobID, ok := jobs.JobIDFromContext(ctx)
+	if !ok {
+		r.logger.Warn("Discarding job result: no job ID in context", zap.String("job_type", jobType))
+		return nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	r.mu.RLock()
+	store, ttl := r.results, r.resultTTL
+	r.mu.RUnlock()
+
+	return store.SaveResult(ctx, jobID, data, ttl)
+}
+
 // ListHandlers returns all registered handler types
 func (r *Registry) ListHandlers() map[string]string {
 	r.mu.RLock()
@@ -70,13 +141,16 @@ func (r *Registry) ListHandlers() map[string]string {
 
 // Example job payload types
 
-// EmailJobPayload is the payload for email jobs
+// EmailJobPayload is the payload for email jobs. With a TemplateName the
+// body is rendered from that template and TemplateData; otherwise Body is
+// sent as plain text.
 type EmailJobPayload struct {
-	To          []string          `json:"to"`
-	Subject     string            `json:"subject"`
-	Body        string            `json:"body"`
-	TemplateID  string            `json:"template_id,omitempty"`
-	TemplateData map[string]any   `json:"template_data,omitempty"`
+	To           []string       `json:"to"`
+	Subject      string         `json:"subject"`
+	Body         string         `json:"body"`
+	TemplateName string         `json:"template_name,omitempty"`
+	TemplateID   string         `json:"template_id,omitempty"` // Deprecated: use TemplateName
+	TemplateData map[string]any `json:"template_data,omitempty"`
 }
 
 // WebhookJobPayload is the payload for webhook jobs
@@ -90,7 +164,7 @@ type WebhookJobPayload struct {
 
 // CleanupJobPayload is the payload for cleanup jobs
 type CleanupJobPayload struct {
-	Type       string `json:"type"` // "expired_tokens", "old_logs", etc.
+	Type       string `json:"type"` // "expired_tokens", "expired_password_resets", "orphaned_plugins", "old_audit_logs"
 	OlderThan  int    `json:"older_than_days"`
 	DryRun     bool   `json:"dry_run"`
 }
@@ -107,7 +181,7 @@ type NotificationJobPayload struct {
 // ReportJobPayload is the payload for report generation jobs
 type ReportJobPayload struct {
 	ReportType string            `json:"report_type"`
-	Format     string            `json:"format"` // "pdf", "csv", "xlsx"
+	Format     string            `json:"format"` // "csv", "json", "pdf"
 	Parameters map[string]any    `json:"parameters,omitempty"`
 	Recipients []string          `json:"recipients,omitempty"`
 }
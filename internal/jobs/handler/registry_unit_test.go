@@ -2,7 +2,9 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -11,18 +13,30 @@ import (
 )
 
 // mockQueue is a no-op Queue for unit testing without Redis
-type mockQueue struct{}
+type mockQueue struct {
+	results map[string]json.RawMessage
+}
 
 func (m *mockQueue) Enqueue(ctx context.Context, job *jobs.JobPayload) error { return nil }
+func (m *mockQueue) EnqueueBatch(ctx context.Context, batch []*jobs.JobPayload) ([]error, error) {
+	return make([]error, len(batch)), nil
+}
 func (m *mockQueue) Dequeue(ctx context.Context, priorities ...jobs.Priority) (*jobs.JobPayload, error) {
 	return nil, nil
 }
+func (m *mockQueue) DequeueFrom(ctx context.Context, queues []string) (*jobs.JobPayload, error) {
+	return nil, nil
+}
 func (m *mockQueue) GetJob(ctx context.Context, jobID string) (*jobs.JobPayload, error) {
 	return nil, nil
 }
 func (m *mockQueue) UpdateJob(ctx context.Context, job *jobs.JobPayload) error  { return nil }
 func (m *mockQueue) Complete(ctx context.Context, jobID string) error            { return nil }
 func (m *mockQueue) Fail(ctx context.Context, jobID string, jobErr error) error  { return nil }
+func (m *mockQueue) ScheduleRetry(ctx context.Context, jobID string, jobErr error, delay time.Duration) error {
+	return nil
+}
+func (m *mockQueue) MoveToDLQ(ctx context.Context, jobID string, jobErr error) error { return nil }
 func (m *mockQueue) ProcessScheduled(ctx context.Context) (int, error)           { return 0, nil }
 func (m *mockQueue) GetDLQJobs(ctx context.Context, limit int64) ([]*jobs.JobPayload, error) {
 	return nil, nil
@@ -33,6 +47,34 @@ func (m *mockQueue) RequeueJob(ctx context.Context, jobID string, queueKey strin
 func (m *mockQueue) GetStats(ctx context.Context) (map[string]int64, error) {
 	return map[string]int64{}, nil
 }
+func (m *mockQueue) DepthByPriority(ctx context.Context, queue string) (map[jobs.Priority]int64, error) {
+	return map[jobs.Priority]int64{}, nil
+}
+func (m *mockQueue) SaveResult(ctx context.Context, jobID string, result json.RawMessage, ttl time.Duration) error {
+	if m.results == nil {
+		m.results = make(map[string]json.RawMessage)
+	}
+	m.results[jobID] = result
+	return nil
+}
+func (m *mockQueue) GetResult(ctx context.Context, jobID string) (json.RawMessage, error) {
+	if r, ok := m.results[jobID]; ok {
+		return r, nil
+	}
+	return nil, jobs.ErrResultNotFound
+}
+func (m *mockQueue) SetProgress(ctx context.Context, jobID string, progress jobs.JobProgress) error {
+	return nil
+}
+func (m *mockQueue) GetProgress(ctx context.Context, jobID string) (*jobs.JobProgress, error) {
+	return nil, jobs.ErrProgressNotFound
+}
+func (m *mockQueue) ExpireProgress(ctx context.Context, jobID string, ttl time.Duration) error {
+	return nil
+}
+func (m *mockQueue) PauseType(ctx context.Context, jobType string) error  { return nil }
+func (m *mockQueue) ResumeType(ctx context.Context, jobType string) error { return nil }
+func (m *mockQueue) PausedTypes(ctx context.Context) ([]string, error)    { return nil, nil }
 
 func newTestRegistry(t *testing.T) *Registry {
 	t.Helper()
@@ -129,3 +171,79 @@ func TestRegister_Unit_OverwriteHandler(t *testing.T) {
 		t.Errorf("len(handlers) = %d, want 1 (overwrite)", len(handlers))
 	}
 }
+
+func TestRegisterWithResult_Unit_StoresResult(t *testing.T) {
+	r := newTestRegistry(t)
+
+	type In struct{ A, B int }
+	type Out struct{ Sum int }
+	RegisterWithResult(r, "sum", func(ctx context.Context, p In) (Out, error) {
+		return Out{Sum: p.A + p.B}, nil
+	})
+
+	h, ok := r.pool.GetHandler("sum")
+	if !ok {
+		t.Fatal("sum handler not registered")
+	}
+	ctx := jobs.ContextWithJobID(context.Background(), "job-42")
+	if err := h(ctx, []byte(`{"A":2,"B":3}`)); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	result, err := r.pool.Queue().GetResult(ctx, "job-42")
+	if err != nil {
+		t.Fatalf("GetResult() error = %v", err)
+	}
+	if string(result) != `{"Sum":5}` {
+		t.Errorf("result = %s, want {\"Sum\":5}", result)
+	}
+}
+
+func TestRegisterWithResult_Unit_ErrorSkipsResult(t *testing.T) {
+	r := newTestRegistry(t)
+
+	type P struct{}
+	RegisterWithResult(r, "fails", func(ctx context.Context, p P) (string, error) {
+		return "", context.DeadlineExceeded
+	})
+
+	h, _ := r.pool.GetHandler("fails")
+	ctx := jobs.ContextWithJobID(context.Background(), "job-err")
+	if err := h(ctx, []byte(`{}`)); err == nil {
+		t.Fatal("expected handler error")
+	}
+	if _, err := r.pool.Queue().GetResult(ctx, "job-err"); err != jobs.ErrResultNotFound {
+		t.Errorf("GetResult() error = %v, want jobs.ErrResultNotFound", err)
+	}
+}
+
+func TestRegisterRetryPolicy_Unit(t *testing.T) {
+	r := newTestRegistry(t)
+
+	policy := RetryPolicy{MaxAttempts: 7, InitialInterval: time.Second, Multiplier: 1.5, MaxInterval: time.Minute}
+	RegisterRetryPolicy(r, "webhook", policy)
+
+	got, ok := r.pool.GetRetryPolicy("webhook")
+	if !ok {
+		t.Fatal("retry policy not registered")
+	}
+	if got != policy {
+		t.Errorf("GetRetryPolicy() = %+v, want %+v", got, policy)
+	}
+	if _, ok := r.pool.GetRetryPolicy("cleanup"); ok {
+		t.Error("unregistered type should have no retry policy")
+	}
+}
+
+func TestRegisterTimeout_Unit(t *testing.T) {
+	r := newTestRegistry(t)
+
+	RegisterTimeout(r, "webhook", 30*time.Second)
+
+	if got, ok := r.pool.GetTimeout("webhook"); !ok || got != 30*time.Second {
+		t.Errorf("GetTimeout() = %v, %v, want 30s, true", got, ok)
+	}
+	if _, ok := r.pool.GetTimeout("cleanup"); ok {
+		t.Error("unregistered type should have no timeout")
+	}
+}
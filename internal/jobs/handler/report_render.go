@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// reportWriter renders rows as they are produced
+type reportWriter interface {
+	WriteRow(row []any) error
+	// Close completes the document
+	Close() error
+}
+
+// reportFormat describes an output format of reports
+type reportFormat struct {
+	extension   string
+	contentType string
+	newWriter   func(w io.Writer, title string, columns []ReportColumn) (reportWriter, error)
+}
+
+var reportFormats = map[string]reportFormat{
+	"csv":  {".csv", "text/csv; charset=utf-8", newCSVReportWriter},
+	"json": {".json", "application/json", newJSONReportWriter},
+	"pdf":  {".pdf", "application/pdf", newPDFReportWriter},
+}
+
+// formatReportCell renders a value as text for CSV and PDF
+func formatReportCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// csvReportWriter writes a header line, then a line per row
+type csvReportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVReportWriter(w io.Writer, _ string, columns []ReportColumn) (reportWriter, error) {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Title
+	}
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvReportWriter{w: cw}, nil
+}
+
+func (c *csvReportWriter) WriteRow(row []any) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = csvSafe(formatReportCell(value))
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvReportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvSafe keeps spreadsheets from evaluating user-supplied text, such as a
+// username, as a formula
+func csvSafe(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// jsonReportWriter writes an array of objects keyed by column, one per line
+type jsonReportWriter struct {
+	w       *bufio.Writer
+	columns []ReportColumn
+	rows    int
+}
+
+func newJSONReportWriter(w io.Writer, _ string, columns []ReportColumn) (reportWriter, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString("["); err != nil {
+		return nil, err
+	}
+	return &jsonReportWriter{w: bw, columns: columns}, nil
+}
+
+func (j *jsonReportWriter) WriteRow(row []any) error {
+	separator := "\n"
+	if j.rows > 0 {
+		separator = ",\n"
+	}
+	j.rows++
+
+	var object bytes.Buffer
+	object.WriteString(separator + "{")
+	for i, column := range j.columns {
+		if i > 0 {
+			object.WriteByte(',')
+		}
+		key, _ := json.Marshal(column.Key)
+		value, err := json.Marshal(row[i])
+		if err != nil {
+			return err
+		}
+		object.Write(key)
+		object.WriteByte(':')
+		object.Write(value)
+	}
+	object.WriteByte('}')
+	_, err := j.w.Write(object.Bytes())
+	return err
+}
+
+func (j *jsonReportWriter) Close() error {
+	if _, err := j.w.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
+
+// PDF page layout, in points: A4 landscape
+const (
+	pdfPageWidth   = 842
+	pdfPageHeight  = 595
+	pdfMargin      = 36
+	pdfFontSize    = 8
+	pdfLineHeight  = 11
+	pdfTitleSize   = 14
+	pdfTitleHeight = 24
+)
+
+// The objects written before the pages; the page tree is written last, once
+// its pages are known
+const (
+	pdfCatalogObject = 1
+	pdfPagesObject   = 2
+	pdfFontObject    = 3
+	pdfBoldObject    = 4
+)
+
+// pdfReportWriter writes a table as a PDF document using the standard
+// Helvetica font, so nothing is embedded. Only the page being filled is kept
+// in memory: each page is written once full. Columns share the page width
+// equally and long cells are cut short.
+type pdfReportWriter struct {
+	out      *bufio.Writer
+	w        *countingWriter
+	title    string
+	columns  []ReportColumn
+	offsets  []int64 // by object number
+	pages    []int   // page object numbers
+	page     bytes.Buffer
+	y        int // baseline of the next line on the page
+	colWidth float64
+	maxChars int
+	err      error
+}
+
+func newPDFReportWriter(w io.Writer, title string, columns []ReportColumn) (reportWriter, error) {
+	colWidth := float64(pdfPageWidth-2*pdfMargin) / float64(max(len(columns), 1))
+	out := bufio.NewWriter(w)
+	p := &pdfReportWriter{
+		out:      out,
+		w:        &countingWriter{w: out},
+		title:    title,
+		columns:  columns,
+		offsets:  make([]int64, pdfBoldObject+1),
+		colWidth: colWidth,
+		// Helvetica averages about half an em per character
+		maxChars: max(int(colWidth/(pdfFontSize*0.55)), 2),
+	}
+
+	p.writeString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	p.writeObject(pdfCatalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObject))
+	p.writeObject(pdfFontObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	p.writeObject(pdfBoldObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	p.startPage()
+	return p, p.err
+}
+
+func (p *pdfReportWriter) WriteRow(row []any) error {
+	if p.y < pdfMargin {
+		p.finishPage()
+		p.startPage()
+	}
+	p.writeLine("F1", row)
+	return p.err
+}
+
+func (p *pdfReportWriter) Close() error {
+	p.finishPage()
+
+	kids := make([]string, len(p.pages))
+	for i, page := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	p.writeObject(pdfPagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>",
+		strings.Join(kids, " "), len(p.pages)))
+
+	xref := p.w.n
+	p.writeString(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(p.offsets)))
+	for _, offset := range p.offsets[1:] {
+		p.writeString(fmt.Sprintf("%010d 00000 n \n", offset))
+	}
+	p.writeString(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(p.offsets), pdfCatalogObject, xref))
+
+	if p.err != nil {
+		return p.err
+	}
+	return p.out.Flush()
+}
+
+// startPage begins a page with the title, on the first page, and the
+// column headings
+func (p *pdfReportWriter) startPage() {
+	p.page.Reset()
+	p.y = pdfPageHeight - pdfMargin - pdfFontSize
+	if len(p.pages) == 0 {
+		fmt.Fprintf(&p.page, "BT /F2 %d Tf 1 0 0 1 %d %d Tm (%s) Tj ET\n",
+			pdfTitleSize, pdfMargin, p.y-pdfTitleSize+pdfFontSize, pdfEscape(p.title))
+		p.y -= pdfTitleHeight
+	}
+
+	headings := make([]any, len(p.columns))
+	for i, column := range p.columns {
+		headings[i] = column.Title
+	}
+	p.writeLine("F2", headings)
+	p.y -= pdfLineHeight / 2
+}
+
+// writeLine adds a line of cells to the page
+func (p *pdfReportWriter) writeLine(font string, cells []any) {
+	fmt.Fprintf(&p.page, "BT /%s %d Tf\n", font, pdfFontSize)
+	for i, cell := range cells {
+		text := formatReportCell(cell)
+		if utf8.RuneCountInString(text) > p.maxChars {
+			text = string([]rune(text)[:p.maxChars-2]) + ".."
+		}
+		x := float64(pdfMargin) + float64(i)*p.colWidth
+		fmt.Fprintf(&p.page, "1 0 0 1 %.1f %d Tm (%s) Tj\n", x, p.y, pdfEscape(text))
+	}
+	p.page.WriteString("ET\n")
+	p.y -= pdfLineHeight
+}
+
+// finishPage writes the page being filled, numbering it in the footer
+func (p *pdfReportWriter) finishPage() {
+	fmt.Fprintf(&p.page, "BT /F1 %d Tf 1 0 0 1 %d %d Tm (Page %d) Tj ET\n",
+		pdfFontSize, pdfPageWidth-pdfMargin-40, pdfMargin/2, len(p.pages)+1)
+
+	content := len(p.offsets)
+	page := content + 1
+	p.offsets = append(p.offsets, 0, 0)
+	p.writeObject(content, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.page.Len(), p.page.String()))
+	p.writeObject(page, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] "+
+		"/Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObject, pdfPageWidth, pdfPageHeight, pdfFontObject, pdfBoldObject, content))
+	p.pages = append(p.pages, page)
+}
+
+// writeObject writes an indirect object, recording its offset for the
+// cross-reference table
+func (p *pdfReportWriter) writeObject(number int, body string) {
+	p.offsets[number] = p.w.n
+	p.writeString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", number, body))
+}
+
+func (p *pdfReportWriter) writeString(s string) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = io.WriteString(p.w, s)
+}
+
+// pdfEscape encodes text as the body of a PDF string in WinAnsiEncoding.
+// Characters outside Latin-1 become '?'.
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// countingWriter counts the bytes written, for PDF object offsets
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
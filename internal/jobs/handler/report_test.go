@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/storage"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
+)
+
+// staticReportSource emits fixed rows
+type staticReportSource struct {
+	rows [][]any
+	err  error
+}
+
+func (s *staticReportSource) Title() string { return "Test (Report)" }
+
+func (s *staticReportSource) Columns() []ReportColumn {
+	return []ReportColumn{{"id", "ID"}, {"name", "Name"}, {"at", "At"}}
+}
+
+func (s *staticReportSource) Rows(_ context.Context, _ map[string]any, emit func(row []any) error) error {
+	for _, row := range s.rows {
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+func newReportStore(t *testing.T) *storage.LocalStore {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	return store
+}
+
+func readReport(t *testing.T, store storage.Store, key string) []byte {
+	t.Helper()
+	object, err := store.Open(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Open(%q) error = %v", key, err)
+	}
+	defer object.Body.Close()
+	body, _ := io.ReadAll(object.Body)
+	return body
+}
+
+var reportTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestReportHandler_CSV(t *testing.T) {
+	store := newReportStore(t)
+	source := &staticReportSource{rows: [][]any{
+		{1, "alice", reportTime},
+		{2, "=cmd()", time.Time{}},
+	}}
+	handle := NewReportHandler(map[string]ReportSource{"test": source}, store)
+
+	ctx := jobs.ContextWithJobID(context.Background(), "job-1")
+	result, err := handle(ctx, ReportJobPayload{ReportType: "test", Format: "csv"})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	if result.Key != "reports/job-1.csv" || result.ReportID != "job-1" || result.Rows != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.DownloadURL != "/api/v1/reports/job-1/download" {
+		t.Errorf("DownloadURL = %q", result.DownloadURL)
+	}
+	if !strings.HasPrefix(result.FileName, "test-") || !strings.HasSuffix(result.FileName, ".csv") {
+		t.Errorf("FileName = %q", result.FileName)
+	}
+
+	body := readReport(t, store, result.Key)
+	if int64(len(body)) != result.Size {
+		t.Errorf("Size = %d, stored %d bytes", result.Size, len(body))
+	}
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	want := [][]string{
+		{"ID", "Name", "At"},
+		{"1", "alice", "2026-01-02T03:04:05Z"},
+		{"2", "'=cmd()", ""},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Errorf("records = %v, want %v", records, want)
+	}
+}
+
+func TestReportHandler_JSON(t *testing.T) {
+	store := newReportStore(t)
+	source := &staticReportSource{rows: [][]any{
+		{1, "alice", reportTime},
+		{2, `bob "b"`, reportTime},
+	}}
+	handle := NewReportHandler(map[string]ReportSource{"test": source}, store)
+
+	result, err := handle(jobs.ContextWithJobID(context.Background(), "job-2"), ReportJobPayload{ReportType: "test", Format: "json"})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	var rows []map[string]any
+	if err := json.Unmarshal(readReport(t, store, result.Key), &rows); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(rows) != 2 || rows[1]["name"] != `bob "b"` || rows[0]["id"] != float64(1) || rows[0]["at"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func TestReportHandler_JSONEmpty(t *testing.T) {
+	store := newReportStore(t)
+	handle := NewReportHandler(map[string]ReportSource{"test": &staticReportSource{}}, store)
+
+	result, err := handle(jobs.ContextWithJobID(context.Background(), "job-3"), ReportJobPayload{ReportType: "test", Format: "json"})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(readReport(t, store, result.Key), &rows); err != nil || len(rows) != 0 {
+		t.Errorf("rows = %v, err = %v", rows, err)
+	}
+}
+
+func TestReportHandler_PDF(t *testing.T) {
+	store := newReportStore(t)
+	var rows [][]any
+	for i := range 120 {
+		rows = append(rows, []any{i, fmt.Sprintf("user-%d (café)", i), reportTime})
+	}
+	handle := NewReportHandler(map[string]ReportSource{"test": &staticReportSource{rows: rows}}, store)
+
+	result, err := handle(jobs.ContextWithJobID(context.Background(), "job-4"), ReportJobPayload{ReportType: "test", Format: "pdf"})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if result.ContentType != "application/pdf" || result.Rows != 120 {
+		t.Errorf("result = %+v", result)
+	}
+
+	body := readReport(t, store, result.Key)
+	if !bytes.HasPrefix(body, []byte("%PDF-1.4")) || !bytes.HasSuffix(body, []byte("%%EOF\n")) {
+		t.Fatal("not a complete PDF document")
+	}
+	if !bytes.Contains(body, []byte(`(Test \(Report\))`)) {
+		t.Error("title missing or unescaped")
+	}
+	if !bytes.Contains(body, []byte(`(user-119 \(caf\351\))`)) {
+		t.Error("last row missing or not Latin-1 encoded")
+	}
+
+	// 120 rows do not fit on one page
+	pages := bytes.Count(body, []byte("/Type /Page /Parent"))
+	if pages < 2 || !bytes.Contains(body, []byte(fmt.Sprintf("/Count %d", pages))) {
+		t.Errorf("pages = %d, page tree does not match", pages)
+	}
+
+	// Every cross-reference entry points at its object
+	startxref := bytes.LastIndex(body, []byte("startxref\n"))
+	var xref int
+	fmt.Sscanf(string(body[startxref+len("startxref\n"):]), "%d", &xref)
+	if !bytes.HasPrefix(body[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	lines := strings.Split(string(body[xref:]), "\n")
+	var count int
+	fmt.Sscanf(lines[1], "0 %d", &count)
+	for number := 1; number < count; number++ {
+		var offset int
+		fmt.Sscanf(lines[2+number], "%d", &offset)
+		if !bytes.HasPrefix(body[offset:], []byte(fmt.Sprintf("%d 0 obj\n", number))) {
+			t.Errorf("xref entry %d points at offset %d, not the object", number, offset)
+		}
+	}
+}
+
+func TestReportHandler_PermanentFailures(t *testing.T) {
+	store := newReportStore(t)
+	handle := NewReportHandler(map[string]ReportSource{"test": &staticReportSource{}}, store)
+	ctx := jobs.ContextWithJobID(context.Background(), "job-5")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		payload ReportJobPayload
+	}{
+		{"unknown type", ctx, ReportJobPayload{ReportType: "missing", Format: "csv"}},
+		{"unknown format", ctx, ReportJobPayload{ReportType: "test", Format: "xlsx"}},
+		{"no job ID", context.Background(), ReportJobPayload{ReportType: "test", Format: "csv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handle(tt.ctx, tt.payload)
+			if !jobs.IsPermanent(err) {
+				t.Errorf("error = %v, want permanent", err)
+			}
+		})
+	}
+}
+
+func TestReportHandler_SourceFailureKeepsNoArtifact(t *testing.T) {
+	store := newReportStore(t)
+	failing := &staticReportSource{rows: [][]any{{1, "a", reportTime}}, err: errors.New("database down")}
+	handle := NewReportHandler(map[string]ReportSource{"test": failing}, store)
+
+	_, err := handle(jobs.ContextWithJobID(context.Background(), "job-6"), ReportJobPayload{ReportType: "test", Format: "csv"})
+	if err == nil || jobs.IsPermanent(err) {
+		t.Fatalf("error = %v, want a retryable failure", err)
+	}
+	if _, err := store.Open(context.Background(), "reports/job-6.csv"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Open() error = %v, want no partial report stored", err)
+	}
+}
+
+func TestUserReportSource_PagesThroughUsers(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	ctx := context.Background()
+	for i := range reportBatchSize + 3 {
+		if err := repo.Create(ctx, &entity.User{
+			Username: fmt.Sprintf("user%d", i),
+			Email:    fmt.Sprintf("user%d@example.com", i),
+			Role:     entity.RoleUser,
+		}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	var ids []uint
+	err := UserReportSource(repo).Rows(ctx, nil, func(row []any) error {
+		ids = append(ids, row[0].(uint))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	if len(ids) != reportBatchSize+3 {
+		t.Fatalf("rows = %d, want %d", len(ids), reportBatchSize+3)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("rows not ordered by ID at %d", i)
+		}
+	}
+}
+
+func TestAuditLogReportSource(t *testing.T) {
+	repo := mocks.NewMockAuditLogRepository()
+	ctx := context.Background()
+	for i := range 250 {
+		action := entity.AuditActionLogin
+		if i%2 == 1 {
+			action = entity.AuditActionLogout
+		}
+		if err := repo.Create(ctx, &entity.AuditLog{Actor: "alice", Action: action, Outcome: entity.AuditOutcomeSuccess}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	source := AuditLogReportSource(repo)
+
+	var rows int
+	err := source.Rows(ctx, map[string]any{"action": entity.AuditActionLogin}, func(row []any) error {
+		rows++
+		if row[4] != entity.AuditActionLogin {
+			t.Errorf("row action = %v", row[4])
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	if rows != 125 {
+		t.Errorf("rows = %d, want 125", rows)
+	}
+
+	for _, params := range []map[string]any{{"from": "yesterday"}, {"actor": 42}} {
+		if err := source.Rows(ctx, params, func([]any) error { return nil }); !jobs.IsPermanent(err) {
+			t.Errorf("Rows(%v) error = %v, want permanent", params, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts one session on a local port, rejecting RCPT for
+// addresses containing "unknown" and recording the message data
+func fakeSMTPServer(t *testing.T) (port int, data chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on loopback: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	data = make(chan string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 fake ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case cmd == "EHLO" || cmd == "HELO":
+				tp.PrintfLine("250 fake")
+			case cmd == "RCPT" && strings.Contains(line, "unknown"):
+				tp.PrintfLine("550 5.1.1 no such user")
+			case cmd == "DATA":
+				tp.PrintfLine("354 go ahead")
+				body, _ := tp.ReadDotLines()
+				data <- strings.Join(body, "\n")
+				tp.PrintfLine("250 queued")
+			case cmd == "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("250 ok")
+			}
+		}
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port, data
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	port, data := fakeSMTPServer(t)
+	sender := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: port, From: "Arcana <noreply@example.com>", Timeout: 5 * time.Second})
+
+	err := sender.Send(context.Background(), EmailMessage{
+		To:      []string{"Ann <ann@example.com>"},
+		Subject: "Hello",
+		Body:    "<p>hi</p>",
+		HTML:    true,
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	select {
+	case got := <-data:
+		for _, want := range []string{"To: Ann <ann@example.com>", "Subject: Hello", "Content-Type: text/html; charset=UTF-8", "<p>hi</p>"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("message missing %q:\n%s", want, got)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server received no message")
+	}
+}
+
+func TestSMTPSender_RejectedRecipient(t *testing.T) {
+	port, _ := fakeSMTPServer(t)
+	sender := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@example.com", Timeout: 5 * time.Second})
+
+	err := sender.Send(context.Background(), EmailMessage{To: []string{"unknown@example.com"}, Subject: "Hi"})
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("Send() error = %v, want %v", err, ErrInvalidRecipient)
+	}
+}
+
+func TestSMTPSender_MalformedRecipient(t *testing.T) {
+	sender := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: 1, From: "noreply@example.com"})
+
+	err := sender.Send(context.Background(), EmailMessage{To: []string{"not an address"}})
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("Send() error = %v, want %v", err, ErrInvalidRecipient)
+	}
+}
+
+func TestSMTPSender_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on loopback: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	sender := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@example.com", Timeout: time.Second})
+	err = sender.Send(context.Background(), EmailMessage{To: []string{"ann@example.com"}})
+	if err == nil || errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("Send() error = %v, want a transient connection error", err)
+	}
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// Sync endpoints and entity types of the built-in adapters
+const (
+	SyncSourceDatabase   = "database"
+	SyncDestinationCache = "cache"
+	SyncEntityStats      = "stats"
+)
+
+const (
+	// StatsCacheKey is the Redis hash the stats entity is cached in
+	StatsCacheKey = "arcana:stats"
+
+	// statsCacheTTL outlives two missed hourly syncs, so stale stats expire
+	// rather than linger
+	statsCacheTTL = 3 * time.Hour
+)
+
+// SyncResult reports what a sync job copied
+type SyncResult struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	EntityType  string `json:"entity_type"`
+	FullSync    bool   `json:"full_sync"`
+	// Synced counts the records written to the destination
+	Synced int64 `json:"synced"`
+	// Failed counts the records that could not be written
+	Failed     int64     `json:"failed"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// SyncAdapter copies entities from one source to one destination. An entity
+// type it does not handle is a permanent error.
+type SyncAdapter interface {
+	Sync(ctx context.Context, payload SyncJobPayload) (SyncResult, error)
+}
+
+// SyncAdapterFunc adapts a function to SyncAdapter
+type SyncAdapterFunc func(ctx context.Context, payload SyncJobPayload) (SyncResult, error)
+
+// Sync calls f
+func (f SyncAdapterFunc) Sync(ctx context.Context, payload SyncJobPayload) (SyncResult, error) {
+	return f(ctx, payload)
+}
+
+// SyncAdapterRegistry maps source and destination pairs to the adapters
+// syncing between them
+type SyncAdapterRegistry struct {
+	mu       sync.RWMutex
+	adapters map[[2]string]SyncAdapter
+}
+
+// NewSyncAdapterRegistry creates an empty registry
+func NewSyncAdapterRegistry() *SyncAdapterRegistry {
+	return &SyncAdapterRegistry{adapters: make(map[[2]string]SyncAdapter)}
+}
+
+// Register sets the adapter syncing from source to destination, replacing
+// any registered before
+func (r *SyncAdapterRegistry) Register(source, destination string, adapter SyncAdapter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.adapters[[2]string{source, destination}] = adapter
+}
+
+// Get returns the adapter syncing from source to destination
+func (r *SyncAdapterRegistry) Get(source, destination string) (SyncAdapter, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	adapter, ok := r.adapters[[2]string{source, destination}]
+	return adapter, ok
+}
+
+// Pairs lists the registered pairs as "source->destination", sorted
+func (r *SyncAdapterRegistry) Pairs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pairs := make([]string, 0, len(r.adapters))
+	for pair := range r.adapters {
+		pairs = append(pairs, pair[0]+"->"+pair[1])
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// NewSyncHandler returns the handler for sync jobs. It runs the adapter
+// registered for the payload's source and destination; a pair without one
+// fails permanently, since retrying cannot help.
+func NewSyncHandler(adapters *SyncAdapterRegistry, logger *zap.Logger) ResultHandlerFunc[SyncJobPayload, SyncResult] {
+	return func(ctx context.Context, payload SyncJobPayload) (SyncResult, error) {
+		adapter, ok := adapters.Get(payload.Source, payload.Destination)
+		if !ok {
+			return SyncResult{}, jobs.Permanent(fmt.Errorf("no sync adapter from %q to %q (available: %v)",
+				payload.Source, payload.Destination, adapters.Pairs()))
+		}
+
+		result, err := adapter.Sync(ctx, payload)
+		if err != nil {
+			return SyncResult{}, fmt.Errorf("sync from %s to %s failed: %w", payload.Source, payload.Destination, err)
+		}
+		result.Source = payload.Source
+		result.Destination = payload.Destination
+		result.EntityType = payload.EntityType
+		result.FullSync = payload.FullSync
+		if result.FinishedAt.IsZero() {
+			result.FinishedAt = time.Now().UTC()
+		}
+
+		logger.Info("Sync completed",
+			zap.String("source", payload.Source),
+			zap.String("destination", payload.Destination),
+			zap.String("entity_type", payload.EntityType),
+			zap.Int64("synced", result.Synced),
+			zap.Int64("failed", result.Failed),
+		)
+		return result, nil
+	}
+}
+
+// SyncCache is the cache a database to cache sync writes to
+type SyncCache interface {
+	// ReplaceHash replaces the hash at key with fields, expiring after ttl
+	ReplaceHash(ctx context.Context, key string, fields map[string]any, ttl time.Duration) error
+}
+
+// RedisSyncCache writes synced entities to Redis
+type RedisSyncCache struct {
+	client *redis.Client
+}
+
+// NewRedisSyncCache creates a cache writing to client
+func NewRedisSyncCache(client *redis.Client) *RedisSyncCache {
+	return &RedisSyncCache{client: client}
+}
+
+// ReplaceHash replaces the hash in one transaction, so readers never see it
+// missing or half written
+func (c *RedisSyncCache) ReplaceHash(ctx context.Context, key string, fields map[string]any, ttl time.Duration) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, fields)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+// NewDatabaseCacheSyncAdapter returns the adapter copying entities from the
+// database to the cache. It handles the "stats" entity: counts of users and
+// of recent audit events, cached in the StatsCacheKey hash one field per
+// count, along with "updated_at" in Unix seconds. Stats are always
+// recomputed in full.
+func NewDatabaseCacheSyncAdapter(users repository.UserRepository, auditLogs repository.AuditLogRepository, cache SyncCache) SyncAdapter {
+	return SyncAdapterFunc(func(ctx context.Context, payload SyncJobPayload) (SyncResult, error) {
+		if payload.EntityType != SyncEntityStats {
+			return SyncResult{}, jobs.Permanent(fmt.Errorf("database to cache sync does not support entity type %q", payload.EntityType))
+		}
+
+		stats, err := collectStats(ctx, users, auditLogs, time.Now())
+		if err != nil {
+			return SyncResult{}, err
+		}
+
+		fields := make(map[string]any, len(stats)+1)
+		for name, value := range stats {
+			fields[name] = value
+		}
+		fields["updated_at"] = time.Now().Unix()
+		if err := cache.ReplaceHash(ctx, StatsCacheKey, fields, statsCacheTTL); err != nil {
+			return SyncResult{}, fmt.Errorf("failed to cache stats: %w", err)
+		}
+		return SyncResult{Synced: int64(len(stats))}, nil
+	})
+}
+
+// collectStats counts users and the audit events of the last day
+func collectStats(ctx context.Context, users repository.UserRepository, auditLogs repository.AuditLogRepository, now time.Time) (map[string]int64, error) {
+	active, verified, admin := true, true, entity.RoleAdmin
+	dayAgo := now.Add(-24 * time.Hour)
+
+	userCounts := map[string]*entity.UserQuery{
+		"users_total":    {},
+		"users_active":   {IsActive: &active},
+		"users_verified": {IsVerified: &verified},
+		"users_admin":    {Role: &admin},
+		"users_new_24h":  {CreatedAfter: &dayAgo},
+	}
+	auditCounts := map[string]*entity.AuditQuery{
+		"audit_events_24h": {From: &dayAgo},
+		"logins_24h":       {From: &dayAgo, Action: entity.AuditActionLogin},
+	}
+
+	stats := make(map[string]int64, len(userCounts)+len(auditCounts))
+	for name, query := range userCounts {
+		// Only the total is needed, so fetch the smallest page
+		query.Size = 1
+		query.Normalize()
+		_, total, err := users.Search(ctx, query)
+		if errors.Is(err, repository.ErrUnsupported) {
+			// The database cannot count users by field
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", name, err)
+		}
+		stats[name] = total
+	}
+	for name, query := range auditCounts {
+		query.Size = 1
+		query.Normalize()
+		_, total, err := auditLogs.Query(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", name, err)
+		}
+		stats[name] = total
+	}
+	return stats, nil
+}
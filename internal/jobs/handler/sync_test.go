@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
+)
+
+// memorySyncCache records the hashes written to it
+type memorySyncCache struct {
+	mu     sync.Mutex
+	hashes map[string]map[string]any
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newMemorySyncCache() *memorySyncCache {
+	return &memorySyncCache{hashes: map[string]map[string]any{}, ttls: map[string]time.Duration{}}
+}
+
+func (c *memorySyncCache) ReplaceHash(_ context.Context, key string, fields map[string]any, ttl time.Duration) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hashes[key] = fields
+	c.ttls[key] = ttl
+	return nil
+}
+
+func TestSyncHandler_RunsRegisteredAdapter(t *testing.T) {
+	adapters := NewSyncAdapterRegistry()
+	var got SyncJobPayload
+	adapters.Register("a", "b", SyncAdapterFunc(func(_ context.Context, payload SyncJobPayload) (SyncResult, error) {
+		got = payload
+		return SyncResult{Synced: 7, Failed: 1}, nil
+	}))
+
+	handle := NewSyncHandler(adapters, zap.NewNop())
+	result, err := handle(context.Background(), SyncJobPayload{Source: "a", Destination: "b", EntityType: "things", FullSync: true})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if got.EntityType != "things" {
+		t.Errorf("adapter payload = %+v", got)
+	}
+	if result.Synced != 7 || result.Failed != 1 || result.Source != "a" || result.Destination != "b" ||
+		result.EntityType != "things" || !result.FullSync || result.FinishedAt.IsZero() {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestSyncHandler_UnknownPairIsPermanent(t *testing.T) {
+	adapters := NewSyncAdapterRegistry()
+	adapters.Register(SyncSourceDatabase, SyncDestinationCache, SyncAdapterFunc(func(context.Context, SyncJobPayload) (SyncResult, error) {
+		t.Error("adapter called for another pair")
+		return SyncResult{}, nil
+	}))
+
+	_, err := NewSyncHandler(adapters, zap.NewNop())(context.Background(), SyncJobPayload{Source: "cache", Destination: "database"})
+	if !jobs.IsPermanent(err) {
+		t.Fatalf("error = %v, want permanent", err)
+	}
+	if !strings.Contains(err.Error(), `from "cache" to "database"`) || !strings.Contains(err.Error(), "database->cache") {
+		t.Errorf("error = %q, want the pair and the available pairs", err)
+	}
+}
+
+func TestSyncHandler_AdapterFailureIsRetryable(t *testing.T) {
+	adapters := NewSyncAdapterRegistry()
+	adapters.Register("a", "b", SyncAdapterFunc(func(context.Context, SyncJobPayload) (SyncResult, error) {
+		return SyncResult{}, errors.New("connection refused")
+	}))
+
+	_, err := NewSyncHandler(adapters, zap.NewNop())(context.Background(), SyncJobPayload{Source: "a", Destination: "b"})
+	if err == nil || jobs.IsPermanent(err) {
+		t.Errorf("error = %v, want a retryable failure", err)
+	}
+}
+
+func TestSyncAdapterRegistry_Pairs(t *testing.T) {
+	adapters := NewSyncAdapterRegistry()
+	noop := SyncAdapterFunc(func(context.Context, SyncJobPayload) (SyncResult, error) { return SyncResult{}, nil })
+	adapters.Register("database", "search", noop)
+	adapters.Register("database", "cache", noop)
+	adapters.Register("database", "cache", noop)
+
+	pairs := adapters.Pairs()
+	if len(pairs) != 2 || pairs[0] != "database->cache" || pairs[1] != "database->search" {
+		t.Errorf("Pairs() = %v", pairs)
+	}
+	if _, ok := adapters.Get("search", "database"); ok {
+		t.Error("Get() found an adapter for the reversed pair")
+	}
+}
+
+func TestDatabaseCacheSyncAdapter_Stats(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewMockUserRepository()
+	for _, user := range []*entity.User{
+		{Username: "admin", Role: entity.RoleAdmin, IsActive: true, IsVerified: true, CreatedAt: time.Now().Add(-48 * time.Hour)},
+		{Username: "alice", Role: entity.RoleUser, IsActive: true, CreatedAt: time.Now()},
+		{Username: "bob", Role: entity.RoleUser, CreatedAt: time.Now()},
+	} {
+		if err := users.Create(ctx, user); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	auditLogs := mocks.NewMockAuditLogRepository()
+	for _, action := range []string{entity.AuditActionLogin, entity.AuditActionLogin, entity.AuditActionLogout} {
+		if err := auditLogs.Create(ctx, &entity.AuditLog{Action: action, Outcome: entity.AuditOutcomeSuccess}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	cache := newMemorySyncCache()
+
+	adapter := NewDatabaseCacheSyncAdapter(users, auditLogs, cache)
+	result, err := adapter.Sync(ctx, SyncJobPayload{EntityType: SyncEntityStats})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	stats := cache.hashes[StatsCacheKey]
+	want := map[string]int64{
+		"users_total":      3,
+		"users_active":     2,
+		"users_verified":   1,
+		"users_admin":      1,
+		"users_new_24h":    2,
+		"audit_events_24h": 3,
+		"logins_24h":       2,
+	}
+	for name, value := range want {
+		if stats[name] != value {
+			t.Errorf("%s = %v, want %d", name, stats[name], value)
+		}
+	}
+	if _, ok := stats["updated_at"]; !ok {
+		t.Error("updated_at not cached")
+	}
+	if result.Synced != int64(len(want)) {
+		t.Errorf("Synced = %d, want %d", result.Synced, len(want))
+	}
+	if cache.ttls[StatsCacheKey] != statsCacheTTL {
+		t.Errorf("ttl = %v, want %v", cache.ttls[StatsCacheKey], statsCacheTTL)
+	}
+}
+
+func TestDatabaseCacheSyncAdapter_UserSearchUnsupported(t *testing.T) {
+	ctx := context.Background()
+	users := mocks.NewMockUserRepository()
+	users.SearchErr = repository.ErrUnsupported
+	cache := newMemorySyncCache()
+
+	adapter := NewDatabaseCacheSyncAdapter(users, mocks.NewMockAuditLogRepository(), cache)
+	if _, err := adapter.Sync(ctx, SyncJobPayload{EntityType: SyncEntityStats}); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	stats := cache.hashes[StatsCacheKey]
+	if _, ok := stats["users_total"]; ok {
+		t.Error("users_total cached without user search")
+	}
+	if _, ok := stats["audit_events_24h"]; !ok {
+		t.Error("audit_events_24h not cached")
+	}
+}
+
+func TestDatabaseCacheSyncAdapter_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	adapter := NewDatabaseCacheSyncAdapter(mocks.NewMockUserRepository(), mocks.NewMockAuditLogRepository(), newMemorySyncCache())
+	if _, err := adapter.Sync(ctx, SyncJobPayload{EntityType: "orders"}); !jobs.IsPermanent(err) {
+		t.Errorf("unknown entity error = %v, want permanent", err)
+	}
+
+	users := mocks.NewMockUserRepository()
+	users.SearchErr = errors.New("database down")
+	adapter = NewDatabaseCacheSyncAdapter(users, mocks.NewMockAuditLogRepository(), newMemorySyncCache())
+	if _, err := adapter.Sync(ctx, SyncJobPayload{EntityType: SyncEntityStats}); err == nil || jobs.IsPermanent(err) {
+		t.Errorf("database error = %v, want a retryable failure", err)
+	}
+
+	failing := newMemorySyncCache()
+	failing.err = errors.New("redis down")
+	adapter = NewDatabaseCacheSyncAdapter(mocks.NewMockUserRepository(), mocks.NewMockAuditLogRepository(), failing)
+	if _, err := adapter.Sync(ctx, SyncJobPayload{EntityType: SyncEntityStats}); err == nil || jobs.IsPermanent(err) {
+		t.Errorf("cache error = %v, want a retryable failure", err)
+	}
+}
+
+func TestRedisSyncCache_ReplaceHash(t *testing.T) {
+	testutil.SkipIfNoRedis(t)
+	client := testutil.NewTestRedisClient(t, testutil.DefaultTestConfig())
+	ctx := context.Background()
+	key := "test:sync:" + testutil.GenerateTestID()
+	defer client.Del(ctx, key)
+
+	cache := NewRedisSyncCache(client)
+	if err := cache.ReplaceHash(ctx, key, map[string]any{"old": 1, "kept": 1}, time.Minute); err != nil {
+		t.Fatalf("ReplaceHash() error = %v", err)
+	}
+	if err := cache.ReplaceHash(ctx, key, map[string]any{"kept": 2}, time.Minute); err != nil {
+		t.Fatalf("ReplaceHash() error = %v", err)
+	}
+
+	fields, err := client.HGetAll(ctx, key).Result()
+	if err != nil {
+		t.Fatalf("HGetAll() error = %v", err)
+	}
+	if len(fields) != 1 || fields["kept"] != "2" {
+		t.Errorf("hash = %v, want only the new fields", fields)
+	}
+	if ttl := client.TTL(ctx, key).Val(); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL = %v", ttl)
+	}
+}
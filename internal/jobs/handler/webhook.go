@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// WebhookSignatureHeader carries the HMAC-SHA256 of the request body, as
+// "sha256=<hex>", when a signing secret is configured
+const WebhookSignatureHeader = "X-Signature"
+
+// WebhookConfig configures webhook delivery
+type WebhookConfig struct {
+	Secret  string        // Signs request bodies; requests are unsigned when empty
+	Timeout time.Duration // Per-request timeout unless the payload sets its own
+}
+
+// NewWebhookHandler returns the handler for webhook jobs. Each destination
+// host gets its own circuit breaker from breakers, so a failing endpoint is
+// skipped until it recovers instead of being called on every retry.
+// Non-2xx responses are retried, except 4xx other than 429 which fail
+// permanently.
+func NewWebhookHandler(config WebhookConfig, breakers *resilience.CircuitBreakerRegistry) HandlerFunc[WebhookJobPayload] {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	client := &http.Client{}
+
+	return func(ctx context.Context, payload WebhookJobPayload) error {
+		target, err := url.Parse(payload.URL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return jobs.Permanent(fmt.Errorf("invalid webhook URL %q", payload.URL))
+		}
+
+		timeout := config.Timeout
+		if payload.Timeout > 0 {
+			timeout = time.Duration(payload.Timeout) * time.Second
+		}
+
+		// A rejected request means the endpoint is up, so it is kept out of the
+		// breaker's failure count and reported once the call returns
+		var rejected error
+		err = breakers.Get("webhook:"+target.Host).Execute(ctx, func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			status, err := deliverWebhook(ctx, client, config.Secret, payload)
+			if err != nil {
+				return err
+			}
+			switch {
+			case status >= 200 && status < 300:
+				return nil
+			case status >= 400 && status < 500 && status != http.StatusTooManyRequests:
+				rejected = fmt.Errorf("webhook %s returned status %d", payload.URL, status)
+				return nil
+			default:
+				return fmt.Errorf("webhook %s returned status %d", payload.URL, status)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("webhook delivery failed: %w", err)
+		}
+		if rejected != nil {
+			return jobs.Permanent(rejected)
+		}
+		return nil
+	}
+}
+
+// deliverWebhook sends the request and returns the response status
+func deliverWebhook(ctx context.Context, client *http.Client, secret string, payload WebhookJobPayload) (int, error) {
+	method := payload.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, payload.URL, bytes.NewReader(payload.Body))
+	if err != nil {
+		return 0, jobs.Permanent(fmt.Errorf("invalid webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range payload.Headers {
+		req.Header.Set(key, value)
+	}
+	if secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(secret, payload.Body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the X-Signature value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+func TestWebhookHandler_SignsBody(t *testing.T) {
+	const secret = "s3cret"
+	body := json.RawMessage(`{"event":"user.created"}`)
+
+	var gotSignature, gotHeader, gotMethod string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotHeader = r.Header.Get("X-Event")
+		gotMethod = r.Method
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	handle := NewWebhookHandler(WebhookConfig{Secret: secret}, resilience.NewCircuitBreakerRegistry(zap.NewNop()))
+	err := handle(context.Background(), WebhookJobPayload{
+		URL:     server.URL,
+		Headers: map[string]string{"X-Event": "user.created"},
+		Body:    body,
+	})
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %s, want POST", gotMethod)
+	}
+	if string(gotBody) != string(body) {
+		t.Errorf("body = %s, want %s", gotBody, body)
+	}
+	if gotHeader != "user.created" {
+		t.Errorf("X-Event = %q, want user.created", gotHeader)
+	}
+	if want := SignWebhook(secret, gotBody); gotSignature != want {
+		t.Errorf("%s = %q, want %q", WebhookSignatureHeader, gotSignature, want)
+	}
+	if SignWebhook("other", gotBody) == gotSignature {
+		t.Error("signature should depend on the secret")
+	}
+}
+
+func TestWebhookHandler_StatusClassification(t *testing.T) {
+	tests := []struct {
+		status    int
+		wantErr   bool
+		permanent bool
+	}{
+		{http.StatusOK, false, false},
+		{http.StatusBadRequest, true, true},
+		{http.StatusNotFound, true, true},
+		{http.StatusTooManyRequests, true, false},
+		{http.StatusInternalServerError, true, false},
+		{http.StatusServiceUnavailable, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			handle := NewWebhookHandler(WebhookConfig{}, resilience.NewCircuitBreakerRegistry(zap.NewNop()))
+			err := handle(context.Background(), WebhookJobPayload{URL: server.URL})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if jobs.IsPermanent(err) != tt.permanent {
+				t.Errorf("IsPermanent(%v) = %v, want %v", err, jobs.IsPermanent(err), tt.permanent)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_InvalidURL(t *testing.T) {
+	handle := NewWebhookHandler(WebhookConfig{}, resilience.NewCircuitBreakerRegistry(zap.NewNop()))
+	err := handle(context.Background(), WebhookJobPayload{URL: "ftp://example.com/hook"})
+	if !jobs.IsPermanent(err) {
+		t.Errorf("error = %v, want a permanent error", err)
+	}
+}
+
+func TestWebhookHandler_CircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+	breakers := resilience.NewCircuitBreakerRegistry(zap.NewNop())
+	cbConfig := resilience.DefaultCircuitBreakerConfig("webhook:" + target.Host)
+	cbConfig.FailureThreshold = 2
+	breakers.RegisterConfig(cbConfig)
+
+	handle := NewWebhookHandler(WebhookConfig{}, breakers)
+	for i := 0; i < 4; i++ {
+		err := handle(context.Background(), WebhookJobPayload{URL: server.URL})
+		if err == nil || jobs.IsPermanent(err) {
+			t.Fatalf("call %d: error = %v, want a retryable error", i, err)
+		}
+		if i >= 2 && !errors.Is(err, resilience.ErrCircuitOpen) {
+			t.Errorf("call %d: error = %v, want %v", i, err, resilience.ErrCircuitOpen)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server calls = %d, want 2 before the circuit opened", got)
+	}
+}
+
+func TestWebhookHandler_RejectionsDoNotTripBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+	breakers := resilience.NewCircuitBreakerRegistry(zap.NewNop())
+	cbConfig := resilience.DefaultCircuitBreakerConfig("webhook:" + target.Host)
+	cbConfig.FailureThreshold = 1
+	breakers.RegisterConfig(cbConfig)
+
+	handle := NewWebhookHandler(WebhookConfig{}, breakers)
+	for i := 0; i < 3; i++ {
+		_ = handle(context.Background(), WebhookJobPayload{URL: server.URL})
+	}
+	if state := breakers.Get("webhook:" + target.Host).State(); state != resilience.StateClosed {
+		t.Errorf("breaker state = %s, want CLOSED", state)
+	}
+}
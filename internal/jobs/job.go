@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,12 +12,70 @@ import (
 
 // Common errors
 var (
-	ErrJobNotFound     = errors.New("job not found")
-	ErrDuplicateJob    = errors.New("duplicate job with same unique key")
-	ErrQueueEmpty      = errors.New("queue is empty")
-	ErrJobAlreadyTaken = errors.New("job already taken by another worker")
+	ErrJobNotFound        = errors.New("job not found")
+	ErrDuplicateJob       = errors.New("duplicate job with same unique key")
+	ErrQueueEmpty         = errors.New("queue is empty")
+	ErrJobAlreadyTaken    = errors.New("job already taken by another worker")
+	ErrResultNotFound     = errors.New("job result not found")
+	ErrJobTimeout         = errors.New("job timed out")
+	ErrInvalidConcurrency = errors.New("worker concurrency must be at least 1")
 )
 
+// DuplicateJobError reports a job suppressed because another job holds its
+// unique key within a uniqueness window. It matches ErrDuplicateJob.
+type DuplicateJobError struct {
+	JobID string // The job holding the unique key
+}
+
+func (e *DuplicateJobError) Error() string {
+	return ErrDuplicateJob.Error() + ": held by job " + e.JobID
+}
+
+// Is reports whether target is ErrDuplicateJob
+func (e *DuplicateJobError) Is(target error) bool {
+	return target == ErrDuplicateJob
+}
+
+// PanicError is a job failure caused by a handler panic. It carries the
+// recovered value and the stack the panic was raised on.
+type PanicError struct {
+	Value any
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprint(e.Value)
+}
+
+// PermanentError is a job failure that retrying cannot fix, such as an
+// invalid payload. Workers move such jobs straight to the DLQ.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks err as a failure that should not be retried. It returns
+// nil for a nil err.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err, or an error it wraps, is permanent
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
 // Priority represents job priority levels
 type Priority int
 
@@ -47,6 +106,19 @@ func (p Priority) QueueName() string {
 	return "arcana:jobs:queue:" + p.String()
 }
 
+// DefaultQueue is the queue a job goes to when it names none
+const DefaultQueue = "default"
+
+// QueueKey returns the Redis list holding a named queue's jobs of a
+// priority. The default queue keeps the keys used before queues could be
+// named, so jobs queued by older releases are still consumed.
+func QueueKey(queue string, p Priority) string {
+	if queue == "" || queue == DefaultQueue {
+		return p.QueueName()
+	}
+	return "arcana:jobs:queue:" + queue + ":" + p.String()
+}
+
 // JobStatus represents the current status of a job
 type JobStatus string
 
@@ -137,23 +209,54 @@ type Job interface {
 
 // JobPayload is the serializable job data stored in the queue
 type JobPayload struct {
-	ID            string          `json:"id"`
-	Type          string          `json:"type"`
-	Payload       json.RawMessage `json:"payload"`
-	Priority      Priority        `json:"priority"`
-	Status        JobStatus       `json:"status"`
-	Attempts      int             `json:"attempts"`
-	MaxRetries    int             `json:"max_retries"`
-	RetryPolicy   RetryPolicy     `json:"retry_policy"`
-	Timeout       time.Duration   `json:"timeout"`
-	ScheduledAt   *time.Time      `json:"scheduled_at,omitempty"`
-	CreatedAt     time.Time       `json:"created_at"`
-	StartedAt     *time.Time      `json:"started_at,omitempty"`
-	CompletedAt   *time.Time      `json:"completed_at,omitempty"`
-	LastError     string          `json:"last_error,omitempty"`
-	CorrelationID string          `json:"correlation_id,omitempty"`
-	UniqueKey     string          `json:"unique_key,omitempty"`
-	Tags          []string        `json:"tags,omitempty"`
+	ID            string            `json:"id"`
+	Type          string            `json:"type"`
+	Payload       json.RawMessage   `json:"payload"`
+	Priority      Priority          `json:"priority"`
+	Queue         string            `json:"queue,omitempty"` // Named queue; empty is DefaultQueue
+	Status        JobStatus         `json:"status"`
+	Attempts      int               `json:"attempts"`
+	MaxRetries    int               `json:"max_retries"`
+	RetryPolicy   RetryPolicy       `json:"retry_policy"`
+	Timeout       time.Duration     `json:"timeout"`
+	ScheduledAt   *time.Time        `json:"scheduled_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	StartedAt     *time.Time        `json:"started_at,omitempty"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty"`
+	LastError     string            `json:"last_error,omitempty"`
+	LastStack     string            `json:"last_stack,omitempty"`  // Stack of the panic behind LastError, if it was one
+	FailedAt      *time.Time        `json:"failed_at,omitempty"`   // When the job last moved to the DLQ
+	DLQRetries    int               `json:"dlq_retries,omitempty"` // Automatic retries out of the DLQ so far
+	DLQBackoff    time.Duration     `json:"dlq_backoff,omitempty"` // Wait after FailedAt before the next DLQ retry
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	UniqueKey     string            `json:"unique_key,omitempty"`
+	UniqueFor     time.Duration     `json:"unique_for,omitempty"` // Window the unique key blocks duplicates, even after the job finishes
+	Tags          []string          `json:"tags,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"` // Propagated context such as the W3C traceparent
+}
+
+// QueueName returns the name of the queue the job belongs to
+func (jp *JobPayload) QueueName() string {
+	if jp.Queue == "" {
+		return DefaultQueue
+	}
+	return jp.Queue
+}
+
+// QueueKey returns the Redis list the job waits in
+func (jp *JobPayload) QueueKey() string {
+	return QueueKey(jp.Queue, jp.Priority)
+}
+
+// RecordFailure stores why the job's latest attempt failed, including the
+// stack when the failure was a panic
+func (jp *JobPayload) RecordFailure(err error) {
+	jp.LastError = err.Error()
+	jp.LastStack = ""
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		jp.LastStack = panicErr.Stack
+	}
 }
 
 // NewJobPayload creates a new job payload
@@ -193,6 +296,13 @@ func WithPriority(p Priority) JobOption {
 	}
 }
 
+// WithQueue puts the job on a named queue
+func WithQueue(queue string) JobOption {
+	return func(jp *JobPayload) {
+		jp.Queue = queue
+	}
+}
+
 // WithRetryPolicy sets a custom retry policy
 func WithRetryPolicy(policy RetryPolicy) JobOption {
 	return func(jp *JobPayload) {
@@ -237,6 +347,15 @@ func WithUniqueKey(key string) JobOption {
 	}
 }
 
+// WithUniqueFor blocks other jobs with the same unique key for d from
+// enqueue, regardless of whether the job has finished. A duplicate enqueued
+// in the window returns the ID of the job holding the key.
+func WithUniqueFor(d time.Duration) JobOption {
+	return func(jp *JobPayload) {
+		jp.UniqueFor = d
+	}
+}
+
 // WithTags adds tags to the job
 func WithTags(tags ...string) JobOption {
 	return func(jp *JobPayload) {
@@ -244,6 +363,16 @@ func WithTags(tags ...string) JobOption {
 	}
 }
 
+// WithMetadata sets a metadata entry on the job
+func WithMetadata(key, value string) JobOption {
+	return func(jp *JobPayload) {
+		if jp.Metadata == nil {
+			jp.Metadata = make(map[string]string)
+		}
+		jp.Metadata[key] = value
+	}
+}
+
 // JobResult represents the result of a job execution
 type JobResult struct {
 	JobID       string        `json:"job_id"`
@@ -253,12 +382,35 @@ type JobResult struct {
 	CompletedAt time.Time     `json:"completed_at"`
 }
 
+// DefaultResultTTL is how long job results are retained when no TTL is configured
+const DefaultResultTTL = 24 * time.Hour
+
+// ResultStore persists job results
+type ResultStore interface {
+	// SaveResult stores the JSON-encoded result of a job
+	SaveResult(ctx context.Context, jobID string, result json.RawMessage, ttl time.Duration) error
+	// GetResult retrieves a stored job result
+	GetResult(ctx context.Context, jobID string) (json.RawMessage, error)
+}
+
+// StatsQueueDepthPrefix prefixes the Queue.GetStats entries holding the
+// number of jobs waiting in each named queue
+const StatsQueueDepthPrefix = "queue_depth:"
+
 // Queue is the interface for job queue operations
 type Queue interface {
+	ResultStore
+	ProgressStore
+
 	// Enqueue adds a job to the queue
 	Enqueue(ctx context.Context, job *JobPayload) error
-	// Dequeue retrieves the next job from the queue
+	// EnqueueBatch adds multiple jobs in one round trip; the returned slice holds a
+	// per-job error (nil on success) in input order
+	EnqueueBatch(ctx context.Context, jobs []*JobPayload) ([]error, error)
+	// Dequeue retrieves the next job from the default queue
 	Dequeue(ctx context.Context, priorities ...Priority) (*JobPayload, error)
+	// DequeueFrom retrieves the next job from any of the named queues
+	DequeueFrom(ctx context.Context, queues []string) (*JobPayload, error)
 	// GetJob retrieves a job by ID
 	GetJob(ctx context.Context, jobID string) (*JobPayload, error)
 	// UpdateJob updates a job's data
@@ -267,6 +419,10 @@ type Queue interface {
 	Complete(ctx context.Context, jobID string) error
 	// Fail marks a job as failed and handles retry logic
 	Fail(ctx context.Context, jobID string, jobErr error) error
+	// ScheduleRetry marks a job as failed and schedules another attempt after delay
+	ScheduleRetry(ctx context.Context, jobID string, jobErr error, delay time.Duration) error
+	// MoveToDLQ marks a job as failed and moves it to the dead letter queue
+	MoveToDLQ(ctx context.Context, jobID string, jobErr error) error
 	// ProcessScheduled moves scheduled jobs that are due to their queues
 	ProcessScheduled(ctx context.Context) (int, error)
 	// GetDLQJobs retrieves jobs from the dead letter queue
@@ -277,8 +433,16 @@ type Queue interface {
 	DeleteJob(ctx context.Context, jobID string) error
 	// RequeueJob adds a job back to the queue
 	RequeueJob(ctx context.Context, jobID string, queueKey string) error
+	// PauseType stops jobs of a type from being processed, fleet-wide
+	PauseType(ctx context.Context, jobType string) error
+	// ResumeType lifts a pause on a job type
+	ResumeType(ctx context.Context, jobType string) error
+	// PausedTypes returns the job types currently paused
+	PausedTypes(ctx context.Context) ([]string, error)
 	// GetStats returns queue statistics
 	GetStats(ctx context.Context) (map[string]int64, error)
+	// DepthByPriority returns how many jobs wait in each priority of a named queue
+	DepthByPriority(ctx context.Context, queue string) (map[Priority]int64, error)
 }
 
 // ScheduledJobInfo represents information about a scheduled job
@@ -289,6 +453,23 @@ type ScheduledJobInfo struct {
 	NextRun   time.Time `json:"next_run"`
 	Priority  string    `json:"priority"`
 	Singleton bool      `json:"singleton"`
+	Timezone  string    `json:"timezone"`
+	// Enabled is false while the job is disabled and not enqueued on its schedule
+	Enabled bool `json:"enabled"`
+	// LastTriggeredAt is when the job was last enqueued, on schedule or manually
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+}
+
+// SchedulerStatus describes the scheduler leader election as seen by one instance
+type SchedulerStatus struct {
+	InstanceID string `json:"instance_id"`
+	IsLeader   bool   `json:"is_leader"`
+	// LeaderID is the instance holding the leader lease, empty if none does
+	LeaderID string `json:"leader_id,omitempty"`
+	// LeaseExpiresAt is when the leader lease runs out unless renewed
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+	// LastElectionAt is when any instance last became leader
+	LastElectionAt *time.Time `json:"last_election_at,omitempty"`
 }
 
 // Scheduler is the interface for job scheduler operations
@@ -2,6 +2,10 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -13,19 +17,31 @@ type WorkerPool interface {
 	Stop(ctx context.Context) error
 	// Stats returns worker pool statistics
 	Stats() WorkerPoolStats
+	// PauseType stops processing jobs of a type fleet-wide
+	PauseType(ctx context.Context, jobType string) error
+	// ResumeType resumes processing jobs of a paused type
+	ResumeType(ctx context.Context, jobType string) error
+	// SetConcurrency changes the number of workers at runtime
+	SetConcurrency(n int) error
 }
 
 // WorkerPoolStats contains worker pool statistics
 type WorkerPoolStats struct {
-	Running       bool
-	WorkerID      string
-	ActiveWorkers int64
-	ProcessedJobs int64
-	FailedJobs    int64
-	SkippedJobs   int64
-	Concurrency   int
+	Running            bool
+	WorkerID           string
+	ActiveWorkers      int64
+	ProcessedJobs      int64
+	FailedJobs         int64
+	SkippedJobs        int64
+	Concurrency        int              // Workers the pool runs, or is scaling to
+	CurrentConcurrency int              // Workers running now; differs from Concurrency while scaling
+	ThrottledJobs      map[string]int64 // Jobs held back by per-type concurrency limits
 }
 
+// maxDLQScan caps how many dead letter queue entries are read when the
+// whole DLQ is walked
+const maxDLQScan = 10000
+
 // jobService implements Service
 type jobService struct {
 	queue     Queue
@@ -47,8 +63,13 @@ func (s *jobService) Enqueue(ctx context.Context, jobType string, payload any, o
 	if err != nil {
 		return "", err
 	}
+	InjectTraceContext(ctx, job)
 
 	if err := s.queue.Enqueue(ctx, job); err != nil {
+		var dup *DuplicateJobError
+		if errors.As(err, &dup) {
+			return dup.JobID, nil
+		}
 		return "", err
 	}
 
@@ -65,10 +86,56 @@ func (s *jobService) EnqueueIn(ctx context.Context, jobType string, payload any,
 	return s.Enqueue(ctx, jobType, payload, opts...)
 }
 
+func (s *jobService) EnqueueBatch(ctx context.Context, reqs []EnqueueRequest) ([]*JobPayload, error) {
+	results := make([]*JobPayload, len(reqs))
+	var failures []BatchItemError
+
+	batch := make([]*JobPayload, 0, len(reqs))
+	indexes := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		job, err := NewJobPayload(req.Type, req.Payload, req.Options...)
+		if err != nil {
+			failures = append(failures, BatchItemError{Index: i, Err: err})
+			continue
+		}
+		InjectTraceContext(ctx, job)
+		batch = append(batch, job)
+		indexes = append(indexes, i)
+	}
+
+	if len(batch) > 0 {
+		itemErrs, err := s.queue.EnqueueBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for j, job := range batch {
+			if itemErrs[j] != nil {
+				failures = append(failures, BatchItemError{Index: indexes[j], Err: itemErrs[j]})
+				continue
+			}
+			results[indexes[j]] = job
+		}
+	}
+
+	if len(failures) > 0 {
+		sort.Slice(failures, func(a, b int) bool { return failures[a].Index < failures[b].Index })
+		return results, &BatchEnqueueError{Failures: failures}
+	}
+	return results, nil
+}
+
 func (s *jobService) GetJob(ctx context.Context, jobID string) (*JobPayload, error) {
 	return s.queue.GetJob(ctx, jobID)
 }
 
+func (s *jobService) GetJobResult(ctx context.Context, jobID string) (json.RawMessage, error) {
+	return s.queue.GetResult(ctx, jobID)
+}
+
+func (s *jobService) GetJobProgress(ctx context.Context, jobID string) (*JobProgress, error) {
+	return s.queue.GetProgress(ctx, jobID)
+}
+
 func (s *jobService) CancelJob(ctx context.Context, jobID string) error {
 	return s.queue.DeleteJob(ctx, jobID)
 }
@@ -88,12 +155,29 @@ func (s *jobService) RetryJob(ctx context.Context, jobID string) error {
 	return s.queue.Enqueue(ctx, job)
 }
 
+func (s *jobService) PauseJobType(ctx context.Context, jobType string) error {
+	return s.pool.PauseType(ctx, jobType)
+}
+
+func (s *jobService) ResumeJobType(ctx context.Context, jobType string) error {
+	return s.pool.ResumeType(ctx, jobType)
+}
+
+func (s *jobService) SetWorkerConcurrency(ctx context.Context, n int) error {
+	return s.pool.SetConcurrency(n)
+}
+
 func (s *jobService) GetQueueStats(ctx context.Context) (*QueueStats, error) {
 	queueStats, err := s.queue.GetStats(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	pausedTypes, err := s.queue.PausedTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	poolStats := s.pool.Stats()
 
 	var schedulerStats SchedulerStats
@@ -110,6 +194,13 @@ func (s *jobService) GetQueueStats(ctx context.Context) (*QueueStats, error) {
 		}
 	}
 
+	queueDepths := make(map[string]int64)
+	for key, depth := range queueStats {
+		if name, ok := strings.CutPrefix(key, StatsQueueDepthPrefix); ok {
+			queueDepths[name] = depth
+		}
+	}
+
 	return &QueueStats{
 		Pending:   queueStats["pending"],
 		Scheduled: queueStats["scheduled"],
@@ -122,27 +213,71 @@ func (s *jobService) GetQueueStats(ctx context.Context) (*QueueStats, error) {
 			"normal":   queueStats["queue_normal"],
 			"low":      queueStats["queue_low"],
 		},
+		QueueDepths: queueDepths,
 		WorkerStats: WorkerStats{
-			Running:       poolStats.Running,
-			ActiveWorkers: poolStats.ActiveWorkers,
-			Concurrency:   poolStats.Concurrency,
-			ProcessedJobs: poolStats.ProcessedJobs,
-			FailedJobs:    poolStats.FailedJobs,
+			Running:            poolStats.Running,
+			ActiveWorkers:      poolStats.ActiveWorkers,
+			Concurrency:        poolStats.Concurrency,
+			CurrentConcurrency: poolStats.CurrentConcurrency,
+			ProcessedJobs:      poolStats.ProcessedJobs,
+			FailedJobs:         poolStats.FailedJobs,
+			ThrottledJobs:      poolStats.ThrottledJobs,
 		},
+		PausedTypes:    pausedTypes,
 		SchedulerStats: schedulerStats,
 	}, nil
 }
 
+func (s *jobService) GetQueueBacklog(ctx context.Context, queue string, priority Priority) (*QueueBacklog, error) {
+	depths, err := s.queue.DepthByPriority(ctx, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	backlog := &QueueBacklog{
+		Queue:          queue,
+		Priority:       priority,
+		Depth:          depths[priority],
+		ProcessingRate: GlobalMetrics.ProcessingRate(),
+	}
+	if backlog.ProcessingRate > 0 {
+		backlog.EstimatedWait = time.Duration(float64(backlog.Depth) / backlog.ProcessingRate * float64(time.Second))
+	}
+	return backlog, nil
+}
+
 func (s *jobService) GetDLQJobs(ctx context.Context, limit int) ([]*JobPayload, error) {
 	return s.queue.GetDLQJobs(ctx, int64(limit))
 }
 
+func (s *jobService) FindDLQJobs(ctx context.Context, filter DLQFilter, limit int) ([]*JobPayload, error) {
+	if filter == (DLQFilter{}) {
+		return s.GetDLQJobs(ctx, limit)
+	}
+
+	deadJobs, err := s.queue.GetDLQJobs(ctx, maxDLQScan)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]*JobPayload, 0, min(limit, len(deadJobs)))
+	for _, job := range deadJobs {
+		if len(matched) == limit {
+			break
+		}
+		if filter.Matches(job) {
+			matched = append(matched, job)
+		}
+	}
+	return matched, nil
+}
+
 func (s *jobService) RetryDLQJob(ctx context.Context, jobID string) error {
 	return s.queue.RetryDLQJob(ctx, jobID)
 }
 
 func (s *jobService) PurgeDLQ(ctx context.Context) error {
-	jobs, err := s.queue.GetDLQJobs(ctx, 10000)
+	jobs, err := s.queue.GetDLQJobs(ctx, maxDLQScan)
 	if err != nil {
 		return err
 	}
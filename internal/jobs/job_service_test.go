@@ -2,34 +2,49 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ----- Mock Queue -----
 
 type mockQueue struct {
 	enqueueFunc          func(ctx context.Context, job *JobPayload) error
+	enqueueBatchFunc     func(ctx context.Context, jobs []*JobPayload) ([]error, error)
 	dequeueFunc          func(ctx context.Context, priorities ...Priority) (*JobPayload, error)
 	getJobFunc           func(ctx context.Context, jobID string) (*JobPayload, error)
 	updateJobFunc        func(ctx context.Context, job *JobPayload) error
 	completeFunc         func(ctx context.Context, jobID string) error
 	failFunc             func(ctx context.Context, jobID string, jobErr error) error
+	scheduleRetryFunc    func(ctx context.Context, jobID string, jobErr error, delay time.Duration) error
+	moveToDLQFunc        func(ctx context.Context, jobID string, jobErr error) error
 	processScheduledFunc func(ctx context.Context) (int, error)
 	getDLQJobsFunc       func(ctx context.Context, limit int64) ([]*JobPayload, error)
 	retryDLQJobFunc      func(ctx context.Context, jobID string) error
 	deleteJobFunc        func(ctx context.Context, jobID string) error
 	requeueJobFunc       func(ctx context.Context, jobID string, queueKey string) error
 	getStatsFunc         func(ctx context.Context) (map[string]int64, error)
+	depthByPriorityFunc  func(ctx context.Context, queue string) (map[Priority]int64, error)
+	saveResultFunc       func(ctx context.Context, jobID string, result json.RawMessage, ttl time.Duration) error
+	getResultFunc        func(ctx context.Context, jobID string) (json.RawMessage, error)
+	setProgressFunc      func(ctx context.Context, jobID string, progress JobProgress) error
+	getProgressFunc      func(ctx context.Context, jobID string) (*JobProgress, error)
+	pausedTypesFunc      func(ctx context.Context) ([]string, error)
 }
 
 func newDefaultMockQueue() *mockQueue {
 	return &mockQueue{
 		enqueueFunc: func(_ context.Context, _ *JobPayload) error { return nil },
+		enqueueBatchFunc: func(_ context.Context, jobs []*JobPayload) ([]error, error) {
+			return make([]error, len(jobs)), nil
+		},
 		dequeueFunc: func(_ context.Context, _ ...Priority) (*JobPayload, error) { return nil, ErrQueueEmpty },
 		getJobFunc: func(_ context.Context, jobID string) (*JobPayload, error) {
 			return &JobPayload{ID: jobID, Type: "test", Status: JobStatusPending}, nil
@@ -37,6 +52,8 @@ func newDefaultMockQueue() *mockQueue {
 		updateJobFunc:        func(_ context.Context, _ *JobPayload) error { return nil },
 		completeFunc:         func(_ context.Context, _ string) error { return nil },
 		failFunc:             func(_ context.Context, _ string, _ error) error { return nil },
+		scheduleRetryFunc:    func(_ context.Context, _ string, _ error, _ time.Duration) error { return nil },
+		moveToDLQFunc:        func(_ context.Context, _ string, _ error) error { return nil },
 		processScheduledFunc: func(_ context.Context) (int, error) { return 0, nil },
 		getDLQJobsFunc:       func(_ context.Context, _ int64) ([]*JobPayload, error) { return []*JobPayload{}, nil },
 		retryDLQJobFunc:      func(_ context.Context, _ string) error { return nil },
@@ -55,15 +72,33 @@ func newDefaultMockQueue() *mockQueue {
 				"queue_low":        0,
 			}, nil
 		},
+		saveResultFunc: func(_ context.Context, _ string, _ json.RawMessage, _ time.Duration) error { return nil },
+		getResultFunc: func(_ context.Context, _ string) (json.RawMessage, error) {
+			return nil, ErrResultNotFound
+		},
+		setProgressFunc: func(_ context.Context, _ string, _ JobProgress) error { return nil },
+		pausedTypesFunc: func(_ context.Context) ([]string, error) { return nil, nil },
+		depthByPriorityFunc: func(_ context.Context, _ string) (map[Priority]int64, error) {
+			return map[Priority]int64{}, nil
+		},
+		getProgressFunc: func(_ context.Context, _ string) (*JobProgress, error) {
+			return nil, ErrProgressNotFound
+		},
 	}
 }
 
 func (m *mockQueue) Enqueue(ctx context.Context, job *JobPayload) error {
 	return m.enqueueFunc(ctx, job)
 }
+func (m *mockQueue) EnqueueBatch(ctx context.Context, jobs []*JobPayload) ([]error, error) {
+	return m.enqueueBatchFunc(ctx, jobs)
+}
 func (m *mockQueue) Dequeue(ctx context.Context, priorities ...Priority) (*JobPayload, error) {
 	return m.dequeueFunc(ctx, priorities...)
 }
+func (m *mockQueue) DequeueFrom(ctx context.Context, queues []string) (*JobPayload, error) {
+	return m.dequeueFunc(ctx)
+}
 func (m *mockQueue) GetJob(ctx context.Context, jobID string) (*JobPayload, error) {
 	return m.getJobFunc(ctx, jobID)
 }
@@ -76,6 +111,12 @@ func (m *mockQueue) Complete(ctx context.Context, jobID string) error {
 func (m *mockQueue) Fail(ctx context.Context, jobID string, jobErr error) error {
 	return m.failFunc(ctx, jobID, jobErr)
 }
+func (m *mockQueue) ScheduleRetry(ctx context.Context, jobID string, jobErr error, delay time.Duration) error {
+	return m.scheduleRetryFunc(ctx, jobID, jobErr, delay)
+}
+func (m *mockQueue) MoveToDLQ(ctx context.Context, jobID string, jobErr error) error {
+	return m.moveToDLQFunc(ctx, jobID, jobErr)
+}
 func (m *mockQueue) ProcessScheduled(ctx context.Context) (int, error) {
 	return m.processScheduledFunc(ctx)
 }
@@ -94,16 +135,58 @@ func (m *mockQueue) RequeueJob(ctx context.Context, jobID string, queueKey strin
 func (m *mockQueue) GetStats(ctx context.Context) (map[string]int64, error) {
 	return m.getStatsFunc(ctx)
 }
+func (m *mockQueue) DepthByPriority(ctx context.Context, queue string) (map[Priority]int64, error) {
+	return m.depthByPriorityFunc(ctx, queue)
+}
+func (m *mockQueue) SaveResult(ctx context.Context, jobID string, result json.RawMessage, ttl time.Duration) error {
+	return m.saveResultFunc(ctx, jobID, result, ttl)
+}
+func (m *mockQueue) GetResult(ctx context.Context, jobID string) (json.RawMessage, error) {
+	return m.getResultFunc(ctx, jobID)
+}
+func (m *mockQueue) SetProgress(ctx context.Context, jobID string, progress JobProgress) error {
+	return m.setProgressFunc(ctx, jobID, progress)
+}
+func (m *mockQueue) GetProgress(ctx context.Context, jobID string) (*JobProgress, error) {
+	return m.getProgressFunc(ctx, jobID)
+}
+func (m *mockQueue) ExpireProgress(ctx context.Context, jobID string, ttl time.Duration) error {
+	return nil
+}
+func (m *mockQueue) PauseType(ctx context.Context, jobType string) error { return nil }
+func (m *mockQueue) ResumeType(ctx context.Context, jobType string) error { return nil }
+func (m *mockQueue) PausedTypes(ctx context.Context) ([]string, error) {
+	return m.pausedTypesFunc(ctx)
+}
 
 // ----- Mock WorkerPool -----
 
 type mockWorkerPool struct {
-	stats WorkerPoolStats
+	stats  WorkerPoolStats
+	paused map[string]bool
 }
 
 func (m *mockWorkerPool) Start(_ context.Context) error { return nil }
 func (m *mockWorkerPool) Stop(_ context.Context) error  { return nil }
 func (m *mockWorkerPool) Stats() WorkerPoolStats        { return m.stats }
+func (m *mockWorkerPool) PauseType(_ context.Context, jobType string) error {
+	if m.paused == nil {
+		m.paused = make(map[string]bool)
+	}
+	m.paused[jobType] = true
+	return nil
+}
+func (m *mockWorkerPool) ResumeType(_ context.Context, jobType string) error {
+	delete(m.paused, jobType)
+	return nil
+}
+func (m *mockWorkerPool) SetConcurrency(n int) error {
+	if n < 1 {
+		return ErrInvalidConcurrency
+	}
+	m.stats.Concurrency = n
+	return nil
+}
 
 // ----- Mock Scheduler -----
 
@@ -171,6 +254,199 @@ func TestJobService_Enqueue_QueueError(t *testing.T) {
 	assert.Contains(t, err.Error(), "queue full")
 }
 
+// TestJobService_Enqueue_Duplicate returns the holder's ID only within a uniqueness window
+func TestJobService_Enqueue_Duplicate(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.enqueueFunc = func(_ context.Context, job *JobPayload) error {
+		if job.UniqueFor > 0 {
+			return &DuplicateJobError{JobID: "existing-job"}
+		}
+		return ErrDuplicateJob
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	jobID, err := svc.Enqueue(context.Background(), "test-job", nil, WithUniqueKey("k"), WithUniqueFor(10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "existing-job", jobID)
+
+	_, err = svc.Enqueue(context.Background(), "test-job", nil, WithUniqueKey("k"))
+	assert.ErrorIs(t, err, ErrDuplicateJob)
+}
+
+// TestJobService_Enqueue_PropagatesTraceContext stores the caller's trace in the job
+func TestJobService_Enqueue_PropagatesTraceContext(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	var enqueued *JobPayload
+	q := newDefaultMockQueue()
+	q.enqueueFunc = func(_ context.Context, job *JobPayload) error {
+		enqueued = job
+		return nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	_, err := svc.Enqueue(ctx, "test-job", nil)
+	require.NoError(t, err)
+	require.NotNil(t, enqueued)
+	assert.Contains(t, enqueued.Metadata["traceparent"], span.SpanContext().TraceID().String())
+
+	extracted := trace.SpanContextFromContext(ExtractTraceContext(context.Background(), enqueued))
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+}
+
+// TestJobService_Enqueue_NoTraceContext leaves metadata empty without a span
+func TestJobService_Enqueue_NoTraceContext(t *testing.T) {
+	var enqueued *JobPayload
+	q := newDefaultMockQueue()
+	q.enqueueFunc = func(_ context.Context, job *JobPayload) error {
+		enqueued = job
+		return nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	_, err := svc.Enqueue(context.Background(), "test-job", nil)
+	require.NoError(t, err)
+	assert.Nil(t, enqueued.Metadata)
+}
+
+// TestJobService_EnqueueBatch_Success returns jobs in request order
+func TestJobService_EnqueueBatch_Success(t *testing.T) {
+	q := newDefaultMockQueue()
+	var batchSize int
+	q.enqueueBatchFunc = func(_ context.Context, jobs []*JobPayload) ([]error, error) {
+		batchSize = len(jobs)
+		return make([]error, len(jobs)), nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	results, err := svc.EnqueueBatch(context.Background(), []EnqueueRequest{
+		{Type: "a", Payload: map[string]int{"n": 1}},
+		{Type: "b", Payload: nil, Options: []JobOption{WithPriority(PriorityHigh)}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 2, batchSize)
+	assert.Equal(t, "a", results[0].Type)
+	assert.Equal(t, "b", results[1].Type)
+	assert.Equal(t, PriorityHigh, results[1].Priority)
+}
+
+// TestJobService_EnqueueBatch_PartialFailure reports failures by index
+func TestJobService_EnqueueBatch_PartialFailure(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.enqueueBatchFunc = func(_ context.Context, jobs []*JobPayload) ([]error, error) {
+		errs := make([]error, len(jobs))
+		errs[1] = ErrDuplicateJob
+		return errs, nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	results, err := svc.EnqueueBatch(context.Background(), []EnqueueRequest{
+		{Type: "ok"},
+		{Type: "bad", Payload: make(chan int)}, // cannot be marshaled
+		{Type: "dup", Options: []JobOption{WithUniqueKey("k")}},
+		{Type: "ok"},
+	})
+	var batchErr *BatchEnqueueError
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Failures, 2)
+	assert.Equal(t, 1, batchErr.Failures[0].Index)
+	assert.Equal(t, 2, batchErr.Failures[1].Index)
+	assert.ErrorIs(t, batchErr.Failures[1].Err, ErrDuplicateJob)
+
+	require.Len(t, results, 4)
+	assert.NotNil(t, results[0])
+	assert.Nil(t, results[1])
+	assert.Nil(t, results[2])
+	assert.NotNil(t, results[3])
+}
+
+// TestJobService_EnqueueBatch_QueueError fails the whole batch
+func TestJobService_EnqueueBatch_QueueError(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.enqueueBatchFunc = func(_ context.Context, _ []*JobPayload) ([]error, error) {
+		return nil, errors.New("pipeline failed")
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	_, err := svc.EnqueueBatch(context.Background(), []EnqueueRequest{{Type: "a"}})
+	assert.ErrorContains(t, err, "pipeline failed")
+}
+
+// TestJobService_GetJobProgress returns the latest progress
+func TestJobService_GetJobProgress(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.getProgressFunc = func(_ context.Context, jobID string) (*JobProgress, error) {
+		return &JobProgress{Percent: 40, Message: "rendering"}, nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	progress, err := svc.GetJobProgress(context.Background(), "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, 40, progress.Percent)
+	assert.Equal(t, "rendering", progress.Message)
+}
+
+// TestJobService_PauseResumeJobType delegates to the worker pool
+func TestJobService_PauseResumeJobType(t *testing.T) {
+	pool := &mockWorkerPool{}
+	svc := newTestJobService(newDefaultMockQueue(), pool, nil)
+
+	require.NoError(t, svc.PauseJobType(context.Background(), "webhook"))
+	assert.True(t, pool.paused["webhook"])
+
+	require.NoError(t, svc.ResumeJobType(context.Background(), "webhook"))
+	assert.False(t, pool.paused["webhook"])
+}
+
+// TestJobService_SetWorkerConcurrency scales the worker pool
+func TestJobService_SetWorkerConcurrency(t *testing.T) {
+	pool := &mockWorkerPool{}
+	svc := newTestJobService(newDefaultMockQueue(), pool, nil)
+
+	require.NoError(t, svc.SetWorkerConcurrency(context.Background(), 16))
+	assert.Equal(t, 16, pool.stats.Concurrency)
+	assert.ErrorIs(t, svc.SetWorkerConcurrency(context.Background(), 0), ErrInvalidConcurrency)
+}
+
+// TestJobService_GetQueueStats_PausedTypes includes paused job types
+func TestJobService_GetQueueStats_PausedTypes(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.pausedTypesFunc = func(_ context.Context) ([]string, error) {
+		return []string{"webhook"}, nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	stats, err := svc.GetQueueStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"webhook"}, stats.PausedTypes)
+}
+
+// TestJobService_GetJobResult returns the stored result
+func TestJobService_GetJobResult(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.getResultFunc = func(_ context.Context, jobID string) (json.RawMessage, error) {
+		assert.Equal(t, "job-1", jobID)
+		return json.RawMessage(`{"ok":true}`), nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	result, err := svc.GetJobResult(context.Background(), "job-1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok":true}`, string(result))
+}
+
+// TestJobService_GetJobResult_NotFound propagates ErrResultNotFound
+func TestJobService_GetJobResult_NotFound(t *testing.T) {
+	svc := newTestJobService(newDefaultMockQueue(), &mockWorkerPool{}, nil)
+
+	_, err := svc.GetJobResult(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrResultNotFound)
+}
+
 // TestJobService_EnqueueAt schedules a job at a specific time
 func TestJobService_EnqueueAt(t *testing.T) {
 	q := newDefaultMockQueue()
@@ -300,6 +576,54 @@ func TestJobService_GetQueueStats_Success(t *testing.T) {
 	assert.Equal(t, 4, stats.WorkerStats.Concurrency)
 }
 
+// TestJobService_GetQueueBacklog estimates the wait from the processing rate
+func TestJobService_GetQueueBacklog(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.depthByPriorityFunc = func(_ context.Context, queue string) (map[Priority]int64, error) {
+		assert.Equal(t, "mail", queue)
+		return map[Priority]int64{PriorityHigh: 12, PriorityNormal: 40}, nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+	for range 6 {
+		GlobalMetrics.RecordJobCompleted("test", time.Millisecond)
+	}
+
+	backlog, err := svc.GetQueueBacklog(context.Background(), "mail", PriorityHigh)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), backlog.Depth)
+	require.Positive(t, backlog.ProcessingRate)
+	assert.InDelta(t, 12/backlog.ProcessingRate, backlog.EstimatedWait.Seconds(), 0.001)
+}
+
+// TestJobService_GetQueueBacklog_Error passes queue errors through
+func TestJobService_GetQueueBacklog_Error(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.depthByPriorityFunc = func(_ context.Context, _ string) (map[Priority]int64, error) {
+		return nil, errors.New("redis down")
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	_, err := svc.GetQueueBacklog(context.Background(), DefaultQueue, PriorityNormal)
+	assert.Error(t, err)
+}
+
+// TestJobService_GetQueueStats_QueueDepths reports each named queue's depth
+func TestJobService_GetQueueStats_QueueDepths(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.getStatsFunc = func(_ context.Context) (map[string]int64, error) {
+		return map[string]int64{
+			"pending":                             7,
+			StatsQueueDepthPrefix + DefaultQueue: 4,
+			StatsQueueDepthPrefix + "mail":       3,
+		}, nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	stats, err := svc.GetQueueStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{DefaultQueue: 4, "mail": 3}, stats.QueueDepths)
+}
+
 // TestJobService_GetQueueStats_WithScheduler includes scheduler stats
 func TestJobService_GetQueueStats_WithScheduler(t *testing.T) {
 	q := newDefaultMockQueue()
@@ -352,6 +676,44 @@ func TestJobService_GetDLQJobs_Success(t *testing.T) {
 	assert.Equal(t, "dlq-1", jobs[0].ID)
 }
 
+// TestJobService_FindDLQJobs filters DLQ jobs by type and error text
+func TestJobService_FindDLQJobs(t *testing.T) {
+	q := newDefaultMockQueue()
+	q.getDLQJobsFunc = func(_ context.Context, _ int64) ([]*JobPayload, error) {
+		return []*JobPayload{
+			{ID: "dlq-1", Type: "email", LastError: "SMTP timeout"},
+			{ID: "dlq-2", Type: "webhook", LastError: "connection refused"},
+			{ID: "dlq-3", Type: "email", LastError: "invalid address"},
+			{ID: "dlq-4", Type: "email", LastError: "smtp timeout again"},
+		}, nil
+	}
+	svc := newTestJobService(q, &mockWorkerPool{}, nil)
+
+	tests := []struct {
+		name    string
+		filter  DLQFilter
+		limit   int
+		wantIDs []string
+	}{
+		{"no filter", DLQFilter{}, 10, []string{"dlq-1", "dlq-2", "dlq-3", "dlq-4"}},
+		{"by type", DLQFilter{Type: "email"}, 10, []string{"dlq-1", "dlq-3", "dlq-4"}},
+		{"by error", DLQFilter{ErrorContains: "Timeout"}, 10, []string{"dlq-1", "dlq-4"}},
+		{"by both", DLQFilter{Type: "email", ErrorContains: "address"}, 10, []string{"dlq-3"}},
+		{"limited", DLQFilter{Type: "email"}, 1, []string{"dlq-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := svc.FindDLQJobs(context.Background(), tt.filter, tt.limit)
+			require.NoError(t, err)
+			ids := make([]string, len(found))
+			for i, job := range found {
+				ids[i] = job.ID
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
+
 // TestJobService_GetDLQJobs_Error propagates error
 func TestJobService_GetDLQJobs_Error(t *testing.T) {
 	q := newDefaultMockQueue()
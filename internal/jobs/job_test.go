@@ -2,6 +2,8 @@ package jobs
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -37,6 +39,26 @@ func TestPriority_QueueName(t *testing.T) {
 	assert.Equal(t, "arcana:jobs:queue:critical", PriorityCritical.QueueName())
 }
 
+// TestQueueKey tests named queues keep the default queue's keys unchanged
+func TestQueueKey(t *testing.T) {
+	assert.Equal(t, "arcana:jobs:queue:high", QueueKey("", PriorityHigh))
+	assert.Equal(t, "arcana:jobs:queue:high", QueueKey(DefaultQueue, PriorityHigh))
+	assert.Equal(t, "arcana:jobs:queue:mail:high", QueueKey("mail", PriorityHigh))
+}
+
+// TestWithQueue tests the queue option
+func TestWithQueue(t *testing.T) {
+	jp, err := NewJobPayload("test", nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultQueue, jp.QueueName())
+	assert.Equal(t, PriorityNormal.QueueName(), jp.QueueKey())
+
+	jp, err = NewJobPayload("test", nil, WithQueue("mail"))
+	require.NoError(t, err)
+	assert.Equal(t, "mail", jp.QueueName())
+	assert.Equal(t, "arcana:jobs:queue:mail:normal", jp.QueueKey())
+}
+
 // TestDefaultRetryPolicy verifies defaults
 func TestDefaultRetryPolicy(t *testing.T) {
 	policy := DefaultRetryPolicy()
@@ -148,6 +170,7 @@ func TestNewJobPayload_WithOptions(t *testing.T) {
 		WithScheduledAt(scheduledAt),
 		WithCorrelationID("corr-123"),
 		WithUniqueKey("unique-key"),
+		WithUniqueFor(10*time.Minute),
 		WithTags("tag1", "tag2"),
 	)
 	require.NoError(t, err)
@@ -158,6 +181,7 @@ func TestNewJobPayload_WithOptions(t *testing.T) {
 	assert.WithinDuration(t, scheduledAt, *jp.ScheduledAt, time.Second)
 	assert.Equal(t, "corr-123", jp.CorrelationID)
 	assert.Equal(t, "unique-key", jp.UniqueKey)
+	assert.Equal(t, 10*time.Minute, jp.UniqueFor)
 	assert.Equal(t, []string{"tag1", "tag2"}, jp.Tags)
 }
 
@@ -213,6 +237,26 @@ func TestJobErrors(t *testing.T) {
 	assert.NotNil(t, ErrJobAlreadyTaken)
 }
 
+// TestJobPayload_RecordFailure keeps a panic's stack and clears it otherwise
+func TestJobPayload_RecordFailure(t *testing.T) {
+	jp := &JobPayload{}
+
+	jp.RecordFailure(fmt.Errorf("handler panicked: %w", &PanicError{Value: "boom", Stack: "goroutine 1"}))
+	assert.Equal(t, "handler panicked: boom", jp.LastError)
+	assert.Equal(t, "goroutine 1", jp.LastStack)
+
+	jp.RecordFailure(errors.New("timeout"))
+	assert.Equal(t, "timeout", jp.LastError)
+	assert.Empty(t, jp.LastStack)
+}
+
+// TestDuplicateJobError matches ErrDuplicateJob and names the holder
+func TestDuplicateJobError(t *testing.T) {
+	var err error = &DuplicateJobError{JobID: "job-1"}
+	assert.ErrorIs(t, err, ErrDuplicateJob)
+	assert.Contains(t, err.Error(), "job-1")
+}
+
 // TestPow helper function
 func TestPow(t *testing.T) {
 	assert.Equal(t, 1.0, pow(2, 0))
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,18 +19,36 @@ const (
 	keyPrefixRunningJobs = "arcana:jobs:running"
 	keyPrefixIdempotency = "arcana:jobs:idempotency:"
 	keyPrefixWorkerJobs  = "arcana:jobs:worker:"
+	keyPrefixFenceToken  = "arcana:jobs:fence:"
 
 	// Default settings
 	defaultLockTTL       = 5 * time.Minute
 	defaultHeartbeatRate = 30 * time.Second
+
+	// fenceTokenTTL is how long a job's fencing counter survives after its last
+	// acquisition; a worker stalled for longer than this can no longer be fenced
+	fenceTokenTTL = 24 * time.Hour
 )
 
 var (
 	ErrLockNotAcquired = errors.New("failed to acquire job lock")
 	ErrLockNotHeld     = errors.New("lock not held by this worker")
 	ErrJobAlreadyDone  = errors.New("job already completed (idempotency check)")
+	ErrStaleToken      = errors.New("stale fencing token")
 )
 
+// acquireScript takes the lock and, only if it was free, issues the next
+// fencing token for the job. Doing both atomically guarantees tokens increase
+// in acquisition order.
+var acquireScript = redis.NewScript(`
+	if not redis.call("set", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+		return 0
+	end
+	local token = redis.call("incr", KEYS[2])
+	redis.call("pexpire", KEYS[2], ARGV[3])
+	return token
+`)
+
 // JobLock represents a distributed lock for job execution
 type JobLock struct {
 	redis      *redis.Client
@@ -36,7 +56,9 @@ type JobLock struct {
 	workerID   string
 	lockKey    string
 	ttl        time.Duration
+	token      int64
 	held       bool
+	lost       chan struct{}
 	cancelFunc context.CancelFunc
 	mu         sync.Mutex
 }
@@ -48,6 +70,19 @@ func (jl *JobLock) IsHeld() bool {
 	return jl.held
 }
 
+// Lost returns a channel that is closed when the lock expires or is taken over
+// by another worker while it is held. It is not closed by ReleaseLock.
+func (jl *JobLock) Lost() <-chan struct{} {
+	return jl.lost
+}
+
+// Token returns the fencing token issued when the lock was acquired. Tokens for
+// a job strictly increase with every acquisition, so a higher token always
+// belongs to a more recent owner.
+func (jl *JobLock) Token() int64 {
+	return jl.token
+}
+
 // LockManager manages distributed locks for job execution
 type LockManager struct {
 	redis           *redis.Client
@@ -56,12 +91,16 @@ type LockManager struct {
 	heartbeatRate   time.Duration
 	idempotencyTTL  time.Duration
 	activeLocks     map[string]*JobLock
+	onRenewFailure  func(jobID string, err error)
 	mu              sync.RWMutex
 }
 
 // LockManagerConfig holds configuration for the lock manager
 type LockManagerConfig struct {
-	LockTTL        time.Duration
+	LockTTL time.Duration
+	// HeartbeatRate is how often held locks are renewed. It is capped at a
+	// third of LockTTL, so a lock survives a couple of missed renewals; zero
+	// uses that cap.
 	HeartbeatRate  time.Duration
 	IdempotencyTTL time.Duration
 }
@@ -81,29 +120,42 @@ func NewLockManager(redisClient *redis.Client, config LockManagerConfig) *LockMa
 		redis:          redisClient,
 		workerID:       uuid.New().String(),
 		lockTTL:        config.LockTTL,
-		heartbeatRate:  config.HeartbeatRate,
+		heartbeatRate:  heartbeatInterval(config.LockTTL, config.HeartbeatRate),
 		idempotencyTTL: config.IdempotencyTTL,
 		activeLocks:    make(map[string]*JobLock),
 	}
 }
 
+// heartbeatInterval returns rate capped at a third of lockTTL, or the cap
+// itself when rate is unset
+func heartbeatInterval(lockTTL, rate time.Duration) time.Duration {
+	limit := lockTTL / 3
+	if rate <= 0 || rate > limit {
+		return limit
+	}
+	return rate
+}
+
 // GetWorkerID returns this worker's unique ID
 func (lm *LockManager) GetWorkerID() string {
 	return lm.workerID
 }
 
-// AcquireLock attempts to acquire an exclusive lock for a job
+// AcquireLock attempts to acquire an exclusive lock for a job. The returned
+// lock carries a fencing token (see JobLock.Token and ValidateToken).
 func (lm *LockManager) AcquireLock(ctx context.Context, jobID string) (*JobLock, error) {
 	lockKey := keyPrefixJobLock + jobID
 
-	// Try to acquire lock with SETNX
+	// Try to acquire lock with SET NX, issuing a fencing token on success
 	lockValue := fmt.Sprintf("%s:%d", lm.workerID, time.Now().UnixNano())
-	acquired, err := lm.redis.SetNX(ctx, lockKey, lockValue, lm.lockTTL).Result()
+	token, err := acquireScript.Run(ctx, lm.redis,
+		[]string{lockKey, keyPrefixFenceToken + jobID},
+		lockValue, lm.lockTTL.Milliseconds(), fenceTokenTTL.Milliseconds()).Int64()
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire lock: %w", err)
 	}
 
-	if !acquired {
+	if token == 0 {
 		// Check if the existing lock is stale (owner crashed)
 		_, err := lm.redis.Get(ctx, lockKey).Result()
 		if err != nil && err != redis.Nil {
@@ -122,15 +174,17 @@ func (lm *LockManager) AcquireLock(ctx context.Context, jobID string) (*JobLock,
 		workerID:   lm.workerID,
 		lockKey:    lockKey,
 		ttl:        lm.lockTTL,
+		token:      token,
 		held:       true,
+		lost:       make(chan struct{}),
 		cancelFunc: cancel,
 	}
 
 	// Start heartbeat to maintain lock
-	go lock.heartbeat(lockCtx, lm.heartbeatRate)
+	go lm.heartbeat(lockCtx, lock)
 
 	// Track in running jobs
-	lm.trackRunningJob(ctx, jobID)
+	lm.trackRunningJob(ctx, jobID, token)
 
 	// Store in active locks
 	lm.mu.Lock()
@@ -150,6 +204,13 @@ func (lm *LockManager) ReleaseLock(ctx context.Context, lock *JobLock) error {
 	defer lock.mu.Unlock()
 
 	if !lock.held {
+		// Lost locks were taken over or expired; only forget them
+		lock.cancelFunc()
+		lm.mu.Lock()
+		if lm.activeLocks[lock.jobID] == lock {
+			delete(lm.activeLocks, lock.jobID)
+		}
+		lm.mu.Unlock()
 		return nil
 	}
 
@@ -184,9 +245,104 @@ func (lm *LockManager) ReleaseLock(ctx context.Context, lock *JobLock) error {
 	return nil
 }
 
-// heartbeat maintains the lock by extending TTL periodically
-func (jl *JobLock) heartbeat(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
+// RenewLock extends the TTL of a lock held by this worker and refreshes the
+// job's running timestamp. It returns ErrLockNotHeld without extending anything
+// if the lock has expired or been taken over by another worker.
+func (lm *LockManager) RenewLock(ctx context.Context, jobID string) error {
+	lm.mu.RLock()
+	lock, ok := lm.activeLocks[jobID]
+	lm.mu.RUnlock()
+	if !ok {
+		return ErrLockNotHeld
+	}
+	return lm.renewLock(ctx, lock)
+}
+
+// OnRenewFailure registers fn to be called whenever a heartbeat fails to renew
+// a lock, including when the lock turns out to be lost. It must be called
+// before any lock is acquired.
+func (lm *LockManager) OnRenewFailure(fn func(jobID string, err error)) {
+	lm.onRenewFailure = fn
+}
+
+// renewLock extends the lock TTL and refreshes the job's running timestamp
+func (lm *LockManager) renewLock(ctx context.Context, lock *JobLock) error {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	if err := lock.renew(ctx); err != nil {
+		return err
+	}
+
+	lm.trackRunningJob(ctx, lock.jobID, lock.token)
+	return nil
+}
+
+// ValidateToken reports whether token is still the newest fencing token issued
+// for a job. It returns ErrStaleToken if the lock has since been acquired by
+// another worker (or the token can no longer be verified).
+//
+// Handlers should call it immediately before performing an external side
+// effect and abort on error: a worker that stalled past its lock TTL (GC pause,
+// network partition) may resume after another worker took over the job.
+// Resources that can store state should go further and persist the highest
+// token they have seen for a job, rejecting any write carrying a lower one,
+// which also closes the window between validation and the write.
+func (lm *LockManager) ValidateToken(ctx context.Context, jobID string, token int64) error {
+	current, err := lm.redis.Get(ctx, keyPrefixFenceToken+jobID).Int64()
+	if err == redis.Nil {
+		return ErrStaleToken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to validate fencing token: %w", err)
+	}
+	if token != current {
+		return ErrStaleToken
+	}
+	return nil
+}
+
+// LockTTL returns the TTL applied to job locks
+func (lm *LockManager) LockTTL() time.Duration {
+	return lm.lockTTL
+}
+
+// renew extends the lock TTL only if this worker still owns it. Callers must hold jl.mu.
+func (jl *JobLock) renew(ctx context.Context) error {
+	if !jl.held {
+		return ErrLockNotHeld
+	}
+
+	// Use string.find with plain=true (4th arg) to avoid Lua pattern matching issues with UUID hyphens
+	script := `
+		local val = redis.call("get", KEYS[1])
+		if val and string.find(val, ARGV[1], 1, true) then
+			return redis.call("pexpire", KEYS[1], ARGV[2])
+		else
+			return 0
+		end
+	`
+	result, err := jl.redis.Eval(ctx, script, []string{jl.lockKey},
+		jl.workerID+":", jl.ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to renew lock: %w", err)
+	}
+	if result == 0 {
+		// Lost the lock
+		jl.held = false
+		close(jl.lost)
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// heartbeat maintains the lock by extending its TTL periodically until the
+// lock is released or lost. Transient failures are retried on the next tick,
+// as the lock outlives several missed heartbeats.
+func (lm *LockManager) heartbeat(ctx context.Context, jl *JobLock) {
+	if lm.heartbeatRate <= 0 {
+		return
+	}
+	ticker := time.NewTicker(lm.heartbeatRate)
 	defer ticker.Stop()
 
 	for {
@@ -194,39 +350,57 @@ func (jl *JobLock) heartbeat(ctx context.Context, interval time.Duration) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			jl.mu.Lock()
-			if !jl.held {
-				jl.mu.Unlock()
+			err := lm.renewLock(ctx, jl)
+			if err == nil {
+				continue
+			}
+			if ctx.Err() != nil {
 				return
 			}
-
-			// Extend lock TTL only if we still own it
-			// Use string.find with plain=true (4th arg) to avoid Lua pattern matching issues with UUID hyphens
-			script := `
-				local val = redis.call("get", KEYS[1])
-				if val and string.find(val, ARGV[1], 1, true) then
-					return redis.call("pexpire", KEYS[1], ARGV[2])
-				else
-					return 0
-				end
-			`
-			result, err := jl.redis.Eval(ctx, script, []string{jl.lockKey},
-				jl.workerID+":", jl.ttl.Milliseconds()).Int()
-			if err != nil || result == 0 {
-				// Lost the lock
-				jl.held = false
-				jl.mu.Unlock()
+			if lm.onRenewFailure != nil {
+				lm.onRenewFailure(jl.jobID, err)
+			}
+			if errors.Is(err, ErrLockNotHeld) {
 				return
 			}
-			jl.mu.Unlock()
 		}
 	}
 }
 
+// RunningJob describes a job entry in the running jobs set
+type RunningJob struct {
+	WorkerID    string
+	HeartbeatAt time.Time
+	Token       int64
+}
+
+// ParseRunningJob parses a running jobs entry as returned by GetRunningJobs,
+// in the form "<workerID>:<unix heartbeat>:<fencing token>". Entries written
+// before fencing tokens existed have no token and parse with Token 0.
+func ParseRunningJob(value string) (RunningJob, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return RunningJob{}, fmt.Errorf("invalid running job entry %q", value)
+	}
+
+	heartbeat, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return RunningJob{}, fmt.Errorf("invalid running job heartbeat %q: %w", value, err)
+	}
+
+	job := RunningJob{WorkerID: parts[0], HeartbeatAt: time.Unix(heartbeat, 0)}
+	if len(parts) == 3 {
+		if job.Token, err = strconv.ParseInt(parts[2], 10, 64); err != nil {
+			return RunningJob{}, fmt.Errorf("invalid running job token %q: %w", value, err)
+		}
+	}
+	return job, nil
+}
+
 // trackRunningJob adds a job to the running jobs set
-func (lm *LockManager) trackRunningJob(ctx context.Context, jobID string) {
+func (lm *LockManager) trackRunningJob(ctx context.Context, jobID string, token int64) {
 	// Add to global running jobs set with worker info
-	lm.redis.HSet(ctx, keyPrefixRunningJobs, jobID, fmt.Sprintf("%s:%d", lm.workerID, time.Now().Unix()))
+	lm.redis.HSet(ctx, keyPrefixRunningJobs, jobID, fmt.Sprintf("%s:%d:%d", lm.workerID, time.Now().Unix(), token))
 
 	// Add to this worker's job list
 	lm.redis.SAdd(ctx, keyPrefixWorkerJobs+lm.workerID, jobID)
@@ -267,7 +441,8 @@ func (lm *LockManager) MarkCompleted(ctx context.Context, uniqueKey string, jobI
 	return nil
 }
 
-// GetRunningJobs returns all currently running jobs
+// GetRunningJobs returns all currently running jobs, mapping job ID to
+// "<workerID>:<unix heartbeat>:<fencing token>" (see ParseRunningJob)
 func (lm *LockManager) GetRunningJobs(ctx context.Context) (map[string]string, error) {
 	return lm.redis.HGetAll(ctx, keyPrefixRunningJobs).Result()
 }
@@ -288,10 +463,11 @@ func (lm *LockManager) CleanupStaleJobs(ctx context.Context, staleDuration time.
 	now := time.Now().Unix()
 
 	for jobID, value := range runningJobs {
-		// Parse worker:timestamp
-		var workerID string
+		// Unparseable entries are treated as stale
 		var timestamp int64
-		fmt.Sscanf(value, "%s:%d", &workerID, &timestamp)
+		if running, err := ParseRunningJob(value); err == nil {
+			timestamp = running.HeartbeatAt.Unix()
+		}
 
 		// Check if job is stale
 		if now-timestamp > int64(staleDuration.Seconds()) {
@@ -306,6 +482,39 @@ func (lm *LockManager) CleanupStaleJobs(ctx context.Context, staleDuration time.
 	return cleaned, nil
 }
 
+// reclaimScript removes a running job entry only if it is unchanged, along with
+// the owner's lock if it still holds it, so exactly one caller reclaims a job
+var reclaimScript = redis.NewScript(`
+	if redis.call("hget", KEYS[1], ARGV[1]) ~= ARGV[2] then
+		return 0
+	end
+	redis.call("hdel", KEYS[1], ARGV[1])
+	redis.call("srem", KEYS[2], ARGV[1])
+	local val = redis.call("get", KEYS[3])
+	if val and string.find(val, ARGV[3], 1, true) == 1 then
+		redis.call("del", KEYS[3])
+	end
+	return 1
+`)
+
+// ReclaimRunningJob takes over a running job whose owner stopped heartbeating.
+// entry must be the job's current value from GetRunningJobs; if the owner has
+// since renewed or another worker reclaimed the job, nothing is changed and
+// false is returned.
+func (lm *LockManager) ReclaimRunningJob(ctx context.Context, jobID, entry string) (bool, error) {
+	running, err := ParseRunningJob(entry)
+	if err != nil {
+		return false, err
+	}
+
+	keys := []string{keyPrefixRunningJobs, keyPrefixWorkerJobs + running.WorkerID, keyPrefixJobLock + jobID}
+	reclaimed, err := reclaimScript.Run(ctx, lm.redis, keys, jobID, entry, running.WorkerID+":").Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to reclaim running job: %w", err)
+	}
+	return reclaimed == 1, nil
+}
+
 // ReleaseAllLocks releases all locks held by this worker (for graceful shutdown)
 func (lm *LockManager) ReleaseAllLocks(ctx context.Context) error {
 	lm.mu.Lock()
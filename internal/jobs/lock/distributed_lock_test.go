@@ -33,6 +33,26 @@ func TestDefaultLockManagerConfig(t *testing.T) {
 	}
 }
 
+func TestHeartbeatInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		lockTTL time.Duration
+		rate    time.Duration
+		want    time.Duration
+	}{
+		{"within the cap", 5 * time.Minute, 30 * time.Second, 30 * time.Second},
+		{"longer than the TTL allows", 30 * time.Second, time.Minute, 10 * time.Second},
+		{"unset", 90 * time.Second, 0, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := heartbeatInterval(tt.lockTTL, tt.rate); got != tt.want {
+				t.Errorf("heartbeatInterval(%v, %v) = %v, want %v", tt.lockTTL, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNewLockManager(t *testing.T) {
 	testutil.SkipIfNoRedis(t)
 	config := testutil.DefaultTestConfig()
@@ -368,6 +388,222 @@ func TestLockManager_HeartbeatExtendsLock(t *testing.T) {
 	lm.ReleaseLock(ctx, lock)
 }
 
+func TestLockManager_HeartbeatDetectsLoss(t *testing.T) {
+	lm, ctx := setupTestLockManager(t)
+
+	failures := make(chan error, 1)
+	lm.OnRenewFailure(func(jobID string, err error) {
+		failures <- err
+	})
+
+	lock, err := lm.AcquireLock(ctx, "heartbeat-lost-job")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	defer lm.ReleaseLock(ctx, lock)
+
+	// Simulate expiry and takeover by another worker
+	lm.redis.Set(ctx, keyPrefixJobLock+"heartbeat-lost-job", "other-worker:1", time.Minute)
+	defer lm.redis.Del(ctx, keyPrefixJobLock+"heartbeat-lost-job")
+
+	select {
+	case <-lock.Lost():
+	case <-time.After(3 * time.Second):
+		t.Fatal("heartbeat should detect the lost lock")
+	}
+	if err := <-failures; err != ErrLockNotHeld {
+		t.Errorf("renew failure = %v, want ErrLockNotHeld", err)
+	}
+}
+
+func TestLockManager_RenewLock(t *testing.T) {
+	lm, ctx := setupTestLockManager(t)
+
+	lock, err := lm.AcquireLock(ctx, "renew-job")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	defer lm.ReleaseLock(ctx, lock)
+
+	if err := lm.RenewLock(ctx, "renew-job"); err != nil {
+		t.Fatalf("RenewLock() error = %v", err)
+	}
+	ttl, _ := lm.redis.PTTL(ctx, keyPrefixJobLock+"renew-job").Result()
+	if ttl <= 4*time.Second {
+		t.Errorf("lock TTL after renewal = %v, want close to %v", ttl, lm.LockTTL())
+	}
+}
+
+func TestLockManager_RenewLock_NotHeld(t *testing.T) {
+	lm, ctx := setupTestLockManager(t)
+
+	if err := lm.RenewLock(ctx, "never-locked"); err != ErrLockNotHeld {
+		t.Errorf("RenewLock() error = %v, want ErrLockNotHeld", err)
+	}
+}
+
+func TestLockManager_RenewLock_OwnershipLost(t *testing.T) {
+	lm, ctx := setupTestLockManager(t)
+
+	lock, err := lm.AcquireLock(ctx, "stolen-job")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	defer lm.ReleaseLock(ctx, lock)
+
+	// Simulate expiry and takeover by another worker
+	lm.redis.Set(ctx, keyPrefixJobLock+"stolen-job", "other-worker:1", time.Minute)
+
+	if err := lm.RenewLock(ctx, "stolen-job"); err != ErrLockNotHeld {
+		t.Errorf("RenewLock() error = %v, want ErrLockNotHeld", err)
+	}
+	if lock.IsHeld() {
+		t.Error("lock should no longer be held")
+	}
+	select {
+	case <-lock.Lost():
+	default:
+		t.Error("Lost() should be closed")
+	}
+	val, _ := lm.redis.Get(ctx, keyPrefixJobLock+"stolen-job").Result()
+	if val != "other-worker:1" {
+		t.Errorf("other worker's lock was modified: %q", val)
+	}
+	lm.redis.Del(ctx, keyPrefixJobLock+"stolen-job")
+}
+
+func TestLockManager_FencingToken_Increases(t *testing.T) {
+	lm, ctx := setupTestLockManager(t)
+
+	first, err := lm.AcquireLock(ctx, "fence-job")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	lm.ReleaseLock(ctx, first)
+
+	second, err := lm.AcquireLock(ctx, "fence-job")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	defer lm.ReleaseLock(ctx, second)
+
+	if second.Token() <= first.Token() {
+		t.Errorf("second token = %d, want > %d", second.Token(), first.Token())
+	}
+
+	running, err := lm.GetRunningJobs(ctx)
+	if err != nil {
+		t.Fatalf("GetRunningJobs() error = %v", err)
+	}
+	entry, err := ParseRunningJob(running["fence-job"])
+	if err != nil {
+		t.Fatalf("ParseRunningJob() error = %v", err)
+	}
+	if entry.Token != second.Token() {
+		t.Errorf("running job token = %d, want %d", entry.Token, second.Token())
+	}
+}
+
+func TestLockManager_ValidateToken(t *testing.T) {
+	lm, ctx := setupTestLockManager(t)
+
+	stale, err := lm.AcquireLock(ctx, "validate-job")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	if err := lm.ValidateToken(ctx, "validate-job", stale.Token()); err != nil {
+		t.Errorf("ValidateToken() with current token error = %v", err)
+	}
+
+	// Simulate the lock expiring and another worker taking over the job
+	lm.redis.Del(ctx, keyPrefixJobLock+"validate-job")
+	other := NewLockManager(lm.redis, DefaultLockManagerConfig())
+	current, err := other.AcquireLock(ctx, "validate-job")
+	if err != nil {
+		t.Fatalf("AcquireLock() by other worker error = %v", err)
+	}
+	defer other.ReleaseLock(ctx, current)
+	defer lm.ReleaseLock(ctx, stale)
+
+	if err := lm.ValidateToken(ctx, "validate-job", stale.Token()); err != ErrStaleToken {
+		t.Errorf("ValidateToken() with stale token error = %v, want ErrStaleToken", err)
+	}
+	if err := lm.ValidateToken(ctx, "validate-job", current.Token()); err != nil {
+		t.Errorf("ValidateToken() with new token error = %v", err)
+	}
+}
+
+func TestLockManager_ValidateToken_Unknown(t *testing.T) {
+	lm, ctx := setupTestLockManager(t)
+
+	if err := lm.ValidateToken(ctx, testutil.GenerateTestID(), 1); err != ErrStaleToken {
+		t.Errorf("ValidateToken() for unknown job error = %v, want ErrStaleToken", err)
+	}
+}
+
+func TestLockManager_ReclaimRunningJob(t *testing.T) {
+	lm, ctx := setupTestLockManager(t)
+
+	lock, err := lm.AcquireLock(ctx, "reclaim-job")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+	defer lm.ReleaseLock(ctx, lock)
+
+	running, _ := lm.GetRunningJobs(ctx)
+	entry := running["reclaim-job"]
+
+	// A changed entry means the owner heartbeated since it was read
+	if reclaimed, err := lm.ReclaimRunningJob(ctx, "reclaim-job", entry+"0"); err != nil || reclaimed {
+		t.Errorf("ReclaimRunningJob() with outdated entry = %v, %v, want false", reclaimed, err)
+	}
+
+	reclaimed, err := lm.ReclaimRunningJob(ctx, "reclaim-job", entry)
+	if err != nil || !reclaimed {
+		t.Fatalf("ReclaimRunningJob() = %v, %v, want true", reclaimed, err)
+	}
+	if exists, _ := lm.redis.Exists(ctx, keyPrefixJobLock+"reclaim-job").Result(); exists != 0 {
+		t.Error("owner's lock should be deleted")
+	}
+	running, _ = lm.GetRunningJobs(ctx)
+	if _, ok := running["reclaim-job"]; ok {
+		t.Error("reclaimed job should no longer be running")
+	}
+
+	// Only one caller reclaims a job
+	if reclaimed, _ := lm.ReclaimRunningJob(ctx, "reclaim-job", entry); reclaimed {
+		t.Error("ReclaimRunningJob() should not reclaim a job twice")
+	}
+}
+
+func TestParseRunningJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    RunningJob
+		wantErr bool
+	}{
+		{"with token", "worker-1:1700000000:42", RunningJob{WorkerID: "worker-1", HeartbeatAt: time.Unix(1700000000, 0), Token: 42}, false},
+		{"legacy entry", "worker-1:1700000000", RunningJob{WorkerID: "worker-1", HeartbeatAt: time.Unix(1700000000, 0)}, false},
+		{"missing heartbeat", "worker-1", RunningJob{}, true},
+		{"bad heartbeat", "worker-1:soon:1", RunningJob{}, true},
+		{"bad token", "worker-1:1700000000:x", RunningJob{}, true},
+		{"empty worker", ":1700000000:1", RunningJob{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRunningJob(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRunningJob(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !got.HeartbeatAt.Equal(tt.want.HeartbeatAt) || got.WorkerID != tt.want.WorkerID || got.Token != tt.want.Token {
+				t.Errorf("ParseRunningJob(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
 // Error cases
 func TestErrors(t *testing.T) {
 	t.Run("ErrLockNotAcquired", func(t *testing.T) {
@@ -387,6 +623,12 @@ func TestErrors(t *testing.T) {
 			t.Errorf("ErrJobAlreadyDone = %v", ErrJobAlreadyDone)
 		}
 	})
+
+	t.Run("ErrStaleToken", func(t *testing.T) {
+		if ErrStaleToken.Error() != "stale fencing token" {
+			t.Errorf("ErrStaleToken = %v", ErrStaleToken)
+		}
+	})
 }
 
 // Benchmarks
@@ -2,46 +2,174 @@ package jobs
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
+// OtherJobType is the job_type label of jobs whose type has no registered
+// handler. Only registered types get their own label, which keeps the label
+// set bounded whatever types callers enqueue.
+const OtherJobType = "other"
+
+// processingRateWindow is how far back ProcessingRate looks, in seconds
+const processingRateWindow = 60
+
+// durationBuckets are the upper bounds, in seconds, of the job duration
+// histogram
+var durationBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// JobTypeStats are the counters and handler durations of one job type
+type JobTypeStats struct {
+	Enqueued  int64
+	Succeeded int64
+	Failed    int64
+	Retried   int64
+	Dead      int64
+
+	// Handler run times: per durationBuckets bucket (not cumulative), the
+	// count past the last bound, and their total
+	DurationBuckets []int64
+	DurationCount   int64
+	DurationSum     time.Duration
+}
+
+// observe adds a handler run time to the histogram
+func (s *JobTypeStats) observe(d time.Duration) {
+	if s.DurationBuckets == nil {
+		s.DurationBuckets = make([]int64, len(durationBuckets)+1)
+	}
+	i, _ := slices.BinarySearch(durationBuckets, d.Seconds())
+	s.DurationBuckets[i]++
+	s.DurationCount++
+	s.DurationSum += d
+}
+
+// rateWindow counts events per second over the last processingRateWindow
+// seconds
+type rateWindow struct {
+	counts  [processingRateWindow]int64
+	seconds [processingRateWindow]int64 // Unix second each count belongs to
+	mu      sync.Mutex
+}
+
+// add counts an event at now
+func (r *rateWindow) add(now time.Time) {
+	sec := now.Unix()
+	i := sec % processingRateWindow
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seconds[i] != sec {
+		r.seconds[i] = sec
+		r.counts[i] = 0
+	}
+	r.counts[i]++
+}
+
+// rate returns the events per second over the window ending at now
+func (r *rateWindow) rate(now time.Time) float64 {
+	sec := now.Unix()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total int64
+	for i, s := range r.seconds {
+		if sec-s < processingRateWindow {
+			total += r.counts[i]
+		}
+	}
+	return float64(total) / processingRateWindow
+}
+
 // Metrics collects job system metrics for Prometheus
 type Metrics struct {
 	// Counters
-	JobsEnqueued   atomic.Int64
-	JobsCompleted  atomic.Int64
-	JobsFailed     atomic.Int64
-	JobsRetried    atomic.Int64
-	JobsDead       atomic.Int64
+	JobsEnqueued        atomic.Int64
+	JobsCompleted       atomic.Int64
+	JobsFailed          atomic.Int64
+	JobsRetried         atomic.Int64
+	JobsDead            atomic.Int64
+	LockRenewalFailures atomic.Int64
+	StuckJobsDetected   atomic.Int64
+	LeadershipAcquired  atomic.Int64
+	LeadershipLost      atomic.Int64
 
 	// Gauges
-	JobsPending    atomic.Int64
-	JobsRunning    atomic.Int64
-	WorkersActive  atomic.Int64
+	JobsPending     atomic.Int64
+	JobsRunning     atomic.Int64
+	JobsStuck       atomic.Int64
+	WorkersActive   atomic.Int64
+	SchedulerLeader atomic.Int64 // 1 while this instance leads the scheduler
 
 	// Histograms (simplified - in production use prometheus client)
 	JobDurations []time.Duration
 	durationMu   sync.RWMutex
+
+	// Jobs finished, successfully or not, over the last minute
+	processed rateWindow
+
+	// Per-type gauges
+	throttledByType map[string]int64
+	throttledMu     sync.RWMutex
+
+	// Per-type counters
+	timedOutByType map[string]int64
+	timedOutMu     sync.RWMutex
+
+	// Per-type outcomes, labelled only for registered types
+	registeredTypes map[string]bool
+	byType          map[string]*JobTypeStats
+	typesMu         sync.RWMutex
 }
 
 // NewMetrics creates a new Metrics instance
 func NewMetrics() *Metrics {
 	return &Metrics{
-		JobDurations: make([]time.Duration, 0),
+		JobDurations:    make([]time.Duration, 0),
+		throttledByType: make(map[string]int64),
+		timedOutByType:  make(map[string]int64),
+		registeredTypes: make(map[string]bool),
+		byType:          make(map[string]*JobTypeStats),
 	}
 }
 
 // Global metrics instance
 var GlobalMetrics = NewMetrics()
 
+// RegisterJobType gives a job type its own job_type label. Worker pools
+// register the type of every handler they run.
+func (m *Metrics) RegisterJobType(jobType string) {
+	m.typesMu.Lock()
+	defer m.typesMu.Unlock()
+	m.registeredTypes[jobType] = true
+}
+
+// updateType applies fn to the stats of a job type's label
+func (m *Metrics) updateType(jobType string, fn func(*JobTypeStats)) {
+	m.typesMu.Lock()
+	defer m.typesMu.Unlock()
+
+	if !m.registeredTypes[jobType] {
+		jobType = OtherJobType
+	}
+	stats, ok := m.byType[jobType]
+	if !ok {
+		stats = &JobTypeStats{}
+		m.byType[jobType] = stats
+	}
+	fn(stats)
+}
+
 // RecordJobEnqueued records a job being enqueued
-func (m *Metrics) RecordJobEnqueued(priority Priority) {
+func (m *Metrics) RecordJobEnqueued(jobType string, priority Priority) {
 	m.JobsEnqueued.Add(1)
 	m.JobsPending.Add(1)
+	m.updateType(jobType, func(s *JobTypeStats) { s.Enqueued++ })
 }
 
 // RecordJobStarted records a job starting execution
@@ -52,28 +180,125 @@ func (m *Metrics) RecordJobStarted() {
 }
 
 // RecordJobCompleted records a job completing successfully
-func (m *Metrics) RecordJobCompleted(duration time.Duration) {
+func (m *Metrics) RecordJobCompleted(jobType string, duration time.Duration) {
 	m.JobsCompleted.Add(1)
 	m.JobsRunning.Add(-1)
 	m.WorkersActive.Add(-1)
+	m.processed.add(time.Now())
 	m.durationMu.Lock()
 	m.JobDurations = append(m.JobDurations, duration)
 	m.durationMu.Unlock()
+	m.updateType(jobType, func(s *JobTypeStats) {
+		s.Succeeded++
+		s.observe(duration)
+	})
 }
 
-// RecordJobFailed records a job failure
-func (m *Metrics) RecordJobFailed(willRetry bool) {
+// RecordJobFailed records a job failure. Duration is how long the handler
+// ran, zero if it never did.
+func (m *Metrics) RecordJobFailed(jobType string, willRetry bool, duration time.Duration) {
 	m.JobsFailed.Add(1)
 	m.JobsRunning.Add(-1)
 	m.WorkersActive.Add(-1)
+	m.processed.add(time.Now())
 	if willRetry {
 		m.JobsRetried.Add(1)
 	}
+	m.updateType(jobType, func(s *JobTypeStats) {
+		s.Failed++
+		if willRetry {
+			s.Retried++
+		}
+		if duration > 0 {
+			s.observe(duration)
+		}
+	})
 }
 
 // RecordJobDead records a job moved to DLQ
-func (m *Metrics) RecordJobDead() {
+func (m *Metrics) RecordJobDead(jobType string) {
 	m.JobsDead.Add(1)
+	m.updateType(jobType, func(s *JobTypeStats) { s.Dead++ })
+}
+
+// RecordJobThrottled adjusts the number of jobs of a type held back by a concurrency limit
+func (m *Metrics) RecordJobThrottled(jobType string, delta int64) {
+	m.throttledMu.Lock()
+	defer m.throttledMu.Unlock()
+	m.throttledByType[jobType] += delta
+}
+
+// RecordJobTimeout records a job of a type failing because its handler ran out of time
+func (m *Metrics) RecordJobTimeout(jobType string) {
+	m.timedOutMu.Lock()
+	defer m.timedOutMu.Unlock()
+	m.timedOutByType[jobType]++
+}
+
+// RecordLockRenewalFailure records a failed attempt to extend a running job's lock
+func (m *Metrics) RecordLockRenewalFailure() {
+	m.LockRenewalFailures.Add(1)
+}
+
+// RecordStuckJobs records newly detected stuck jobs and the current number of stuck jobs
+func (m *Metrics) RecordStuckJobs(detected, current int) {
+	m.StuckJobsDetected.Add(int64(detected))
+	m.JobsStuck.Store(int64(current))
+}
+
+// RecordLeadershipChange records this instance gaining or losing scheduler leadership
+func (m *Metrics) RecordLeadershipChange(isLeader bool) {
+	if isLeader {
+		m.LeadershipAcquired.Add(1)
+		m.SchedulerLeader.Store(1)
+		return
+	}
+	m.LeadershipLost.Add(1)
+	m.SchedulerLeader.Store(0)
+}
+
+// ProcessingRate returns the jobs this instance's workers finished per second,
+// successfully or not, averaged over the last minute
+func (m *Metrics) ProcessingRate() float64 {
+	return m.processed.rate(time.Now())
+}
+
+// JobsThrottled returns the current throttled-job gauge per job type
+func (m *Metrics) JobsThrottled() map[string]int64 {
+	m.throttledMu.RLock()
+	defer m.throttledMu.RUnlock()
+
+	result := make(map[string]int64, len(m.throttledByType))
+	for k, v := range m.throttledByType {
+		result[k] = v
+	}
+	return result
+}
+
+// JobsTimedOut returns the number of handler timeouts per job type
+func (m *Metrics) JobsTimedOut() map[string]int64 {
+	m.timedOutMu.RLock()
+	defer m.timedOutMu.RUnlock()
+
+	result := make(map[string]int64, len(m.timedOutByType))
+	for k, v := range m.timedOutByType {
+		result[k] = v
+	}
+	return result
+}
+
+// JobTypes returns the stats of each job_type label
+func (m *Metrics) JobTypes() map[string]JobTypeStats {
+	m.typesMu.RLock()
+	defer m.typesMu.RUnlock()
+
+	result := make(map[string]JobTypeStats, len(m.byType))
+	for k, v := range m.byType {
+		stats := *v
+		stats.DurationBuckets = slices.Clone(v.DurationBuckets)
+		result[k] = stats
+	}
+	return result
 }
 
 // PrometheusHandler returns an HTTP handler for Prometheus metrics
@@ -87,9 +312,32 @@ func (m *Metrics) PrometheusHandler() http.HandlerFunc {
 		writeMetric(w, "arcana_jobs_failed_total", "counter", "Total jobs failed", m.JobsFailed.Load())
 		writeMetric(w, "arcana_jobs_retried_total", "counter", "Total jobs retried", m.JobsRetried.Load())
 		writeMetric(w, "arcana_jobs_dead_total", "counter", "Total jobs moved to DLQ", m.JobsDead.Load())
+		writeMetric(w, "arcana_jobs_lock_renewal_failures_total", "counter", "Total failed job lock renewals", m.LockRenewalFailures.Load())
+		writeMetric(w, "arcana_jobs_stuck_detected_total", "counter", "Total running jobs detected as stuck", m.StuckJobsDetected.Load())
+		writeMetric(w, "arcana_scheduler_leadership_acquired_total", "counter", "Total times this instance became scheduler leader", m.LeadershipAcquired.Load())
+		writeMetric(w, "arcana_scheduler_leadership_lost_total", "counter", "Total times this instance stopped being scheduler leader", m.LeadershipLost.Load())
 		writeMetric(w, "arcana_jobs_pending", "gauge", "Current pending jobs", m.JobsPending.Load())
 		writeMetric(w, "arcana_jobs_running", "gauge", "Current running jobs", m.JobsRunning.Load())
+		writeMetric(w, "arcana_jobs_stuck", "gauge", "Current stuck jobs", m.JobsStuck.Load())
 		writeMetric(w, "arcana_workers_active", "gauge", "Active worker count", m.WorkersActive.Load())
+		writeMetric(w, "arcana_scheduler_is_leader", "gauge", "Whether this instance is the scheduler leader", m.SchedulerLeader.Load())
+
+		if throttled := m.JobsThrottled(); len(throttled) > 0 {
+			fmt.Fprintf(w, "# HELP arcana_jobs_throttled Jobs held back by per-type concurrency limits\n# TYPE arcana_jobs_throttled gauge\n")
+			for jobType, count := range throttled {
+				fmt.Fprintf(w, "arcana_jobs_throttled{type=%q} %d\n", jobType, count)
+			}
+		}
+		if timedOut := m.JobsTimedOut(); len(timedOut) > 0 {
+			fmt.Fprintf(w, "# HELP arcana_jobs_timed_out_total Jobs failed by their handler timeout\n# TYPE arcana_jobs_timed_out_total counter\n")
+			for jobType, count := range timedOut {
+				fmt.Fprintf(w, "arcana_jobs_timed_out_total{type=%q} %d\n", jobType, count)
+			}
+		}
+
+		writeMetricFloat(w, "arcana_jobs_processing_rate", "gauge", "Jobs finished per second over the last minute", m.ProcessingRate())
+
+		writeJobTypeMetrics(w, m.JobTypes())
 
 		// Calculate average duration
 		m.durationMu.RLock()
@@ -108,6 +356,48 @@ func (m *Metrics) PrometheusHandler() http.HandlerFunc {
 	}
 }
 
+// writeJobTypeMetrics writes the per-type counters and duration histogram
+func writeJobTypeMetrics(w http.ResponseWriter, byType map[string]JobTypeStats) {
+	if len(byType) == 0 {
+		return
+	}
+	types := slices.Sorted(maps.Keys(byType))
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(JobTypeStats) int64
+	}{
+		{"arcana_job_type_enqueued_total", "Jobs enqueued by type", func(s JobTypeStats) int64 { return s.Enqueued }},
+		{"arcana_job_type_succeeded_total", "Jobs completed by type", func(s JobTypeStats) int64 { return s.Succeeded }},
+		{"arcana_job_type_failed_total", "Job failures by type", func(s JobTypeStats) int64 { return s.Failed }},
+		{"arcana_job_type_retried_total", "Job failures retried by type", func(s JobTypeStats) int64 { return s.Retried }},
+		{"arcana_job_type_dead_total", "Jobs moved to DLQ by type", func(s JobTypeStats) int64 { return s.Dead }},
+	}
+	for _, c := range counters {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+		for _, jobType := range types {
+			fmt.Fprintf(w, "%s{job_type=%q} %d\n", c.name, jobType, c.value(byType[jobType]))
+		}
+	}
+
+	const histogram = "arcana_job_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Job handler run time by type\n# TYPE %s histogram\n", histogram, histogram)
+	for _, jobType := range types {
+		stats := byType[jobType]
+		var cumulative int64
+		for i, bound := range durationBuckets {
+			if stats.DurationBuckets != nil {
+				cumulative += stats.DurationBuckets[i]
+			}
+			fmt.Fprintf(w, "%s_bucket{job_type=%q,le=\"%s\"} %d\n", histogram, jobType, strconv.FormatFloat(bound, 'f', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{job_type=%q,le=\"+Inf\"} %d\n", histogram, jobType, stats.DurationCount)
+		fmt.Fprintf(w, "%s_sum{job_type=%q} %s\n", histogram, jobType, strconv.FormatFloat(stats.DurationSum.Seconds(), 'f', -1, 64))
+		fmt.Fprintf(w, "%s_count{job_type=%q} %d\n", histogram, jobType, stats.DurationCount)
+	}
+}
+
 func writeMetric(w http.ResponseWriter, name, metricType, help string, value int64) {
 	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n",
 		name, help, name, metricType, name, strconv.FormatInt(value, 10))
@@ -20,11 +20,11 @@ func TestNewMetrics(t *testing.T) {
 func TestMetrics_RecordJobEnqueued(t *testing.T) {
 	m := NewMetrics()
 
-	m.RecordJobEnqueued(PriorityNormal)
+	m.RecordJobEnqueued("test", PriorityNormal)
 	assert.Equal(t, int64(1), m.JobsEnqueued.Load())
 	assert.Equal(t, int64(1), m.JobsPending.Load())
 
-	m.RecordJobEnqueued(PriorityHigh)
+	m.RecordJobEnqueued("test", PriorityHigh)
 	assert.Equal(t, int64(2), m.JobsEnqueued.Load())
 	assert.Equal(t, int64(2), m.JobsPending.Load())
 }
@@ -32,8 +32,8 @@ func TestMetrics_RecordJobEnqueued(t *testing.T) {
 // TestMetrics_RecordJobStarted moves from pending to running
 func TestMetrics_RecordJobStarted(t *testing.T) {
 	m := NewMetrics()
-	m.RecordJobEnqueued(PriorityNormal)
-	m.RecordJobEnqueued(PriorityNormal)
+	m.RecordJobEnqueued("test", PriorityNormal)
+	m.RecordJobEnqueued("test", PriorityNormal)
 
 	m.RecordJobStarted()
 	assert.Equal(t, int64(1), m.JobsPending.Load())
@@ -44,10 +44,10 @@ func TestMetrics_RecordJobStarted(t *testing.T) {
 // TestMetrics_RecordJobCompleted increments completed and records duration
 func TestMetrics_RecordJobCompleted(t *testing.T) {
 	m := NewMetrics()
-	m.RecordJobEnqueued(PriorityNormal)
+	m.RecordJobEnqueued("test", PriorityNormal)
 	m.RecordJobStarted()
 
-	m.RecordJobCompleted(150 * time.Millisecond)
+	m.RecordJobCompleted("test", 150*time.Millisecond)
 	assert.Equal(t, int64(1), m.JobsCompleted.Load())
 	assert.Equal(t, int64(0), m.JobsRunning.Load())
 	assert.Equal(t, int64(0), m.WorkersActive.Load())
@@ -64,9 +64,9 @@ func TestMetrics_RecordJobCompleted_Multiple(t *testing.T) {
 	durations := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
 
 	for _, d := range durations {
-		m.RecordJobEnqueued(PriorityNormal)
+		m.RecordJobEnqueued("test", PriorityNormal)
 		m.RecordJobStarted()
-		m.RecordJobCompleted(d)
+		m.RecordJobCompleted("test", d)
 	}
 
 	assert.Equal(t, int64(3), m.JobsCompleted.Load())
@@ -79,10 +79,10 @@ func TestMetrics_RecordJobCompleted_Multiple(t *testing.T) {
 // TestMetrics_RecordJobFailed_WithRetry increments failed and retried
 func TestMetrics_RecordJobFailed_WithRetry(t *testing.T) {
 	m := NewMetrics()
-	m.RecordJobEnqueued(PriorityNormal)
+	m.RecordJobEnqueued("test", PriorityNormal)
 	m.RecordJobStarted()
 
-	m.RecordJobFailed(true)
+	m.RecordJobFailed("test", true, 0)
 	assert.Equal(t, int64(1), m.JobsFailed.Load())
 	assert.Equal(t, int64(1), m.JobsRetried.Load())
 	assert.Equal(t, int64(0), m.JobsRunning.Load())
@@ -92,10 +92,10 @@ func TestMetrics_RecordJobFailed_WithRetry(t *testing.T) {
 // TestMetrics_RecordJobFailed_NoRetry increments failed but not retried
 func TestMetrics_RecordJobFailed_NoRetry(t *testing.T) {
 	m := NewMetrics()
-	m.RecordJobEnqueued(PriorityNormal)
+	m.RecordJobEnqueued("test", PriorityNormal)
 	m.RecordJobStarted()
 
-	m.RecordJobFailed(false)
+	m.RecordJobFailed("test", false, 0)
 	assert.Equal(t, int64(1), m.JobsFailed.Load())
 	assert.Equal(t, int64(0), m.JobsRetried.Load())
 }
@@ -104,21 +104,140 @@ func TestMetrics_RecordJobFailed_NoRetry(t *testing.T) {
 func TestMetrics_RecordJobDead(t *testing.T) {
 	m := NewMetrics()
 
-	m.RecordJobDead()
+	m.RecordJobDead("test")
 	assert.Equal(t, int64(1), m.JobsDead.Load())
 
-	m.RecordJobDead()
+	m.RecordJobDead("test")
 	assert.Equal(t, int64(2), m.JobsDead.Load())
 }
 
+// TestMetrics_RecordJobThrottled tracks a per-type gauge
+func TestMetrics_RecordJobThrottled(t *testing.T) {
+	m := NewMetrics()
+
+	m.RecordJobThrottled("report", 1)
+	m.RecordJobThrottled("report", 1)
+	m.RecordJobThrottled("report", -1)
+	assert.Equal(t, map[string]int64{"report": 1}, m.JobsThrottled())
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rr := httptest.NewRecorder()
+	m.PrometheusHandler()(rr, req)
+	assert.Contains(t, rr.Body.String(), `arcana_jobs_throttled{type="report"} 1`)
+}
+
+// TestMetrics_RecordJobTimeout counts timeouts per type
+func TestMetrics_RecordJobTimeout(t *testing.T) {
+	m := NewMetrics()
+
+	m.RecordJobTimeout("report")
+	m.RecordJobTimeout("report")
+	m.RecordJobTimeout("email")
+	assert.Equal(t, map[string]int64{"report": 2, "email": 1}, m.JobsTimedOut())
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rr := httptest.NewRecorder()
+	m.PrometheusHandler()(rr, req)
+	assert.Contains(t, rr.Body.String(), `arcana_jobs_timed_out_total{type="report"} 2`)
+}
+
+// TestMetrics_RecordLeadershipChange counts acquisitions and losses
+func TestMetrics_RecordLeadershipChange(t *testing.T) {
+	m := NewMetrics()
+
+	m.RecordLeadershipChange(true)
+	assert.Equal(t, int64(1), m.SchedulerLeader.Load())
+	m.RecordLeadershipChange(false)
+	m.RecordLeadershipChange(true)
+	assert.Equal(t, int64(2), m.LeadershipAcquired.Load())
+	assert.Equal(t, int64(1), m.LeadershipLost.Load())
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rr := httptest.NewRecorder()
+	m.PrometheusHandler()(rr, req)
+	body := rr.Body.String()
+	assert.Contains(t, body, "arcana_scheduler_leadership_acquired_total 2")
+	assert.Contains(t, body, "arcana_scheduler_leadership_lost_total 1")
+	assert.Contains(t, body, "arcana_scheduler_is_leader 1")
+}
+
+// TestRateWindow averages events over the last minute
+func TestRateWindow(t *testing.T) {
+	var r rateWindow
+	start := time.Unix(1_000_000, 0)
+
+	for i := range 30 {
+		r.add(start.Add(time.Duration(i) * time.Second))
+		r.add(start.Add(time.Duration(i) * time.Second))
+	}
+	assert.InDelta(t, 1.0, r.rate(start.Add(30*time.Second)), 0.001)
+
+	// Events age out of the window
+	assert.InDelta(t, 0.5, r.rate(start.Add(74*time.Second)), 0.001)
+	assert.Zero(t, r.rate(start.Add(5*time.Minute)))
+
+	// A slot reused a minute later drops its old count
+	r.add(start.Add(time.Minute))
+	assert.InDelta(t, 59.0/60, r.rate(start.Add(time.Minute)), 0.001)
+}
+
+// TestMetrics_ProcessingRate counts finished jobs, failed or not
+func TestMetrics_ProcessingRate(t *testing.T) {
+	m := NewMetrics()
+	assert.Zero(t, m.ProcessingRate())
+
+	m.RecordJobCompleted("test", time.Millisecond)
+	m.RecordJobFailed("test", true, time.Millisecond)
+	m.RecordJobFailed("test", false, 0)
+	assert.InDelta(t, 3.0/60, m.ProcessingRate(), 0.001)
+}
+
+// TestMetrics_JobTypes breaks outcomes down by registered type only
+func TestMetrics_JobTypes(t *testing.T) {
+	m := NewMetrics()
+	m.RegisterJobType("report")
+
+	m.RecordJobEnqueued("report", PriorityNormal)
+	m.RecordJobCompleted("report", 30*time.Millisecond)
+	m.RecordJobFailed("report", true, 2*time.Second)
+	m.RecordJobFailed("report", false, 0)
+	m.RecordJobDead("report")
+	m.RecordJobEnqueued("user-supplied-1", PriorityNormal)
+	m.RecordJobEnqueued("user-supplied-2", PriorityNormal)
+
+	byType := m.JobTypes()
+	assert.Len(t, byType, 2, "unregistered types should share one label")
+
+	report := byType["report"]
+	assert.Equal(t, int64(1), report.Enqueued)
+	assert.Equal(t, int64(1), report.Succeeded)
+	assert.Equal(t, int64(2), report.Failed)
+	assert.Equal(t, int64(1), report.Retried)
+	assert.Equal(t, int64(1), report.Dead)
+	assert.Equal(t, int64(2), report.DurationCount, "a handler that never ran has no duration")
+	assert.Equal(t, int64(2), byType[OtherJobType].Enqueued)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rr := httptest.NewRecorder()
+	m.PrometheusHandler()(rr, req)
+	body := rr.Body.String()
+	assert.Contains(t, body, `arcana_job_type_failed_total{job_type="report"} 2`)
+	assert.Contains(t, body, `arcana_job_type_enqueued_total{job_type="other"} 2`)
+	assert.Contains(t, body, `arcana_job_duration_seconds_bucket{job_type="report",le="0.05"} 1`)
+	assert.Contains(t, body, `arcana_job_duration_seconds_bucket{job_type="report",le="2.5"} 2`)
+	assert.Contains(t, body, `arcana_job_duration_seconds_bucket{job_type="report",le="+Inf"} 2`)
+	assert.Contains(t, body, `arcana_job_duration_seconds_count{job_type="report"} 2`)
+	assert.NotContains(t, body, "user-supplied")
+}
+
 // TestMetrics_PrometheusHandler returns valid Prometheus metrics
 func TestMetrics_PrometheusHandler(t *testing.T) {
 	m := NewMetrics()
-	m.RecordJobEnqueued(PriorityNormal)
-	m.RecordJobEnqueued(PriorityHigh)
+	m.RecordJobEnqueued("test", PriorityNormal)
+	m.RecordJobEnqueued("test", PriorityHigh)
 	m.RecordJobStarted()
-	m.RecordJobCompleted(200 * time.Millisecond)
-	m.RecordJobDead()
+	m.RecordJobCompleted("test", 200*time.Millisecond)
+	m.RecordJobDead("test")
 
 	handler := m.PrometheusHandler()
 	assert.NotNil(t, handler)
@@ -192,3 +311,29 @@ func TestMetrics_GetHealthCheck_NotLeader(t *testing.T) {
 func TestGlobalMetrics(t *testing.T) {
 	assert.NotNil(t, GlobalMetrics)
 }
+
+func TestMetrics_RecordLockRenewalFailure(t *testing.T) {
+	m := NewMetrics()
+	m.RecordLockRenewalFailure()
+	m.RecordLockRenewalFailure()
+
+	assert.Equal(t, int64(2), m.LockRenewalFailures.Load())
+
+	w := httptest.NewRecorder()
+	m.PrometheusHandler()(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, w.Body.String(), "arcana_jobs_lock_renewal_failures_total 2")
+}
+
+func TestMetrics_RecordStuckJobs(t *testing.T) {
+	m := NewMetrics()
+	m.RecordStuckJobs(2, 2)
+	m.RecordStuckJobs(1, 1)
+
+	assert.Equal(t, int64(3), m.StuckJobsDetected.Load())
+	assert.Equal(t, int64(1), m.JobsStuck.Load())
+
+	w := httptest.NewRecorder()
+	m.PrometheusHandler()(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, w.Body.String(), "arcana_jobs_stuck_detected_total 3")
+	assert.Contains(t, w.Body.String(), "arcana_jobs_stuck 1")
+}
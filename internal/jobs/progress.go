@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrProgressNotFound is returned when a job has not reported progress or it has expired
+var ErrProgressNotFound = errors.New("job progress not found")
+
+// DefaultProgressTTL is how long progress stays readable after a job finishes
+const DefaultProgressTTL = 5 * time.Minute
+
+// JobProgress is the latest progress reported by a running job
+type JobProgress struct {
+	Percent   int       `json:"percent"`
+	Message   string    `json:"message"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProgressStore persists job progress
+type ProgressStore interface {
+	// SetProgress records the latest progress of a job
+	SetProgress(ctx context.Context, jobID string, progress JobProgress) error
+	// GetProgress retrieves the latest progress of a job
+	GetProgress(ctx context.Context, jobID string) (*JobProgress, error)
+	// ExpireProgress schedules a job's progress for removal after ttl
+	ExpireProgress(ctx context.Context, jobID string, ttl time.Duration) error
+}
+
+// ProgressReporter lets a handler report how far along a job is
+type ProgressReporter interface {
+	// Set records progress; percent is clamped to [0, 100]. Reporting is best
+	// effort and never fails the job.
+	Set(percent int, message string)
+}
+
+const progressContextKey contextKey = "progress"
+
+// ContextWithProgress returns a context carrying a progress reporter
+func ContextWithProgress(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressContextKey, reporter)
+}
+
+// ProgressFromContext returns the job's progress reporter, or a no-op reporter
+// when progress tracking is disabled, so handlers can report unconditionally
+func ProgressFromContext(ctx context.Context) ProgressReporter {
+	if reporter, ok := ctx.Value(progressContextKey).(ProgressReporter); ok {
+		return reporter
+	}
+	return noopProgress{}
+}
+
+// NewProgressReporter creates a reporter that writes a job's progress to store
+func NewProgressReporter(ctx context.Context, store ProgressStore, jobID string) ProgressReporter {
+	return &storeProgress{ctx: ctx, store: store, jobID: jobID}
+}
+
+type storeProgress struct {
+	ctx   context.Context
+	store ProgressStore
+	jobID string
+}
+
+func (p *storeProgress) Set(percent int, message string) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	_ = p.store.SetProgress(p.ctx, p.jobID, JobProgress{
+		Percent:   percent,
+		Message:   message,
+		UpdatedAt: time.Now().UTC(),
+	})
+}
+
+type noopProgress struct{}
+
+func (noopProgress) Set(int, string) {}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressFromContext_NoopWhenDisabled(t *testing.T) {
+	reporter := ProgressFromContext(context.Background())
+	require.NotNil(t, reporter)
+	assert.NotPanics(t, func() { reporter.Set(50, "ignored") })
+}
+
+func TestNewProgressReporter_WritesToStore(t *testing.T) {
+	q := newDefaultMockQueue()
+	var saved []JobProgress
+	q.setProgressFunc = func(_ context.Context, jobID string, progress JobProgress) error {
+		assert.Equal(t, "job-1", jobID)
+		saved = append(saved, progress)
+		return nil
+	}
+
+	ctx := ContextWithProgress(context.Background(), NewProgressReporter(context.Background(), q, "job-1"))
+	before := time.Now().UTC()
+	ProgressFromContext(ctx).Set(150, "done")
+	ProgressFromContext(ctx).Set(-5, "rewound")
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, 100, saved[0].Percent)
+	assert.Equal(t, "done", saved[0].Message)
+	assert.False(t, saved[0].UpdatedAt.Before(before))
+	assert.Equal(t, 0, saved[1].Percent)
+}
@@ -0,0 +1,58 @@
+package queue
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// gzipMagic starts every gzip stream. A serialized job is a JSON object and
+// never starts with these bytes, so they mark a compressed entry and
+// compressed and uncompressed jobs can be read side by side.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// encodeJob serializes a job for storage, compressing it if compression is
+// enabled and the job reaches the size threshold
+func (q *RedisQueue) encodeJob(job *jobs.JobPayload) ([]byte, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize job: %w", err)
+	}
+	if !q.config.Compress || len(data) < q.config.CompressThreshold {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress job: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress job: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeJob deserializes a stored job, decompressing it if needed
+func decodeJob(data []byte) (*jobs.JobPayload, error) {
+	if bytes.HasPrefix(data, gzipMagic) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress job: %w", err)
+		}
+		defer zr.Close()
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, fmt.Errorf("failed to decompress job: %w", err)
+		}
+	}
+
+	var job jobs.JobPayload
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("failed to deserialize job: %w", err)
+	}
+	return &job, nil
+}
@@ -0,0 +1,101 @@
+package queue
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// largeJob returns a job with a representative large payload: a report
+// request listing a few hundred records
+func largeJob(t testing.TB) *jobs.JobPayload {
+	t.Helper()
+	records := make([]map[string]any, 300)
+	for i := range records {
+		records[i] = map[string]any{
+			"id":     i,
+			"email":  "user" + strings.Repeat("x", i%7) + "@example.com",
+			"status": "active",
+			"tags":   []string{"customer", "newsletter", "region-eu"},
+		}
+	}
+	job, err := jobs.NewJobPayload("report", map[string]any{"records": records}, jobs.WithTags("reports"))
+	if err != nil {
+		t.Fatalf("NewJobPayload() error = %v", err)
+	}
+	return job
+}
+
+func TestEncodeJob_Compression(t *testing.T) {
+	large := largeJob(t)
+	small, _ := jobs.NewJobPayload("ping", nil)
+
+	tests := []struct {
+		name           string
+		config         QueueConfig
+		job            *jobs.JobPayload
+		wantCompressed bool
+	}{
+		{"disabled", DefaultQueueConfig(), large, false},
+		{"above threshold", QueueConfig{Compress: true, CompressThreshold: 1024}, large, true},
+		{"below threshold", QueueConfig{Compress: true, CompressThreshold: 1024}, small, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewRedisQueueWithConfig(nil, tt.config)
+			data, err := q.encodeJob(tt.job)
+			if err != nil {
+				t.Fatalf("encodeJob() error = %v", err)
+			}
+			if got := bytes.HasPrefix(data, gzipMagic); got != tt.wantCompressed {
+				t.Errorf("compressed = %v, want %v", got, tt.wantCompressed)
+			}
+
+			// Entries decode the same whether or not they were compressed
+			decoded, err := decodeJob(data)
+			if err != nil {
+				t.Fatalf("decodeJob() error = %v", err)
+			}
+			if decoded.ID != tt.job.ID || !bytes.Equal(decoded.Payload, tt.job.Payload) {
+				t.Errorf("decodeJob() = %+v, want %+v", decoded, tt.job)
+			}
+		})
+	}
+}
+
+func TestDecodeJob_Corrupt(t *testing.T) {
+	if _, err := decodeJob(append(append([]byte{}, gzipMagic...), "garbage"...)); err == nil {
+		t.Error("decodeJob() with a corrupt gzip entry should fail")
+	}
+	if _, err := decodeJob([]byte("not json")); err == nil {
+		t.Error("decodeJob() with invalid JSON should fail")
+	}
+}
+
+// BenchmarkEncodeJob reports the stored size of a large job with and
+// without compression
+func BenchmarkEncodeJob(b *testing.B) {
+	job := largeJob(b)
+	configs := map[string]QueueConfig{
+		"uncompressed": DefaultQueueConfig(),
+		"gzip":         {Compress: true, CompressThreshold: 1024},
+	}
+
+	for name, config := range configs {
+		b.Run(name, func(b *testing.B) {
+			q := NewRedisQueueWithConfig(nil, config)
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := q.encodeJob(job)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(data)
+			}
+			b.ReportMetric(float64(size), "stored-bytes")
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+func TestDequeueOrder(t *testing.T) {
+	t.Run("strict", func(t *testing.T) {
+		for _, config := range []QueueConfig{{StrictPriority: true, PriorityWeights: DefaultPriorityWeights()}, {}} {
+			q := NewRedisQueueWithConfig(nil, config)
+			for i := 0; i < 20; i++ {
+				if first := q.dequeueOrder()[0]; first != jobs.PriorityCritical {
+					t.Fatalf("dequeueOrder()[0] = %v, want critical", first)
+				}
+			}
+		}
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		q := NewRedisQueueWithConfig(nil, DefaultQueueConfig())
+		firsts := make(map[jobs.Priority]int)
+		for i := 0; i < 15000; i++ {
+			order := q.dequeueOrder()
+			if len(order) != len(strictPriorityOrder) {
+				t.Fatalf("dequeueOrder() = %v, want every priority once", order)
+			}
+			firsts[order[0]]++
+		}
+
+		// 8:4:2:1 of 15000 draws is 8000:4000:2000:1000
+		for p, want := range map[jobs.Priority]int{
+			jobs.PriorityCritical: 8000,
+			jobs.PriorityHigh:     4000,
+			jobs.PriorityNormal:   2000,
+			jobs.PriorityLow:      1000,
+		} {
+			if got := firsts[p]; got < want*8/10 || got > want*12/10 {
+				t.Errorf("%v drawn first %d times, want about %d", p, got, want)
+			}
+		}
+	})
+
+	t.Run("zero weight", func(t *testing.T) {
+		q := NewRedisQueueWithConfig(nil, QueueConfig{PriorityWeights: map[jobs.Priority]int{jobs.PriorityLow: 1}})
+		for i := 0; i < 20; i++ {
+			if order := q.dequeueOrder(); order[0] != jobs.PriorityLow || order[1] != jobs.PriorityCritical {
+				t.Fatalf("dequeueOrder() = %v, want low then strict order", order)
+			}
+		}
+	})
+}
+
+// TestDequeueOrder_NoStarvation simulates a critical queue that never runs
+// dry and checks queued low priority jobs still drain
+func TestDequeueOrder_NoStarvation(t *testing.T) {
+	q := NewRedisQueueWithConfig(nil, DefaultQueueConfig())
+
+	low := 10
+	for i := 0; i < 600 && low > 0; i++ {
+		// The first non-empty queue in the order is dequeued; critical always has work
+		for _, p := range q.dequeueOrder() {
+			if p == jobs.PriorityCritical {
+				break
+			}
+			if p == jobs.PriorityLow && low > 0 {
+				low--
+				break
+			}
+		}
+	}
+
+	if low != 0 {
+		t.Errorf("%d low priority jobs still queued, want all drained", low)
+	}
+}
+
+func TestParsePriorityWeights(t *testing.T) {
+	weights, err := ParsePriorityWeights(map[string]int{"critical": 3, "Low": 1})
+	if err != nil {
+		t.Fatalf("ParsePriorityWeights() error = %v", err)
+	}
+	if weights[jobs.PriorityCritical] != 3 || weights[jobs.PriorityLow] != 1 || len(weights) != 2 {
+		t.Errorf("ParsePriorityWeights() = %v", weights)
+	}
+
+	if _, err := ParsePriorityWeights(map[string]int{"urgent": 1}); err == nil {
+		t.Error("ParsePriorityWeights() with an unknown priority should fail")
+	}
+	if _, err := ParsePriorityWeights(map[string]int{"low": -1}); err == nil {
+		t.Error("ParsePriorityWeights() with a negative weight should fail")
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,16 +24,96 @@ const (
 	keyPrefixUnique    = "arcana:jobs:unique:"
 	keyPrefixDLQ       = "arcana:jobs:dlq"
 	keyPrefixStats     = "arcana:jobs:stats"
+	keyPrefixResult    = "arcana:jobs:result:"
+	keyPrefixProgress  = "arcana:jobs:progress:"
+	keyPrefixJobType   = "arcana:jobs:type:"  // Job type by ID, read by the dequeue script
+	keyPrefixPaused    = "arcana:jobs:paused" // Set of paused job types
+	keyQueues          = "arcana:jobs:queues" // Set of named queues besides the default one
 )
 
+// pausedScanWindow bounds how many of the oldest jobs in a queue the dequeue
+// script looks at to find one whose type is not paused
+const pausedScanWindow = 500
+
+// QueueConfig holds queue configuration
+type QueueConfig struct {
+	// Compress gzips stored jobs whose serialized size is at least
+	// CompressThreshold bytes. Jobs are read back either way, so it can be
+	// switched on while uncompressed jobs are still queued.
+	Compress          bool
+	CompressThreshold int
+	// PriorityWeights sets how often each priority queue is tried first on
+	// dequeue: with weights 8:4:2:1 critical jobs get about eight slots for
+	// every low priority one, so no priority starves while a higher one is
+	// busy. Missing or zero weights never win a draw; with no weights at all
+	// dequeue falls back to strict priority.
+	PriorityWeights map[jobs.Priority]int
+	// StrictPriority always drains higher priorities first, ignoring
+	// PriorityWeights
+	StrictPriority bool
+}
+
+// DefaultQueueConfig returns default queue configuration
+func DefaultQueueConfig() QueueConfig {
+	return QueueConfig{
+		Compress:          false,
+		CompressThreshold: 1024,
+		PriorityWeights:   DefaultPriorityWeights(),
+	}
+}
+
+// DefaultPriorityWeights returns the default dequeue weights,
+// critical:high:normal:low = 8:4:2:1
+func DefaultPriorityWeights() map[jobs.Priority]int {
+	return map[jobs.Priority]int{
+		jobs.PriorityCritical: 8,
+		jobs.PriorityHigh:     4,
+		jobs.PriorityNormal:   2,
+		jobs.PriorityLow:      1,
+	}
+}
+
+// ParsePriorityWeights converts weights keyed by priority name ("low",
+// "normal", "high", "critical") into PriorityWeights
+func ParsePriorityWeights(weights map[string]int) (map[jobs.Priority]int, error) {
+	parsed := make(map[jobs.Priority]int, len(weights))
+	for name, weight := range weights {
+		priority, ok := priorityByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown priority %q", name)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("negative weight for priority %q", name)
+		}
+		parsed[priority] = weight
+	}
+	return parsed, nil
+}
+
+func priorityByName(name string) (jobs.Priority, bool) {
+	for _, p := range strictPriorityOrder {
+		if strings.EqualFold(p.String(), name) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
 // RedisQueue implements a Redis-backed job queue
 type RedisQueue struct {
 	client *redis.Client
+	config QueueConfig
+	next   atomic.Uint64 // Rotates which named queue DequeueFrom tries first
 }
 
 // NewRedisQueue creates a new Redis queue
 func NewRedisQueue(client *redis.Client) *RedisQueue {
-	return &RedisQueue{client: client}
+	return NewRedisQueueWithConfig(client, DefaultQueueConfig())
+}
+
+// NewRedisQueueWithConfig creates a new Redis queue with custom configuration
+func NewRedisQueueWithConfig(client *redis.Client, config QueueConfig) *RedisQueue {
+	return &RedisQueue{client: client, config: config}
 }
 
 // checkDuplicate returns ErrDuplicateJob if the unique key already exists
@@ -44,45 +128,109 @@ func (q *RedisQueue) checkDuplicate(ctx context.Context, uniqueKey string) error
 	return nil
 }
 
+// claimUniqueKey holds a job's unique key for its uniqueness window. If
+// another job holds the key it returns a DuplicateJobError naming that job.
+func (q *RedisQueue) claimUniqueKey(ctx context.Context, job *jobs.JobPayload) error {
+	key := keyPrefixUnique + job.UniqueKey
+	claimed, err := q.client.SetNX(ctx, key, job.ID, job.UniqueFor).Result()
+	if err != nil {
+		return fmt.Errorf("failed to claim unique key: %w", err)
+	}
+	if claimed {
+		return nil
+	}
+
+	holder, err := q.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		// The window ended since the claim was attempted
+		return q.claimUniqueKey(ctx, job)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read unique key: %w", err)
+	}
+	return &jobs.DuplicateJobError{JobID: holder}
+}
+
 // scheduleOrEnqueue adds the job to the scheduled set or immediate priority queue
 func (q *RedisQueue) scheduleOrEnqueue(ctx context.Context, job *jobs.JobPayload) error {
 	if job.ScheduledAt != nil && job.ScheduledAt.After(time.Now()) {
 		score := float64(job.ScheduledAt.Unix())
 		return q.client.ZAdd(ctx, keyPrefixScheduled, redis.Z{Score: score, Member: job.ID}).Err()
 	}
-	return q.client.LPush(ctx, job.Priority.QueueName(), job.ID).Err()
+	return q.client.LPush(ctx, job.QueueKey(), job.ID).Err()
 }
 
-// setUniqueKey stores the unique key with the appropriate TTL
-func (q *RedisQueue) setUniqueKey(ctx context.Context, job *jobs.JobPayload) error {
+// registerQueue records a named queue so its depth shows in the stats
+func (q *RedisQueue) registerQueue(ctx context.Context, job *jobs.JobPayload) error {
+	if job.QueueName() == jobs.DefaultQueue {
+		return nil
+	}
+	return q.client.SAdd(ctx, keyQueues, job.Queue).Err()
+}
+
+// uniqueKeyTTL returns how long a job's unique key should be held
+func uniqueKeyTTL(job *jobs.JobPayload) time.Duration {
+	if job.UniqueFor > 0 {
+		return job.UniqueFor
+	}
 	ttl := 24 * time.Hour
 	if job.ScheduledAt != nil {
 		ttl = time.Until(*job.ScheduledAt) + 24*time.Hour
 	}
-	return q.client.Set(ctx, keyPrefixUnique+job.UniqueKey, job.ID, ttl).Err()
+	return ttl
 }
 
-// Enqueue adds a job to the queue
-func (q *RedisQueue) Enqueue(ctx context.Context, job *jobs.JobPayload) error {
-	if job.UniqueKey != "" {
-		if err := q.checkDuplicate(ctx, job.UniqueKey); err != nil {
-			return err
-		}
-	}
+// setUniqueKey stores the unique key with the appropriate TTL
+func (q *RedisQueue) setUniqueKey(ctx context.Context, job *jobs.JobPayload) error {
+	return q.client.Set(ctx, keyPrefixUnique+job.UniqueKey, job.ID, uniqueKeyTTL(job)).Err()
+}
 
-	data, err := json.Marshal(job)
+// storeAndQueue stores a job and adds it to its queue
+func (q *RedisQueue) storeAndQueue(ctx context.Context, job *jobs.JobPayload) error {
+	data, err := q.encodeJob(job)
 	if err != nil {
-		return fmt.Errorf("failed to serialize job: %w", err)
+		return err
 	}
 	if err := q.client.Set(ctx, keyPrefixJob+job.ID, data, 24*time.Hour).Err(); err != nil {
 		return fmt.Errorf("failed to store job: %w", err)
 	}
-
+	if err := q.client.Set(ctx, keyPrefixJobType+job.ID, job.Type, 24*time.Hour).Err(); err != nil {
+		return fmt.Errorf("failed to store job type: %w", err)
+	}
+	if err := q.registerQueue(ctx, job); err != nil {
+		return fmt.Errorf("failed to register queue: %w", err)
+	}
 	if err := q.scheduleOrEnqueue(ctx, job); err != nil {
 		return fmt.Errorf("failed to queue job: %w", err)
 	}
+	return nil
+}
 
-	if job.UniqueKey != "" {
+// Enqueue adds a job to the queue. A job with a uniqueness window that is
+// suppressed as a duplicate returns a DuplicateJobError naming the job
+// holding the key.
+func (q *RedisQueue) Enqueue(ctx context.Context, job *jobs.JobPayload) error {
+	// A uniqueness window is claimed up front; otherwise the key is only
+	// checked here and held once the job is queued
+	windowed := job.UniqueKey != "" && job.UniqueFor > 0
+	if windowed {
+		if err := q.claimUniqueKey(ctx, job); err != nil {
+			return err
+		}
+	} else if job.UniqueKey != "" {
+		if err := q.checkDuplicate(ctx, job.UniqueKey); err != nil {
+			return err
+		}
+	}
+
+	if err := q.storeAndQueue(ctx, job); err != nil {
+		if windowed {
+			q.client.Del(ctx, keyPrefixUnique+job.UniqueKey)
+		}
+		return err
+	}
+
+	if job.UniqueKey != "" && !windowed {
 		if err := q.setUniqueKey(ctx, job); err != nil {
 			return fmt.Errorf("failed to set unique key: %w", err)
 		}
@@ -90,26 +238,203 @@ func (q *RedisQueue) Enqueue(ctx context.Context, job *jobs.JobPayload) error {
 
 	q.client.HIncrBy(ctx, keyPrefixStats, "enqueued_total", 1)
 	q.client.HIncrBy(ctx, keyPrefixStats, "pending", 1)
+	jobs.GlobalMetrics.RecordJobEnqueued(job.Type, job.Priority)
 	return nil
 }
 
-// Dequeue retrieves the next job from the queue
+// EnqueueBatch adds multiple jobs using Redis pipelines. Unique keys are claimed
+// with SETNX first, so duplicates (including two jobs with the same key in one
+// batch) fail individually with ErrDuplicateJob without affecting other jobs.
+// The accepted jobs are queued in a single transaction; keys claimed for jobs
+// that end up not queued are released.
+func (q *RedisQueue) EnqueueBatch(ctx context.Context, batch []*jobs.JobPayload) ([]error, error) {
+	itemErrs := make([]error, len(batch))
+
+	// Claim unique keys
+	claims := make(map[int]*redis.BoolCmd)
+	if _, err := q.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, job := range batch {
+			if job.UniqueKey != "" {
+				claims[i] = pipe.SetNX(ctx, keyPrefixUnique+job.UniqueKey, job.ID, uniqueKeyTTL(job))
+			}
+		}
+		return nil
+	}); err != nil {
+		// Some claims may have gone through before the failure
+		q.releaseUniqueKeys(ctx, batch, claims, nil)
+		return nil, fmt.Errorf("failed to claim unique keys: %w", err)
+	}
+	for i, cmd := range claims {
+		if !cmd.Val() {
+			itemErrs[i] = jobs.ErrDuplicateJob
+		}
+	}
+
+	// Store and queue the accepted jobs
+	enqueued := int64(0)
+	if _, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, job := range batch {
+			if itemErrs[i] != nil {
+				continue
+			}
+			data, err := q.encodeJob(job)
+			if err != nil {
+				itemErrs[i] = err
+				continue
+			}
+			pipe.Set(ctx, keyPrefixJob+job.ID, data, 24*time.Hour)
+			pipe.Set(ctx, keyPrefixJobType+job.ID, job.Type, 24*time.Hour)
+			if job.QueueName() != jobs.DefaultQueue {
+				pipe.SAdd(ctx, keyQueues, job.Queue)
+			}
+			if job.ScheduledAt != nil && job.ScheduledAt.After(time.Now()) {
+				pipe.ZAdd(ctx, keyPrefixScheduled, redis.Z{Score: float64(job.ScheduledAt.Unix()), Member: job.ID})
+			} else {
+				pipe.LPush(ctx, job.QueueKey(), job.ID)
+			}
+			enqueued++
+		}
+		if enqueued > 0 {
+			pipe.HIncrBy(ctx, keyPrefixStats, "enqueued_total", enqueued)
+			pipe.HIncrBy(ctx, keyPrefixStats, "pending", enqueued)
+		}
+		return nil
+	}); err != nil {
+		q.releaseUniqueKeys(ctx, batch, claims, nil)
+		return nil, fmt.Errorf("failed to enqueue batch: %w", err)
+	}
+	q.releaseUniqueKeys(ctx, batch, claims, itemErrs)
+
+	for i, job := range batch {
+		if itemErrs[i] == nil {
+			jobs.GlobalMetrics.RecordJobEnqueued(job.Type, job.Priority)
+		}
+	}
+	return itemErrs, nil
+}
+
+// releaseUniqueKeys deletes the unique keys EnqueueBatch claimed for jobs that
+// were not queued. With itemErrs nil no job was queued; otherwise the jobs
+// with an error other than ErrDuplicateJob were not.
+func (q *RedisQueue) releaseUniqueKeys(ctx context.Context, batch []*jobs.JobPayload, claims map[int]*redis.BoolCmd, itemErrs []error) {
+	var keys []string
+	for i, cmd := range claims {
+		claimed := cmd.Err() == nil && cmd.Val()
+		if !claimed || (itemErrs != nil && itemErrs[i] == nil) {
+			continue
+		}
+		keys = append(keys, keyPrefixUnique+batch[i].UniqueKey)
+	}
+	if len(keys) > 0 {
+		q.client.Del(context.WithoutCancel(ctx), keys...)
+	}
+}
+
+// strictPriorityOrder lists the priorities from highest to lowest
+var strictPriorityOrder = []jobs.Priority{
+	jobs.PriorityCritical,
+	jobs.PriorityHigh,
+	jobs.PriorityNormal,
+	jobs.PriorityLow,
+}
+
+// dequeueOrder returns the order to try the priority queues in. Unless
+// strict priority is configured, one priority drawn by weight goes first and
+// the rest follow from highest to lowest, so an empty drawn queue does not
+// waste the slot.
+func (q *RedisQueue) dequeueOrder() []jobs.Priority {
+	if q.config.StrictPriority {
+		return strictPriorityOrder
+	}
+
+	total := 0
+	for _, p := range strictPriorityOrder {
+		total += max(q.config.PriorityWeights[p], 0)
+	}
+	if total == 0 {
+		return strictPriorityOrder
+	}
+
+	draw := rand.IntN(total)
+	first := strictPriorityOrder[0]
+	for _, p := range strictPriorityOrder {
+		draw -= max(q.config.PriorityWeights[p], 0)
+		if draw < 0 {
+			first = p
+			break
+		}
+	}
+
+	order := make([]jobs.Priority, 0, len(strictPriorityOrder))
+	order = append(order, first)
+	for _, p := range strictPriorityOrder {
+		if p != first {
+			order = append(order, p)
+		}
+	}
+	return order
+}
+
+// Dequeue retrieves the next job from the default queue. The given
+// priorities are tried in order; without any, the order is drawn by priority
+// weight.
 func (q *RedisQueue) Dequeue(ctx context.Context, priorities ...jobs.Priority) (*jobs.JobPayload, error) {
 	if len(priorities) == 0 {
-		priorities = []jobs.Priority{
-			jobs.PriorityCritical,
-			jobs.PriorityHigh,
-			jobs.PriorityNormal,
-			jobs.PriorityLow,
-		}
+		priorities = q.dequeueOrder()
 	}
 
-	// Try each priority queue in order
-	for _, priority := range priorities {
-		queueKey := priority.QueueName()
+	keys := make([]string, len(priorities))
+	for i, priority := range priorities {
+		keys[i] = priority.QueueName()
+	}
+	return q.dequeueKeys(ctx, keys)
+}
+
+// DequeueFrom retrieves the next job from any of the named queues. Each call
+// starts from the next queue in turn so a busy queue does not starve the
+// others; within a queue priorities are drawn by weight.
+func (q *RedisQueue) DequeueFrom(ctx context.Context, queues []string) (*jobs.JobPayload, error) {
+	if len(queues) == 0 {
+		return q.Dequeue(ctx)
+	}
 
-		// Use RPOP (non-blocking) to avoid 1s minimum timeout of BRPOP
-		jobID, err := q.client.RPop(ctx, queueKey).Result()
+	start := int(q.next.Add(1) % uint64(len(queues)))
+	keys := make([]string, 0, len(queues)*len(strictPriorityOrder))
+	for i := range queues {
+		name := queues[(start+i)%len(queues)]
+		for _, priority := range q.dequeueOrder() {
+			keys = append(keys, jobs.QueueKey(name, priority))
+		}
+	}
+	return q.dequeueKeys(ctx, keys)
+}
+
+// popUnpausedScript pops the oldest job of a queue whose type is not paused.
+// Jobs of paused types are left in place, so they keep their position and
+// still count as queued. Only the oldest pausedScanWindow jobs are looked at;
+// jobs whose type is unknown are never held back.
+var popUnpausedScript = redis.NewScript(`
+if redis.call("SCARD", KEYS[2]) == 0 then
+	return redis.call("RPOP", KEYS[1])
+end
+local ids = redis.call("LRANGE", KEYS[1], -tonumber(ARGV[2]), -1)
+for i = #ids, 1, -1 do
+	local jobType = redis.call("GET", ARGV[1] .. ids[i])
+	if not jobType or redis.call("SISMEMBER", KEYS[2], jobType) == 0 then
+		redis.call("LREM", KEYS[1], -1, ids[i])
+		return ids[i]
+	end
+end
+return false
+`)
+
+// dequeueKeys pops the first job found in the given lists, in order, skipping
+// jobs of paused types
+func (q *RedisQueue) dequeueKeys(ctx context.Context, keys []string) (*jobs.JobPayload, error) {
+	for _, queueKey := range keys {
+		// Non-blocking pop to avoid the 1s minimum timeout of BRPOP
+		jobID, err := popUnpausedScript.Run(ctx, q.client, []string{queueKey, keyPrefixPaused},
+			keyPrefixJobType, pausedScanWindow).Text()
 		if err == redis.Nil {
 			continue
 		}
@@ -150,22 +475,21 @@ func (q *RedisQueue) GetJob(ctx context.Context, jobID string) (*jobs.JobPayload
 		return nil, fmt.Errorf("failed to get job: %w", err)
 	}
 
-	var job jobs.JobPayload
-	if err := json.Unmarshal(data, &job); err != nil {
-		return nil, fmt.Errorf("failed to deserialize job: %w", err)
-	}
-
-	return &job, nil
+	return decodeJob(data)
 }
 
 // UpdateJob updates a job's data
 func (q *RedisQueue) UpdateJob(ctx context.Context, job *jobs.JobPayload) error {
-	data, err := json.Marshal(job)
+	data, err := q.encodeJob(job)
 	if err != nil {
-		return fmt.Errorf("failed to serialize job: %w", err)
+		return err
 	}
 
-	if err := q.client.Set(ctx, keyPrefixJob+job.ID, data, 24*time.Hour).Err(); err != nil {
+	if _, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, keyPrefixJob+job.ID, data, 24*time.Hour)
+		pipe.Set(ctx, keyPrefixJobType+job.ID, job.Type, 24*time.Hour)
+		return nil
+	}); err != nil {
 		return fmt.Errorf("failed to update job: %w", err)
 	}
 
@@ -187,8 +511,8 @@ func (q *RedisQueue) Complete(ctx context.Context, jobID string) error {
 		return err
 	}
 
-	// Clean up unique key
-	if job.UniqueKey != "" {
+	// Clean up unique key; a uniqueness window outlives the job
+	if job.UniqueKey != "" && job.UniqueFor == 0 {
 		q.client.Del(ctx, keyPrefixUnique+job.UniqueKey)
 	}
 
@@ -204,46 +528,50 @@ func (q *RedisQueue) Fail(ctx context.Context, jobID string, jobErr error) error
 		return err
 	}
 
-	job.LastError = jobErr.Error()
+	job.RecordFailure(jobErr)
 
 	// Check if we should retry
 	if job.Attempts < job.MaxRetries {
-		job.Status = jobs.JobStatusRetrying
-		delay := job.RetryPolicy.CalculateDelay(job.Attempts)
-		scheduledAt := time.Now().Add(delay)
-		job.ScheduledAt = &scheduledAt
+		err = q.scheduleRetry(ctx, job, job.RetryPolicy.CalculateDelay(job.Attempts))
+	} else {
+		err = q.moveToDLQ(ctx, job)
+	}
+	if err != nil {
+		return err
+	}
 
-		if err := q.UpdateJob(ctx, job); err != nil {
-			return err
-		}
+	q.client.HIncrBy(ctx, keyPrefixStats, "failed_total", 1)
 
-		// Add to scheduled queue
-		score := float64(scheduledAt.Unix())
-		if err := q.client.ZAdd(ctx, keyPrefixScheduled, redis.Z{
-			Score:  score,
-			Member: job.ID,
-		}).Err(); err != nil {
-			return fmt.Errorf("failed to schedule retry: %w", err)
-		}
+	return nil
+}
 
-		q.client.HIncrBy(ctx, keyPrefixStats, "retries_total", 1)
-	} else {
-		// Move to DLQ
-		job.Status = jobs.JobStatusDead
-		if err := q.UpdateJob(ctx, job); err != nil {
-			return err
-		}
+// ScheduleRetry marks a job as failed and schedules another attempt after delay
+func (q *RedisQueue) ScheduleRetry(ctx context.Context, jobID string, jobErr error, delay time.Duration) error {
+	job, err := q.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
 
-		if err := q.client.LPush(ctx, keyPrefixDLQ, job.ID).Err(); err != nil {
-			return fmt.Errorf("failed to move to DLQ: %w", err)
-		}
+	job.RecordFailure(jobErr)
+	if err := q.scheduleRetry(ctx, job, delay); err != nil {
+		return err
+	}
 
-		// Clean up unique key
-		if job.UniqueKey != "" {
-			q.client.Del(ctx, keyPrefixUnique+job.UniqueKey)
-		}
+	q.client.HIncrBy(ctx, keyPrefixStats, "failed_total", 1)
 
-		q.client.HIncrBy(ctx, keyPrefixStats, "dead_total", 1)
+	return nil
+}
+
+// MoveToDLQ marks a job as failed and moves it to the dead letter queue
+func (q *RedisQueue) MoveToDLQ(ctx context.Context, jobID string, jobErr error) error {
+	job, err := q.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	job.RecordFailure(jobErr)
+	if err := q.moveToDLQ(ctx, job); err != nil {
+		return err
 	}
 
 	q.client.HIncrBy(ctx, keyPrefixStats, "failed_total", 1)
@@ -251,6 +579,54 @@ func (q *RedisQueue) Fail(ctx context.Context, jobID string, jobErr error) error
 	return nil
 }
 
+// scheduleRetry puts a failed job on the scheduled set to run again after delay
+func (q *RedisQueue) scheduleRetry(ctx context.Context, job *jobs.JobPayload, delay time.Duration) error {
+	job.Status = jobs.JobStatusRetrying
+	scheduledAt := time.Now().Add(delay)
+	job.ScheduledAt = &scheduledAt
+
+	if err := q.UpdateJob(ctx, job); err != nil {
+		return err
+	}
+
+	// Add to scheduled queue
+	score := float64(scheduledAt.Unix())
+	if err := q.client.ZAdd(ctx, keyPrefixScheduled, redis.Z{
+		Score:  score,
+		Member: job.ID,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to schedule retry: %w", err)
+	}
+
+	q.client.HIncrBy(ctx, keyPrefixStats, "retries_total", 1)
+
+	return nil
+}
+
+// moveToDLQ marks a job dead and pushes it onto the dead letter queue
+func (q *RedisQueue) moveToDLQ(ctx context.Context, job *jobs.JobPayload) error {
+	failedAt := time.Now()
+	job.Status = jobs.JobStatusDead
+	job.FailedAt = &failedAt
+	job.DLQBackoff = 0
+	if err := q.UpdateJob(ctx, job); err != nil {
+		return err
+	}
+
+	if err := q.client.LPush(ctx, keyPrefixDLQ, job.ID).Err(); err != nil {
+		return fmt.Errorf("failed to move to DLQ: %w", err)
+	}
+
+	// Clean up unique key; a uniqueness window outlives the job
+	if job.UniqueKey != "" && job.UniqueFor == 0 {
+		q.client.Del(ctx, keyPrefixUnique+job.UniqueKey)
+	}
+
+	q.client.HIncrBy(ctx, keyPrefixStats, "dead_total", 1)
+
+	return nil
+}
+
 // ProcessScheduled moves scheduled jobs that are due to their queues
 func (q *RedisQueue) ProcessScheduled(ctx context.Context) (int, error) {
 	now := time.Now().Unix()
@@ -284,7 +660,7 @@ func (q *RedisQueue) ProcessScheduled(ctx context.Context) (int, error) {
 		}
 
 		// Add to priority queue
-		queueKey := job.Priority.QueueName()
+		queueKey := job.QueueKey()
 		if err := q.client.LPush(ctx, queueKey, job.ID).Err(); err != nil {
 			continue
 		}
@@ -344,10 +720,12 @@ func (q *RedisQueue) DeleteJob(ctx context.Context, jobID string) error {
 	}
 
 	// Remove from all possible locations
-	q.client.Del(ctx, keyPrefixJob+jobID)
-	q.client.LRem(ctx, job.Priority.QueueName(), 0, jobID)
+	q.client.Del(ctx, keyPrefixJob+jobID, keyPrefixJobType+jobID)
+	q.client.LRem(ctx, job.QueueKey(), 0, jobID)
 	q.client.ZRem(ctx, keyPrefixScheduled, jobID)
 	q.client.LRem(ctx, keyPrefixDLQ, 0, jobID)
+	q.client.Del(ctx, keyPrefixResult+jobID)
+	q.client.Del(ctx, keyPrefixProgress+jobID)
 
 	if job.UniqueKey != "" {
 		q.client.Del(ctx, keyPrefixUnique+job.UniqueKey)
@@ -356,11 +734,100 @@ func (q *RedisQueue) DeleteJob(ctx context.Context, jobID string) error {
 	return nil
 }
 
+// SaveResult stores the JSON-encoded result of a job; it expires after ttl
+func (q *RedisQueue) SaveResult(ctx context.Context, jobID string, result json.RawMessage, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = jobs.DefaultResultTTL
+	}
+	if err := q.client.Set(ctx, keyPrefixResult+jobID, []byte(result), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store job result: %w", err)
+	}
+	return nil
+}
+
+// GetResult retrieves a stored job result
+func (q *RedisQueue) GetResult(ctx context.Context, jobID string) (json.RawMessage, error) {
+	data, err := q.client.Get(ctx, keyPrefixResult+jobID).Bytes()
+	if err == redis.Nil {
+		return nil, jobs.ErrResultNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job result: %w", err)
+	}
+	return json.RawMessage(data), nil
+}
+
+// SetProgress records the latest progress of a job in a Redis hash
+func (q *RedisQueue) SetProgress(ctx context.Context, jobID string, progress jobs.JobProgress) error {
+	key := keyPrefixProgress + jobID
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"percent", progress.Percent,
+			"message", progress.Message,
+			"updated_at", progress.UpdatedAt.Format(time.RFC3339Nano),
+		)
+		// Safety net in case the job never finishes
+		pipe.Expire(ctx, key, 24*time.Hour)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store job progress: %w", err)
+	}
+	return nil
+}
+
+// GetProgress retrieves the latest progress of a job
+func (q *RedisQueue) GetProgress(ctx context.Context, jobID string) (*jobs.JobProgress, error) {
+	fields, err := q.client.HGetAll(ctx, keyPrefixProgress+jobID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job progress: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, jobs.ErrProgressNotFound
+	}
+
+	progress := &jobs.JobProgress{Message: fields["message"]}
+	progress.Percent, _ = strconv.Atoi(fields["percent"])
+	progress.UpdatedAt, _ = time.Parse(time.RFC3339Nano, fields["updated_at"])
+	return progress, nil
+}
+
+// ExpireProgress schedules a job's progress for removal after ttl
+func (q *RedisQueue) ExpireProgress(ctx context.Context, jobID string, ttl time.Duration) error {
+	return q.client.Expire(ctx, keyPrefixProgress+jobID, ttl).Err()
+}
+
 // RequeueJob adds a job back to the queue
 func (q *RedisQueue) RequeueJob(ctx context.Context, jobID string, queueKey string) error {
 	return q.client.LPush(ctx, queueKey, jobID).Err()
 }
 
+// PauseType stops jobs of a type from being processed, fleet-wide
+func (q *RedisQueue) PauseType(ctx context.Context, jobType string) error {
+	if err := q.client.SAdd(ctx, keyPrefixPaused, jobType).Err(); err != nil {
+		return fmt.Errorf("failed to pause job type: %w", err)
+	}
+	return nil
+}
+
+// ResumeType lifts a pause; jobs of the type held in their queues become
+// available to dequeue again
+func (q *RedisQueue) ResumeType(ctx context.Context, jobType string) error {
+	if err := q.client.SRem(ctx, keyPrefixPaused, jobType).Err(); err != nil {
+		return fmt.Errorf("failed to resume job type: %w", err)
+	}
+	return nil
+}
+
+// PausedTypes returns the job types currently paused
+func (q *RedisQueue) PausedTypes(ctx context.Context) ([]string, error) {
+	types, err := q.client.SMembers(ctx, keyPrefixPaused).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paused job types: %w", err)
+	}
+	return types, nil
+}
+
 // GetStats returns queue statistics
 func (q *RedisQueue) GetStats(ctx context.Context) (map[string]int64, error) {
 	stats, err := q.client.HGetAll(ctx, keyPrefixStats).Result()
@@ -375,10 +842,20 @@ func (q *RedisQueue) GetStats(ctx context.Context) (map[string]int64, error) {
 		result[k] = val
 	}
 
-	// Add queue sizes
-	for _, p := range []jobs.Priority{jobs.PriorityCritical, jobs.PriorityHigh, jobs.PriorityNormal, jobs.PriorityLow} {
-		size, _ := q.client.LLen(ctx, p.QueueName()).Result()
-		result["queue_"+p.String()] = size
+	// Add queue sizes, per priority across queues and per named queue
+	queues, _ := q.client.SMembers(ctx, keyQueues).Result()
+	queues = append([]string{jobs.DefaultQueue}, queues...)
+	for _, p := range strictPriorityOrder {
+		result["queue_"+p.String()] = 0
+	}
+	for _, name := range queues {
+		depth := int64(0)
+		for _, p := range strictPriorityOrder {
+			size, _ := q.client.LLen(ctx, jobs.QueueKey(name, p)).Result()
+			result["queue_"+p.String()] += size
+			depth += size
+		}
+		result[jobs.StatsQueueDepthPrefix+name] = depth
 	}
 
 	// Add scheduled and DLQ sizes
@@ -390,3 +867,23 @@ func (q *RedisQueue) GetStats(ctx context.Context) (map[string]int64, error) {
 
 	return result, nil
 }
+
+// DepthByPriority returns how many jobs wait in each priority of a named
+// queue, not counting scheduled jobs that are not due yet
+func (q *RedisQueue) DepthByPriority(ctx context.Context, queue string) (map[jobs.Priority]int64, error) {
+	cmds := make(map[jobs.Priority]*redis.IntCmd, len(strictPriorityOrder))
+	if _, err := q.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, p := range strictPriorityOrder {
+			cmds[p] = pipe.LLen(ctx, jobs.QueueKey(queue, p))
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get queue depth: %w", err)
+	}
+
+	depths := make(map[jobs.Priority]int64, len(cmds))
+	for p, cmd := range cmds {
+		depths[p] = cmd.Val()
+	}
+	return depths, nil
+}
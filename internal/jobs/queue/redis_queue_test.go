@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -120,6 +121,84 @@ func TestRedisQueue_Enqueue_UniqueKey(t *testing.T) {
 	}
 }
 
+func TestRedisQueue_Enqueue_UniqueFor(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	job1, _ := jobs.NewJobPayload("unique-job", nil, jobs.WithUniqueKey("window-123"), jobs.WithUniqueFor(time.Minute))
+	if err := q.Enqueue(ctx, job1); err != nil {
+		t.Fatalf("First Enqueue() error = %v", err)
+	}
+
+	// The window outlives the job
+	if err := q.Complete(ctx, job1.ID); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	job2, _ := jobs.NewJobPayload("unique-job", nil, jobs.WithUniqueKey("window-123"), jobs.WithUniqueFor(time.Minute))
+	err := q.Enqueue(ctx, job2)
+	var dup *jobs.DuplicateJobError
+	if !errors.As(err, &dup) || dup.JobID != job1.ID {
+		t.Errorf("Second Enqueue() error = %v, want DuplicateJobError held by %s", err, job1.ID)
+	}
+	if ttl := q.client.TTL(ctx, keyPrefixUnique+"window-123").Val(); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("unique key TTL = %v, want within 1m", ttl)
+	}
+}
+
+func TestRedisQueue_EnqueueBatch(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	job1, _ := jobs.NewJobPayload("batch-job", nil)
+	job2, _ := jobs.NewJobPayload("batch-job", nil, jobs.WithUniqueKey("batch-unique-1"))
+	job3, _ := jobs.NewJobPayload("batch-job", nil, jobs.WithUniqueKey("batch-unique-1"))
+	job4, _ := jobs.NewJobPayload("batch-job", nil, jobs.WithDelay(time.Hour))
+
+	errs, err := q.EnqueueBatch(ctx, []*jobs.JobPayload{job1, job2, job3, job4})
+	if err != nil {
+		t.Fatalf("EnqueueBatch() error = %v", err)
+	}
+	if len(errs) != 4 {
+		t.Fatalf("len(errs) = %d, want 4", len(errs))
+	}
+	if errs[0] != nil || errs[1] != nil || errs[3] != nil {
+		t.Errorf("unexpected item errors: %v", errs)
+	}
+	if errs[2] != jobs.ErrDuplicateJob {
+		t.Errorf("errs[2] = %v, want jobs.ErrDuplicateJob", errs[2])
+	}
+
+	for _, job := range []*jobs.JobPayload{job1, job2, job4} {
+		if _, err := q.GetJob(ctx, job.ID); err != nil {
+			t.Errorf("GetJob(%s) error = %v", job.ID, err)
+		}
+	}
+	if _, err := q.GetJob(ctx, job3.ID); err != jobs.ErrJobNotFound {
+		t.Errorf("duplicate job should not be stored, GetJob() error = %v", err)
+	}
+}
+
+func TestRedisQueue_EnqueueBatch_ReleasesUnqueuedKeys(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	// An invalid payload cannot be encoded
+	broken, _ := jobs.NewJobPayload("batch-job", nil, jobs.WithUniqueKey("batch-release-1"))
+	broken.Payload = json.RawMessage("{")
+
+	errs, err := q.EnqueueBatch(ctx, []*jobs.JobPayload{broken})
+	if err != nil {
+		t.Fatalf("EnqueueBatch() error = %v", err)
+	}
+	if errs[0] == nil {
+		t.Fatal("EnqueueBatch() should fail the job that cannot be encoded")
+	}
+
+	retry, _ := jobs.NewJobPayload("batch-job", nil, jobs.WithUniqueKey("batch-release-1"))
+	errs, err = q.EnqueueBatch(ctx, []*jobs.JobPayload{retry})
+	if err != nil || errs[0] != nil {
+		t.Errorf("retry with the released key: error = %v, item errors = %v", err, errs)
+	}
+}
+
 func TestRedisQueue_Dequeue(t *testing.T) {
 	q, ctx := setupTestQueue(t)
 
@@ -151,6 +230,7 @@ func TestRedisQueue_Dequeue(t *testing.T) {
 
 func TestRedisQueue_Dequeue_PriorityOrder(t *testing.T) {
 	q, ctx := setupTestQueue(t)
+	q = NewRedisQueueWithConfig(q.client, QueueConfig{StrictPriority: true})
 
 	// Enqueue jobs with different priorities
 	lowJob, _ := jobs.NewJobPayload("low-job", nil, jobs.WithPriority(jobs.PriorityLow))
@@ -163,7 +243,7 @@ func TestRedisQueue_Dequeue_PriorityOrder(t *testing.T) {
 	q.Enqueue(ctx, highJob)
 	q.Enqueue(ctx, criticalJob)
 
-	// Should dequeue in priority order
+	// Strict priority dequeues in priority order
 	expectedOrder := []string{criticalJob.ID, highJob.ID, normalJob.ID, lowJob.ID}
 	for i, expectedID := range expectedOrder {
 		dequeued, err := q.Dequeue(ctx)
@@ -176,6 +256,63 @@ func TestRedisQueue_Dequeue_PriorityOrder(t *testing.T) {
 	}
 }
 
+func TestRedisQueue_Dequeue_WeightedNoStarvation(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	const lowJobs = 10
+	for i := 0; i < lowJobs; i++ {
+		job, _ := jobs.NewJobPayload("low-job", nil, jobs.WithPriority(jobs.PriorityLow))
+		q.Enqueue(ctx, job)
+	}
+
+	// A steady stream of critical jobs keeps the critical queue busy
+	drained := 0
+	for i := 0; i < 600 && drained < lowJobs; i++ {
+		critical, _ := jobs.NewJobPayload("critical-job", nil, jobs.WithPriority(jobs.PriorityCritical))
+		q.Enqueue(ctx, critical)
+
+		dequeued, err := q.Dequeue(ctx)
+		if err != nil {
+			t.Fatalf("Dequeue() %d error = %v", i, err)
+		}
+		if dequeued.Priority == jobs.PriorityLow {
+			drained++
+		}
+	}
+
+	if drained != lowJobs {
+		t.Errorf("drained %d low priority jobs, want %d", drained, lowJobs)
+	}
+}
+
+func TestRedisQueue_DequeueFrom_NamedQueues(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	defaultJob, _ := jobs.NewJobPayload("default-job", nil)
+	mailJob, _ := jobs.NewJobPayload("mail-job", nil, jobs.WithQueue("mail"))
+	q.Enqueue(ctx, defaultJob)
+	q.Enqueue(ctx, mailJob)
+
+	dequeued, err := q.DequeueFrom(ctx, []string{"mail"})
+	if err != nil {
+		t.Fatalf("DequeueFrom() error = %v", err)
+	}
+	if dequeued.ID != mailJob.ID {
+		t.Errorf("DequeueFrom() ID = %v, want %v", dequeued.ID, mailJob.ID)
+	}
+	if _, err := q.DequeueFrom(ctx, []string{"mail"}); err != jobs.ErrQueueEmpty {
+		t.Errorf("DequeueFrom() error = %v, want jobs.ErrQueueEmpty", err)
+	}
+
+	stats, err := q.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if stats[jobs.StatsQueueDepthPrefix+jobs.DefaultQueue] != 1 {
+		t.Errorf("default queue depth = %d, want 1", stats[jobs.StatsQueueDepthPrefix+jobs.DefaultQueue])
+	}
+}
+
 func TestRedisQueue_Dequeue_Empty(t *testing.T) {
 	q, ctx := setupTestQueue(t)
 
@@ -335,6 +472,113 @@ func TestRedisQueue_Fail_MoveToDLQ(t *testing.T) {
 	}
 }
 
+func TestRedisQueue_ScheduleRetry(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	job, _ := jobs.NewJobPayload("retry-test", nil)
+	job.MaxRetries = 0 // Policy-driven retries ignore the payload's MaxRetries
+	q.Enqueue(ctx, job)
+
+	before := time.Now()
+	if err := q.ScheduleRetry(ctx, job.ID, jobs.ErrJobNotFound, time.Minute); err != nil {
+		t.Fatalf("ScheduleRetry() error = %v", err)
+	}
+
+	retrying, _ := q.GetJob(ctx, job.ID)
+	if retrying.Status != jobs.JobStatusRetrying {
+		t.Errorf("Status = %v, want retrying", retrying.Status)
+	}
+	if retrying.ScheduledAt == nil || retrying.ScheduledAt.Before(before.Add(time.Minute)) {
+		t.Errorf("ScheduledAt = %v, want >= now+1m", retrying.ScheduledAt)
+	}
+}
+
+func TestRedisQueue_MoveToDLQ(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	job, _ := jobs.NewJobPayload("dlq-direct-test", nil)
+	q.Enqueue(ctx, job)
+
+	if err := q.MoveToDLQ(ctx, job.ID, jobs.ErrJobNotFound); err != nil {
+		t.Fatalf("MoveToDLQ() error = %v", err)
+	}
+
+	dead, _ := q.GetJob(ctx, job.ID)
+	if dead.Status != jobs.JobStatusDead {
+		t.Errorf("Status = %v, want dead", dead.Status)
+	}
+	if dead.LastError != jobs.ErrJobNotFound.Error() {
+		t.Errorf("LastError = %q, want %q", dead.LastError, jobs.ErrJobNotFound.Error())
+	}
+}
+
+func TestRedisQueue_Progress(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	if _, err := q.GetProgress(ctx, "no-progress"); err != jobs.ErrProgressNotFound {
+		t.Errorf("GetProgress() error = %v, want jobs.ErrProgressNotFound", err)
+	}
+
+	updatedAt := time.Now().UTC().Truncate(time.Millisecond)
+	if err := q.SetProgress(ctx, "progress-job", jobs.JobProgress{Percent: 30, Message: "loading", UpdatedAt: updatedAt}); err != nil {
+		t.Fatalf("SetProgress() error = %v", err)
+	}
+
+	progress, err := q.GetProgress(ctx, "progress-job")
+	if err != nil {
+		t.Fatalf("GetProgress() error = %v", err)
+	}
+	if progress.Percent != 30 || progress.Message != "loading" || !progress.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("GetProgress() = %+v", progress)
+	}
+
+	if err := q.ExpireProgress(ctx, "progress-job", 50*time.Millisecond); err != nil {
+		t.Fatalf("ExpireProgress() error = %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := q.GetProgress(ctx, "progress-job"); err != jobs.ErrProgressNotFound {
+		t.Errorf("GetProgress() after expiry error = %v, want jobs.ErrProgressNotFound", err)
+	}
+}
+
+func TestRedisQueue_PauseResumeType(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	job, _ := jobs.NewJobPayload("pausable", nil)
+	q.Enqueue(ctx, job)
+	other, _ := jobs.NewJobPayload("other", nil)
+	q.Enqueue(ctx, other)
+
+	if err := q.PauseType(ctx, "pausable"); err != nil {
+		t.Fatalf("PauseType() error = %v", err)
+	}
+	types, _ := q.PausedTypes(ctx)
+	if len(types) != 1 || types[0] != "pausable" {
+		t.Errorf("PausedTypes() = %v, want [pausable]", types)
+	}
+
+	// The paused job is skipped, not popped
+	dequeued, err := q.Dequeue(ctx)
+	if err != nil || dequeued.ID != other.ID {
+		t.Fatalf("Dequeue() while paused = %v, %v; want job %s", dequeued, err, other.ID)
+	}
+	if _, err := q.Dequeue(ctx); err != jobs.ErrQueueEmpty {
+		t.Errorf("Dequeue() with only paused jobs error = %v, want jobs.ErrQueueEmpty", err)
+	}
+	stats, _ := q.GetStats(ctx)
+	if stats["queue_normal"] != 1 {
+		t.Errorf("queue_normal while paused = %d, want 1", stats["queue_normal"])
+	}
+
+	if err := q.ResumeType(ctx, "pausable"); err != nil {
+		t.Fatalf("ResumeType() error = %v", err)
+	}
+	again, err := q.Dequeue(ctx)
+	if err != nil || again.ID != job.ID {
+		t.Errorf("Dequeue() after resume = %v, %v; want job %s", again, err, job.ID)
+	}
+}
+
 func TestRedisQueue_ProcessScheduled(t *testing.T) {
 	q, ctx := setupTestQueue(t)
 
@@ -487,6 +731,40 @@ func TestRedisQueue_RequeueJob(t *testing.T) {
 	}
 }
 
+func TestRedisQueue_SaveAndGetResult(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	jobID := testutil.GenerateTestID()
+	if _, err := q.GetResult(ctx, jobID); err != jobs.ErrResultNotFound {
+		t.Errorf("GetResult() before save error = %v, want jobs.ErrResultNotFound", err)
+	}
+
+	if err := q.SaveResult(ctx, jobID, json.RawMessage(`{"rows":42}`), time.Minute); err != nil {
+		t.Fatalf("SaveResult() error = %v", err)
+	}
+
+	result, err := q.GetResult(ctx, jobID)
+	if err != nil {
+		t.Fatalf("GetResult() error = %v", err)
+	}
+	if string(result) != `{"rows":42}` {
+		t.Errorf("GetResult() = %s, want {\"rows\":42}", result)
+	}
+}
+
+func TestRedisQueue_Result_Expires(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	jobID := testutil.GenerateTestID()
+	q.SaveResult(ctx, jobID, json.RawMessage(`1`), 100*time.Millisecond)
+
+	time.Sleep(200 * time.Millisecond)
+
+	if _, err := q.GetResult(ctx, jobID); err != jobs.ErrResultNotFound {
+		t.Errorf("GetResult() after TTL error = %v, want jobs.ErrResultNotFound", err)
+	}
+}
+
 func TestRedisQueue_GetStats(t *testing.T) {
 	q, ctx := setupTestQueue(t)
 
@@ -514,6 +792,36 @@ func TestRedisQueue_GetStats(t *testing.T) {
 	}
 }
 
+func TestRedisQueue_DepthByPriority(t *testing.T) {
+	q, ctx := setupTestQueue(t)
+
+	for _, p := range []jobs.Priority{jobs.PriorityHigh, jobs.PriorityHigh, jobs.PriorityLow} {
+		job, _ := jobs.NewJobPayload("depth-test", nil, jobs.WithPriority(p), jobs.WithQueue("reports"))
+		if err := q.Enqueue(ctx, job); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	// Jobs on another queue do not count
+	job, _ := jobs.NewJobPayload("depth-test", nil, jobs.WithPriority(jobs.PriorityHigh))
+	q.Enqueue(ctx, job)
+
+	depths, err := q.DepthByPriority(ctx, "reports")
+	if err != nil {
+		t.Fatalf("DepthByPriority() error = %v", err)
+	}
+	want := map[jobs.Priority]int64{
+		jobs.PriorityCritical: 0,
+		jobs.PriorityHigh:     2,
+		jobs.PriorityNormal:   0,
+		jobs.PriorityLow:      1,
+	}
+	for p, depth := range want {
+		if depths[p] != depth {
+			t.Errorf("depth of %s = %d, want %d", p, depths[p], depth)
+		}
+	}
+}
+
 // Error cases
 func TestRedisQueue_Errors(t *testing.T) {
 	t.Run("ErrJobNotFound", func(t *testing.T) {
@@ -4,7 +4,11 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
@@ -27,15 +31,35 @@ const (
 
 	// Redis key prefixes for scheduler
 	leaderKey             = "arcana:jobs:scheduler:leader"
+	leaderElectedKey      = "arcana:jobs:scheduler:leader:elected" // When the current leader was elected, unix ms
 	cronExecutionPrefix   = "arcana:jobs:cron:execution:"
 	cronLockPrefix        = "arcana:jobs:cron:lock:"
+	oneShotKey            = "arcana:jobs:scheduler:once"      // Sorted set of one-shot job IDs by due time
+	oneShotDataKey        = "arcana:jobs:scheduler:once:jobs" // Hash of one-shot job ID to its definition
+	disabledJobsKey       = "arcana:jobs:scheduler:disabled"  // Set of disabled scheduled job names
+	lastTriggeredKey      = "arcana:jobs:scheduler:triggered" // Hash of scheduled job name to when it last enqueued a job
+
+	// oneShotBatchSize caps the due one-shot jobs enqueued per poll
+	oneShotBatchSize = 100
+)
+
+var (
+	// ErrScheduledJobNotFound is returned for a job name that was never registered
+	ErrScheduledJobNotFound = errors.New("scheduled job not found")
+	// ErrSingletonJobRunning is returned when triggering a singleton job that is running
+	ErrSingletonJobRunning = errors.New("singleton job already running")
 )
 
+// cronParser parses the standard 5-field cron expressions used by ScheduledJob
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
 // SchedulerConfig holds scheduler configuration
 type SchedulerConfig struct {
 	LeaderLockTTL        time.Duration
 	CronExecutionLockTTL time.Duration
 	CronDeduplicationTTL time.Duration
+	Timezone             string        // Default IANA timezone for jobs; empty means server local time
+	OneShotPollInterval  time.Duration // How often the leader looks for due one-shot jobs; defaults to a second
 }
 
 // DefaultSchedulerConfig returns default scheduler configuration
@@ -44,6 +68,7 @@ func DefaultSchedulerConfig() SchedulerConfig {
 		LeaderLockTTL:        30 * time.Second,
 		CronExecutionLockTTL: 60 * time.Second,
 		CronDeduplicationTTL: 24 * time.Hour,
+		OneShotPollInterval:  time.Second,
 	}
 }
 
@@ -56,7 +81,18 @@ type ScheduledJob struct {
 	Priority    jobs.Priority
 	UniqueKey   string // Optional: base key for deduplication
 	Tags        []string
-	Singleton   bool // If true, only one instance can run at a time
+	Singleton   bool   // If true, only one instance can run at a time
+	Timezone    string // Optional: IANA timezone (e.g. "America/New_York"); defaults to SchedulerConfig.Timezone
+
+	location *time.Location // resolved at registration
+}
+
+// oneShotJob is a job persisted to be enqueued once at RunAt
+type oneShotJob struct {
+	ID      string          `json:"id"`
+	JobType string          `json:"job_type"`
+	Payload json.RawMessage `json:"payload"`
+	RunAt   time.Time       `json:"run_at"`
 }
 
 // Scheduler manages cron-based job scheduling with leader election
@@ -74,6 +110,12 @@ type Scheduler struct {
 	isLeader   bool
 	leaderMu   sync.RWMutex
 
+	// Leadership hooks, run by leadershipHookLoop after each change
+	leadershipHooks   []func(isLeader bool)
+	hooksMu           sync.RWMutex
+	leadershipChanged chan struct{}
+	notifiedLeader    bool // last state passed to the hooks
+
 	// State
 	running bool
 	stopCh  chan struct{}
@@ -96,6 +138,8 @@ func NewSchedulerWithConfig(redisClient *redis.Client, jobQueue jobs.Queue, logg
 		jobs:       make(map[string]ScheduledJob),
 		instanceID: uuid.New().String(),
 		stopCh:     make(chan struct{}),
+
+		leadershipChanged: make(chan struct{}, 1),
 	}
 }
 
@@ -108,10 +152,14 @@ func (s *Scheduler) RegisterJob(job ScheduledJob) error {
 		return fmt.Errorf("job %s already registered", job.Name)
 	}
 
-	// Validate cron expression
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	_, err := parser.Parse(job.Schedule)
+	loc, err := s.resolveLocation(job.Timezone)
 	if err != nil {
+		return err
+	}
+	job.location = loc
+
+	// Validate cron expression
+	if _, err := parseSchedule(job.Schedule, loc); err != nil {
 		return fmt.Errorf("invalid cron expression: %w", err)
 	}
 
@@ -120,12 +168,65 @@ func (s *Scheduler) RegisterJob(job ScheduledJob) error {
 		zap.String("name", job.Name),
 		zap.String("schedule", job.Schedule),
 		zap.String("job_type", job.JobType),
+		zap.String("timezone", loc.String()),
 		zap.Bool("singleton", job.Singleton),
 	)
 
 	return nil
 }
 
+// resolveLocation returns the location for a job timezone, falling back to the configured default
+func (s *Scheduler) resolveLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		timezone = s.config.Timezone
+	}
+	if timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return loc, nil
+}
+
+// parseSchedule parses a cron expression and evaluates it in the given location.
+// Next-run calculation happens in wall-clock time of loc, so DST gaps are skipped
+// and times in a repeated (fall-back) hour fire only once.
+func parseSchedule(spec string, loc *time.Location) (cron.Schedule, error) {
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	specSchedule, ok := schedule.(*cron.SpecSchedule)
+	if !ok || loc == nil {
+		return schedule, nil
+	}
+	specSchedule.Location = loc
+	return zonedSchedule{spec: specSchedule}, nil
+}
+
+// zonedSchedule wraps a SpecSchedule so that a wall-clock time repeated by a
+// DST fall-back transition does not trigger a second run
+type zonedSchedule struct {
+	spec *cron.SpecSchedule
+}
+
+// Next returns the next activation time later than t
+func (z zonedSchedule) Next(t time.Time) time.Time {
+	next := z.spec.Next(t)
+	if !next.IsZero() && sameWallClockMinute(t.In(z.spec.Location), next) {
+		next = z.spec.Next(next)
+	}
+	return next
+}
+
+func sameWallClockMinute(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd && a.Hour() == b.Hour() && a.Minute() == b.Minute()
+}
+
 // Start starts the scheduler
 func (s *Scheduler) Start(ctx context.Context) error {
 	if s.running {
@@ -138,6 +239,12 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	// Start leader election
 	s.wg.Add(1)
 	go s.leaderElectionLoop(ctx)
+	s.wg.Add(1)
+	go s.leadershipHookLoop()
+
+	// Start one-shot job polling
+	s.wg.Add(1)
+	go s.oneShotLoop(ctx)
 
 	// Start cron scheduler
 	s.setupCronJobs()
@@ -167,6 +274,9 @@ func (s *Scheduler) Stop(ctx context.Context) error {
 	s.releaseLeadership(ctx)
 
 	s.wg.Wait()
+
+	// The hook loop has exited, so tell the hooks about the release here
+	s.runLeadershipHooks()
 	return nil
 }
 
@@ -177,15 +287,17 @@ func (s *Scheduler) setupCronJobs() {
 
 	for _, job := range s.jobs {
 		j := job // capture loop variable
-		_, err := s.cron.AddFunc(j.Schedule, func() {
-			s.executeScheduledJob(context.Background(), j)
-		})
+		schedule, err := parseSchedule(j.Schedule, j.location)
 		if err != nil {
 			s.logger.Error("Failed to add cron job",
 				zap.String("name", j.Name),
 				zap.Error(err),
 			)
+			continue
 		}
+		s.cron.Schedule(schedule, cron.FuncJob(func() {
+			s.executeScheduledJob(context.Background(), j)
+		}))
 	}
 }
 
@@ -202,6 +314,21 @@ func (s *Scheduler) executeScheduledJob(ctx context.Context, job ScheduledJob) {
 		return
 	}
 
+	disabled, err := s.isJobDisabled(ctx, job.Name)
+	if err != nil {
+		s.logger.Error("Failed to read scheduled job state",
+			zap.String("name", job.Name),
+			zap.Error(err),
+		)
+		return
+	}
+	if disabled {
+		s.logger.Debug("Skipping disabled scheduled job",
+			zap.String("name", job.Name),
+		)
+		return
+	}
+
 	// Generate execution window key for deduplication
 	executionWindow := s.getExecutionWindow(job.Schedule)
 	executionKey := s.generateExecutionKey(job.Name, executionWindow)
@@ -275,6 +402,7 @@ func (s *Scheduler) executeScheduledJob(ctx context.Context, job ScheduledJob) {
 		)
 		return
 	}
+	s.recordTrigger(ctx, job.Name)
 
 	s.logger.Info("Scheduled job enqueued",
 		zap.String("name", job.Name),
@@ -283,6 +411,243 @@ func (s *Scheduler) executeScheduledJob(ctx context.Context, job ScheduledJob) {
 	)
 }
 
+// DisableJob stops a registered job from being enqueued on its schedule
+// until it is enabled again. The state is kept in Redis, so it holds for
+// every instance and survives restarts.
+func (s *Scheduler) DisableJob(ctx context.Context, name string) error {
+	if _, err := s.getJob(name); err != nil {
+		return err
+	}
+	if err := s.redis.SAdd(ctx, disabledJobsKey, name).Err(); err != nil {
+		return fmt.Errorf("failed to disable scheduled job: %w", err)
+	}
+	s.logger.Info("Disabled scheduled job", zap.String("name", name))
+	return nil
+}
+
+// EnableJob lets a disabled job be enqueued on its schedule again
+func (s *Scheduler) EnableJob(ctx context.Context, name string) error {
+	if _, err := s.getJob(name); err != nil {
+		return err
+	}
+	if err := s.redis.SRem(ctx, disabledJobsKey, name).Err(); err != nil {
+		return fmt.Errorf("failed to enable scheduled job: %w", err)
+	}
+	s.logger.Info("Enabled scheduled job", zap.String("name", name))
+	return nil
+}
+
+// TriggerNow enqueues a registered job immediately, outside its schedule and
+// even if it is disabled, and returns the ID of the queued job. Any instance
+// may trigger a job, leader or not. Returns ErrSingletonJobRunning if the job
+// is a singleton and an instance of it is running.
+func (s *Scheduler) TriggerNow(ctx context.Context, name string) (string, error) {
+	job, err := s.getJob(name)
+	if err != nil {
+		return "", err
+	}
+
+	if job.Singleton {
+		running, err := s.isSingletonJobRunning(ctx, job.Name)
+		if err != nil {
+			return "", fmt.Errorf("failed to check singleton job status: %w", err)
+		}
+		if running {
+			return "", ErrSingletonJobRunning
+		}
+	}
+
+	payload, err := jobs.NewJobPayload(job.JobType, job.Payload,
+		jobs.WithPriority(job.Priority),
+		jobs.WithTags(slices.Concat(job.Tags, []string{"scheduled", "cron:" + job.Name, "manual"})...),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create job payload: %w", err)
+	}
+	if err := s.queue.Enqueue(ctx, payload); err != nil {
+		return "", fmt.Errorf("failed to enqueue scheduled job: %w", err)
+	}
+	s.recordTrigger(ctx, job.Name)
+
+	s.logger.Info("Scheduled job triggered manually",
+		zap.String("name", job.Name),
+		zap.String("job_id", payload.ID),
+	)
+	return payload.ID, nil
+}
+
+// getJob returns a registered job by name
+func (s *Scheduler) getJob(name string) (ScheduledJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[name]
+	if !exists {
+		return ScheduledJob{}, fmt.Errorf("%w: %s", ErrScheduledJobNotFound, name)
+	}
+	return job, nil
+}
+
+// isJobDisabled reports whether a job was disabled
+func (s *Scheduler) isJobDisabled(ctx context.Context, name string) (bool, error) {
+	return s.redis.SIsMember(ctx, disabledJobsKey, name).Result()
+}
+
+// recordTrigger stores when a job was last enqueued. A failure is only
+// logged, since the job is already queued.
+func (s *Scheduler) recordTrigger(ctx context.Context, name string) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	if err := s.redis.HSet(ctx, lastTriggeredKey, name, now).Err(); err != nil {
+		s.logger.Warn("Failed to record scheduled job trigger",
+			zap.String("name", name),
+			zap.Error(err),
+		)
+	}
+}
+
+// ScheduleOnce persists a job to be enqueued once at runAt and returns its
+// ID. The intent is stored in Redis, so it survives restarts and leader
+// changes; a job that fell due while no scheduler was running is enqueued
+// as soon as a leader polls again.
+func (s *Scheduler) ScheduleOnce(ctx context.Context, runAt time.Time, jobType string, payload any) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize payload: %w", err)
+	}
+	job := oneShotJob{
+		ID:      uuid.New().String(),
+		JobType: jobType,
+		Payload: data,
+		RunAt:   runAt.UTC(),
+	}
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize one-shot job: %w", err)
+	}
+
+	if _, err := s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, oneShotDataKey, job.ID, encoded)
+		pipe.ZAdd(ctx, oneShotKey, redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID})
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to store one-shot job: %w", err)
+	}
+
+	s.logger.Info("Scheduled one-shot job",
+		zap.String("id", job.ID),
+		zap.String("job_type", jobType),
+		zap.Time("run_at", job.RunAt),
+	)
+	return job.ID, nil
+}
+
+// oneShotLoop enqueues one-shot jobs as they fall due
+func (s *Scheduler) oneShotLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	interval := s.config.OneShotPollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.processOneShotJobs(ctx); err != nil {
+				s.logger.Error("Failed to process one-shot jobs", zap.Error(err))
+			}
+		}
+	}
+}
+
+// processOneShotJobs enqueues the one-shot jobs that are due, if this
+// instance is the leader. It returns how many were handed to the queue.
+func (s *Scheduler) processOneShotJobs(ctx context.Context) (int, error) {
+	if !s.IsLeader() {
+		return 0, nil
+	}
+
+	ids, err := s.redis.ZRangeByScore(ctx, oneShotKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: oneShotBatchSize,
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read due one-shot jobs: %w", err)
+	}
+
+	fired := 0
+	for _, id := range ids {
+		if err := s.fireOneShotJob(ctx, id); err != nil {
+			s.logger.Error("Failed to enqueue one-shot job",
+				zap.String("id", id),
+				zap.Error(err),
+			)
+			continue
+		}
+		fired++
+	}
+	return fired, nil
+}
+
+// fireOneShotJob enqueues a due one-shot job and then forgets it. The queued
+// job's unique key is derived from the one-shot ID, so a job enqueued twice
+// (by two leaders, or again after a crash before it was forgotten) is only
+// queued once.
+func (s *Scheduler) fireOneShotJob(ctx context.Context, id string) error {
+	raw, err := s.redis.HGet(ctx, oneShotDataKey, id).Result()
+	if err == redis.Nil {
+		// Fired and forgotten by another leader since the due set was read
+		s.redis.ZRem(ctx, oneShotKey, id)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var job oneShotJob
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		s.forgetOneShotJob(ctx, id)
+		return fmt.Errorf("invalid one-shot job: %w", err)
+	}
+
+	payload, err := jobs.NewJobPayload(job.JobType, job.Payload,
+		jobs.WithUniqueKey("once:"+job.ID),
+		jobs.WithUniqueFor(s.config.CronDeduplicationTTL),
+		jobs.WithTags("scheduled", "once"),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := s.queue.Enqueue(ctx, payload); err != nil && !errors.Is(err, jobs.ErrDuplicateJob) {
+		return err
+	}
+	s.forgetOneShotJob(ctx, id)
+
+	s.logger.Info("One-shot job enqueued",
+		zap.String("id", job.ID),
+		zap.String("job_type", job.JobType),
+		zap.Time("run_at", job.RunAt),
+	)
+	return nil
+}
+
+// forgetOneShotJob removes a one-shot job from the schedule
+func (s *Scheduler) forgetOneShotJob(ctx context.Context, id string) {
+	s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, oneShotKey, id)
+		pipe.HDel(ctx, oneShotDataKey, id)
+		return nil
+	})
+}
+
 // getExecutionWindow returns a time window identifier based on the cron schedule
 func (s *Scheduler) getExecutionWindow(schedule string) string {
 	now := time.Now().UTC()
@@ -412,38 +777,29 @@ func (s *Scheduler) tryAcquireLeadership(ctx context.Context) {
 	set, err := s.redis.SetNX(ctx, leaderKey, s.instanceID, s.config.LeaderLockTTL).Result()
 	if err != nil {
 		s.logger.Error("Failed to acquire leadership", zap.Error(err))
-		s.isLeader = false
+		s.setLeader(ctx, false, zap.Error(err))
 		return
 	}
 
 	if set {
 		// We acquired leadership
-		if !s.isLeader {
-			s.logger.Info("Acquired scheduler leadership", zap.String("instance_id", s.instanceID))
-		}
-		s.isLeader = true
+		s.setLeader(ctx, true)
 		return
 	}
 
 	// Check if we're already the leader and renew
 	currentLeader, err := s.redis.Get(ctx, leaderKey).Result()
 	if err != nil {
-		s.isLeader = false
+		s.setLeader(ctx, false, zap.Error(err))
 		return
 	}
 
 	if currentLeader == s.instanceID {
 		// Renew our lease
 		s.redis.Expire(ctx, leaderKey, s.config.LeaderLockTTL)
-		s.isLeader = true
+		s.setLeader(ctx, true)
 	} else {
-		if s.isLeader {
-			s.logger.Info("Lost scheduler leadership",
-				zap.String("instance_id", s.instanceID),
-				zap.String("new_leader", currentLeader),
-			)
-		}
-		s.isLeader = false
+		s.setLeader(ctx, false, zap.String("new_leader", currentLeader))
 	}
 }
 
@@ -463,7 +819,121 @@ func (s *Scheduler) releaseLeadership(ctx context.Context) {
 		s.logger.Info("Released scheduler leadership", zap.String("instance_id", s.instanceID))
 	}
 
-	s.isLeader = false
+	s.setLeader(ctx, false)
+}
+
+// setLeader updates this instance's leadership, logging and counting each
+// change and signalling the leadership hooks. The caller holds leaderMu.
+func (s *Scheduler) setLeader(ctx context.Context, isLeader bool, fields ...zap.Field) {
+	if s.isLeader == isLeader {
+		return
+	}
+	s.isLeader = isLeader
+	jobs.GlobalMetrics.RecordLeadershipChange(isLeader)
+
+	fields = append([]zap.Field{zap.String("instance_id", s.instanceID)}, fields...)
+	if isLeader {
+		electedAt := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		if err := s.redis.Set(ctx, leaderElectedKey, electedAt, 0).Err(); err != nil {
+			s.logger.Warn("Failed to record scheduler election time", zap.Error(err))
+		}
+		s.logger.Info("Acquired scheduler leadership", fields...)
+	} else {
+		s.logger.Warn("Lost scheduler leadership", fields...)
+	}
+
+	// Never block elections on the hooks; a pending signal already covers this change
+	select {
+	case s.leadershipChanged <- struct{}{}:
+	default:
+	}
+}
+
+// OnLeadershipChange registers a hook called with the new state whenever this
+// instance gains or loses leadership. Hooks run one at a time on their own
+// goroutine, so a slow hook never delays elections. Changes that happen while
+// hooks are running are coalesced: the hooks then only see the latest state.
+func (s *Scheduler) OnLeadershipChange(fn func(isLeader bool)) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.leadershipHooks = append(s.leadershipHooks, fn)
+}
+
+// leadershipHookLoop runs the leadership hooks after each change until the
+// scheduler stops
+func (s *Scheduler) leadershipHookLoop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-s.leadershipChanged:
+			s.runLeadershipHooks()
+		}
+	}
+}
+
+// runLeadershipHooks passes the current leadership state to the hooks, unless
+// they were already given it
+func (s *Scheduler) runLeadershipHooks() {
+	isLeader := s.IsLeader()
+	if isLeader == s.notifiedLeader {
+		return
+	}
+	s.notifiedLeader = isLeader
+
+	s.hooksMu.RLock()
+	hooks := slices.Clone(s.leadershipHooks)
+	s.hooksMu.RUnlock()
+
+	for _, hook := range hooks {
+		s.runLeadershipHook(hook, isLeader)
+	}
+}
+
+// runLeadershipHook runs one hook, recovering from a panic so the others still run
+func (s *Scheduler) runLeadershipHook(hook func(isLeader bool), isLeader bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Leadership hook panicked",
+				zap.Bool("is_leader", isLeader),
+				zap.Any("panic", r),
+			)
+		}
+	}()
+	hook(isLeader)
+}
+
+// Status returns the leader election state: this instance's view and, from
+// Redis, the current leader, its lease and the last election
+func (s *Scheduler) Status(ctx context.Context) (jobs.SchedulerStatus, error) {
+	status := jobs.SchedulerStatus{
+		InstanceID: s.instanceID,
+		IsLeader:   s.IsLeader(),
+	}
+
+	pipe := s.redis.Pipeline()
+	leaderCmd := pipe.Get(ctx, leaderKey)
+	ttlCmd := pipe.PTTL(ctx, leaderKey)
+	electedCmd := pipe.Get(ctx, leaderElectedKey)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return status, fmt.Errorf("failed to read scheduler leader: %w", err)
+	}
+
+	now := time.Now()
+	if leader, err := leaderCmd.Result(); err == nil {
+		status.LeaderID = leader
+		if ttl := ttlCmd.Val(); ttl > 0 {
+			expiresAt := now.Add(ttl)
+			status.LeaseExpiresAt = &expiresAt
+		}
+	}
+	if ms, err := electedCmd.Int64(); err == nil {
+		electedAt := time.UnixMilli(ms)
+		status.LastElectionAt = &electedAt
+	}
+	return status, nil
 }
 
 // IsLeader returns whether this instance is the leader
@@ -473,24 +943,62 @@ func (s *Scheduler) IsLeader() bool {
 	return s.isLeader
 }
 
-// ListJobs returns all registered scheduled jobs
+// ListJobs returns all registered scheduled jobs. Enabled and
+// LastTriggeredAt are only filled by ListJobsWithState.
 func (s *Scheduler) ListJobs() []jobs.ScheduledJobInfo {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	result := make([]jobs.ScheduledJobInfo, 0, len(s.jobs))
+	now := time.Now()
 	for _, job := range s.jobs {
-		result = append(result, jobs.ScheduledJobInfo{
+		info := jobs.ScheduledJobInfo{
 			Name:      job.Name,
 			Schedule:  job.Schedule,
 			JobType:   job.JobType,
 			Priority:  job.Priority.String(),
 			Singleton: job.Singleton,
-		})
+			Timezone:  job.location.String(),
+		}
+		if schedule, err := parseSchedule(job.Schedule, job.location); err == nil {
+			info.NextRun = schedule.Next(now).UTC()
+		}
+		result = append(result, info)
 	}
 	return result
 }
 
+// ListJobsWithState returns all registered scheduled jobs with whether they
+// are enabled and when they last enqueued a job, as stored in Redis
+func (s *Scheduler) ListJobsWithState(ctx context.Context) ([]jobs.ScheduledJobInfo, error) {
+	var disabled *redis.StringSliceCmd
+	var triggered *redis.MapStringStringCmd
+	if _, err := s.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		disabled = pipe.SMembers(ctx, disabledJobsKey)
+		triggered = pipe.HGetAll(ctx, lastTriggeredKey)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read scheduled job state: %w", err)
+	}
+
+	disabledNames := make(map[string]bool)
+	for _, name := range disabled.Val() {
+		disabledNames[name] = true
+	}
+	triggeredAt := triggered.Val()
+
+	result := s.ListJobs()
+	for i := range result {
+		info := &result[i]
+		info.Enabled = !disabledNames[info.Name]
+		if millis, err := strconv.ParseInt(triggeredAt[info.Name], 10, 64); err == nil {
+			at := time.UnixMilli(millis).UTC()
+			info.LastTriggeredAt = &at
+		}
+	}
+	return result, nil
+}
+
 // ListScheduledJobs returns all registered ScheduledJob structs (internal use)
 func (s *Scheduler) ListScheduledJobs() []ScheduledJob {
 	s.mu.RLock()
@@ -503,7 +1011,7 @@ func (s *Scheduler) ListScheduledJobs() []ScheduledJob {
 	return result
 }
 
-// GetNextRun returns the next scheduled run time for a job
+// GetNextRun returns the next scheduled run time for a job, in UTC
 func (s *Scheduler) GetNextRun(jobName string) (time.Time, error) {
 	s.mu.RLock()
 	job, exists := s.jobs[jobName]
@@ -513,13 +1021,12 @@ func (s *Scheduler) GetNextRun(jobName string) (time.Time, error) {
 		return time.Time{}, fmt.Errorf("job %s not found", jobName)
 	}
 
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	schedule, err := parser.Parse(job.Schedule)
+	schedule, err := parseSchedule(job.Schedule, job.location)
 	if err != nil {
 		return time.Time{}, err
 	}
 
-	return schedule.Next(time.Now()), nil
+	return schedule.Next(time.Now()).UTC(), nil
 }
 
 // GetRecentExecutions returns recent execution records for a job
@@ -2,9 +2,13 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs/queue"
 	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
@@ -39,6 +43,9 @@ func TestDefaultSchedulerConfig(t *testing.T) {
 	if config.CronDeduplicationTTL != 24*time.Hour {
 		t.Errorf("CronDeduplicationTTL = %v, want 24h", config.CronDeduplicationTTL)
 	}
+	if config.OneShotPollInterval != time.Second {
+		t.Errorf("OneShotPollInterval = %v, want 1s", config.OneShotPollInterval)
+	}
 }
 
 func TestNewScheduler(t *testing.T) {
@@ -216,6 +223,96 @@ func TestScheduler_LeaderElection(t *testing.T) {
 	}
 }
 
+func TestScheduler_LeadershipStatusAndHooks(t *testing.T) {
+	sched, _, ctx := setupTestScheduler(t)
+
+	changes := make(chan bool, 2)
+	sched.OnLeadershipChange(func(isLeader bool) { changes <- isLeader })
+
+	if err := sched.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	select {
+	case isLeader := <-changes:
+		if !isLeader {
+			t.Fatal("first leadership change should be an acquisition")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("leadership hook not called on acquisition")
+	}
+
+	status, err := sched.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.IsLeader || status.LeaderID != sched.instanceID {
+		t.Errorf("Status() leader = %q (is leader %v), want %q", status.LeaderID, status.IsLeader, sched.instanceID)
+	}
+	if status.LeaseExpiresAt == nil || !status.LeaseExpiresAt.After(time.Now()) {
+		t.Errorf("Status() LeaseExpiresAt = %v, want a future time", status.LeaseExpiresAt)
+	}
+	if status.LastElectionAt == nil || time.Since(*status.LastElectionAt) > time.Minute {
+		t.Errorf("Status() LastElectionAt = %v, want the election just held", status.LastElectionAt)
+	}
+
+	if err := sched.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	select {
+	case isLeader := <-changes:
+		if isLeader {
+			t.Error("releasing leadership should report a loss")
+		}
+	default:
+		t.Error("leadership hook not called on release")
+	}
+}
+
+func TestScheduler_LeadershipHooksOffCriticalPath(t *testing.T) {
+	sched := NewSchedulerWithConfig(nil, nil, zap.NewNop(), DefaultSchedulerConfig())
+	sched.isLeader = true
+	sched.notifiedLeader = true
+
+	unblock := make(chan struct{})
+	called := make(chan bool, 1)
+	sched.OnLeadershipChange(func(bool) { panic("hook failure") })
+	sched.OnLeadershipChange(func(isLeader bool) {
+		<-unblock
+		called <- isLeader
+	})
+
+	sched.wg.Add(1)
+	go sched.leadershipHookLoop()
+	defer func() {
+		close(sched.stopCh)
+		sched.wg.Wait()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		sched.leaderMu.Lock()
+		sched.setLeader(context.Background(), false)
+		sched.leaderMu.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("setLeader() blocked on a leadership hook")
+	}
+
+	close(unblock)
+	select {
+	case isLeader := <-called:
+		if isLeader {
+			t.Error("hook got isLeader = true, want false")
+		}
+	case <-time.After(time.Second):
+		t.Error("hook after a panicking hook was not called")
+	}
+}
+
 func TestScheduler_IsLeader_NotStarted(t *testing.T) {
 	sched, _, _ := setupTestScheduler(t)
 
@@ -323,6 +420,161 @@ func TestScheduler_SingletonJob(t *testing.T) {
 	sched.ReleaseSingletonLock(ctx, "singleton-test")
 }
 
+func TestScheduler_ScheduleOnce(t *testing.T) {
+	sched, q, ctx := setupTestScheduler(t)
+	sched.isLeader = true
+
+	dueID, err := sched.ScheduleOnce(ctx, time.Now().Add(-time.Minute), "once-test", map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatalf("ScheduleOnce() error = %v", err)
+	}
+	if _, err := sched.ScheduleOnce(ctx, time.Now().Add(time.Hour), "once-test", nil); err != nil {
+		t.Fatalf("ScheduleOnce() future error = %v", err)
+	}
+
+	raw := sched.redis.HGet(ctx, oneShotDataKey, dueID).Val()
+	before, _ := q.GetStats(ctx)
+
+	// A job that fell due while nothing was polling fires on the next poll
+	fired, err := sched.processOneShotJobs(ctx)
+	if err != nil || fired != 1 {
+		t.Fatalf("processOneShotJobs() = %d, %v, want 1 job", fired, err)
+	}
+	if fired, _ := sched.processOneShotJobs(ctx); fired != 0 {
+		t.Errorf("second processOneShotJobs() = %d, want 0", fired)
+	}
+
+	// Firing again, as a second leader or a restart after a crash would, does
+	// not queue it twice
+	sched.redis.HSet(ctx, oneShotDataKey, dueID, raw)
+	sched.redis.ZAdd(ctx, oneShotKey, redis.Z{Score: 0, Member: dueID})
+	if fired, err := sched.processOneShotJobs(ctx); err != nil || fired != 1 {
+		t.Fatalf("refire processOneShotJobs() = %d, %v, want 1 job", fired, err)
+	}
+
+	after, _ := q.GetStats(ctx)
+	if got := after["enqueued_total"] - before["enqueued_total"]; got != 1 {
+		t.Errorf("enqueued jobs = %d, want 1", got)
+	}
+	sched.redis.Del(ctx, oneShotKey, oneShotDataKey)
+}
+
+func TestScheduler_ProcessOneShotJobs_NotLeader(t *testing.T) {
+	sched := NewSchedulerWithConfig(nil, nil, zap.NewNop(), DefaultSchedulerConfig())
+
+	fired, err := sched.processOneShotJobs(context.Background())
+	if err != nil || fired != 0 {
+		t.Errorf("processOneShotJobs() = %d, %v, want nothing fired", fired, err)
+	}
+}
+
+func TestScheduler_DisableEnableJob(t *testing.T) {
+	sched, q, ctx := setupTestScheduler(t)
+	sched.isLeader = true
+	name := testutil.GenerateTestID()
+	defer sched.redis.SRem(ctx, disabledJobsKey, name)
+	defer sched.redis.HDel(ctx, lastTriggeredKey, name)
+
+	job := ScheduledJob{Name: name, Schedule: EveryMinute, JobType: "toggle-test"}
+	if err := sched.RegisterJob(job); err != nil {
+		t.Fatalf("RegisterJob() error = %v", err)
+	}
+	registered, _ := sched.getJob(name)
+
+	if err := sched.DisableJob(ctx, name); err != nil {
+		t.Fatalf("DisableJob() error = %v", err)
+	}
+	before, _ := q.GetStats(ctx)
+	sched.executeScheduledJob(ctx, registered)
+	after, _ := q.GetStats(ctx)
+	if got := after["enqueued_total"] - before["enqueued_total"]; got != 0 {
+		t.Errorf("disabled job enqueued %d jobs, want 0", got)
+	}
+
+	infos, err := sched.ListJobsWithState(ctx)
+	if err != nil {
+		t.Fatalf("ListJobsWithState() error = %v", err)
+	}
+	if len(infos) != 1 || infos[0].Enabled || infos[0].LastTriggeredAt != nil {
+		t.Errorf("ListJobsWithState() = %+v, want one disabled, never triggered job", infos)
+	}
+
+	if err := sched.EnableJob(ctx, name); err != nil {
+		t.Fatalf("EnableJob() error = %v", err)
+	}
+	sched.executeScheduledJob(ctx, registered)
+
+	infos, _ = sched.ListJobsWithState(ctx)
+	if len(infos) != 1 || !infos[0].Enabled || infos[0].LastTriggeredAt == nil {
+		t.Errorf("ListJobsWithState() = %+v, want one enabled, triggered job", infos)
+	}
+}
+
+func TestScheduler_TriggerNow(t *testing.T) {
+	sched, q, ctx := setupTestScheduler(t)
+	name := testutil.GenerateTestID()
+	defer sched.redis.SRem(ctx, disabledJobsKey, name)
+	defer sched.redis.HDel(ctx, lastTriggeredKey, name)
+
+	if err := sched.RegisterJob(ScheduledJob{Name: name, Schedule: MonthlyFirst, JobType: "trigger-test", Tags: []string{"t"}}); err != nil {
+		t.Fatalf("RegisterJob() error = %v", err)
+	}
+
+	// Disabled jobs may still be triggered, on instances that are not leader
+	if err := sched.DisableJob(ctx, name); err != nil {
+		t.Fatalf("DisableJob() error = %v", err)
+	}
+	jobID, err := sched.TriggerNow(ctx, name)
+	if err != nil {
+		t.Fatalf("TriggerNow() error = %v", err)
+	}
+
+	job, err := q.GetJob(ctx, jobID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if job.Type != "trigger-test" {
+		t.Errorf("job.Type = %q, want trigger-test", job.Type)
+	}
+
+	infos, _ := sched.ListJobsWithState(ctx)
+	if len(infos) != 1 || infos[0].LastTriggeredAt == nil {
+		t.Errorf("ListJobsWithState() = %+v, want a last trigger time", infos)
+	}
+}
+
+func TestScheduler_TriggerNow_SingletonRunning(t *testing.T) {
+	sched, _, ctx := setupTestScheduler(t)
+	name := testutil.GenerateTestID()
+
+	if err := sched.RegisterJob(ScheduledJob{Name: name, Schedule: EveryHour, JobType: "singleton", Singleton: true}); err != nil {
+		t.Fatalf("RegisterJob() error = %v", err)
+	}
+	if _, err := sched.AcquireSingletonLock(ctx, name, time.Minute); err != nil {
+		t.Fatalf("AcquireSingletonLock() error = %v", err)
+	}
+	defer sched.ReleaseSingletonLock(ctx, name)
+
+	if _, err := sched.TriggerNow(ctx, name); !errors.Is(err, ErrSingletonJobRunning) {
+		t.Errorf("TriggerNow() error = %v, want ErrSingletonJobRunning", err)
+	}
+}
+
+func TestScheduler_UnknownJob(t *testing.T) {
+	sched := NewSchedulerWithConfig(nil, nil, zap.NewNop(), DefaultSchedulerConfig())
+	ctx := context.Background()
+
+	if err := sched.DisableJob(ctx, "missing"); !errors.Is(err, ErrScheduledJobNotFound) {
+		t.Errorf("DisableJob() error = %v, want ErrScheduledJobNotFound", err)
+	}
+	if err := sched.EnableJob(ctx, "missing"); !errors.Is(err, ErrScheduledJobNotFound) {
+		t.Errorf("EnableJob() error = %v, want ErrScheduledJobNotFound", err)
+	}
+	if _, err := sched.TriggerNow(ctx, "missing"); !errors.Is(err, ErrScheduledJobNotFound) {
+		t.Errorf("TriggerNow() error = %v, want ErrScheduledJobNotFound", err)
+	}
+}
+
 func TestScheduler_ExecutionWindow(t *testing.T) {
 	sched, _, _ := setupTestScheduler(t)
 
@@ -362,6 +614,105 @@ func TestScheduler_GetRecentExecutions(t *testing.T) {
 	}
 }
 
+func TestScheduler_RegisterJob_InvalidTimezone(t *testing.T) {
+	sched := NewScheduler(nil, nil, testutil.NewTestLogger(t))
+
+	err := sched.RegisterJob(ScheduledJob{
+		Name:     "bad-tz",
+		Schedule: DailyMidnight,
+		JobType:  "test",
+		Timezone: "Mars/Olympus_Mons",
+	})
+	if err == nil {
+		t.Error("RegisterJob() should return error for unknown timezone")
+	}
+}
+
+func TestScheduler_GetNextRun_Timezone(t *testing.T) {
+	sched := NewScheduler(nil, nil, testutil.NewTestLogger(t))
+
+	if err := sched.RegisterJob(ScheduledJob{
+		Name:     "tz-job",
+		Schedule: "0 9 * * *",
+		JobType:  "test",
+		Timezone: "Asia/Tokyo",
+	}); err != nil {
+		t.Fatalf("RegisterJob() error = %v", err)
+	}
+
+	nextRun, err := sched.GetNextRun("tz-job")
+	if err != nil {
+		t.Fatalf("GetNextRun() error = %v", err)
+	}
+	if nextRun.Location() != time.UTC {
+		t.Errorf("NextRun location = %v, want UTC", nextRun.Location())
+	}
+	// 09:00 JST is 00:00 UTC
+	if nextRun.Hour() != 0 || nextRun.Minute() != 0 {
+		t.Errorf("NextRun = %v, want 00:00 UTC", nextRun)
+	}
+
+	infos := sched.ListJobs()
+	if len(infos) != 1 || infos[0].Timezone != "Asia/Tokyo" {
+		t.Fatalf("ListJobs() = %+v, want timezone Asia/Tokyo", infos)
+	}
+	if !infos[0].NextRun.Equal(nextRun) {
+		t.Errorf("ListJobs() NextRun = %v, want %v", infos[0].NextRun, nextRun)
+	}
+}
+
+func TestScheduler_DefaultTimezoneFromConfig(t *testing.T) {
+	config := DefaultSchedulerConfig()
+	config.Timezone = "Europe/Berlin"
+	sched := NewSchedulerWithConfig(nil, nil, testutil.NewTestLogger(t), config)
+
+	if err := sched.RegisterJob(ScheduledJob{Name: "cfg-tz", Schedule: EveryHour, JobType: "test"}); err != nil {
+		t.Fatalf("RegisterJob() error = %v", err)
+	}
+	if tz := sched.ListJobs()[0].Timezone; tz != "Europe/Berlin" {
+		t.Errorf("Timezone = %v, want Europe/Berlin", tz)
+	}
+}
+
+func TestParseSchedule_DSTSpringForward(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	schedule, err := parseSchedule("30 2 * * *", loc)
+	if err != nil {
+		t.Fatalf("parseSchedule() error = %v", err)
+	}
+
+	// 2:30 does not exist on 2024-03-10 in New York; the run is skipped to the next day
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, loc)
+	next := schedule.Next(from)
+	want := time.Date(2024, 3, 11, 2, 30, 0, 0, loc)
+	if !next.Equal(want) {
+		t.Errorf("Next() = %v, want %v", next, want)
+	}
+}
+
+func TestParseSchedule_DSTFallBack(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	schedule, err := parseSchedule("30 1 * * *", loc)
+	if err != nil {
+		t.Fatalf("parseSchedule() error = %v", err)
+	}
+
+	// 1:30 occurs twice on 2024-11-03; the job fires once, then moves to the next day
+	first := schedule.Next(time.Date(2024, 11, 3, 0, 0, 0, 0, loc))
+	second := schedule.Next(first)
+	if second.Day() != 4 {
+		t.Errorf("second Next() = %v, want 2024-11-04", second)
+	}
+}
+
 func TestScheduledJob_Struct(t *testing.T) {
 	job := ScheduledJob{
 		Name:      "struct-test",
@@ -2,12 +2,17 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
 // Service defines the interface for job operations
 type Service interface {
-	// Enqueue adds a job to the queue
+	// Enqueue adds a job to the queue. A duplicate suppressed within its
+	// uniqueness window (WithUniqueFor) returns the existing job's ID instead
+	// of an error.
 	Enqueue(ctx context.Context, jobType string, payload any, opts ...JobOption) (string, error)
 
 	// EnqueueAt schedules a job for a specific time
@@ -16,9 +21,20 @@ type Service interface {
 	// EnqueueIn schedules a job after a delay
 	EnqueueIn(ctx context.Context, jobType string, payload any, delay time.Duration, opts ...JobOption) (string, error)
 
+	// EnqueueBatch adds multiple jobs in a single round trip. The returned slice
+	// is in request order with nil entries for jobs that failed; when any job
+	// fails the error is a *BatchEnqueueError listing the failures by index.
+	EnqueueBatch(ctx context.Context, reqs []EnqueueRequest) ([]*JobPayload, error)
+
 	// GetJob retrieves a job by ID
 	GetJob(ctx context.Context, jobID string) (*JobPayload, error)
 
+	// GetJobResult retrieves the stored result of a completed job
+	GetJobResult(ctx context.Context, jobID string) (json.RawMessage, error)
+
+	// GetJobProgress retrieves the latest progress reported by a job
+	GetJobProgress(ctx context.Context, jobID string) (*JobProgress, error)
+
 	// CancelJob cancels a pending job
 	CancelJob(ctx context.Context, jobID string) error
 
@@ -28,9 +44,26 @@ type Service interface {
 	// GetQueueStats returns queue statistics
 	GetQueueStats(ctx context.Context) (*QueueStats, error)
 
+	// GetQueueBacklog reports how many jobs wait at a priority of a named
+	// queue and roughly how long a job added there waits
+	GetQueueBacklog(ctx context.Context, queue string, priority Priority) (*QueueBacklog, error)
+
+	// PauseJobType stops processing jobs of a type until resumed
+	PauseJobType(ctx context.Context, jobType string) error
+
+	// ResumeJobType resumes processing jobs of a paused type
+	ResumeJobType(ctx context.Context, jobType string) error
+
+	// SetWorkerConcurrency scales this instance's worker pool to n workers
+	SetWorkerConcurrency(ctx context.Context, n int) error
+
 	// GetDLQJobs returns jobs in the dead letter queue
 	GetDLQJobs(ctx context.Context, limit int) ([]*JobPayload, error)
 
+	// FindDLQJobs returns up to limit jobs in the dead letter queue that
+	// match filter
+	FindDLQJobs(ctx context.Context, filter DLQFilter, limit int) ([]*JobPayload, error)
+
 	// RetryDLQJob retries a job from the DLQ
 	RetryDLQJob(ctx context.Context, jobID string) error
 
@@ -38,6 +71,59 @@ type Service interface {
 	PurgeDLQ(ctx context.Context) error
 }
 
+// DLQFilter narrows a dead letter queue listing. Empty fields match any job.
+type DLQFilter struct {
+	Type          string // Exact job type
+	ErrorContains string // Case-insensitive substring of the last error
+}
+
+// Matches reports whether a job passes the filter
+func (f DLQFilter) Matches(job *JobPayload) bool {
+	if f.Type != "" && job.Type != f.Type {
+		return false
+	}
+	if f.ErrorContains != "" && !strings.Contains(strings.ToLower(job.LastError), strings.ToLower(f.ErrorContains)) {
+		return false
+	}
+	return true
+}
+
+// EnqueueRequest describes a single job in a batch enqueue
+type EnqueueRequest struct {
+	Type    string
+	Payload any
+	Options []JobOption
+}
+
+// BatchItemError reports why a single job in a batch was not enqueued
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+// BatchEnqueueError is returned by EnqueueBatch when one or more jobs fail
+type BatchEnqueueError struct {
+	Failures []BatchItemError
+}
+
+func (e *BatchEnqueueError) Error() string {
+	return fmt.Sprintf("%d job(s) in batch failed to enqueue", len(e.Failures))
+}
+
+// QueueBacklog is the backlog at one priority of a named queue
+type QueueBacklog struct {
+	Queue    string
+	Priority Priority
+	Depth    int64 // Jobs waiting at the priority
+	// ProcessingRate is the jobs per second this instance's workers finished
+	// over the last minute; zero when it has none to go by
+	ProcessingRate float64
+	// EstimatedWait is how long Depth jobs take at ProcessingRate. It ignores
+	// higher priorities and other instances, so it is only a rough guide, and
+	// is zero when ProcessingRate is.
+	EstimatedWait time.Duration
+}
+
 // QueueStats contains queue statistics
 type QueueStats struct {
 	Pending        int64            `json:"pending"`
@@ -47,17 +133,21 @@ type QueueStats struct {
 	Failed         int64            `json:"failed"`
 	Dead           int64            `json:"dead"`
 	QueueSizes     map[string]int64 `json:"queue_sizes"`
+	QueueDepths    map[string]int64 `json:"queue_depths"`
+	PausedTypes    []string         `json:"paused_types"`
 	WorkerStats    WorkerStats      `json:"worker_stats"`
 	SchedulerStats SchedulerStats   `json:"scheduler_stats"`
 }
 
 // WorkerStats contains worker pool statistics
 type WorkerStats struct {
-	Running       bool  `json:"running"`
-	ActiveWorkers int64 `json:"active_workers"`
-	Concurrency   int   `json:"concurrency"`
-	ProcessedJobs int64 `json:"processed_jobs"`
-	FailedJobs    int64 `json:"failed_jobs"`
+	Running            bool             `json:"running"`
+	ActiveWorkers      int64            `json:"active_workers"`
+	Concurrency        int              `json:"concurrency"`
+	CurrentConcurrency int              `json:"current_concurrency"`
+	ProcessedJobs      int64            `json:"processed_jobs"`
+	FailedJobs         int64            `json:"failed_jobs"`
+	ThrottledJobs      map[string]int64 `json:"throttled_jobs,omitempty"`
 }
 
 // SchedulerStats contains scheduler statistics
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// tracePropagator carries W3C trace context (traceparent, tracestate) and
+// baggage between the enqueuing request and the worker
+var tracePropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// InjectTraceContext stores the trace context of ctx in the job's metadata so
+// the worker executing the job can continue the trace. It does nothing if ctx
+// carries no span.
+func InjectTraceContext(ctx context.Context, job *JobPayload) {
+	carrier := propagation.MapCarrier{}
+	tracePropagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return
+	}
+	if job.Metadata == nil {
+		job.Metadata = make(map[string]string, len(carrier))
+	}
+	for k, v := range carrier {
+		job.Metadata[k] = v
+	}
+}
+
+// ExtractTraceContext returns ctx carrying the trace context stored in the
+// job's metadata by InjectTraceContext
+func ExtractTraceContext(ctx context.Context, job *JobPayload) context.Context {
+	if len(job.Metadata) == 0 {
+		return ctx
+	}
+	return tracePropagator.Extract(ctx, propagation.MapCarrier(job.Metadata))
+}
@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// SetConcurrency changes how many workers the pool runs. Extra workers start
+// immediately on a running pool; surplus workers finish their current job
+// before exiting. A stopped pool starts with the new count.
+func (p *WorkerPool) SetConcurrency(n int) error {
+	if n < 1 {
+		return jobs.ErrInvalidConcurrency
+	}
+
+	p.scaleMu.Lock()
+	defer p.scaleMu.Unlock()
+
+	previous := p.targetWorkers.Swap(int64(n))
+	if p.running.Load() {
+		p.spawnWorkers(p.runCtx)
+	}
+
+	p.logger.Info("Worker concurrency changed",
+		zap.Int64("from", previous),
+		zap.Int("to", n),
+	)
+	return nil
+}
+
+// spawnWorkers starts workers until the target count is running. Callers
+// must hold scaleMu.
+func (p *WorkerPool) spawnWorkers(ctx context.Context) {
+	for p.liveWorkers.Load() < p.targetWorkers.Load() {
+		p.liveWorkers.Add(1)
+		p.wg.Add(1)
+		go p.worker(ctx, int(p.nextWorkerID.Add(1)-1))
+	}
+}
+
+// retire reports whether the calling worker is surplus to the target count,
+// giving up its slot if so
+func (p *WorkerPool) retire() bool {
+	for {
+		live := p.liveWorkers.Load()
+		if live <= p.targetWorkers.Load() {
+			return false
+		}
+		if p.liveWorkers.CompareAndSwap(live, live-1) {
+			return true
+		}
+	}
+}
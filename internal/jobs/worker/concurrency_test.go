@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
+)
+
+func TestWorkerPool_Unit_SetConcurrency(t *testing.T) {
+	job, _ := jobs.NewJobPayload("slow", map[string]string{})
+	q := newFakeQueue()
+
+	config := DefaultWorkerPoolConfig()
+	config.Concurrency = 2
+	config.PollInterval = 5 * time.Millisecond
+	pool := newUnitTestPool(q, config)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	pool.RegisterHandler("slow", func(ctx context.Context, payload []byte) error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	if err := pool.SetConcurrency(0); !errors.Is(err, jobs.ErrInvalidConcurrency) {
+		t.Errorf("SetConcurrency(0) error = %v, want %v", err, jobs.ErrInvalidConcurrency)
+	}
+
+	ctx := context.Background()
+	if err := pool.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer pool.Stop(ctx)
+
+	if err := pool.SetConcurrency(4); err != nil {
+		t.Fatalf("SetConcurrency(4) error = %v", err)
+	}
+	if stats := pool.Stats(); stats.Concurrency != 4 || stats.CurrentConcurrency != 4 {
+		t.Errorf("Concurrency = %d, CurrentConcurrency = %d, want 4 and 4", stats.Concurrency, stats.CurrentConcurrency)
+	}
+
+	// Scale down while a job is running; it must finish, not be dropped
+	q.Enqueue(ctx, job)
+	<-started
+	if err := pool.SetConcurrency(1); err != nil {
+		t.Fatalf("SetConcurrency(1) error = %v", err)
+	}
+	testutil.WaitForCondition(t, 5*time.Second, func() bool {
+		return pool.Stats().CurrentConcurrency == 1
+	}, "surplus workers should retire")
+
+	close(release)
+	testutil.WaitForCondition(t, 5*time.Second, func() bool {
+		return pool.Stats().ProcessedJobs == 1
+	}, "in-flight job should complete")
+	if stats := pool.Stats(); stats.Concurrency != 1 || stats.CurrentConcurrency != 1 {
+		t.Errorf("Concurrency = %d, CurrentConcurrency = %d, want 1 and 1", stats.Concurrency, stats.CurrentConcurrency)
+	}
+}
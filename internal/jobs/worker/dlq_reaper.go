@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// dlqReaperBatchSize caps how many DLQ jobs are inspected per reaper pass
+const dlqReaperBatchSize = 1000
+
+// LeaderChecker reports whether this instance currently holds leadership
+type LeaderChecker interface {
+	IsLeader() bool
+}
+
+// dlqReaper periodically retries dead jobs whose backoff has elapsed
+func (p *WorkerPool) dlqReaper(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.DLQReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !p.leader.IsLeader() {
+				continue
+			}
+			retried, err := p.reapDLQ(ctx, time.Now())
+			if err != nil {
+				p.logger.Error("Failed to reap DLQ", zap.Error(err))
+			} else if retried > 0 {
+				p.logger.Info("Retried jobs from DLQ", zap.Int("count", retried))
+			}
+		}
+	}
+}
+
+// reapDLQ re-enqueues DLQ jobs whose FailedAt + DLQBackoff is before now.
+// A job's backoff is computed when the reaper first sees it and stored on the
+// job record, growing with each DLQ retry. Returns the number of jobs retried.
+func (p *WorkerPool) reapDLQ(ctx context.Context, now time.Time) (int, error) {
+	deadJobs, err := p.queue.GetDLQJobs(ctx, dlqReaperBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	retried := 0
+	for _, job := range deadJobs {
+		if job.DLQRetries >= p.config.MaxDLQRetries || job.FailedAt == nil {
+			continue
+		}
+
+		logger := p.logger.With(zap.String("job_id", job.ID), zap.String("job_type", job.Type))
+
+		if job.DLQBackoff == 0 {
+			job.DLQBackoff = resilience.ExponentialBackoff(job.DLQRetries+1, p.config.DLQRetryBackoff, p.config.DLQRetryMaxBackoff)
+			if err := p.queue.UpdateJob(ctx, job); err != nil {
+				logger.Warn("Failed to store DLQ backoff", zap.Error(err))
+				continue
+			}
+		}
+		if now.Before(job.FailedAt.Add(job.DLQBackoff)) {
+			continue
+		}
+
+		job.DLQRetries++
+		if err := p.queue.UpdateJob(ctx, job); err != nil {
+			logger.Warn("Failed to record DLQ retry", zap.Error(err))
+			continue
+		}
+		if err := p.queue.RetryDLQJob(ctx, job.ID); err != nil {
+			logger.Warn("Failed to retry DLQ job", zap.Error(err))
+			continue
+		}
+		logger.Debug("Retried job from DLQ", zap.Int("dlq_retries", job.DLQRetries))
+		retried++
+	}
+
+	return retried, nil
+}
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+type fakeLeader bool
+
+func (l fakeLeader) IsLeader() bool { return bool(l) }
+
+func newDeadJob(t *testing.T, q *fakeQueue, failedAt time.Time, dlqRetries int) *jobs.JobPayload {
+	t.Helper()
+	job, _ := jobs.NewJobPayload("webhook", nil)
+	job.Status = jobs.JobStatusDead
+	job.FailedAt = &failedAt
+	job.DLQRetries = dlqRetries
+	q.stored[job.ID] = job
+	q.dead = append(q.dead, job.ID)
+	return job
+}
+
+func TestWorkerPool_Unit_ReapDLQ(t *testing.T) {
+	q := newFakeQueue()
+	config := DefaultWorkerPoolConfig()
+	config.MaxDLQRetries = 2
+	config.DLQRetryBackoff = time.Minute
+	config.DLQRetryMaxBackoff = time.Hour
+	pool := newUnitTestPool(q, config)
+
+	now := time.Now()
+	due := newDeadJob(t, q, now.Add(-time.Hour), 0)
+	recent := newDeadJob(t, q, now, 0)
+	exhausted := newDeadJob(t, q, now.Add(-24*time.Hour), 2)
+
+	retried, err := pool.reapDLQ(context.Background(), now)
+	if err != nil {
+		t.Fatalf("reapDLQ() error = %v", err)
+	}
+	if retried != 1 {
+		t.Errorf("retried = %d, want 1", retried)
+	}
+	if len(q.revived) != 1 || q.revived[0] != due.ID {
+		t.Errorf("revived = %v, want [%s]", q.revived, due.ID)
+	}
+	if due.DLQRetries != 1 {
+		t.Errorf("due.DLQRetries = %d, want 1", due.DLQRetries)
+	}
+
+	// The recent job has its backoff stored but is not yet retried
+	if recent.DLQBackoff < time.Minute || recent.DLQBackoff > 75*time.Second {
+		t.Errorf("recent.DLQBackoff = %v, want ~1m", recent.DLQBackoff)
+	}
+	if recent.DLQRetries != 0 {
+		t.Errorf("recent.DLQRetries = %d, want 0", recent.DLQRetries)
+	}
+
+	// Jobs that used up their DLQ retries are left alone
+	if exhausted.DLQBackoff != 0 || exhausted.DLQRetries != 2 {
+		t.Errorf("exhausted job was modified: backoff=%v retries=%d", exhausted.DLQBackoff, exhausted.DLQRetries)
+	}
+}
+
+func TestWorkerPool_Unit_ReapDLQ_BackoffGrows(t *testing.T) {
+	q := newFakeQueue()
+	config := DefaultWorkerPoolConfig()
+	config.DLQRetryBackoff = time.Minute
+	config.DLQRetryMaxBackoff = time.Hour
+	pool := newUnitTestPool(q, config)
+
+	now := time.Now()
+	job := newDeadJob(t, q, now, 2)
+
+	if _, err := pool.reapDLQ(context.Background(), now); err != nil {
+		t.Fatalf("reapDLQ() error = %v", err)
+	}
+	// Third DLQ retry waits 1m * 2^2
+	if job.DLQBackoff < 4*time.Minute || job.DLQBackoff > 5*time.Minute {
+		t.Errorf("DLQBackoff = %v, want ~4m", job.DLQBackoff)
+	}
+}
+
+func TestWorkerPool_Unit_DLQReaperRequiresLeader(t *testing.T) {
+	q := newFakeQueue()
+	newDeadJob(t, q, time.Now().Add(-time.Hour), 0)
+
+	config := DefaultWorkerPoolConfig()
+	config.DLQReaperInterval = 10 * time.Millisecond
+	config.DLQRetryBackoff = time.Millisecond
+	pool := newUnitTestPool(q, config)
+	pool.SetLeaderChecker(fakeLeader(false))
+
+	ctx := context.Background()
+	if err := pool.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	pool.Stop(ctx)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.revived) != 0 {
+		t.Errorf("revived = %v, want none on a non-leader", q.revived)
+	}
+}
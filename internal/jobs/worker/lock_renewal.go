@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs/lock"
+)
+
+// errLockLost is the cause of a handler's context being cancelled because
+// its job's lock was lost
+var errLockLost = errors.New("job lock lost")
+
+// cancelOnLockLoss returns a context that is cancelled with errLockLost as soon
+// as the job's lock is lost, so the handler stops instead of running on while
+// another worker takes the job over. The lock itself is kept alive by its
+// heartbeat (see lock.LockManager.AcquireLock). The returned function stops
+// watching the lock.
+func cancelOnLockLoss(ctx context.Context, jobLock *lock.JobLock) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	go func() {
+		select {
+		case <-jobLock.Lost():
+			cancel(errLockLost)
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() { cancel(nil) }
+}
+
+// recordLockRenewFailure counts and logs a failed lock heartbeat
+func (p *WorkerPool) recordLockRenewFailure(jobID string, err error) {
+	jobs.GlobalMetrics.RecordLockRenewalFailure()
+	logger := p.logger.With(zap.String("job_id", jobID))
+	if errors.Is(err, lock.ErrLockNotHeld) {
+		logger.Error("Lost job lock, cancelling the job's handler")
+		return
+	}
+	logger.Warn("Failed to renew job lock", zap.Error(err))
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PauseType stops processing jobs of a type across all workers sharing the queue.
+// The queue does not hand out jobs of a paused type; they stay queued in place
+// and run once the type is resumed.
+func (p *WorkerPool) PauseType(ctx context.Context, jobType string) error {
+	if err := p.queue.PauseType(ctx, jobType); err != nil {
+		return err
+	}
+	p.setPaused(jobType, true)
+	p.logger.Info("Paused job type", zap.String("type", jobType))
+	return nil
+}
+
+// ResumeType resumes processing jobs of a previously paused type
+func (p *WorkerPool) ResumeType(ctx context.Context, jobType string) error {
+	if err := p.queue.ResumeType(ctx, jobType); err != nil {
+		return err
+	}
+	p.setPaused(jobType, false)
+	p.logger.Info("Resumed job type", zap.String("type", jobType))
+	return nil
+}
+
+// IsPaused reports whether jobs of a type are currently paused
+func (p *WorkerPool) IsPaused(jobType string) bool {
+	p.pausedMu.RLock()
+	defer p.pausedMu.RUnlock()
+	return p.paused[jobType]
+}
+
+func (p *WorkerPool) setPaused(jobType string, paused bool) {
+	p.pausedMu.Lock()
+	defer p.pausedMu.Unlock()
+	if paused {
+		p.paused[jobType] = true
+	} else {
+		delete(p.paused, jobType)
+	}
+}
+
+// refreshPaused reloads the paused job types from the queue so pauses issued
+// by other instances take effect here
+func (p *WorkerPool) refreshPaused(ctx context.Context) {
+	types, err := p.queue.PausedTypes(ctx)
+	if err != nil {
+		p.logger.Warn("Failed to load paused job types", zap.Error(err))
+		return
+	}
+
+	paused := make(map[string]bool, len(types))
+	for _, jobType := range types {
+		paused[jobType] = true
+	}
+
+	p.pausedMu.Lock()
+	p.paused = paused
+	p.pausedMu.Unlock()
+}
+
+// pauseWatcher periodically refreshes the paused job types
+func (p *WorkerPool) pauseWatcher(ctx context.Context) {
+	defer p.wg.Done()
+
+	interval := p.config.PauseRefresh
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.refreshPaused(ctx)
+		}
+	}
+}
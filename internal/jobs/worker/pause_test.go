@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+func TestWorkerPool_Unit_PausedTypeStaysQueued(t *testing.T) {
+	webhook, _ := jobs.NewJobPayload("webhook", map[string]string{})
+	q := newFakeQueue(webhook)
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+
+	var calls int
+	pool.RegisterHandler("webhook", func(ctx context.Context, payload []byte) error {
+		calls++
+		return nil
+	})
+
+	ctx := context.Background()
+	if err := pool.PauseType(ctx, "webhook"); err != nil {
+		t.Fatalf("PauseType() error = %v", err)
+	}
+	if !pool.IsPaused("webhook") {
+		t.Fatal("webhook should be paused")
+	}
+
+	pool.processNextJob(ctx, zap.NewNop())
+
+	if calls != 0 {
+		t.Errorf("handler calls = %d, want 0 while paused", calls)
+	}
+	if len(q.pending) != 1 || q.pending[0].ID != webhook.ID {
+		t.Errorf("pending = %v, want the webhook job still queued", q.pending)
+	}
+	if webhook.Attempts != 0 {
+		t.Errorf("Attempts = %d, want 0 (a paused job is not dequeued)", webhook.Attempts)
+	}
+
+	if err := pool.ResumeType(ctx, "webhook"); err != nil {
+		t.Fatalf("ResumeType() error = %v", err)
+	}
+	pool.processNextJob(ctx, zap.NewNop())
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1 after resume", calls)
+	}
+}
+
+func TestWorkerPool_Unit_RefreshPaused(t *testing.T) {
+	q := newFakeQueue()
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+
+	// Paused by another instance
+	q.PauseType(context.Background(), "report")
+	if pool.IsPaused("report") {
+		t.Fatal("pause should not be visible before refresh")
+	}
+
+	pool.refreshPaused(context.Background())
+	if !pool.IsPaused("report") {
+		t.Error("report should be paused after refresh")
+	}
+
+	q.ResumeType(context.Background(), "report")
+	pool.refreshPaused(context.Background())
+	if pool.IsPaused("report") {
+		t.Error("report should be resumed after refresh")
+	}
+}
@@ -2,11 +2,14 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
@@ -18,13 +21,40 @@ type JobHandler func(ctx context.Context, payload []byte) error
 
 // WorkerPoolConfig configures the worker pool
 type WorkerPoolConfig struct {
-	Concurrency        int           // Number of concurrent workers
-	PollInterval       time.Duration // How often to poll for jobs
-	ShutdownTimeout    time.Duration // Timeout for graceful shutdown
-	EnableLocking      bool          // Enable distributed locking
-	EnableIdempotency  bool          // Enable idempotency checks
-	StaleJobCleanup    time.Duration // Interval for cleaning stale jobs
-	StaleJobThreshold  time.Duration // Time after which a job is considered stale
+	Concurrency       int           // Number of concurrent workers
+	PollInterval      time.Duration // How often to poll for jobs
+	ShutdownTimeout   time.Duration // Timeout for graceful shutdown
+	EnableLocking     bool          // Enable distributed locking
+	EnableIdempotency bool          // Enable idempotency checks
+	StaleJobCleanup   time.Duration // Interval for cleaning stale jobs
+	StaleJobThreshold time.Duration // Time after which a job is considered stale
+	EnableProgress    bool          // Give handlers a progress reporter backed by the queue
+	ProgressTTL       time.Duration // How long progress stays readable after a job finishes
+	PauseRefresh      time.Duration // How often paused job types are reloaded from the queue
+	JobTimeout        time.Duration // Handler deadline for all job types; overrides the job's own timeout when > 0
+
+	// Queues binds the pool to named queues; it consumes only their jobs,
+	// taking turns between them. Empty consumes the default queue.
+	Queues []string
+
+	// MaxConcurrencyPerType caps how many jobs of a given type may run at once.
+	// Types without an entry (or with a value <= 0) are limited only by Concurrency,
+	// which remains the upper bound: a per-type limit above Concurrency has no effect.
+	// Jobs over their type limit are requeued without occupying a worker.
+	MaxConcurrencyPerType map[string]int
+
+	// DLQReaperInterval enables automatic retries out of the dead letter queue when > 0.
+	// The reaper only runs on the scheduler leader (see SetLeaderChecker).
+	DLQReaperInterval  time.Duration
+	MaxDLQRetries      int           // Automatic DLQ retries per job before it stays dead
+	DLQRetryBackoff    time.Duration // Wait after the first DLQ entry; doubles per retry
+	DLQRetryMaxBackoff time.Duration // Upper bound on the DLQ retry wait
+
+	// StuckJobCheck is how often running jobs are checked for missed heartbeats
+	// (0 disables the check). Requires locking, which maintains the heartbeats.
+	StuckJobCheck    time.Duration
+	StuckJobGrace    time.Duration // Heartbeat age after which a running job is stuck; between LockTTL/3 and StaleJobThreshold
+	RequeueStuckJobs bool          // Fail stuck jobs so they are retried (or dead-lettered) elsewhere
 }
 
 // DefaultWorkerPoolConfig returns sensible defaults
@@ -37,6 +67,14 @@ func DefaultWorkerPoolConfig() WorkerPoolConfig {
 		EnableIdempotency:  true,
 		StaleJobCleanup:    time.Minute,
 		StaleJobThreshold:  10 * time.Minute,
+		EnableProgress:     true,
+		ProgressTTL:        jobs.DefaultProgressTTL,
+		PauseRefresh:       2 * time.Second,
+		MaxDLQRetries:      3,
+		DLQRetryBackoff:    5 * time.Minute,
+		DLQRetryMaxBackoff: 6 * time.Hour,
+		StuckJobCheck:      time.Minute,
+		StuckJobGrace:      5 * time.Minute,
 	}
 }
 
@@ -45,15 +83,41 @@ type WorkerPool struct {
 	config      WorkerPoolConfig
 	queue       jobs.Queue
 	lockManager *lock.LockManager
+	leader      LeaderChecker
+	tracer      trace.Tracer
 	logger      *zap.Logger
 	handlers    map[string]JobHandler
 	mu          sync.RWMutex
 
+	// Per-type retry policies and timeouts, guarded by mu
+	retryPolicies map[string]RetryPolicy
+	timeouts      map[string]time.Duration
+
+	// Per-type concurrency
+	typeSlots   map[string]chan struct{}
+	throttled   map[string]throttledJob // jobs requeued by a type limit, by ID
+	throttledMu sync.Mutex
+
+	// Paused job types, cached from the queue
+	paused   map[string]bool
+	pausedMu sync.RWMutex
+
+	// Jobs flagged by the stuck-job monitor
+	stuck   map[string]StuckJob
+	stuckMu sync.RWMutex
+
 	// State
 	running atomic.Bool
 	wg      sync.WaitGroup
 	stopCh  chan struct{}
 
+	// Worker goroutines; scaleMu serializes starting them
+	runCtx        context.Context
+	scaleMu       sync.Mutex
+	targetWorkers atomic.Int64
+	liveWorkers   atomic.Int64
+	nextWorkerID  atomic.Int64
+
 	// Metrics
 	activeWorkers atomic.Int64
 	processedJobs atomic.Int64
@@ -63,18 +127,35 @@ type WorkerPool struct {
 
 // NewWorkerPool creates a new worker pool
 func NewWorkerPool(q jobs.Queue, logger *zap.Logger, config WorkerPoolConfig) *WorkerPool {
-	return &WorkerPool{
-		config:   config,
-		queue:    q,
-		logger:   logger,
-		handlers: make(map[string]JobHandler),
-		stopCh:   make(chan struct{}),
+	typeSlots := make(map[string]chan struct{})
+	for jobType, limit := range config.MaxConcurrencyPerType {
+		if limit > 0 {
+			typeSlots[jobType] = make(chan struct{}, limit)
+		}
+	}
+
+	p := &WorkerPool{
+		config:        config,
+		queue:         q,
+		tracer:        otel.Tracer(tracerName),
+		logger:        logger,
+		handlers:      make(map[string]JobHandler),
+		retryPolicies: make(map[string]RetryPolicy),
+		timeouts:      make(map[string]time.Duration),
+		typeSlots:     typeSlots,
+		throttled:     make(map[string]throttledJob),
+		paused:        make(map[string]bool),
+		stuck:         make(map[string]StuckJob),
+		stopCh:        make(chan struct{}),
 	}
+	p.targetWorkers.Store(int64(config.Concurrency))
+	return p
 }
 
 // SetLockManager sets the lock manager for distributed locking
 func (p *WorkerPool) SetLockManager(lm *lock.LockManager) {
 	p.lockManager = lm
+	lm.OnRenewFailure(p.recordLockRenewFailure)
 	p.logger.Info("Lock manager configured",
 		zap.String("worker_id", lm.GetWorkerID()),
 		zap.Bool("locking_enabled", p.config.EnableLocking),
@@ -82,14 +163,34 @@ func (p *WorkerPool) SetLockManager(lm *lock.LockManager) {
 	)
 }
 
+// SetLeaderChecker sets the leader election used to run fleet-wide singleton
+// tasks such as the DLQ reaper on exactly one instance
+func (p *WorkerPool) SetLeaderChecker(lc LeaderChecker) {
+	p.leader = lc
+}
+
 // RegisterHandler registers a handler for a job type
 func (p *WorkerPool) RegisterHandler(jobType string, handler JobHandler) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.handlers[jobType] = handler
+	jobs.GlobalMetrics.RegisterJobType(jobType)
 	p.logger.Info("Registered job handler", zap.String("type", jobType))
 }
 
+// Queue returns the queue the pool consumes from
+func (p *WorkerPool) Queue() jobs.Queue {
+	return p.queue
+}
+
+// GetHandler returns the handler registered for a job type
+func (p *WorkerPool) GetHandler(jobType string) (JobHandler, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	handler, ok := p.handlers[jobType]
+	return handler, ok
+}
+
 // Start starts the worker pool
 func (p *WorkerPool) Start(ctx context.Context) error {
 	if p.running.Load() {
@@ -98,17 +199,22 @@ func (p *WorkerPool) Start(ctx context.Context) error {
 
 	p.running.Store(true)
 	p.logger.Info("Starting worker pool",
-		zap.Int("concurrency", p.config.Concurrency),
+		zap.Int64("concurrency", p.targetWorkers.Load()),
 		zap.Duration("poll_interval", p.config.PollInterval),
 		zap.Bool("locking_enabled", p.config.EnableLocking && p.lockManager != nil),
 		zap.Bool("idempotency_enabled", p.config.EnableIdempotency && p.lockManager != nil),
 	)
 
+	// Load paused job types before taking any work
+	p.refreshPaused(ctx)
+	p.wg.Add(1)
+	go p.pauseWatcher(ctx)
+
 	// Start workers
-	for i := 0; i < p.config.Concurrency; i++ {
-		p.wg.Add(1)
-		go p.worker(ctx, i)
-	}
+	p.scaleMu.Lock()
+	p.runCtx = ctx
+	p.spawnWorkers(ctx)
+	p.scaleMu.Unlock()
 
 	// Start scheduled job processor
 	p.wg.Add(1)
@@ -118,6 +224,21 @@ func (p *WorkerPool) Start(ctx context.Context) error {
 	if p.config.EnableLocking && p.lockManager != nil {
 		p.wg.Add(1)
 		go p.staleJobCleaner(ctx)
+
+		if p.config.StuckJobCheck > 0 {
+			p.wg.Add(1)
+			go p.stuckJobMonitor(ctx)
+		}
+	}
+
+	// Start DLQ reaper if enabled
+	if p.config.DLQReaperInterval > 0 {
+		if p.leader == nil {
+			p.logger.Warn("DLQ reaper enabled without a leader checker, not starting")
+		} else {
+			p.wg.Add(1)
+			go p.dlqReaper(ctx)
+		}
 	}
 
 	return nil
@@ -159,7 +280,8 @@ func (p *WorkerPool) Stop(ctx context.Context) error {
 	return nil
 }
 
-// worker is a single worker goroutine
+// worker is a single worker goroutine. It exits between jobs when the pool
+// is scaled down below the number of running workers.
 func (p *WorkerPool) worker(ctx context.Context, id int) {
 	defer p.wg.Done()
 
@@ -176,13 +298,19 @@ func (p *WorkerPool) worker(ctx context.Context, id int) {
 			if p.running.Load() {
 				logger.Debug("Worker stopping")
 			}
+			p.liveWorkers.Add(-1)
 			return
 		case <-ctx.Done():
 			if p.running.Load() {
 				logger.Debug("Worker context cancelled")
 			}
+			p.liveWorkers.Add(-1)
 			return
 		case <-ticker.C:
+			if p.retire() {
+				logger.Debug("Worker retired by a concurrency change")
+				return
+			}
 			p.processNextJob(ctx, logger)
 		}
 	}
@@ -227,26 +355,66 @@ func (p *WorkerPool) acquireLock(ctx context.Context, job *jobs.JobPayload, logg
 	return jobLock, true
 }
 
-// executeJob runs the handler and records the outcome
-func (p *WorkerPool) executeJob(ctx context.Context, job *jobs.JobPayload, handler JobHandler, logger *zap.Logger) {
-	execCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+// executeJob runs the handler and records the outcome. When jobLock is set the
+// handler is cancelled if the lock is lost, and its failure is left to the
+// lock's new owner.
+func (p *WorkerPool) executeJob(ctx context.Context, job *jobs.JobPayload, handler JobHandler, jobLock *lock.JobLock, logger *zap.Logger) {
+	execCtx, span := p.startJobSpan(ctx, job)
+	execCtx = jobs.ContextWithJobID(execCtx, job.ID)
+	timeout := p.jobTimeout(job)
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		execCtx, cancel = context.WithTimeout(execCtx, timeout)
+	} else {
+		execCtx, cancel = context.WithCancel(execCtx)
+	}
 	defer cancel()
+	if jobLock != nil {
+		var stopWatch func()
+		execCtx, stopWatch = cancelOnLockLoss(execCtx, jobLock)
+		defer stopWatch()
+	}
+
+	if p.config.EnableProgress {
+		execCtx = jobs.ContextWithProgress(execCtx, jobs.NewProgressReporter(ctx, p.queue, job.ID))
+		defer func() {
+			if err := p.queue.ExpireProgress(ctx, job.ID, p.config.ProgressTTL); err != nil {
+				logger.Debug("Failed to expire job progress", zap.Error(err))
+			}
+		}()
+	}
 
 	start := time.Now()
 	err := handler(execCtx, job.Payload)
 	duration := time.Since(start)
+	if err != nil && errors.Is(execCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		// The handler gave up because its deadline passed; fail it as a timeout
+		// so it is retried like any other failure
+		err = fmt.Errorf("%w after %s: %w", jobs.ErrJobTimeout, timeout, err)
+		jobs.GlobalMetrics.RecordJobTimeout(job.Type)
+	}
+	endJobSpan(span, err)
 
+	if err != nil && errors.Is(context.Cause(execCtx), errLockLost) {
+		// Another worker may already be running the job; failing it here would
+		// interfere with that run
+		logger.Error("Job lock lost, leaving the job to its new owner", zap.Error(err), zap.Duration("duration", duration))
+		return
+	}
 	if err != nil {
 		logger.Error("Job failed", zap.Error(err), zap.Duration("duration", duration))
-		p.queue.Fail(ctx, job.ID, err)
+		retrying := p.failJob(ctx, job, err, logger)
 		p.failedJobs.Add(1)
-		jobs.GlobalMetrics.RecordJobFailed(job.Attempts < job.MaxRetries)
+		jobs.GlobalMetrics.RecordJobFailed(job.Type, retrying, duration)
+		if !retrying {
+			jobs.GlobalMetrics.RecordJobDead(job.Type)
+		}
 		return
 	}
 	logger.Info("Job completed", zap.Duration("duration", duration))
 	p.queue.Complete(ctx, job.ID)
 	p.processedJobs.Add(1)
-	jobs.GlobalMetrics.RecordJobCompleted(duration)
+	jobs.GlobalMetrics.RecordJobCompleted(job.Type, duration)
 	if p.config.EnableIdempotency && p.lockManager != nil && job.UniqueKey != "" {
 		if err := p.lockManager.MarkCompleted(ctx, job.UniqueKey, job.ID); err != nil {
 			logger.Warn("Failed to mark job as completed for idempotency", zap.Error(err))
@@ -254,9 +422,17 @@ func (p *WorkerPool) executeJob(ctx context.Context, job *jobs.JobPayload, handl
 	}
 }
 
+// dequeue takes the next job from the queues the pool is bound to
+func (p *WorkerPool) dequeue(ctx context.Context) (*jobs.JobPayload, error) {
+	if len(p.config.Queues) == 0 {
+		return p.queue.Dequeue(ctx)
+	}
+	return p.queue.DequeueFrom(ctx, p.config.Queues)
+}
+
 // processNextJob attempts to process the next available job
 func (p *WorkerPool) processNextJob(ctx context.Context, logger *zap.Logger) {
-	job, err := p.queue.Dequeue(ctx)
+	job, err := p.dequeue(ctx)
 	if err == jobs.ErrQueueEmpty {
 		return
 	}
@@ -273,10 +449,20 @@ func (p *WorkerPool) processNextJob(ctx context.Context, logger *zap.Logger) {
 		zap.Int("attempt", job.Attempts),
 	)
 
+	p.clearThrottled(job)
+
 	if p.checkIdempotency(ctx, job, logger) {
 		return
 	}
 
+	if !p.acquireTypeSlot(job.Type) {
+		logger.Debug("Job type at concurrency limit, requeueing")
+		p.markThrottled(job)
+		p.requeueJob(ctx, job, logger)
+		return
+	}
+	defer p.releaseTypeSlot(job.Type)
+
 	jobLock, acquired := p.acquireLock(ctx, job, logger)
 	if !acquired {
 		return
@@ -295,19 +481,99 @@ func (p *WorkerPool) processNextJob(ctx context.Context, logger *zap.Logger) {
 
 	logger.Info("Processing job")
 
-	p.mu.RLock()
-	handler, ok := p.handlers[job.Type]
-	p.mu.RUnlock()
+	handler, ok := p.GetHandler(job.Type)
 
 	if !ok {
 		logger.Error("No handler registered for job type")
 		p.queue.Fail(ctx, job.ID, fmt.Errorf("no handler for job type: %s", job.Type))
 		p.failedJobs.Add(1)
-		jobs.GlobalMetrics.RecordJobFailed(false)
+		jobs.GlobalMetrics.RecordJobFailed(job.Type, false, 0)
 		return
 	}
 
-	p.executeJob(ctx, job, handler, logger)
+	if jobLock != nil {
+		ctx = jobs.ContextWithFencingToken(ctx, jobLock.Token())
+	}
+	p.executeJob(ctx, job, handler, jobLock, logger)
+}
+
+// acquireTypeSlot reserves a per-type concurrency slot without blocking;
+// returns false when the job type is at its limit
+func (p *WorkerPool) acquireTypeSlot(jobType string) bool {
+	slots, ok := p.typeSlots[jobType]
+	if !ok {
+		return true
+	}
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseTypeSlot frees a slot reserved by acquireTypeSlot
+func (p *WorkerPool) releaseTypeSlot(jobType string) {
+	if slots, ok := p.typeSlots[jobType]; ok {
+		<-slots
+	}
+}
+
+// throttledTTL is how long a throttled job stays counted without being
+// throttled again. A job that was cancelled, expired or run by another
+// instance is never dequeued here again, so its entry lapses.
+const throttledTTL = 5 * time.Minute
+
+// throttledJob is a job requeued by its type limit
+type throttledJob struct {
+	jobType  string
+	markedAt time.Time
+}
+
+// markThrottled records a job as waiting on its type limit
+func (p *WorkerPool) markThrottled(job *jobs.JobPayload) {
+	p.throttledMu.Lock()
+	defer p.throttledMu.Unlock()
+	now := time.Now()
+	p.sweepThrottled(now)
+	if _, ok := p.throttled[job.ID]; !ok {
+		jobs.GlobalMetrics.RecordJobThrottled(job.Type, 1)
+	}
+	p.throttled[job.ID] = throttledJob{jobType: job.Type, markedAt: now}
+}
+
+// clearThrottled removes a job from the throttled set once it is dequeued again
+func (p *WorkerPool) clearThrottled(job *jobs.JobPayload) {
+	p.throttledMu.Lock()
+	defer p.throttledMu.Unlock()
+	if throttled, ok := p.throttled[job.ID]; ok {
+		delete(p.throttled, job.ID)
+		jobs.GlobalMetrics.RecordJobThrottled(throttled.jobType, -1)
+	}
+}
+
+// sweepThrottled drops jobs not throttled again within throttledTTL; the
+// caller holds throttledMu
+func (p *WorkerPool) sweepThrottled(now time.Time) {
+	for jobID, throttled := range p.throttled {
+		if now.Sub(throttled.markedAt) > throttledTTL {
+			delete(p.throttled, jobID)
+			jobs.GlobalMetrics.RecordJobThrottled(throttled.jobType, -1)
+		}
+	}
+}
+
+// ThrottledByType returns the number of jobs currently held back by per-type limits
+func (p *WorkerPool) ThrottledByType() map[string]int64 {
+	p.throttledMu.Lock()
+	defer p.throttledMu.Unlock()
+	p.sweepThrottled(time.Now())
+
+	result := make(map[string]int64)
+	for _, throttled := range p.throttled {
+		result[throttled.jobType]++
+	}
+	return result
 }
 
 // requeueJob re-queues a job that couldn't be processed
@@ -323,7 +589,7 @@ func (p *WorkerPool) requeueJob(ctx context.Context, job *jobs.JobPayload, logge
 	}
 
 	// Add back to queue
-	queueKey := job.Priority.QueueName()
+	queueKey := job.QueueKey()
 	if err := p.queue.RequeueJob(ctx, job.ID, queueKey); err != nil {
 		logger.Error("Failed to requeue job", zap.Error(err))
 	}
@@ -383,12 +649,14 @@ func (p *WorkerPool) staleJobCleaner(ctx context.Context) {
 // Stats returns worker pool statistics
 func (p *WorkerPool) Stats() jobs.WorkerPoolStats {
 	stats := jobs.WorkerPoolStats{
-		Running:       p.running.Load(),
-		ActiveWorkers: p.activeWorkers.Load(),
-		ProcessedJobs: p.processedJobs.Load(),
-		FailedJobs:    p.failedJobs.Load(),
-		SkippedJobs:   p.skippedJobs.Load(),
-		Concurrency:   p.config.Concurrency,
+		Running:            p.running.Load(),
+		ActiveWorkers:      p.activeWorkers.Load(),
+		ProcessedJobs:      p.processedJobs.Load(),
+		FailedJobs:         p.failedJobs.Load(),
+		SkippedJobs:        p.skippedJobs.Load(),
+		Concurrency:        int(p.targetWorkers.Load()),
+		CurrentConcurrency: int(p.liveWorkers.Load()),
+		ThrottledJobs:      p.ThrottledByType(),
 	}
 
 	if p.lockManager != nil {
@@ -500,6 +500,56 @@ func TestWorkerPool_DisableIdempotency(t *testing.T) {
 	}, "Job should be processed even with idempotency disabled")
 }
 
+func TestWorkerPool_LockLossCancelsHandler(t *testing.T) {
+	testutil.SkipIfNoRedis(t)
+	config := testutil.DefaultTestConfig()
+	client := testutil.NewTestRedisClient(t, config)
+	q := queue.NewRedisQueue(client)
+	lmConfig := lock.DefaultLockManagerConfig()
+	lmConfig.LockTTL = 5 * time.Second
+	lmConfig.HeartbeatRate = 200 * time.Millisecond
+	lm := lock.NewLockManager(client, lmConfig)
+	logger := testutil.NewTestLogger(t)
+
+	poolConfig := DefaultWorkerPoolConfig()
+	poolConfig.Concurrency = 1
+	poolConfig.PollInterval = 50 * time.Millisecond
+
+	pool := NewWorkerPool(q, logger, poolConfig)
+	pool.SetLockManager(lm)
+
+	started := make(chan struct{})
+	var cancelled atomic.Bool
+	pool.RegisterHandler("lock-loss-test", func(ctx context.Context, payload []byte) error {
+		close(started)
+		<-ctx.Done()
+		cancelled.Store(errors.Is(context.Cause(ctx), errLockLost))
+		return ctx.Err()
+	})
+
+	ctx := context.Background()
+	pool.Start(ctx)
+	defer pool.Stop(context.Background())
+
+	job, _ := jobs.NewJobPayload("lock-loss-test", nil)
+	q.Enqueue(ctx, job)
+
+	select {
+	case <-started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("handler did not start")
+	}
+
+	// Another worker takes the job over
+	lockKey := "arcana:jobs:lock:" + job.ID
+	client.Set(ctx, lockKey, "other-worker:1", time.Minute)
+	defer client.Del(ctx, lockKey)
+
+	testutil.WaitForCondition(t, 5*time.Second, func() bool {
+		return cancelled.Load()
+	}, "handler should be cancelled once the lock is lost")
+}
+
 func TestWorkerPoolStats_Struct(t *testing.T) {
 	stats := jobs.WorkerPoolStats{
 		Running:       true,
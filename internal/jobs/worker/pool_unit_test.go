@@ -0,0 +1,439 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// fakeQueue is an in-memory Queue for unit testing without Redis
+type fakeQueue struct {
+	mu       sync.Mutex
+	pending  []*jobs.JobPayload
+	stored   map[string]*jobs.JobPayload
+	requeued []string
+	done     []string
+	failed   map[string]error
+	retries  map[string]time.Duration
+	dead     []string
+	revived  []string // DLQ jobs retried via RetryDLQJob
+	progress map[string]jobs.JobProgress
+	expired  map[string]time.Duration // progress TTLs set via ExpireProgress
+	paused   map[string]bool
+}
+
+func newFakeQueue(pending ...*jobs.JobPayload) *fakeQueue {
+	q := &fakeQueue{
+		stored:   make(map[string]*jobs.JobPayload),
+		failed:   make(map[string]error),
+		retries:  make(map[string]time.Duration),
+		progress: make(map[string]jobs.JobProgress),
+		expired:  make(map[string]time.Duration),
+		paused:   make(map[string]bool),
+	}
+	for _, job := range pending {
+		q.pending = append(q.pending, job)
+		q.stored[job.ID] = job
+	}
+	return q
+}
+
+func (q *fakeQueue) Enqueue(ctx context.Context, job *jobs.JobPayload) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, job)
+	q.stored[job.ID] = job
+	return nil
+}
+func (q *fakeQueue) EnqueueBatch(ctx context.Context, batch []*jobs.JobPayload) ([]error, error) {
+	errs := make([]error, len(batch))
+	for i, job := range batch {
+		errs[i] = q.Enqueue(ctx, job)
+	}
+	return errs, nil
+}
+func (q *fakeQueue) Dequeue(ctx context.Context, priorities ...jobs.Priority) (*jobs.JobPayload, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, job := range q.pending {
+		if !q.paused[job.Type] {
+			q.pending = append(q.pending[:i:i], q.pending[i+1:]...)
+			job.Attempts++
+			return job, nil
+		}
+	}
+	return nil, jobs.ErrQueueEmpty
+}
+func (q *fakeQueue) DequeueFrom(ctx context.Context, queues []string) (*jobs.JobPayload, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, job := range q.pending {
+		if slices.Contains(queues, job.QueueName()) && !q.paused[job.Type] {
+			q.pending = append(q.pending[:i:i], q.pending[i+1:]...)
+			job.Attempts++
+			return job, nil
+		}
+	}
+	return nil, jobs.ErrQueueEmpty
+}
+func (q *fakeQueue) GetJob(ctx context.Context, jobID string) (*jobs.JobPayload, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if job, ok := q.stored[jobID]; ok {
+		return job, nil
+	}
+	return nil, jobs.ErrJobNotFound
+}
+func (q *fakeQueue) UpdateJob(ctx context.Context, job *jobs.JobPayload) error { return nil }
+func (q *fakeQueue) Complete(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.done = append(q.done, jobID)
+	return nil
+}
+func (q *fakeQueue) Fail(ctx context.Context, jobID string, jobErr error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.failed[jobID] = jobErr
+	return nil
+}
+func (q *fakeQueue) ScheduleRetry(ctx context.Context, jobID string, jobErr error, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.failed[jobID] = jobErr
+	q.retries[jobID] = delay
+	return nil
+}
+func (q *fakeQueue) MoveToDLQ(ctx context.Context, jobID string, jobErr error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.failed[jobID] = jobErr
+	q.dead = append(q.dead, jobID)
+	return nil
+}
+func (q *fakeQueue) ProcessScheduled(ctx context.Context) (int, error) { return 0, nil }
+func (q *fakeQueue) GetDLQJobs(ctx context.Context, limit int64) ([]*jobs.JobPayload, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var dead []*jobs.JobPayload
+	for _, id := range q.dead {
+		dead = append(dead, q.stored[id])
+	}
+	return dead, nil
+}
+func (q *fakeQueue) RetryDLQJob(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.revived = append(q.revived, jobID)
+	return nil
+}
+func (q *fakeQueue) DeleteJob(ctx context.Context, jobID string) error { return nil }
+func (q *fakeQueue) RequeueJob(ctx context.Context, jobID string, queueKey string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.requeued = append(q.requeued, jobID)
+	q.pending = append(q.pending, q.stored[jobID])
+	return nil
+}
+func (q *fakeQueue) GetStats(ctx context.Context) (map[string]int64, error) {
+	return map[string]int64{}, nil
+}
+func (q *fakeQueue) DepthByPriority(ctx context.Context, queue string) (map[jobs.Priority]int64, error) {
+	return map[jobs.Priority]int64{}, nil
+}
+func (q *fakeQueue) SaveResult(ctx context.Context, jobID string, result json.RawMessage, ttl time.Duration) error {
+	return nil
+}
+func (q *fakeQueue) GetResult(ctx context.Context, jobID string) (json.RawMessage, error) {
+	return nil, jobs.ErrResultNotFound
+}
+func (q *fakeQueue) SetProgress(ctx context.Context, jobID string, progress jobs.JobProgress) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.progress[jobID] = progress
+	return nil
+}
+func (q *fakeQueue) GetProgress(ctx context.Context, jobID string) (*jobs.JobProgress, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if progress, ok := q.progress[jobID]; ok {
+		return &progress, nil
+	}
+	return nil, jobs.ErrProgressNotFound
+}
+func (q *fakeQueue) ExpireProgress(ctx context.Context, jobID string, ttl time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expired[jobID] = ttl
+	return nil
+}
+func (q *fakeQueue) PauseType(ctx context.Context, jobType string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.paused[jobType] = true
+	return nil
+}
+func (q *fakeQueue) ResumeType(ctx context.Context, jobType string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.paused, jobType)
+	return nil
+}
+func (q *fakeQueue) PausedTypes(ctx context.Context) ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var types []string
+	for jobType := range q.paused {
+		types = append(types, jobType)
+	}
+	return types, nil
+}
+
+func newUnitTestPool(q jobs.Queue, config WorkerPoolConfig) *WorkerPool {
+	config.EnableLocking = false
+	config.EnableIdempotency = false
+	return NewWorkerPool(q, zap.NewNop(), config)
+}
+
+func TestWorkerPool_Unit_TypeSlots(t *testing.T) {
+	config := DefaultWorkerPoolConfig()
+	config.MaxConcurrencyPerType = map[string]int{"report": 2, "email": 0}
+	pool := newUnitTestPool(newFakeQueue(), config)
+
+	if _, ok := pool.typeSlots["email"]; ok {
+		t.Error("non-positive limit should not create a semaphore")
+	}
+
+	if !pool.acquireTypeSlot("report") || !pool.acquireTypeSlot("report") {
+		t.Fatal("first two report slots should be acquired")
+	}
+	if pool.acquireTypeSlot("report") {
+		t.Error("third report slot should not be acquired")
+	}
+	if !pool.acquireTypeSlot("email") {
+		t.Error("unlimited type should always acquire")
+	}
+
+	pool.releaseTypeSlot("report")
+	if !pool.acquireTypeSlot("report") {
+		t.Error("slot should be available after release")
+	}
+}
+
+func TestWorkerPool_Unit_ThrottledJobIsRequeued(t *testing.T) {
+	job, _ := jobs.NewJobPayload("report", map[string]string{})
+	q := newFakeQueue(job)
+
+	config := DefaultWorkerPoolConfig()
+	config.MaxConcurrencyPerType = map[string]int{"report": 1}
+	pool := newUnitTestPool(q, config)
+
+	var calls int
+	pool.RegisterHandler("report", func(ctx context.Context, payload []byte) error {
+		calls++
+		return nil
+	})
+
+	// Occupy the only report slot, as if another report were running
+	pool.acquireTypeSlot("report")
+
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	if calls != 0 {
+		t.Errorf("handler calls = %d, want 0 while throttled", calls)
+	}
+	if len(q.requeued) != 1 || q.requeued[0] != job.ID {
+		t.Errorf("requeued = %v, want [%s]", q.requeued, job.ID)
+	}
+	if job.Attempts != 0 {
+		t.Errorf("Attempts = %d, want 0 (throttling is not an attempt)", job.Attempts)
+	}
+	if got := pool.Stats().ThrottledJobs["report"]; got != 1 {
+		t.Errorf("ThrottledJobs[report] = %d, want 1", got)
+	}
+
+	// Free the slot; the job should now run and leave the throttled set
+	pool.releaseTypeSlot("report")
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+	if got := pool.Stats().ThrottledJobs["report"]; got != 0 {
+		t.Errorf("ThrottledJobs[report] = %d, want 0", got)
+	}
+	if len(pool.typeSlots["report"]) != 0 {
+		t.Error("report slot should be released after the job finished")
+	}
+}
+
+func TestWorkerPool_Unit_ThrottledJobLapses(t *testing.T) {
+	pool := newUnitTestPool(newFakeQueue(), DefaultWorkerPoolConfig())
+	cancelled, _ := jobs.NewJobPayload("report", map[string]string{})
+	waiting, _ := jobs.NewJobPayload("report", map[string]string{})
+
+	pool.markThrottled(cancelled)
+	pool.markThrottled(waiting)
+
+	// The cancelled job was throttled long ago and never dequeued again
+	pool.throttledMu.Lock()
+	pool.throttled[cancelled.ID] = throttledJob{jobType: "report", markedAt: time.Now().Add(-throttledTTL - time.Second)}
+	pool.throttledMu.Unlock()
+
+	if got := pool.ThrottledByType()["report"]; got != 1 {
+		t.Errorf("ThrottledByType()[report] = %d, want 1 after the cancelled job lapsed", got)
+	}
+	if _, ok := pool.throttled[waiting.ID]; !ok {
+		t.Error("recently throttled job should still be counted")
+	}
+}
+
+func TestWorkerPool_Unit_BoundQueues(t *testing.T) {
+	other, _ := jobs.NewJobPayload("report", map[string]string{})
+	mail, _ := jobs.NewJobPayload("report", map[string]string{}, jobs.WithQueue("mail"))
+	q := newFakeQueue(other, mail)
+
+	config := DefaultWorkerPoolConfig()
+	config.Queues = []string{"mail"}
+	pool := newUnitTestPool(q, config)
+
+	pool.RegisterHandler("report", func(ctx context.Context, payload []byte) error {
+		return nil
+	})
+
+	pool.processNextJob(context.Background(), zap.NewNop())
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	if len(q.done) != 1 || q.done[0] != mail.ID {
+		t.Errorf("done = %v, want only the mail job [%s]", q.done, mail.ID)
+	}
+	if len(q.pending) != 1 || q.pending[0].ID != other.ID {
+		t.Error("job on the default queue should be left for other pools")
+	}
+}
+
+func TestWorkerPool_Unit_JobTypeMetrics(t *testing.T) {
+	ok, _ := jobs.NewJobPayload("metrics-ok", map[string]string{})
+	bad, _ := jobs.NewJobPayload("metrics-bad", map[string]string{})
+	q := newFakeQueue(ok, bad)
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+	pool.RegisterHandler("metrics-ok", func(ctx context.Context, payload []byte) error {
+		return nil
+	})
+	pool.RegisterHandler("metrics-bad", func(ctx context.Context, payload []byte) error {
+		return errors.New("boom")
+	})
+	pool.SetRetryPolicy("metrics-bad", RetryPolicy{MaxAttempts: 1})
+
+	pool.processNextJob(context.Background(), zap.NewNop())
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	byType := jobs.GlobalMetrics.JobTypes()
+	if got := byType["metrics-ok"]; got.Succeeded != 1 || got.Failed != 0 || got.DurationCount != 1 {
+		t.Errorf("metrics-ok stats = %+v, want 1 success timed", got)
+	}
+	if got := byType["metrics-bad"]; got.Failed != 1 || got.Dead != 1 || got.Succeeded != 0 || got.DurationCount != 1 {
+		t.Errorf("metrics-bad stats = %+v, want 1 dead-lettered failure timed", got)
+	}
+}
+
+func TestWorkerPool_Unit_ProgressReporting(t *testing.T) {
+	job, _ := jobs.NewJobPayload("report", map[string]string{})
+	q := newFakeQueue(job)
+	config := DefaultWorkerPoolConfig()
+	config.ProgressTTL = time.Minute
+	pool := newUnitTestPool(q, config)
+	pool.RegisterHandler("report", func(ctx context.Context, payload []byte) error {
+		jobs.ProgressFromContext(ctx).Set(75, "rendering")
+		return nil
+	})
+
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	progress, err := q.GetProgress(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("GetProgress() error = %v", err)
+	}
+	if progress.Percent != 75 || progress.Message != "rendering" {
+		t.Errorf("progress = %+v, want 75%% rendering", progress)
+	}
+	if ttl := q.expired[job.ID]; ttl != time.Minute {
+		t.Errorf("progress TTL = %v, want 1m after completion", ttl)
+	}
+}
+
+func TestWorkerPool_Unit_ProgressDisabled(t *testing.T) {
+	job, _ := jobs.NewJobPayload("report", map[string]string{})
+	q := newFakeQueue(job)
+	config := DefaultWorkerPoolConfig()
+	config.EnableProgress = false
+	pool := newUnitTestPool(q, config)
+	pool.RegisterHandler("report", func(ctx context.Context, payload []byte) error {
+		jobs.ProgressFromContext(ctx).Set(75, "rendering")
+		return nil
+	})
+
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	if _, err := q.GetProgress(context.Background(), job.ID); err != jobs.ErrProgressNotFound {
+		t.Errorf("GetProgress() error = %v, want jobs.ErrProgressNotFound", err)
+	}
+}
+
+func TestWorkerPool_Unit_JobSpanContinuesEnqueuingTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "POST /api/v1/jobs")
+	job, _ := jobs.NewJobPayload("report", map[string]string{})
+	jobs.InjectTraceContext(parentCtx, job)
+	parent.End()
+
+	q := newFakeQueue(job)
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+	pool.SetTracerProvider(tp)
+	var handlerSpan trace.SpanContext
+	pool.RegisterHandler("report", func(ctx context.Context, payload []byte) error {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return errors.New("render failed")
+	})
+
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	var span sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "report" {
+			span = s
+		}
+	}
+	if span == nil {
+		t.Fatal("no span recorded for the job")
+	}
+	if span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Error("job span should continue the enqueuing trace")
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("job span should be a child of the enqueuing span")
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("handler context should carry the job span")
+	}
+	if span.SpanKind() != trace.SpanKindConsumer {
+		t.Errorf("SpanKind = %v, want consumer", span.SpanKind())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("Status = %v, want error for a failed job", span.Status().Code)
+	}
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// RetryPolicy configures how failed jobs of a given type are retried.
+// It overrides the retry settings carried on the job payload.
+type RetryPolicy struct {
+	MaxAttempts     int           // Total attempts, including the first, before moving to the DLQ
+	InitialInterval time.Duration // Delay before the first retry
+	Multiplier      float64       // Growth factor between retries (defaults to 2)
+	MaxInterval     time.Duration // Upper bound on the delay between retries
+}
+
+// Backoff returns the delay before retrying after the given (1-based) attempt
+func (rp RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := rp.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	maxInterval := rp.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = rp.InitialInterval
+	}
+	return resilience.ExponentialBackoffWithMultiplier(attempt, rp.InitialInterval, multiplier, maxInterval)
+}
+
+// SetRetryPolicy registers a retry policy for a job type
+func (p *WorkerPool) SetRetryPolicy(jobType string, policy RetryPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retryPolicies[jobType] = policy
+	p.logger.Info("Registered retry policy",
+		zap.String("type", jobType),
+		zap.Int("max_attempts", policy.MaxAttempts),
+	)
+}
+
+// GetRetryPolicy returns the retry policy registered for a job type
+func (p *WorkerPool) GetRetryPolicy(jobType string) (RetryPolicy, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	policy, ok := p.retryPolicies[jobType]
+	return policy, ok
+}
+
+// failJob records a handler failure, retrying or dead-lettering the job according
+// to its type's retry policy. Jobs without a policy use the queue's default handling;
+// permanent failures are dead-lettered at once. Returns whether the job will be retried.
+func (p *WorkerPool) failJob(ctx context.Context, job *jobs.JobPayload, jobErr error, logger *zap.Logger) bool {
+	if jobs.IsPermanent(jobErr) {
+		if err := p.queue.MoveToDLQ(ctx, job.ID, jobErr); err != nil {
+			logger.Error("Failed to move job to DLQ", zap.Error(err))
+		}
+		return false
+	}
+
+	policy, ok := p.GetRetryPolicy(job.Type)
+	if !ok {
+		if err := p.queue.Fail(ctx, job.ID, jobErr); err != nil {
+			logger.Error("Failed to record job failure", zap.Error(err))
+		}
+		return job.Attempts < job.MaxRetries
+	}
+
+	if job.Attempts < policy.MaxAttempts {
+		delay := policy.Backoff(job.Attempts)
+		if err := p.queue.ScheduleRetry(ctx, job.ID, jobErr, delay); err != nil {
+			logger.Error("Failed to schedule job retry", zap.Error(err))
+		}
+		logger.Debug("Job retry scheduled", zap.Duration("delay", delay))
+		return true
+	}
+
+	if err := p.queue.MoveToDLQ(ctx, job.ID, jobErr); err != nil {
+		logger.Error("Failed to move job to DLQ", zap.Error(err))
+	}
+	return false
+}
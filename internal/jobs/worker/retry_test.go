@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:     5,
+		InitialInterval: time.Second,
+		Multiplier:      3,
+		MaxInterval:     5 * time.Second,
+	}
+
+	// Allow for up to 25% jitter
+	tests := []struct {
+		attempt int
+		min     time.Duration
+	}{
+		{1, time.Second},
+		{2, 3 * time.Second},
+		{3, 5 * time.Second}, // capped
+	}
+	for _, tt := range tests {
+		got := policy.Backoff(tt.attempt)
+		if got < tt.min || got > tt.min+tt.min/4 {
+			t.Errorf("Backoff(%d) = %v, want in [%v, %v]", tt.attempt, got, tt.min, tt.min+tt.min/4)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff_DefaultMultiplier(t *testing.T) {
+	policy := RetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Minute}
+
+	got := policy.Backoff(3)
+	if got < 400*time.Millisecond || got > 500*time.Millisecond {
+		t.Errorf("Backoff(3) = %v, want ~400ms with multiplier 2", got)
+	}
+}
+
+func TestWorkerPool_Unit_RetryPolicy(t *testing.T) {
+	job, _ := jobs.NewJobPayload("webhook", map[string]string{})
+	q := newFakeQueue(job)
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+	pool.RegisterHandler("webhook", func(ctx context.Context, payload []byte) error {
+		return errors.New("endpoint unavailable")
+	})
+	pool.SetRetryPolicy("webhook", RetryPolicy{
+		MaxAttempts:     2,
+		InitialInterval: 10 * time.Second,
+		MaxInterval:     time.Minute,
+	})
+
+	// First attempt fails and is retried with the policy's backoff
+	pool.processNextJob(context.Background(), zap.NewNop())
+	delay, ok := q.retries[job.ID]
+	if !ok {
+		t.Fatal("job should have been scheduled for retry")
+	}
+	if delay < 10*time.Second || delay > 12500*time.Millisecond {
+		t.Errorf("retry delay = %v, want ~10s", delay)
+	}
+	if len(q.dead) != 0 {
+		t.Errorf("dead = %v, want none after first attempt", q.dead)
+	}
+
+	// Second attempt exhausts MaxAttempts and goes to the DLQ
+	q.Enqueue(context.Background(), job)
+	pool.processNextJob(context.Background(), zap.NewNop())
+	if len(q.dead) != 1 || q.dead[0] != job.ID {
+		t.Errorf("dead = %v, want [%s]", q.dead, job.ID)
+	}
+}
+
+func TestWorkerPool_Unit_NoRetryPolicyUsesQueueDefault(t *testing.T) {
+	job, _ := jobs.NewJobPayload("cleanup", map[string]string{})
+	q := newFakeQueue(job)
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+	pool.RegisterHandler("cleanup", func(ctx context.Context, payload []byte) error {
+		return errors.New("boom")
+	})
+
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	if _, ok := q.failed[job.ID]; !ok {
+		t.Error("job failure should be recorded via Fail")
+	}
+	if len(q.retries) != 0 || len(q.dead) != 0 {
+		t.Errorf("retries = %v, dead = %v, want none without a policy", q.retries, q.dead)
+	}
+}
+
+func TestWorkerPool_Unit_PermanentFailureSkipsRetries(t *testing.T) {
+	job, _ := jobs.NewJobPayload("email", map[string]string{})
+	q := newFakeQueue(job)
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+	pool.RegisterHandler("email", func(ctx context.Context, payload []byte) error {
+		return jobs.Permanent(errors.New("mailbox does not exist"))
+	})
+
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	if len(q.dead) != 1 || q.dead[0] != job.ID {
+		t.Errorf("dead = %v, want [%s] on the first attempt", q.dead, job.ID)
+	}
+	if len(q.retries) != 0 {
+		t.Errorf("retries = %v, want none for a permanent failure", q.retries)
+	}
+}
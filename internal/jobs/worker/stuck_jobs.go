@@ -0,0 +1,171 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs/lock"
+)
+
+// errJobStuck is recorded on jobs failed by the stuck-job monitor
+var errJobStuck = errors.New("job stuck: worker stopped heartbeating")
+
+// StuckJob is a running job whose worker has not renewed its heartbeat within
+// the configured grace period, most likely because the worker crashed
+type StuckJob struct {
+	JobID         string    `json:"job_id"`
+	WorkerID      string    `json:"worker_id"`
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+	Token         int64     `json:"token"`
+
+	entry string // raw running jobs entry, used to reclaim the job
+}
+
+// findStuckJobs returns the running jobs whose heartbeat is older than grace,
+// ordered by job ID. Entries that cannot be parsed are left to CleanupStaleJobs.
+func findStuckJobs(running map[string]string, now time.Time, grace time.Duration) []StuckJob {
+	var stuck []StuckJob
+	for jobID, entry := range running {
+		job, err := lock.ParseRunningJob(entry)
+		if err != nil || now.Sub(job.HeartbeatAt) <= grace {
+			continue
+		}
+		stuck = append(stuck, StuckJob{
+			JobID:         jobID,
+			WorkerID:      job.WorkerID,
+			LastHeartbeat: job.HeartbeatAt,
+			Token:         job.Token,
+			entry:         entry,
+		})
+	}
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].JobID < stuck[j].JobID })
+	return stuck
+}
+
+// StuckJobs returns the jobs flagged as stuck by the last monitor pass
+func (p *WorkerPool) StuckJobs() []StuckJob {
+	p.stuckMu.RLock()
+	defer p.stuckMu.RUnlock()
+
+	stuck := make([]StuckJob, 0, len(p.stuck))
+	for _, job := range p.stuck {
+		stuck = append(stuck, job)
+	}
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].JobID < stuck[j].JobID })
+	return stuck
+}
+
+// stuckJobMonitor periodically flags running jobs that missed their heartbeats
+func (p *WorkerPool) stuckJobMonitor(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.StuckJobCheck)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.checkStuckJobs(ctx, time.Now()); err != nil {
+				p.logger.Error("Failed to check for stuck jobs", zap.Error(err))
+			}
+		}
+	}
+}
+
+// checkStuckJobs compares running jobs against their heartbeats, logging and
+// counting newly stuck jobs and requeueing them when RequeueStuckJobs is set
+func (p *WorkerPool) checkStuckJobs(ctx context.Context, now time.Time) error {
+	running, err := p.lockManager.GetRunningJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	stuck := findStuckJobs(running, now, p.config.StuckJobGrace)
+	detected := p.logNewStuckJobs(stuck)
+	if p.config.RequeueStuckJobs {
+		stuck = p.requeueStuckJobs(ctx, stuck)
+	}
+	p.setStuckJobs(stuck)
+	jobs.GlobalMetrics.RecordStuckJobs(detected, len(stuck))
+	return nil
+}
+
+// logNewStuckJobs logs jobs not flagged by the previous pass and returns their count
+func (p *WorkerPool) logNewStuckJobs(stuck []StuckJob) int {
+	p.stuckMu.RLock()
+	defer p.stuckMu.RUnlock()
+
+	detected := 0
+	for _, job := range stuck {
+		if _, seen := p.stuck[job.JobID]; seen {
+			continue
+		}
+		detected++
+		p.logger.Warn("Job stuck, worker stopped heartbeating",
+			zap.String("job_id", job.JobID),
+			zap.String("worker_id", job.WorkerID),
+			zap.Time("last_heartbeat", job.LastHeartbeat),
+		)
+	}
+	return detected
+}
+
+// setStuckJobs replaces the flagged jobs
+func (p *WorkerPool) setStuckJobs(stuck []StuckJob) {
+	current := make(map[string]StuckJob, len(stuck))
+	for _, job := range stuck {
+		current[job.JobID] = job
+	}
+
+	p.stuckMu.Lock()
+	p.stuck = current
+	p.stuckMu.Unlock()
+}
+
+// requeueStuckJobs reclaims stuck jobs and fails them through the job's retry
+// handling, so they run again elsewhere or go to the DLQ once out of attempts.
+// Returns the jobs that remain stuck.
+func (p *WorkerPool) requeueStuckJobs(ctx context.Context, stuck []StuckJob) []StuckJob {
+	remaining := stuck[:0]
+	for _, sj := range stuck {
+		logger := p.logger.With(zap.String("job_id", sj.JobID), zap.String("worker_id", sj.WorkerID))
+
+		// Only one instance reclaims a job; the owner may also have recovered
+		reclaimed, err := p.lockManager.ReclaimRunningJob(ctx, sj.JobID, sj.entry)
+		if err != nil {
+			logger.Warn("Failed to reclaim stuck job", zap.Error(err))
+			remaining = append(remaining, sj)
+			continue
+		}
+		if !reclaimed {
+			continue
+		}
+
+		p.recoverStuckJob(ctx, sj.JobID, logger)
+	}
+	return remaining
+}
+
+// recoverStuckJob fails a reclaimed job so it is retried or dead-lettered
+func (p *WorkerPool) recoverStuckJob(ctx context.Context, jobID string, logger *zap.Logger) {
+	job, err := p.queue.GetJob(ctx, jobID)
+	if err != nil {
+		logger.Warn("Failed to load stuck job", zap.Error(err))
+		return
+	}
+	if job.Status != jobs.JobStatusRunning {
+		return
+	}
+
+	retrying := p.failJob(ctx, job, errJobStuck, logger)
+	logger.Info("Requeued stuck job", zap.Bool("retrying", retrying))
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+func TestFindStuckJobs(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	running := map[string]string{
+		"fresh":   "worker-1:1699999990:3",
+		"stuck-b": "worker-2:1699999000:7",
+		"stuck-a": "worker-1:1699999500",
+		"garbage": "not-an-entry",
+	}
+
+	stuck := findStuckJobs(running, now, time.Minute)
+	if len(stuck) != 2 {
+		t.Fatalf("len(stuck) = %d, want 2: %+v", len(stuck), stuck)
+	}
+	if stuck[0].JobID != "stuck-a" || stuck[1].JobID != "stuck-b" {
+		t.Errorf("stuck = [%s %s], want [stuck-a stuck-b]", stuck[0].JobID, stuck[1].JobID)
+	}
+	if stuck[1].WorkerID != "worker-2" || stuck[1].Token != 7 || !stuck[1].LastHeartbeat.Equal(time.Unix(1699999000, 0)) {
+		t.Errorf("stuck[1] = %+v", stuck[1])
+	}
+}
+
+func TestWorkerPool_Unit_StuckJobsTracking(t *testing.T) {
+	pool := newUnitTestPool(newFakeQueue(), DefaultWorkerPoolConfig())
+
+	first := []StuckJob{{JobID: "a"}, {JobID: "b"}}
+	if detected := pool.logNewStuckJobs(first); detected != 2 {
+		t.Errorf("detected = %d, want 2", detected)
+	}
+	pool.setStuckJobs(first)
+
+	// Jobs still stuck on the next pass are not counted again
+	second := []StuckJob{{JobID: "b"}, {JobID: "c"}}
+	if detected := pool.logNewStuckJobs(second); detected != 1 {
+		t.Errorf("detected = %d, want 1", detected)
+	}
+	pool.setStuckJobs(second)
+
+	got := pool.StuckJobs()
+	if len(got) != 2 || got[0].JobID != "b" || got[1].JobID != "c" {
+		t.Errorf("StuckJobs() = %+v, want [b c]", got)
+	}
+}
+
+func TestWorkerPool_Unit_RecoverStuckJob(t *testing.T) {
+	q := newFakeQueue()
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+
+	running, _ := jobs.NewJobPayload("report", nil)
+	running.Status = jobs.JobStatusRunning
+	finished, _ := jobs.NewJobPayload("report", nil)
+	finished.Status = jobs.JobStatusCompleted
+	q.stored[running.ID] = running
+	q.stored[finished.ID] = finished
+
+	pool.recoverStuckJob(context.Background(), running.ID, zap.NewNop())
+	pool.recoverStuckJob(context.Background(), finished.ID, zap.NewNop())
+
+	if err := q.failed[running.ID]; err != errJobStuck {
+		t.Errorf("failed[running] = %v, want errJobStuck", err)
+	}
+	if _, ok := q.failed[finished.ID]; ok {
+		t.Error("a job that is no longer running should not be failed")
+	}
+}
@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// SetTimeout registers how long handlers of a job type may run before their
+// context is cancelled. It overrides the pool's JobTimeout and the timeout
+// carried on the job payload.
+func (p *WorkerPool) SetTimeout(jobType string, timeout time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.timeouts[jobType] = timeout
+	p.logger.Info("Registered job timeout",
+		zap.String("type", jobType),
+		zap.Duration("timeout", timeout),
+	)
+}
+
+// GetTimeout returns the timeout registered for a job type
+func (p *WorkerPool) GetTimeout(jobType string) (time.Duration, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	timeout, ok := p.timeouts[jobType]
+	return timeout, ok
+}
+
+// jobTimeout resolves how long a job may run: its type's registered timeout,
+// else the pool-wide JobTimeout, else the timeout on the payload. Zero means
+// no deadline.
+func (p *WorkerPool) jobTimeout(job *jobs.JobPayload) time.Duration {
+	if timeout, ok := p.GetTimeout(job.Type); ok && timeout > 0 {
+		return timeout
+	}
+	if p.config.JobTimeout > 0 {
+		return p.config.JobTimeout
+	}
+	return max(job.Timeout, 0)
+}
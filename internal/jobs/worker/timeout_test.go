@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+func TestWorkerPool_Unit_JobTimeoutResolution(t *testing.T) {
+	job, _ := jobs.NewJobPayload("report", nil, jobs.WithTimeout(time.Minute))
+
+	config := DefaultWorkerPoolConfig()
+	pool := newUnitTestPool(newFakeQueue(), config)
+	if got := pool.jobTimeout(job); got != time.Minute {
+		t.Errorf("jobTimeout() = %v, want the job's own 1m", got)
+	}
+
+	config.JobTimeout = 30 * time.Second
+	pool = newUnitTestPool(newFakeQueue(), config)
+	if got := pool.jobTimeout(job); got != 30*time.Second {
+		t.Errorf("jobTimeout() = %v, want the pool-wide 30s", got)
+	}
+
+	pool.SetTimeout("report", 5*time.Second)
+	if got := pool.jobTimeout(job); got != 5*time.Second {
+		t.Errorf("jobTimeout() = %v, want the type's 5s", got)
+	}
+}
+
+func TestWorkerPool_Unit_HandlerTimeout(t *testing.T) {
+	job, _ := jobs.NewJobPayload("wedged", map[string]string{})
+	q := newFakeQueue(job)
+	pool := newUnitTestPool(q, DefaultWorkerPoolConfig())
+	pool.SetTimeout("wedged", 20*time.Millisecond)
+
+	pool.RegisterHandler("wedged", func(ctx context.Context, payload []byte) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	before := jobs.GlobalMetrics.JobsTimedOut()["wedged"]
+	pool.processNextJob(context.Background(), zap.NewNop())
+
+	if err := q.failed[job.ID]; !errors.Is(err, jobs.ErrJobTimeout) {
+		t.Errorf("failure = %v, want %v", err, jobs.ErrJobTimeout)
+	}
+	if got := jobs.GlobalMetrics.JobsTimedOut()["wedged"]; got != before+1 {
+		t.Errorf("JobsTimedOut[wedged] = %d, want %d", got, before+1)
+	}
+}
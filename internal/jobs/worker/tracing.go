@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/jobs"
+)
+
+// tracerName identifies the worker's spans
+const tracerName = "github.com/jrjohn/arcana-cloud-go/internal/jobs/worker"
+
+// SetTracerProvider sets the tracer provider used for job execution spans.
+// By default the global OpenTelemetry provider is used.
+func (p *WorkerPool) SetTracerProvider(tp trace.TracerProvider) {
+	p.tracer = tp.Tracer(tracerName)
+}
+
+// startJobSpan starts a span named after the job type, continuing the trace
+// of the request that enqueued the job
+func (p *WorkerPool) startJobSpan(ctx context.Context, job *jobs.JobPayload) (context.Context, trace.Span) {
+	ctx = jobs.ExtractTraceContext(ctx, job)
+	return p.tracer.Start(ctx, job.Type,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("job.id", job.ID),
+			attribute.String("job.type", job.Type),
+			attribute.Int("job.attempt", job.Attempts),
+		),
+	)
+}
+
+// endJobSpan records the job outcome on its span and ends it
+func endJobSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
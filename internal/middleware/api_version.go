@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+)
+
+const (
+	// APIVersionHeader selects the API version of an unversioned route, and
+	// reports the version that served a request
+	APIVersionHeader = "X-API-Version"
+	// APIVersionKey is the context key for the API version
+	APIVersionKey = "api_version"
+)
+
+// vendorVersion matches the version of a vendor media type such as
+// application/vnd.arcana.v2+json
+var vendorVersion = regexp.MustCompile(`^application/vnd\.[a-z0-9-]+\.v(\d+)(\+json)?$`)
+
+// APIDeprecation announces the retirement of an API version
+type APIDeprecation struct {
+	// At is when the version was deprecated, sent as the Deprecation header
+	At time.Time
+	// Sunset is when the version stops being served, sent as the Sunset header
+	Sunset time.Time
+	// Link points to the migration guide
+	Link string
+}
+
+// APIVersionConfig holds API versioning configuration
+type APIVersionConfig struct {
+	// PathPrefix is the path the /vN version segment follows
+	PathPrefix string
+	// Supported lists the versions served
+	Supported []int
+	// Default is the version of unversioned requests that name none
+	Default int
+	// Deprecated lists the deprecation notices by version
+	Deprecated map[int]APIDeprecation
+}
+
+// DefaultAPIVersionConfig returns the default versioning configuration,
+// serving v1 and v2 with v1 the default
+func DefaultAPIVersionConfig() APIVersionConfig {
+	return APIVersionConfig{
+		PathPrefix: "/api",
+		Supported:  []int{1, 2},
+		Default:    1,
+	}
+}
+
+// RegisterAPIVersions registers the routes of every supported version under
+// PathPrefix/vN, and once more under PathPrefix itself for clients choosing
+// the version by header. register is called once per group and should branch
+// on GetAPIVersion where versions differ.
+func RegisterAPIVersions(router gin.IRouter, config APIVersionConfig, register func(api *gin.RouterGroup)) {
+	versioning := APIVersion(config)
+	for _, version := range config.Supported {
+		register(router.Group(fmt.Sprintf("%s/v%d", config.PathPrefix, version), versioning))
+	}
+	register(router.Group(config.PathPrefix, versioning))
+}
+
+// APIVersion resolves the API version of a request and stores it in the
+// context. A /vN segment following PathPrefix, as in /api/v2/users, takes
+// precedence; then
+// the X-API-Version header, e.g. "2" or "v2"; then an Accept header naming a
+// version parameter or a vendor media type, e.g. application/json;version=2
+// or application/vnd.arcana.v2+json. Requests naming none get the default.
+//
+// Unsupported versions are rejected with 400 Bad Request. Deprecated versions
+// get Deprecation, Sunset and Link headers.
+func APIVersion(config APIVersionConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, fromPath, err := resolveAPIVersion(c.Request, config.PathPrefix)
+		if !fromPath {
+			// The response depends on the request headers
+			c.Writer.Header().Add("Vary", "Accept, "+APIVersionHeader)
+		}
+		if err == nil && version == 0 {
+			version = config.Default
+		}
+		if err != nil || !slices.Contains(config.Supported, version) {
+			c.JSON(http.StatusBadRequest, response.NewError[any](unsupportedVersionMessage(config.Supported)))
+			c.Abort()
+			return
+		}
+
+		c.Set(APIVersionKey, version)
+		c.Header(APIVersionHeader, strconv.Itoa(version))
+		if deprecation, ok := config.Deprecated[version]; ok {
+			setDeprecationHeaders(c, deprecation)
+		}
+
+		c.Next()
+	}
+}
+
+// GetAPIVersion retrieves the API version from context, or 0 outside
+// APIVersion
+func GetAPIVersion(c *gin.Context) int {
+	if version, exists := c.Get(APIVersionKey); exists {
+		if v, ok := version.(int); ok {
+			return v
+		}
+	}
+	return 0
+}
+
+// resolveAPIVersion returns the version a request names, or 0 if it names
+// none, and whether it came from the path
+func resolveAPIVersion(r *http.Request, prefix string) (int, bool, error) {
+	if rest, ok := strings.CutPrefix(r.URL.Path, strings.TrimSuffix(prefix, "/")+"/"); ok {
+		segment, _, _ := strings.Cut(rest, "/")
+		if version, ok := parseVersion(segment, true); ok {
+			return version, true, nil
+		}
+	}
+
+	if header := strings.TrimSpace(r.Header.Get(APIVersionHeader)); header != "" {
+		version, ok := parseVersion(header, false)
+		if !ok {
+			return 0, false, fmt.Errorf("invalid %s: %q", APIVersionHeader, header)
+		}
+		return version, false, nil
+	}
+
+	for _, accept := range strings.Split(strings.Join(r.Header.Values("Accept"), ","), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if value, ok := params["version"]; ok {
+			version, ok := parseVersion(value, false)
+			if !ok {
+				return 0, false, fmt.Errorf("invalid Accept version: %q", value)
+			}
+			return version, false, nil
+		}
+		if match := vendorVersion.FindStringSubmatch(mediaType); match != nil {
+			version, _ := strconv.Atoi(match[1])
+			return version, false, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// parseVersion parses "v2", or "2" unless the prefix is required
+func parseVersion(s string, requirePrefix bool) (int, bool) {
+	digits, hasPrefix := strings.CutPrefix(strings.ToLower(s), "v")
+	if (requirePrefix && !hasPrefix) || digits == "" {
+		return 0, false
+	}
+	version, err := strconv.Atoi(digits)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// setDeprecationHeaders announces a deprecated version as described by RFC
+// 9745 (Deprecation) and RFC 8594 (Sunset)
+func setDeprecationHeaders(c *gin.Context, deprecation APIDeprecation) {
+	if !deprecation.At.IsZero() {
+		c.Header("Deprecation", "@"+strconv.FormatInt(deprecation.At.Unix(), 10))
+	}
+	if !deprecation.Sunset.IsZero() {
+		c.Header("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if deprecation.Link != "" {
+		c.Writer.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", deprecation.Link))
+	}
+}
+
+func unsupportedVersionMessage(supported []int) string {
+	versions := make([]string, len(supported))
+	for i, version := range supported {
+		versions[i] = "v" + strconv.Itoa(version)
+	}
+	return "unsupported API version; supported versions are " + strings.Join(versions, ", ")
+}
@@ -11,10 +11,14 @@ import (
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
+// APIKeyHeader is the request header carrying a service caller's API key
+const APIKeyHeader = "X-API-Key"
+
 // AuthMiddleware provides authentication middleware
 type AuthMiddleware struct {
 	jwtProvider     *security.JWTProvider
 	securityService *security.SecurityService
+	tokenDenylist   security.TokenDenylist
 }
 
 // NewAuthMiddleware creates a new AuthMiddleware instance
@@ -25,7 +29,14 @@ func NewAuthMiddleware(jwtProvider *security.JWTProvider, securityService *secur
 	}
 }
 
-// Authenticate validates the JWT token and sets the user in context
+// SetTokenDenylist makes Authenticate reject revoked access tokens. Without it
+// access tokens stay valid until they expire.
+func (m *AuthMiddleware) SetTokenDenylist(denylist security.TokenDenylist) {
+	m.tokenDenylist = denylist
+}
+
+// Authenticate validates the JWT token and sets the user in context. If the
+// token denylist cannot be read the token is accepted.
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -58,6 +69,12 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		if m.isRevoked(c, claims) {
+			c.JSON(http.StatusUnauthorized, response.NewError[any]("token has been revoked"))
+			c.Abort()
+			return
+		}
+
 		// Set claims in context
 		m.securityService.SetCurrentClaims(c, claims)
 
@@ -83,7 +100,7 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		tokenString := parts[1]
 
 		claims, err := m.jwtProvider.ValidateAccessToken(tokenString)
-		if err == nil {
+		if err == nil && !m.isRevoked(c, claims) {
 			m.securityService.SetCurrentClaims(c, claims)
 		}
 
@@ -91,6 +108,60 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	}
 }
 
+// APIKeyAuth authenticates service callers with the API key in the X-API-Key
+// header. The caller gets the SERVICE role and the key's scopes, which routes
+// check with RequireScope.
+func (m *AuthMiddleware) APIKeyAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			c.JSON(http.StatusUnauthorized, response.NewError[any]("API key required"))
+			c.Abort()
+			return
+		}
+
+		claims, err := m.securityService.ValidateAPIKey(c.Request.Context(), key)
+		if err != nil {
+			switch err {
+			case security.ErrInvalidAPIKey, security.ErrAPIKeyAuthUnavailable:
+				c.JSON(http.StatusUnauthorized, response.NewError[any]("invalid API key"))
+			default:
+				c.JSON(http.StatusInternalServerError, response.NewError[any]("failed to validate API key"))
+			}
+			c.Abort()
+			return
+		}
+
+		m.securityService.SetCurrentClaims(c, claims)
+
+		c.Next()
+	}
+}
+
+// AuthenticateOrAPIKey authenticates with an API key if the request carries
+// an X-API-Key header, and with a bearer token otherwise
+func (m *AuthMiddleware) AuthenticateOrAPIKey() gin.HandlerFunc {
+	authenticate := m.Authenticate()
+	apiKeyAuth := m.APIKeyAuth()
+	return func(c *gin.Context) {
+		if c.GetHeader(APIKeyHeader) != "" {
+			apiKeyAuth(c)
+			return
+		}
+		authenticate(c)
+	}
+}
+
+// isRevoked reports whether the token was revoked, failing open if the
+// denylist cannot be read
+func (m *AuthMiddleware) isRevoked(c *gin.Context, claims *security.UserClaims) bool {
+	if m.tokenDenylist == nil {
+		return false
+	}
+	revoked, err := m.tokenDenylist.IsRevoked(c.Request.Context(), claims)
+	return err == nil && revoked
+}
+
 // RequireRole checks if the user has the required role
 func (m *AuthMiddleware) RequireRole(roles ...entity.UserRole) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -117,3 +188,77 @@ func (m *AuthMiddleware) RequireRole(roles ...entity.UserRole) gin.HandlerFunc {
 func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	return m.RequireRole(entity.RoleAdmin)
 }
+
+// RequirePermission checks that the caller holds all of the permissions. The
+// check uses the permissions embedded in the access token when it was issued,
+// so role changes take effect when the token is refreshed. API keys are
+// checked against their scopes.
+func (m *AuthMiddleware) RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.securityService.IsAuthenticated(c) {
+			c.JSON(http.StatusUnauthorized, response.NewError[any]("authentication required"))
+			c.Abort()
+			return
+		}
+
+		for _, permission := range permissions {
+			if !m.securityService.HasPermission(c, permission) {
+				c.JSON(http.StatusForbidden, response.NewError[any]("missing permission "+permission))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// HasPermission reports whether the caller holds the permission, for checks
+// that depend on the request body and cannot be a route middleware
+func (m *AuthMiddleware) HasPermission(c *gin.Context, permission string) bool {
+	return m.securityService.HasPermission(c, permission)
+}
+
+// RequireScope checks that a caller authenticated with an API key was granted
+// all of the scopes. Users pass; their access is governed by RequireRole.
+func (m *AuthMiddleware) RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.securityService.IsAuthenticated(c) {
+			c.JSON(http.StatusUnauthorized, response.NewError[any]("authentication required"))
+			c.Abort()
+			return
+		}
+
+		for _, scope := range scopes {
+			if !m.securityService.HasScope(c, scope) {
+				c.JSON(http.StatusForbidden, response.NewError[any]("API key is missing scope "+scope))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireVerified checks if the user has verified their email address. The
+// check uses the access token, so users must refresh their token after
+// verifying before they pass it.
+func (m *AuthMiddleware) RequireVerified() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := m.securityService.GetCurrentClaims(c)
+		if claims == nil {
+			c.JSON(http.StatusUnauthorized, response.NewError[any]("authentication required"))
+			c.Abort()
+			return
+		}
+
+		if !claims.Verified {
+			c.JSON(http.StatusForbidden, response.NewError[any]("email verification required"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+)
+
+// MaxBodySize limits request bodies to maxBytes. Requests declaring a larger
+// Content-Length are rejected up front with 413 Request Entity Too Large;
+// otherwise the body is wrapped with http.MaxBytesReader so reads past the
+// limit fail. Handlers should pass body read and bind errors to BodyTooLarge
+// to respond with 413 instead of a generic 400.
+//
+// Applying MaxBodySize again on a route, e.g. a multipart upload, replaces the
+// outer limit for that route. maxBytes <= 0 disables the limit.
+func MaxBodySize(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := c.Request.Body
+		if limited, ok := body.(*limitedBody); ok {
+			body = limited.original
+		}
+		if maxBytes <= 0 || body == nil || body == http.NoBody {
+			c.Request.Body = body
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			abortBodyTooLarge(c, maxBytes)
+			return
+		}
+
+		c.Request.Body = &limitedBody{
+			ReadCloser: http.MaxBytesReader(c.Writer, body, maxBytes),
+			original:   body,
+		}
+		c.Next()
+	}
+}
+
+// BodyTooLarge responds with 413 and returns true if err was caused by a
+// request body exceeding the MaxBodySize limit
+func BodyTooLarge(c *gin.Context, err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		return false
+	}
+	abortBodyTooLarge(c, maxBytesErr.Limit)
+	return true
+}
+
+// limitedBody is a size-limited request body that remembers the original so a
+// nested MaxBodySize can apply a different limit
+type limitedBody struct {
+	io.ReadCloser
+	original io.ReadCloser
+}
+
+func abortBodyTooLarge(c *gin.Context, limit int64) {
+	message := fmt.Sprintf("request body exceeds the %s limit", formatBytes(limit))
+	c.JSON(http.StatusRequestEntityTooLarge, response.NewError[any](message))
+	c.Abort()
+}
+
+// formatBytes formats a byte count using binary units, e.g. "10 MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d bytes", n)
+	}
+	value, suffix := float64(n), ""
+	for _, s := range []string{"KB", "MB", "GB"} {
+		value /= unit
+		suffix = s
+		if value < unit {
+			break
+		}
+	}
+	if value == float64(int64(value)) {
+		return fmt.Sprintf("%d %s", int64(value), suffix)
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
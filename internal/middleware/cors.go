@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,7 +11,13 @@ import (
 
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
-	AllowOrigins     []string
+	// AllowOrigins lists the allowed origins. "*" allows any origin and an
+	// entry containing "*" is a pattern, e.g. "https://*.example.com", where
+	// each "*" matches one or more host characters.
+	AllowOrigins []string
+	// AllowOriginFunc, when set, decides which origins are allowed instead
+	// of AllowOrigins, e.g. to consult an allowlist that changes at runtime
+	AllowOriginFunc  func(origin string) bool
 	AllowMethods     []string
 	AllowHeaders     []string
 	ExposeHeaders    []string
@@ -36,17 +44,70 @@ func DefaultCORSConfig() CORSConfig {
 	}
 }
 
-// resolveAllowedOrigin returns the origin to reflect, or "" if origin is not allowed
-func resolveAllowedOrigin(origin string, allowOrigins []string) string {
-	for _, o := range allowOrigins {
-		if o == "*" || o == origin {
-			return origin
+// originMatcher reports whether a request origin is allowed
+type originMatcher struct {
+	any      bool
+	exact    map[string]struct{}
+	patterns []*regexp.Regexp
+	allow    func(origin string) bool
+}
+
+func newOriginMatcher(cfg CORSConfig) *originMatcher {
+	m := &originMatcher{exact: make(map[string]struct{}), allow: cfg.AllowOriginFunc}
+	for _, o := range cfg.AllowOrigins {
+		switch {
+		case o == "*":
+			m.any = true
+		case strings.Contains(o, "*"):
+			m.patterns = append(m.patterns, compileOriginPattern(o))
+		default:
+			m.exact[o] = struct{}{}
+		}
+	}
+	return m
+}
+
+// compileOriginPattern compiles a wildcard origin such as
+// "https://*.example.com" or "http://localhost:*". A "*" matches host name
+// characters and port digits only, and the whole origin must match, so
+// "https://*.example.com" does not match "https://example.com.evil.com".
+func compileOriginPattern(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, "[a-zA-Z0-9.-]+") + "$")
+}
+
+func (m *originMatcher) matches(origin string) bool {
+	if m.allow != nil {
+		return m.allow(origin)
+	}
+	if m.any {
+		return true
+	}
+	if _, ok := m.exact[origin]; ok {
+		return true
+	}
+	for _, p := range m.patterns {
+		if p.MatchString(origin) {
+			return true
 		}
 	}
-	if len(allowOrigins) > 0 && allowOrigins[0] == "*" {
+	return false
+}
+
+// resolveAllowedOrigin returns the origin to reflect, or "" if origin is not
+// allowed. Any origin is answered with "*" unless credentials are allowed,
+// which browsers only honour for the specific requesting origin.
+func (m *originMatcher) resolveAllowedOrigin(origin string, allowCredentials bool) string {
+	if origin == "" || !m.matches(origin) {
+		return ""
+	}
+	if m.any && m.allow == nil && !allowCredentials {
 		return "*"
 	}
-	return ""
+	return origin
 }
 
 // applyPreflightHeaders sets the CORS preflight response headers
@@ -59,13 +120,19 @@ func applyPreflightHeaders(c *gin.Context, cfg CORSConfig) {
 
 // CORS returns a CORS middleware with the given configuration
 func CORS(config CORSConfig) gin.HandlerFunc {
+	origins := newOriginMatcher(config)
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
-		allowOrigin := resolveAllowedOrigin(origin, config.AllowOrigins)
+		allowOrigin := origins.resolveAllowedOrigin(origin, config.AllowCredentials)
 
 		if allowOrigin != "" {
 			c.Header("Access-Control-Allow-Origin", allowOrigin)
 		}
+		if allowOrigin != "*" {
+			// The response depends on the requesting origin
+			c.Writer.Header().Add("Vary", "Origin")
+		}
 		if config.AllowCredentials {
 			c.Header("Access-Control-Allow-Credentials", "true")
 		}
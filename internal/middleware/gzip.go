@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GzipConfig holds response compression configuration
+type GzipConfig struct {
+	// Level is the gzip compression level, from gzip.BestSpeed to gzip.BestCompression
+	Level int
+	// MinSize is the smallest response body in bytes worth compressing
+	MinSize int
+	// ExcludedContentTypes are media type prefixes that are never compressed,
+	// typically because they are already compressed
+	ExcludedContentTypes []string
+}
+
+// DefaultGzipConfig returns the default gzip configuration
+func DefaultGzipConfig() GzipConfig {
+	return GzipConfig{
+		Level:   gzip.DefaultCompression,
+		MinSize: 1024,
+		ExcludedContentTypes: []string{
+			"image/", "video/", "audio/", "font/woff",
+			"application/zip", "application/gzip", "application/x-gzip",
+			"application/x-7z-compressed", "application/x-rar-compressed",
+			"application/pdf", "text/event-stream",
+		},
+	}
+}
+
+// Gzip returns a middleware that gzip-compresses responses at the given level
+// using the default threshold and excluded content types
+func Gzip(level int) gin.HandlerFunc {
+	config := DefaultGzipConfig()
+	config.Level = level
+	return GzipWithConfig(config)
+}
+
+// GzipWithConfig returns a middleware that gzip-compresses responses for
+// clients sending Accept-Encoding: gzip. Responses smaller than MinSize, with an
+// excluded content type or that already set Content-Encoding are sent as is.
+// Protocol upgrades such as WebSocket handshakes are never touched.
+// It panics if config.Level is not a valid gzip level.
+func GzipWithConfig(config GzipConfig) gin.HandlerFunc {
+	if _, err := gzip.NewWriterLevel(nil, config.Level); err != nil {
+		panic(err)
+	}
+	pool := &sync.Pool{
+		New: func() any {
+			gz, _ := gzip.NewWriterLevel(nil, config.Level)
+			return gz
+		},
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		gw := &gzipWriter{ResponseWriter: c.Writer, config: &config, pool: pool}
+		defer func() {
+			gw.finish()
+			c.Writer = gw.ResponseWriter
+		}()
+
+		c.Writer = gw
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+			continue
+		}
+		q := strings.TrimSpace(params)
+		if value, ok := strings.CutPrefix(q, "q="); ok {
+			if weight, err := strconv.ParseFloat(value, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWriter buffers the start of a response until it can decide whether to
+// compress it, then either streams it through gzip or writes it unchanged
+type gzipWriter struct {
+	gin.ResponseWriter
+
+	config  *GzipConfig
+	pool    *sync.Pool
+	buf     []byte
+	gz      *gzip.Writer
+	decided bool
+}
+
+// decide chooses between compressing and passing the response through,
+// writing out anything buffered so far
+func (w *gzipWriter) decide(large bool) error {
+	w.decided = true
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if large && w.compressible() {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = w.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.write(buf)
+	return err
+}
+
+func (w *gzipWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	mediaType, _, _ := strings.Cut(header.Get("Content-Type"), ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, excluded := range w.config.ExcludedContentTypes {
+		if strings.HasPrefix(mediaType, excluded) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *gzipWriter) write(data []byte) (int, error) {
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// finish writes out a response still being buffered and completes the gzip stream
+func (w *gzipWriter) finish() {
+	if !w.decided {
+		if len(w.buf) == 0 {
+			return
+		}
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+		w.gz.Reset(nil)
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		return w.write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= w.config.MinSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipWriter) WriteHeaderNow() {
+	if !w.decided {
+		_ = w.decide(len(w.buf) > 0)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *gzipWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends what has been written so far. A response flushed before reaching
+// MinSize is assumed to be streamed and compressed if its type allows.
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if w.decided && w.gz != nil {
+		return nil, nil, errors.New("gzip: cannot hijack a compressed response")
+	}
+	return w.ResponseWriter.Hijack()
+}
@@ -0,0 +1,287 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	keyPrefixIdempotency = "arcana:idempotency:"
+)
+
+// IdempotencyRecord is the state of an idempotency key. It is pending while the
+// first request is in flight and holds that request's response once complete.
+type IdempotencyRecord struct {
+	Fingerprint string      `json:"fingerprint"`
+	Completed   bool        `json:"completed"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// IdempotencyStore persists idempotency records
+type IdempotencyStore interface {
+	// Reserve claims key with a pending record for ttl. It returns nil if the key
+	// was claimed, or the existing record if the key is already in use.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error)
+	// Save stores the completed record for key
+	Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error
+	// Release removes key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyConfig holds idempotency configuration
+type IdempotencyConfig struct {
+	// TTL is how long a completed response is replayed for
+	TTL time.Duration
+	// InFlightTTL bounds how long a key stays reserved if the server handling
+	// it dies; it should exceed the request timeout
+	InFlightTTL time.Duration
+}
+
+// DefaultIdempotencyConfig returns the default idempotency configuration
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		TTL:         24 * time.Hour,
+		InFlightTTL: time.Minute,
+	}
+}
+
+// Idempotency makes POST and PATCH requests carrying an Idempotency-Key header
+// safe to retry, using the default configuration
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return IdempotencyWithConfig(store, DefaultIdempotencyConfig())
+}
+
+// IdempotencyWithConfig makes POST and PATCH requests carrying an
+// Idempotency-Key header safe to retry. The first request with a key runs the
+// handler and its response is stored; later requests with the same key and
+// body get that response replayed without running the handler. A request
+// arriving while the first is still in flight gets 409 Conflict, and reusing a
+// key with a different body gets 422 Unprocessable Entity.
+//
+// Keys are scoped to the authenticated user, so it should run after
+// authentication. Server errors, 429 responses, panics and requests that end
+// without writing a response are not stored, so the client may retry them. If
+// the store is unavailable requests proceed normally.
+func IdempotencyWithConfig(store IdempotencyStore, config IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+		if (method != http.MethodPost && method != http.MethodPatch) || idempotencyKey == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			if !BodyTooLarge(c, err) {
+				c.JSON(http.StatusBadRequest, response.NewError[any]("failed to read request body"))
+				c.Abort()
+			}
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := idempotencyStoreKey(c, idempotencyKey)
+		fingerprint := hashHex([]byte(method), []byte(c.Request.URL.RequestURI()), body)
+
+		ctx := context.WithoutCancel(c.Request.Context())
+		record, err := store.Reserve(ctx, key, fingerprint, config.InFlightTTL)
+		if err != nil {
+			c.Next()
+			return
+		}
+		if record != nil {
+			replayIdempotent(c, record, fingerprint)
+			return
+		}
+
+		rw := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = rw
+		defer func() {
+			c.Writer = rw.ResponseWriter
+			// A panicking handler has not produced its response yet; the
+			// recovery middleware turns it into a 500, which is not stored
+			if p := recover(); p != nil {
+				_ = store.Release(ctx, key)
+				panic(p)
+			}
+			status := rw.Status()
+			if !rw.responded() || status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+				_ = store.Release(ctx, key)
+				return
+			}
+			_ = store.Save(ctx, key, &IdempotencyRecord{
+				Fingerprint: fingerprint,
+				Completed:   true,
+				Status:      status,
+				Header:      replayableHeader(rw.Header()),
+				Body:        rw.body.Bytes(),
+			}, config.TTL)
+		}()
+
+		c.Next()
+	}
+}
+
+func replayIdempotent(c *gin.Context, record *IdempotencyRecord, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		c.JSON(http.StatusUnprocessableEntity, response.NewError[any]("Idempotency-Key was already used for a different request"))
+	case !record.Completed:
+		c.JSON(http.StatusConflict, response.NewError[any]("a request with this Idempotency-Key is already in progress"))
+	default:
+		for name, values := range record.Header {
+			c.Writer.Header()[name] = values
+		}
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Writer.WriteHeader(record.Status)
+		_, _ = c.Writer.Write(record.Body)
+	}
+	c.Abort()
+}
+
+// idempotencyStoreKey scopes the client's key to the user or API key and route
+func idempotencyStoreKey(c *gin.Context, idempotencyKey string) string {
+	scope := ""
+	if claims, ok := c.Get(security.ContextKeyClaims); ok {
+		if userClaims, ok := claims.(*security.UserClaims); ok {
+			scope = strconv.FormatUint(uint64(userClaims.UserID), 10)
+			if userClaims.APIKeyID != 0 {
+				scope = "key:" + strconv.FormatUint(uint64(userClaims.APIKeyID), 10)
+			}
+		}
+	}
+	return hashHex([]byte(scope), []byte(c.Request.Method), []byte(c.Request.URL.Path), []byte(idempotencyKey))
+}
+
+func hashHex(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// nonReplayableHeaders are response headers specific to the original request
+var nonReplayableHeaders = []string{
+	RequestIDHeader, RateLimitLimitHeader, RateLimitRemainingHeader, RateLimitResetHeader,
+	"Retry-After", "Content-Length", "Content-Encoding", "Vary", "Date",
+}
+
+func replayableHeader(header http.Header) http.Header {
+	replay := header.Clone()
+	for _, name := range nonReplayableHeaders {
+		replay.Del(name)
+	}
+	return replay
+}
+
+// recordingWriter keeps a copy of the response body
+type recordingWriter struct {
+	gin.ResponseWriter
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+// responded reports whether the handler set a status or wrote a body. Gin only
+// sends a status set with c.Status once the handler chain returns.
+func (w *recordingWriter) responded() bool {
+	return w.wroteHeader || w.Written()
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+var _ IdempotencyStore = (*RedisIdempotencyStore)(nil)
+
+// RedisIdempotencyStore stores idempotency records in Redis so retries are
+// recognized by every replica
+type RedisIdempotencyStore struct {
+	client *redis.Client
+}
+
+// NewRedisIdempotencyStore creates a new Redis-backed idempotency store
+func NewRedisIdempotencyStore(client *redis.Client) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client}
+}
+
+// Reserve claims key with a pending record, or returns the existing record
+func (s *RedisIdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error) {
+	pending, err := json.Marshal(&IdempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	// Retry once in case the existing record expires between SETNX and GET
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := s.client.SetNX(ctx, keyPrefixIdempotency+key, pending, ttl).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return nil, nil
+		}
+
+		data, err := s.client.Get(ctx, keyPrefixIdempotency+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var record IdempotencyRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+		return &record, nil
+	}
+	return &IdempotencyRecord{Fingerprint: fingerprint}, nil
+}
+
+// Save stores the completed record for key
+func (s *RedisIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, keyPrefixIdempotency+key, data, ttl).Err()
+}
+
+// Release removes key so the request can be retried
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, keyPrefixIdempotency+key).Err()
+}
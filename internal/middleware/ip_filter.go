@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+)
+
+// ForwardedForHeader lists the client and the proxies a request passed through
+const ForwardedForHeader = "X-Forwarded-For"
+
+// IPFilterConfig holds network restrictions. Entries are CIDRs such as
+// "10.0.0.0/8" or "2001:db8::/32", or single addresses.
+type IPFilterConfig struct {
+	// Allow lists the networks allowed in; empty allows every network not denied
+	Allow []string
+	// Deny lists networks refused even when allowed
+	Deny []string
+	// TrustedProxies lists the proxies whose X-Forwarded-For is believed.
+	// Without them the header is ignored, since any client can send it.
+	TrustedProxies []string
+}
+
+// IPFilter rejects requests from networks not allowed by cfg with 403
+// Forbidden. Apply it to the route groups to restrict, e.g. admin routes,
+// so public endpoints stay open. It returns an error for an invalid entry.
+func IPFilter(cfg IPFilterConfig) (gin.HandlerFunc, error) {
+	allow, err := parsePrefixes(cfg.Allow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allow entry: %w", err)
+	}
+	deny, err := parsePrefixes(cfg.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deny entry: %w", err)
+	}
+	trusted, err := parsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxy entry: %w", err)
+	}
+
+	return func(c *gin.Context) {
+		ip, ok := filterClientIP(c.Request, trusted)
+		if !ok || containsAddr(deny, ip) || (len(allow) > 0 && !containsAddr(allow, ip)) {
+			c.JSON(http.StatusForbidden, response.NewError[any]("access denied from this network"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+// filterClientIP returns the address of the client. The peer is the client
+// unless it is a trusted proxy, in which case X-Forwarded-For is walked from
+// the nearest hop back, skipping trusted proxies; the first untrusted address
+// is the client. A malformed hop reports false, as the client is unknown.
+func filterClientIP(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	ip = ip.Unmap().WithZone("")
+
+	if !containsAddr(trusted, ip) {
+		return ip, true
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values(ForwardedForHeader), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		addr, err := netip.ParseAddr(hop)
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		ip = addr.Unmap()
+		if !containsAddr(trusted, ip) {
+			return ip, true
+		}
+	}
+	// Every hop is a trusted proxy, so the request came from inside
+	return ip, true
+}
+
+// parsePrefixes parses CIDRs and single addresses
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+func containsAddr(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
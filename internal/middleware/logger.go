@@ -1,19 +1,102 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
+// redactedValue replaces the values of redacted fields in logged bodies
+const redactedValue = "[REDACTED]"
+
+// LoggerConfig holds request logging configuration
+type LoggerConfig struct {
+	// CaptureBodies logs the request and response bodies of error responses,
+	// and of any request carrying DebugHeader
+	CaptureBodies bool
+	// MaxBodySize is the most bytes of each body logged
+	MaxBodySize int
+	// RedactFields are JSON and form field names, matched case-insensitively,
+	// whose values are never logged
+	RedactFields []string
+	// DebugHeader, when sent with any non-empty value by an authenticated
+	// admin, logs the bodies of a successful request too; empty disables it
+	DebugHeader string
+	// SkippedContentTypes are media type prefixes whose bodies are never
+	// logged, typically because they are binary or streamed
+	SkippedContentTypes []string
+}
+
+// DefaultLoggerConfig returns the default logging configuration, with body
+// capture off
+func DefaultLoggerConfig() LoggerConfig {
+	return LoggerConfig{
+		MaxBodySize: 4096,
+		RedactFields: []string{
+			"password", "new_password", "old_password", "token", "access_token",
+			"refresh_token", "secret", "api_key", "key", "authorization",
+			"code", "recovery_codes", "challenge_id",
+		},
+		DebugHeader: "X-Debug-Log",
+		SkippedContentTypes: []string{
+			"multipart/", "application/octet-stream", "image/", "video/", "audio/",
+			"font/", "application/zip", "application/gzip", "application/pdf",
+			"text/event-stream",
+		},
+	}
+}
+
+// isAdminCaller reports whether the request was authenticated as an admin
+func isAdminCaller(c *gin.Context) bool {
+	claims, ok := c.Get(security.ContextKeyClaims)
+	if !ok {
+		return false
+	}
+	userClaims, ok := claims.(*security.UserClaims)
+	return ok && userClaims.Role == entity.RoleAdmin
+}
+
 // Logger returns a request logging middleware
 func Logger(logger *zap.Logger) gin.HandlerFunc {
+	return LoggerWithConfig(logger, DefaultLoggerConfig())
+}
+
+// LoggerWithConfig returns a request logging middleware. With CaptureBodies,
+// the request body is recorded as the handler reads it and the response body
+// as it is written, both up to MaxBodySize, so streamed responses are not held
+// back. Bodies with a skipped content type or a Content-Encoding are not
+// logged.
+func LoggerWithConfig(logger *zap.Logger, config LoggerConfig) gin.HandlerFunc {
+	redact := newBodyRedactor(config.RedactFields)
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
+		var reqBody *bodyCapture
+		var respWriter *bodyLogWriter
+		if config.CaptureBodies {
+			if c.Request.Body != nil && !skippedContentType(c.Request.Header.Get("Content-Type"), config.SkippedContentTypes) {
+				reqBody = &bodyCapture{limit: config.MaxBodySize}
+				c.Request.Body = &capturingBody{ReadCloser: c.Request.Body, capture: reqBody}
+			}
+			respWriter = &bodyLogWriter{ResponseWriter: c.Writer, config: &config, capture: bodyCapture{limit: config.MaxBodySize}}
+			c.Writer = respWriter
+			defer func() { c.Writer = respWriter.ResponseWriter }()
+		}
+
 		c.Next()
 
 		latency := time.Since(start)
@@ -34,6 +117,19 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 			fields = append(fields, zap.String("errors", c.Errors.String()))
 		}
 
+		// Claims are set by the route's auth middleware during c.Next
+		debug := config.DebugHeader != "" && c.GetHeader(config.DebugHeader) != "" && isAdminCaller(c)
+		if config.CaptureBodies && (status >= 400 || debug) {
+			if reqBody != nil && reqBody.buf.Len() > 0 {
+				fields = append(fields, zap.String("request_body",
+					reqBody.String(redact, c.Request.Header.Get("Content-Type"))))
+			}
+			if respWriter.loggable() {
+				fields = append(fields, zap.String("response_body",
+					respWriter.capture.String(redact, respWriter.Header().Get("Content-Type"))))
+			}
+		}
+
 		switch {
 		case status >= 500:
 			logger.Error("server error", fields...)
@@ -44,3 +140,159 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 		}
 	}
 }
+
+// skippedContentType reports whether a Content-Type matches a skipped prefix
+func skippedContentType(contentType string, skipped []string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, prefix := range skipped {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyCapture keeps the first bytes of a body, up to limit
+type bodyCapture struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *bodyCapture) record(data []byte) {
+	if room := b.limit - b.buf.Len(); room < len(data) {
+		data = data[:max(room, 0)]
+		b.truncated = true
+	}
+	b.buf.Write(data)
+}
+
+// String returns the captured body with sensitive fields redacted, marking
+// a truncated one
+func (b *bodyCapture) String(redact *bodyRedactor, contentType string) string {
+	body := redact.redact(b.buf.Bytes(), contentType, b.truncated)
+	if b.truncated {
+		body += "...(truncated)"
+	}
+	return body
+}
+
+// capturingBody records a request body as the handler reads it
+type capturingBody struct {
+	io.ReadCloser
+	capture *bodyCapture
+}
+
+func (b *capturingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.capture.record(p[:n])
+	return n, err
+}
+
+// bodyLogWriter records the start of a response while writing it through
+// unchanged, so flushing and hijacking keep working
+type bodyLogWriter struct {
+	gin.ResponseWriter
+
+	config  *LoggerConfig
+	capture bodyCapture
+	skipped bool
+	checked bool
+}
+
+// loggable reports whether the captured response body may be logged
+func (w *bodyLogWriter) loggable() bool {
+	return !w.skipped && w.capture.buf.Len() > 0
+}
+
+func (w *bodyLogWriter) Write(data []byte) (int, error) {
+	if !w.checked {
+		// Decided on the first write, once the handler has set its headers
+		w.checked = true
+		header := w.Header()
+		w.skipped = header.Get("Content-Encoding") != "" ||
+			skippedContentType(header.Get("Content-Type"), w.config.SkippedContentTypes)
+	}
+	n, err := w.ResponseWriter.Write(data)
+	if !w.skipped {
+		w.capture.record(data[:n])
+	}
+	return n, err
+}
+
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// bodyRedactor hides the values of sensitive fields in JSON and form bodies
+type bodyRedactor struct {
+	fields map[string]bool
+	// pattern matches "field": "value" pairs, for JSON cut off by the size cap
+	pattern *regexp.Regexp
+}
+
+func newBodyRedactor(fields []string) *bodyRedactor {
+	r := &bodyRedactor{fields: make(map[string]bool, len(fields))}
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		r.fields[strings.ToLower(field)] = true
+		quoted = append(quoted, regexp.QuoteMeta(field))
+	}
+	if len(quoted) > 0 {
+		r.pattern = regexp.MustCompile(`(?i)("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\s]+)`)
+	}
+	return r
+}
+
+// redact returns body with the values of sensitive fields replaced
+func (r *bodyRedactor) redact(body []byte, contentType string, truncated bool) string {
+	if len(r.fields) == 0 {
+		return string(body)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return redactedValue
+		}
+		for key := range values {
+			if r.fields[strings.ToLower(key)] {
+				values[key] = []string{redactedValue}
+			}
+		}
+		return values.Encode()
+	case !truncated && json.Valid(body):
+		// Numbers are kept as written rather than round-tripped through float64
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err == nil {
+			if redacted, err := json.Marshal(r.redactValue(value)); err == nil {
+				return string(redacted)
+			}
+		}
+	}
+	// Invalid or truncated JSON, or another text type
+	return r.pattern.ReplaceAllString(string(body), `${1}"`+redactedValue+`"`)
+}
+
+func (r *bodyRedactor) redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if r.fields[strings.ToLower(key)] {
+				v[key] = redactedValue
+			} else {
+				v[key] = r.redactValue(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = r.redactValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/observability"
+)
+
+// GlobalHTTPMetrics is the HTTP metrics instance used by Metrics. Its
+// collectors are served on /metrics once registered on the
+// observability.MetricsProvider, as the DI module does.
+var GlobalHTTPMetrics = observability.NewHTTPMetrics()
+
+// Metrics records request count, duration and in-flight requests in
+// GlobalHTTPMetrics, labeled by method, route template and status code
+func Metrics() gin.HandlerFunc {
+	return GlobalHTTPMetrics.Middleware()
+}
@@ -1,17 +1,38 @@
 package middleware
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/config"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/observability"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil/mocks"
 )
 
 func init() {
@@ -231,6 +252,114 @@ func TestCORS_SpecificOrigins(t *testing.T) {
 	})
 }
 
+func TestCORS_OriginPatterns(t *testing.T) {
+	cfg := CORSConfig{
+		AllowOrigins:     []string{"https://app.example.org", "https://*.example.com", "http://localhost:*"},
+		AllowMethods:     []string{"GET"},
+		AllowCredentials: true,
+	}
+
+	router := newTestRouter()
+	router.Use(CORS(cfg))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	tests := []struct {
+		origin string
+		want   string
+	}{
+		{"https://app.example.org", "https://app.example.org"},
+		{"https://api.example.com", "https://api.example.com"},
+		{"https://eu.api.example.com", "https://eu.api.example.com"},
+		{"http://localhost:3000", "http://localhost:3000"},
+		{"https://example.com", ""},
+		{"http://api.example.com", ""},
+		{"https://example.com.evil.com", ""},
+		{"https://api.example.com.evil.com", ""},
+		{"https://evil.com/.example.com", ""},
+		{"http://localhost", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Origin", tt.origin)
+			router.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.want {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.want)
+			}
+			if got := w.Header().Get("Vary"); got != "Origin" {
+				t.Errorf("Vary = %q, want Origin", got)
+			}
+		})
+	}
+}
+
+func TestCORS_AllowOriginFunc(t *testing.T) {
+	allowlist := map[string]bool{"https://partner.test": true}
+	cfg := CORSConfig{
+		// The func takes precedence over AllowOrigins
+		AllowOrigins:     []string{"*"},
+		AllowOriginFunc:  func(origin string) bool { return allowlist[origin] },
+		AllowMethods:     []string{"GET"},
+		AllowCredentials: true,
+	}
+
+	router := newTestRouter()
+	router.Use(CORS(cfg))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	allowOrigin := func(origin string) string {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Origin", origin)
+		router.ServeHTTP(w, req)
+		return w.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	if got := allowOrigin("https://partner.test"); got != "https://partner.test" {
+		t.Errorf("Allow-Origin = %q, want https://partner.test", got)
+	}
+	if got := allowOrigin("https://other.test"); got != "" {
+		t.Errorf("Allow-Origin = %q, want none", got)
+	}
+
+	// The allowlist is consulted on every request
+	allowlist["https://other.test"] = true
+	if got := allowOrigin("https://other.test"); got != "https://other.test" {
+		t.Errorf("Allow-Origin = %q, want https://other.test", got)
+	}
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	for _, credentials := range []bool{true, false} {
+		router := newTestRouter()
+		router.Use(CORS(CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: credentials}))
+		router.GET("/test", func(c *gin.Context) {
+			c.String(http.StatusOK, "OK")
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Origin", "https://anywhere.test")
+		router.ServeHTTP(w, req)
+
+		// Browsers reject "*" on credentialed requests
+		want := "*"
+		if credentials {
+			want = "https://anywhere.test"
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("credentials %v: Allow-Origin = %q, want %q", credentials, got, want)
+		}
+	}
+}
+
 // Logger Middleware Tests
 func TestLogger(t *testing.T) {
 	logger := zap.NewNop()
@@ -283,6 +412,203 @@ func TestLogger_StatusCodes(t *testing.T) {
 	}
 }
 
+// newBodyLogRouter routes POST /test through a body capturing logger,
+// recording its entries
+func newBodyLogRouter(config LoggerConfig, handler gin.HandlerFunc) (*gin.Engine, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.InfoLevel)
+	config.CaptureBodies = true
+	router := newTestRouter()
+	router.Use(LoggerWithConfig(zap.New(core), config))
+	router.POST("/test", handler)
+	return router, logs
+}
+
+// loggedField returns a string field of the only log entry
+func loggedField(t *testing.T, logs *observer.ObservedLogs, key string) (string, bool) {
+	t.Helper()
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("log entries = %d, want 1", len(entries))
+	}
+	value, ok := entries[0].ContextMap()[key]
+	if !ok {
+		return "", false
+	}
+	return value.(string), true
+}
+
+func echoBadRequest(c *gin.Context) {
+	body, _ := io.ReadAll(c.Request.Body)
+	c.Data(http.StatusBadRequest, "application/json", body)
+}
+
+func TestLogger_CaptureBodies(t *testing.T) {
+	router, logs := newBodyLogRouter(DefaultLoggerConfig(), echoBadRequest)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/test",
+		strings.NewReader(`{"username":"alice","Password":"hunter2","nested":{"token":"abc"},"id":12345678901234567890}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), "hunter2") {
+		t.Error("Response should reach the client unchanged")
+	}
+	for _, key := range []string{"request_body", "response_body"} {
+		body, ok := loggedField(t, logs, key)
+		if !ok {
+			t.Fatalf("%s not logged", key)
+		}
+		if strings.Contains(body, "hunter2") || strings.Contains(body, "abc") {
+			t.Errorf("%s = %s, want secrets redacted", key, body)
+		}
+		for _, want := range []string{`"username":"alice"`, `"Password":"[REDACTED]"`, `"token":"[REDACTED]"`, "12345678901234567890"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s = %s, want it to contain %s", key, body, want)
+			}
+		}
+	}
+}
+
+func TestLogger_CaptureBodies_SuccessNotLogged(t *testing.T) {
+	router, logs := newBodyLogRouter(DefaultLoggerConfig(), func(c *gin.Context) {
+		c.String(http.StatusOK, "fine")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("hello")))
+	if _, ok := loggedField(t, logs, "response_body"); ok {
+		t.Error("Bodies of successful requests should not be logged")
+	}
+
+	// Unless an admin's debug header asks for them
+	logs.TakeAll()
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("hello"))
+	req.Header.Set("X-Debug-Log", "1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if _, ok := loggedField(t, logs, "response_body"); ok {
+		t.Error("The debug header of an anonymous caller should be ignored")
+	}
+}
+
+func TestLogger_CaptureBodies_AdminDebugHeader(t *testing.T) {
+	for _, tt := range []struct {
+		role entity.UserRole
+		want bool
+	}{
+		{entity.RoleAdmin, true},
+		{entity.RoleUser, false},
+	} {
+		router, logs := newBodyLogRouter(DefaultLoggerConfig(), func(c *gin.Context) {
+			c.Set(security.ContextKeyClaims, &security.UserClaims{UserID: 1, Role: tt.role})
+			c.String(http.StatusOK, "fine")
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("hello"))
+		req.Header.Set("X-Debug-Log", "1")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		if body, _ := loggedField(t, logs, "response_body"); (body == "fine") != tt.want {
+			t.Errorf("%s: response_body = %q, want logged %v", tt.role, body, tt.want)
+		}
+	}
+}
+
+func TestLogger_CaptureBodies_RedactsTwoFactorFields(t *testing.T) {
+	router, logs := newBodyLogRouter(DefaultLoggerConfig(), echoBadRequest)
+
+	req := httptest.NewRequest(http.MethodPost, "/test",
+		strings.NewReader(`{"challenge_id":"ch-1","code":"123456","key":"JBSWY3DP","recovery_codes":["aaaa-bbbb"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	body, _ := loggedField(t, logs, "request_body")
+	for _, secret := range []string{"ch-1", "123456", "JBSWY3DP", "aaaa-bbbb"} {
+		if strings.Contains(body, secret) {
+			t.Errorf("request_body = %s, want %s redacted", body, secret)
+		}
+	}
+}
+
+func TestLogger_CaptureBodies_Truncated(t *testing.T) {
+	config := DefaultLoggerConfig()
+	config.MaxBodySize = 24
+	router, logs := newBodyLogRouter(config, echoBadRequest)
+
+	payload := `{"name":"x","password":"hunter2-and-then-some","extra":"padding"}`
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != payload {
+		t.Error("Response should not be truncated")
+	}
+	body, _ := loggedField(t, logs, "request_body")
+	if strings.Contains(body, "hunter") {
+		t.Errorf("request_body = %s, want the cut off password redacted", body)
+	}
+	if !strings.HasSuffix(body, "...(truncated)") {
+		t.Errorf("request_body = %s, want it marked truncated", body)
+	}
+}
+
+func TestLogger_CaptureBodies_Form(t *testing.T) {
+	router, logs := newBodyLogRouter(DefaultLoggerConfig(), func(c *gin.Context) {
+		_ = c.Request.ParseForm()
+		c.Status(http.StatusUnauthorized)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("user=alice&password=hunter2"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	body, _ := loggedField(t, logs, "request_body")
+	if body != "password=%5BREDACTED%5D&user=alice" {
+		t.Errorf("request_body = %q, want the password redacted", body)
+	}
+}
+
+func TestLogger_CaptureBodies_SkipsBinary(t *testing.T) {
+	router, logs := newBodyLogRouter(DefaultLoggerConfig(), func(c *gin.Context) {
+		_, _ = io.ReadAll(c.Request.Body)
+		c.Data(http.StatusBadRequest, "image/png", []byte{0x89, 'P', 'N', 'G'})
+	})
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	_ = writer.WriteField("password", "hunter2")
+	_ = writer.Close()
+	req := httptest.NewRequest(http.MethodPost, "/test", &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, key := range []string{"request_body", "response_body"} {
+		if _, ok := loggedField(t, logs, key); ok {
+			t.Errorf("%s should not be logged for binary content", key)
+		}
+	}
+}
+
+func TestLogger_CaptureBodies_Streaming(t *testing.T) {
+	router, logs := newBodyLogRouter(DefaultLoggerConfig(), func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+		c.Header("Content-Type", "text/plain")
+		_, _ = c.Writer.WriteString("partial")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString(" failure")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/test", nil))
+
+	if !w.Flushed {
+		t.Error("Flush should reach the client")
+	}
+	if body, _ := loggedField(t, logs, "response_body"); body != "partial failure" {
+		t.Errorf("response_body = %q, want %q", body, "partial failure")
+	}
+}
+
 // Recovery Middleware Tests
 func TestRecovery(t *testing.T) {
 	logger := zap.NewNop()
@@ -389,6 +715,103 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 	})
 }
 
+func TestAuthMiddleware_Authenticate_RevokedToken(t *testing.T) {
+	provider := newTestJWTProvider()
+	secService := newTestSecurityService(provider)
+	authMiddleware := NewAuthMiddleware(provider, secService)
+	denylist := mocks.NewMockTokenDenylist()
+	authMiddleware.SetTokenDenylist(denylist)
+
+	router := newTestRouter()
+	router.GET("/protected", authMiddleware.Authenticate(), func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+	router.GET("/optional", authMiddleware.OptionalAuth(), func(c *gin.Context) {
+		if secService.GetCurrentClaims(c) != nil {
+			c.String(http.StatusOK, "authenticated")
+			return
+		}
+		c.String(http.StatusOK, "anonymous")
+	})
+
+	request := func(path, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	user := &entity.User{ID: 1, Username: "test", Email: "test@test.com", Role: entity.RoleUser}
+	revoked, _ := provider.GenerateAccessToken(user)
+	valid, _ := provider.GenerateAccessToken(user)
+	claims, _ := provider.ValidateAccessToken(revoked)
+	_ = denylist.Revoke(context.Background(), claims.ID, claims.ExpiresAt.Time)
+
+	if w := request("/protected", revoked); w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "revoked") {
+		t.Errorf("revoked token: Status = %v, body = %s, want 401 revoked", w.Code, w.Body.String())
+	}
+	if w := request("/protected", valid); w.Code != http.StatusOK {
+		t.Errorf("other token: Status = %v, want %v", w.Code, http.StatusOK)
+	}
+	if w := request("/optional", revoked); w.Body.String() != "anonymous" {
+		t.Errorf("OptionalAuth with revoked token = %s, want anonymous", w.Body.String())
+	}
+
+	// Revoking all of a user's tokens rejects tokens issued before then
+	_ = denylist.RevokeAllForUser(context.Background(), 1, time.Hour)
+	if w := request("/protected", valid); w.Code != http.StatusUnauthorized {
+		t.Errorf("after revoking all: Status = %v, want %v", w.Code, http.StatusUnauthorized)
+	}
+
+	// An unavailable denylist does not lock everyone out
+	denylist.IsRevokedErr = errors.New("redis down")
+	if w := request("/protected", valid); w.Code != http.StatusOK {
+		t.Errorf("denylist error: Status = %v, want %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestRedisTokenDenylist(t *testing.T) {
+	testutil.SkipIfNoRedis(t)
+	client := testutil.NewTestRedisClient(t, testutil.DefaultTestConfig())
+	denylist := security.NewRedisTokenDenylist(client)
+	ctx := context.Background()
+
+	provider := newTestJWTProvider()
+	user := &entity.User{ID: uint(time.Now().UnixNano() % 1_000_000_000), Username: "test", Email: "test@test.com"}
+	token, _ := provider.GenerateAccessToken(user)
+	claims, _ := provider.ValidateAccessToken(token)
+
+	if revoked, err := denylist.IsRevoked(ctx, claims); err != nil || revoked {
+		t.Fatalf("IsRevoked() = %v, %v, want false", revoked, err)
+	}
+
+	if err := denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if revoked, err := denylist.IsRevoked(ctx, claims); err != nil || !revoked {
+		t.Errorf("IsRevoked() after Revoke = %v, %v, want true", revoked, err)
+	}
+	if ttl := client.TTL(ctx, "arcana:revoked:jti:"+claims.ID).Val(); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("TTL = %v, want the remaining token lifetime", ttl)
+	}
+
+	// Tokens issued in an earlier second than RevokeAllForUser are revoked
+	other, _ := provider.GenerateAccessToken(user)
+	otherClaims, _ := provider.ValidateAccessToken(other)
+	otherClaims.IssuedAt.Time = otherClaims.IssuedAt.Add(-time.Second)
+	if err := denylist.RevokeAllForUser(ctx, user.ID, time.Minute); err != nil {
+		t.Fatalf("RevokeAllForUser() error = %v", err)
+	}
+	if revoked, err := denylist.IsRevoked(ctx, otherClaims); err != nil || !revoked {
+		t.Errorf("IsRevoked() after RevokeAllForUser = %v, %v, want true", revoked, err)
+	}
+	otherClaims.IssuedAt.Time = time.Now().Add(time.Second)
+	if revoked, _ := denylist.IsRevoked(ctx, otherClaims); revoked {
+		t.Error("tokens issued after RevokeAllForUser should be valid")
+	}
+}
+
 func TestAuthMiddleware_OptionalAuth(t *testing.T) {
 	provider := newTestJWTProvider()
 	secService := newTestSecurityService(provider)
@@ -508,80 +931,1604 @@ func TestAuthMiddleware_RequireAdmin(t *testing.T) {
 	})
 }
 
-// Helper function tests
-func TestJoinStrings(t *testing.T) {
+func TestAuthMiddleware_RequireVerified(t *testing.T) {
+	provider := newTestJWTProvider()
+	secService := newTestSecurityService(provider)
+	authMiddleware := NewAuthMiddleware(provider, secService)
+
+	router := newTestRouter()
+	router.GET("/verified", authMiddleware.Authenticate(), authMiddleware.RequireVerified(), func(c *gin.Context) {
+		c.String(http.StatusOK, "verified")
+	})
+	router.GET("/no-auth", authMiddleware.RequireVerified(), func(c *gin.Context) {
+		c.String(http.StatusOK, "verified")
+	})
+
 	tests := []struct {
-		name     string
-		input    []string
-		expected string
+		name       string
+		path       string
+		verified   bool
+		wantStatus int
 	}{
-		{"empty", []string{}, ""},
-		{"single", []string{"one"}, "one"},
-		{"multiple", []string{"one", "two", "three"}, "one, two, three"},
+		{"verified user", "/verified", true, http.StatusOK},
+		{"unverified user", "/verified", false, http.StatusForbidden},
+		{"unauthenticated", "/no-auth", true, http.StatusUnauthorized},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := joinStrings(tt.input)
-			if result != tt.expected {
-				t.Errorf("joinStrings() = %v, want %v", result, tt.expected)
+			user := &entity.User{ID: 1, Username: "user", Email: "user@test.com", Role: entity.RoleUser, IsVerified: tt.verified}
+			token, _ := provider.GenerateAccessToken(user)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", w.Code, tt.wantStatus)
 			}
 		})
 	}
 }
 
-// Benchmarks
-func BenchmarkRequestID(b *testing.B) {
+func TestAuthMiddleware_RequirePermission(t *testing.T) {
+	provider := newTestJWTProvider()
+	provider.SetRolePermissions(security.NewRolePermissions(map[string][]string{
+		"USER": {security.PermissionJobsManageQueues},
+	}))
+	secService := newTestSecurityService(provider)
+	apiKeyRepo := mocks.NewMockAPIKeyRepository()
+	secService.SetAPIKeyRepository(apiKeyRepo)
+	authMiddleware := NewAuthMiddleware(provider, secService)
+
+	purgeKey := newTestAPIKey(t, apiKeyRepo, security.PermissionJobsPurgeDLQ)
+	admin, _ := provider.GenerateAccessToken(&entity.User{ID: 1, Username: "admin", Role: entity.RoleAdmin})
+	user, _ := provider.GenerateAccessToken(&entity.User{ID: 2, Username: "user", Role: entity.RoleUser})
+
 	router := newTestRouter()
-	router.Use(RequestID())
-	router.GET("/test", func(c *gin.Context) {
+	router.Use(authMiddleware.AuthenticateOrAPIKey())
+	router.POST("/pause", authMiddleware.RequirePermission(security.PermissionJobsManageQueues), func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+	router.DELETE("/dlq", authMiddleware.RequirePermission(security.PermissionJobsPurgeDLQ), func(c *gin.Context) {
 		c.String(http.StatusOK, "OK")
 	})
 
-	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		apiKey     string
+		wantStatus int
+	}{
+		{"admin holds every permission", http.MethodDelete, "/dlq", admin, "", http.StatusOK},
+		{"user with permission", http.MethodPost, "/pause", user, "", http.StatusOK},
+		{"user without permission", http.MethodDelete, "/dlq", user, "", http.StatusForbidden},
+		{"key with scope", http.MethodDelete, "/dlq", "", purgeKey, http.StatusOK},
+		{"key without scope", http.MethodPost, "/pause", "", purgeKey, http.StatusForbidden},
 	}
-}
 
-func BenchmarkCORS(b *testing.B) {
-	cfg := DefaultCORSConfig()
-	router := newTestRouter()
-	router.Use(CORS(cfg))
-	router.GET("/test", func(c *gin.Context) {
-		c.String(http.StatusOK, "OK")
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			router.ServeHTTP(w, req)
 
-	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	req.Header.Set("Origin", "http://example.com")
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
+			if w.Code != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		r := newTestRouter()
+		r.GET("/guarded", authMiddleware.RequirePermission(security.PermissionJobsPurgeDLQ), func(c *gin.Context) {
+			c.String(http.StatusOK, "OK")
+		})
 		w := httptest.NewRecorder()
-		router.ServeHTTP(w, req)
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/guarded", nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Status = %v, want %v", w.Code, http.StatusUnauthorized)
+		}
+	})
+}
+
+// newTestAPIKey stores an API key with the given scopes and returns the raw key
+func newTestAPIKey(t *testing.T, repo *mocks.MockAPIKeyRepository, scopes ...string) string {
+	t.Helper()
+	key, prefix, err := security.GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	apiKey := &entity.APIKey{Name: "svc", Prefix: prefix, KeyHash: security.HashAPIKey(key)}
+	apiKey.SetScopes(scopes)
+	if err := repo.Create(context.Background(), apiKey); err != nil {
+		t.Fatalf("Create() error = %v", err)
 	}
+	return key
 }
 
-func BenchmarkAuthenticate(b *testing.B) {
+func TestAuthMiddleware_APIKeyAuth(t *testing.T) {
 	provider := newTestJWTProvider()
 	secService := newTestSecurityService(provider)
+	apiKeyRepo := mocks.NewMockAPIKeyRepository()
+	secService.SetAPIKeyRepository(apiKeyRepo)
 	authMiddleware := NewAuthMiddleware(provider, secService)
 
+	key := newTestAPIKey(t, apiKeyRepo, "jobs:read")
+	revokedKey := newTestAPIKey(t, apiKeyRepo)
+	_, _ = apiKeyRepo.Revoke(context.Background(), 2)
+
 	router := newTestRouter()
-	router.Use(authMiddleware.Authenticate())
-	router.GET("/test", func(c *gin.Context) {
-		c.String(http.StatusOK, "OK")
+	router.GET("/service", authMiddleware.APIKeyAuth(), func(c *gin.Context) {
+		claims := secService.GetCurrentClaims(c)
+		c.String(http.StatusOK, string(claims.Role))
 	})
 
-	user := &entity.User{ID: 1, Username: "test", Email: "test@test.com", Role: entity.RoleUser}
-	token, _ := provider.GenerateAccessToken(user)
+	tests := []struct {
+		name       string
+		key        string
+		wantStatus int
+	}{
+		{"valid key", key, http.StatusOK},
+		{"missing key", "", http.StatusUnauthorized},
+		{"unknown key", "ak_unknown", http.StatusUnauthorized},
+		{"revoked key", revokedKey, http.StatusUnauthorized},
+	}
 
-	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	b.ResetTimer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/service", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != string(entity.RoleService) {
+				t.Errorf("Role = %q, want %q", w.Body.String(), entity.RoleService)
+			}
+		})
+	}
+
+	t.Run("repository error", func(t *testing.T) {
+		apiKeyRepo.GetByKeyHashErr = errors.New("db down")
+		defer func() { apiKeyRepo.GetByKeyHashErr = nil }()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/service", nil)
+		req.Header.Set(APIKeyHeader, key)
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("Status = %v, want %v", w.Code, http.StatusInternalServerError)
+		}
+	})
+}
+
+func TestAuthMiddleware_RequireScope(t *testing.T) {
+	provider := newTestJWTProvider()
+	secService := newTestSecurityService(provider)
+	apiKeyRepo := mocks.NewMockAPIKeyRepository()
+	secService.SetAPIKeyRepository(apiKeyRepo)
+	authMiddleware := NewAuthMiddleware(provider, secService)
+
+	readKey := newTestAPIKey(t, apiKeyRepo, "jobs:read")
+	user := &entity.User{ID: 1, Username: "user", Email: "user@test.com", Role: entity.RoleUser}
+	token, _ := provider.GenerateAccessToken(user)
+
+	router := newTestRouter()
+	router.Use(authMiddleware.AuthenticateOrAPIKey())
+	router.GET("/read", authMiddleware.RequireScope("jobs:read"), func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+	router.POST("/write", authMiddleware.RequireScope("jobs:write"), func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+	router.POST("/admin", authMiddleware.RequireAdmin(), func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		apiKey     string
+		token      string
+		wantStatus int
+	}{
+		{"key with scope", http.MethodGet, "/read", readKey, "", http.StatusOK},
+		{"key without scope", http.MethodPost, "/write", readKey, "", http.StatusForbidden},
+		{"key on admin route", http.MethodPost, "/admin", readKey, "", http.StatusForbidden},
+		{"user is not limited by scopes", http.MethodPost, "/write", "", token, http.StatusOK},
+		{"unauthenticated", http.MethodGet, "/read", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// RateLimit Middleware Tests
+func newTestRateLimitRouter(limiter resilience.KeyedLimiter, keyFunc func(*gin.Context) string) *gin.Engine {
+	router := newTestRouter()
+	router.Use(RateLimit(limiter, keyFunc))
+	router.GET("/limited", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+	router.OPTIONS("/limited", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := resilience.NewTokenBucketLimiter(&resilience.RateLimiterConfig{
+		Name: "test", Rate: 1, Period: time.Minute, BurstSize: 2,
+	})
+	router := newTestRateLimitRouter(resilience.SharedLimiter(limiter), RateLimitByIP)
+
+	t.Run("allowed requests get rate limit headers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/limited", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status = %v, want %v", w.Code, http.StatusOK)
+		}
+		if got := w.Header().Get(RateLimitLimitHeader); got != "2" {
+			t.Errorf("%s = %q, want 2", RateLimitLimitHeader, got)
+		}
+		if got := w.Header().Get(RateLimitRemainingHeader); got != "1" {
+			t.Errorf("%s = %q, want 1", RateLimitRemainingHeader, got)
+		}
+		if got := w.Header().Get(RateLimitResetHeader); got != "60" {
+			t.Errorf("%s = %q, want 60", RateLimitResetHeader, got)
+		}
+	})
+
+	t.Run("denied requests get 429 with Retry-After", func(t *testing.T) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/limited", nil))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/limited", nil))
+
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Status = %v, want %v", w.Code, http.StatusTooManyRequests)
+		}
+		retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+		if err != nil || retryAfter < 1 || retryAfter > 60 {
+			t.Errorf("Retry-After = %q, want 1-60 seconds", w.Header().Get("Retry-After"))
+		}
+	})
+
+	t.Run("preflight requests bypass the limiter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/limited", nil))
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("Status = %v, want %v", w.Code, http.StatusNoContent)
+		}
+	})
+}
+
+func TestRateLimit_PerKey(t *testing.T) {
+	limiter := resilience.NewKeyedRateLimiter(&resilience.KeyedRateLimiterConfig{
+		RateLimiterConfig: resilience.RateLimiterConfig{Name: "test", Rate: 1, Period: time.Minute, BurstSize: 1},
+		MaxKeys:           10,
+	})
+	router := newTestRateLimitRouter(limiter, RateLimitByIP)
+
+	request := func(ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := request("10.0.0.1"); code != http.StatusOK {
+		t.Errorf("first client Status = %v, want %v", code, http.StatusOK)
+	}
+	if code := request("10.0.0.1"); code != http.StatusTooManyRequests {
+		t.Errorf("first client repeat Status = %v, want %v", code, http.StatusTooManyRequests)
+	}
+	if code := request("10.0.0.2"); code != http.StatusOK {
+		t.Errorf("second client Status = %v, want %v", code, http.StatusOK)
+	}
+}
+
+func TestRateLimitKeyFuncs(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	c.Request.RemoteAddr = "10.0.0.1:1234"
+
+	if got := RateLimitByIP(c); got != "ip:10.0.0.1" {
+		t.Errorf("RateLimitByIP() = %q, want ip:10.0.0.1", got)
+	}
+	if got := RateLimitByUser(c); got != "ip:10.0.0.1" {
+		t.Errorf("RateLimitByUser() anonymous = %q, want ip:10.0.0.1", got)
+	}
+
+	c.Set(security.ContextKeyClaims, &security.UserClaims{UserID: 42})
+	if got := RateLimitByUser(c); got != "user:42" {
+		t.Errorf("RateLimitByUser() = %q, want user:42", got)
+	}
+
+	if got := RateLimitByRoute(c); got != "route:GET " {
+		t.Errorf("RateLimitByRoute() unmatched = %q, want route:GET ", got)
+	}
+}
+
+// Timeout Middleware Tests
+func TestTimeout(t *testing.T) {
+	t.Run("slow handler gets 503 and a cancelled context", func(t *testing.T) {
+		cancelled := make(chan error, 1)
+		router := newTestRouter()
+		router.Use(Timeout(20 * time.Millisecond))
+		router.GET("/slow", func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			cancelled <- c.Request.Context().Err()
+			c.Header("X-Late", "true")
+			c.String(http.StatusOK, "too late")
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("Status = %v, want %v", w.Code, http.StatusServiceUnavailable)
+		}
+		if !strings.Contains(w.Body.String(), `"message":"request timed out"`) || strings.Contains(w.Body.String(), "too late") {
+			t.Errorf("Body = %s, want only the timeout envelope", w.Body.String())
+		}
+		if w.Header().Get("X-Late") != "" {
+			t.Error("headers set after the timeout should be discarded")
+		}
+		if err := <-cancelled; err != context.DeadlineExceeded {
+			t.Errorf("handler context error = %v, want DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("fast handler responds normally", func(t *testing.T) {
+		router := newTestRouter()
+		router.Use(Timeout(time.Second))
+		router.GET("/fast", func(c *gin.Context) {
+			if _, ok := c.Request.Context().Deadline(); !ok {
+				t.Error("request context should carry a deadline")
+			}
+			c.Header("X-Custom", "value")
+			c.String(http.StatusCreated, "OK")
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+		if w.Code != http.StatusCreated || w.Body.String() != "OK" {
+			t.Errorf("response = %v %q, want 201 OK", w.Code, w.Body.String())
+		}
+		if w.Header().Get("X-Custom") != "value" {
+			t.Error("handler headers should be kept")
+		}
+	})
+
+	t.Run("handler that already responded is not overwritten", func(t *testing.T) {
+		router := newTestRouter()
+		router.Use(Timeout(20 * time.Millisecond))
+		router.GET("/stream", func(c *gin.Context) {
+			c.String(http.StatusOK, "partial")
+			c.Writer.Flush()
+			<-c.Request.Context().Done()
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != "partial" {
+			t.Errorf("response = %v %q, want 200 partial", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("route group overrides the timeout", func(t *testing.T) {
+		router := newTestRouter()
+		router.Use(Timeout(20 * time.Millisecond))
+		slow := router.Group("/slow", Timeout(time.Second))
+		slow.GET("/work", func(c *gin.Context) {
+			time.Sleep(50 * time.Millisecond)
+			c.String(http.StatusOK, "done")
+		})
+		unlimited := router.Group("/stream", Timeout(0))
+		unlimited.GET("/events", func(c *gin.Context) {
+			if _, ok := c.Request.Context().Deadline(); ok {
+				t.Error("Timeout(0) should remove the deadline")
+			}
+			c.Status(http.StatusNoContent)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow/work", nil))
+		if w.Code != http.StatusOK || w.Body.String() != "done" {
+			t.Errorf("response = %v %q, want 200 done", w.Code, w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream/events", nil))
+		if w.Code != http.StatusNoContent {
+			t.Errorf("Status = %v, want %v", w.Code, http.StatusNoContent)
+		}
+	})
+}
+
+// MaxBodySize Middleware Tests
+func newTestBodySizeRouter(maxBytes int64) *gin.Engine {
+	router := newTestRouter()
+	router.Use(MaxBodySize(maxBytes))
+	router.POST("/json", func(c *gin.Context) {
+		var body map[string]any
+		if err := c.ShouldBindJSON(&body); err != nil {
+			if BodyTooLarge(c, err) {
+				return
+			}
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestMaxBodySize(t *testing.T) {
+	router := newTestBodySizeRouter(32)
+	large := `{"data":"` + strings.Repeat("x", 64) + `"}`
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		wantStatus    int
+	}{
+		{"within limit", `{"a":1}`, 7, http.StatusOK},
+		{"declared length over limit", large, int64(len(large)), http.StatusRequestEntityTooLarge},
+		{"streamed body over limit", large, -1, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(tt.body))
+			req.ContentLength = tt.contentLength
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusRequestEntityTooLarge && !strings.Contains(w.Body.String(), "exceeds the 32 bytes limit") {
+				t.Errorf("Body = %s, want the limit named", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMaxBodySize_Override(t *testing.T) {
+	router := newTestRouter()
+	router.Use(MaxBodySize(8))
+	router.POST("/upload", MaxBodySize(1024), func(c *gin.Context) {
+		file, _, err := c.Request.FormFile("file")
+		if err != nil {
+			if BodyTooLarge(c, err) {
+				return
+			}
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		file.Close()
+		c.Status(http.StatusOK)
+	})
+
+	upload := func(size int) int {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "plugin.so")
+		part.Write(bytes.Repeat([]byte("x"), size))
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := upload(100); code != http.StatusOK {
+		t.Errorf("upload within route limit Status = %v, want %v", code, http.StatusOK)
+	}
+	if code := upload(2048); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("upload over route limit Status = %v, want %v", code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{512, "512 bytes"},
+		{1024, "1 KB"},
+		{1536, "1.5 KB"},
+		{50 << 20, "50 MB"},
+		{2 << 30, "2 GB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+// Gzip Middleware Tests
+func newTestGzipRouter(config GzipConfig) *gin.Engine {
+	router := newTestRouter()
+	router.Use(GzipWithConfig(config))
+	router.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": strings.Repeat("arcana ", 500)})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": "small"})
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", bytes.Repeat([]byte{0x89}, 4096))
+	})
+	router.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "br")
+		c.Data(http.StatusOK, "application/json", bytes.Repeat([]byte("x"), 4096))
+	})
+	router.GET("/ws", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("x", 4096))
+	})
+	return router
+}
+
+func TestGzip(t *testing.T) {
+	router := newTestGzipRouter(DefaultGzipConfig())
+
+	get := func(path, acceptEncoding string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("compresses large responses", func(t *testing.T) {
+		w := get("/large", "deflate, gzip;q=0.8")
+
+		if w.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("Content-Encoding = %q, want gzip", w.Header().Get("Content-Encoding"))
+		}
+		if w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("Vary = %q, want Accept-Encoding", w.Header().Get("Vary"))
+		}
+		if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			t.Errorf("Content-Type = %q, want application/json", w.Header().Get("Content-Type"))
+		}
+
+		gz, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("gzip.NewReader() error = %v", err)
+		}
+		body, err := io.ReadAll(gz)
+		if err != nil {
+			t.Fatalf("reading gzip body error = %v", err)
+		}
+		if !strings.Contains(string(body), "arcana arcana") {
+			t.Errorf("decompressed body = %.50s..., want original JSON", body)
+		}
+	})
+
+	t.Run("passes through", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			path           string
+			acceptEncoding string
+			headers        []string
+		}{
+			{"without Accept-Encoding", "/large", "", nil},
+			{"when gzip is refused", "/large", "gzip;q=0", nil},
+			{"responses below the minimum size", "/small", "gzip", nil},
+			{"excluded content types", "/image", "gzip", nil},
+			{"already encoded responses", "/encoded", "gzip", nil},
+			{"WebSocket upgrades", "/ws", "gzip", []string{"Connection", "Upgrade", "Upgrade", "websocket"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := get(tt.path, tt.acceptEncoding, tt.headers...)
+
+				if w.Header().Get("Content-Encoding") == "gzip" {
+					t.Error("response should not be gzip-compressed")
+				}
+				if w.Code != http.StatusOK || w.Body.Len() == 0 {
+					t.Errorf("response = %v with %d bytes, want 200 with the body", w.Code, w.Body.Len())
+				}
+			})
+		}
+	})
+
+	t.Run("configurable threshold and exclusions", func(t *testing.T) {
+		config := DefaultGzipConfig()
+		config.MinSize = 10
+		config.ExcludedContentTypes = []string{"application/json"}
+		router := newTestGzipRouter(config)
+
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Header().Get("Content-Encoding") != "gzip" {
+			t.Error("text response above MinSize should be compressed")
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/large", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Header().Get("Content-Encoding") != "" {
+			t.Error("excluded JSON response should not be compressed")
+		}
+	})
+}
+
+func TestGzip_InvalidLevel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Gzip() with an invalid level should panic")
+		}
+	}()
+	Gzip(42)
+}
+
+// Metrics Middleware Tests
+func TestHTTPMetrics(t *testing.T) {
+	mp, err := observability.NewMetricsProvider(observability.DefaultMetricsConfig(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewMetricsProvider() error = %v", err)
+	}
+	defer mp.Shutdown(context.Background())
+	if err := mp.Register(GlobalHTTPMetrics.Collectors()...); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	router := newTestRouter()
+	router.Use(Metrics())
+	router.GET("/metrics-test/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/metrics", gin.WrapH(mp.Handler()))
+
+	for _, path := range []string{"/metrics-test/users/1", "/metrics-test/users/2", "/metrics-test/users/3"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`arcana_http_requests_total{method="GET",path="/metrics-test/users/:id",status="200"} 3`,
+		`arcana_http_request_duration_seconds_count{method="GET",path="/metrics-test/users/:id",status="200"} 3`,
+		`arcana_http_requests_in_flight{method="GET",path="/metrics-test/users/:id"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+	if strings.Contains(body, "/metrics-test/users/1") {
+		t.Error("metrics output should not contain raw request paths")
+	}
+}
+
+func TestHTTPMetrics_Register(t *testing.T) {
+	mp, err := observability.NewMetricsProvider(observability.DefaultMetricsConfig(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewMetricsProvider() error = %v", err)
+	}
+	defer mp.Shutdown(context.Background())
+
+	if err := mp.Register(GlobalHTTPMetrics.Collectors()...); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := mp.Register(GlobalHTTPMetrics.Collectors()...); err == nil {
+		t.Error("registering the HTTP metrics twice on one registry should fail")
+	}
+}
+
+// Idempotency Middleware Tests
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*IdempotencyRecord
+	err     error
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: make(map[string]*IdempotencyRecord)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	if record, ok := s.records[key]; ok {
+		return record, nil
+	}
+	s.records[key] = &IdempotencyRecord{Fingerprint: fingerprint}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Save(ctx context.Context, key string, record *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = record
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func newTestIdempotencyRouter(store IdempotencyStore, calls *atomic.Int32, handler gin.HandlerFunc) *gin.Engine {
+	router := newTestRouter()
+	router.Use(func(c *gin.Context) {
+		if userID, err := strconv.Atoi(c.GetHeader("X-Test-User")); err == nil {
+			c.Set(security.ContextKeyClaims, &security.UserClaims{UserID: uint(userID)})
+		}
+		c.Next()
+	})
+	router.Use(Idempotency(store))
+	router.POST("/jobs", func(c *gin.Context) {
+		calls.Add(1)
+		handler(c)
+	})
+	router.PUT("/jobs", func(c *gin.Context) {
+		calls.Add(1)
+		handler(c)
+	})
+	return router
+}
+
+func idempotentRequest(router *gin.Engine, method, key, user, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/jobs", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	var calls atomic.Int32
+	router := newTestIdempotencyRouter(newMemoryIdempotencyStore(), &calls, func(c *gin.Context) {
+		c.Header("Location", "/jobs/"+strconv.Itoa(int(calls.Load())))
+		c.JSON(http.StatusCreated, gin.H{"call": calls.Load()})
+	})
+
+	first := idempotentRequest(router, http.MethodPost, "key-1", "1", `{"type":"email"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first Status = %v, want %v", first.Code, http.StatusCreated)
+	}
+
+	t.Run("retry replays the stored response", func(t *testing.T) {
+		w := idempotentRequest(router, http.MethodPost, "key-1", "1", `{"type":"email"}`)
+
+		if calls.Load() != 1 {
+			t.Errorf("handler calls = %d, want 1", calls.Load())
+		}
+		if w.Code != http.StatusCreated || w.Body.String() != first.Body.String() {
+			t.Errorf("replay = %v %s, want %v %s", w.Code, w.Body.String(), first.Code, first.Body.String())
+		}
+		if w.Header().Get("Location") != "/jobs/1" || w.Header().Get(IdempotentReplayedHeader) != "true" {
+			t.Errorf("replay headers = %v", w.Header())
+		}
+	})
+
+	t.Run("different body with the same key is rejected", func(t *testing.T) {
+		w := idempotentRequest(router, http.MethodPost, "key-1", "1", `{"type":"webhook"}`)
+
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Status = %v, want %v", w.Code, http.StatusUnprocessableEntity)
+		}
+	})
+
+	t.Run("keys are scoped per user", func(t *testing.T) {
+		w := idempotentRequest(router, http.MethodPost, "key-1", "2", `{"type":"email"}`)
+
+		if w.Code != http.StatusCreated || w.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("another user's request should run the handler, got %v", w.Code)
+		}
+	})
+
+	t.Run("requests without a key or with idempotent methods pass through", func(t *testing.T) {
+		before := calls.Load()
+		idempotentRequest(router, http.MethodPost, "", "1", `{}`)
+		idempotentRequest(router, http.MethodPost, "", "1", `{}`)
+		idempotentRequest(router, http.MethodPut, "key-put", "1", `{}`)
+		idempotentRequest(router, http.MethodPut, "key-put", "1", `{}`)
+
+		if got := calls.Load() - before; got != 4 {
+			t.Errorf("handler calls = %d, want 4", got)
+		}
+	})
+}
+
+func TestIdempotency_InFlight(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := newTestIdempotencyRouter(newMemoryIdempotencyStore(), &calls, func(c *gin.Context) {
+		close(started)
+		<-release
+		c.Status(http.StatusAccepted)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+	}()
+	<-started
+
+	w := idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+	close(release)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("concurrent Status = %v, want %v", w.Code, http.StatusConflict)
+	}
+	if first := <-done; first.Code != http.StatusAccepted {
+		t.Errorf("first Status = %v, want %v", first.Code, http.StatusAccepted)
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	var calls atomic.Int32
+	router := newTestIdempotencyRouter(newMemoryIdempotencyStore(), &calls, func(c *gin.Context) {
+		if calls.Load() == 1 {
+			c.JSON(http.StatusInternalServerError, response.NewError[any]("boom"))
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+	w := idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+
+	if calls.Load() != 2 || w.Code != http.StatusCreated {
+		t.Errorf("retry after a server error: calls = %d, Status = %v, want 2 and %v", calls.Load(), w.Code, http.StatusCreated)
+	}
+}
+
+func TestIdempotency_PanicsAreNotStored(t *testing.T) {
+	var calls atomic.Int32
+	store := newMemoryIdempotencyStore()
+	router := newTestIdempotencyRouter(store, &calls, func(c *gin.Context) {
+		if calls.Load() == 1 {
+			panic("boom")
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	// The panic reaches the caller, as there is no recovery middleware
+	func() {
+		defer func() { _ = recover() }()
+		idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+	}()
+	w := idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+
+	if calls.Load() != 2 || w.Code != http.StatusCreated {
+		t.Errorf("retry after a panic: calls = %d, Status = %v, want 2 and %v", calls.Load(), w.Code, http.StatusCreated)
+	}
+}
+
+func TestIdempotency_UnwrittenResponsesAreNotStored(t *testing.T) {
+	var calls atomic.Int32
+	router := newTestIdempotencyRouter(newMemoryIdempotencyStore(), &calls, func(c *gin.Context) {
+		if calls.Load() == 1 {
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+	w := idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+
+	if calls.Load() != 2 || w.Code != http.StatusCreated {
+		t.Errorf("retry after an empty response: calls = %d, Status = %v, want 2 and %v", calls.Load(), w.Code, http.StatusCreated)
+	}
+}
+
+func TestIdempotency_StoreUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	store := newMemoryIdempotencyStore()
+	store.err = errors.New("connection refused")
+	router := newTestIdempotencyRouter(store, &calls, func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	w := idempotentRequest(router, http.MethodPost, "key-1", "1", `{}`)
+
+	if calls.Load() != 1 || w.Code != http.StatusCreated {
+		t.Errorf("calls = %d, Status = %v, want the request to proceed", calls.Load(), w.Code)
+	}
+}
+
+func TestRedisIdempotencyStore(t *testing.T) {
+	testutil.SkipIfNoRedis(t)
+	client := testutil.NewTestRedisClient(t, testutil.DefaultTestConfig())
+	store := NewRedisIdempotencyStore(client)
+	ctx := context.Background()
+	key := testutil.GenerateTestID()
+	defer store.Release(ctx, key)
+
+	record, err := store.Reserve(ctx, key, "fp", time.Minute)
+	if err != nil || record != nil {
+		t.Fatalf("Reserve() = %v, %v, want the key claimed", record, err)
+	}
+
+	record, err = store.Reserve(ctx, key, "fp", time.Minute)
+	if err != nil || record == nil || record.Completed {
+		t.Fatalf("Reserve() = %+v, %v, want the pending record", record, err)
+	}
+
+	saved := &IdempotencyRecord{Fingerprint: "fp", Completed: true, Status: http.StatusCreated, Body: []byte(`{"ok":true}`)}
+	if err := store.Save(ctx, key, saved, time.Minute); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	record, err = store.Reserve(ctx, key, "fp", time.Minute)
+	if err != nil || record == nil || !record.Completed || string(record.Body) != `{"ok":true}` {
+		t.Errorf("Reserve() = %+v, %v, want the saved record", record, err)
+	}
+
+	if err := store.Release(ctx, key); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if record, _ := store.Reserve(ctx, key, "fp", time.Minute); record != nil {
+		t.Error("Reserve() after Release() should claim the key")
+	}
+}
+
+// Tracing Middleware Tests
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	router := newTestRouter()
+	router.Use(Tracing(tp.Tracer("test")))
+	var handlerSpan trace.SpanContext
+	router.GET("/users/:id", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /users/:id" {
+		t.Errorf("span name = %q, want route template", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("SpanKind = %v, want server", span.SpanKind())
+	}
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("trace ID = %s, want the caller's %s", got, traceID)
+	}
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %s, want the caller's span", got)
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("request context should carry the server span")
+	}
+	if span.Status().Code == codes.Error {
+		t.Error("successful request should not have error status")
+	}
+}
+
+func TestTracing_ServerError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	router := newTestRouter()
+	router.Use(Tracing(tp.Tracer("test")))
+	router.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(errors.New("database unavailable"))
+		c.Status(http.StatusInternalServerError)
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("5xx status = %v, want error", spans[0].Status().Code)
+	}
+	if len(spans[0].Events()) == 0 {
+		t.Error("handler errors should be recorded on the span")
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Error("4xx responses should not mark the server span as failed")
+	}
+}
+
+// Helper function tests
+func TestJoinStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{"empty", []string{}, ""},
+		{"single", []string{"one"}, "one"},
+		{"multiple", []string{"one", "two", "three"}, "one, two, three"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := joinStrings(tt.input)
+			if result != tt.expected {
+				t.Errorf("joinStrings() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// Benchmarks
+func BenchmarkRequestID(b *testing.B) {
+	router := newTestRouter()
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkCORS(b *testing.B) {
+	cfg := DefaultCORSConfig()
+	router := newTestRouter()
+	router.Use(CORS(cfg))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Origin", "http://example.com")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkAuthenticate(b *testing.B) {
+	provider := newTestJWTProvider()
+	secService := newTestSecurityService(provider)
+	authMiddleware := NewAuthMiddleware(provider, secService)
+
+	router := newTestRouter()
+	router.Use(authMiddleware.Authenticate())
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	user := &entity.User{ID: 1, Username: "test", Email: "test@test.com", Role: entity.RoleUser}
+	token, _ := provider.GenerateAccessToken(user)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 	}
 }
+
+// IP Filter Tests
+func newIPFilterRouter(t *testing.T, cfg IPFilterConfig) *gin.Engine {
+	t.Helper()
+	filter, err := IPFilter(cfg)
+	if err != nil {
+		t.Fatalf("IPFilter() error = %v", err)
+	}
+
+	router := newTestRouter()
+	router.GET("/public", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+	admin := router.Group("/admin", filter)
+	admin.GET("", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+	return router
+}
+
+func ipFilterStatus(router *gin.Engine, path, remoteAddr, forwardedFor string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set(ForwardedForHeader, forwardedFor)
+	}
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestIPFilter_CIDRMatching(t *testing.T) {
+	router := newIPFilterRouter(t, IPFilterConfig{
+		Allow: []string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32"},
+		Deny:  []string{"10.1.0.0/16", "2001:db8:bad::/48"},
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       int
+	}{
+		{"IPv4 in allowed CIDR", "10.2.3.4:5000", http.StatusOK},
+		{"allowed single IPv4", "192.168.1.10:5000", http.StatusOK},
+		{"IPv4 outside allow list", "192.168.1.11:5000", http.StatusForbidden},
+		{"IPv4 in denied CIDR", "10.1.2.3:5000", http.StatusForbidden},
+		{"IPv4-mapped IPv6", "[::ffff:10.2.3.4]:5000", http.StatusOK},
+		{"IPv6 in allowed CIDR", "[2001:db8:1::1]:5000", http.StatusOK},
+		{"IPv6 in denied CIDR", "[2001:db8:bad::1]:5000", http.StatusForbidden},
+		{"IPv6 outside allow list", "[2001:db9::1]:5000", http.StatusForbidden},
+		{"unparseable peer", "not-an-ip", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ipFilterStatus(router, "/admin", tt.remoteAddr, ""); got != tt.want {
+				t.Errorf("status = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Routes outside the filtered group stay open
+	if got := ipFilterStatus(router, "/public", "203.0.113.1:5000", ""); got != http.StatusOK {
+		t.Errorf("public status = %v, want %v", got, http.StatusOK)
+	}
+}
+
+func TestIPFilter_DenyOnly(t *testing.T) {
+	router := newIPFilterRouter(t, IPFilterConfig{Deny: []string{"203.0.113.0/24"}})
+
+	if got := ipFilterStatus(router, "/admin", "198.51.100.1:5000", ""); got != http.StatusOK {
+		t.Errorf("status = %v, want %v", got, http.StatusOK)
+	}
+	if got := ipFilterStatus(router, "/admin", "203.0.113.9:5000", ""); got != http.StatusForbidden {
+		t.Errorf("status = %v, want %v", got, http.StatusForbidden)
+	}
+}
+
+func TestIPFilter_TrustedProxies(t *testing.T) {
+	cfg := IPFilterConfig{Allow: []string{"10.0.0.0/8"}}
+
+	t.Run("X-Forwarded-For ignored without trusted proxies", func(t *testing.T) {
+		router := newIPFilterRouter(t, cfg)
+		if got := ipFilterStatus(router, "/admin", "203.0.113.1:5000", "10.0.0.1"); got != http.StatusForbidden {
+			t.Errorf("spoofed status = %v, want %v", got, http.StatusForbidden)
+		}
+	})
+
+	cfg.TrustedProxies = []string{"172.16.0.0/12", "fd00::/8"}
+	router := newIPFilterRouter(t, cfg)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		want         int
+	}{
+		{"client behind trusted proxy", "172.16.0.5:5000", "10.0.0.1", http.StatusOK},
+		{"client behind proxy chain", "172.16.0.5:5000", "10.0.0.1, 172.16.0.9", http.StatusOK},
+		{"IPv6 trusted proxy", "[fd00::1]:5000", "10.0.0.1", http.StatusOK},
+		{"denied client behind trusted proxy", "172.16.0.5:5000", "203.0.113.1", http.StatusForbidden},
+		{"spoofed hop before the real client", "172.16.0.5:5000", "10.0.0.1, 203.0.113.1", http.StatusForbidden},
+		{"untrusted peer", "203.0.113.1:5000", "10.0.0.1", http.StatusForbidden},
+		{"malformed hop", "172.16.0.5:5000", "10.0.0.1, garbage", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ipFilterStatus(router, "/admin", tt.remoteAddr, tt.forwardedFor); got != tt.want {
+				t.Errorf("status = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPFilter_InvalidConfig(t *testing.T) {
+	for _, cfg := range []IPFilterConfig{
+		{Allow: []string{"10.0.0.0/33"}},
+		{Deny: []string{"not-an-ip"}},
+		{TrustedProxies: []string{"172.16.0.0/"}},
+	} {
+		if _, err := IPFilter(cfg); err == nil {
+			t.Errorf("IPFilter(%+v) error = nil, want an error", cfg)
+		}
+	}
+}
+
+// API Version Middleware Tests
+func TestAPIVersion(t *testing.T) {
+	router := newTestRouter()
+	RegisterAPIVersions(router, DefaultAPIVersionConfig(), func(api *gin.RouterGroup) {
+		api.GET("/users/:name", func(c *gin.Context) {
+			c.String(http.StatusOK, strconv.Itoa(GetAPIVersion(c)))
+		})
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		headers    map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{"path v1", "/api/v1/users/alice", nil, http.StatusOK, "1"},
+		{"path v2", "/api/v2/users/alice", nil, http.StatusOK, "2"},
+		{"path wins over header", "/api/v1/users/alice", map[string]string{APIVersionHeader: "2"}, http.StatusOK, "1"},
+		{"unsupported path", "/api/v3/users/alice", nil, http.StatusNotFound, ""},
+		{"default", "/api/users/alice", nil, http.StatusOK, "1"},
+		{"segment after the version", "/api/users/v2", nil, http.StatusOK, "1"},
+		{"header", "/api/users/alice", map[string]string{APIVersionHeader: "2"}, http.StatusOK, "2"},
+		{"prefixed header", "/api/users/alice", map[string]string{APIVersionHeader: "v2"}, http.StatusOK, "2"},
+		{"accept parameter", "/api/users/alice", map[string]string{"Accept": "application/json; version=2"}, http.StatusOK, "2"},
+		{"accept vendor type", "/api/users/alice", map[string]string{"Accept": "text/html, application/vnd.arcana.v2+json"}, http.StatusOK, "2"},
+		{"unsupported header", "/api/users/alice", map[string]string{APIVersionHeader: "3"}, http.StatusBadRequest, ""},
+		{"invalid header", "/api/users/alice", map[string]string{APIVersionHeader: "latest"}, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" {
+				if w.Body.String() != tt.wantBody {
+					t.Errorf("Version = %v, want %v", w.Body.String(), tt.wantBody)
+				}
+				if got := w.Header().Get(APIVersionHeader); got != tt.wantBody {
+					t.Errorf("%s = %v, want %v", APIVersionHeader, got, tt.wantBody)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIVersion_Vary(t *testing.T) {
+	router := newTestRouter()
+	RegisterAPIVersions(router, DefaultAPIVersionConfig(), func(api *gin.RouterGroup) {
+		api.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/test", nil))
+	if !strings.Contains(w.Header().Get("Vary"), APIVersionHeader) {
+		t.Error("Header-selected responses should vary on the version header")
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/test", nil))
+	if w.Header().Get("Vary") != "" {
+		t.Error("Path-selected responses should not vary on headers")
+	}
+}
+
+func TestAPIVersion_Deprecated(t *testing.T) {
+	deprecated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	config := DefaultAPIVersionConfig()
+	config.Deprecated = map[int]APIDeprecation{
+		1: {At: deprecated, Sunset: sunset, Link: "https://example.com/migrate-to-v2"},
+	}
+
+	router := newTestRouter()
+	RegisterAPIVersions(router, config, func(api *gin.RouterGroup) {
+		api.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/test", nil))
+	if got, want := w.Header().Get("Deprecation"), "@1767225600"; got != want {
+		t.Errorf("Deprecation = %v, want %v", got, want)
+	}
+	if got, want := w.Header().Get("Sunset"), "Thu, 31 Dec 2026 00:00:00 GMT"; got != want {
+		t.Errorf("Sunset = %v, want %v", got, want)
+	}
+	if got, want := w.Header().Get("Link"), `<https://example.com/migrate-to-v2>; rel="deprecation"`; got != want {
+		t.Errorf("Link = %v, want %v", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/test", nil))
+	if w.Header().Get("Deprecation") != "" || w.Header().Get("Sunset") != "" {
+		t.Error("Current versions should not be marked deprecated")
+	}
+}
+
+func TestGetAPIVersion_NotSet(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if got := GetAPIVersion(c); got != 0 {
+		t.Errorf("GetAPIVersion() = %v, want 0", got)
+	}
+}
+
+// CSRF Middleware Tests
+func newCSRFRouter(config CSRFConfig) *gin.Engine {
+	router := newTestRouter()
+	router.Use(CSRF(config))
+	handler := func(c *gin.Context) { c.String(http.StatusOK, GetCSRFToken(c)) }
+	router.GET("/form", handler)
+	router.POST("/submit", handler)
+	router.POST("/webhooks/github", handler)
+	return router
+}
+
+// csrfCookie returns the CSRF cookie set by a response
+func csrfCookie(w *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == CSRFCookie {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestCSRF_IssuesCookie(t *testing.T) {
+	router := newCSRFRouter(DefaultCSRFConfig())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/form", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %v, want %v", w.Code, http.StatusOK)
+	}
+	cookie := csrfCookie(w)
+	if cookie == nil {
+		t.Fatal("CSRF cookie should be set")
+	}
+	if cookie.HttpOnly {
+		t.Error("CSRF cookie must be readable by scripts")
+	}
+	if !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("CSRF cookie Secure = %v, SameSite = %v", cookie.Secure, cookie.SameSite)
+	}
+	if w.Body.String() != cookie.Value {
+		t.Error("GetCSRFToken() should return the cookie's token")
+	}
+
+	// A request already holding the cookie keeps it
+	req := httptest.NewRequest(http.MethodGet, "/form", nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if csrfCookie(w) != nil || w.Body.String() != cookie.Value {
+		t.Error("An existing CSRF cookie should be reused")
+	}
+}
+
+func TestCSRF_UnsafeMethods(t *testing.T) {
+	router := newCSRFRouter(DefaultCSRFConfig())
+	cookie := &http.Cookie{Name: CSRFCookie, Value: "token-value"}
+
+	tests := []struct {
+		name       string
+		cookie     *http.Cookie
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"matching header", cookie, map[string]string{CSRFHeader: "token-value"}, http.StatusOK},
+		{"mismatched header", cookie, map[string]string{CSRFHeader: "other"}, http.StatusForbidden},
+		{"missing header", cookie, nil, http.StatusForbidden},
+		{"missing cookie", nil, map[string]string{CSRFHeader: "token-value"}, http.StatusForbidden},
+		{"bearer token", nil, map[string]string{"Authorization": "Bearer abc"}, http.StatusOK},
+		{"api key", nil, map[string]string{APIKeyHeader: "key"}, http.StatusOK},
+		{"basic auth is checked", cookie, map[string]string{"Authorization": "Basic abc"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusForbidden && !strings.Contains(w.Body.String(), `"success":false`) {
+				t.Errorf("Body = %s, want the error envelope", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCSRF_Exemptions(t *testing.T) {
+	config := DefaultCSRFConfig()
+	config.ExemptPaths = []string{"/webhooks/*"}
+	config.ExemptMethods = append(config.ExemptMethods, http.MethodPost)
+	router := newCSRFRouter(config)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/submit", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Exempt method status = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	config.ExemptMethods = DefaultCSRFConfig().ExemptMethods
+	router = newCSRFRouter(config)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhooks/github", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Exempt path status = %v, want %v", w.Code, http.StatusOK)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/submit", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Other path status = %v, want %v", w.Code, http.StatusForbidden)
+	}
+}
+
+// Content Negotiation Tests
+func newNegotiationRouter(negotiate bool) *gin.Engine {
+	router := newTestRouter()
+	if negotiate {
+		router.Use(ContentNegotiation())
+	}
+	router.GET("/test", func(c *gin.Context) {
+		Respond(c, http.StatusCreated, response.NewSuccess(map[string]any{"name": "job", "count": 3}, "created"))
+	})
+	return router
+}
+
+func TestRespond_ContentTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		wantType string
+	}{
+		{"no accept", "", "application/json"},
+		{"json", "application/json", "application/json"},
+		{"any", "*/*", "application/json"},
+		{"msgpack", "application/msgpack", MIMEMsgPack},
+		{"x-msgpack", "application/x-msgpack", MIMEMsgPack},
+		{"msgpack preferred", "application/msgpack, application/json", MIMEMsgPack},
+		{"json preferred", "application/json, application/msgpack", "application/json"},
+		{"unsupported", "text/html", "application/json"},
+	}
+
+	router := newNegotiationRouter(true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusCreated {
+				t.Errorf("Status = %v, want %v", w.Code, http.StatusCreated)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.wantType) {
+				t.Errorf("Content-Type = %v, want %v", got, tt.wantType)
+			}
+			if !strings.Contains(w.Header().Get("Vary"), "Accept") {
+				t.Error("Negotiated responses should vary on Accept")
+			}
+		})
+	}
+}
+
+func TestRespond_MsgPackBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept", MIMEMsgPack)
+	w := httptest.NewRecorder()
+	newNegotiationRouter(true).ServeHTTP(w, req)
+
+	var body map[string]any
+	handle := &codec.MsgpackHandle{}
+	handle.RawToString = true
+	if err := codec.NewDecoderBytes(w.Body.Bytes(), handle).Decode(&body); err != nil {
+		t.Fatalf("Body is not MessagePack: %v", err)
+	}
+	if body["success"] != true || body["message"] != "created" {
+		t.Errorf("Body = %v, want the response envelope", body)
+	}
+	if _, ok := body["timestamp"].(time.Time); !ok {
+		t.Errorf("timestamp = %T, want a MessagePack timestamp", body["timestamp"])
+	}
+	data, _ := body["data"].(map[any]any)
+	if data["name"] != "job" {
+		t.Errorf("data = %v, want the payload", body["data"])
+	}
+}
+
+func TestRespond_JSONWithoutNegotiation(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept", MIMEMsgPack)
+	w := httptest.NewRecorder()
+	newNegotiationRouter(false).ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+		t.Errorf("Content-Type = %v, want JSON without ContentNegotiation", got)
+	}
+	if !strings.Contains(w.Body.String(), `"name":"job"`) {
+		t.Errorf("Body = %s, want JSON", w.Body.String())
+	}
+}
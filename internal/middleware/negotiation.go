@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// MIMEMsgPack is the media type of MessagePack responses
+	MIMEMsgPack = binding.MIMEMSGPACK2
+	// ResponseFormatsKey is the context key for the formats Respond may use
+	ResponseFormatsKey = "response_formats"
+)
+
+// negotiableFormats are the formats offered under ContentNegotiation, JSON
+// first so it wins for */* and missing Accept headers
+var negotiableFormats = []string{binding.MIMEJSON, MIMEMsgPack, binding.MIMEMSGPACK}
+
+// msgPackHandle encodes with the current MessagePack spec: strings as str,
+// time.Time as the timestamp extension, and struct fields named by their
+// json tags. gin's own MsgPack render uses the legacy raw format.
+var msgPackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.RawToString = true
+	return h
+}()
+
+// ContentNegotiation lets Respond answer in MessagePack when the Accept
+// header asks for application/msgpack (or application/x-msgpack) before
+// JSON, saving bandwidth for high-volume clients such as dashboards.
+func ContentNegotiation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ResponseFormatsKey, negotiableFormats)
+		// The response depends on the Accept header
+		c.Writer.Header().Add("Vary", "Accept")
+		c.Next()
+	}
+}
+
+// Respond writes payload with status, as MessagePack when ContentNegotiation
+// is in use and the request prefers it, otherwise as JSON
+func Respond(c *gin.Context, status int, payload any) {
+	formats, _ := c.Value(ResponseFormatsKey).([]string)
+	if len(formats) == 0 {
+		c.JSON(status, payload)
+		return
+	}
+
+	switch c.NegotiateFormat(formats...) {
+	case MIMEMsgPack, binding.MIMEMSGPACK:
+		c.Render(status, msgPackRender{data: payload})
+	default:
+		c.JSON(status, payload)
+	}
+}
+
+// msgPackRender renders MessagePack with msgPackHandle
+type msgPackRender struct {
+	data any
+}
+
+func (r msgPackRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return codec.NewEncoder(w, msgPackHandle).Encode(r.data)
+}
+
+func (r msgPackRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", MIMEMsgPack)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+)
+
+const (
+	// Rate limit response headers
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimit rejects requests denied by the limiter for the request's key with
+// 429 Too Many Requests and a Retry-After header. Allowed requests get
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (seconds until
+// the bucket is full) headers. Preflight OPTIONS requests are never limited.
+//
+// Use a resilience.KeyedRateLimiter with keyFunc to give each client its own
+// bucket, or resilience.SharedLimiter to apply one limit to all requests.
+func RateLimit(limiter resilience.KeyedLimiter, keyFunc func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		bucket := limiter.ForKey(keyFunc(c))
+		if !bucket.Allow() {
+			status := bucket.Status()
+			c.Header("Retry-After", formatSeconds(status.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, response.NewError[any]("rate limit exceeded"))
+			c.Abort()
+			return
+		}
+
+		status := bucket.Status()
+		c.Header(RateLimitLimitHeader, strconv.Itoa(status.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(status.Remaining))
+		c.Header(RateLimitResetHeader, formatSeconds(status.Reset))
+
+		c.Next()
+	}
+}
+
+// RateLimitByIP keys rate limits by client IP
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser keys rate limits by the authenticated user, falling back to
+// the client IP for anonymous requests. It must run after authentication.
+func RateLimitByUser(c *gin.Context) string {
+	if claims, ok := c.Get(security.ContextKeyClaims); ok {
+		if userClaims, ok := claims.(*security.UserClaims); ok {
+			return "user:" + strconv.FormatUint(uint64(userClaims.UserID), 10)
+		}
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByRoute keys rate limits by the matched route pattern
+func RateLimitByRoute(c *gin.Context) string {
+	return "route:" + c.Request.Method + " " + c.FullPath()
+}
+
+// formatSeconds formats a duration as whole seconds, rounding up
+func formatSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+)
+
+// Timeout bounds how long a request may take. The request context carries a
+// deadline of d from the start of the request, so services and DAOs using it
+// stop work once it passes. If the handler has not started its response by
+// then, the client gets 503 Service Unavailable with an error envelope and
+// anything the handler writes afterwards is discarded.
+//
+// Applying Timeout again on a route group overrides the outer timeout for that
+// group, and d <= 0 disables it, e.g. for streaming endpoints.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Nested in an outer Timeout: replace its deadline
+		if tw, ok := c.Writer.(*timeoutWriter); ok {
+			tw.reset(c, d)
+			c.Next()
+			return
+		}
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		tw := newTimeoutWriter(c)
+		tw.reset(c, d)
+		defer func() {
+			tw.finish()
+			c.Writer = tw.ResponseWriter
+		}()
+
+		c.Writer = tw
+		c.Next()
+	}
+}
+
+// timeoutWriter guards the response writer so that either the handler or the
+// timeout writes the response, never both
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	mu        sync.Mutex
+	header    http.Header // handler's headers, copied to the response on commit
+	parent    context.Context
+	start     time.Time
+	deadline  time.Time // zero if the timeout is disabled
+	cancel    context.CancelFunc
+	timer     *time.Timer
+	committed bool // the handler has started its response
+	timedOut  bool
+	done      bool // the middleware returned
+}
+
+func newTimeoutWriter(c *gin.Context) *timeoutWriter {
+	return &timeoutWriter{
+		ResponseWriter: c.Writer,
+		header:         c.Writer.Header().Clone(),
+		parent:         c.Request.Context(),
+		start:          time.Now(),
+	}
+}
+
+// reset sets the request deadline to d from the start of the request,
+// replacing any previous deadline. Must be called from the handler goroutine.
+func (w *timeoutWriter) reset(c *gin.Context, d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+
+	ctx, cancel := w.parent, context.CancelFunc(func() {})
+	w.deadline = time.Time{}
+	if d > 0 {
+		w.deadline = w.start.Add(d)
+		ctx, cancel = context.WithDeadline(w.parent, w.deadline)
+		w.timer = time.AfterFunc(time.Until(w.deadline), w.timeout)
+	}
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.cancel = cancel
+	c.Request = c.Request.WithContext(ctx)
+}
+
+// timeout writes the timeout response unless the handler already responded
+func (w *timeoutWriter) timeout() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.committed && !w.done {
+		w.writeTimeout()
+	}
+}
+
+// writeTimeout writes the timeout response (must be called with mutex held)
+func (w *timeoutWriter) writeTimeout() {
+	w.timedOut = true
+
+	body, _ := json.Marshal(response.NewError[any]("request timed out"))
+	w.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.ResponseWriter.Write(body)
+	w.ResponseWriter.Flush()
+}
+
+// finish stops the timer and cancels the request context once the handler returns
+func (w *timeoutWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.done = true
+	// Carry over headers set by a handler that returned without writing a body
+	w.commit()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.cancel()
+}
+
+// commit marks the response as started by the handler (must be called with mutex held).
+// Returns false if the request timed out. A handler woken by the cancelled
+// context can get here before the timer fires, so the deadline is checked too.
+func (w *timeoutWriter) commit() bool {
+	if w.timedOut {
+		return false
+	}
+	if !w.committed && !w.deadline.IsZero() && !time.Now().Before(w.deadline) {
+		w.writeTimeout()
+		return false
+	}
+	if !w.committed {
+		w.committed = true
+		dst := w.ResponseWriter.Header()
+		for k, v := range w.header {
+			dst[k] = v
+		}
+	}
+	return true
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// gin only records the status here; the response starts on the first write
+	if !w.timedOut {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.commit() {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.commit() {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.commit() {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.commit() {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.commit() {
+		return nil, nil, http.ErrHandlerTimeout
+	}
+	return w.ResponseWriter.Hijack()
+}
@@ -0,0 +1,48 @@
+package observability
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/cache"
+)
+
+// DAOCacheCollector exports the hit, miss and error counts of the DAO
+// cache, labeled by entity. The hit rate is
+// rate(hits) / (rate(hits) + rate(misses)).
+type DAOCacheCollector struct {
+	metrics *cache.Metrics
+
+	hits   *prometheus.Desc
+	misses *prometheus.Desc
+	errors *prometheus.Desc
+}
+
+// NewDAOCacheCollector creates a collector reading metrics on every scrape
+func NewDAOCacheCollector(metrics *cache.Metrics) *DAOCacheCollector {
+	labels := []string{"entity"}
+	return &DAOCacheCollector{
+		metrics: metrics,
+		hits: prometheus.NewDesc("arcana_dao_cache_hits_total",
+			"DAO lookups served from the cache", labels, nil),
+		misses: prometheus.NewDesc("arcana_dao_cache_misses_total",
+			"DAO lookups that went to the database", labels, nil),
+		errors: prometheus.NewDesc("arcana_dao_cache_errors_total",
+			"DAO cache store operations that failed", labels, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *DAOCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.errors
+}
+
+// Collect implements prometheus.Collector
+func (c *DAOCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for entity, stats := range c.metrics.Snapshot() {
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits), entity)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses), entity)
+		ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, float64(stats.Errors), entity)
+	}
+}
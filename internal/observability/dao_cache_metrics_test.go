@@ -0,0 +1,54 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao/cache"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// stubUserDAO finds every user by ID
+type stubUserDAO struct {
+	dao.UserDAO
+}
+
+func (stubUserDAO) FindByID(_ context.Context, id uint) (*entity.User, error) {
+	return &entity.User{ID: id, Username: "alice"}, nil
+}
+
+// TestDAOCacheCollector verifies hits and misses are exported per cached entity
+func TestDAOCacheCollector(t *testing.T) {
+	caches, err := cache.New(cache.NewMemoryStore(0), cache.Options{
+		Entities: []string{cache.EntityUsers, cache.EntityPlugins},
+	})
+	require.NoError(t, err)
+
+	users := caches.Users(stubUserDAO{})
+	for range 3 {
+		_, err := users.FindByID(context.Background(), 1)
+		require.NoError(t, err)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewDAOCacheCollector(caches.Metrics()))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()+"/"+metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, 2.0, values["arcana_dao_cache_hits_total/users"])
+	assert.Equal(t, 1.0, values["arcana_dao_cache_misses_total/users"])
+	assert.Equal(t, 0.0, values["arcana_dao_cache_errors_total/users"])
+	// Entities without lookups yet are exported too
+	assert.Contains(t, values, "arcana_dao_cache_hits_total/plugins")
+}
@@ -0,0 +1,233 @@
+package observability
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.uber.org/zap"
+)
+
+// DefaultPoolMetricsInterval is how often pool stats are sampled when no
+// interval is configured
+const DefaultPoolMetricsInterval = 15 * time.Second
+
+// PoolStats is a point-in-time snapshot of a connection pool
+type PoolStats struct {
+	Open         int
+	InUse        int
+	Idle         int
+	WaitCount    int64
+	WaitDuration time.Duration
+}
+
+// PoolStatsFunc returns the current stats of a connection pool
+type PoolStatsFunc func() PoolStats
+
+// SQLPoolStats reads pool stats from a database/sql pool, such as the one
+// returned by gorm.DB.DB()
+func SQLPoolStats(db *sql.DB) PoolStatsFunc {
+	return func() PoolStats {
+		s := db.Stats()
+		return PoolStats{
+			Open:         s.OpenConnections,
+			InUse:        s.InUse,
+			Idle:         s.Idle,
+			WaitCount:    s.WaitCount,
+			WaitDuration: s.WaitDuration,
+		}
+	}
+}
+
+// MongoPoolMonitor tracks MongoDB connection pool stats from driver pool
+// events, since the driver exposes no stats snapshot. Every checkout goes
+// through the pool's wait queue, so WaitCount counts checkouts and
+// WaitDuration is the total time spent checking out connections.
+type MongoPoolMonitor struct {
+	mu           sync.Mutex
+	open         int
+	inUse        int
+	waitCount    int64
+	waitDuration time.Duration
+}
+
+// NewMongoPoolMonitor creates a MongoDB pool monitor
+func NewMongoPoolMonitor() *MongoPoolMonitor {
+	return &MongoPoolMonitor{}
+}
+
+// PoolMonitor returns the driver hook to pass to
+// options.ClientOptions.SetPoolMonitor
+func (m *MongoPoolMonitor) PoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: m.handle}
+}
+
+func (m *MongoPoolMonitor) handle(e *event.PoolEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch e.Type {
+	case event.ConnectionCreated:
+		m.open++
+	case event.ConnectionClosed:
+		m.open--
+	case event.ConnectionCheckedOut:
+		m.inUse++
+		m.waitCount++
+		m.waitDuration += e.Duration
+	case event.ConnectionCheckOutFailed:
+		m.waitCount++
+		m.waitDuration += e.Duration
+	case event.ConnectionCheckedIn:
+		m.inUse--
+	}
+}
+
+// Stats returns the current pool stats
+func (m *MongoPoolMonitor) Stats() PoolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return PoolStats{
+		Open:         m.open,
+		InUse:        m.inUse,
+		Idle:         max(m.open-m.inUse, 0),
+		WaitCount:    m.waitCount,
+		WaitDuration: m.waitDuration,
+	}
+}
+
+// DBPoolMetrics periodically samples database connection pools and exposes
+// their stats as Prometheus gauges labeled by database name
+type DBPoolMetrics struct {
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	sources map[string]PoolStatsFunc
+	stop    chan struct{}
+	done    chan struct{}
+
+	open         *prometheus.GaugeVec
+	inUse        *prometheus.GaugeVec
+	idle         *prometheus.GaugeVec
+	waitCount    *prometheus.GaugeVec
+	waitDuration *prometheus.GaugeVec
+}
+
+// NewDBPoolMetrics creates pool metrics sampled every interval. A
+// non-positive interval uses DefaultPoolMetricsInterval.
+func NewDBPoolMetrics(interval time.Duration, logger *zap.Logger) *DBPoolMetrics {
+	if interval <= 0 {
+		interval = DefaultPoolMetricsInterval
+	}
+
+	labels := []string{"db"}
+	return &DBPoolMetrics{
+		interval: interval,
+		logger:   logger,
+		sources:  make(map[string]PoolStatsFunc),
+		open: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "arcana_db_pool_open_connections",
+			Help: "Established connections, both in use and idle",
+		}, labels),
+		inUse: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "arcana_db_pool_in_use_connections",
+			Help: "Connections currently in use",
+		}, labels),
+		idle: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "arcana_db_pool_idle_connections",
+			Help: "Idle connections",
+		}, labels),
+		waitCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "arcana_db_pool_wait_count",
+			Help: "Total number of times a connection was waited for",
+		}, labels),
+		waitDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "arcana_db_pool_wait_duration_seconds",
+			Help: "Total time spent waiting for a connection in seconds",
+		}, labels),
+	}
+}
+
+// Collectors returns the gauges to register on a Prometheus registry
+func (m *DBPoolMetrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.open, m.inUse, m.idle, m.waitCount, m.waitDuration}
+}
+
+// Add registers a pool under the given database name and samples it
+// immediately
+func (m *DBPoolMetrics) Add(name string, stats PoolStatsFunc) {
+	m.mu.Lock()
+	m.sources[name] = stats
+	m.mu.Unlock()
+
+	m.record(name, stats())
+}
+
+// Refresh samples every registered pool
+func (m *DBPoolMetrics) Refresh() {
+	m.mu.Lock()
+	sources := make(map[string]PoolStatsFunc, len(m.sources))
+	for name, stats := range m.sources {
+		sources[name] = stats
+	}
+	m.mu.Unlock()
+
+	for name, stats := range sources {
+		m.record(name, stats())
+	}
+}
+
+func (m *DBPoolMetrics) record(name string, s PoolStats) {
+	m.open.WithLabelValues(name).Set(float64(s.Open))
+	m.inUse.WithLabelValues(name).Set(float64(s.InUse))
+	m.idle.WithLabelValues(name).Set(float64(s.Idle))
+	m.waitCount.WithLabelValues(name).Set(float64(s.WaitCount))
+	m.waitDuration.WithLabelValues(name).Set(s.WaitDuration.Seconds())
+}
+
+// Start samples all pools every interval until Stop is called
+func (m *DBPoolMetrics) Start() {
+	m.mu.Lock()
+	if m.stop != nil {
+		m.mu.Unlock()
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	stop, done := m.stop, m.done
+	m.mu.Unlock()
+
+	m.logger.Info("Starting DB pool metrics", zap.Duration("interval", m.interval))
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.Refresh()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops periodic sampling and waits for the sampler to exit
+func (m *DBPoolMetrics) Stop() {
+	m.mu.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
@@ -0,0 +1,130 @@
+package observability
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// gaugeValues gathers the pool gauges and returns them keyed by metric name
+// for the given db label
+func gaugeValues(t *testing.T, m *DBPoolMetrics, db string) map[string]float64 {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(m.Collectors()...)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "db" && label.GetValue() == db {
+					values[family.GetName()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	return values
+}
+
+// TestNewDBPoolMetrics_DefaultInterval verifies a non-positive interval falls back to the default
+func TestNewDBPoolMetrics_DefaultInterval(t *testing.T) {
+	m := NewDBPoolMetrics(0, testLogger())
+	assert.Equal(t, DefaultPoolMetricsInterval, m.interval)
+	assert.Len(t, m.Collectors(), 5)
+}
+
+// TestDBPoolMetrics_AddAndRefresh verifies gauges follow the registered pool stats
+func TestDBPoolMetrics_AddAndRefresh(t *testing.T) {
+	m := NewDBPoolMetrics(time.Minute, testLogger())
+
+	stats := PoolStats{Open: 3, InUse: 1, Idle: 2, WaitCount: 4, WaitDuration: 1500 * time.Millisecond}
+	m.Add("arcana", func() PoolStats { return stats })
+
+	values := gaugeValues(t, m, "arcana")
+	assert.Equal(t, 3.0, values["arcana_db_pool_open_connections"])
+	assert.Equal(t, 1.0, values["arcana_db_pool_in_use_connections"])
+	assert.Equal(t, 2.0, values["arcana_db_pool_idle_connections"])
+	assert.Equal(t, 4.0, values["arcana_db_pool_wait_count"])
+	assert.Equal(t, 1.5, values["arcana_db_pool_wait_duration_seconds"])
+
+	stats.InUse = 3
+	stats.Idle = 0
+	m.Refresh()
+
+	values = gaugeValues(t, m, "arcana")
+	assert.Equal(t, 3.0, values["arcana_db_pool_in_use_connections"])
+	assert.Equal(t, 0.0, values["arcana_db_pool_idle_connections"])
+}
+
+// TestDBPoolMetrics_StartStop verifies the sampler refreshes on its interval and stops cleanly
+func TestDBPoolMetrics_StartStop(t *testing.T) {
+	m := NewDBPoolMetrics(10*time.Millisecond, testLogger())
+
+	var open int
+	m.Add("arcana", func() PoolStats { return PoolStats{Open: open} })
+	open = 7
+
+	m.Start()
+	m.Start() // second start is a no-op
+	assert.Eventually(t, func() bool {
+		return gaugeValues(t, m, "arcana")["arcana_db_pool_open_connections"] == 7
+	}, time.Second, 10*time.Millisecond)
+
+	m.Stop()
+	m.Stop() // second stop is a no-op
+}
+
+// TestSQLPoolStats verifies stats are read from a database/sql pool
+func TestSQLPoolStats(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	conn, err := db.Conn(t.Context())
+	require.NoError(t, err)
+
+	stats := SQLPoolStats(db)()
+	assert.Equal(t, 1, stats.Open)
+	assert.Equal(t, 1, stats.InUse)
+	assert.Equal(t, 0, stats.Idle)
+
+	require.NoError(t, conn.Close())
+
+	stats = SQLPoolStats(db)()
+	assert.Equal(t, 0, stats.InUse)
+	assert.Equal(t, 1, stats.Idle)
+}
+
+// TestMongoPoolMonitor verifies pool events are folded into stats
+func TestMongoPoolMonitor(t *testing.T) {
+	m := NewMongoPoolMonitor()
+	monitor := m.PoolMonitor()
+
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCreated})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCreated})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCheckedOut, Duration: 20 * time.Millisecond})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCheckedOut, Duration: 30 * time.Millisecond})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCheckedIn})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCheckOutFailed, Duration: 50 * time.Millisecond})
+
+	stats := m.Stats()
+	assert.Equal(t, 2, stats.Open)
+	assert.Equal(t, 1, stats.InUse)
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, int64(3), stats.WaitCount)
+	assert.Equal(t, 100*time.Millisecond, stats.WaitDuration)
+
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionClosed})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionClosed})
+	assert.Equal(t, 0, m.Stats().Idle)
+}
@@ -0,0 +1,66 @@
+package observability
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTPMetrics holds Prometheus metrics for HTTP requests
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+}
+
+// NewHTTPMetrics creates HTTP metrics. Register their Collectors on the
+// MetricsProvider to serve them.
+func NewHTTPMetrics() *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "arcana_http_requests_total",
+			Help: "Total HTTP requests",
+		}, []string{"method", "path", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "arcana_http_request_duration_seconds",
+			Help:    "HTTP request duration in seconds",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "path", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "arcana_http_requests_in_flight",
+			Help: "HTTP requests currently being served",
+		}, []string{"method", "path"}),
+	}
+}
+
+// Collectors returns the collectors to register for scraping
+func (m *HTTPMetrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.requests, m.duration, m.inFlight}
+}
+
+// Middleware records request count, duration and in-flight requests labeled
+// by method, route template (e.g. /api/v1/users/:id) and status code
+func (m *HTTPMetrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		path := c.FullPath()
+		if path == "" {
+			path = unmatchedRoute
+		}
+
+		inFlight := m.inFlight.WithLabelValues(method, path)
+		inFlight.Inc()
+		start := time.Now()
+
+		defer func() {
+			inFlight.Dec()
+			status := strconv.Itoa(c.Writer.Status())
+			m.requests.WithLabelValues(method, path, status).Inc()
+			m.duration.WithLabelValues(method, path, status).Observe(time.Since(start).Seconds())
+		}()
+
+		c.Next()
+	}
+}
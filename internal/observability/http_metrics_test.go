@@ -0,0 +1,95 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	mp, err := NewMetricsProvider(DefaultMetricsConfig(), testLogger())
+	require.NoError(t, err)
+	defer mp.Shutdown(context.Background())
+
+	metrics := NewHTTPMetrics()
+	require.NoError(t, mp.Register(metrics.Collectors()...))
+
+	router := gin.New()
+	router.Use(metrics.Middleware())
+	router.GET("/users/:id", func(c *gin.Context) {
+		if got := promtestutil.ToFloat64(metrics.inFlight.WithLabelValues(http.MethodGet, "/users/:id")); got != 1 {
+			t.Errorf("in-flight during request = %v, want 1", got)
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/metrics", gin.WrapH(mp.Handler()))
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/3", "/no/such/route"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := promtestutil.ToFloat64(metrics.requests.WithLabelValues(http.MethodGet, "/users/:id", "200")); got != 3 {
+		t.Errorf("requests for /users/:id = %v, want 3 collapsed into the route template", got)
+	}
+	if got := promtestutil.ToFloat64(metrics.requests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")); got != 1 {
+		t.Errorf("unmatched requests = %v, want 1", got)
+	}
+	if got := promtestutil.ToFloat64(metrics.inFlight.WithLabelValues(http.MethodGet, "/users/:id")); got != 0 {
+		t.Errorf("in-flight after requests = %v, want 0", got)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`arcana_http_requests_total{method="GET",path="/users/:id",status="200"} 3`,
+		`arcana_http_request_duration_seconds_count{method="GET",path="/users/:id",status="200"} 3`,
+		"arcana_http_requests_in_flight",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+	if strings.Contains(body, "/users/1") {
+		t.Error("metrics output should not contain raw request paths")
+	}
+}
+
+func TestMetricsProvider_Register(t *testing.T) {
+	mp, err := NewMetricsProvider(DefaultMetricsConfig(), testLogger())
+	require.NoError(t, err)
+	defer mp.Shutdown(context.Background())
+
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "arcana_test_gauge", Help: "test"})
+	gauge.Set(42)
+
+	if err := mp.Register(gauge); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := mp.Register(gauge); err == nil {
+		t.Error("Register() of a duplicate collector should fail")
+	}
+
+	w := httptest.NewRecorder()
+	mp.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(w.Body.String(), "arcana_test_gauge 42") {
+		t.Error("metrics output missing registered collector")
+	}
+}
+
+func TestMetricsProvider_Register_Disabled(t *testing.T) {
+	mp, err := NewMetricsProvider(&MetricsConfig{ServiceName: "test-disabled"}, testLogger())
+	require.NoError(t, err)
+
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "arcana_test_gauge", Help: "test"})
+	if err := mp.Register(gauge); err != nil {
+		t.Errorf("Register() error = %v, want nil when disabled", err)
+	}
+}
@@ -294,6 +294,21 @@ func (mp *MetricsProvider) Handler() http.Handler {
 	return http.NotFoundHandler()
 }
 
+// Register adds Prometheus collectors to the registry served by Handler, so
+// that HTTP, DB pool and other metrics appear on one endpoint. It does
+// nothing when metrics are disabled.
+func (mp *MetricsProvider) Register(collectors ...prometheus.Collector) error {
+	if mp.registry == nil {
+		return nil
+	}
+	for _, c := range collectors {
+		if err := mp.registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Meter returns the meter for creating custom metrics
 func (mp *MetricsProvider) Meter() metric.Meter {
 	return mp.meter
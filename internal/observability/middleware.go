@@ -2,6 +2,7 @@ package observability
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,9 +18,23 @@ import (
 
 const attrGRPCStatusCode = "rpc.grpc.status_code"
 
-// TracingMiddleware returns a Gin middleware for HTTP tracing
+// unmatchedRoute stands in for the route of requests that matched no route,
+// so that arbitrary paths such as scanner probes do not each create a new
+// span name or metric series
+const unmatchedRoute = "unmatched"
+
+// TracingMiddleware returns a Gin middleware for HTTP tracing with the global
+// tracer provider
 func TracingMiddleware(serviceName string) gin.HandlerFunc {
-	tracer := otel.Tracer(serviceName)
+	return TracingMiddlewareWithTracer(otel.Tracer(serviceName))
+}
+
+// TracingMiddlewareWithTracer returns a Gin middleware starting a server span
+// with tracer for each request. It continues the caller's trace read by the
+// global propagator, names spans after the route template (e.g. "POST
+// /api/v1/jobs") and puts the span context on the request context, so jobs
+// enqueued by the handler continue the trace.
+func TracingMiddlewareWithTracer(tracer trace.Tracer) gin.HandlerFunc {
 	propagator := otel.GetTextMapPropagator()
 
 	return func(c *gin.Context) {
@@ -27,17 +42,17 @@ func TracingMiddleware(serviceName string) gin.HandlerFunc {
 		ctx := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
 
 		// Start span
-		spanName := c.FullPath()
-		if spanName == "" {
-			spanName = c.Request.URL.Path
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
 		}
 
-		ctx, span := tracer.Start(ctx, spanName,
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
 			trace.WithSpanKind(trace.SpanKindServer),
 			trace.WithAttributes(
 				AttrHTTPMethod.String(c.Request.Method),
 				AttrHTTPURL.String(c.Request.URL.String()),
-				AttrHTTPRoute.String(spanName),
+				AttrHTTPRoute.String(route),
 			),
 		)
 		defer span.End()
@@ -57,18 +72,16 @@ func TracingMiddleware(serviceName string) gin.HandlerFunc {
 			attribute.Int64("http.response_time_ms", duration.Milliseconds()),
 		)
 
-		// Set span status based on HTTP status code
-		if statusCode >= 400 {
-			span.SetStatus(codes.Error, "HTTP error")
+		// Client errors are not server span errors
+		if statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(statusCode))
 		} else {
 			span.SetStatus(codes.Ok, "")
 		}
 
 		// Record errors
-		if len(c.Errors) > 0 {
-			for _, err := range c.Errors {
-				span.RecordError(err.Err)
-			}
+		for _, err := range c.Errors {
+			span.RecordError(err.Err)
 		}
 	}
 }
@@ -0,0 +1,90 @@
+package observability
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingMiddlewareWithTracer(t *testing.T) {
+	otel.SetTextMapPropagator(createPropagator("tracecontext"))
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	router := gin.New()
+	router.Use(TracingMiddlewareWithTracer(tp.Tracer("test")))
+	var handlerSpan trace.SpanContext
+	router.GET("/users/:id", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /users/:id" {
+		t.Errorf("span name = %q, want route template", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("SpanKind = %v, want server", span.SpanKind())
+	}
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("trace ID = %s, want the caller's %s", got, traceID)
+	}
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %s, want the caller's span", got)
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("request context should carry the server span")
+	}
+	if span.Status().Code == codes.Error {
+		t.Error("successful request should not have error status")
+	}
+}
+
+func TestTracingMiddlewareWithTracer_ServerError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	router := gin.New()
+	router.Use(TracingMiddlewareWithTracer(tp.Tracer("test")))
+	router.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(errors.New("database unavailable"))
+		c.Status(http.StatusInternalServerError)
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("5xx status = %v, want error", spans[0].Status().Code)
+	}
+	if len(spans[0].Events()) == 0 {
+		t.Error("handler errors should be recorded on the span")
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Error("4xx responses should not mark the server span as failed")
+	}
+}
@@ -0,0 +1,182 @@
+package observability
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// RateLimiterStatsFunc returns the current metrics of a rate limiter
+type RateLimiterStatsFunc func() resilience.RateLimiterMetricsSnapshot
+
+// KeyedRateLimiterStats reads the metrics of a keyed rate limiter, summed
+// over all its keys
+func KeyedRateLimiterStats(limiter *resilience.KeyedRateLimiter) RateLimiterStatsFunc {
+	return func() resilience.RateLimiterMetricsSnapshot {
+		return limiter.Metrics().RateLimiterMetricsSnapshot
+	}
+}
+
+// ResilienceCollector exports circuit breaker, rate limiter, retry budget and
+// hedged request metrics, labeled by name. Metrics are read from the primitives' snapshots on
+// every scrape, so a scrape never races the calls it reports on.
+type ResilienceCollector struct {
+	mu         sync.RWMutex
+	registries []*resilience.CircuitBreakerRegistry
+	limiters   map[string]RateLimiterStatsFunc
+	budgets    map[string]*resilience.RetryBudget
+	hedges     map[string]*resilience.HedgeMetrics
+
+	breakerState       *prometheus.Desc
+	breakerCalls       *prometheus.Desc
+	breakerFailed      *prometheus.Desc
+	breakerSlow        *prometheus.Desc
+	breakerRejected    *prometheus.Desc
+	breakerTransitions *prometheus.Desc
+	limiterRequests    *prometheus.Desc
+	limiterAllowed     *prometheus.Desc
+	limiterRejected    *prometheus.Desc
+	retriesAllowed     *prometheus.Desc
+	retriesDenied      *prometheus.Desc
+	hedgeCalls         *prometheus.Desc
+	hedgedCalls        *prometheus.Desc
+	hedgesStarted      *prometheus.Desc
+	hedgeWins          *prometheus.Desc
+}
+
+// NewResilienceCollector creates a collector with nothing to export yet
+func NewResilienceCollector() *ResilienceCollector {
+	labels := []string{"name"}
+	return &ResilienceCollector{
+		limiters: make(map[string]RateLimiterStatsFunc),
+		budgets:  make(map[string]*resilience.RetryBudget),
+		hedges:   make(map[string]*resilience.HedgeMetrics),
+		breakerState: prometheus.NewDesc("arcana_circuit_breaker_state",
+			"Circuit breaker state: 0 closed, 1 open, 2 half-open", labels, nil),
+		breakerCalls: prometheus.NewDesc("arcana_circuit_breaker_calls_total",
+			"Calls executed through the circuit breaker", labels, nil),
+		breakerFailed: prometheus.NewDesc("arcana_circuit_breaker_failed_calls_total",
+			"Calls that failed", labels, nil),
+		breakerSlow: prometheus.NewDesc("arcana_circuit_breaker_slow_calls_total",
+			"Calls slower than the slow call threshold", labels, nil),
+		breakerRejected: prometheus.NewDesc("arcana_circuit_breaker_rejected_calls_total",
+			"Calls rejected without running because the breaker was open", labels, nil),
+		breakerTransitions: prometheus.NewDesc("arcana_circuit_breaker_state_transitions_total",
+			"Circuit breaker state changes", labels, nil),
+		limiterRequests: prometheus.NewDesc("arcana_rate_limiter_requests_total",
+			"Requests checked against the rate limiter", labels, nil),
+		limiterAllowed: prometheus.NewDesc("arcana_rate_limiter_allowed_requests_total",
+			"Requests the rate limiter allowed", labels, nil),
+		limiterRejected: prometheus.NewDesc("arcana_rate_limiter_rejected_requests_total",
+			"Requests the rate limiter rejected", labels, nil),
+		retriesAllowed: prometheus.NewDesc("arcana_retry_budget_allowed_retries_total",
+			"Retries the retry budget allowed", labels, nil),
+		retriesDenied: prometheus.NewDesc("arcana_retry_budget_denied_retries_total",
+			"Retries skipped because the retry budget was exhausted", labels, nil),
+		hedgeCalls: prometheus.NewDesc("arcana_hedge_calls_total",
+			"Calls made through hedging", labels, nil),
+		hedgedCalls: prometheus.NewDesc("arcana_hedge_hedged_calls_total",
+			"Calls that started at least one backup attempt", labels, nil),
+		hedgesStarted: prometheus.NewDesc("arcana_hedge_backup_attempts_total",
+			"Backup attempts started", labels, nil),
+		hedgeWins: prometheus.NewDesc("arcana_hedge_wins_total",
+			"Successful calls by winning attempt, 0 being the first", []string{"name", "attempt"}, nil),
+	}
+}
+
+// AddCircuitBreakers exports every breaker of registry, including those it
+// creates later
+func (c *ResilienceCollector) AddCircuitBreakers(registry *resilience.CircuitBreakerRegistry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registries = append(c.registries, registry)
+}
+
+// AddRateLimiter exports a rate limiter under the given name
+func (c *ResilienceCollector) AddRateLimiter(name string, stats RateLimiterStatsFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limiters[name] = stats
+}
+
+// AddRetryBudget exports a retry budget under the given name
+func (c *ResilienceCollector) AddRetryBudget(name string, budget *resilience.RetryBudget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.budgets[name] = budget
+}
+
+// AddHedge exports the metrics of hedged calls under the given name
+func (c *ResilienceCollector) AddHedge(name string, metrics *resilience.HedgeMetrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hedges[name] = metrics
+}
+
+// Describe implements prometheus.Collector
+func (c *ResilienceCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		c.breakerState, c.breakerCalls, c.breakerFailed, c.breakerSlow, c.breakerRejected,
+		c.breakerTransitions, c.limiterRequests, c.limiterAllowed, c.limiterRejected,
+		c.retriesAllowed, c.retriesDenied, c.hedgeCalls, c.hedgedCalls, c.hedgesStarted, c.hedgeWins,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *ResilienceCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	registries := append([]*resilience.CircuitBreakerRegistry(nil), c.registries...)
+	limiters := make(map[string]RateLimiterStatsFunc, len(c.limiters))
+	for name, stats := range c.limiters {
+		limiters[name] = stats
+	}
+	budgets := make(map[string]*resilience.RetryBudget, len(c.budgets))
+	for name, budget := range c.budgets {
+		budgets[name] = budget
+	}
+	hedges := make(map[string]*resilience.HedgeMetrics, len(c.hedges))
+	for name, metrics := range c.hedges {
+		hedges[name] = metrics
+	}
+	c.mu.RUnlock()
+
+	for _, registry := range registries {
+		for name, breaker := range registry.GetAll() {
+			m := breaker.Metrics()
+			ch <- prometheus.MustNewConstMetric(c.breakerState, prometheus.GaugeValue, float64(breaker.State()), name)
+			ch <- prometheus.MustNewConstMetric(c.breakerCalls, prometheus.CounterValue, float64(m.TotalCalls), name)
+			ch <- prometheus.MustNewConstMetric(c.breakerFailed, prometheus.CounterValue, float64(m.FailedCalls), name)
+			ch <- prometheus.MustNewConstMetric(c.breakerSlow, prometheus.CounterValue, float64(m.SlowCalls), name)
+			ch <- prometheus.MustNewConstMetric(c.breakerRejected, prometheus.CounterValue, float64(m.RejectedCalls), name)
+			ch <- prometheus.MustNewConstMetric(c.breakerTransitions, prometheus.CounterValue, float64(m.StateTransitions), name)
+		}
+	}
+
+	for name, stats := range limiters {
+		m := stats()
+		ch <- prometheus.MustNewConstMetric(c.limiterRequests, prometheus.CounterValue, float64(m.TotalRequests), name)
+		ch <- prometheus.MustNewConstMetric(c.limiterAllowed, prometheus.CounterValue, float64(m.AllowedRequests), name)
+		ch <- prometheus.MustNewConstMetric(c.limiterRejected, prometheus.CounterValue, float64(m.RejectedRequests), name)
+	}
+
+	for name, budget := range budgets {
+		m := budget.Metrics()
+		ch <- prometheus.MustNewConstMetric(c.retriesAllowed, prometheus.CounterValue, float64(m.AllowedRetries), name)
+		ch <- prometheus.MustNewConstMetric(c.retriesDenied, prometheus.CounterValue, float64(m.DeniedRetries), name)
+	}
+
+	for name, metrics := range hedges {
+		m := metrics.Snapshot()
+		ch <- prometheus.MustNewConstMetric(c.hedgeCalls, prometheus.CounterValue, float64(m.TotalCalls), name)
+		ch <- prometheus.MustNewConstMetric(c.hedgedCalls, prometheus.CounterValue, float64(m.HedgedCalls), name)
+		ch <- prometheus.MustNewConstMetric(c.hedgesStarted, prometheus.CounterValue, float64(m.HedgesStarted), name)
+		for attempt, wins := range m.Wins {
+			ch <- prometheus.MustNewConstMetric(c.hedgeWins, prometheus.CounterValue, float64(wins), name, strconv.Itoa(attempt))
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// resilienceValues gathers the collector and returns the value of each metric
+// keyed by metric name for the given name label
+func resilienceValues(t *testing.T, c *ResilienceCollector, name string) map[string]float64 {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "name" && label.GetValue() == name {
+					if metric.GetCounter() != nil {
+						values[family.GetName()] = metric.GetCounter().GetValue()
+					} else {
+						values[family.GetName()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+	}
+	return values
+}
+
+// TestResilienceCollector_CircuitBreakers verifies breaker state and call counts are exported
+func TestResilienceCollector_CircuitBreakers(t *testing.T) {
+	breakers := resilience.NewCircuitBreakerRegistry(zap.NewNop())
+	cfg := resilience.DefaultCircuitBreakerConfig("email")
+	cfg.FailureThreshold = 2
+	breakers.RegisterConfig(cfg)
+
+	c := NewResilienceCollector()
+	c.AddCircuitBreakers(breakers)
+
+	// Breakers created after registration are exported too
+	cb := breakers.Get("email")
+	ctx := context.Background()
+	failing := func(context.Context) error { return errors.New("smtp down") }
+	_ = cb.Execute(ctx, func(context.Context) error { return nil })
+	_ = cb.Execute(ctx, failing)
+	_ = cb.Execute(ctx, failing)
+	_ = cb.Execute(ctx, failing) // rejected, the breaker is open
+
+	values := resilienceValues(t, c, "email")
+	assert.Equal(t, float64(resilience.StateOpen), values["arcana_circuit_breaker_state"])
+	assert.Equal(t, 3.0, values["arcana_circuit_breaker_calls_total"])
+	assert.Equal(t, 2.0, values["arcana_circuit_breaker_failed_calls_total"])
+	assert.Equal(t, 1.0, values["arcana_circuit_breaker_rejected_calls_total"])
+	assert.Equal(t, 1.0, values["arcana_circuit_breaker_state_transitions_total"])
+	assert.Equal(t, 0.0, values["arcana_circuit_breaker_slow_calls_total"])
+}
+
+// TestResilienceCollector_RateLimiters verifies limiter request counts are exported per name
+func TestResilienceCollector_RateLimiters(t *testing.T) {
+	cfg := resilience.DefaultRateLimiterConfig("api")
+	cfg.BurstSize = 2
+	cfg.Rate = 1
+	cfg.Period = time.Hour
+	limiter := resilience.NewTokenBucketLimiter(cfg)
+
+	keyed := resilience.NewKeyedRateLimiter(resilience.DefaultKeyedRateLimiterConfig("login"))
+
+	c := NewResilienceCollector()
+	c.AddRateLimiter("api", limiter.Metrics)
+	c.AddRateLimiter("login", KeyedRateLimiterStats(keyed))
+
+	for i := 0; i < 3; i++ {
+		limiter.Allow()
+	}
+	keyed.Allow("alice")
+	keyed.Allow("bob")
+
+	values := resilienceValues(t, c, "api")
+	assert.Equal(t, 3.0, values["arcana_rate_limiter_requests_total"])
+	assert.Equal(t, 2.0, values["arcana_rate_limiter_allowed_requests_total"])
+	assert.Equal(t, 1.0, values["arcana_rate_limiter_rejected_requests_total"])
+
+	values = resilienceValues(t, c, "login")
+	assert.Equal(t, 2.0, values["arcana_rate_limiter_requests_total"])
+	assert.Equal(t, 2.0, values["arcana_rate_limiter_allowed_requests_total"])
+}
+
+// TestResilienceCollector_RetryBudgets verifies allowed and denied retries are exported
+func TestResilienceCollector_RetryBudgets(t *testing.T) {
+	budget := resilience.NewRetryBudget(&resilience.RetryBudgetConfig{Name: "downloads", MaxRetries: 1, Window: time.Hour})
+
+	c := NewResilienceCollector()
+	c.AddRetryBudget("downloads", budget)
+
+	budget.AllowRetry()
+	budget.AllowRetry()
+
+	values := resilienceValues(t, c, "downloads")
+	assert.Equal(t, 1.0, values["arcana_retry_budget_allowed_retries_total"])
+	assert.Equal(t, 1.0, values["arcana_retry_budget_denied_retries_total"])
+}
+
+// TestResilienceCollector_Hedges verifies hedging counts and wins by attempt are exported
+func TestResilienceCollector_Hedges(t *testing.T) {
+	metrics := resilience.NewHedgeMetrics()
+	cfg := &resilience.HedgeConfig{Name: "profiles", HedgeDelay: 10 * time.Millisecond, MaxHedges: 1, Metrics: metrics}
+
+	c := NewResilienceCollector()
+	c.AddHedge("profiles", metrics)
+
+	var attempts atomic.Int32
+	_, err := resilience.Hedge(context.Background(), cfg, func(ctx context.Context) (int, error) {
+		if attempts.Add(1) == 1 {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return 1, nil
+	})
+	require.NoError(t, err)
+
+	values := resilienceValues(t, c, "profiles")
+	assert.Equal(t, 1.0, values["arcana_hedge_calls_total"])
+	assert.Equal(t, 1.0, values["arcana_hedge_hedged_calls_total"])
+	assert.Equal(t, 1.0, values["arcana_hedge_backup_attempts_total"])
+	assert.Equal(t, 1.0, values["arcana_hedge_wins_total"])
+}
+
+// TestResilienceCollector_ConcurrentScrapes verifies scraping while calls run
+// is race free (run with -race)
+func TestResilienceCollector_ConcurrentScrapes(t *testing.T) {
+	breakers := resilience.NewCircuitBreakerRegistry(zap.NewNop())
+	c := NewResilienceCollector()
+	c.AddCircuitBreakers(breakers)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cb := breakers.Get("payments")
+			for j := 0; j < 200; j++ {
+				_ = cb.Execute(context.Background(), func(context.Context) error { return nil })
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		_, err := registry.Gather()
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 800.0, resilienceValues(t, c, "payments")["arcana_circuit_breaker_calls_total"])
+}
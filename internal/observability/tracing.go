@@ -55,8 +55,14 @@ type TracingProvider struct {
 	logger         *zap.Logger
 }
 
-// NewTracingProvider creates a new tracing provider
+// NewTracingProvider creates a new tracing provider and sets the global
+// propagator, which the HTTP middleware, gRPC interceptors and job metadata
+// use to carry trace context
 func NewTracingProvider(config *TracingConfig, logger *zap.Logger) (*TracingProvider, error) {
+	// The propagator is set even without tracing so that callers' trace
+	// context still reaches the services and jobs a request leads to
+	otel.SetTextMapPropagator(createPropagator(config.PropagatorType))
+
 	if !config.Enabled {
 		return &TracingProvider{
 			config: config,
@@ -102,9 +108,8 @@ func NewTracingProvider(config *TracingConfig, logger *zap.Logger) (*TracingProv
 		sdktrace.WithSampler(sampler),
 	)
 
-	// Set global tracer provider and propagator
+	// Set global tracer provider
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(createPropagator(config.PropagatorType))
 
 	logger.Info("OpenTelemetry tracing initialized",
 		zap.String("service", config.ServiceName),
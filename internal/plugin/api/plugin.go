@@ -52,6 +52,22 @@ type HealthStatus struct {
 	Details map[string]string `json:"details,omitempty"`
 }
 
+// PluginLifecycle extends Plugin with hooks run as the plugin is enabled,
+// disabled and uninstalled. A hook that fails moves the plugin to the ERROR
+// state.
+type PluginLifecycle interface {
+	Plugin
+
+	// OnEnable runs before the plugin is marked enabled
+	OnEnable(ctx context.Context) error
+
+	// OnDisable runs before the plugin is marked disabled
+	OnDisable(ctx context.Context) error
+
+	// OnUninstall runs before the plugin's files and record are removed
+	OnUninstall(ctx context.Context) error
+}
+
 // RESTEndpointPlugin extends Plugin with REST endpoint capabilities
 type RESTEndpointPlugin interface {
 	Plugin
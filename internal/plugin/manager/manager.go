@@ -3,9 +3,11 @@ package manager
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"plugin"
+	"strings"
 	"sync"
 
 	"go.uber.org/zap"
@@ -197,6 +199,76 @@ func (m *Manager) StopPlugin(ctx context.Context, key string) error {
 	return nil
 }
 
+// ReloadPlugin applies a new config to a plugin. A started plugin is stopped,
+// re-initialized with the config and started again; a stopped one keeps the
+// config for reference. A plugin that is not loaded has nothing to reload.
+func (m *Manager) ReloadPlugin(ctx context.Context, key string, config map[string]any) error {
+	m.mutex.Lock()
+	managed, exists := m.plugins[key]
+	m.mutex.Unlock()
+
+	if !exists {
+		return nil
+	}
+
+	if managed.State != StateStarted {
+		managed.Config = config
+		return nil
+	}
+
+	if err := managed.Plugin.Stop(ctx); err != nil {
+		managed.State = StateError
+		managed.Error = err
+		return fmt.Errorf("failed to stop plugin: %w", err)
+	}
+	managed.State = StateStopped
+
+	m.logger.Info("reloading plugin", zap.String("key", key))
+
+	return m.StartPlugin(ctx, key, config)
+}
+
+// RunEnableHook runs a plugin's OnEnable hook
+func (m *Manager) RunEnableHook(ctx context.Context, key string) error {
+	return m.runHook(ctx, key, "OnEnable", pluginapi.PluginLifecycle.OnEnable)
+}
+
+// RunDisableHook runs a plugin's OnDisable hook
+func (m *Manager) RunDisableHook(ctx context.Context, key string) error {
+	return m.runHook(ctx, key, "OnDisable", pluginapi.PluginLifecycle.OnDisable)
+}
+
+// RunUninstallHook runs a plugin's OnUninstall hook
+func (m *Manager) RunUninstallHook(ctx context.Context, key string) error {
+	return m.runHook(ctx, key, "OnUninstall", pluginapi.PluginLifecycle.OnUninstall)
+}
+
+// runHook runs a lifecycle hook of a loaded plugin. A plugin that is not
+// loaded or does not implement PluginLifecycle has nothing to run.
+func (m *Manager) runHook(ctx context.Context, key, name string, hook func(pluginapi.PluginLifecycle, context.Context) error) error {
+	m.mutex.RLock()
+	managed, exists := m.plugins[key]
+	m.mutex.RUnlock()
+
+	if !exists {
+		return nil
+	}
+	lifecycle, ok := managed.Plugin.(pluginapi.PluginLifecycle)
+	if !ok {
+		return nil
+	}
+
+	if err := hook(lifecycle, ctx); err != nil {
+		managed.State = StateError
+		managed.Error = err
+		return fmt.Errorf("%s hook failed: %w", name, err)
+	}
+
+	m.logger.Info("plugin hook completed", zap.String("key", key), zap.String("hook", name))
+
+	return nil
+}
+
 // UnloadPlugin unloads a plugin
 func (m *Manager) UnloadPlugin(ctx context.Context, key string) error {
 	m.mutex.Lock()
@@ -244,6 +316,27 @@ func (m *Manager) ListPlugins() []*ManagedPlugin {
 	return result
 }
 
+// CheckHealth asks every started plugin for its health, failing when any
+// reports itself unhealthy or cannot answer
+func (m *Manager) CheckHealth(ctx context.Context) error {
+	var unhealthy []string
+	for _, managed := range m.ListPlugins() {
+		if managed.State != StateStarted {
+			continue
+		}
+		status, err := managed.Plugin.Health(ctx)
+		if err != nil {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s: %v", managed.Info.Key, err))
+		} else if status.Status == "unhealthy" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s: %s", managed.Info.Key, status.Message))
+		}
+	}
+	if len(unhealthy) > 0 {
+		return fmt.Errorf("unhealthy plugins: %s", strings.Join(unhealthy, "; "))
+	}
+	return nil
+}
+
 // GetRESTRoutes returns all REST routes from loaded plugins
 func (m *Manager) GetRESTRoutes() []pluginapi.Route {
 	m.mutex.RLock()
@@ -262,6 +355,50 @@ func (m *Manager) GetRESTRoutes() []pluginapi.Route {
 	return routes
 }
 
+// ResolveHandler returns the handler a started REST endpoint plugin serves
+// for the routes whose Path is handler. Routes are matched by method; a
+// route without a method accepts any.
+func (m *Manager) ResolveHandler(pluginKey, handler string) (http.Handler, bool) {
+	m.mutex.RLock()
+	managed, exists := m.plugins[pluginKey]
+	m.mutex.RUnlock()
+
+	if !exists || managed.State != StateStarted {
+		return nil, false
+	}
+	restPlugin, ok := managed.Plugin.(pluginapi.RESTEndpointPlugin)
+	if !ok {
+		return nil, false
+	}
+
+	byMethod := make(map[string]http.Handler)
+	for _, route := range restPlugin.Routes() {
+		if route.Path != handler || route.Handler == nil {
+			continue
+		}
+		var h http.Handler = route.Handler
+		for i := len(route.Middlewares) - 1; i >= 0; i-- {
+			h = route.Middlewares[i](h)
+		}
+		byMethod[strings.ToUpper(route.Method)] = h
+	}
+	if len(byMethod) == 0 {
+		return nil, false
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h, ok := byMethod[r.Method]; ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if h, ok := byMethod[""]; ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}), true
+}
+
 // GetMiddlewares returns all middleware from loaded plugins
 func (m *Manager) GetMiddlewares() []pluginapi.MiddlewarePlugin {
 	m.mutex.RLock()
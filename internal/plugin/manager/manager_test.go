@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+
+	pluginapi "github.com/jrjohn/arcana-cloud-go/internal/plugin/api"
+)
+
+// fakePlugin is a started plugin without lifecycle hooks
+type fakePlugin struct{}
+
+func (p *fakePlugin) Info() pluginapi.PluginInfo                     { return pluginapi.PluginInfo{Key: "fake"} }
+func (p *fakePlugin) Init(_ context.Context, _ map[string]any) error { return nil }
+func (p *fakePlugin) Start(_ context.Context) error                  { return nil }
+func (p *fakePlugin) Stop(_ context.Context) error                   { return nil }
+func (p *fakePlugin) Health(_ context.Context) (pluginapi.HealthStatus, error) {
+	return pluginapi.HealthStatus{Status: "healthy"}, nil
+}
+
+// fakeLifecyclePlugin records the hooks it runs, failing with err if set
+type fakeLifecyclePlugin struct {
+	fakePlugin
+	hooks []string
+	err   error
+}
+
+func (p *fakeLifecyclePlugin) OnEnable(_ context.Context) error {
+	p.hooks = append(p.hooks, "OnEnable")
+	return p.err
+}
+
+func (p *fakeLifecyclePlugin) OnDisable(_ context.Context) error {
+	p.hooks = append(p.hooks, "OnDisable")
+	return p.err
+}
+
+func (p *fakeLifecyclePlugin) OnUninstall(_ context.Context) error {
+	p.hooks = append(p.hooks, "OnUninstall")
+	return p.err
+}
+
+func newTestManager(plugins map[string]pluginapi.Plugin) *Manager {
+	m := NewManager("", zap.NewNop())
+	for key, p := range plugins {
+		m.plugins[key] = &ManagedPlugin{Info: p.Info(), Plugin: p, State: StateStarted}
+	}
+	return m
+}
+
+func TestManager_RunHooks(t *testing.T) {
+	lifecycle := &fakeLifecyclePlugin{}
+	m := newTestManager(map[string]pluginapi.Plugin{"lifecycle": lifecycle, "plain": &fakePlugin{}})
+	ctx := context.Background()
+
+	for _, key := range []string{"lifecycle", "plain", "missing"} {
+		if err := m.RunEnableHook(ctx, key); err != nil {
+			t.Errorf("RunEnableHook(%s) error = %v", key, err)
+		}
+		if err := m.RunDisableHook(ctx, key); err != nil {
+			t.Errorf("RunDisableHook(%s) error = %v", key, err)
+		}
+		if err := m.RunUninstallHook(ctx, key); err != nil {
+			t.Errorf("RunUninstallHook(%s) error = %v", key, err)
+		}
+	}
+
+	want := []string{"OnEnable", "OnDisable", "OnUninstall"}
+	if !slices.Equal(lifecycle.hooks, want) {
+		t.Errorf("hooks = %v, want %v", lifecycle.hooks, want)
+	}
+}
+
+func TestManager_RunHook_Failure(t *testing.T) {
+	hookErr := errors.New("migration failed")
+	m := newTestManager(map[string]pluginapi.Plugin{"lifecycle": &fakeLifecyclePlugin{err: hookErr}})
+
+	err := m.RunEnableHook(context.Background(), "lifecycle")
+	if !errors.Is(err, hookErr) {
+		t.Fatalf("RunEnableHook() error = %v, want %v", err, hookErr)
+	}
+
+	managed, _ := m.GetPlugin("lifecycle")
+	if managed.State != StateError || !errors.Is(managed.Error, hookErr) {
+		t.Errorf("plugin State = %v, Error = %v, want ERROR with the hook error", managed.State, managed.Error)
+	}
+}
+
+// fakeUnhealthyPlugin reports itself unhealthy
+type fakeUnhealthyPlugin struct{ fakePlugin }
+
+func (p *fakeUnhealthyPlugin) Health(_ context.Context) (pluginapi.HealthStatus, error) {
+	return pluginapi.HealthStatus{Status: "unhealthy", Message: "database unreachable"}, nil
+}
+
+func TestManager_CheckHealth(t *testing.T) {
+	m := newTestManager(map[string]pluginapi.Plugin{"plain": &fakePlugin{}})
+	if err := m.CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth() error = %v, want nil", err)
+	}
+
+	m = newTestManager(map[string]pluginapi.Plugin{"plain": &fakePlugin{}, "sick": &fakeUnhealthyPlugin{}})
+	if err := m.CheckHealth(context.Background()); err == nil {
+		t.Error("CheckHealth() should fail with an unhealthy plugin")
+	}
+
+	// Only started plugins are asked
+	managed, _ := m.GetPlugin("sick")
+	managed.State = StateStopped
+	if err := m.CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth() error = %v, want nil with the unhealthy plugin stopped", err)
+	}
+}
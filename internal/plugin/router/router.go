@@ -0,0 +1,152 @@
+// Package router serves the REST extensions of enabled plugins. Routes are
+// added and removed at runtime as plugins are enabled and disabled, behind a
+// single catch-all route mounted on the Gin engine.
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
+)
+
+// ErrRouteConflict is returned when a plugin claims a path another plugin
+// already routes
+var ErrRouteConflict = errors.New("plugin route conflict")
+
+// HandlerResolver looks up the handler a plugin serves under a handler name.
+// It is consulted on every request so a restarted plugin serves its new
+// handler.
+type HandlerResolver interface {
+	ResolveHandler(pluginKey, handler string) (http.Handler, bool)
+}
+
+// route is a registered extension path
+type route struct {
+	pluginKey string
+	handler   string
+}
+
+// ExtensionRouter dispatches requests under its mount point to the plugin
+// extension registered for the path
+type ExtensionRouter struct {
+	resolver HandlerResolver
+	logger   *zap.Logger
+
+	mutex    sync.RWMutex
+	routes   map[string]route
+	byPlugin map[string][]string
+}
+
+// NewExtensionRouter creates an ExtensionRouter
+func NewExtensionRouter(resolver HandlerResolver, logger *zap.Logger) *ExtensionRouter {
+	return &ExtensionRouter{
+		resolver: resolver,
+		logger:   logger,
+		routes:   make(map[string]route),
+		byPlugin: make(map[string][]string),
+	}
+}
+
+// RegisterRoutes mounts the extension routes under /ext. Plugins handle
+// their own authentication.
+func (r *ExtensionRouter) RegisterRoutes(router *gin.RouterGroup) {
+	router.Any("/ext/*path", r.Handle)
+}
+
+// RegisterExtensions routes a plugin's REST_ENDPOINT extensions, replacing
+// any routes it registered before. Extensions of other types or without a
+// path are ignored. Nothing is registered if any path belongs to another
+// plugin.
+func (r *ExtensionRouter) RegisterExtensions(pluginKey string, extensions []*entity.PluginExtension) error {
+	claimed := make(map[string]route)
+	for _, ext := range extensions {
+		if ext.Type != entity.PluginTypeRestEndpoint || ext.Path == "" {
+			continue
+		}
+		p := normalizePath(ext.Path)
+		if existing, ok := claimed[p]; ok && existing.handler != ext.Handler {
+			return fmt.Errorf("%w: %s is declared twice by %s", ErrRouteConflict, p, pluginKey)
+		}
+		claimed[p] = route{pluginKey: pluginKey, handler: ext.Handler}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for p := range claimed {
+		if existing, ok := r.routes[p]; ok && existing.pluginKey != pluginKey {
+			return fmt.Errorf("%w: %s is routed by %s", ErrRouteConflict, p, existing.pluginKey)
+		}
+	}
+
+	r.unregisterLocked(pluginKey)
+	paths := make([]string, 0, len(claimed))
+	for p, rt := range claimed {
+		r.routes[p] = rt
+		paths = append(paths, p)
+	}
+	if len(paths) > 0 {
+		r.byPlugin[pluginKey] = paths
+	}
+
+	r.logger.Info("plugin routes registered",
+		zap.String("plugin", pluginKey),
+		zap.Strings("paths", paths),
+	)
+	return nil
+}
+
+// UnregisterExtensions removes every route of a plugin. It is a no-op for a
+// plugin without routes.
+func (r *ExtensionRouter) UnregisterExtensions(pluginKey string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if paths := r.unregisterLocked(pluginKey); len(paths) > 0 {
+		r.logger.Info("plugin routes unregistered",
+			zap.String("plugin", pluginKey),
+			zap.Strings("paths", paths),
+		)
+	}
+}
+
+func (r *ExtensionRouter) unregisterLocked(pluginKey string) []string {
+	paths := r.byPlugin[pluginKey]
+	for _, p := range paths {
+		delete(r.routes, p)
+	}
+	delete(r.byPlugin, pluginKey)
+	return paths
+}
+
+// Handle dispatches a request to the plugin routing its path
+func (r *ExtensionRouter) Handle(c *gin.Context) {
+	r.mutex.RLock()
+	rt, ok := r.routes[normalizePath(c.Param("path"))]
+	r.mutex.RUnlock()
+
+	if !ok {
+		c.JSON(http.StatusNotFound, response.NewError[any]("route not found"))
+		return
+	}
+
+	handler, ok := r.resolver.ResolveHandler(rt.pluginKey, rt.handler)
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, response.NewError[any]("plugin is not serving this route"))
+		return
+	}
+	handler.ServeHTTP(c.Writer, c.Request)
+}
+
+// normalizePath cleans a path so "/a/b/", "a/b" and "/a//b" route alike
+func normalizePath(p string) string {
+	return path.Clean("/" + p)
+}
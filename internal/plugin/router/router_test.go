@@ -0,0 +1,162 @@
+package router
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// fakeResolver serves a plugin's handlers by name, answering with the
+// plugin key and handler name
+type fakeResolver struct {
+	stopped map[string]bool
+}
+
+func (f *fakeResolver) ResolveHandler(pluginKey, handler string) (http.Handler, bool) {
+	if f.stopped[pluginKey] {
+		return nil, false
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, pluginKey+":"+handler)
+	}), true
+}
+
+func setupRouter(t *testing.T) (*ExtensionRouter, *fakeResolver, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	resolver := &fakeResolver{stopped: map[string]bool{}}
+	extRouter := NewExtensionRouter(resolver, zap.NewNop())
+	engine := gin.New()
+	extRouter.RegisterRoutes(engine.Group("/api/v1"))
+	return extRouter, resolver, engine
+}
+
+func restExtension(path, handler string) *entity.PluginExtension {
+	return &entity.PluginExtension{Type: entity.PluginTypeRestEndpoint, Path: path, Handler: handler}
+}
+
+func serve(engine *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestExtensionRouter_Dispatch(t *testing.T) {
+	extRouter, resolver, engine := setupRouter(t)
+
+	err := extRouter.RegisterExtensions("weather", []*entity.PluginExtension{
+		restExtension("/weather/today/", "today"),
+		restExtension("forecast", "forecast"),
+		{Type: entity.PluginTypeService, Path: "/ignored", Handler: "svc"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterExtensions() error = %v", err)
+	}
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{http.MethodGet, "/api/v1/ext/weather/today", http.StatusOK, "weather:today"},
+		{http.MethodPost, "/api/v1/ext/forecast/", http.StatusOK, "weather:forecast"},
+		{http.MethodGet, "/api/v1/ext/ignored", http.StatusNotFound, ""},
+		{http.MethodGet, "/api/v1/ext/weather", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := serve(engine, tt.method, tt.path)
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, w.Code, tt.wantCode)
+		}
+		if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+			t.Errorf("%s %s body = %q, want %q", tt.method, tt.path, w.Body.String(), tt.wantBody)
+		}
+	}
+
+	// A registered plugin that is not running cannot serve its routes
+	resolver.stopped["weather"] = true
+	if w := serve(engine, http.MethodGet, "/api/v1/ext/forecast"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("stopped plugin status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestExtensionRouter_Reregister(t *testing.T) {
+	extRouter, _, engine := setupRouter(t)
+
+	if err := extRouter.RegisterExtensions("weather", []*entity.PluginExtension{restExtension("/old", "old")}); err != nil {
+		t.Fatalf("RegisterExtensions() error = %v", err)
+	}
+	// Registering again replaces the plugin's routes
+	for i := 0; i < 2; i++ {
+		if err := extRouter.RegisterExtensions("weather", []*entity.PluginExtension{restExtension("/new", "new")}); err != nil {
+			t.Fatalf("RegisterExtensions() again error = %v", err)
+		}
+	}
+
+	if w := serve(engine, http.MethodGet, "/api/v1/ext/old"); w.Code != http.StatusNotFound {
+		t.Errorf("replaced route status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := serve(engine, http.MethodGet, "/api/v1/ext/new"); w.Code != http.StatusOK {
+		t.Errorf("new route status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestExtensionRouter_Conflict(t *testing.T) {
+	extRouter, _, engine := setupRouter(t)
+
+	if err := extRouter.RegisterExtensions("first", []*entity.PluginExtension{restExtension("/shared", "a")}); err != nil {
+		t.Fatalf("RegisterExtensions() error = %v", err)
+	}
+
+	// A conflict registers none of the plugin's routes
+	err := extRouter.RegisterExtensions("second", []*entity.PluginExtension{
+		restExtension("/own", "own"),
+		restExtension("/shared/", "b"),
+	})
+	if !errors.Is(err, ErrRouteConflict) {
+		t.Errorf("RegisterExtensions() error = %v, want %v", err, ErrRouteConflict)
+	}
+	if w := serve(engine, http.MethodGet, "/api/v1/ext/own"); w.Code != http.StatusNotFound {
+		t.Errorf("rejected plugin route status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := serve(engine, http.MethodGet, "/api/v1/ext/shared"); w.Body.String() != "first:a" {
+		t.Errorf("shared route body = %q, want %q", w.Body.String(), "first:a")
+	}
+
+	err = extRouter.RegisterExtensions("third", []*entity.PluginExtension{
+		restExtension("/dup", "a"),
+		restExtension("/dup", "b"),
+	})
+	if !errors.Is(err, ErrRouteConflict) {
+		t.Errorf("RegisterExtensions() duplicate error = %v, want %v", err, ErrRouteConflict)
+	}
+
+	// Once the owner is gone the path is free to claim
+	extRouter.UnregisterExtensions("first")
+	if err := extRouter.RegisterExtensions("second", []*entity.PluginExtension{restExtension("/shared", "b")}); err != nil {
+		t.Errorf("RegisterExtensions() after unregister error = %v", err)
+	}
+}
+
+func TestExtensionRouter_Unregister(t *testing.T) {
+	extRouter, _, engine := setupRouter(t)
+
+	if err := extRouter.RegisterExtensions("weather", []*entity.PluginExtension{restExtension("/today", "today")}); err != nil {
+		t.Fatalf("RegisterExtensions() error = %v", err)
+	}
+	extRouter.UnregisterExtensions("weather")
+	extRouter.UnregisterExtensions("weather")
+	extRouter.UnregisterExtensions("unknown")
+
+	if w := serve(engine, http.MethodGet, "/api/v1/ext/today"); w.Code != http.StatusNotFound {
+		t.Errorf("unregistered route status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,339 @@
+// Package schema validates plugin configs against the JSON Schema a plugin
+// declares. It supports the subset of JSON Schema useful for flat and nested
+// config objects: type, properties, required, additionalProperties, items,
+// enum, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength,
+// maxLength, pattern, minItems and maxItems. Other keywords are ignored.
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FieldError describes a config value that does not match the schema
+type FieldError struct {
+	// Field is the path to the offending value, such as "servers[0].port";
+	// empty for the config itself
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+var jsonTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// Check reports whether a schema is well formed for the supported keywords,
+// so a broken schema is rejected when it is declared rather than when a
+// config is validated against it
+func Check(schema map[string]any) error {
+	return check(schema, "")
+}
+
+func check(schema map[string]any, path string) error {
+	for _, t := range schemaTypes(schema) {
+		if !jsonTypes[t] {
+			return fmt.Errorf("%sunknown type %q", at(path), t)
+		}
+	}
+	if t, ok := schema["type"]; ok {
+		if _, isString := t.(string); !isString && schemaTypes(schema) == nil {
+			return fmt.Errorf("%stype must be a string or a list of strings", at(path))
+		}
+	}
+
+	if props, ok := schema["properties"]; ok {
+		propMap, ok := props.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%sproperties must be an object", at(path))
+		}
+		for name, prop := range propMap {
+			propSchema, ok := prop.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%sproperty schema must be an object", at(join(path, name)))
+			}
+			if err := check(propSchema, join(path, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if items, ok := schema["items"]; ok {
+		itemSchema, ok := items.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%sitems must be an object", at(path))
+		}
+		if err := check(itemSchema, path+"[]"); err != nil {
+			return err
+		}
+	}
+
+	if required, ok := schema["required"]; ok {
+		if _, ok := stringList(required); !ok {
+			return fmt.Errorf("%srequired must be a list of strings", at(path))
+		}
+	}
+
+	if additional, ok := schema["additionalProperties"]; ok {
+		if _, ok := additional.(bool); !ok {
+			return fmt.Errorf("%sadditionalProperties must be a boolean", at(path))
+		}
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		if _, ok := enum.([]any); !ok {
+			return fmt.Errorf("%senum must be a list", at(path))
+		}
+	}
+
+	for _, keyword := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "minItems", "maxItems"} {
+		if v, ok := schema[keyword]; ok {
+			if _, ok := toFloat(v); !ok {
+				return fmt.Errorf("%s%s must be a number", at(path), keyword)
+			}
+		}
+	}
+
+	if pattern, ok := schema["pattern"]; ok {
+		s, ok := pattern.(string)
+		if !ok {
+			return fmt.Errorf("%spattern must be a string", at(path))
+		}
+		if _, err := regexp.Compile(s); err != nil {
+			return fmt.Errorf("%sinvalid pattern: %w", at(path), err)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks value against schema, returning every mismatch found. The
+// schema should have passed Check; malformed keywords are skipped.
+func Validate(schema map[string]any, value any) []FieldError {
+	var errs []FieldError
+	validate(schema, value, "", &errs)
+	return errs
+}
+
+func validate(schema map[string]any, value any, path string, errs *[]FieldError) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types := schemaTypes(schema); types != nil && !matchesAnyType(value, types) {
+		fail("must be of type %s", strings.Join(types, " or "))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !inEnum(value, enum) {
+		fail("must be one of %v", enum)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		validateObject(schema, v, path, errs)
+	case []any:
+		if n, ok := toFloat(schema["minItems"]); ok && float64(len(v)) < n {
+			fail("must have at least %v items", n)
+		}
+		if n, ok := toFloat(schema["maxItems"]); ok && float64(len(v)) > n {
+			fail("must have at most %v items", n)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				validate(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if n, ok := toFloat(schema["minLength"]); ok && length < n {
+			fail("must be at least %v characters", n)
+		}
+		if n, ok := toFloat(schema["maxLength"]); ok && length > n {
+			fail("must be at most %v characters", n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				fail("must match pattern %q", pattern)
+			}
+		}
+	default:
+		n, ok := toFloat(value)
+		if !ok {
+			return
+		}
+		if limit, ok := toFloat(schema["minimum"]); ok && n < limit {
+			fail("must be at least %v", limit)
+		}
+		if limit, ok := toFloat(schema["maximum"]); ok && n > limit {
+			fail("must be at most %v", limit)
+		}
+		if limit, ok := toFloat(schema["exclusiveMinimum"]); ok && n <= limit {
+			fail("must be greater than %v", limit)
+		}
+		if limit, ok := toFloat(schema["exclusiveMaximum"]); ok && n >= limit {
+			fail("must be less than %v", limit)
+		}
+	}
+}
+
+func validateObject(schema map[string]any, obj map[string]any, path string, errs *[]FieldError) {
+	required, _ := stringList(schema["required"])
+	for _, name := range required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, FieldError{Field: join(path, name), Message: "is required"})
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"].(bool)
+
+	// Walk fields in a stable order so errors are reported consistently
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propSchema, ok := props[name].(map[string]any)
+		if !ok {
+			if hasAdditional && !additional {
+				*errs = append(*errs, FieldError{Field: join(path, name), Message: "is not allowed"})
+			}
+			continue
+		}
+		validate(propSchema, obj[name], join(path, name), errs)
+	}
+}
+
+// schemaTypes returns the types a schema allows, or nil if it does not
+// constrain the type
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	default:
+		types, _ := stringList(t)
+		return types
+	}
+}
+
+func matchesAnyType(value any, types []string) bool {
+	for _, t := range types {
+		if matchesType(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(value any, t string) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		n, ok := toFloat(value)
+		return ok && n == math.Trunc(n)
+	}
+	return false
+}
+
+func inEnum(value any, enum []any) bool {
+	n, isNumber := toFloat(value)
+	for _, candidate := range enum {
+		if isNumber {
+			if m, ok := toFloat(candidate); ok && m == n {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// toFloat converts a JSON number, as decoded by encoding/json or written in
+// Go, to a float64
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func stringList(v any) ([]string, bool) {
+	switch list := v.(type) {
+	case []string:
+		return list, true
+	case []any:
+		result := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// at prefixes a schema error with the path it occurred at
+func at(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + ": "
+}
@@ -0,0 +1,139 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decode(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var v map[string]any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("invalid test JSON %s: %v", s, err)
+	}
+	return v
+}
+
+const testSchema = `{
+	"type": "object",
+	"required": ["endpoint", "port"],
+	"additionalProperties": false,
+	"properties": {
+		"endpoint": {"type": "string", "pattern": "^https?://", "maxLength": 50},
+		"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+		"mode": {"enum": ["fast", "safe"]},
+		"ratio": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string", "minLength": 1}},
+		"retry": {
+			"type": "object",
+			"properties": {"attempts": {"type": "integer"}}
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	schema := decode(t, testSchema)
+
+	tests := []struct {
+		name   string
+		config string
+		want   []FieldError
+	}{
+		{
+			name:   "valid",
+			config: `{"endpoint": "https://api", "port": 443, "mode": "safe", "ratio": 0.5, "tags": ["a"], "retry": {"attempts": 3}}`,
+		},
+		{
+			name:   "missing required",
+			config: `{"endpoint": "https://api"}`,
+			want:   []FieldError{{Field: "port", Message: "is required"}},
+		},
+		{
+			name:   "wrong types",
+			config: `{"endpoint": 1, "port": 1.5}`,
+			want: []FieldError{
+				{Field: "endpoint", Message: "must be of type string"},
+				{Field: "port", Message: "must be of type integer"},
+			},
+		},
+		{
+			name:   "constraints",
+			config: `{"endpoint": "ftp://api", "port": 0, "mode": "slow", "ratio": 1, "tags": ["", "b", "c"]}`,
+			want: []FieldError{
+				{Field: "endpoint", Message: `must match pattern "^https?://"`},
+				{Field: "mode", Message: "must be one of [fast safe]"},
+				{Field: "port", Message: "must be at least 1"},
+				{Field: "ratio", Message: "must be less than 1"},
+				{Field: "tags", Message: "must have at most 2 items"},
+				{Field: "tags[0]", Message: "must be at least 1 characters"},
+			},
+		},
+		{
+			name:   "nested and unknown fields",
+			config: `{"endpoint": "http://api", "port": 80, "retry": {"attempts": "3"}, "extra": true}`,
+			want: []FieldError{
+				{Field: "extra", Message: "is not allowed"},
+				{Field: "retry.attempts", Message: "must be of type integer"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Validate(schema, decode(t, tt.config))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_GoValues(t *testing.T) {
+	schema := decode(t, `{"properties": {"port": {"type": "integer", "maximum": 10}}}`)
+
+	got := Validate(schema, map[string]any{"port": 11})
+	want := []FieldError{{Field: "port", Message: "must be at most 10"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{"valid", testSchema, false},
+		{"empty", `{}`, false},
+		{"type list", `{"type": ["string", "null"]}`, false},
+		{"unknown type", `{"type": "text"}`, true},
+		{"bad type", `{"type": 1}`, true},
+		{"bad properties", `{"properties": []}`, true},
+		{"bad nested property", `{"properties": {"a": {"type": "str"}}}`, true},
+		{"bad required", `{"required": "a"}`, true},
+		{"bad additionalProperties", `{"additionalProperties": {}}`, true},
+		{"bad minimum", `{"minimum": "1"}`, true},
+		{"bad pattern", `{"pattern": "("}`, true},
+		{"bad items", `{"items": [{"type": "string"}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(decode(t, tt.schema))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldError_Error(t *testing.T) {
+	if got := (FieldError{Field: "port", Message: "is required"}).Error(); got != "port: is required" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := (FieldError{Message: "must be of type object"}).Error(); got != "must be of type object" {
+		t.Errorf("Error() = %q", got)
+	}
+}
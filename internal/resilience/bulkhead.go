@@ -0,0 +1,137 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// BulkheadConfig holds bulkhead configuration
+type BulkheadConfig struct {
+	Name          string        `mapstructure:"name"`
+	MaxConcurrent int           `mapstructure:"max_concurrent"` // max in-flight calls
+	MaxQueue      int           `mapstructure:"max_queue"`      // max calls waiting for a slot
+	WaitTimeout   time.Duration `mapstructure:"wait_timeout"`   // max wait for a slot
+}
+
+// DefaultBulkheadConfig returns default configuration
+func DefaultBulkheadConfig(name string) *BulkheadConfig {
+	return &BulkheadConfig{
+		Name:          name,
+		MaxConcurrent: 10,
+		MaxQueue:      20,
+		WaitTimeout:   time.Second,
+	}
+}
+
+// Bulkhead caps concurrent in-flight calls so a slow dependency can't exhaust
+// goroutines. Calls beyond MaxConcurrent wait in a bounded queue for up to
+// WaitTimeout; calls that can't be queued or time out are rejected.
+type Bulkhead struct {
+	config  *BulkheadConfig
+	slots   chan struct{}
+	metrics *BulkheadMetrics
+}
+
+// BulkheadMetrics holds bulkhead metrics (internal, contains mutex)
+type BulkheadMetrics struct {
+	ActiveCalls   int64
+	QueuedCalls   int64
+	TotalCalls    int64
+	RejectedCalls int64
+	mutex         sync.RWMutex
+}
+
+// BulkheadMetricsSnapshot is a read-only snapshot of BulkheadMetrics (safe to copy)
+type BulkheadMetricsSnapshot struct {
+	ActiveCalls   int64
+	QueuedCalls   int64
+	TotalCalls    int64
+	RejectedCalls int64
+}
+
+// NewBulkhead creates a new bulkhead
+func NewBulkhead(config *BulkheadConfig) *Bulkhead {
+	return &Bulkhead{
+		config:  config,
+		slots:   make(chan struct{}, config.MaxConcurrent),
+		metrics: &BulkheadMetrics{},
+	}
+}
+
+// Acquire reserves a slot, waiting in the queue if all slots are busy. It
+// returns ErrBulkheadFull if the queue is saturated or no slot frees up within
+// WaitTimeout. Every successful Acquire must be paired with Release.
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	b.metrics.mutex.Lock()
+	b.metrics.TotalCalls++
+	select {
+	case b.slots <- struct{}{}:
+		b.metrics.ActiveCalls++
+		b.metrics.mutex.Unlock()
+		return nil
+	default:
+	}
+	if b.metrics.QueuedCalls >= int64(b.config.MaxQueue) {
+		b.metrics.RejectedCalls++
+		b.metrics.mutex.Unlock()
+		return ErrBulkheadFull
+	}
+	b.metrics.QueuedCalls++
+	b.metrics.mutex.Unlock()
+
+	timer := time.NewTimer(b.config.WaitTimeout)
+	defer timer.Stop()
+
+	select {
+	case b.slots <- struct{}{}:
+		b.metrics.mutex.Lock()
+		b.metrics.QueuedCalls--
+		b.metrics.ActiveCalls++
+		b.metrics.mutex.Unlock()
+		return nil
+	case <-timer.C:
+		b.metrics.mutex.Lock()
+		b.metrics.QueuedCalls--
+		b.metrics.RejectedCalls++
+		b.metrics.mutex.Unlock()
+		return ErrBulkheadFull
+	case <-ctx.Done():
+		b.metrics.mutex.Lock()
+		b.metrics.QueuedCalls--
+		b.metrics.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot reserved by Acquire
+func (b *Bulkhead) Release() {
+	b.metrics.mutex.Lock()
+	b.metrics.ActiveCalls--
+	b.metrics.mutex.Unlock()
+	<-b.slots
+}
+
+// Execute runs fn once a slot is available
+func (b *Bulkhead) Execute(ctx context.Context, fn func(context.Context) error) error {
+	if err := b.Acquire(ctx); err != nil {
+		return err
+	}
+	defer b.Release()
+	return fn(ctx)
+}
+
+// Metrics returns a snapshot of the current metrics
+func (b *Bulkhead) Metrics() BulkheadMetricsSnapshot {
+	b.metrics.mutex.RLock()
+	defer b.metrics.mutex.RUnlock()
+	return BulkheadMetricsSnapshot{
+		ActiveCalls:   b.metrics.ActiveCalls,
+		QueuedCalls:   b.metrics.QueuedCalls,
+		TotalCalls:    b.metrics.TotalCalls,
+		RejectedCalls: b.metrics.RejectedCalls,
+	}
+}
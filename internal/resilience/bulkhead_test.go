@@ -0,0 +1,133 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDefaultBulkheadConfig(t *testing.T) {
+	cfg := DefaultBulkheadConfig("test")
+	if cfg.Name != "test" {
+		t.Errorf("Name = %v, want test", cfg.Name)
+	}
+	if cfg.MaxConcurrent != 10 {
+		t.Errorf("MaxConcurrent = %v, want 10", cfg.MaxConcurrent)
+	}
+	if cfg.MaxQueue != 20 {
+		t.Errorf("MaxQueue = %v, want 20", cfg.MaxQueue)
+	}
+	if cfg.WaitTimeout != time.Second {
+		t.Errorf("WaitTimeout = %v, want 1s", cfg.WaitTimeout)
+	}
+}
+
+func TestBulkhead_AcquireRelease(t *testing.T) {
+	b := NewBulkhead(&BulkheadConfig{Name: "test", MaxConcurrent: 2, WaitTimeout: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	if err := b.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if err := b.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if m := b.Metrics(); m.ActiveCalls != 2 {
+		t.Errorf("ActiveCalls = %v, want 2", m.ActiveCalls)
+	}
+
+	// No queue: the third call is rejected immediately
+	if err := b.Acquire(ctx); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Acquire() error = %v, want ErrBulkheadFull", err)
+	}
+
+	b.Release()
+	if err := b.Acquire(ctx); err != nil {
+		t.Errorf("Acquire() after Release() error = %v", err)
+	}
+
+	m := b.Metrics()
+	if m.TotalCalls != 4 || m.RejectedCalls != 1 || m.ActiveCalls != 2 {
+		t.Errorf("metrics = %+v, want 4 total, 1 rejected, 2 active", m)
+	}
+}
+
+func TestBulkhead_QueuedCallGetsSlot(t *testing.T) {
+	b := NewBulkhead(&BulkheadConfig{Name: "test", MaxConcurrent: 1, MaxQueue: 1, WaitTimeout: time.Second})
+	ctx := context.Background()
+
+	if err := b.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- b.Acquire(ctx) }()
+
+	// Wait until the call is queued, then saturate the queue
+	deadline := time.Now().Add(time.Second)
+	for b.Metrics().QueuedCalls != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := b.Acquire(ctx); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Acquire() with full queue error = %v, want ErrBulkheadFull", err)
+	}
+
+	b.Release()
+	if err := <-acquired; err != nil {
+		t.Errorf("queued Acquire() error = %v", err)
+	}
+	if m := b.Metrics(); m.QueuedCalls != 0 || m.ActiveCalls != 1 {
+		t.Errorf("metrics = %+v, want 0 queued, 1 active", m)
+	}
+}
+
+func TestBulkhead_WaitTimeout(t *testing.T) {
+	b := NewBulkhead(&BulkheadConfig{Name: "test", MaxConcurrent: 1, MaxQueue: 1, WaitTimeout: 20 * time.Millisecond})
+	ctx := context.Background()
+	b.Acquire(ctx)
+
+	start := time.Now()
+	if err := b.Acquire(ctx); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Acquire() error = %v, want ErrBulkheadFull", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Acquire() gave up after %v, want >= WaitTimeout", elapsed)
+	}
+	if m := b.Metrics(); m.QueuedCalls != 0 || m.RejectedCalls != 1 {
+		t.Errorf("metrics = %+v, want 0 queued, 1 rejected", m)
+	}
+}
+
+func TestBulkhead_ContextCancelled(t *testing.T) {
+	b := NewBulkhead(&BulkheadConfig{Name: "test", MaxConcurrent: 1, MaxQueue: 1, WaitTimeout: time.Second})
+	b.Acquire(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestBulkhead_Execute(t *testing.T) {
+	b := NewBulkhead(DefaultBulkheadConfig("test"))
+	cb := NewCircuitBreaker(DefaultCircuitBreakerConfig("test"), newTestLogger())
+	testErr := errors.New("downstream error")
+
+	// Bulkhead and circuit breaker compose through the same Execute signature
+	err := b.Execute(context.Background(), func(ctx context.Context) error {
+		return cb.Execute(ctx, func(ctx context.Context) error {
+			if m := b.Metrics(); m.ActiveCalls != 1 {
+				t.Errorf("ActiveCalls during call = %v, want 1", m.ActiveCalls)
+			}
+			return testErr
+		})
+	})
+	if !errors.Is(err, testErr) {
+		t.Errorf("Execute() error = %v, want %v", err, testErr)
+	}
+	if m := b.Metrics(); m.ActiveCalls != 0 {
+		t.Errorf("ActiveCalls after call = %v, want 0", m.ActiveCalls)
+	}
+}
@@ -31,12 +31,30 @@ func (s State) String() string {
 	}
 }
 
+// Sliding window types
+const (
+	SlidingWindowTypeCount = "count"
+	SlidingWindowTypeTime  = "time"
+)
+
 var (
 	ErrCircuitOpen     = errors.New("circuit breaker is open")
 	ErrTooManyRequests = errors.New("too many requests in half-open state")
 )
 
-// CircuitBreakerConfig holds circuit breaker configuration
+// CircuitBreakerConfig holds circuit breaker configuration.
+//
+// By default the breaker opens after FailureThreshold consecutive failures.
+// Setting FailureRateThreshold opens it instead when the failure rate over the
+// sliding window reaches the threshold, and setting SlowCallRateThreshold also
+// opens it when the rate of calls slower than SlowCallDurationThreshold does.
+// Rates are only evaluated once the window holds MinimumCalls outcomes
+// (the full SlidingWindowSize when zero).
+//
+// The window holds the last SlidingWindowSize outcomes, however old. With
+// SlidingWindowType "time" it holds the outcomes of the last
+// SlidingWindowDuration instead, counted in SlidingWindowBuckets buckets, so
+// that failures stop counting once they age out when traffic is low.
 type CircuitBreakerConfig struct {
 	Name                     string        `mapstructure:"name"`
 	FailureThreshold         int           `mapstructure:"failure_threshold"`
@@ -45,8 +63,12 @@ type CircuitBreakerConfig struct {
 	MaxHalfOpenRequests      int           `mapstructure:"max_half_open_requests"`
 	SlidingWindowSize        int           `mapstructure:"sliding_window_size"`
 	SlidingWindowType        string        `mapstructure:"sliding_window_type"` // "count" or "time"
+	SlidingWindowDuration    time.Duration `mapstructure:"sliding_window_duration"` // for the "time" type
+	SlidingWindowBuckets     int           `mapstructure:"sliding_window_buckets"`  // for the "time" type
 	SlowCallDurationThreshold time.Duration `mapstructure:"slow_call_duration_threshold"`
 	SlowCallRateThreshold    float64       `mapstructure:"slow_call_rate_threshold"`
+	FailureRateThreshold     float64       `mapstructure:"failure_rate_threshold"`
+	MinimumCalls             int           `mapstructure:"minimum_calls"`
 }
 
 // DefaultCircuitBreakerConfig returns default configuration
@@ -58,9 +80,10 @@ func DefaultCircuitBreakerConfig(name string) *CircuitBreakerConfig {
 		Timeout:                  30 * time.Second,
 		MaxHalfOpenRequests:      3,
 		SlidingWindowSize:        10,
-		SlidingWindowType:        "count",
+		SlidingWindowType:        SlidingWindowTypeCount,
+		SlidingWindowDuration:    time.Minute,
+		SlidingWindowBuckets:     DefaultTimeWindowBuckets,
 		SlowCallDurationThreshold: 2 * time.Second,
-		SlowCallRateThreshold:    0.5,
 	}
 }
 
@@ -75,7 +98,7 @@ type CircuitBreaker struct {
 	mutex            sync.RWMutex
 	logger           *zap.Logger
 	metrics          *CircuitBreakerMetrics
-	slidingWindow    *SlidingWindow
+	slidingWindow    outcomeWindow
 }
 
 // CircuitBreakerMetrics holds circuit breaker metrics (internal, contains mutex)
@@ -131,6 +154,21 @@ func (sw *SlidingWindow) Record(success bool, duration time.Duration) {
 	}
 }
 
+// Count returns the number of outcomes in the window
+func (sw *SlidingWindow) Count() int {
+	sw.mutex.RLock()
+	defer sw.mutex.RUnlock()
+	return sw.count
+}
+
+// Reset discards all recorded outcomes
+func (sw *SlidingWindow) Reset() {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	sw.index = 0
+	sw.count = 0
+}
+
 // FailureRate returns the failure rate
 func (sw *SlidingWindow) FailureRate() float64 {
 	sw.mutex.RLock()
@@ -167,6 +205,33 @@ func (sw *SlidingWindow) SlowCallRate(threshold time.Duration) float64 {
 	return float64(slowCalls) / float64(sw.count)
 }
 
+// outcomeWindow is the window of recent outcomes a breaker computes its rates over
+type outcomeWindow interface {
+	Record(success bool, duration time.Duration)
+	Count() int
+	Reset()
+	FailureRate() float64
+	SlowCallRate() float64
+}
+
+// countWindow adapts a SlidingWindow to the breaker's slow call threshold
+type countWindow struct {
+	*SlidingWindow
+	slowCallThreshold time.Duration
+}
+
+func (w countWindow) SlowCallRate() float64 {
+	return w.SlidingWindow.SlowCallRate(w.slowCallThreshold)
+}
+
+// newOutcomeWindow creates the sliding window selected by config
+func newOutcomeWindow(config *CircuitBreakerConfig) outcomeWindow {
+	if config.SlidingWindowType == SlidingWindowTypeTime {
+		return NewTimeSlidingWindow(config.SlidingWindowDuration, config.SlidingWindowBuckets, config.SlowCallDurationThreshold)
+	}
+	return countWindow{NewSlidingWindow(config.SlidingWindowSize), config.SlowCallDurationThreshold}
+}
+
 // NewCircuitBreaker creates a new circuit breaker
 func NewCircuitBreaker(config *CircuitBreakerConfig, logger *zap.Logger) *CircuitBreaker {
 	return &CircuitBreaker{
@@ -174,7 +239,7 @@ func NewCircuitBreaker(config *CircuitBreakerConfig, logger *zap.Logger) *Circui
 		state:         StateClosed,
 		logger:        logger.With(zap.String("circuit_breaker", config.Name)),
 		metrics:       &CircuitBreakerMetrics{},
-		slidingWindow: NewSlidingWindow(config.SlidingWindowSize),
+		slidingWindow: newOutcomeWindow(config),
 	}
 }
 
@@ -251,15 +316,38 @@ func (cb *CircuitBreaker) updateMetrics(success bool, duration time.Duration) {
 func (cb *CircuitBreaker) updateStateClosed(success bool) {
 	if success {
 		cb.failures = 0
-		return
+	} else {
+		cb.failures++
+		cb.lastFailure = time.Now()
 	}
-	cb.failures++
-	cb.lastFailure = time.Now()
-	if cb.failures >= cb.config.FailureThreshold {
+	if cb.shouldOpen() {
+		cb.lastFailure = time.Now()
 		cb.transitionTo(StateOpen)
 	}
 }
 
+// shouldOpen reports whether a closed breaker should trip (must be called with mutex held)
+func (cb *CircuitBreaker) shouldOpen() bool {
+	if cb.config.FailureRateThreshold <= 0 && cb.failures >= cb.config.FailureThreshold {
+		return true
+	}
+
+	minimumCalls := cb.config.MinimumCalls
+	if minimumCalls <= 0 {
+		minimumCalls = cb.config.SlidingWindowSize
+	}
+	if cb.slidingWindow.Count() < minimumCalls {
+		return false
+	}
+
+	if cb.config.FailureRateThreshold > 0 &&
+		cb.slidingWindow.FailureRate() >= cb.config.FailureRateThreshold {
+		return true
+	}
+	return cb.config.SlowCallRateThreshold > 0 &&
+		cb.slidingWindow.SlowCallRate() >= cb.config.SlowCallRateThreshold
+}
+
 // updateStateHalfOpen handles state transitions for the HalfOpen state
 func (cb *CircuitBreaker) updateStateHalfOpen(success bool) {
 	if success {
@@ -306,6 +394,10 @@ func (cb *CircuitBreaker) transitionTo(newState State) {
 	cb.failures = 0
 	cb.successes = 0
 	cb.halfOpenRequests = 0
+	if newState == StateClosed {
+		// Start the rates afresh so outcomes from before the circuit opened don't trip it again
+		cb.slidingWindow.Reset()
+	}
 
 	cb.metrics.mutex.Lock()
 	cb.metrics.StateTransitions++
@@ -346,4 +438,5 @@ func (cb *CircuitBreaker) Reset() {
 	cb.failures = 0
 	cb.successes = 0
 	cb.halfOpenRequests = 0
+	cb.slidingWindow.Reset()
 }
@@ -441,6 +441,99 @@ func TestCircuitBreaker_SlowCalls(t *testing.T) {
 	}
 }
 
+func TestCircuitBreaker_FailureRateThreshold(t *testing.T) {
+	cfg := DefaultCircuitBreakerConfig("rate")
+	cfg.FailureThreshold = 2 // ignored in rate mode
+	cfg.FailureRateThreshold = 0.5
+	cfg.MinimumCalls = 4
+	cb := NewCircuitBreaker(cfg, newTestLogger())
+
+	ctx := context.Background()
+	fail := func(ctx context.Context) error { return errors.New("fail") }
+	succeed := func(ctx context.Context) error { return nil }
+
+	// Consecutive failures below MinimumCalls don't open the circuit
+	cb.Execute(ctx, fail)
+	cb.Execute(ctx, fail)
+	cb.Execute(ctx, succeed)
+	if cb.State() != StateClosed {
+		t.Fatalf("State before MinimumCalls = %v, want CLOSED", cb.State())
+	}
+
+	// 2 of 4 calls failed: 50% reaches the threshold
+	cb.Execute(ctx, succeed)
+	if cb.State() != StateOpen {
+		t.Errorf("State at 50%% failure rate = %v, want OPEN", cb.State())
+	}
+}
+
+func TestCircuitBreaker_FailureRateBelowThreshold(t *testing.T) {
+	cfg := DefaultCircuitBreakerConfig("rate")
+	cfg.FailureRateThreshold = 0.5
+	cfg.SlidingWindowSize = 4
+	cb := NewCircuitBreaker(cfg, newTestLogger())
+
+	ctx := context.Background()
+	for i := 0; i < 8; i++ {
+		cb.Execute(ctx, func(ctx context.Context) error {
+			if i%4 == 0 {
+				return errors.New("fail")
+			}
+			return nil
+		})
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("State at 25%% failure rate = %v, want CLOSED", cb.State())
+	}
+}
+
+func TestCircuitBreaker_SlowCallRateThreshold(t *testing.T) {
+	cfg := DefaultCircuitBreakerConfig("slow")
+	cfg.SlidingWindowSize = 2
+	cfg.SlowCallDurationThreshold = 5 * time.Millisecond
+	cfg.SlowCallRateThreshold = 1
+	cb := NewCircuitBreaker(cfg, newTestLogger())
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		cb.Execute(ctx, func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("State after slow calls = %v, want OPEN", cb.State())
+	}
+}
+
+func TestCircuitBreaker_RateWindowResetsOnClose(t *testing.T) {
+	cfg := DefaultCircuitBreakerConfig("rate")
+	cfg.FailureRateThreshold = 0.5
+	cfg.SlidingWindowSize = 2
+	cfg.SuccessThreshold = 1
+	cfg.Timeout = 10 * time.Millisecond
+	cb := NewCircuitBreaker(cfg, newTestLogger())
+
+	ctx := context.Background()
+	cb.Execute(ctx, func(ctx context.Context) error { return errors.New("fail") })
+	cb.Execute(ctx, func(ctx context.Context) error { return errors.New("fail") })
+	if cb.State() != StateOpen {
+		t.Fatalf("State = %v, want OPEN", cb.State())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(ctx, func(ctx context.Context) error { return nil })
+	if cb.State() != StateClosed {
+		t.Fatalf("State after half-open success = %v, want CLOSED", cb.State())
+	}
+
+	// Failures from before the circuit opened no longer count
+	cb.Execute(ctx, func(ctx context.Context) error { return nil })
+	if cb.State() != StateClosed {
+		t.Errorf("State = %v, want CLOSED", cb.State())
+	}
+}
+
 // SlidingWindow tests
 func TestSlidingWindow_FailureRate(t *testing.T) {
 	sw := NewSlidingWindow(10)
@@ -0,0 +1,133 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+var ErrConcurrencyLimitExceeded = errors.New("concurrency limit exceeded")
+
+// ConcurrencyLimiterConfig holds concurrency limiter configuration
+type ConcurrencyLimiterConfig struct {
+	Name          string        `mapstructure:"name"`
+	MaxConcurrent int           `mapstructure:"max_concurrent"` // max in-flight calls
+	MaxWait       time.Duration `mapstructure:"max_wait"`       // max wait for a slot; 0 waits until ctx is done
+}
+
+// DefaultConcurrencyLimiterConfig returns default configuration
+func DefaultConcurrencyLimiterConfig(name string) *ConcurrencyLimiterConfig {
+	return &ConcurrencyLimiterConfig{
+		Name:          name,
+		MaxConcurrent: 10,
+	}
+}
+
+// ConcurrencyLimiter caps in-flight calls at MaxConcurrent, with no time
+// dimension: unlike a rate limiter, a slot is held for as long as the call
+// runs. Callers beyond the limit wait, in arrival order, for up to MaxWait.
+//
+// Unlike Bulkhead, the number of waiting callers is not capped, so a call is
+// only rejected for having waited too long; use a Bulkhead to also shed load
+// once a bounded queue is full.
+type ConcurrencyLimiter struct {
+	config  *ConcurrencyLimiterConfig
+	sem     *semaphore.Weighted
+	metrics *ConcurrencyLimiterMetrics
+}
+
+// ConcurrencyLimiterMetrics holds concurrency limiter metrics (internal, contains mutex)
+type ConcurrencyLimiterMetrics struct {
+	ActiveCalls   int64
+	WaitingCalls  int64
+	TotalCalls    int64
+	RejectedCalls int64
+	mutex         sync.RWMutex
+}
+
+// ConcurrencyLimiterMetricsSnapshot is a read-only snapshot of ConcurrencyLimiterMetrics (safe to copy)
+type ConcurrencyLimiterMetricsSnapshot struct {
+	ActiveCalls   int64
+	WaitingCalls  int64
+	TotalCalls    int64
+	RejectedCalls int64
+}
+
+// NewConcurrencyLimiter creates a new concurrency limiter
+func NewConcurrencyLimiter(config *ConcurrencyLimiterConfig) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		config:  config,
+		sem:     semaphore.NewWeighted(int64(config.MaxConcurrent)),
+		metrics: &ConcurrencyLimiterMetrics{},
+	}
+}
+
+// Acquire reserves a slot, waiting if all slots are busy. It returns
+// ErrConcurrencyLimitExceeded if no slot frees up within MaxWait, or the
+// context error if ctx is done first. Every successful Acquire must be paired
+// with Release.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) error {
+	l.metrics.mutex.Lock()
+	l.metrics.TotalCalls++
+	if l.sem.TryAcquire(1) {
+		l.metrics.ActiveCalls++
+		l.metrics.mutex.Unlock()
+		return nil
+	}
+	l.metrics.WaitingCalls++
+	l.metrics.mutex.Unlock()
+
+	waitCtx := ctx
+	if l.config.MaxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, l.config.MaxWait)
+		defer cancel()
+	}
+	err := l.sem.Acquire(waitCtx, 1)
+
+	l.metrics.mutex.Lock()
+	defer l.metrics.mutex.Unlock()
+	l.metrics.WaitingCalls--
+	switch {
+	case err == nil:
+		l.metrics.ActiveCalls++
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	default:
+		l.metrics.RejectedCalls++
+		return ErrConcurrencyLimitExceeded
+	}
+}
+
+// Release frees a slot reserved by Acquire
+func (l *ConcurrencyLimiter) Release() {
+	l.metrics.mutex.Lock()
+	l.metrics.ActiveCalls--
+	l.metrics.mutex.Unlock()
+	l.sem.Release(1)
+}
+
+// Execute runs fn once a slot is available
+func (l *ConcurrencyLimiter) Execute(ctx context.Context, fn func(context.Context) error) error {
+	if err := l.Acquire(ctx); err != nil {
+		return err
+	}
+	defer l.Release()
+	return fn(ctx)
+}
+
+// Metrics returns a snapshot of the current metrics
+func (l *ConcurrencyLimiter) Metrics() ConcurrencyLimiterMetricsSnapshot {
+	l.metrics.mutex.RLock()
+	defer l.metrics.mutex.RUnlock()
+	return ConcurrencyLimiterMetricsSnapshot{
+		ActiveCalls:   l.metrics.ActiveCalls,
+		WaitingCalls:  l.metrics.WaitingCalls,
+		TotalCalls:    l.metrics.TotalCalls,
+		RejectedCalls: l.metrics.RejectedCalls,
+	}
+}
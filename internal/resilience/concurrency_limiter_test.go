@@ -0,0 +1,94 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDefaultConcurrencyLimiterConfig(t *testing.T) {
+	cfg := DefaultConcurrencyLimiterConfig("test")
+	if cfg.Name != "test" || cfg.MaxConcurrent != 10 || cfg.MaxWait != 0 {
+		t.Errorf("DefaultConcurrencyLimiterConfig() = %+v", cfg)
+	}
+}
+
+func TestConcurrencyLimiter_Execute_Limit(t *testing.T) {
+	limiter := NewConcurrencyLimiter(&ConcurrencyLimiterConfig{Name: "test", MaxConcurrent: 2})
+
+	var active, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := limiter.Execute(context.Background(), func(ctx context.Context) error {
+				n := active.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				active.Add(-1)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Execute() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak.Load())
+	}
+	metrics := limiter.Metrics()
+	if metrics.TotalCalls != 10 || metrics.ActiveCalls != 0 || metrics.WaitingCalls != 0 || metrics.RejectedCalls != 0 {
+		t.Errorf("Metrics() = %+v", metrics)
+	}
+}
+
+func TestConcurrencyLimiter_Acquire_MaxWait(t *testing.T) {
+	limiter := NewConcurrencyLimiter(&ConcurrencyLimiterConfig{Name: "test", MaxConcurrent: 1, MaxWait: 20 * time.Millisecond})
+
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if err := limiter.Acquire(context.Background()); !errors.Is(err, ErrConcurrencyLimitExceeded) {
+		t.Errorf("Acquire() error = %v, want ErrConcurrencyLimitExceeded", err)
+	}
+
+	metrics := limiter.Metrics()
+	if metrics.ActiveCalls != 1 || metrics.RejectedCalls != 1 {
+		t.Errorf("Metrics() = %+v, want 1 active and 1 rejected", metrics)
+	}
+
+	limiter.Release()
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire() after Release() error = %v", err)
+	}
+}
+
+func TestConcurrencyLimiter_Acquire_ContextCancelled(t *testing.T) {
+	limiter := NewConcurrencyLimiter(&ConcurrencyLimiterConfig{Name: "test", MaxConcurrent: 1})
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// Without MaxWait, a caller waits until its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	metrics := limiter.Metrics()
+	if metrics.WaitingCalls != 0 || metrics.RejectedCalls != 0 {
+		t.Errorf("Metrics() = %+v, want no waiting or rejected calls", metrics)
+	}
+}
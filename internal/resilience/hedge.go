@@ -0,0 +1,144 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HedgeConfig holds hedged request configuration
+type HedgeConfig struct {
+	Name       string        `mapstructure:"name"`
+	HedgeDelay time.Duration `mapstructure:"hedge_delay"` // wait before each backup attempt
+	MaxHedges  int           `mapstructure:"max_hedges"`  // backup attempts beyond the first
+	// Metrics, when set, counts the hedged calls; share it between the calls
+	// to the same downstream
+	Metrics *HedgeMetrics `mapstructure:"-"`
+}
+
+// DefaultHedgeConfig returns default configuration
+func DefaultHedgeConfig(name string) *HedgeConfig {
+	return &HedgeConfig{
+		Name:       name,
+		HedgeDelay: 100 * time.Millisecond,
+		MaxHedges:  1,
+	}
+}
+
+// HedgeMetrics counts hedged calls and which attempts won them
+type HedgeMetrics struct {
+	totalCalls    int64
+	hedgedCalls   int64
+	hedgesStarted int64
+	wins          map[int]int64
+	mutex         sync.RWMutex
+}
+
+// HedgeMetricsSnapshot is a read-only snapshot of HedgeMetrics (safe to copy)
+type HedgeMetricsSnapshot struct {
+	TotalCalls    int64
+	HedgedCalls   int64         // calls that started at least one backup attempt
+	HedgesStarted int64         // backup attempts started
+	Wins          map[int]int64 // successful calls by winning attempt, 0 being the first
+}
+
+// NewHedgeMetrics creates empty hedged request metrics
+func NewHedgeMetrics() *HedgeMetrics {
+	return &HedgeMetrics{wins: make(map[int]int64)}
+}
+
+func (m *HedgeMetrics) record(hedges, winner int) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.totalCalls++
+	if hedges > 0 {
+		m.hedgedCalls++
+		m.hedgesStarted += int64(hedges)
+	}
+	if winner >= 0 {
+		m.wins[winner]++
+	}
+}
+
+// Snapshot returns a snapshot of the current metrics
+func (m *HedgeMetrics) Snapshot() HedgeMetricsSnapshot {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	wins := make(map[int]int64, len(m.wins))
+	for attempt, count := range m.wins {
+		wins[attempt] = count
+	}
+	return HedgeMetricsSnapshot{
+		TotalCalls:    m.totalCalls,
+		HedgedCalls:   m.hedgedCalls,
+		HedgesStarted: m.hedgesStarted,
+		Wins:          wins,
+	}
+}
+
+// Hedge runs fn and, each time HedgeDelay passes without a result, starts
+// another concurrent attempt, up to MaxHedges more. The first success is
+// returned and the other attempts' contexts are cancelled. If every attempt
+// started fails, the last error is returned without starting more: hedging
+// works around slow responses, use Retry for failures.
+//
+// Only hedge idempotent reads, since several attempts may complete. Like
+// WithTimeout, fn must honor ctx cancellation for losing attempts to stop.
+func Hedge[T any](ctx context.Context, config *HedgeConfig, fn func(context.Context) (T, error)) (T, error) {
+	hedgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		attempt int
+		result  T
+		err     error
+	}
+	maxAttempts := 1 + max(config.MaxHedges, 0)
+	// Buffered so losing attempts can finish without blocking
+	done := make(chan outcome, maxAttempts)
+	start := func(attempt int) {
+		go func() {
+			result, err := fn(hedgeCtx)
+			done <- outcome{attempt, result, err}
+		}()
+	}
+
+	started, pending := 1, 1
+	start(0)
+
+	timer := time.NewTimer(config.HedgeDelay)
+	defer timer.Stop()
+
+	var zero T
+	var lastErr error
+	for {
+		select {
+		case o := <-done:
+			pending--
+			if o.err == nil {
+				config.Metrics.record(started-1, o.attempt)
+				return o.result, nil
+			}
+			lastErr = o.err
+			if pending == 0 {
+				config.Metrics.record(started-1, -1)
+				return zero, lastErr
+			}
+		case <-timer.C:
+			if started < maxAttempts {
+				start(started)
+				started++
+				pending++
+				timer.Reset(config.HedgeDelay)
+			}
+		case <-ctx.Done():
+			config.Metrics.record(started-1, -1)
+			return zero, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDefaultHedgeConfig(t *testing.T) {
+	cfg := DefaultHedgeConfig("test")
+	if cfg.Name != "test" || cfg.HedgeDelay != 100*time.Millisecond || cfg.MaxHedges != 1 {
+		t.Errorf("DefaultHedgeConfig() = %+v", cfg)
+	}
+}
+
+func TestHedge_FastFirstAttempt(t *testing.T) {
+	metrics := NewHedgeMetrics()
+	cfg := &HedgeConfig{Name: "test", HedgeDelay: 50 * time.Millisecond, MaxHedges: 2, Metrics: metrics}
+
+	var calls atomic.Int32
+	result, err := Hedge(context.Background(), cfg, func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		return "ok", nil
+	})
+	if err != nil || result != "ok" {
+		t.Fatalf("Hedge() = %q, %v", result, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+
+	snapshot := metrics.Snapshot()
+	if snapshot.TotalCalls != 1 || snapshot.HedgedCalls != 0 || snapshot.Wins[0] != 1 {
+		t.Errorf("Snapshot() = %+v", snapshot)
+	}
+}
+
+func TestHedge_SlowFirstAttempt(t *testing.T) {
+	metrics := NewHedgeMetrics()
+	cfg := &HedgeConfig{Name: "test", HedgeDelay: 20 * time.Millisecond, MaxHedges: 2, Metrics: metrics}
+
+	var attempts atomic.Int32
+	cancelled := make(chan struct{})
+	result, err := Hedge(context.Background(), cfg, func(ctx context.Context) (int, error) {
+		attempt := attempts.Add(1)
+		if attempt == 1 {
+			// The slow first attempt is cancelled once the hedge wins
+			<-ctx.Done()
+			close(cancelled)
+			return 0, ctx.Err()
+		}
+		return int(attempt), nil
+	})
+	if err != nil || result != 2 {
+		t.Fatalf("Hedge() = %d, %v, want the second attempt's result", result, err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("losing attempt was not cancelled")
+	}
+
+	snapshot := metrics.Snapshot()
+	if snapshot.HedgedCalls != 1 || snapshot.HedgesStarted != 1 || snapshot.Wins[1] != 1 {
+		t.Errorf("Snapshot() = %+v", snapshot)
+	}
+}
+
+func TestHedge_MaxHedges(t *testing.T) {
+	cfg := &HedgeConfig{Name: "test", HedgeDelay: 5 * time.Millisecond, MaxHedges: 2}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	_, err := Hedge(context.Background(), cfg, func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("Hedge() error = %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+}
+
+func TestHedge_AllFail(t *testing.T) {
+	metrics := NewHedgeMetrics()
+	cfg := &HedgeConfig{Name: "test", HedgeDelay: 10 * time.Millisecond, MaxHedges: 1, Metrics: metrics}
+	testErr := errors.New("read failed")
+
+	var attempts atomic.Int32
+	_, err := Hedge(context.Background(), cfg, func(ctx context.Context) (int, error) {
+		if attempts.Add(1) == 1 {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return 0, testErr
+	})
+	if !errors.Is(err, testErr) {
+		t.Fatalf("Hedge() error = %v, want %v", err, testErr)
+	}
+
+	snapshot := metrics.Snapshot()
+	if snapshot.TotalCalls != 1 || len(snapshot.Wins) != 0 {
+		t.Errorf("Snapshot() = %+v", snapshot)
+	}
+}
+
+func TestHedge_ContextCancelled(t *testing.T) {
+	cfg := &HedgeConfig{Name: "test", HedgeDelay: 10 * time.Millisecond, MaxHedges: 1}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, err := Hedge(ctx, cfg, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Hedge() error = %v, want context.DeadlineExceeded", err)
+	}
+}
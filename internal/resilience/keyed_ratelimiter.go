@@ -0,0 +1,155 @@
+package resilience
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// KeyedRateLimiterConfig holds keyed rate limiter configuration. The embedded
+// RateLimiterConfig applies to each key's bucket.
+type KeyedRateLimiterConfig struct {
+	RateLimiterConfig `mapstructure:",squash"`
+	MaxKeys           int           `mapstructure:"max_keys"` // max tracked keys; least recently used are evicted
+	IdleTTL           time.Duration `mapstructure:"idle_ttl"` // evict keys unused for this long (0 = never)
+}
+
+// DefaultKeyedRateLimiterConfig returns default configuration
+func DefaultKeyedRateLimiterConfig(name string) *KeyedRateLimiterConfig {
+	return &KeyedRateLimiterConfig{
+		RateLimiterConfig: *DefaultRateLimiterConfig(name),
+		MaxKeys:           10000,
+		IdleTTL:           10 * time.Minute,
+	}
+}
+
+// KeyedRateLimiter keeps an independent token bucket per key, such as a user ID
+// or API key. Buckets are created on first use and evicted once idle for
+// IdleTTL or, beyond MaxKeys, least recently used first, bounding memory under
+// a flood of unique keys. An evicted key starts again with a full bucket.
+type KeyedRateLimiter struct {
+	config    *KeyedRateLimiterConfig
+	entries   map[string]*list.Element
+	lru       *list.List // front = most recently used
+	evictions int64
+	retired   RateLimiterMetricsSnapshot // metrics of evicted buckets
+	mutex     sync.Mutex
+}
+
+type keyedLimiterEntry struct {
+	key      string
+	limiter  *TokenBucketLimiter
+	lastUsed time.Time
+}
+
+// KeyedRateLimiterMetricsSnapshot is a read-only snapshot of keyed rate limiter metrics
+type KeyedRateLimiterMetricsSnapshot struct {
+	RateLimiterMetricsSnapshot
+	TrackedKeys int64
+	Evictions   int64
+}
+
+// NewKeyedRateLimiter creates a new keyed rate limiter
+func NewKeyedRateLimiter(config *KeyedRateLimiterConfig) *KeyedRateLimiter {
+	return &KeyedRateLimiter{
+		config:  config,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Allow checks if a request for key is allowed
+func (l *KeyedRateLimiter) Allow(key string) bool {
+	return l.limiter(key).Allow()
+}
+
+// AllowN checks if N requests for key are allowed
+func (l *KeyedRateLimiter) AllowN(key string, n int) bool {
+	return l.limiter(key).AllowN(n)
+}
+
+// Wait waits until a request for key is allowed or context is done
+func (l *KeyedRateLimiter) Wait(ctx context.Context, key string) error {
+	return l.limiter(key).Wait(ctx)
+}
+
+// ForKey returns the rate limiter for key
+func (l *KeyedRateLimiter) ForKey(key string) RateLimiter {
+	return l.limiter(key)
+}
+
+// limiter returns the bucket for key, creating it and evicting others as needed
+func (l *KeyedRateLimiter) limiter(key string) *TokenBucketLimiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.evictIdle(now)
+
+	if elem, ok := l.entries[key]; ok {
+		entry := elem.Value.(*keyedLimiterEntry)
+		entry.lastUsed = now
+		l.lru.MoveToFront(elem)
+		return entry.limiter
+	}
+
+	for l.config.MaxKeys > 0 && l.lru.Len() >= l.config.MaxKeys {
+		l.evict(l.lru.Back())
+	}
+
+	entry := &keyedLimiterEntry{
+		key:      key,
+		limiter:  NewTokenBucketLimiter(&l.config.RateLimiterConfig),
+		lastUsed: now,
+	}
+	l.entries[key] = l.lru.PushFront(entry)
+	return entry.limiter
+}
+
+// evictIdle removes buckets unused for IdleTTL (must be called with mutex held)
+func (l *KeyedRateLimiter) evictIdle(now time.Time) {
+	if l.config.IdleTTL <= 0 {
+		return
+	}
+	for elem := l.lru.Back(); elem != nil; elem = l.lru.Back() {
+		if now.Sub(elem.Value.(*keyedLimiterEntry).lastUsed) < l.config.IdleTTL {
+			return
+		}
+		l.evict(elem)
+	}
+}
+
+// evict removes a bucket, keeping its metrics (must be called with mutex held)
+func (l *KeyedRateLimiter) evict(elem *list.Element) {
+	entry := l.lru.Remove(elem).(*keyedLimiterEntry)
+	delete(l.entries, entry.key)
+	l.evictions++
+	l.retired = addRateLimiterMetrics(l.retired, entry.limiter.Metrics())
+}
+
+// Metrics returns a snapshot of the current metrics, aggregated over all keys
+// including evicted ones
+func (l *KeyedRateLimiter) Metrics() KeyedRateLimiterMetricsSnapshot {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	totals := l.retired
+	for elem := l.lru.Front(); elem != nil; elem = elem.Next() {
+		totals = addRateLimiterMetrics(totals, elem.Value.(*keyedLimiterEntry).limiter.Metrics())
+	}
+	return KeyedRateLimiterMetricsSnapshot{
+		RateLimiterMetricsSnapshot: totals,
+		TrackedKeys:                int64(l.lru.Len()),
+		Evictions:                  l.evictions,
+	}
+}
+
+func addRateLimiterMetrics(a, b RateLimiterMetricsSnapshot) RateLimiterMetricsSnapshot {
+	return RateLimiterMetricsSnapshot{
+		TotalRequests:    a.TotalRequests + b.TotalRequests,
+		AllowedRequests:  a.AllowedRequests + b.AllowedRequests,
+		RejectedRequests: a.RejectedRequests + b.RejectedRequests,
+		WaitedRequests:   a.WaitedRequests + b.WaitedRequests,
+	}
+}
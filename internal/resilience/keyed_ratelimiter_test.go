@@ -0,0 +1,133 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestKeyedConfig(maxKeys int, idleTTL time.Duration) *KeyedRateLimiterConfig {
+	return &KeyedRateLimiterConfig{
+		RateLimiterConfig: RateLimiterConfig{
+			Name:        "keyed",
+			Rate:        1,
+			Period:      time.Hour,
+			BurstSize:   2,
+			WaitTimeout: time.Millisecond,
+		},
+		MaxKeys: maxKeys,
+		IdleTTL: idleTTL,
+	}
+}
+
+func TestDefaultKeyedRateLimiterConfig(t *testing.T) {
+	cfg := DefaultKeyedRateLimiterConfig("users")
+	if cfg.Name != "users" || cfg.Rate != 100 {
+		t.Errorf("bucket config = %+v, want rate limiter defaults", cfg.RateLimiterConfig)
+	}
+	if cfg.MaxKeys != 10000 {
+		t.Errorf("MaxKeys = %v, want 10000", cfg.MaxKeys)
+	}
+	if cfg.IdleTTL != 10*time.Minute {
+		t.Errorf("IdleTTL = %v, want 10m", cfg.IdleTTL)
+	}
+}
+
+func TestKeyedRateLimiter_IndependentKeys(t *testing.T) {
+	limiter := NewKeyedRateLimiter(newTestKeyedConfig(10, 0))
+
+	for i := 0; i < 2; i++ {
+		if !limiter.Allow("alice") {
+			t.Fatalf("alice request %d should be allowed", i+1)
+		}
+	}
+	if limiter.Allow("alice") {
+		t.Error("alice should be rate limited after the burst")
+	}
+
+	// bob has a separate bucket
+	if !limiter.Allow("bob") {
+		t.Error("bob should not be limited by alice's usage")
+	}
+	if err := limiter.Wait(context.Background(), "alice"); err != ErrRateLimitExceeded {
+		t.Errorf("Wait() error = %v, want ErrRateLimitExceeded", err)
+	}
+
+	m := limiter.Metrics()
+	if m.TrackedKeys != 2 || m.TotalRequests != 5 || m.AllowedRequests != 3 || m.RejectedRequests != 2 {
+		t.Errorf("metrics = %+v", m)
+	}
+}
+
+func TestKeyedRateLimiter_ForKey(t *testing.T) {
+	limiter := NewKeyedRateLimiter(newTestKeyedConfig(10, 0))
+
+	alice := limiter.ForKey("alice")
+	alice.AllowN(2)
+
+	if limiter.Allow("alice") {
+		t.Error("ForKey() should return the bucket used by Allow()")
+	}
+	if status := limiter.ForKey("bob").Status(); status.Remaining != 2 {
+		t.Errorf("bob Remaining = %v, want 2", status.Remaining)
+	}
+}
+
+func TestKeyedRateLimiter_EvictsLeastRecentlyUsed(t *testing.T) {
+	limiter := NewKeyedRateLimiter(newTestKeyedConfig(2, 0))
+
+	limiter.Allow("a")
+	limiter.Allow("b")
+	limiter.Allow("a") // a is now most recently used
+	limiter.Allow("c") // evicts b
+
+	m := limiter.Metrics()
+	if m.TrackedKeys != 2 || m.Evictions != 1 {
+		t.Errorf("TrackedKeys = %d, Evictions = %d, want 2 and 1", m.TrackedKeys, m.Evictions)
+	}
+	// Evicted buckets still count towards the totals
+	if m.TotalRequests != 4 {
+		t.Errorf("TotalRequests = %d, want 4", m.TotalRequests)
+	}
+
+	// a kept its bucket and is now exhausted; b starts over with a full bucket
+	if limiter.Allow("a") {
+		t.Error("a should still be rate limited")
+	}
+	if !limiter.Allow("b") {
+		t.Error("b should have been evicted and start with a full bucket")
+	}
+}
+
+func TestKeyedRateLimiter_BoundedUnderKeyFlood(t *testing.T) {
+	limiter := NewKeyedRateLimiter(newTestKeyedConfig(100, 0))
+
+	for i := 0; i < 10000; i++ {
+		limiter.Allow(fmt.Sprintf("key-%d", i))
+	}
+
+	m := limiter.Metrics()
+	if m.TrackedKeys != 100 {
+		t.Errorf("TrackedKeys = %d, want 100", m.TrackedKeys)
+	}
+	if m.Evictions != 9900 {
+		t.Errorf("Evictions = %d, want 9900", m.Evictions)
+	}
+}
+
+func TestKeyedRateLimiter_EvictsIdleKeys(t *testing.T) {
+	limiter := NewKeyedRateLimiter(newTestKeyedConfig(10, 20*time.Millisecond))
+
+	limiter.Allow("idle")
+	limiter.Allow("idle")
+	time.Sleep(30 * time.Millisecond)
+
+	// The idle bucket is dropped on the next access
+	if !limiter.Allow("idle") {
+		t.Error("idle key should have been evicted and start with a full bucket")
+	}
+	if m := limiter.Metrics(); m.Evictions != 1 || m.TrackedKeys != 1 {
+		t.Errorf("Evictions = %d, TrackedKeys = %d, want 1 and 1", m.Evictions, m.TrackedKeys)
+	}
+}
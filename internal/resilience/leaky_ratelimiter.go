@@ -0,0 +1,172 @@
+package resilience
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// LeakyBucketLimiter implements leaky bucket rate limiting. Requests join a
+// bucket holding up to BurstSize of them, the one leaving included, that
+// drains at a fixed Rate per Period, so requests leave evenly spaced however
+// they arrive. A request that would overflow the bucket is rejected; the
+// bucket size, not WaitTimeout, bounds how long a request waits.
+//
+// Choose it over TokenBucketLimiter to smooth egress to a downstream that
+// cannot absorb bursts, e.g. an SMTP relay or a third-party API. A token bucket
+// lets a full burst through at once, then limits the average rate; a leaky
+// bucket never lets requests out faster than the rate, at the cost of
+// latency while they queue.
+//
+// Allow and AllowN only admit requests that can leave immediately, which is
+// when the queue is empty. Wait and WaitN queue requests and block until
+// their turn.
+type LeakyBucketLimiter struct {
+	config   *RateLimiterConfig
+	capacity float64
+	interval time.Duration // between two requests leaving
+	next     time.Time     // when the next request may leave
+	mutex    sync.Mutex
+	metrics  *RateLimiterMetrics
+}
+
+// NewLeakyBucketLimiter creates a new leaky bucket rate limiter
+func NewLeakyBucketLimiter(config *RateLimiterConfig) *LeakyBucketLimiter {
+	return &LeakyBucketLimiter{
+		config:   config,
+		capacity: float64(config.BurstSize),
+		interval: config.Period / time.Duration(config.Rate),
+		next:     time.Now(),
+		metrics:  &RateLimiterMetrics{},
+	}
+}
+
+// Allow checks if a request may leave now
+func (l *LeakyBucketLimiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN checks if N requests may leave now
+func (l *LeakyBucketLimiter) AllowN(n int) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.metrics.mutex.Lock()
+	defer l.metrics.mutex.Unlock()
+	l.metrics.TotalRequests++
+
+	now := time.Now()
+	if l.next.After(now) || float64(n) > l.capacity {
+		l.metrics.RejectedRequests++
+		return false
+	}
+
+	l.next = now.Add(time.Duration(n) * l.interval)
+	l.metrics.AllowedRequests++
+	return true
+}
+
+// Wait queues a request and waits until it may leave. It returns
+// ErrRateLimitExceeded without waiting when the queue is full.
+func (l *LeakyBucketLimiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN queues N requests and waits until they may leave
+func (l *LeakyBucketLimiter) WaitN(ctx context.Context, n int) error {
+	l.mutex.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	if l.queued(start, now)+float64(n) > l.capacity {
+		l.mutex.Unlock()
+		l.record(false, false)
+		return ErrRateLimitExceeded
+	}
+	end := start.Add(time.Duration(n) * l.interval)
+	l.next = end
+	l.mutex.Unlock()
+
+	waitTime := start.Sub(now)
+	if waitTime <= 0 {
+		l.record(true, false)
+		return nil
+	}
+
+	timer := time.NewTimer(waitTime)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		// Give the slot back unless later requests queued behind it
+		l.mutex.Lock()
+		if l.next.Equal(end) {
+			l.next = start
+		}
+		l.mutex.Unlock()
+		l.record(false, true)
+		return ctx.Err()
+	case <-timer.C:
+		l.record(true, true)
+		return nil
+	}
+}
+
+// Status returns the current state of the queue
+func (l *LeakyBucketLimiter) Status() RateLimitStatus {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	queued := l.queued(l.next, now)
+	status := RateLimitStatus{
+		Limit:     int(l.capacity),
+		Remaining: int(math.Max(0, math.Floor(l.capacity-queued))),
+	}
+	if l.next.After(now) {
+		status.Reset = l.next.Sub(now)
+	}
+	if status.Remaining == 0 {
+		status.RetryAfter = time.Duration((queued - l.capacity + 1) * float64(l.interval))
+	}
+	return status
+}
+
+// queued returns how many requests are waiting at now when the next one
+// leaves at next (must be called with mutex held)
+func (l *LeakyBucketLimiter) queued(next, now time.Time) float64 {
+	if !next.After(now) {
+		return 0
+	}
+	return float64(next.Sub(now)) / float64(l.interval)
+}
+
+func (l *LeakyBucketLimiter) record(allowed, waited bool) {
+	l.metrics.mutex.Lock()
+	defer l.metrics.mutex.Unlock()
+
+	l.metrics.TotalRequests++
+	if allowed {
+		l.metrics.AllowedRequests++
+	} else {
+		l.metrics.RejectedRequests++
+	}
+	if waited {
+		l.metrics.WaitedRequests++
+	}
+}
+
+// Metrics returns a snapshot of the current metrics
+func (l *LeakyBucketLimiter) Metrics() RateLimiterMetricsSnapshot {
+	l.metrics.mutex.RLock()
+	defer l.metrics.mutex.RUnlock()
+	return RateLimiterMetricsSnapshot{
+		TotalRequests:    l.metrics.TotalRequests,
+		AllowedRequests:  l.metrics.AllowedRequests,
+		RejectedRequests: l.metrics.RejectedRequests,
+		WaitedRequests:   l.metrics.WaitedRequests,
+	}
+}
@@ -0,0 +1,113 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestLeakyBucket(rate, size int) *LeakyBucketLimiter {
+	return NewLeakyBucketLimiter(&RateLimiterConfig{
+		Name:      "test",
+		Rate:      rate,
+		Period:    time.Second,
+		BurstSize: size,
+	})
+}
+
+func TestLeakyBucketLimiter_Allow(t *testing.T) {
+	limiter := newTestLeakyBucket(10, 5)
+
+	// Unlike a token bucket, a burst is not let through at once
+	if !limiter.Allow() {
+		t.Fatal("First request should be allowed")
+	}
+	if limiter.Allow() {
+		t.Error("Second request should wait for the bucket to drain")
+	}
+
+	time.Sleep(110 * time.Millisecond)
+	if !limiter.Allow() {
+		t.Error("Request should be allowed after one drain interval")
+	}
+	if limiter.AllowN(6) {
+		t.Error("AllowN() larger than the bucket should be rejected")
+	}
+}
+
+func TestLeakyBucketLimiter_Wait_Paced(t *testing.T) {
+	limiter := newTestLeakyBucket(20, 5) // one request every 50ms
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	// The first leaves at once, the next three 50ms apart
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("4 requests left after %v, want them spaced 50ms apart", elapsed)
+	}
+}
+
+func TestLeakyBucketLimiter_Wait_Overflow(t *testing.T) {
+	limiter := newTestLeakyBucket(10, 2)
+
+	done := make(chan error, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	go func() { done <- limiter.Wait(context.Background()) }()
+
+	// The bucket holds the request leaving and the one queued
+	time.Sleep(10 * time.Millisecond)
+	if err := limiter.Wait(context.Background()); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("Wait() error = %v, want ErrRateLimitExceeded", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("queued Wait() error = %v", err)
+	}
+
+	metrics := limiter.Metrics()
+	if metrics.TotalRequests != 3 || metrics.AllowedRequests != 2 || metrics.RejectedRequests != 1 || metrics.WaitedRequests != 1 {
+		t.Errorf("Metrics() = %+v", metrics)
+	}
+}
+
+func TestLeakyBucketLimiter_Wait_ContextCancelled(t *testing.T) {
+	limiter := newTestLeakyBucket(1, 3)
+	limiter.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// The cancelled request gave its place back
+	if status := limiter.Status(); status.Remaining != 2 {
+		t.Errorf("Remaining = %d, want 2", status.Remaining)
+	}
+}
+
+func TestLeakyBucketLimiter_Status(t *testing.T) {
+	limiter := newTestLeakyBucket(10, 2)
+
+	status := limiter.Status()
+	if status.Limit != 2 || status.Remaining != 2 || status.Reset != 0 || status.RetryAfter != 0 {
+		t.Errorf("empty Status() = %+v", status)
+	}
+
+	limiter.AllowN(2)
+	status = limiter.Status()
+	if status.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", status.Remaining)
+	}
+	if status.RetryAfter <= 0 || status.RetryAfter > 100*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want up to one drain interval", status.RetryAfter)
+	}
+	if status.Reset <= 100*time.Millisecond || status.Reset > 200*time.Millisecond {
+		t.Errorf("Reset = %v, want up to two drain intervals", status.Reset)
+	}
+}
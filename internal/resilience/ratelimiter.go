@@ -3,12 +3,56 @@ package resilience
 import (
 	"context"
 	"errors"
+	"math"
 	"sync"
 	"time"
 )
 
 var ErrRateLimitExceeded = errors.New("rate limit exceeded")
 
+// RateLimiter is implemented by token and leaky bucket limiters, local or distributed
+type RateLimiter interface {
+	Allow() bool
+	AllowN(n int) bool
+	Wait(ctx context.Context) error
+	WaitN(ctx context.Context, n int) error
+	Status() RateLimitStatus
+	Metrics() RateLimiterMetricsSnapshot
+}
+
+// KeyedLimiter provides the rate limiter for a key such as a user ID or client IP
+type KeyedLimiter interface {
+	ForKey(key string) RateLimiter
+}
+
+var (
+	_ RateLimiter  = (*TokenBucketLimiter)(nil)
+	_ RateLimiter  = (*RedisTokenBucketLimiter)(nil)
+	_ RateLimiter  = (*LeakyBucketLimiter)(nil)
+	_ KeyedLimiter = (*KeyedRateLimiter)(nil)
+)
+
+// RateLimitStatus describes the current state of a token or leaky bucket
+type RateLimitStatus struct {
+	Limit      int           // bucket capacity
+	Remaining  int           // whole tokens currently available
+	RetryAfter time.Duration // until the next token is available; 0 if Remaining > 0
+	Reset      time.Duration // until the bucket is full again
+}
+
+// SharedLimiter returns a KeyedLimiter that applies one limiter to every key
+func SharedLimiter(limiter RateLimiter) KeyedLimiter {
+	return sharedLimiter{limiter}
+}
+
+type sharedLimiter struct {
+	limiter RateLimiter
+}
+
+func (s sharedLimiter) ForKey(string) RateLimiter {
+	return s.limiter
+}
+
 // RateLimiterConfig holds rate limiter configuration
 type RateLimiterConfig struct {
 	Name            string        `mapstructure:"name"`
@@ -17,6 +61,7 @@ type RateLimiterConfig struct {
 	BurstSize       int           `mapstructure:"burst_size"`        // max burst
 	WaitTimeout     time.Duration `mapstructure:"wait_timeout"`      // max wait time
 	FairnessEnabled bool          `mapstructure:"fairness_enabled"`
+	FailOpen        bool          `mapstructure:"fail_open"`         // allow requests when a distributed limiter's backend is unreachable
 }
 
 // DefaultRateLimiterConfig returns default configuration
@@ -28,6 +73,7 @@ func DefaultRateLimiterConfig(name string) *RateLimiterConfig {
 		BurstSize:       10,
 		WaitTimeout:     time.Second,
 		FairnessEnabled: true,
+		FailOpen:        true,
 	}
 }
 
@@ -158,6 +204,23 @@ func (l *TokenBucketLimiter) WaitN(ctx context.Context, n int) error {
 	}
 }
 
+// Status returns the current state of the bucket
+func (l *TokenBucketLimiter) Status() RateLimitStatus {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.refill()
+	status := RateLimitStatus{
+		Limit:     int(l.maxTokens),
+		Remaining: int(math.Max(0, math.Floor(l.tokens))),
+		Reset:     time.Duration(math.Max(0, l.maxTokens-l.tokens) / l.refillRate),
+	}
+	if status.Remaining == 0 {
+		status.RetryAfter = time.Duration((1 - l.tokens) / l.refillRate)
+	}
+	return status
+}
+
 // refill adds tokens based on elapsed time (must be called with mutex held)
 func (l *TokenBucketLimiter) refill() {
 	now := time.Now()
@@ -181,6 +181,44 @@ func TestTokenBucketLimiter_Refill(t *testing.T) {
 	}
 }
 
+func TestTokenBucketLimiter_Status(t *testing.T) {
+	cfg := &RateLimiterConfig{
+		Name:      "test",
+		Rate:      1,
+		Period:    time.Minute,
+		BurstSize: 2,
+	}
+	limiter := NewTokenBucketLimiter(cfg)
+
+	status := limiter.Status()
+	if status.Limit != 2 || status.Remaining != 2 || status.RetryAfter != 0 || status.Reset != 0 {
+		t.Errorf("fresh bucket status = %+v, want full bucket", status)
+	}
+
+	limiter.Allow()
+	limiter.Allow()
+
+	status = limiter.Status()
+	if status.Remaining != 0 {
+		t.Errorf("Remaining = %v, want 0", status.Remaining)
+	}
+	if status.RetryAfter <= 59*time.Second || status.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter = %v, want ~1m", status.RetryAfter)
+	}
+	if status.Reset <= 119*time.Second || status.Reset > 2*time.Minute {
+		t.Errorf("Reset = %v, want ~2m", status.Reset)
+	}
+}
+
+func TestSharedLimiter(t *testing.T) {
+	limiter := NewTokenBucketLimiter(DefaultRateLimiterConfig("test"))
+	shared := SharedLimiter(limiter)
+
+	if shared.ForKey("a") != limiter || shared.ForKey("b") != limiter {
+		t.Error("ForKey() should return the shared limiter for every key")
+	}
+}
+
 // SlidingWindowLimiter tests
 func TestSlidingWindowLimiter_Allow(t *testing.T) {
 	cfg := &RateLimiterConfig{
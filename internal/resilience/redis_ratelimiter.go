@@ -0,0 +1,214 @@
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefixRateLimit = "arcana:ratelimit:"
+
+// tokenBucketScript refills and consumes tokens atomically. The bucket is a hash
+// of the token count and the time of the last refill in microseconds; the Redis
+// server clock is used so replicas with skewed clocks share one consistent bucket.
+// Returns {allowed, microseconds until the requested tokens are available,
+// remaining whole tokens, microseconds until the bucket is full}.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local requested = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= requested then
+	tokens = tokens - requested
+	allowed = 1
+else
+	wait = math.ceil((requested - tokens) / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], ttl)
+return {allowed, wait, math.floor(tokens), math.ceil((capacity - tokens) / rate)}
+`)
+
+// RedisTokenBucketLimiter implements token bucket rate limiting shared by all
+// replicas through Redis. When Redis is unreachable requests are allowed or
+// rejected according to RateLimiterConfig.FailOpen.
+type RedisTokenBucketLimiter struct {
+	config     *RateLimiterConfig
+	client     *redis.Client
+	key        string
+	refillRate float64 // tokens per microsecond
+	ttl        time.Duration
+	metrics    *RateLimiterMetrics
+}
+
+// NewRedisTokenBucketLimiter creates a token bucket rate limiter stored in Redis
+// under the config name; limiters with the same name share one bucket
+func NewRedisTokenBucketLimiter(client *redis.Client, config *RateLimiterConfig) *RedisTokenBucketLimiter {
+	refillRate := float64(config.Rate) / float64(config.Period.Microseconds())
+
+	// Keep idle buckets until they would have refilled completely
+	ttl := time.Duration(float64(config.BurstSize)/refillRate) * time.Microsecond
+	if ttl < config.Period {
+		ttl = config.Period
+	}
+
+	return &RedisTokenBucketLimiter{
+		config:     config,
+		client:     client,
+		key:        keyPrefixRateLimit + config.Name,
+		refillRate: refillRate,
+		ttl:        ttl + time.Second,
+		metrics:    &RateLimiterMetrics{},
+	}
+}
+
+// Allow checks if a request is allowed
+func (l *RedisTokenBucketLimiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN checks if N requests are allowed
+func (l *RedisTokenBucketLimiter) AllowN(n int) bool {
+	l.metrics.mutex.Lock()
+	l.metrics.TotalRequests++
+	l.metrics.mutex.Unlock()
+
+	bucket, err := l.take(context.Background(), n)
+	allowed := bucket.allowed
+	if err != nil {
+		allowed = l.config.FailOpen
+	}
+
+	l.record(allowed)
+	return allowed
+}
+
+// Wait waits until a request is allowed or context is done
+func (l *RedisTokenBucketLimiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN waits until N requests are allowed. Unlike the in-memory limiter it
+// cannot reserve tokens ahead of time, so it retries once enough tokens should
+// have been refilled, giving up when that would exceed WaitTimeout.
+func (l *RedisTokenBucketLimiter) WaitN(ctx context.Context, n int) error {
+	l.metrics.mutex.Lock()
+	l.metrics.TotalRequests++
+	l.metrics.mutex.Unlock()
+
+	deadline := time.Now().Add(l.config.WaitTimeout)
+	waited := false
+	for {
+		bucket, err := l.take(ctx, n)
+		allowed, wait := bucket.allowed, bucket.wait
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			allowed = l.config.FailOpen
+			wait = 0
+		}
+		if allowed {
+			l.record(true)
+			return nil
+		}
+		if wait == 0 || time.Now().Add(wait).After(deadline) {
+			l.record(false)
+			return ErrRateLimitExceeded
+		}
+
+		if !waited {
+			waited = true
+			l.metrics.mutex.Lock()
+			l.metrics.WaitedRequests++
+			l.metrics.mutex.Unlock()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Status returns the current state of the shared bucket. If Redis is
+// unreachable only the limit is reported.
+func (l *RedisTokenBucketLimiter) Status() RateLimitStatus {
+	status := RateLimitStatus{Limit: l.config.BurstSize}
+	bucket, err := l.take(context.Background(), 0)
+	if err != nil {
+		return status
+	}
+	status.Remaining = bucket.remaining
+	status.Reset = bucket.reset
+	if status.Remaining == 0 {
+		// The bucket is full after reset; one token is available this much sooner
+		status.RetryAfter = bucket.reset - time.Duration(float64(l.config.BurstSize-1)/l.refillRate)*time.Microsecond
+	}
+	return status
+}
+
+// redisBucket is the outcome of running the token bucket script
+type redisBucket struct {
+	allowed   bool
+	wait      time.Duration // until the requested tokens are available
+	remaining int
+	reset     time.Duration // until the bucket is full
+}
+
+// take runs the token bucket script, consuming n tokens if available
+func (l *RedisTokenBucketLimiter) take(ctx context.Context, n int) (redisBucket, error) {
+	result, err := tokenBucketScript.Run(ctx, l.client, []string{l.key},
+		l.config.BurstSize, l.refillRate, n, l.ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return redisBucket{}, err
+	}
+	return redisBucket{
+		allowed:   result[0] == 1,
+		wait:      time.Duration(result[1]) * time.Microsecond,
+		remaining: int(result[2]),
+		reset:     time.Duration(result[3]) * time.Microsecond,
+	}, nil
+}
+
+func (l *RedisTokenBucketLimiter) record(allowed bool) {
+	l.metrics.mutex.Lock()
+	defer l.metrics.mutex.Unlock()
+	if allowed {
+		l.metrics.AllowedRequests++
+	} else {
+		l.metrics.RejectedRequests++
+	}
+}
+
+// Metrics returns a snapshot of the current metrics
+func (l *RedisTokenBucketLimiter) Metrics() RateLimiterMetricsSnapshot {
+	l.metrics.mutex.RLock()
+	defer l.metrics.mutex.RUnlock()
+	return RateLimiterMetricsSnapshot{
+		TotalRequests:    l.metrics.TotalRequests,
+		AllowedRequests:  l.metrics.AllowedRequests,
+		RejectedRequests: l.metrics.RejectedRequests,
+		WaitedRequests:   l.metrics.WaitedRequests,
+	}
+}
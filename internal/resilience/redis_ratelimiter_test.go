@@ -0,0 +1,145 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
+)
+
+func newUnreachableRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func setupRedisLimiterTest(t *testing.T) *redis.Client {
+	testutil.SkipIfNoRedis(t)
+	return testutil.NewTestRedisClient(t, testutil.DefaultTestConfig())
+}
+
+func TestRedisTokenBucketLimiter_FailOpen(t *testing.T) {
+	cfg := DefaultRateLimiterConfig("fail-open")
+	limiter := NewRedisTokenBucketLimiter(newUnreachableRedisClient(t), cfg)
+
+	if !limiter.Allow() {
+		t.Error("Allow() should succeed when Redis is unreachable and FailOpen is set")
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() error = %v, want nil", err)
+	}
+
+	m := limiter.Metrics()
+	if m.TotalRequests != 2 || m.AllowedRequests != 2 {
+		t.Errorf("metrics = %+v, want 2 total and 2 allowed", m)
+	}
+}
+
+func TestRedisTokenBucketLimiter_FailClosed(t *testing.T) {
+	cfg := DefaultRateLimiterConfig("fail-closed")
+	cfg.FailOpen = false
+	limiter := NewRedisTokenBucketLimiter(newUnreachableRedisClient(t), cfg)
+
+	if limiter.Allow() {
+		t.Error("Allow() should fail when Redis is unreachable and FailOpen is unset")
+	}
+	if err := limiter.Wait(context.Background()); err != ErrRateLimitExceeded {
+		t.Errorf("Wait() error = %v, want ErrRateLimitExceeded", err)
+	}
+
+	m := limiter.Metrics()
+	if m.TotalRequests != 2 || m.RejectedRequests != 2 {
+		t.Errorf("metrics = %+v, want 2 total and 2 rejected", m)
+	}
+}
+
+func TestRedisTokenBucketLimiter_SharedBucket(t *testing.T) {
+	client := setupRedisLimiterTest(t)
+	cfg := &RateLimiterConfig{
+		Name:        "shared-" + testutil.GenerateTestID(),
+		Rate:        1,
+		Period:      time.Hour,
+		BurstSize:   3,
+		WaitTimeout: time.Millisecond,
+	}
+
+	// Two replicas draw from the same bucket
+	a := NewRedisTokenBucketLimiter(client, cfg)
+	b := NewRedisTokenBucketLimiter(client, cfg)
+
+	allowed := 0
+	for i := 0; i < 3; i++ {
+		if a.Allow() {
+			allowed++
+		}
+		if b.Allow() {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("allowed = %d, want burst of 3 across both limiters", allowed)
+	}
+	if a.AllowN(1) || b.Wait(context.Background()) != ErrRateLimitExceeded {
+		t.Error("bucket should be exhausted")
+	}
+}
+
+func TestRedisTokenBucketLimiter_Status(t *testing.T) {
+	client := setupRedisLimiterTest(t)
+	limiter := NewRedisTokenBucketLimiter(client, &RateLimiterConfig{
+		Name:        "status-" + testutil.GenerateTestID(),
+		Rate:        1,
+		Period:      time.Minute,
+		BurstSize:   2,
+		WaitTimeout: time.Millisecond,
+	})
+
+	if status := limiter.Status(); status.Limit != 2 || status.Remaining != 2 {
+		t.Errorf("fresh bucket status = %+v, want 2 remaining", status)
+	}
+
+	limiter.AllowN(2)
+
+	status := limiter.Status()
+	if status.Remaining != 0 {
+		t.Errorf("Remaining = %v, want 0", status.Remaining)
+	}
+	if status.RetryAfter <= 59*time.Second || status.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter = %v, want ~1m", status.RetryAfter)
+	}
+}
+
+func TestRedisTokenBucketLimiter_Wait(t *testing.T) {
+	client := setupRedisLimiterTest(t)
+	cfg := &RateLimiterConfig{
+		Name:        "wait-" + testutil.GenerateTestID(),
+		Rate:        20,
+		Period:      time.Second,
+		BurstSize:   1,
+		WaitTimeout: time.Second,
+	}
+	limiter := NewRedisTokenBucketLimiter(client, cfg)
+
+	if !limiter.Allow() {
+		t.Fatal("first request should be allowed")
+	}
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Wait() returned after %v, want ~50ms for a refill", elapsed)
+	}
+	if m := limiter.Metrics(); m.WaitedRequests != 1 {
+		t.Errorf("WaitedRequests = %d, want 1", m.WaitedRequests)
+	}
+}
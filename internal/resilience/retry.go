@@ -15,6 +15,9 @@ type RetryConfig struct {
 	Multiplier        float64       `mapstructure:"multiplier"`
 	RandomizationFactor float64     `mapstructure:"randomization_factor"`
 	RetryableErrors   []error       `mapstructure:"-"`
+	// Budget, when set, is consulted before each retry; once it is exhausted
+	// the last error is returned without retrying
+	Budget            *RetryBudget  `mapstructure:"-"`
 }
 
 // DefaultRetryConfig returns default retry configuration
@@ -41,6 +44,11 @@ func isRetryableError(err error, retryableErrors []error) bool {
 	return false
 }
 
+// allowRetry reports whether the retry budget, if any, allows another attempt
+func (c *RetryConfig) allowRetry() bool {
+	return c.Budget == nil || c.Budget.AllowRetry()
+}
+
 // nextBackoffInterval advances the exponential backoff interval and returns the sleep duration
 func nextBackoffInterval(current time.Duration, config *RetryConfig) (sleep, next time.Duration) {
 	sleep = calculateInterval(current, config)
@@ -73,6 +81,9 @@ func Retry(ctx context.Context, config *RetryConfig, fn func(context.Context) er
 		}
 
 		if attempt < config.MaxAttempts {
+			if !config.allowRetry() {
+				return lastErr
+			}
 			sleepDur, next := nextBackoffInterval(interval, config)
 			select {
 			case <-ctx.Done():
@@ -105,6 +116,9 @@ func RetryWithResult[T any](ctx context.Context, config *RetryConfig, fn func(co
 		}
 
 		if attempt < config.MaxAttempts {
+			if !config.allowRetry() {
+				return result, lastErr
+			}
 			nextInterval := calculateInterval(interval, config)
 
 			select {
@@ -138,7 +152,13 @@ func calculateInterval(base time.Duration, config *RetryConfig) time.Duration {
 
 // ExponentialBackoff calculates exponential backoff duration
 func ExponentialBackoff(attempt int, baseDelay time.Duration, maxDelay time.Duration) time.Duration {
-	delay := time.Duration(float64(baseDelay) * math.Pow(2, float64(attempt-1)))
+	return ExponentialBackoffWithMultiplier(attempt, baseDelay, 2, maxDelay)
+}
+
+// ExponentialBackoffWithMultiplier calculates exponential backoff duration using
+// a custom growth factor between attempts
+func ExponentialBackoffWithMultiplier(attempt int, baseDelay time.Duration, multiplier float64, maxDelay time.Duration) time.Duration {
+	delay := time.Duration(float64(baseDelay) * math.Pow(multiplier, float64(attempt-1)))
 	if delay > maxDelay {
 		delay = maxDelay
 	}
@@ -0,0 +1,59 @@
+package resilience
+
+import "time"
+
+// RetryBudgetConfig holds retry budget configuration
+type RetryBudgetConfig struct {
+	Name       string        `mapstructure:"name"`
+	MaxRetries int           `mapstructure:"max_retries"` // retries allowed per window
+	Window     time.Duration `mapstructure:"window"`
+}
+
+// DefaultRetryBudgetConfig returns default configuration
+func DefaultRetryBudgetConfig(name string) *RetryBudgetConfig {
+	return &RetryBudgetConfig{
+		Name:       name,
+		MaxRetries: 100,
+		Window:     10 * time.Second,
+	}
+}
+
+// RetryBudget caps the retries made by all the calls sharing it, so that
+// during an outage retries cannot multiply the load on the failing
+// downstream. It is a token bucket of MaxRetries retries per Window; first
+// attempts are never limited.
+type RetryBudget struct {
+	limiter *TokenBucketLimiter
+}
+
+// RetryBudgetMetricsSnapshot is a read-only snapshot of retry budget metrics
+type RetryBudgetMetricsSnapshot struct {
+	AllowedRetries int64
+	DeniedRetries  int64
+}
+
+// NewRetryBudget creates a new retry budget, initially full
+func NewRetryBudget(config *RetryBudgetConfig) *RetryBudget {
+	return &RetryBudget{
+		limiter: NewTokenBucketLimiter(&RateLimiterConfig{
+			Name:      config.Name,
+			Rate:      config.MaxRetries,
+			Period:    config.Window,
+			BurstSize: config.MaxRetries,
+		}),
+	}
+}
+
+// AllowRetry takes a retry from the budget, reporting false if none is left
+func (b *RetryBudget) AllowRetry() bool {
+	return b.limiter.Allow()
+}
+
+// Metrics returns a snapshot of the current metrics
+func (b *RetryBudget) Metrics() RetryBudgetMetricsSnapshot {
+	m := b.limiter.Metrics()
+	return RetryBudgetMetricsSnapshot{
+		AllowedRetries: m.AllowedRequests,
+		DeniedRetries:  m.RejectedRequests,
+	}
+}
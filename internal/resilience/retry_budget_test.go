@@ -0,0 +1,96 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDefaultRetryBudgetConfig(t *testing.T) {
+	cfg := DefaultRetryBudgetConfig("test")
+	if cfg.Name != "test" || cfg.MaxRetries != 100 || cfg.Window != 10*time.Second {
+		t.Errorf("DefaultRetryBudgetConfig() = %+v", cfg)
+	}
+}
+
+func TestRetryBudget_AllowRetry(t *testing.T) {
+	budget := NewRetryBudget(&RetryBudgetConfig{Name: "test", MaxRetries: 2, Window: time.Hour})
+
+	if !budget.AllowRetry() || !budget.AllowRetry() {
+		t.Fatal("Retries within the budget should be allowed")
+	}
+	if budget.AllowRetry() {
+		t.Error("Retry beyond the budget should be denied")
+	}
+
+	metrics := budget.Metrics()
+	if metrics.AllowedRetries != 2 || metrics.DeniedRetries != 1 {
+		t.Errorf("Metrics() = %+v, want 2 allowed and 1 denied", metrics)
+	}
+}
+
+func TestRetry_BudgetExhausted(t *testing.T) {
+	budget := NewRetryBudget(&RetryBudgetConfig{Name: "test", MaxRetries: 3, Window: time.Hour})
+	cfg := &RetryConfig{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		Multiplier:      1,
+		Budget:          budget,
+	}
+	testErr := errors.New("downstream unavailable")
+
+	// The first call spends two retries, the second only the one left
+	calls := 0
+	fn := func(ctx context.Context) error {
+		calls++
+		return testErr
+	}
+	if err := Retry(context.Background(), cfg, fn); !errors.Is(err, testErr) {
+		t.Fatalf("Retry() error = %v, want %v", err, testErr)
+	}
+	if err := Retry(context.Background(), cfg, fn); !errors.Is(err, testErr) {
+		t.Fatalf("Retry() error = %v, want %v", err, testErr)
+	}
+	if calls != 5 {
+		t.Errorf("calls = %d, want 5", calls)
+	}
+
+	// With the budget spent, calls are attempted once
+	calls = 0
+	if err := Retry(context.Background(), cfg, fn); !errors.Is(err, testErr) {
+		t.Fatalf("Retry() error = %v, want %v", err, testErr)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	metrics := budget.Metrics()
+	if metrics.AllowedRetries != 3 || metrics.DeniedRetries != 2 {
+		t.Errorf("Metrics() = %+v, want 3 allowed and 2 denied", metrics)
+	}
+}
+
+func TestRetryWithResult_BudgetExhausted(t *testing.T) {
+	cfg := &RetryConfig{
+		MaxAttempts:     5,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		Multiplier:      1,
+		Budget:          NewRetryBudget(&RetryBudgetConfig{Name: "test", MaxRetries: 1, Window: time.Hour}),
+	}
+	testErr := errors.New("downstream unavailable")
+
+	calls := 0
+	_, err := RetryWithResult(context.Background(), cfg, func(ctx context.Context) (int, error) {
+		calls++
+		return 0, testErr
+	})
+	if !errors.Is(err, testErr) {
+		t.Fatalf("RetryWithResult() error = %v, want %v", err, testErr)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
@@ -311,6 +311,19 @@ func TestExponentialBackoff(t *testing.T) {
 	}
 }
 
+func TestExponentialBackoffWithMultiplier(t *testing.T) {
+	// attempt 3 with multiplier 3: 100ms * 3^2 = 900ms, plus up to 25% jitter
+	delay := ExponentialBackoffWithMultiplier(3, 100*time.Millisecond, 3, 10*time.Second)
+	if delay < 900*time.Millisecond || delay > 1125*time.Millisecond {
+		t.Errorf("Backoff delay = %v, want between 900ms and 1125ms", delay)
+	}
+
+	delay = ExponentialBackoffWithMultiplier(10, time.Second, 3, 2*time.Second)
+	if delay > 2500*time.Millisecond {
+		t.Errorf("Backoff delay = %v, exceeds maxDelay with jitter", delay)
+	}
+}
+
 func TestCalculateInterval_NoRandomization(t *testing.T) {
 	cfg := &RetryConfig{
 		MaxAttempts:         3,
@@ -0,0 +1,127 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTimeWindowBuckets is the number of buckets a time sliding window is
+// split into when none is configured
+const DefaultTimeWindowBuckets = 10
+
+// TimeSlidingWindow tracks the call outcomes of the last window duration.
+// Outcomes are counted in a ring of buckets, each covering window/buckets,
+// and a bucket stops counting once it is older than the window. Unlike
+// SlidingWindow, which keeps the last N outcomes however old, the rates decay
+// as outcomes age out, even when no new calls arrive.
+//
+// Calls are classified as slow when recorded, against slowCallThreshold.
+type TimeSlidingWindow struct {
+	bucketWidth       time.Duration
+	slowCallThreshold time.Duration
+	buckets           []outcomeBucket
+	now               func() time.Time
+	mutex             sync.RWMutex
+}
+
+// outcomeBucket counts the outcomes of one sub-interval of the window
+type outcomeBucket struct {
+	epoch     int64 // index of the sub-interval since the Unix epoch
+	calls     int
+	failures  int
+	slowCalls int
+}
+
+// NewTimeSlidingWindow creates a sliding window over the last window
+// duration (a minute when not positive), split into the given number of buckets
+func NewTimeSlidingWindow(window time.Duration, buckets int, slowCallThreshold time.Duration) *TimeSlidingWindow {
+	if window <= 0 {
+		window = time.Minute
+	}
+	if buckets <= 0 {
+		buckets = DefaultTimeWindowBuckets
+	}
+	bucketWidth := window / time.Duration(buckets)
+	if bucketWidth <= 0 {
+		bucketWidth = 1
+	}
+	return &TimeSlidingWindow{
+		bucketWidth:       bucketWidth,
+		slowCallThreshold: slowCallThreshold,
+		buckets:           make([]outcomeBucket, buckets),
+		now:               time.Now,
+	}
+}
+
+// Record records an outcome
+func (tw *TimeSlidingWindow) Record(success bool, duration time.Duration) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	epoch := tw.epoch()
+	bucket := &tw.buckets[epoch%int64(len(tw.buckets))]
+	if bucket.epoch != epoch {
+		// The slot last held an expired sub-interval
+		*bucket = outcomeBucket{epoch: epoch}
+	}
+	bucket.calls++
+	if !success {
+		bucket.failures++
+	}
+	if duration > tw.slowCallThreshold {
+		bucket.slowCalls++
+	}
+}
+
+// Count returns the number of outcomes in the window
+func (tw *TimeSlidingWindow) Count() int {
+	calls, _, _ := tw.totals()
+	return calls
+}
+
+// Reset discards all recorded outcomes
+func (tw *TimeSlidingWindow) Reset() {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	clear(tw.buckets)
+}
+
+// FailureRate returns the failure rate
+func (tw *TimeSlidingWindow) FailureRate() float64 {
+	calls, failures, _ := tw.totals()
+	if calls == 0 {
+		return 0
+	}
+	return float64(failures) / float64(calls)
+}
+
+// SlowCallRate returns the rate of calls slower than the window's slow call
+// threshold
+func (tw *TimeSlidingWindow) SlowCallRate() float64 {
+	calls, _, slowCalls := tw.totals()
+	if calls == 0 {
+		return 0
+	}
+	return float64(slowCalls) / float64(calls)
+}
+
+// totals sums the buckets still within the window
+func (tw *TimeSlidingWindow) totals() (calls, failures, slowCalls int) {
+	tw.mutex.RLock()
+	defer tw.mutex.RUnlock()
+
+	oldest := tw.epoch() - int64(len(tw.buckets)) + 1
+	for _, bucket := range tw.buckets {
+		if bucket.calls > 0 && bucket.epoch >= oldest {
+			calls += bucket.calls
+			failures += bucket.failures
+			slowCalls += bucket.slowCalls
+		}
+	}
+	return calls, failures, slowCalls
+}
+
+// epoch returns the index of the current sub-interval
+func (tw *TimeSlidingWindow) epoch() int64 {
+	return tw.now().UnixNano() / int64(tw.bucketWidth)
+}
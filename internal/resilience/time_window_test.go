@@ -0,0 +1,119 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestTimeWindow returns a window over the last 10s in 1s buckets, and a
+// function advancing its clock
+func newTestTimeWindow() (*TimeSlidingWindow, func(time.Duration)) {
+	tw := NewTimeSlidingWindow(10*time.Second, 10, 100*time.Millisecond)
+	now := time.Unix(1_700_000_000, 0)
+	tw.now = func() time.Time { return now }
+	return tw, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestTimeSlidingWindow_RatesDecay(t *testing.T) {
+	tw, advance := newTestTimeWindow()
+
+	for i := 0; i < 4; i++ {
+		tw.Record(false, 200*time.Millisecond)
+	}
+	advance(5 * time.Second)
+	for i := 0; i < 4; i++ {
+		tw.Record(true, time.Millisecond)
+	}
+	if rate := tw.FailureRate(); rate != 0.5 {
+		t.Errorf("FailureRate() = %v, want 0.5", rate)
+	}
+	if rate := tw.SlowCallRate(); rate != 0.5 {
+		t.Errorf("SlowCallRate() = %v, want 0.5", rate)
+	}
+
+	// Without new calls, the failures age out of the window
+	advance(6 * time.Second)
+	if count := tw.Count(); count != 4 {
+		t.Errorf("Count() = %d, want 4", count)
+	}
+	if rate := tw.FailureRate(); rate != 0 {
+		t.Errorf("FailureRate() = %v, want 0", rate)
+	}
+	if rate := tw.SlowCallRate(); rate != 0 {
+		t.Errorf("SlowCallRate() = %v, want 0", rate)
+	}
+
+	advance(5 * time.Second)
+	if count := tw.Count(); count != 0 {
+		t.Errorf("Count() = %d, want 0 once every outcome aged out", count)
+	}
+}
+
+func TestTimeSlidingWindow_BucketReuse(t *testing.T) {
+	tw, advance := newTestTimeWindow()
+
+	tw.Record(false, 0)
+	// A full window later the same bucket slot holds a new sub-interval
+	advance(10 * time.Second)
+	tw.Record(true, 0)
+
+	if count := tw.Count(); count != 1 {
+		t.Errorf("Count() = %d, want 1", count)
+	}
+	if rate := tw.FailureRate(); rate != 0 {
+		t.Errorf("FailureRate() = %v, want 0", rate)
+	}
+}
+
+func TestTimeSlidingWindow_Reset(t *testing.T) {
+	tw, _ := newTestTimeWindow()
+	tw.Record(false, 0)
+	tw.Reset()
+
+	if count := tw.Count(); count != 0 {
+		t.Errorf("Count() after Reset() = %d, want 0", count)
+	}
+}
+
+func TestNewTimeSlidingWindow_Defaults(t *testing.T) {
+	tw := NewTimeSlidingWindow(0, 0, time.Second)
+	if len(tw.buckets) != DefaultTimeWindowBuckets {
+		t.Errorf("buckets = %d, want %d", len(tw.buckets), DefaultTimeWindowBuckets)
+	}
+	if tw.bucketWidth != time.Minute/DefaultTimeWindowBuckets {
+		t.Errorf("bucketWidth = %v, want %v", tw.bucketWidth, time.Minute/DefaultTimeWindowBuckets)
+	}
+}
+
+func TestCircuitBreaker_TimeWindow(t *testing.T) {
+	cfg := DefaultCircuitBreakerConfig("time")
+	cfg.FailureRateThreshold = 0.5
+	cfg.MinimumCalls = 3
+	cfg.SlidingWindowType = SlidingWindowTypeTime
+	cfg.SlidingWindowDuration = 50 * time.Millisecond
+	cfg.SlidingWindowBuckets = 5
+	cb := NewCircuitBreaker(cfg, newTestLogger())
+
+	ctx := context.Background()
+	fail := func(ctx context.Context) error { return errors.New("fail") }
+	succeed := func(ctx context.Context) error { return nil }
+
+	cb.Execute(ctx, fail)
+	cb.Execute(ctx, fail)
+	time.Sleep(70 * time.Millisecond)
+
+	// The earlier failures aged out, so the rate is 1/3 rather than 3/5
+	cb.Execute(ctx, succeed)
+	cb.Execute(ctx, succeed)
+	cb.Execute(ctx, fail)
+	if cb.State() != StateClosed {
+		t.Errorf("State = %v, want CLOSED", cb.State())
+	}
+
+	cb.Execute(ctx, fail)
+	if cb.State() != StateOpen {
+		t.Errorf("State after recent failures = %v, want OPEN", cb.State())
+	}
+}
@@ -0,0 +1,56 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrTimeout = errors.New("operation timed out")
+
+// WithTimeout runs fn with a context derived from ctx that is cancelled after d,
+// returning ErrTimeout if fn has not finished by then. If ctx itself is done
+// first, its error is returned instead.
+//
+// Go cannot forcibly stop a goroutine: on timeout WithTimeout returns at once
+// and fn keeps running until it notices the cancelled context. fn must
+// therefore honor ctx cancellation, or it will leak for as long as it runs.
+func WithTimeout(ctx context.Context, d time.Duration, fn func(context.Context) error) error {
+	_, err := WithTimeoutResult(ctx, d, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// WithTimeoutResult runs fn like WithTimeout and returns its result. On
+// timeout the zero value of T is returned with ErrTimeout.
+func WithTimeoutResult[T any](ctx context.Context, d time.Duration, fn func(context.Context) (T, error)) (T, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	type outcome struct {
+		result T
+		err    error
+	}
+	// Buffered so fn's goroutine can finish after a timeout without blocking
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := fn(timeoutCtx)
+		done <- outcome{result, err}
+	}()
+
+	var zero T
+	select {
+	case o := <-done:
+		// fn may have returned the context error as the deadline passed
+		if o.err != nil && ctx.Err() == nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			return zero, ErrTimeout
+		}
+		return o.result, o.err
+	case <-timeoutCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+		return zero, ErrTimeout
+	}
+}
@@ -0,0 +1,103 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout_Completes(t *testing.T) {
+	err := WithTimeout(context.Background(), time.Second, func(ctx context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Errorf("WithTimeout() error = %v, want nil", err)
+	}
+}
+
+func TestWithTimeout_ReturnsFnError(t *testing.T) {
+	testErr := errors.New("test error")
+	err := WithTimeout(context.Background(), time.Second, func(ctx context.Context) error {
+		return testErr
+	})
+	if !errors.Is(err, testErr) {
+		t.Errorf("WithTimeout() error = %v, want %v", err, testErr)
+	}
+}
+
+func TestWithTimeout_TimesOut(t *testing.T) {
+	cancelled := make(chan struct{})
+	start := time.Now()
+	err := WithTimeout(context.Background(), 20*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		close(cancelled)
+		time.Sleep(100 * time.Millisecond) // slow to wind down
+		return ctx.Err()
+	})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("WithTimeout() error = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 80*time.Millisecond {
+		t.Errorf("WithTimeout() returned after %v, should not wait for fn", elapsed)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("fn's context was not cancelled")
+	}
+}
+
+func TestWithTimeout_ParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := WithTimeout(ctx, time.Second, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WithTimeout() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithTimeoutResult(t *testing.T) {
+	result, err := WithTimeoutResult(context.Background(), time.Second, func(ctx context.Context) (string, error) {
+		return "success", nil
+	})
+	if err != nil || result != "success" {
+		t.Errorf("WithTimeoutResult() = %q, %v, want success, nil", result, err)
+	}
+
+	result, err = WithTimeoutResult(context.Background(), 10*time.Millisecond, func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "late", ctx.Err()
+	})
+	if !errors.Is(err, ErrTimeout) || result != "" {
+		t.Errorf("WithTimeoutResult() = %q, %v, want \"\", ErrTimeout", result, err)
+	}
+}
+
+func TestWithTimeout_InsideRetry(t *testing.T) {
+	cfg := &RetryConfig{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 1}
+
+	attempts := 0
+	err := Retry(context.Background(), cfg, func(ctx context.Context) error {
+		attempts++
+		attempt := attempts
+		return WithTimeout(ctx, 10*time.Millisecond, func(ctx context.Context) error {
+			if attempt < 3 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Errorf("Retry(WithTimeout) error = %v, want nil", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
@@ -0,0 +1,106 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// stubAPIKeyRepository serves API keys from a map keyed by hash
+type stubAPIKeyRepository struct {
+	keys map[string]*entity.APIKey
+	err  error
+}
+
+func (r *stubAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error { return nil }
+func (r *stubAPIKeyRepository) GetByID(ctx context.Context, id uint) (*entity.APIKey, error) {
+	return nil, nil
+}
+func (r *stubAPIKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	return r.keys[keyHash], r.err
+}
+func (r *stubAPIKeyRepository) List(ctx context.Context, page, size int) ([]*entity.APIKey, int64, error) {
+	return nil, 0, nil
+}
+func (r *stubAPIKeyRepository) Revoke(ctx context.Context, id uint) (bool, error) { return false, nil }
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	if !strings.HasPrefix(key, "ak_") || !strings.HasPrefix(key, prefix) || len(prefix) != apiKeyPrefixLength {
+		t.Errorf("GenerateAPIKey() = %q, prefix %q", key, prefix)
+	}
+
+	other, _, _ := GenerateAPIKey()
+	if key == other {
+		t.Error("GenerateAPIKey() should return a different key each time")
+	}
+	if HashAPIKey(key) == HashAPIKey(other) || len(HashAPIKey(key)) != 64 {
+		t.Error("HashAPIKey() should return distinct SHA-256 hex hashes")
+	}
+}
+
+func TestSecurityService_ValidateAPIKey(t *testing.T) {
+	s := newTestSecurityService()
+	ctx := context.Background()
+
+	if _, err := s.ValidateAPIKey(ctx, "ak_anything"); !errors.Is(err, ErrAPIKeyAuthUnavailable) {
+		t.Errorf("ValidateAPIKey() without repository error = %v, want %v", err, ErrAPIKeyAuthUnavailable)
+	}
+
+	valid, _, _ := GenerateAPIKey()
+	expired, _, _ := GenerateAPIKey()
+	past := time.Now().Add(-time.Minute)
+	repo := &stubAPIKeyRepository{keys: map[string]*entity.APIKey{
+		HashAPIKey(valid):   {ID: 3, Name: "billing", Scopes: "jobs:read"},
+		HashAPIKey(expired): {ID: 4, Name: "old", ExpiresAt: &past},
+	}}
+	s.SetAPIKeyRepository(repo)
+
+	claims, err := s.ValidateAPIKey(ctx, valid)
+	if err != nil {
+		t.Fatalf("ValidateAPIKey() error = %v", err)
+	}
+	if claims.APIKeyID != 3 || claims.UserID != 0 || claims.Role != entity.RoleService || claims.Username != "billing" {
+		t.Errorf("ValidateAPIKey() claims = %+v", claims)
+	}
+	if len(claims.Scopes) != 1 || claims.Scopes[0] != "jobs:read" {
+		t.Errorf("ValidateAPIKey() scopes = %v", claims.Scopes)
+	}
+
+	for _, key := range []string{expired, "ak_unknown", "not-an-api-key"} {
+		if _, err := s.ValidateAPIKey(ctx, key); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("ValidateAPIKey(%q) error = %v, want %v", key, err, ErrInvalidAPIKey)
+		}
+	}
+
+	repo.err = errors.New("db down")
+	if _, err := s.ValidateAPIKey(ctx, valid); err == nil || errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("ValidateAPIKey() should return repository errors, got %v", err)
+	}
+}
+
+func TestSecurityService_HasScope(t *testing.T) {
+	s := newTestSecurityService()
+
+	c, _ := newTestContext()
+	if s.HasScope(c, "jobs:read") {
+		t.Error("HasScope() should be false when unauthenticated")
+	}
+
+	s.SetCurrentClaims(c, &UserClaims{UserID: 1, Role: entity.RoleUser})
+	if !s.HasScope(c, "jobs:read") || s.IsAPIKeyPrincipal(c) {
+		t.Error("users should not be limited by scopes")
+	}
+
+	s.SetCurrentClaims(c, &UserClaims{APIKeyID: 1, Role: entity.RoleService, Scopes: []string{"jobs:read"}})
+	if !s.IsAPIKeyPrincipal(c) || !s.HasScope(c, "jobs:read") || s.HasScope(c, "jobs:write") {
+		t.Error("API keys should be limited to their scopes")
+	}
+}
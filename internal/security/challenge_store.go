@@ -0,0 +1,62 @@
+package security
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefixChallengeAttempts = "arcana:2fa:attempts:"
+	keyPrefixChallengeUsed     = "arcana:2fa:used:"
+)
+
+// MaxTwoFactorAttempts is the number of codes that may be tried against a
+// single two-factor challenge before the user has to log in again
+const MaxTwoFactorAttempts = 5
+
+// TwoFactorChallengeStore tracks two-factor challenges until they expire, so
+// that a challenge can only be used once and its codes cannot be brute-forced
+type TwoFactorChallengeStore interface {
+	// RecordAttempt counts a code tried against the challenge with the given
+	// jti and returns the number of attempts so far, including this one
+	RecordAttempt(ctx context.Context, jti string, expiresAt time.Time) (int64, error)
+	// Consume marks the challenge as used. It reports false if the challenge
+	// was already used.
+	Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+}
+
+var _ TwoFactorChallengeStore = (*RedisChallengeStore)(nil)
+
+// RedisChallengeStore stores two-factor challenge state in Redis so that
+// every replica sees it. Entries expire with the challenges they track.
+type RedisChallengeStore struct {
+	client *redis.Client
+}
+
+// NewRedisChallengeStore creates a new Redis-backed challenge store
+func NewRedisChallengeStore(client *redis.Client) *RedisChallengeStore {
+	return &RedisChallengeStore{client: client}
+}
+
+// RecordAttempt counts a code tried against the challenge
+func (s *RedisChallengeStore) RecordAttempt(ctx context.Context, jti string, expiresAt time.Time) (int64, error) {
+	key := keyPrefixChallengeAttempts + jti
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireAt(ctx, key, expiresAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// Consume marks the challenge as used
+func (s *RedisChallengeStore) Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return false, nil
+	}
+	return s.client.SetNX(ctx, keyPrefixChallengeUsed+jti, 1, ttl).Result()
+}
@@ -0,0 +1,52 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrInvalidCiphertext is returned when a value cannot be decrypted
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// SecretCipher encrypts secrets stored at rest, such as TOTP secrets, with
+// AES-256-GCM
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher creates a SecretCipher with a key derived from the given
+// passphrase
+func NewSecretCipher(passphrase string) *SecretCipher {
+	key := sha256.Sum256([]byte(passphrase))
+	block, _ := aes.NewCipher(key[:]) // a 32-byte key never fails
+	aead, _ := cipher.NewGCM(block)
+	return &SecretCipher{aead: aead}
+}
+
+// Encrypt encrypts plaintext, returning base64 of the nonce and ciphertext
+func (c *SecretCipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt
+func (c *SecretCipher) Decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,276 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/config"
+)
+
+// writePEM writes a PEM block to a temporary file and returns its path
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newAsymmetricConfig(algorithm string) *config.JWTConfig {
+	return &config.JWTConfig{
+		Algorithm:            algorithm,
+		AccessTokenDuration:  time.Hour,
+		RefreshTokenDuration: 24 * time.Hour,
+		Issuer:               "test-issuer",
+	}
+}
+
+func newRSAKeyFiles(t *testing.T) (privatePath, publicPath string, key *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	return writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		writePEM(t, "PUBLIC KEY", publicDER), key
+}
+
+func newECKeyFiles(t *testing.T) (privatePath, publicPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	publicDER, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	return writePEM(t, "PRIVATE KEY", privateDER), writePEM(t, "PUBLIC KEY", publicDER)
+}
+
+func TestLoadJWTProvider_Asymmetric(t *testing.T) {
+	rsaPrivate, rsaPublic, _ := newRSAKeyFiles(t)
+	ecPrivate, ecPublic := newECKeyFiles(t)
+
+	tests := []struct {
+		algorithm   string
+		privatePath string
+		publicPath  string
+	}{
+		{AlgorithmRS256, rsaPrivate, rsaPublic},
+		{AlgorithmES256, ecPrivate, ecPublic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			cfg := newAsymmetricConfig(tt.algorithm)
+			cfg.PrivateKeyFile = tt.privatePath
+			issuer, err := LoadJWTProvider(cfg)
+			if err != nil {
+				t.Fatalf("LoadJWTProvider() error = %v", err)
+			}
+			if issuer.Algorithm() != tt.algorithm {
+				t.Errorf("Algorithm() = %s, want %s", issuer.Algorithm(), tt.algorithm)
+			}
+
+			token, err := issuer.GenerateAccessToken(newTestUser())
+			if err != nil {
+				t.Fatalf("GenerateAccessToken() error = %v", err)
+			}
+			parsed, _, _ := jwt.NewParser().ParseUnverified(token, &UserClaims{})
+			if parsed.Header["alg"] != tt.algorithm || parsed.Header["kid"] == "" {
+				t.Errorf("header = %v, want alg %s and a kid", parsed.Header, tt.algorithm)
+			}
+
+			// A service holding only the public key can verify but not issue
+			cfg = newAsymmetricConfig(tt.algorithm)
+			cfg.PublicKeyFile = tt.publicPath
+			verifier, err := LoadJWTProvider(cfg)
+			if err != nil {
+				t.Fatalf("LoadJWTProvider(public key) error = %v", err)
+			}
+			claims, err := verifier.ValidateAccessToken(token)
+			if err != nil {
+				t.Fatalf("ValidateAccessToken() error = %v", err)
+			}
+			if claims.UserID != 1 {
+				t.Errorf("UserID = %d, want 1", claims.UserID)
+			}
+			if _, err := verifier.GenerateAccessToken(newTestUser()); !errors.Is(err, ErrSigningKeyUnavailable) {
+				t.Errorf("GenerateAccessToken() without private key error = %v, want ErrSigningKeyUnavailable", err)
+			}
+
+			// HS256 tokens are rejected, whatever key they were signed with
+			hsToken, _ := newTestJWTProvider().GenerateAccessToken(newTestUser())
+			if _, err := verifier.ValidateAccessToken(hsToken); err != ErrInvalidToken {
+				t.Errorf("ValidateAccessToken(HS256) error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestLoadJWTProvider_AlgorithmConfusion(t *testing.T) {
+	rsaPrivate, rsaPublic, _ := newRSAKeyFiles(t)
+	cfg := newAsymmetricConfig(AlgorithmRS256)
+	cfg.PrivateKeyFile = rsaPrivate
+	provider := NewJWTProvider(cfg)
+
+	// An attacker signs an HS256 token using the public key as the secret
+	publicPEM, _ := os.ReadFile(rsaPublic)
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, UserClaims{
+		UserID: 1,
+		Role:   "ADMIN",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString(publicPEM)
+
+	if _, err := provider.ValidateAccessToken(forged); err != ErrInvalidToken {
+		t.Errorf("ValidateAccessToken(forged) error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestLoadJWTProvider_Errors(t *testing.T) {
+	rsaPrivate, _, _ := newRSAKeyFiles(t)
+	notPEM := filepath.Join(t.TempDir(), "key.txt")
+	_ = os.WriteFile(notPEM, []byte("not a key"), 0o600)
+
+	tests := []struct {
+		name string
+		cfg  *config.JWTConfig
+	}{
+		{"unsupported algorithm", &config.JWTConfig{Algorithm: "none"}},
+		{"no key", &config.JWTConfig{Algorithm: AlgorithmRS256}},
+		{"missing file", &config.JWTConfig{Algorithm: AlgorithmRS256, PrivateKeyFile: "/does/not/exist"}},
+		{"not PEM", &config.JWTConfig{Algorithm: AlgorithmRS256, PrivateKeyFile: notPEM}},
+		{"key type mismatch", &config.JWTConfig{Algorithm: AlgorithmES256, PrivateKeyFile: rsaPrivate}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadJWTProvider(tt.cfg); err == nil {
+				t.Error("LoadJWTProvider() error = nil, want an error")
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewJWTProvider() should panic when keys cannot be loaded")
+		}
+	}()
+	NewJWTProvider(&config.JWTConfig{Algorithm: AlgorithmRS256})
+}
+
+func TestJWTProvider_JWKS(t *testing.T) {
+	if keys := newTestJWTProvider().JWKS().Keys; len(keys) != 0 {
+		t.Errorf("HS256 JWKS = %v, want no keys", keys)
+	}
+
+	rsaPrivate, _, rsaKey := newRSAKeyFiles(t)
+	cfg := newAsymmetricConfig(AlgorithmRS256)
+	cfg.PrivateKeyFile = rsaPrivate
+	provider := NewJWTProvider(cfg)
+
+	set := provider.JWKS()
+	if len(set.Keys) != 1 {
+		t.Fatalf("len(keys) = %d, want 1", len(set.Keys))
+	}
+	jwk := set.Keys[0]
+	if jwk.Kty != "RSA" || jwk.Alg != AlgorithmRS256 || jwk.Use != "sig" || jwk.Kid != jwk.Thumbprint() {
+		t.Errorf("JWK = %+v", jwk)
+	}
+	if jwk.E != "AQAB" {
+		t.Errorf("e = %s, want AQAB", jwk.E)
+	}
+
+	key, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey() error = %v", err)
+	}
+	if !rsaKey.PublicKey.Equal(key) {
+		t.Error("PublicKey() does not match the signing key")
+	}
+
+	ecPrivate, _ := newECKeyFiles(t)
+	cfg = newAsymmetricConfig(AlgorithmES256)
+	cfg.PrivateKeyFile = ecPrivate
+	ecJWK := NewJWTProvider(cfg).JWKS().Keys[0]
+	ecKey, err := ecJWK.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey() error = %v", err)
+	}
+	if ecJWK.Crv != "P-256" || !ecKey.(*ecdsa.PublicKey).Equal(NewJWTProvider(cfg).publicKey) {
+		t.Errorf("EC JWK = %+v does not round-trip", ecJWK)
+	}
+}
+
+func TestJWK_Thumbprint_RFC7638(t *testing.T) {
+	// The example key from RFC 7638 section 3.1
+	jwk := JWK{
+		Kty: "RSA",
+		E:   "AQAB",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMs" +
+			"tn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5" +
+			"hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	}
+	if got := jwk.Thumbprint(); got != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+		t.Errorf("Thumbprint() = %s", got)
+	}
+}
+
+func TestJWTProvider_RemoteJWKS(t *testing.T) {
+	rsaPrivate, _, _ := newRSAKeyFiles(t)
+	cfg := newAsymmetricConfig(AlgorithmRS256)
+	cfg.PrivateKeyFile = rsaPrivate
+	issuer := NewJWTProvider(cfg)
+
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(issuer.JWKS())
+	}))
+	defer server.Close()
+
+	cfg = newAsymmetricConfig(AlgorithmRS256)
+	cfg.JWKSURL = server.URL
+	verifier := NewJWTProvider(cfg)
+
+	for range 3 {
+		token, _ := issuer.GenerateAccessToken(newTestUser())
+		if _, err := verifier.ValidateAccessToken(token); err != nil {
+			t.Fatalf("ValidateAccessToken() error = %v", err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+
+	// A token from an unknown key does not trigger another fetch straight away
+	otherPrivate, _, _ := newRSAKeyFiles(t)
+	cfg = newAsymmetricConfig(AlgorithmRS256)
+	cfg.PrivateKeyFile = otherPrivate
+	token, _ := NewJWTProvider(cfg).GenerateAccessToken(newTestUser())
+	if _, err := verifier.ValidateAccessToken(token); err != ErrInvalidToken {
+		t.Errorf("ValidateAccessToken(unknown key) error = %v, want ErrInvalidToken", err)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+}
@@ -1,7 +1,11 @@
 package security
 
 import (
+	"crypto"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,9 +16,34 @@ import (
 )
 
 var (
-	ErrInvalidToken     = errors.New("invalid token")
-	ErrExpiredToken     = errors.New("token has expired")
-	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrInvalidToken          = errors.New("invalid token")
+	ErrExpiredToken          = errors.New("token has expired")
+	ErrInvalidSignature      = errors.New("invalid token signature")
+	ErrSigningKeyUnavailable = errors.New("no private key configured for signing tokens")
+)
+
+// Supported JWT signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+)
+
+const (
+	// verificationAudience marks email verification tokens so they are never
+	// accepted as access tokens
+	verificationAudience = "email-verification"
+
+	// verificationTokenDuration is how long an email verification token is valid
+	verificationTokenDuration = 24 * time.Hour
+
+	// twoFactorAudience marks two-factor login challenge tokens so they are
+	// never accepted as access tokens
+	twoFactorAudience = "two-factor-challenge"
+
+	// twoFactorChallengeDuration is how long a user has to enter their TOTP code
+	// after entering their password
+	twoFactorChallengeDuration = 5 * time.Minute
 )
 
 // UserClaims represents the JWT claims for a user
@@ -23,36 +52,203 @@ type UserClaims struct {
 	Username string          `json:"username"`
 	Email    string          `json:"email"`
 	Role     entity.UserRole `json:"role"`
+	Verified bool            `json:"verified,omitempty"`
+	// Permissions are resolved from the role when the token is issued, so
+	// permission checks need no lookup
+	Permissions []string `json:"permissions,omitempty"`
+	// SessionID is the ID of the refresh token issued alongside the token
+	SessionID uint `json:"sid,omitempty"`
+	// APIKeyID and Scopes are set for callers authenticated with an API key
+	// and never appear in tokens
+	APIKeyID uint     `json:"-"`
+	Scopes   []string `json:"-"`
 	jwt.RegisteredClaims
 }
 
-// JWTProvider handles JWT token generation and validation
+// VerificationClaims represents the JWT claims of an email verification token
+type VerificationClaims struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// TwoFactorChallengeClaims represents the JWT claims of a two-factor login
+// challenge, issued after the password check and exchanged for tokens once the
+// second factor is verified
+type TwoFactorChallengeClaims struct {
+	UserID uint `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// JWTProvider handles JWT token generation and validation. Tokens are signed
+// with HS256 and a shared secret by default, or with RS256/ES256 and a private
+// key so that other services can verify them with only the public key.
 type JWTProvider struct {
 	secret               []byte
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
 	issuer               string
+
+	method     jwt.SigningMethod
+	signingKey any
+	// publicKey verifies asymmetric tokens; remoteKeys is used instead when
+	// keys come from a JWKS URL
+	publicKey  crypto.PublicKey
+	keyID      string
+	remoteKeys *remoteKeySet
+
+	rolePermissions RolePermissions
 }
 
-// NewJWTProvider creates a new JWTProvider instance
+// NewJWTProvider creates a new JWTProvider instance. It panics if the
+// configured signing keys cannot be loaded; use LoadJWTProvider to handle the
+// error instead.
 func NewJWTProvider(cfg *config.JWTConfig) *JWTProvider {
-	return &JWTProvider{
+	provider, err := LoadJWTProvider(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return provider
+}
+
+// LoadJWTProvider creates a new JWTProvider, loading the keys required by
+// cfg.Algorithm. For RS256 and ES256 a private key is needed to issue tokens;
+// a service that only verifies tokens may configure just the public key or a
+// JWKS URL.
+func LoadJWTProvider(cfg *config.JWTConfig) (*JWTProvider, error) {
+	p := &JWTProvider{
 		secret:               []byte(cfg.Secret),
 		accessTokenDuration:  cfg.AccessTokenDuration,
 		refreshTokenDuration: cfg.RefreshTokenDuration,
 		issuer:               cfg.Issuer,
+		rolePermissions:      DefaultRolePermissions(),
+	}
+
+	switch strings.ToUpper(cfg.Algorithm) {
+	case "", AlgorithmHS256:
+		p.method = jwt.SigningMethodHS256
+		p.signingKey = p.secret
+		return p, nil
+	case AlgorithmRS256:
+		p.method = jwt.SigningMethodRS256
+	case AlgorithmES256:
+		p.method = jwt.SigningMethodES256
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
 	}
+
+	if err := p.loadKeys(cfg); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetRolePermissions sets the permissions embedded in access tokens for each
+// role, replacing DefaultRolePermissions
+func (p *JWTProvider) SetRolePermissions(permissions RolePermissions) {
+	p.rolePermissions = permissions
+}
+
+func (p *JWTProvider) loadKeys(cfg *config.JWTConfig) error {
+	switch {
+	case cfg.PrivateKeyFile != "":
+		key, err := loadPrivateKey(cfg.PrivateKeyFile)
+		if err != nil {
+			return err
+		}
+		p.signingKey = key
+		p.publicKey = key.Public()
+	case cfg.PublicKeyFile != "":
+		key, err := loadPublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return err
+		}
+		p.publicKey = key
+	case cfg.JWKSURL != "":
+		p.remoteKeys = newRemoteKeySet(cfg.JWKSURL)
+		return nil
+	default:
+		return fmt.Errorf("JWT algorithm %s requires a private key, public key or JWKS URL", p.method.Alg())
+	}
+
+	if !keyMatchesMethod(p.publicKey, p.method) {
+		return fmt.Errorf("JWT key type %T does not match algorithm %s", p.publicKey, p.method.Alg())
+	}
+	jwk, err := newJWK(p.publicKey, p.method.Alg(), "")
+	if err != nil {
+		return err
+	}
+	p.keyID = jwk.Thumbprint()
+	return nil
+}
+
+// sign signs claims with the configured algorithm and key
+func (p *JWTProvider) sign(claims jwt.Claims) (string, error) {
+	if p.signingKey == nil {
+		return "", ErrSigningKeyUnavailable
+	}
+	token := jwt.NewWithClaims(p.method, claims)
+	if p.keyID != "" {
+		token.Header["kid"] = p.keyID
+	}
+	return token.SignedString(p.signingKey)
+}
+
+// keyFunc returns the key verifying a token. Only the configured algorithm is
+// accepted, so an HS256 token signed with the public key cannot pass as RS256.
+func (p *JWTProvider) keyFunc(token *jwt.Token) (any, error) {
+	if token.Method.Alg() != p.method.Alg() {
+		return nil, ErrInvalidSignature
+	}
+	switch {
+	case p.remoteKeys != nil:
+		kid, _ := token.Header["kid"].(string)
+		return p.remoteKeys.key(kid)
+	case p.publicKey != nil:
+		return p.publicKey, nil
+	default:
+		return p.secret, nil
+	}
+}
+
+// Algorithm returns the signing algorithm, e.g. "HS256"
+func (p *JWTProvider) Algorithm() string {
+	return p.method.Alg()
+}
+
+// JWKS returns the public key as a JSON Web Key Set so that other services
+// can verify tokens. It is empty for HS256, whose key must stay secret.
+func (p *JWTProvider) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if p.publicKey == nil {
+		return set
+	}
+	jwk, err := newJWK(p.publicKey, p.method.Alg(), p.keyID)
+	if err == nil {
+		set.Keys = append(set.Keys, *jwk)
+	}
+	return set
 }
 
 // GenerateAccessToken generates a new access token for a user
 func (p *JWTProvider) GenerateAccessToken(user *entity.User) (string, error) {
+	return p.GenerateSessionAccessToken(user, 0)
+}
+
+// GenerateSessionAccessToken generates a new access token tied to the session
+// of the refresh token with the given ID
+func (p *JWTProvider) GenerateSessionAccessToken(user *entity.User, sessionID uint) (string, error) {
 	now := time.Now()
 	claims := UserClaims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Role:     user.Role,
+		UserID:      user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		Role:        user.Role,
+		Verified:    user.IsVerified,
+		Permissions: p.rolePermissions.For(user.Role),
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(), // jti, used to revoke the token
 			Issuer:    p.issuer,
 			Subject:   user.Username,
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -60,8 +256,7 @@ func (p *JWTProvider) GenerateAccessToken(user *entity.User) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(p.secret)
+	return p.sign(claims)
 }
 
 // GenerateRefreshToken generates a new refresh token
@@ -77,19 +272,13 @@ func (p *JWTProvider) GenerateRefreshToken(user *entity.User) (string, time.Time
 		ExpiresAt: jwt.NewNumericDate(expiresAt),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(p.secret)
+	tokenString, err := p.sign(claims)
 	return tokenString, expiresAt, err
 }
 
 // ValidateAccessToken validates an access token and returns the claims
 func (p *JWTProvider) ValidateAccessToken(tokenString string) (*UserClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidSignature
-		}
-		return p.secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, p.keyFunc)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -99,7 +288,9 @@ func (p *JWTProvider) ValidateAccessToken(tokenString string) (*UserClaims, erro
 	}
 
 	claims, ok := token.Claims.(*UserClaims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid ||
+		slices.Contains(claims.Audience, verificationAudience) ||
+		slices.Contains(claims.Audience, twoFactorAudience) {
 		return nil, ErrInvalidToken
 	}
 
@@ -108,12 +299,7 @@ func (p *JWTProvider) ValidateAccessToken(tokenString string) (*UserClaims, erro
 
 // ValidateRefreshToken validates a refresh token
 func (p *JWTProvider) ValidateRefreshToken(tokenString string) (*jwt.RegisteredClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidSignature
-		}
-		return p.secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, p.keyFunc)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -130,6 +316,89 @@ func (p *JWTProvider) ValidateRefreshToken(tokenString string) (*jwt.RegisteredC
 	return claims, nil
 }
 
+// GenerateVerificationToken generates a signed token confirming the user's
+// current email address
+func (p *JWTProvider) GenerateVerificationToken(user *entity.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(verificationTokenDuration)
+
+	claims := VerificationClaims{
+		UserID: user.ID,
+		Email:  user.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    p.issuer,
+			Subject:   user.Username,
+			Audience:  jwt.ClaimStrings{verificationAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	tokenString, err := p.sign(claims)
+	return tokenString, expiresAt, err
+}
+
+// ValidateVerificationToken validates an email verification token
+func (p *JWTProvider) ValidateVerificationToken(tokenString string) (*VerificationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &VerificationClaims{}, p.keyFunc, jwt.WithAudience(verificationAudience))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*VerificationClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// GenerateTwoFactorChallenge generates a short-lived token identifying a login
+// that has passed the password check but still needs the second factor
+func (p *JWTProvider) GenerateTwoFactorChallenge(user *entity.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(twoFactorChallengeDuration)
+
+	claims := TwoFactorChallengeClaims{
+		UserID: user.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    p.issuer,
+			Subject:   user.Username,
+			Audience:  jwt.ClaimStrings{twoFactorAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	tokenString, err := p.sign(claims)
+	return tokenString, expiresAt, err
+}
+
+// ValidateTwoFactorChallenge validates a two-factor login challenge token
+func (p *JWTProvider) ValidateTwoFactorChallenge(tokenString string) (*TwoFactorChallengeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &TwoFactorChallengeClaims{}, p.keyFunc, jwt.WithAudience(twoFactorAudience))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*TwoFactorChallengeClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
 // GetAccessTokenDuration returns the access token duration in seconds
 func (p *JWTProvider) GetAccessTokenDuration() int64 {
 	return int64(p.accessTokenDuration.Seconds())
@@ -261,6 +261,87 @@ func TestJWTProvider_ValidateRefreshToken_Expired(t *testing.T) {
 	}
 }
 
+func TestJWTProvider_VerificationToken(t *testing.T) {
+	provider := newTestJWTProvider()
+	user := newTestUser()
+
+	token, expiresAt, err := provider.GenerateVerificationToken(user)
+	if err != nil {
+		t.Fatalf("GenerateVerificationToken() error = %v", err)
+	}
+	if time.Until(expiresAt) > verificationTokenDuration {
+		t.Errorf("expiresAt = %v, want within %v", expiresAt, verificationTokenDuration)
+	}
+
+	claims, err := provider.ValidateVerificationToken(token)
+	if err != nil {
+		t.Fatalf("ValidateVerificationToken() error = %v", err)
+	}
+	if claims.UserID != user.ID || claims.Email != user.Email {
+		t.Errorf("claims = %+v, want user %d with email %s", claims, user.ID, user.Email)
+	}
+
+	// Verification and access tokens are not interchangeable
+	if _, err := provider.ValidateAccessToken(token); err != ErrInvalidToken {
+		t.Errorf("ValidateAccessToken(verification token) error = %v, want ErrInvalidToken", err)
+	}
+	accessToken, _ := provider.GenerateAccessToken(user)
+	if _, err := provider.ValidateVerificationToken(accessToken); err != ErrInvalidToken {
+		t.Errorf("ValidateVerificationToken(access token) error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTProvider_TwoFactorChallenge(t *testing.T) {
+	provider := newTestJWTProvider()
+	user := newTestUser()
+
+	token, expiresAt, err := provider.GenerateTwoFactorChallenge(user)
+	if err != nil {
+		t.Fatalf("GenerateTwoFactorChallenge() error = %v", err)
+	}
+	if time.Until(expiresAt) > twoFactorChallengeDuration {
+		t.Errorf("expiresAt = %v, want within %v", expiresAt, twoFactorChallengeDuration)
+	}
+
+	claims, err := provider.ValidateTwoFactorChallenge(token)
+	if err != nil {
+		t.Fatalf("ValidateTwoFactorChallenge() error = %v", err)
+	}
+	if claims.UserID != user.ID {
+		t.Errorf("UserID = %d, want %d", claims.UserID, user.ID)
+	}
+
+	// A challenge must never grant access on its own
+	if _, err := provider.ValidateAccessToken(token); err != ErrInvalidToken {
+		t.Errorf("ValidateAccessToken(challenge) error = %v, want ErrInvalidToken", err)
+	}
+	accessToken, _ := provider.GenerateAccessToken(user)
+	if _, err := provider.ValidateTwoFactorChallenge(accessToken); err != ErrInvalidToken {
+		t.Errorf("ValidateTwoFactorChallenge(access token) error = %v, want ErrInvalidToken", err)
+	}
+	verificationToken, _, _ := provider.GenerateVerificationToken(user)
+	if _, err := provider.ValidateTwoFactorChallenge(verificationToken); err != ErrInvalidToken {
+		t.Errorf("ValidateTwoFactorChallenge(verification token) error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTProvider_AccessTokenVerifiedClaim(t *testing.T) {
+	provider := newTestJWTProvider()
+	user := newTestUser()
+
+	for _, verified := range []bool{false, true} {
+		user.IsVerified = verified
+		token, _ := provider.GenerateAccessToken(user)
+		claims, err := provider.ValidateAccessToken(token)
+		if err != nil {
+			t.Fatalf("ValidateAccessToken() error = %v", err)
+		}
+		if claims.Verified != verified {
+			t.Errorf("Verified = %v, want %v", claims.Verified, verified)
+		}
+	}
+}
+
 func TestJWTProvider_GetAccessTokenDuration(t *testing.T) {
 	provider := newTestJWTProvider()
 
@@ -1,26 +1,115 @@
 package security
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
 const (
 	// DefaultCost is the default bcrypt cost
 	DefaultCost = 12
+
+	// DefaultArgon2Memory is the default Argon2id memory in KiB (64 MiB)
+	DefaultArgon2Memory = 64 * 1024
+	// DefaultArgon2Iterations is the default number of Argon2id passes
+	DefaultArgon2Iterations = 3
+	// DefaultArgon2Parallelism is the default number of Argon2id lanes
+	DefaultArgon2Parallelism = 2
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// Supported password hashing algorithms
+const (
+	HashAlgorithmBcrypt   = "bcrypt"
+	HashAlgorithmArgon2id = "argon2id"
 )
 
-// PasswordHasher handles password hashing and verification
+var ErrInvalidHash = errors.New("invalid password hash")
+
+// HasherConfig holds password hashing parameters. Zero values take the
+// defaults.
+type HasherConfig struct {
+	// Algorithm is bcrypt (default) or argon2id
+	Algorithm string
+	// BcryptCost is the bcrypt cost factor
+	BcryptCost int
+	// Argon2Memory is the Argon2id memory in KiB
+	Argon2Memory uint32
+	// Argon2Iterations is the number of Argon2id passes over the memory
+	Argon2Iterations uint32
+	// Argon2Parallelism is the number of Argon2id lanes
+	Argon2Parallelism uint8
+}
+
+// PasswordHasher handles password hashing and verification. The algorithm
+// and its parameters are encoded in each hash, so hashes made with other
+// settings still verify and NeedsRehash reports them as out of date.
 type PasswordHasher struct {
-	cost int
+	algorithm   string
+	cost        int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
 }
 
-// NewPasswordHasher creates a new PasswordHasher instance
+// NewPasswordHasher creates a new bcrypt PasswordHasher with the default cost
 func NewPasswordHasher() *PasswordHasher {
-	return &PasswordHasher{cost: DefaultCost}
+	return &PasswordHasher{algorithm: HashAlgorithmBcrypt, cost: DefaultCost}
 }
 
-// Hash generates a bcrypt hash of the password
+// NewPasswordHasherWithConfig creates a new PasswordHasher hashing with the
+// configured algorithm and parameters
+func NewPasswordHasherWithConfig(cfg HasherConfig) (*PasswordHasher, error) {
+	h := &PasswordHasher{
+		algorithm:   strings.ToLower(cfg.Algorithm),
+		cost:        cfg.BcryptCost,
+		memory:      cfg.Argon2Memory,
+		iterations:  cfg.Argon2Iterations,
+		parallelism: cfg.Argon2Parallelism,
+	}
+
+	switch h.algorithm {
+	case "", HashAlgorithmBcrypt:
+		h.algorithm = HashAlgorithmBcrypt
+		if h.cost == 0 {
+			h.cost = DefaultCost
+		}
+		if h.cost < bcrypt.MinCost || h.cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case HashAlgorithmArgon2id:
+		if h.memory == 0 {
+			h.memory = DefaultArgon2Memory
+		}
+		if h.iterations == 0 {
+			h.iterations = DefaultArgon2Iterations
+		}
+		if h.parallelism == 0 {
+			h.parallelism = DefaultArgon2Parallelism
+		}
+		if h.memory < 8*uint32(h.parallelism) {
+			return nil, errors.New("argon2id memory must be at least 8 KiB per lane")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm %q", cfg.Algorithm)
+	}
+	return h, nil
+}
+
+// Hash hashes the password with the configured algorithm
 func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.algorithm == HashAlgorithmArgon2id {
+		return h.hashArgon2id(password)
+	}
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
 	if err != nil {
 		return "", err
@@ -28,8 +117,80 @@ func (h *PasswordHasher) Hash(password string) (string, error) {
 	return string(bytes), nil
 }
 
-// Verify checks if the password matches the hash
+// Verify checks if the password matches the hash, whichever supported
+// algorithm made it
 func (h *PasswordHasher) Verify(password, hash string) bool {
+	if strings.HasPrefix(hash, "$"+HashAlgorithmArgon2id+"$") {
+		params, salt, key, err := decodeArgon2idHash(hash)
+		if err != nil {
+			return false
+		}
+		derived := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(derived, key) == 1
+	}
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// NeedsRehash reports whether hash was made with a different algorithm or
+// parameters than the hasher's, so it should be replaced once the password
+// is known
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if h.algorithm == HashAlgorithmArgon2id {
+		params, _, _, err := decodeArgon2idHash(hash)
+		return err != nil || params.memory != h.memory || params.iterations != h.iterations || params.parallelism != h.parallelism
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// hashArgon2id encodes the hash in the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func (h *PasswordHasher) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, argon2KeyLength)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		HashAlgorithmArgon2id, argon2.Version, h.memory, h.iterations, h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func decodeArgon2idHash(hash string) (*argon2Params, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != HashAlgorithmArgon2id {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var params argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	if params.memory == 0 || params.iterations == 0 || params.parallelism == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	return &params, salt, key, nil
+}
@@ -292,3 +292,172 @@ func BenchmarkPasswordHasher_Verify_Failure(b *testing.B) {
 		hasher.Verify(wrongPassword, hash)
 	}
 }
+
+func testArgon2Hasher(t *testing.T) *PasswordHasher {
+	t.Helper()
+	hasher, err := NewPasswordHasherWithConfig(HasherConfig{
+		Algorithm:         HashAlgorithmArgon2id,
+		Argon2Memory:      1024,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewPasswordHasherWithConfig() error = %v", err)
+	}
+	return hasher
+}
+
+func TestNewPasswordHasherWithConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     HasherConfig
+		wantErr bool
+	}{
+		{name: "zero config is default bcrypt", cfg: HasherConfig{}},
+		{name: "bcrypt cost", cfg: HasherConfig{Algorithm: "BCRYPT", BcryptCost: 10}},
+		{name: "bcrypt cost too low", cfg: HasherConfig{BcryptCost: 2}, wantErr: true},
+		{name: "bcrypt cost too high", cfg: HasherConfig{BcryptCost: 32}, wantErr: true},
+		{name: "argon2id defaults", cfg: HasherConfig{Algorithm: HashAlgorithmArgon2id}},
+		{name: "argon2id memory too low", cfg: HasherConfig{Algorithm: HashAlgorithmArgon2id, Argon2Memory: 8, Argon2Parallelism: 4}, wantErr: true},
+		{name: "unknown algorithm", cfg: HasherConfig{Algorithm: "scrypt"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPasswordHasherWithConfig(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewPasswordHasherWithConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	hasher, _ := NewPasswordHasherWithConfig(HasherConfig{Algorithm: HashAlgorithmArgon2id})
+	if hasher.memory != DefaultArgon2Memory || hasher.iterations != DefaultArgon2Iterations || hasher.parallelism != DefaultArgon2Parallelism {
+		t.Errorf("argon2id params = %d/%d/%d, want defaults", hasher.memory, hasher.iterations, hasher.parallelism)
+	}
+}
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	hasher := testArgon2Hasher(t)
+
+	hash, err := hasher.Hash("password123")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("Hash() = %q, want PHC-encoded argon2id", hash)
+	}
+	if !hasher.Verify("password123", hash) {
+		t.Error("Verify() failed for correct password")
+	}
+	if hasher.Verify("wrongpassword", hash) {
+		t.Error("Verify() succeeded for wrong password")
+	}
+
+	other, _ := hasher.Hash("password123")
+	if other == hash {
+		t.Error("Hash() should use a random salt")
+	}
+
+	// Hashes stay verifiable by a hasher configured differently
+	if !NewPasswordHasher().Verify("password123", hash) {
+		t.Error("bcrypt hasher failed to verify argon2id hash")
+	}
+}
+
+func TestPasswordHasher_VerifiesBcryptWhenConfiguredForArgon2id(t *testing.T) {
+	bcryptHasher, _ := NewPasswordHasherWithConfig(HasherConfig{BcryptCost: 4})
+	hash, _ := bcryptHasher.Hash("password123")
+
+	if !testArgon2Hasher(t).Verify("password123", hash) {
+		t.Error("argon2id hasher failed to verify bcrypt hash")
+	}
+}
+
+func TestPasswordHasher_VerifyMalformedArgon2id(t *testing.T) {
+	hasher := testArgon2Hasher(t)
+	hash, _ := hasher.Hash("password123")
+	parts := strings.Split(hash, "$")
+
+	malformed := []string{
+		"$argon2id$",
+		"$argon2id$v=18$" + strings.Join(parts[3:], "$"),
+		"$argon2id$v=19$m=0,t=1,p=1$" + strings.Join(parts[4:], "$"),
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$" + parts[5],
+		"$argon2id$v=19$m=1024,t=1,p=1$" + parts[4] + "$",
+	}
+	for _, h := range malformed {
+		if hasher.Verify("password123", h) {
+			t.Errorf("Verify() succeeded for malformed hash %q", h)
+		}
+		if !hasher.NeedsRehash(h) {
+			t.Errorf("NeedsRehash(%q) = false, want true", h)
+		}
+	}
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	bcrypt4, _ := NewPasswordHasherWithConfig(HasherConfig{BcryptCost: 4})
+	bcrypt5, _ := NewPasswordHasherWithConfig(HasherConfig{BcryptCost: 5})
+	argon := testArgon2Hasher(t)
+	argonMoreMemory, _ := NewPasswordHasherWithConfig(HasherConfig{
+		Algorithm: HashAlgorithmArgon2id, Argon2Memory: 2048, Argon2Iterations: 1, Argon2Parallelism: 1,
+	})
+
+	bcryptHash, _ := bcrypt4.Hash("password123")
+	argonHash, _ := argon.Hash("password123")
+
+	tests := []struct {
+		name   string
+		hasher *PasswordHasher
+		hash   string
+		want   bool
+	}{
+		{name: "bcrypt same cost", hasher: bcrypt4, hash: bcryptHash, want: false},
+		{name: "bcrypt different cost", hasher: bcrypt5, hash: bcryptHash, want: true},
+		{name: "bcrypt to argon2id", hasher: argon, hash: bcryptHash, want: true},
+		{name: "argon2id same params", hasher: argon, hash: argonHash, want: false},
+		{name: "argon2id different params", hasher: argonMoreMemory, hash: argonHash, want: true},
+		{name: "argon2id to bcrypt", hasher: bcrypt4, hash: argonHash, want: true},
+		{name: "invalid hash", hasher: bcrypt4, hash: "invalid", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkPasswordHasher shows how the tuning knobs trade login latency for
+// brute-force resistance. Run with: go test -bench PasswordHasher ./internal/security
+func BenchmarkPasswordHasher(b *testing.B) {
+	configs := []struct {
+		name string
+		cfg  HasherConfig
+	}{
+		{name: "bcrypt/cost=10", cfg: HasherConfig{BcryptCost: 10}},
+		{name: "bcrypt/cost=12", cfg: HasherConfig{BcryptCost: 12}},
+		{name: "bcrypt/cost=14", cfg: HasherConfig{BcryptCost: 14}},
+		{name: "argon2id/m=19MiB,t=2,p=1", cfg: HasherConfig{Algorithm: HashAlgorithmArgon2id, Argon2Memory: 19 * 1024, Argon2Iterations: 2, Argon2Parallelism: 1}},
+		{name: "argon2id/m=64MiB,t=3,p=2", cfg: HasherConfig{Algorithm: HashAlgorithmArgon2id, Argon2Memory: 64 * 1024, Argon2Iterations: 3, Argon2Parallelism: 2}},
+		{name: "argon2id/m=64MiB,t=3,p=4", cfg: HasherConfig{Algorithm: HashAlgorithmArgon2id, Argon2Memory: 64 * 1024, Argon2Iterations: 3, Argon2Parallelism: 4}},
+		{name: "argon2id/m=256MiB,t=4,p=4", cfg: HasherConfig{Algorithm: HashAlgorithmArgon2id, Argon2Memory: 256 * 1024, Argon2Iterations: 4, Argon2Parallelism: 4}},
+	}
+
+	for _, c := range configs {
+		hasher, err := NewPasswordHasherWithConfig(c.cfg)
+		if err != nil {
+			b.Fatalf("%s: %v", c.name, err)
+		}
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := hasher.Hash("C0mpl3x!P@ssw0rd#2024"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package security
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+// Permissions guarding destructive or sensitive operations. A permission is
+// written resource:action; "resource:*" grants every action on a resource and
+// "*" grants everything.
+const (
+	PermissionPluginsInstall      = "plugins:install"
+	PermissionPluginsManage       = "plugins:manage"
+	PermissionPluginsUninstall    = "plugins:uninstall"
+	PermissionJobsManageQueues    = "jobs:manage_queues"
+	PermissionJobsPurgeDLQ        = "jobs:purge_dlq"
+	PermissionJobsManageWorkers   = "jobs:manage_workers"
+	PermissionJobsManageSchedules = "jobs:manage_schedules"
+	PermissionJobsRunCleanup      = "jobs:run_cleanup"
+	PermissionAuditRead           = "audit:read"
+	PermissionReportsRead         = "reports:read"
+
+	PermissionAll = "*"
+)
+
+// RolePermissions maps each role to the permissions it grants
+type RolePermissions map[entity.UserRole][]string
+
+// DefaultRolePermissions returns the built-in role to permission mapping:
+// admins may do anything and users hold no extra permissions
+func DefaultRolePermissions() RolePermissions {
+	return RolePermissions{
+		entity.RoleAdmin: {PermissionAll},
+		entity.RoleUser:  {},
+	}
+}
+
+// NewRolePermissions returns the default mapping with the roles in overrides
+// replaced. Role names are matched case-insensitively, since configuration
+// keys are lowercased when loaded.
+func NewRolePermissions(overrides map[string][]string) RolePermissions {
+	permissions := DefaultRolePermissions()
+	for role, granted := range overrides {
+		permissions[entity.UserRole(strings.ToUpper(role))] = slices.Clone(granted)
+	}
+	return permissions
+}
+
+// For returns the permissions granted to role
+func (p RolePermissions) For(role entity.UserRole) []string {
+	return slices.Clone(p[role])
+}
+
+// GrantsPermission reports whether the granted permissions include
+// permission, directly or through a wildcard
+func GrantsPermission(granted []string, permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, g := range granted {
+		if g == permission || g == PermissionAll || g == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+)
+
+func TestGrantsPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		granted    []string
+		permission string
+		expected   bool
+	}{
+		{"exact match", []string{"jobs:purge_dlq"}, "jobs:purge_dlq", true},
+		{"other permission", []string{"jobs:manage_queues"}, "jobs:purge_dlq", false},
+		{"resource wildcard", []string{"jobs:*"}, "jobs:purge_dlq", true},
+		{"other resource wildcard", []string{"plugins:*"}, "jobs:purge_dlq", false},
+		{"global wildcard", []string{PermissionAll}, "plugins:install", true},
+		{"none granted", nil, "plugins:install", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GrantsPermission(tt.granted, tt.permission); got != tt.expected {
+				t.Errorf("GrantsPermission(%v, %q) = %v, want %v", tt.granted, tt.permission, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewRolePermissions(t *testing.T) {
+	defaults := NewRolePermissions(nil)
+	if !GrantsPermission(defaults.For(entity.RoleAdmin), PermissionJobsPurgeDLQ) {
+		t.Error("admins should hold every permission by default")
+	}
+	if len(defaults.For(entity.RoleUser)) != 0 {
+		t.Errorf("users should hold no permissions by default, got %v", defaults.For(entity.RoleUser))
+	}
+
+	// Configuration keys arrive lowercased
+	overridden := NewRolePermissions(map[string][]string{"user": {PermissionPluginsInstall}})
+	if !GrantsPermission(overridden.For(entity.RoleUser), PermissionPluginsInstall) {
+		t.Errorf("override not applied: %v", overridden.For(entity.RoleUser))
+	}
+	if !GrantsPermission(overridden.For(entity.RoleAdmin), PermissionPluginsInstall) {
+		t.Error("roles without an override should keep their defaults")
+	}
+	if overridden.For(entity.RoleService) != nil {
+		t.Error("unknown roles should hold no permissions")
+	}
+}
+
+func TestJWTProvider_AccessTokenPermissions(t *testing.T) {
+	provider := newTestJWTProvider()
+	provider.SetRolePermissions(NewRolePermissions(map[string][]string{"USER": {PermissionJobsManageQueues}}))
+
+	token, err := provider.GenerateAccessToken(&entity.User{ID: 1, Username: "user", Role: entity.RoleUser})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+	claims, err := provider.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	if len(claims.Permissions) != 1 || claims.Permissions[0] != PermissionJobsManageQueues {
+		t.Errorf("Permissions = %v, want [%s]", claims.Permissions, PermissionJobsManageQueues)
+	}
+}
@@ -1,9 +1,12 @@
 package security
 
 import (
+	"slices"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
 )
 
 const (
@@ -16,6 +19,7 @@ const (
 // SecurityService provides security-related utilities
 type SecurityService struct {
 	jwtProvider *JWTProvider
+	apiKeyRepo  repository.APIKeyRepository
 }
 
 // NewSecurityService creates a new SecurityService instance
@@ -23,6 +27,11 @@ func NewSecurityService(jwtProvider *JWTProvider) *SecurityService {
 	return &SecurityService{jwtProvider: jwtProvider}
 }
 
+// SetAPIKeyRepository enables ValidateAPIKey
+func (s *SecurityService) SetAPIKeyRepository(apiKeyRepo repository.APIKeyRepository) {
+	s.apiKeyRepo = apiKeyRepo
+}
+
 // GetCurrentUser retrieves the current user from the context
 func (s *SecurityService) GetCurrentUser(c *gin.Context) *entity.User {
 	user, exists := c.Get(ContextKeyUser)
@@ -84,3 +93,37 @@ func (s *SecurityService) HasRole(c *gin.Context, role entity.UserRole) bool {
 func (s *SecurityService) IsAdmin(c *gin.Context) bool {
 	return s.HasRole(c, entity.RoleAdmin)
 }
+
+// HasPermission checks if the current caller holds the permission: for users
+// through the permissions in their access token, for API keys through the
+// key's scopes
+func (s *SecurityService) HasPermission(c *gin.Context, permission string) bool {
+	claims := s.GetCurrentClaims(c)
+	if claims == nil {
+		return false
+	}
+	if claims.APIKeyID != 0 {
+		return GrantsPermission(claims.Scopes, permission)
+	}
+	return GrantsPermission(claims.Permissions, permission)
+}
+
+// IsAPIKeyPrincipal checks if the current request was authenticated with an
+// API key
+func (s *SecurityService) IsAPIKeyPrincipal(c *gin.Context) bool {
+	claims := s.GetCurrentClaims(c)
+	return claims != nil && claims.APIKeyID != 0
+}
+
+// HasScope checks if the current caller may use scope. Scopes restrict API
+// keys only; users are governed by their role.
+func (s *SecurityService) HasScope(c *gin.Context, scope string) bool {
+	claims := s.GetCurrentClaims(c)
+	if claims == nil {
+		return false
+	}
+	if claims.APIKeyID == 0 {
+		return true
+	}
+	return slices.Contains(claims.Scopes, scope)
+}
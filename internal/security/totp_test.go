@@ -0,0 +1,171 @@
+package security
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/config"
+)
+
+// rfc6238Secret is the base32 encoding of the RFC 6238 SHA-1 test key
+// "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTestTOTPProvider() *TOTPProvider {
+	return NewTOTPProvider(&config.TOTPConfig{Issuer: "Arcana Test"}, "fallback-key")
+}
+
+func TestGenerateTOTPCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateTOTPCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateTOTPCode() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("GenerateTOTPCode(t=%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, _ := GenerateTOTPCode(rfc6238Secret, now)
+
+	tests := []struct {
+		name string
+		code string
+		at   time.Time
+		want bool
+	}{
+		{"current step", code, now, true},
+		{"previous step", code, now.Add(totpPeriod), true},
+		{"next step", code, now.Add(-totpPeriod), true},
+		{"outside skew", code, now.Add(3 * totpPeriod), false},
+		{"wrong code", "000000", now, false},
+		{"wrong length", code[:5], now, false},
+		{"empty", "", now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateTOTPCode(rfc6238Secret, tt.code, tt.at); got != tt.want {
+				t.Errorf("ValidateTOTPCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if ValidateTOTPCode("not base32!", code, now) {
+		t.Error("ValidateTOTPCode() accepted an invalid secret")
+	}
+}
+
+func TestTOTPProvider_GenerateKey(t *testing.T) {
+	provider := newTestTOTPProvider()
+
+	key, err := provider.GenerateKey("user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	if key.EncryptedSecret == key.Secret || strings.Contains(key.EncryptedSecret, key.Secret) {
+		t.Error("EncryptedSecret must not contain the plain secret")
+	}
+
+	u, err := url.Parse(key.URL)
+	if err != nil {
+		t.Fatalf("URL %q is invalid: %v", key.URL, err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Errorf("URL = %s, want otpauth://totp/...", key.URL)
+	}
+	if u.Path != "/Arcana Test:user@example.com" {
+		t.Errorf("label = %q, want %q", u.Path, "/Arcana Test:user@example.com")
+	}
+	if got := u.Query().Get("secret"); got != key.Secret {
+		t.Errorf("secret = %s, want %s", got, key.Secret)
+	}
+	if got := u.Query().Get("issuer"); got != "Arcana Test" {
+		t.Errorf("issuer = %s, want Arcana Test", got)
+	}
+
+	other, _ := provider.GenerateKey("user@example.com")
+	if other.Secret == key.Secret {
+		t.Error("GenerateKey() returned the same secret twice")
+	}
+}
+
+func TestTOTPProvider_Validate(t *testing.T) {
+	provider := newTestTOTPProvider()
+	key, _ := provider.GenerateKey("user@example.com")
+
+	code, _ := GenerateTOTPCode(key.Secret, time.Now())
+	valid, err := provider.Validate(key.EncryptedSecret, code)
+	if err != nil || !valid {
+		t.Errorf("Validate() = %v, %v, want true", valid, err)
+	}
+
+	// A provider with another key cannot decrypt the secret
+	other := NewTOTPProvider(&config.TOTPConfig{Issuer: "Arcana Test", EncryptionKey: "other-key"}, "fallback-key")
+	if _, err := other.Validate(key.EncryptedSecret, code); err != ErrInvalidCiphertext {
+		t.Errorf("Validate() with another key error = %v, want ErrInvalidCiphertext", err)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("len = %d, want 10", len(codes))
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("code %q, want xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestSecretCipher_RoundTrip(t *testing.T) {
+	cipher := NewSecretCipher("passphrase")
+
+	encrypted, err := cipher.Encrypt("secret value")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	again, _ := cipher.Encrypt("secret value")
+	if again == encrypted {
+		t.Error("Encrypt() must use a random nonce")
+	}
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	if err != nil || decrypted != "secret value" {
+		t.Errorf("Decrypt() = %q, %v, want %q", decrypted, err, "secret value")
+	}
+
+	for _, bad := range []string{"", "not base64!", "c2hvcnQ=", encrypted[:len(encrypted)-4] + "AAAA"} {
+		if _, err := cipher.Decrypt(bad); err != ErrInvalidCiphertext {
+			t.Errorf("Decrypt(%q) error = %v, want ErrInvalidCiphertext", bad, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// defaultS3Timeout bounds a whole request, including its body
+const defaultS3Timeout = 5 * time.Minute
+
+// S3Config holds the bucket artifacts are kept in. Any S3-compatible service
+// works, e.g. MinIO with PathStyle.
+type S3Config struct {
+	// Endpoint is the service URL; empty uses AWS for Region
+	Endpoint string `mapstructure:"endpoint"`
+	Region   string `mapstructure:"region"`
+	Bucket   string `mapstructure:"bucket"`
+	// AccessKeyID and SecretAccessKey, with SessionToken for temporary keys,
+	// are used as given. Without them credentials come from the default AWS
+	// chain: the environment, a web identity token, the shared config and
+	// credentials files, the ECS container endpoint or the EC2 instance role.
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	SessionToken    string `mapstructure:"session_token"`
+	// Prefix is prepended to every key, e.g. "arcana/"
+	Prefix string `mapstructure:"prefix"`
+	// PathStyle addresses the bucket in the path instead of the host name
+	PathStyle bool `mapstructure:"path_style"`
+	// Timeout bounds each request, including streaming its body: the object
+	// of a download or a part of an upload. Zero uses 5 minutes.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// S3Store keeps artifacts in an S3 bucket through the AWS SDK, which signs
+// requests and retries those that fail transiently
+type S3Store struct {
+	config   S3Config
+	client   *s3.Client
+	uploader *manager.Uploader
+}
+
+// NewS3Store creates a store for the configured bucket
+func NewS3Store(config S3Config) (*S3Store, error) {
+	if config.Bucket == "" || config.Region == "" {
+		return nil, errors.New("s3 storage requires a bucket and a region")
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultS3Timeout
+	}
+
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRegion(config.Region),
+		awsconfig.WithHTTPClient(newS3Client(timeout)),
+		// S3-compatible services do not all accept the flexible checksums
+		// the SDK otherwise adds to uploads
+		awsconfig.WithRequestChecksumCalculation(aws.RequestChecksumCalculationWhenRequired),
+	}
+	if config.AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			config.AccessKeyID, config.SecretAccessKey, config.SessionToken)))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			o.BaseEndpoint = aws.String(config.Endpoint)
+		}
+		o.UsePathStyle = config.PathStyle
+	})
+	return &S3Store{
+		config:   config,
+		client:   client,
+		uploader: manager.NewUploader(client),
+	}, nil
+}
+
+// newS3Client returns a client whose requests give up after timeout, and
+// sooner if the service stops responding
+func newS3Client(timeout time.Duration) *awshttp.BuildableClient {
+	return awshttp.NewBuildableClient().
+		WithTimeout(timeout).
+		WithTransportOptions(func(transport *http.Transport) {
+			transport.ResponseHeaderTimeout = time.Minute
+		})
+}
+
+// Put uploads the object. The content is streamed in parts, as a multipart
+// upload once it outgrows a single part, so only a few parts are held in
+// memory at a time.
+func (s *S3Store) Put(ctx context.Context, key, contentType string, r io.Reader) (int64, error) {
+	if !validKey(key) {
+		return 0, ErrInvalidKey
+	}
+
+	body := &countingReader{r: r}
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.config.Prefix + key),
+		Body:   body,
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if _, err := s.uploader.Upload(ctx, input); err != nil {
+		return 0, fmt.Errorf("s3 upload failed: %w", err)
+	}
+	return body.n, nil
+}
+
+// Open starts downloading the object; its body streams from the bucket
+func (s *S3Store) Open(ctx context.Context, key string) (*Object, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.config.Prefix + key),
+	})
+	if err != nil {
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("s3 download failed: %w", err)
+	}
+	return &Object{
+		Body:        out.Body,
+		ContentType: aws.ToString(out.ContentType),
+		Size:        aws.ToInt64(out.ContentLength),
+	}, nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
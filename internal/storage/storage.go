@@ -0,0 +1,46 @@
+// Package storage keeps generated artifacts, such as reports, in a local
+// directory or an S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Object is a stored artifact opened for reading. Close its Body.
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// Store keeps artifacts by key. Keys are slash-separated relative paths such
+// as "reports/<id>.csv".
+type Store interface {
+	// Put stores the content read from r under key, replacing any object
+	// there, and returns its size. r is streamed rather than read into memory.
+	Put(ctx context.Context, key, contentType string, r io.Reader) (int64, error)
+	// Open returns the object stored under key, or ErrNotFound
+	Open(ctx context.Context, key string) (*Object, error)
+}
+
+// validKey reports whether key is a relative path without empty, "." or
+// ".." segments, so it cannot escape the store
+func validKey(key string) bool {
+	if key == "" || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"reports/1.csv", true},
+		{"a.txt", true},
+		{"", false},
+		{"../secret", false},
+		{"reports/../../etc/passwd", false},
+		{"/absolute", false},
+		{"reports//1.csv", false},
+		{`reports\1.csv`, false},
+	}
+	for _, tt := range tests {
+		if got := validKey(tt.key); got != tt.want {
+			t.Errorf("validKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLocalStore_PutOpen(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	ctx := context.Background()
+
+	size, err := store.Put(ctx, "reports/1.csv", "text/csv", strings.NewReader("a,b\n1,2\n"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if size != 8 {
+		t.Errorf("Put() size = %d, want 8", size)
+	}
+
+	object, err := store.Open(ctx, "reports/1.csv")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer object.Body.Close()
+	body, _ := io.ReadAll(object.Body)
+	if string(body) != "a,b\n1,2\n" {
+		t.Errorf("Open() body = %q", body)
+	}
+	if object.Size != 8 {
+		t.Errorf("Open() size = %d, want 8", object.Size)
+	}
+	if !strings.HasPrefix(object.ContentType, "text/csv") {
+		t.Errorf("Open() content type = %q, want text/csv", object.ContentType)
+	}
+}
+
+func TestLocalStore_Errors(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := store.Open(ctx, "reports/missing.csv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() missing error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Put(ctx, "../escape.csv", "", strings.NewReader("x")); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Put() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := store.Open(ctx, "../escape.csv"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Open() error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestLocalStore_FailedPutKeepsPrevious(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := store.Put(ctx, "a.txt", "", strings.NewReader("old")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	failing := io.MultiReader(strings.NewReader("partial"), errReader{})
+	if _, err := store.Put(ctx, "a.txt", "", failing); err == nil {
+		t.Fatal("Put() expected error from the reader")
+	}
+
+	object, err := store.Open(ctx, "a.txt")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer object.Body.Close()
+	body, _ := io.ReadAll(object.Body)
+	if string(body) != "old" {
+		t.Errorf("body = %q, want the previous content", body)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("read failed") }
+
+func TestNewS3Store_RequiresBucketAndRegion(t *testing.T) {
+	if _, err := NewS3Store(S3Config{Region: "us-east-1"}); err == nil {
+		t.Error("NewS3Store() expected error without a bucket")
+	}
+	if _, err := NewS3Store(S3Config{Bucket: "bucket"}); err == nil {
+		t.Error("NewS3Store() expected error without a region")
+	}
+}
+
+// newFakeS3 serves PUT and GET of whole objects for requests signed with
+// accessKeyID, keyed by URL path
+func newFakeS3(t *testing.T, accessKeyID string) (*httptest.Server, map[string]string) {
+	var mu sync.Mutex
+	objects := map[string]string{}
+	types := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			if r.ContentLength < 0 {
+				w.WriteHeader(http.StatusLengthRequired)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(body)
+			types[r.URL.Path] = r.Header.Get("Content-Type")
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", types[r.URL.Path])
+			io.WriteString(w, body)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, objects
+}
+
+func TestS3Store_PutOpen(t *testing.T) {
+	server, objects := newFakeS3(t, "key")
+	store, err := NewS3Store(S3Config{
+		Endpoint: server.URL, Region: "us-east-1", Bucket: "bucket", Prefix: "arcana/",
+		AccessKeyID: "key", SecretAccessKey: "secret", PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store() error = %v", err)
+	}
+	ctx := context.Background()
+
+	size, err := store.Put(ctx, "reports/1.json", "application/json", strings.NewReader(`[{"id":1}]`))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if size != 10 {
+		t.Errorf("Put() size = %d, want 10", size)
+	}
+	if got := objects["/bucket/arcana/reports/1.json"]; got != `[{"id":1}]` {
+		t.Errorf("object at the bucket path = %q, got %v", got, objects)
+	}
+
+	object, err := store.Open(ctx, "reports/1.json")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer object.Body.Close()
+	body, _ := io.ReadAll(object.Body)
+	if string(body) != `[{"id":1}]` || object.ContentType != "application/json" || object.Size != 10 {
+		t.Errorf("Open() = %q (%s, %d bytes)", body, object.ContentType, object.Size)
+	}
+
+	if _, err := store.Open(ctx, "reports/missing.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() missing error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3Store_PutReportsFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>AccessDenied</Code></Error>")
+	}))
+	defer server.Close()
+
+	store, _ := NewS3Store(S3Config{
+		Endpoint: server.URL, Region: "us-east-1", Bucket: "bucket",
+		AccessKeyID: "key", SecretAccessKey: "secret", PathStyle: true,
+	})
+	_, err := store.Put(context.Background(), "a.txt", "", strings.NewReader("x"))
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Put() error = %v, want the S3 error", err)
+	}
+}
+
+func TestS3Store_PutRetriesTransientFailures(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	var stored string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, "<Error><Code>SlowDown</Code></Error>")
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		stored = string(body)
+	}))
+	defer server.Close()
+
+	store, _ := NewS3Store(S3Config{
+		Endpoint: server.URL, Region: "us-east-1", Bucket: "bucket",
+		AccessKeyID: "key", SecretAccessKey: "secret", PathStyle: true,
+	})
+	if _, err := store.Put(context.Background(), "a.txt", "text/plain", strings.NewReader("retried")); err != nil {
+		t.Fatalf("Put() error = %v, want success after a retry", err)
+	}
+	if attempts != 2 || stored != "retried" {
+		t.Errorf("attempts = %d, stored = %q; want the whole body sent again", attempts, stored)
+	}
+}
+
+func TestS3Store_DefaultCredentialChain(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	server, _ := newFakeS3(t, "env-key")
+	store, err := NewS3Store(S3Config{Endpoint: server.URL, Region: "us-east-1", Bucket: "bucket", PathStyle: true})
+	if err != nil {
+		t.Fatalf("NewS3Store() error = %v", err)
+	}
+	if _, err := store.Put(context.Background(), "a.txt", "", strings.NewReader("x")); err != nil {
+		t.Errorf("Put() error = %v, want the environment credentials used", err)
+	}
+}
@@ -2,27 +2,39 @@ package mocks
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"gorm.io/gorm"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/dao"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/repository"
 )
 
 // MockUserRepository is a mock implementation of UserRepository
 type MockUserRepository struct {
-	mu     sync.RWMutex
-	users  map[uint]*entity.User
-	nextID uint
+	mu      sync.RWMutex
+	users   map[uint]*entity.User
+	deleted map[uint]*entity.User
+	nextID  uint
 
 	// Error injection
 	CreateErr              error
 	GetByIDErr             error
+	RestoreErr             error
 	GetByUsernameErr       error
 	GetByEmailErr          error
 	GetByUsernameOrEmailErr error
 	UpdateErr              error
 	DeleteErr              error
 	ListErr                error
+	ListAfterErr           error
+	SearchErr              error
 	ExistsByUsernameErr    error
 	ExistsByEmailErr       error
 }
@@ -31,8 +43,9 @@ var _ repository.UserRepository = (*MockUserRepository)(nil)
 
 func NewMockUserRepository() *MockUserRepository {
 	return &MockUserRepository{
-		users:  make(map[uint]*entity.User),
-		nextID: 1,
+		users:   make(map[uint]*entity.User),
+		deleted: make(map[uint]*entity.User),
+		nextID:  1,
 	}
 }
 
@@ -108,7 +121,11 @@ func (r *MockUserRepository) Update(ctx context.Context, user *entity.User) erro
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.users[user.ID]; ok {
+	if stored, ok := r.users[user.ID]; ok {
+		if stored.LockVersion != user.LockVersion {
+			return repository.ErrConcurrentModification
+		}
+		user.LockVersion++
 		r.users[user.ID] = user
 		return nil
 	}
@@ -121,10 +138,43 @@ func (r *MockUserRepository) Delete(ctx context.Context, id uint) error {
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if user, ok := r.users[id]; ok {
+		user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		r.deleted[id] = user
+	}
 	delete(r.users, id)
 	return nil
 }
 
+func (r *MockUserRepository) GetByIDIncludingDeleted(ctx context.Context, id uint) (*entity.User, error) {
+	if r.GetByIDErr != nil {
+		return nil, r.GetByIDErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	if user, ok := r.deleted[id]; ok {
+		return user, nil
+	}
+	return nil, nil
+}
+
+func (r *MockUserRepository) Restore(ctx context.Context, id uint) error {
+	if r.RestoreErr != nil {
+		return r.RestoreErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.deleted[id]; ok {
+		user.DeletedAt = gorm.DeletedAt{}
+		r.users[id] = user
+		delete(r.deleted, id)
+	}
+	return nil
+}
+
 func (r *MockUserRepository) List(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
 	if r.ListErr != nil {
 		return nil, 0, r.ListErr
@@ -150,6 +200,113 @@ func (r *MockUserRepository) List(ctx context.Context, page, size int) ([]*entit
 	return users[start:end], int64(len(r.users)), nil
 }
 
+func (r *MockUserRepository) ListIncludingDeleted(ctx context.Context, page, size int) ([]*entity.User, int64, error) {
+	if r.ListErr != nil {
+		return nil, 0, r.ListErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*entity.User, 0, len(r.users)+len(r.deleted))
+	for _, user := range r.users {
+		users = append(users, user)
+	}
+	for _, user := range r.deleted {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID > users[j].ID })
+
+	total := int64(len(users))
+	start := (page - 1) * size
+	if start >= len(users) {
+		return []*entity.User{}, total, nil
+	}
+	end := min(start+size, len(users))
+	return users[start:end], total, nil
+}
+
+func (r *MockUserRepository) ListAfter(ctx context.Context, cursor string, limit int) ([]*entity.User, string, error) {
+	if r.ListAfterErr != nil {
+		return nil, "", r.ListAfterErr
+	}
+	afterID, err := dao.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", repository.ErrInvalidCursor
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*entity.User, 0, len(r.users))
+	for id, item := range r.users {
+		if id > afterID {
+			users = append(users, item)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	if len(users) > limit+1 {
+		users = users[:limit+1]
+	}
+
+	result := dao.NewCursorResult(users, limit, func(e *entity.User) uint { return e.ID })
+	return result.Items, result.NextCursor, nil
+}
+
+func (r *MockUserRepository) Search(ctx context.Context, query *entity.UserQuery) ([]*entity.User, int64, error) {
+	if r.SearchErr != nil {
+		return nil, 0, r.SearchErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []*entity.User
+	for _, user := range r.users {
+		if matchesUserQuery(user, query) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		a, b := users[i], users[j]
+		if query.SortDesc {
+			a, b = b, a
+		}
+		switch query.SortBy {
+		case entity.UserSortUsername:
+			return a.Username < b.Username
+		case entity.UserSortEmail:
+			return a.Email < b.Email
+		case entity.UserSortCreatedAt:
+			return a.CreatedAt.Before(b.CreatedAt)
+		default:
+			return a.ID < b.ID
+		}
+	})
+
+	total := int64(len(users))
+	start := query.Offset()
+	if start >= len(users) {
+		return []*entity.User{}, total, nil
+	}
+	end := start + query.Size
+	if end > len(users) {
+		end = len(users)
+	}
+	return users[start:end], total, nil
+}
+
+func matchesUserQuery(user *entity.User, query *entity.UserQuery) bool {
+	switch {
+	case query.Role != nil && user.Role != *query.Role,
+		query.IsActive != nil && user.IsActive != *query.IsActive,
+		query.IsVerified != nil && user.IsVerified != *query.IsVerified,
+		query.CreatedAfter != nil && user.CreatedAt.Before(*query.CreatedAfter),
+		query.CreatedBefore != nil && !user.CreatedAt.Before(*query.CreatedBefore):
+		return false
+	case query.Text != "":
+		return strings.HasPrefix(user.Username, query.Text) || strings.HasPrefix(user.Email, query.Text)
+	}
+	return true
+}
+
 func (r *MockUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	if r.ExistsByUsernameErr != nil {
 		return false, r.ExistsByUsernameErr
@@ -189,6 +346,20 @@ func (r *MockUserRepository) AddUser(user *entity.User) {
 	r.users[user.ID] = user
 }
 
+// Snapshot captures the repository contents and returns a function restoring them
+func (r *MockUserRepository) Snapshot() func() {
+	r.mu.RLock()
+	users := maps.Clone(r.users)
+	deleted := maps.Clone(r.deleted)
+	nextID := r.nextID
+	r.mu.RUnlock()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.users, r.deleted, r.nextID = users, deleted, nextID
+	}
+}
+
 // MockRefreshTokenRepository is a mock implementation of RefreshTokenRepository
 type MockRefreshTokenRepository struct {
 	mu     sync.RWMutex
@@ -201,6 +372,9 @@ type MockRefreshTokenRepository struct {
 	RevokeByTokenErr     error
 	RevokeAllByUserIDErr error
 	DeleteExpiredErr     error
+	CountExpiredErr      error
+	ListActiveErr        error
+	RevokeByIDErr        error
 }
 
 var _ repository.RefreshTokenRepository = (*MockRefreshTokenRepository)(nil)
@@ -212,6 +386,19 @@ func NewMockRefreshTokenRepository() *MockRefreshTokenRepository {
 	}
 }
 
+// Snapshot captures the repository contents and returns a function restoring them
+func (r *MockRefreshTokenRepository) Snapshot() func() {
+	r.mu.RLock()
+	tokens := maps.Clone(r.tokens)
+	nextID := r.nextID
+	r.mu.RUnlock()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.tokens, r.nextID = tokens, nextID
+	}
+}
+
 func (r *MockRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
 	if r.CreateErr != nil {
 		return r.CreateErr
@@ -267,11 +454,65 @@ func (r *MockRefreshTokenRepository) RevokeAllByUserID(ctx context.Context, user
 	return nil
 }
 
-func (r *MockRefreshTokenRepository) DeleteExpired(ctx context.Context) error {
+func (r *MockRefreshTokenRepository) ListActiveByUserID(ctx context.Context, userID uint) ([]*entity.RefreshToken, error) {
+	if r.ListActiveErr != nil {
+		return nil, r.ListActiveErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var tokens []*entity.RefreshToken
+	for _, rt := range r.tokens {
+		if rt.UserID == userID && rt.IsValid() {
+			tokens = append(tokens, rt)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].ID > tokens[j].ID })
+	return tokens, nil
+}
+
+func (r *MockRefreshTokenRepository) RevokeByID(ctx context.Context, userID, id uint) (bool, error) {
+	if r.RevokeByIDErr != nil {
+		return false, r.RevokeByIDErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rt, ok := r.tokens[id]
+	if !ok || rt.UserID != userID || rt.Revoked {
+		return false, nil
+	}
+	rt.Revoked = true
+	return true, nil
+}
+
+func (r *MockRefreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
 	if r.DeleteExpiredErr != nil {
-		return r.DeleteExpiredErr
+		return 0, r.DeleteExpiredErr
 	}
-	return nil
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, rt := range r.tokens {
+		if rt.ExpiresAt.Before(before) {
+			delete(r.tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *MockRefreshTokenRepository) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	if r.CountExpiredErr != nil {
+		return 0, r.CountExpiredErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int64
+	for _, rt := range r.tokens {
+		if rt.ExpiresAt.Before(before) {
+			count++
+		}
+	}
+	return count, nil
 }
 
 // AddToken adds a token directly (for test setup)
@@ -285,6 +526,223 @@ func (r *MockRefreshTokenRepository) AddToken(token *entity.RefreshToken) {
 	r.tokens[token.ID] = token
 }
 
+// MockPasswordResetTokenRepository is a mock implementation of PasswordResetTokenRepository
+type MockPasswordResetTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[uint]*entity.PasswordResetToken
+	nextID uint
+
+	// Error injection
+	CreateErr                error
+	GetByTokenHashErr        error
+	MarkUsedErr              error
+	InvalidateAllByUserIDErr error
+	DeleteExpiredErr         error
+	CountExpiredErr          error
+}
+
+var _ repository.PasswordResetTokenRepository = (*MockPasswordResetTokenRepository)(nil)
+
+func NewMockPasswordResetTokenRepository() *MockPasswordResetTokenRepository {
+	return &MockPasswordResetTokenRepository{
+		tokens: make(map[uint]*entity.PasswordResetToken),
+		nextID: 1,
+	}
+}
+
+func (r *MockPasswordResetTokenRepository) Create(ctx context.Context, token *entity.PasswordResetToken) error {
+	if r.CreateErr != nil {
+		return r.CreateErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token.ID = r.nextID
+	r.nextID++
+	r.tokens[token.ID] = token
+	return nil
+}
+
+func (r *MockPasswordResetTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	if r.GetByTokenHashErr != nil {
+		return nil, r.GetByTokenHashErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MockPasswordResetTokenRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	if r.MarkUsedErr != nil {
+		return false, r.MarkUsedErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[id]
+	if !ok || t.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	return true, nil
+}
+
+func (r *MockPasswordResetTokenRepository) InvalidateAllByUserID(ctx context.Context, userID uint) error {
+	if r.InvalidateAllByUserIDErr != nil {
+		return r.InvalidateAllByUserIDErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *MockPasswordResetTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	if r.DeleteExpiredErr != nil {
+		return 0, r.DeleteExpiredErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, t := range r.tokens {
+		if t.ExpiresAt.Before(before) {
+			delete(r.tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *MockPasswordResetTokenRepository) CountExpired(ctx context.Context, before time.Time) (int64, error) {
+	if r.CountExpiredErr != nil {
+		return 0, r.CountExpiredErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int64
+	for _, t := range r.tokens {
+		if t.ExpiresAt.Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Tokens returns all stored tokens (for test assertions)
+func (r *MockPasswordResetTokenRepository) Tokens() []*entity.PasswordResetToken {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tokens := make([]*entity.PasswordResetToken, 0, len(r.tokens))
+	for _, t := range r.tokens {
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mu     sync.RWMutex
+	keys   map[uint]*entity.APIKey
+	nextID uint
+
+	// Error injection
+	CreateErr       error
+	GetByIDErr      error
+	GetByKeyHashErr error
+	ListErr         error
+	RevokeErr       error
+}
+
+var _ repository.APIKeyRepository = (*MockAPIKeyRepository)(nil)
+
+func NewMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{
+		keys:   make(map[uint]*entity.APIKey),
+		nextID: 1,
+	}
+}
+
+func (r *MockAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	if r.CreateErr != nil {
+		return r.CreateErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key.ID = r.nextID
+	r.nextID++
+	key.CreatedAt = time.Now()
+	r.keys[key.ID] = key
+	return nil
+}
+
+func (r *MockAPIKeyRepository) GetByID(ctx context.Context, id uint) (*entity.APIKey, error) {
+	if r.GetByIDErr != nil {
+		return nil, r.GetByIDErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.keys[id], nil
+}
+
+func (r *MockAPIKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	if r.GetByKeyHashErr != nil {
+		return nil, r.GetByKeyHashErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash {
+			return k, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MockAPIKeyRepository) List(ctx context.Context, page, size int) ([]*entity.APIKey, int64, error) {
+	if r.ListErr != nil {
+		return nil, 0, r.ListErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]*entity.APIKey, 0, len(r.keys))
+	for id := r.nextID - 1; id > 0; id-- {
+		if k, ok := r.keys[id]; ok {
+			keys = append(keys, k)
+		}
+	}
+	total := int64(len(keys))
+	start := (page - 1) * size
+	if start >= len(keys) {
+		return []*entity.APIKey{}, total, nil
+	}
+	end := min(start+size, len(keys))
+	return keys[start:end], total, nil
+}
+
+func (r *MockAPIKeyRepository) Revoke(ctx context.Context, id uint) (bool, error) {
+	if r.RevokeErr != nil {
+		return false, r.RevokeErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k, ok := r.keys[id]
+	if !ok || k.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	k.RevokedAt = &now
+	return true, nil
+}
+
 // MockPluginRepository is a mock implementation of PluginRepository
 type MockPluginRepository struct {
 	mu      sync.RWMutex
@@ -299,10 +757,13 @@ type MockPluginRepository struct {
 	DeleteErr       error
 	DeleteByKeyErr  error
 	ListErr         error
+	ListAfterErr    error
 	ListByStateErr  error
 	ListEnabledErr  error
 	ExistsByKeyErr  error
 	UpdateStateErr  error
+	PurgeDeletedErr error
+	CountDeletedErr error
 }
 
 var _ repository.PluginRepository = (*MockPluginRepository)(nil)
@@ -358,7 +819,11 @@ func (r *MockPluginRepository) Update(ctx context.Context, plugin *entity.Plugin
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.plugins[plugin.ID]; ok {
+	if stored, ok := r.plugins[plugin.ID]; ok {
+		if stored.LockVersion != plugin.LockVersion {
+			return repository.ErrConcurrentModification
+		}
+		plugin.LockVersion++
 		r.plugins[plugin.ID] = plugin
 	}
 	return nil
@@ -413,6 +878,32 @@ func (r *MockPluginRepository) List(ctx context.Context, page, size int) ([]*ent
 	return plugins[start:end], int64(len(r.plugins)), nil
 }
 
+func (r *MockPluginRepository) ListAfter(ctx context.Context, cursor string, limit int) ([]*entity.Plugin, string, error) {
+	if r.ListAfterErr != nil {
+		return nil, "", r.ListAfterErr
+	}
+	afterID, err := dao.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", repository.ErrInvalidCursor
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plugins := make([]*entity.Plugin, 0, len(r.plugins))
+	for id, item := range r.plugins {
+		if id > afterID {
+			plugins = append(plugins, item)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].ID < plugins[j].ID })
+	if len(plugins) > limit+1 {
+		plugins = plugins[:limit+1]
+	}
+
+	result := dao.NewCursorResult(plugins, limit, func(e *entity.Plugin) uint { return e.ID })
+	return result.Items, result.NextCursor, nil
+}
+
 func (r *MockPluginRepository) ListByState(ctx context.Context, state entity.PluginState) ([]*entity.Plugin, error) {
 	if r.ListByStateErr != nil {
 		return nil, r.ListByStateErr
@@ -462,6 +953,39 @@ func (r *MockPluginRepository) UpdateState(ctx context.Context, id uint, state e
 	return nil
 }
 
+// PurgeDeleted removes the plugins added with a DeletedAt before the given
+// time; Delete and DeleteByKey remove plugins outright
+func (r *MockPluginRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if r.PurgeDeletedErr != nil {
+		return 0, r.PurgeDeletedErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var purged int64
+	for id, plugin := range r.plugins {
+		if plugin.DeletedAt.Valid && plugin.DeletedAt.Time.Before(before) {
+			delete(r.plugins, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *MockPluginRepository) CountDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if r.CountDeletedErr != nil {
+		return 0, r.CountDeletedErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int64
+	for _, plugin := range r.plugins {
+		if plugin.DeletedAt.Valid && plugin.DeletedAt.Time.Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // AddPlugin adds a plugin directly (for test setup)
 func (r *MockPluginRepository) AddPlugin(plugin *entity.Plugin) {
 	r.mu.Lock()
@@ -546,3 +1070,239 @@ func (r *MockPluginExtensionRepository) AddExtension(ext *entity.PluginExtension
 	}
 	r.extensions[ext.ID] = ext
 }
+
+// Snapshot captures the repository contents and returns a function restoring them
+func (r *MockPluginRepository) Snapshot() func() {
+	r.mu.RLock()
+	plugins := make(map[uint]*entity.Plugin, len(r.plugins))
+	for id, plugin := range r.plugins {
+		// UpdateState changes stored plugins in place, so keep copies
+		copied := *plugin
+		plugins[id] = &copied
+	}
+	nextID := r.nextID
+	r.mu.RUnlock()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.plugins, r.nextID = plugins, nextID
+	}
+}
+
+// MockPluginStateTransitionRepository is a mock implementation of PluginStateTransitionRepository
+type MockPluginStateTransitionRepository struct {
+	mu          sync.RWMutex
+	transitions []*entity.PluginStateTransition
+	nextID      uint
+
+	// Error injection
+	CreateErr         error
+	ListByPluginIDErr error
+}
+
+var _ repository.PluginStateTransitionRepository = (*MockPluginStateTransitionRepository)(nil)
+
+func NewMockPluginStateTransitionRepository() *MockPluginStateTransitionRepository {
+	return &MockPluginStateTransitionRepository{nextID: 1}
+}
+
+func (r *MockPluginStateTransitionRepository) Create(ctx context.Context, transition *entity.PluginStateTransition) error {
+	if r.CreateErr != nil {
+		return r.CreateErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	transition.ID = r.nextID
+	r.nextID++
+	transition.CreatedAt = time.Now()
+	r.transitions = append(r.transitions, transition)
+	return nil
+}
+
+func (r *MockPluginStateTransitionRepository) ListByPluginID(ctx context.Context, pluginID uint) ([]*entity.PluginStateTransition, error) {
+	if r.ListByPluginIDErr != nil {
+		return nil, r.ListByPluginIDErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entity.PluginStateTransition, 0)
+	for _, transition := range r.transitions {
+		if transition.PluginID == pluginID {
+			result = append(result, transition)
+		}
+	}
+	return result, nil
+}
+
+// Snapshot captures the repository contents and returns a function restoring them
+func (r *MockPluginStateTransitionRepository) Snapshot() func() {
+	r.mu.RLock()
+	transitions := slices.Clone(r.transitions)
+	nextID := r.nextID
+	r.mu.RUnlock()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.transitions, r.nextID = transitions, nextID
+	}
+}
+
+// Snapshotter is implemented by in-memory repositories that can roll back
+type Snapshotter interface {
+	Snapshot() func()
+}
+
+// MockAuditLogRepository is a mock implementation of AuditLogRepository
+type MockAuditLogRepository struct {
+	mu     sync.RWMutex
+	logs   []*entity.AuditLog
+	nextID uint
+
+	// Error injection
+	CreateErr       error
+	QueryErr        error
+	DeleteBeforeErr error
+	CountBeforeErr  error
+}
+
+var _ repository.AuditLogRepository = (*MockAuditLogRepository)(nil)
+
+func NewMockAuditLogRepository() *MockAuditLogRepository {
+	return &MockAuditLogRepository{nextID: 1}
+}
+
+func (r *MockAuditLogRepository) Create(ctx context.Context, log *entity.AuditLog) error {
+	if r.CreateErr != nil {
+		return r.CreateErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	log.ID = r.nextID
+	r.nextID++
+	log.CreatedAt = time.Now()
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func (r *MockAuditLogRepository) Query(ctx context.Context, query *entity.AuditQuery) ([]*entity.AuditLog, int64, error) {
+	if r.QueryErr != nil {
+		return nil, 0, r.QueryErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Newest first
+	matched := make([]*entity.AuditLog, 0)
+	for i := len(r.logs) - 1; i >= 0; i-- {
+		log := r.logs[i]
+		if query.ActorID != nil && log.ActorID != *query.ActorID {
+			continue
+		}
+		if query.Actor != "" && log.Actor != query.Actor {
+			continue
+		}
+		if query.Action != "" && log.Action != query.Action {
+			continue
+		}
+		if query.From != nil && log.CreatedAt.Before(*query.From) {
+			continue
+		}
+		if query.To != nil && !log.CreatedAt.Before(*query.To) {
+			continue
+		}
+		matched = append(matched, log)
+	}
+
+	total := int64(len(matched))
+	start := query.Offset()
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := min(start+query.Size, len(matched))
+	return matched[start:end], total, nil
+}
+
+func (r *MockAuditLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	if r.DeleteBeforeErr != nil {
+		return 0, r.DeleteBeforeErr
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.logs[:0]
+	for _, log := range r.logs {
+		if !log.CreatedAt.Before(before) {
+			kept = append(kept, log)
+		}
+	}
+	deleted := int64(len(r.logs) - len(kept))
+	clear(r.logs[len(kept):])
+	r.logs = kept
+	return deleted, nil
+}
+
+func (r *MockAuditLogRepository) CountBefore(ctx context.Context, before time.Time) (int64, error) {
+	if r.CountBeforeErr != nil {
+		return 0, r.CountBeforeErr
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int64
+	for _, log := range r.logs {
+		if log.CreatedAt.Before(before) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Logs returns the recorded audit logs, oldest first
+func (r *MockAuditLogRepository) Logs() []*entity.AuditLog {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.logs)
+}
+
+// MockTxManager is a mock implementation of TxManager. It restores the
+// given repositories when a unit of work fails, as a rollback would.
+type MockTxManager struct {
+	mu    sync.Mutex
+	repos []Snapshotter
+
+	Commits   int
+	Rollbacks int
+
+	// Error injection
+	WithTransactionErr error
+}
+
+var _ repository.TxManager = (*MockTxManager)(nil)
+
+func NewMockTxManager(repos ...Snapshotter) *MockTxManager {
+	return &MockTxManager{repos: repos}
+}
+
+func (m *MockTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.WithTransactionErr != nil {
+		return m.WithTransactionErr
+	}
+
+	restores := make([]func(), len(m.repos))
+	for i, repo := range m.repos {
+		restores[i] = repo.Snapshot()
+	}
+
+	err := fn(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		m.Rollbacks++
+		return err
+	}
+	m.Commits++
+	return nil
+}
@@ -0,0 +1,129 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
+)
+
+// MockTokenDenylist is an in-memory implementation of TokenDenylist
+type MockTokenDenylist struct {
+	mu           sync.RWMutex
+	tokens       map[string]time.Time
+	usersRevoked map[uint]time.Time
+	userTTLs     map[uint]time.Duration
+
+	// Error injection
+	RevokeErr    error
+	IsRevokedErr error
+}
+
+var _ security.TokenDenylist = (*MockTokenDenylist)(nil)
+
+func NewMockTokenDenylist() *MockTokenDenylist {
+	return &MockTokenDenylist{
+		tokens:       make(map[string]time.Time),
+		usersRevoked: make(map[uint]time.Time),
+		userTTLs:     make(map[uint]time.Duration),
+	}
+}
+
+func (d *MockTokenDenylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	if d.RevokeErr != nil {
+		return d.RevokeErr
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tokens[jti] = expiresAt
+	return nil
+}
+
+func (d *MockTokenDenylist) RevokeAllForUser(ctx context.Context, userID uint, ttl time.Duration) error {
+	if d.RevokeErr != nil {
+		return d.RevokeErr
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.usersRevoked[userID] = time.Now()
+	d.userTTLs[userID] = ttl
+	return nil
+}
+
+func (d *MockTokenDenylist) IsRevoked(ctx context.Context, claims *security.UserClaims) (bool, error) {
+	if d.IsRevokedErr != nil {
+		return false, d.IsRevokedErr
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if _, ok := d.tokens[claims.ID]; ok {
+		return true, nil
+	}
+	if revokedAt, ok := d.usersRevoked[claims.UserID]; ok {
+		return claims.IssuedAt == nil || claims.IssuedAt.Time.Before(revokedAt), nil
+	}
+	return false, nil
+}
+
+// RevokedTokens returns the revoked jtis and their expiry
+func (d *MockTokenDenylist) RevokedTokens() map[string]time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	tokens := make(map[string]time.Time, len(d.tokens))
+	for jti, expiresAt := range d.tokens {
+		tokens[jti] = expiresAt
+	}
+	return tokens
+}
+
+// UserRevocationTTL returns the TTL of the user's revocation, if any
+func (d *MockTokenDenylist) UserRevocationTTL(userID uint) (time.Duration, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	ttl, ok := d.userTTLs[userID]
+	return ttl, ok
+}
+
+// MockChallengeStore is an in-memory implementation of TwoFactorChallengeStore
+type MockChallengeStore struct {
+	mu       sync.Mutex
+	attempts map[string]int64
+	used     map[string]bool
+
+	// Error injection
+	RecordAttemptErr error
+	ConsumeErr       error
+}
+
+var _ security.TwoFactorChallengeStore = (*MockChallengeStore)(nil)
+
+func NewMockChallengeStore() *MockChallengeStore {
+	return &MockChallengeStore{
+		attempts: make(map[string]int64),
+		used:     make(map[string]bool),
+	}
+}
+
+func (s *MockChallengeStore) RecordAttempt(ctx context.Context, jti string, expiresAt time.Time) (int64, error) {
+	if s.RecordAttemptErr != nil {
+		return 0, s.RecordAttemptErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts[jti]++
+	return s.attempts[jti], nil
+}
+
+func (s *MockChallengeStore) Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	if s.ConsumeErr != nil {
+		return false, s.ConsumeErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.used[jti] {
+		return false, nil
+	}
+	s.used[jti] = true
+	return true, nil
+}
@@ -2,8 +2,11 @@ package mocks
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
@@ -15,11 +18,20 @@ import (
 
 // MockAuthService is a mock implementation of AuthService
 type MockAuthService struct {
-	RegisterFunc     func(ctx context.Context, req *request.RegisterRequest) (*response.AuthResponse, error)
-	LoginFunc        func(ctx context.Context, req *request.LoginRequest) (*response.AuthResponse, error)
-	RefreshTokenFunc func(ctx context.Context, req *request.RefreshTokenRequest) (*response.AuthResponse, error)
-	LogoutFunc       func(ctx context.Context, token string) error
-	LogoutAllFunc    func(ctx context.Context, userID uint) error
+	RegisterFunc             func(ctx context.Context, req *request.RegisterRequest) (*response.AuthResponse, error)
+	LoginFunc                func(ctx context.Context, req *request.LoginRequest) (*response.AuthResponse, error)
+	RefreshTokenFunc         func(ctx context.Context, req *request.RefreshTokenRequest) (*response.AuthResponse, error)
+	LogoutFunc               func(ctx context.Context, token string) error
+	LogoutAllFunc            func(ctx context.Context, userID uint) error
+	RequestPasswordResetFunc func(ctx context.Context, email string) (*service.PasswordReset, error)
+	ResetPasswordFunc        func(ctx context.Context, token, newPassword string) error
+	SendVerificationFunc     func(ctx context.Context, userID uint) (*service.EmailVerification, error)
+	VerifyEmailFunc          func(ctx context.Context, token string) error
+	EnableTOTPFunc           func(ctx context.Context, userID uint) (*service.TOTPSetup, error)
+	ConfirmTOTPFunc          func(ctx context.Context, userID uint, code string) ([]string, error)
+	VerifyTOTPFunc           func(ctx context.Context, challengeID, code string) (*response.AuthResponse, error)
+	ListSessionsFunc         func(ctx context.Context, userID uint) ([]*response.SessionResponse, error)
+	RevokeSessionFunc        func(ctx context.Context, userID, sessionID uint) error
 }
 
 func NewMockAuthService() *MockAuthService {
@@ -86,15 +98,95 @@ func (m *MockAuthService) LogoutAll(ctx context.Context, userID uint) error {
 	return nil
 }
 
+func (m *MockAuthService) RequestPasswordReset(ctx context.Context, email string) (*service.PasswordReset, error) {
+	if m.RequestPasswordResetFunc != nil {
+		return m.RequestPasswordResetFunc(ctx, email)
+	}
+	return nil, nil
+}
+
+func (m *MockAuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if m.ResetPasswordFunc != nil {
+		return m.ResetPasswordFunc(ctx, token, newPassword)
+	}
+	return nil
+}
+
+func (m *MockAuthService) SendVerification(ctx context.Context, userID uint) (*service.EmailVerification, error) {
+	if m.SendVerificationFunc != nil {
+		return m.SendVerificationFunc(ctx, userID)
+	}
+	return &service.EmailVerification{
+		UserID:    userID,
+		Email:     "test@example.com",
+		Token:     "mock-verification-token",
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}, nil
+}
+
+func (m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	if m.VerifyEmailFunc != nil {
+		return m.VerifyEmailFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockAuthService) EnableTOTP(ctx context.Context, userID uint) (*service.TOTPSetup, error) {
+	if m.EnableTOTPFunc != nil {
+		return m.EnableTOTPFunc(ctx, userID)
+	}
+	return &service.TOTPSetup{
+		Secret: "JBSWY3DPEHPK3PXP",
+		URL:    "otpauth://totp/Test:test%40example.com?issuer=Test&secret=JBSWY3DPEHPK3PXP",
+	}, nil
+}
+
+func (m *MockAuthService) ConfirmTOTP(ctx context.Context, userID uint, code string) ([]string, error) {
+	if m.ConfirmTOTPFunc != nil {
+		return m.ConfirmTOTPFunc(ctx, userID, code)
+	}
+	return []string{"abcde-fghij"}, nil
+}
+
+func (m *MockAuthService) VerifyTOTP(ctx context.Context, challengeID, code string) (*response.AuthResponse, error) {
+	if m.VerifyTOTPFunc != nil {
+		return m.VerifyTOTPFunc(ctx, challengeID, code)
+	}
+	return &response.AuthResponse{
+		AccessToken:  "mock-access-token",
+		RefreshToken: "mock-refresh-token",
+		TokenType:    "Bearer",
+		ExpiresIn:    3600,
+	}, nil
+}
+
+func (m *MockAuthService) ListSessions(ctx context.Context, userID uint) ([]*response.SessionResponse, error) {
+	if m.ListSessionsFunc != nil {
+		return m.ListSessionsFunc(ctx, userID)
+	}
+	return []*response.SessionResponse{}, nil
+}
+
+func (m *MockAuthService) RevokeSession(ctx context.Context, userID, sessionID uint) error {
+	if m.RevokeSessionFunc != nil {
+		return m.RevokeSessionFunc(ctx, userID, sessionID)
+	}
+	return nil
+}
+
 // MockUserService is a mock implementation of UserService
 type MockUserService struct {
 	GetByIDFunc         func(ctx context.Context, id uint) (*response.UserResponse, error)
 	GetByUsernameFunc   func(ctx context.Context, username string) (*response.UserResponse, error)
 	GetByEmailFunc      func(ctx context.Context, email string) (*response.UserResponse, error)
 	ListFunc            func(ctx context.Context, page, size int) (*response.PagedResponse[response.UserResponse], error)
+	ListByCursorFunc    func(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.UserResponse], error)
+	ListIncludingDeletedFunc func(ctx context.Context, page, size int) (*response.PagedResponse[response.UserResponse], error)
+	SearchFunc          func(ctx context.Context, req *request.UserSearchRequest) (*response.PagedResponse[response.UserResponse], error)
 	UpdateFunc          func(ctx context.Context, id uint, req *request.UpdateProfileRequest) (*response.UserResponse, error)
 	ChangePasswordFunc  func(ctx context.Context, id uint, req *request.ChangePasswordRequest) error
 	DeleteFunc          func(ctx context.Context, id uint) error
+	RestoreFunc         func(ctx context.Context, id uint) (*response.UserResponse, error)
 	ExistsByUsernameFunc func(ctx context.Context, username string) (bool, error)
 	ExistsByEmailFunc   func(ctx context.Context, email string) (bool, error)
 }
@@ -147,6 +239,38 @@ func (m *MockUserService) List(ctx context.Context, page, size int) (*response.P
 	return &resp, nil
 }
 
+func (m *MockUserService) ListIncludingDeleted(ctx context.Context, page, size int) (*response.PagedResponse[response.UserResponse], error) {
+	if m.ListIncludingDeletedFunc != nil {
+		return m.ListIncludingDeletedFunc(ctx, page, size)
+	}
+	resp := response.NewPagedResponse([]response.UserResponse{
+		{ID: 1, Username: "user1"},
+		{ID: 2, Username: "user2"},
+	}, page, size, 2)
+	return &resp, nil
+}
+
+func (m *MockUserService) ListByCursor(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.UserResponse], error) {
+	if m.ListByCursorFunc != nil {
+		return m.ListByCursorFunc(ctx, cursor, size)
+	}
+	resp := response.NewCursorPagedResponse([]response.UserResponse{
+		{ID: 1, Username: "user1"},
+		{ID: 2, Username: "user2"},
+	}, size, "")
+	return &resp, nil
+}
+
+func (m *MockUserService) Search(ctx context.Context, req *request.UserSearchRequest) (*response.PagedResponse[response.UserResponse], error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, req)
+	}
+	resp := response.NewPagedResponse([]response.UserResponse{
+		{ID: 1, Username: "user1"},
+	}, 1, 10, 1)
+	return &resp, nil
+}
+
 func (m *MockUserService) Update(ctx context.Context, id uint, req *request.UpdateProfileRequest) (*response.UserResponse, error) {
 	if m.UpdateFunc != nil {
 		return m.UpdateFunc(ctx, id, req)
@@ -174,6 +298,13 @@ func (m *MockUserService) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
+func (m *MockUserService) Restore(ctx context.Context, id uint) (*response.UserResponse, error) {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, id)
+	}
+	return &response.UserResponse{ID: id, Username: "testuser"}, nil
+}
+
 func (m *MockUserService) ExistsByUsername(ctx context.Context, username string) (bool, error) {
 	if m.ExistsByUsernameFunc != nil {
 		return m.ExistsByUsernameFunc(ctx, username)
@@ -192,11 +323,19 @@ func (m *MockUserService) ExistsByEmail(ctx context.Context, email string) (bool
 type MockPluginService struct {
 	InstallFunc         func(ctx context.Context, req *request.InstallPluginRequest, file io.Reader) (*response.PluginResponse, error)
 	InstallFromPathFunc func(ctx context.Context, req *request.InstallPluginRequest, filePath string) (*response.PluginResponse, error)
+	InstallFromURLFunc  func(ctx context.Context, req *request.InstallPluginFromURLRequest) (*response.PluginResponse, error)
 	GetByKeyFunc        func(ctx context.Context, key string) (*response.PluginDetailResponse, error)
 	ListFunc            func(ctx context.Context, page, size int) (*response.PagedResponse[response.PluginResponse], error)
+	ListByCursorFunc    func(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.PluginResponse], error)
 	EnableFunc          func(ctx context.Context, key string) (*response.PluginResponse, error)
 	DisableFunc         func(ctx context.Context, key string) (*response.PluginResponse, error)
+	UpgradeFunc         func(ctx context.Context, key string, req *request.UpgradePluginRequest, file io.Reader) (*response.PluginResponse, error)
+	RollbackFunc        func(ctx context.Context, key string) (*response.PluginResponse, error)
+	ValidateConfigFunc  func(ctx context.Context, key string, config map[string]any) error
+	UpdateConfigFunc    func(ctx context.Context, key string, config map[string]any) (*response.PluginResponse, error)
+	GetHistoryFunc      func(ctx context.Context, key string) ([]response.PluginStateTransitionResponse, error)
 	UninstallFunc       func(ctx context.Context, key string) error
+	RestoreRoutesFunc   func(ctx context.Context) error
 	GetHealthFunc       func(ctx context.Context) (*response.PluginHealthResponse, error)
 }
 
@@ -230,6 +369,19 @@ func (m *MockPluginService) InstallFromPath(ctx context.Context, req *request.In
 	}, nil
 }
 
+func (m *MockPluginService) InstallFromURL(ctx context.Context, req *request.InstallPluginFromURLRequest) (*response.PluginResponse, error) {
+	if m.InstallFromURLFunc != nil {
+		return m.InstallFromURLFunc(ctx, req)
+	}
+	return &response.PluginResponse{
+		ID:      1,
+		Key:     "test-plugin",
+		Name:    req.Name,
+		Version: req.Version,
+		State:   string(entity.PluginStateInstalled),
+	}, nil
+}
+
 func (m *MockPluginService) GetByKey(ctx context.Context, key string) (*response.PluginDetailResponse, error) {
 	if m.GetByKeyFunc != nil {
 		return m.GetByKeyFunc(ctx, key)
@@ -255,6 +407,17 @@ func (m *MockPluginService) List(ctx context.Context, page, size int) (*response
 	return &resp, nil
 }
 
+func (m *MockPluginService) ListByCursor(ctx context.Context, cursor string, size int) (*response.CursorPagedResponse[response.PluginResponse], error) {
+	if m.ListByCursorFunc != nil {
+		return m.ListByCursorFunc(ctx, cursor, size)
+	}
+	resp := response.NewCursorPagedResponse([]response.PluginResponse{
+		{ID: 1, Key: "plugin-1", Name: "Plugin 1"},
+		{ID: 2, Key: "plugin-2", Name: "Plugin 2"},
+	}, size, "")
+	return &resp, nil
+}
+
 func (m *MockPluginService) Enable(ctx context.Context, key string) (*response.PluginResponse, error) {
 	if m.EnableFunc != nil {
 		return m.EnableFunc(ctx, key)
@@ -277,6 +440,57 @@ func (m *MockPluginService) Disable(ctx context.Context, key string) (*response.
 	}, nil
 }
 
+func (m *MockPluginService) Upgrade(ctx context.Context, key string, req *request.UpgradePluginRequest, file io.Reader) (*response.PluginResponse, error) {
+	if m.UpgradeFunc != nil {
+		return m.UpgradeFunc(ctx, key, req, file)
+	}
+	return &response.PluginResponse{
+		ID:      1,
+		Key:     key,
+		Version: req.Version,
+		State:   string(entity.PluginStateInstalled),
+	}, nil
+}
+
+func (m *MockPluginService) Rollback(ctx context.Context, key string) (*response.PluginResponse, error) {
+	if m.RollbackFunc != nil {
+		return m.RollbackFunc(ctx, key)
+	}
+	return &response.PluginResponse{
+		ID:    1,
+		Key:   key,
+		State: string(entity.PluginStateInstalled),
+	}, nil
+}
+
+func (m *MockPluginService) ValidateConfig(ctx context.Context, key string, config map[string]any) error {
+	if m.ValidateConfigFunc != nil {
+		return m.ValidateConfigFunc(ctx, key, config)
+	}
+	return nil
+}
+
+func (m *MockPluginService) UpdateConfig(ctx context.Context, key string, config map[string]any) (*response.PluginResponse, error) {
+	if m.UpdateConfigFunc != nil {
+		return m.UpdateConfigFunc(ctx, key, config)
+	}
+	return &response.PluginResponse{
+		ID:    1,
+		Key:   key,
+		State: string(entity.PluginStateInstalled),
+	}, nil
+}
+
+func (m *MockPluginService) GetHistory(ctx context.Context, key string) ([]response.PluginStateTransitionResponse, error) {
+	if m.GetHistoryFunc != nil {
+		return m.GetHistoryFunc(ctx, key)
+	}
+	return []response.PluginStateTransitionResponse{
+		{ToState: "INSTALLED", Reason: "installed version 1.0.0"},
+		{FromState: "INSTALLED", ToState: "ENABLED", Reason: "enabled"},
+	}, nil
+}
+
 func (m *MockPluginService) Uninstall(ctx context.Context, key string) error {
 	if m.UninstallFunc != nil {
 		return m.UninstallFunc(ctx, key)
@@ -284,6 +498,13 @@ func (m *MockPluginService) Uninstall(ctx context.Context, key string) error {
 	return nil
 }
 
+func (m *MockPluginService) RestoreRoutes(ctx context.Context) error {
+	if m.RestoreRoutesFunc != nil {
+		return m.RestoreRoutesFunc(ctx)
+	}
+	return nil
+}
+
 func (m *MockPluginService) GetHealth(ctx context.Context) (*response.PluginHealthResponse, error) {
 	if m.GetHealthFunc != nil {
 		return m.GetHealthFunc(ctx)
@@ -299,16 +520,24 @@ func (m *MockPluginService) GetHealth(ctx context.Context) (*response.PluginHeal
 
 // MockJobService is a mock implementation of jobs.Service
 type MockJobService struct {
-	EnqueueFunc       func(ctx context.Context, jobType string, payload any, opts ...jobs.JobOption) (string, error)
-	EnqueueAtFunc     func(ctx context.Context, jobType string, payload any, scheduledAt time.Time, opts ...jobs.JobOption) (string, error)
-	EnqueueInFunc     func(ctx context.Context, jobType string, payload any, delay time.Duration, opts ...jobs.JobOption) (string, error)
-	GetJobFunc        func(ctx context.Context, jobID string) (*jobs.JobPayload, error)
-	CancelJobFunc     func(ctx context.Context, jobID string) error
-	RetryJobFunc      func(ctx context.Context, jobID string) error
-	GetQueueStatsFunc func(ctx context.Context) (*jobs.QueueStats, error)
-	GetDLQJobsFunc    func(ctx context.Context, limit int) ([]*jobs.JobPayload, error)
-	RetryDLQJobFunc   func(ctx context.Context, jobID string) error
-	PurgeDLQFunc      func(ctx context.Context) error
+	EnqueueFunc        func(ctx context.Context, jobType string, payload any, opts ...jobs.JobOption) (string, error)
+	EnqueueAtFunc      func(ctx context.Context, jobType string, payload any, scheduledAt time.Time, opts ...jobs.JobOption) (string, error)
+	EnqueueInFunc      func(ctx context.Context, jobType string, payload any, delay time.Duration, opts ...jobs.JobOption) (string, error)
+	EnqueueBatchFunc   func(ctx context.Context, reqs []jobs.EnqueueRequest) ([]*jobs.JobPayload, error)
+	GetJobFunc         func(ctx context.Context, jobID string) (*jobs.JobPayload, error)
+	GetJobResultFunc   func(ctx context.Context, jobID string) (json.RawMessage, error)
+	GetJobProgressFunc func(ctx context.Context, jobID string) (*jobs.JobProgress, error)
+	CancelJobFunc      func(ctx context.Context, jobID string) error
+	RetryJobFunc       func(ctx context.Context, jobID string) error
+	GetQueueStatsFunc  func(ctx context.Context) (*jobs.QueueStats, error)
+	GetQueueBacklogFunc func(ctx context.Context, queue string, priority jobs.Priority) (*jobs.QueueBacklog, error)
+	PauseJobTypeFunc   func(ctx context.Context, jobType string) error
+	ResumeJobTypeFunc  func(ctx context.Context, jobType string) error
+	SetWorkerConcurrencyFunc func(ctx context.Context, n int) error
+	GetDLQJobsFunc     func(ctx context.Context, limit int) ([]*jobs.JobPayload, error)
+	FindDLQJobsFunc    func(ctx context.Context, filter jobs.DLQFilter, limit int) ([]*jobs.JobPayload, error)
+	RetryDLQJobFunc    func(ctx context.Context, jobID string) error
+	PurgeDLQFunc       func(ctx context.Context) error
 }
 
 func NewMockJobService() *MockJobService {
@@ -336,6 +565,17 @@ func (m *MockJobService) EnqueueIn(ctx context.Context, jobType string, payload
 	return "job-12345", nil
 }
 
+func (m *MockJobService) EnqueueBatch(ctx context.Context, reqs []jobs.EnqueueRequest) ([]*jobs.JobPayload, error) {
+	if m.EnqueueBatchFunc != nil {
+		return m.EnqueueBatchFunc(ctx, reqs)
+	}
+	results := make([]*jobs.JobPayload, len(reqs))
+	for i, req := range reqs {
+		results[i] = &jobs.JobPayload{ID: fmt.Sprintf("job-%d", i), Type: req.Type, Status: jobs.JobStatusPending}
+	}
+	return results, nil
+}
+
 func (m *MockJobService) GetJob(ctx context.Context, jobID string) (*jobs.JobPayload, error) {
 	if m.GetJobFunc != nil {
 		return m.GetJobFunc(ctx, jobID)
@@ -350,6 +590,20 @@ func (m *MockJobService) GetJob(ctx context.Context, jobID string) (*jobs.JobPay
 	}, nil
 }
 
+func (m *MockJobService) GetJobResult(ctx context.Context, jobID string) (json.RawMessage, error) {
+	if m.GetJobResultFunc != nil {
+		return m.GetJobResultFunc(ctx, jobID)
+	}
+	return json.RawMessage(`{"ok":true}`), nil
+}
+
+func (m *MockJobService) GetJobProgress(ctx context.Context, jobID string) (*jobs.JobProgress, error) {
+	if m.GetJobProgressFunc != nil {
+		return m.GetJobProgressFunc(ctx, jobID)
+	}
+	return &jobs.JobProgress{Percent: 50, Message: "halfway", UpdatedAt: time.Now()}, nil
+}
+
 func (m *MockJobService) CancelJob(ctx context.Context, jobID string) error {
 	if m.CancelJobFunc != nil {
 		return m.CancelJobFunc(ctx, jobID)
@@ -383,6 +637,34 @@ func (m *MockJobService) GetQueueStats(ctx context.Context) (*jobs.QueueStats, e
 	}, nil
 }
 
+func (m *MockJobService) GetQueueBacklog(ctx context.Context, queue string, priority jobs.Priority) (*jobs.QueueBacklog, error) {
+	if m.GetQueueBacklogFunc != nil {
+		return m.GetQueueBacklogFunc(ctx, queue, priority)
+	}
+	return &jobs.QueueBacklog{Queue: queue, Priority: priority}, nil
+}
+
+func (m *MockJobService) PauseJobType(ctx context.Context, jobType string) error {
+	if m.PauseJobTypeFunc != nil {
+		return m.PauseJobTypeFunc(ctx, jobType)
+	}
+	return nil
+}
+
+func (m *MockJobService) ResumeJobType(ctx context.Context, jobType string) error {
+	if m.ResumeJobTypeFunc != nil {
+		return m.ResumeJobTypeFunc(ctx, jobType)
+	}
+	return nil
+}
+
+func (m *MockJobService) SetWorkerConcurrency(ctx context.Context, n int) error {
+	if m.SetWorkerConcurrencyFunc != nil {
+		return m.SetWorkerConcurrencyFunc(ctx, n)
+	}
+	return nil
+}
+
 func (m *MockJobService) GetDLQJobs(ctx context.Context, limit int) ([]*jobs.JobPayload, error) {
 	if m.GetDLQJobsFunc != nil {
 		return m.GetDLQJobsFunc(ctx, limit)
@@ -390,6 +672,24 @@ func (m *MockJobService) GetDLQJobs(ctx context.Context, limit int) ([]*jobs.Job
 	return []*jobs.JobPayload{}, nil
 }
 
+// FindDLQJobs filters the GetDLQJobs result unless FindDLQJobsFunc is set
+func (m *MockJobService) FindDLQJobs(ctx context.Context, filter jobs.DLQFilter, limit int) ([]*jobs.JobPayload, error) {
+	if m.FindDLQJobsFunc != nil {
+		return m.FindDLQJobsFunc(ctx, filter, limit)
+	}
+	deadJobs, err := m.GetDLQJobs(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	matched := []*jobs.JobPayload{}
+	for _, job := range deadJobs {
+		if filter.Matches(job) {
+			matched = append(matched, job)
+		}
+	}
+	return matched, nil
+}
+
 func (m *MockJobService) RetryDLQJob(ctx context.Context, jobID string) error {
 	if m.RetryDLQJobFunc != nil {
 		return m.RetryDLQJobFunc(ctx, jobID)
@@ -450,3 +750,35 @@ func (m *MockSSRService) ClearCache(ctx context.Context) error {
 	}
 	return nil
 }
+
+// MockAuditService is a mock implementation of AuditService that keeps the
+// records it is given
+type MockAuditService struct {
+	RecordFunc func(ctx context.Context, log *entity.AuditLog) error
+	QueryFunc  func(ctx context.Context, req *request.AuditQueryRequest) (*response.PagedResponse[response.AuditLogResponse], error)
+
+	mu      sync.Mutex
+	Records []*entity.AuditLog
+}
+
+func NewMockAuditService() *MockAuditService {
+	return &MockAuditService{}
+}
+
+func (m *MockAuditService) Record(ctx context.Context, log *entity.AuditLog) error {
+	m.mu.Lock()
+	m.Records = append(m.Records, log)
+	m.mu.Unlock()
+	if m.RecordFunc != nil {
+		return m.RecordFunc(ctx, log)
+	}
+	return nil
+}
+
+func (m *MockAuditService) Query(ctx context.Context, req *request.AuditQueryRequest) (*response.PagedResponse[response.AuditLogResponse], error) {
+	if m.QueryFunc != nil {
+		return m.QueryFunc(ctx, req)
+	}
+	result := response.NewPagedResponse([]response.AuditLogResponse{}, 1, 20, 0)
+	return &result, nil
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gocql/gocql"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -15,8 +17,12 @@ import (
 	"go.uber.org/zap/zaptest"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+
+	cassandradao "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/cassandra"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
 )
 
 // testIDCounter is used to generate unique test IDs
@@ -24,13 +30,15 @@ var testIDCounter uint64
 
 // TestConfig holds test configuration
 type TestConfig struct {
-	RedisAddr    string
-	MySQLDSN     string
-	PostgresDSN  string
-	MongoURI     string
-	MongoDB      string
-	UseRealRedis bool
-	UseRealMySQL bool
+	RedisAddr         string
+	MySQLDSN          string
+	PostgresDSN       string
+	MongoURI          string
+	MongoDB           string
+	CassandraHosts    []string
+	CassandraKeyspace string
+	UseRealRedis      bool
+	UseRealMySQL      bool
 }
 
 // DefaultTestConfig returns default test configuration
@@ -64,14 +72,26 @@ func DefaultTestConfig() TestConfig {
 		mongoDB = "arcana_test"
 	}
 
+	cassandraHosts := os.Getenv("TEST_CASSANDRA_HOSTS")
+	if cassandraHosts == "" {
+		cassandraHosts = "localhost:9043"
+	}
+
+	cassandraKeyspace := os.Getenv("TEST_CASSANDRA_KEYSPACE")
+	if cassandraKeyspace == "" {
+		cassandraKeyspace = "arcana_test"
+	}
+
 	return TestConfig{
-		RedisAddr:    redisAddr,
-		MySQLDSN:     mysqlDSN,
-		PostgresDSN:  postgresDSN,
-		MongoURI:     mongoURI,
-		MongoDB:      mongoDB,
-		UseRealRedis: os.Getenv("TEST_USE_REAL_REDIS") == "true",
-		UseRealMySQL: os.Getenv("TEST_USE_REAL_MYSQL") == "true",
+		RedisAddr:         redisAddr,
+		MySQLDSN:          mysqlDSN,
+		PostgresDSN:       postgresDSN,
+		MongoURI:          mongoURI,
+		MongoDB:           mongoDB,
+		CassandraHosts:    strings.Split(cassandraHosts, ","),
+		CassandraKeyspace: cassandraKeyspace,
+		UseRealRedis:      os.Getenv("TEST_USE_REAL_REDIS") == "true",
+		UseRealMySQL:      os.Getenv("TEST_USE_REAL_MYSQL") == "true",
 	}
 }
 
@@ -253,6 +273,42 @@ func NewTestPostgresDB(t *testing.T, config TestConfig) *gorm.DB {
 	return db
 }
 
+// NewTestSQLiteDB creates an in-memory SQLite database with all GORM models
+// migrated. It needs no external services, so it is always available.
+func NewTestSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get sql.DB: %v", err)
+	}
+
+	// Every connection to ":memory:" gets its own database
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(
+		&entity.User{},
+		&entity.RefreshToken{},
+		&entity.PasswordResetToken{},
+		&entity.APIKey{},
+		&entity.Plugin{},
+		&entity.PluginExtension{},
+	); err != nil {
+		t.Fatalf("Failed to migrate SQLite: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	return db
+}
+
 // SkipIfNoPostgres skips the test if PostgreSQL is not available
 func SkipIfNoPostgres(t *testing.T) {
 	config := DefaultTestConfig()
@@ -309,3 +365,62 @@ func SkipIfNoMongo(t *testing.T) {
 		t.Skip("MongoDB not available")
 	}
 }
+
+// NewTestCassandraSession creates a Cassandra session bound to a fresh test
+// keyspace with the DAO tables created. The keyspace is dropped on cleanup.
+func NewTestCassandraSession(t *testing.T, config TestConfig) *gocql.Session {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cluster := gocql.NewCluster(config.CassandraHosts...)
+	cluster.Timeout = 10 * time.Second
+	cluster.ConnectTimeout = 10 * time.Second
+
+	bootstrap, err := cluster.CreateSession()
+	if err != nil {
+		t.Skipf("Cassandra not available: %v", err)
+	}
+	// Drop leftovers of an aborted run so each test starts empty
+	if err := bootstrap.Query(fmt.Sprintf(`DROP KEYSPACE IF EXISTS %s`, config.CassandraKeyspace)).WithContext(ctx).Exec(); err != nil {
+		bootstrap.Close()
+		t.Fatalf("Failed to drop Cassandra keyspace: %v", err)
+	}
+	if err := cassandradao.CreateKeyspace(ctx, bootstrap, config.CassandraKeyspace, 1); err != nil {
+		bootstrap.Close()
+		t.Fatalf("Failed to create Cassandra keyspace: %v", err)
+	}
+
+	cluster.Keyspace = config.CassandraKeyspace
+	session, err := cluster.CreateSession()
+	if err != nil {
+		bootstrap.Close()
+		t.Fatalf("Failed to connect to Cassandra keyspace: %v", err)
+	}
+	if err := cassandradao.CreateSchema(ctx, session); err != nil {
+		session.Close()
+		bootstrap.Close()
+		t.Fatalf("Failed to create Cassandra schema: %v", err)
+	}
+
+	t.Cleanup(func() {
+		session.Close()
+		// Drop test keyspace
+		bootstrap.Query(fmt.Sprintf(`DROP KEYSPACE IF EXISTS %s`, config.CassandraKeyspace)).Exec()
+		bootstrap.Close()
+	})
+
+	return session
+}
+
+// SkipIfNoCassandra skips the test if Cassandra is not available
+func SkipIfNoCassandra(t *testing.T) {
+	config := DefaultTestConfig()
+	cluster := gocql.NewCluster(config.CassandraHosts...)
+	cluster.ConnectTimeout = 5 * time.Second
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		t.Skip("Cassandra not available")
+	}
+	session.Close()
+}
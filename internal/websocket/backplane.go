@@ -0,0 +1,108 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// DefaultBackplaneChannel is the Redis channel broadcasts are relayed on
+const DefaultBackplaneChannel = "arcana:websocket:broadcast"
+
+// Envelope is a broadcast relayed between hub instances. Room and user
+// targeting travel in the message itself.
+type Envelope struct {
+	// Origin is the ID of the hub that published the message, so a hub can
+	// skip its own broadcasts when they come back from the backplane
+	Origin  string   `json:"origin"`
+	Message *Message `json:"message"`
+}
+
+// Backplane relays broadcasts between hub instances
+type Backplane interface {
+	// Publish sends an envelope to every subscribed hub
+	Publish(ctx context.Context, envelope *Envelope) error
+	// Subscribe starts delivering envelopes published by any hub to handler.
+	// It returns once the subscription is active.
+	Subscribe(ctx context.Context, handler func(*Envelope)) error
+	// Close stops the subscription
+	Close() error
+}
+
+// RedisBackplane relays broadcasts over Redis pub/sub
+type RedisBackplane struct {
+	client  *redis.Client
+	channel string
+	logger  *zap.Logger
+
+	mu     sync.Mutex
+	pubsub *redis.PubSub
+}
+
+// NewRedisBackplane creates a backplane publishing on the given channel. An
+// empty channel uses DefaultBackplaneChannel.
+func NewRedisBackplane(client *redis.Client, channel string, logger *zap.Logger) *RedisBackplane {
+	if channel == "" {
+		channel = DefaultBackplaneChannel
+	}
+	return &RedisBackplane{
+		client:  client,
+		channel: channel,
+		logger:  logger,
+	}
+}
+
+// Publish publishes an envelope to the channel
+func (b *RedisBackplane) Publish(ctx context.Context, envelope *Envelope) error {
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+// Subscribe subscribes to the channel and delivers envelopes to handler until
+// Close is called
+func (b *RedisBackplane) Subscribe(ctx context.Context, handler func(*Envelope)) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	b.mu.Lock()
+	b.pubsub = pubsub
+	b.mu.Unlock()
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			var envelope Envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil || envelope.Message == nil {
+				b.logger.Warn("Dropping malformed backplane message",
+					zap.String("channel", b.channel),
+					zap.Error(err),
+				)
+				continue
+			}
+			handler(&envelope)
+		}
+	}()
+
+	return nil
+}
+
+// Close closes the subscription
+func (b *RedisBackplane) Close() error {
+	b.mu.Lock()
+	pubsub := b.pubsub
+	b.pubsub = nil
+	b.mu.Unlock()
+
+	if pubsub == nil {
+		return nil
+	}
+	return pubsub.Close()
+}
@@ -0,0 +1,203 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
+)
+
+// memoryBus is an in-process stand-in for a Redis channel shared by several
+// backplanes
+type memoryBus struct {
+	mu       sync.Mutex
+	handlers []func(*Envelope)
+}
+
+type memoryBackplane struct {
+	bus          *memoryBus
+	subscribeErr error
+	published    []*Envelope
+}
+
+func (b *memoryBackplane) Publish(_ context.Context, envelope *Envelope) error {
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+
+	b.published = append(b.published, envelope)
+	// Round-trip through JSON like the Redis backplane does
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	for _, handler := range b.bus.handlers {
+		var decoded Envelope
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			return err
+		}
+		handler(&decoded)
+	}
+	return nil
+}
+
+func (b *memoryBackplane) Subscribe(_ context.Context, handler func(*Envelope)) error {
+	if b.subscribeErr != nil {
+		return b.subscribeErr
+	}
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+	b.bus.handlers = append(b.bus.handlers, handler)
+	return nil
+}
+
+func (b *memoryBackplane) Close() error { return nil }
+
+func newTestClient(id string, userID uint) *Client {
+	return &Client{
+		ID:     id,
+		UserID: userID,
+		Rooms:  make(map[string]bool),
+		send:   make(chan *Message, sendBufferSize),
+	}
+}
+
+// receive waits briefly for a message on the client's send channel
+func receive(client *Client) *Message {
+	select {
+	case msg := <-client.send:
+		return msg
+	case <-time.After(200 * time.Millisecond):
+		return nil
+	}
+}
+
+// startBackplaneHubs starts two hubs sharing one in-memory backplane channel
+func startBackplaneHubs(t *testing.T) (*Hub, *Hub) {
+	t.Helper()
+	bus := &memoryBus{}
+	hubA := NewHubWithBackplane(zap.NewNop(), &memoryBackplane{bus: bus})
+	hubB := NewHubWithBackplane(zap.NewNop(), &memoryBackplane{bus: bus})
+	go hubA.Run()
+	go hubB.Run()
+
+	require.Eventually(t, func() bool {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		return len(bus.handlers) == 2
+	}, time.Second, 5*time.Millisecond)
+	return hubA, hubB
+}
+
+// TestHub_Backplane_Broadcast delivers to clients on both instances exactly once
+func TestHub_Backplane_Broadcast(t *testing.T) {
+	hubA, hubB := startBackplaneHubs(t)
+	local := newTestClient("local", 0)
+	remote := newTestClient("remote", 0)
+	hubA.registerClient(local)
+	hubB.registerClient(remote)
+
+	hubA.Broadcast(&Message{ID: "m1", Type: MessageTypeMessage, Data: "hello"})
+
+	for _, client := range []*Client{local, remote} {
+		msg := receive(client)
+		require.NotNil(t, msg, client.ID)
+		assert.Equal(t, "m1", msg.ID)
+		assert.Nil(t, receive(client), "%s received a duplicate", client.ID)
+	}
+}
+
+// TestHub_Backplane_RoomAndUserTargeting preserves targeting across instances
+func TestHub_Backplane_RoomAndUserTargeting(t *testing.T) {
+	hubA, hubB := startBackplaneHubs(t)
+	inRoom := newTestClient("in-room", 0)
+	user := newTestClient("user", 7)
+	other := newTestClient("other", 8)
+	for _, client := range []*Client{inRoom, user, other} {
+		hubB.registerClient(client)
+	}
+	hubB.handleJoinRoom(&RoomOperation{Client: inRoom, Room: "lobby"})
+
+	hubA.BroadcastToRoom("lobby", &Message{ID: "room", Type: MessageTypeMessage})
+	msg := receive(inRoom)
+	require.NotNil(t, msg)
+	assert.Equal(t, "lobby", msg.Room)
+
+	hubA.BroadcastToUser(7, &Message{ID: "user", Type: MessageTypeMessage})
+	msg = receive(user)
+	require.NotNil(t, msg)
+	assert.Equal(t, uint(7), msg.UserID)
+
+	assert.Nil(t, receive(other))
+	assert.Nil(t, receive(inRoom))
+}
+
+// TestHub_Backplane_HeartbeatNotPublished keeps heartbeats on the local instance
+func TestHub_Backplane_HeartbeatNotPublished(t *testing.T) {
+	backplane := &memoryBackplane{bus: &memoryBus{}}
+	hub := NewHubWithBackplane(zap.NewNop(), backplane)
+	go hub.Run()
+	client := newTestClient("client", 0)
+	hub.registerClient(client)
+
+	hub.SendHeartbeat()
+
+	msg := receive(client)
+	require.NotNil(t, msg)
+	assert.Equal(t, MessageTypePing, msg.Type)
+	assert.Empty(t, backplane.published)
+}
+
+// TestHub_Backplane_SubscribeFailure falls back to local delivery
+func TestHub_Backplane_SubscribeFailure(t *testing.T) {
+	backplane := &memoryBackplane{bus: &memoryBus{}, subscribeErr: errors.New("redis down")}
+	hub := NewHubWithBackplane(zap.NewNop(), backplane)
+	go hub.Run()
+	client := newTestClient("client", 0)
+	hub.registerClient(client)
+
+	hub.Broadcast(&Message{ID: "m1", Type: MessageTypeMessage})
+
+	msg := receive(client)
+	require.NotNil(t, msg)
+	assert.Equal(t, "m1", msg.ID)
+}
+
+// TestRedisBackplane relays envelopes between two backplanes over Redis
+func TestRedisBackplane(t *testing.T) {
+	testutil.SkipIfNoRedis(t)
+	client := testutil.NewTestRedisClient(t, testutil.DefaultTestConfig())
+	channel := "test:websocket:" + t.Name()
+
+	publisher := NewRedisBackplane(client, channel, zap.NewNop())
+	subscriber := NewRedisBackplane(client, channel, zap.NewNop())
+	defer subscriber.Close()
+
+	received := make(chan *Envelope, 1)
+	require.NoError(t, subscriber.Subscribe(context.Background(), func(e *Envelope) { received <- e }))
+
+	sent := &Envelope{Origin: "hub-a", Message: &Message{ID: "m1", Type: MessageTypeMessage, Room: "lobby", UserID: 7}}
+	require.NoError(t, publisher.Publish(context.Background(), sent))
+
+	select {
+	case envelope := <-received:
+		assert.Equal(t, "hub-a", envelope.Origin)
+		assert.Equal(t, "m1", envelope.Message.ID)
+		assert.Equal(t, "lobby", envelope.Message.Room)
+		assert.Equal(t, uint(7), envelope.Message.UserID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("envelope not received")
+	}
+}
+
+// TestNewRedisBackplane_DefaultChannel falls back to the default channel
+func TestNewRedisBackplane_DefaultChannel(t *testing.T) {
+	assert.Equal(t, DefaultBackplaneChannel, NewRedisBackplane(nil, "", zap.NewNop()).channel)
+}
@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -39,6 +40,9 @@ const (
 	MessageTypeSubscribe    MessageType = "subscribe"
 	MessageTypeUnsubscribe  MessageType = "unsubscribe"
 	MessageTypeAck          MessageType = "ack"
+	MessageTypePresence     MessageType = "presence"
+	MessageTypeTyping       MessageType = "typing"
+	MessageTypeClose        MessageType = "close"
 )
 
 // Message represents a WebSocket message
@@ -47,10 +51,19 @@ type Message struct {
 	Type      MessageType            `json:"type"`
 	Event     string                 `json:"event,omitempty"`
 	Room      string                 `json:"room,omitempty"`
+	Rooms     []string               `json:"rooms,omitempty"`
 	UserID    uint                   `json:"userId,omitempty"`
 	Data      interface{}            `json:"data,omitempty"`
 	Timestamp time.Time              `json:"timestamp"`
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	// RequireAck asks the client to reply with an ack message carrying ID
+	RequireAck bool `json:"requireAck,omitempty"`
+	// Seq numbers the messages of a room that keeps history, without gaps,
+	// so clients can detect missed messages and ask for them on rejoin
+	Seq uint64 `json:"seq,omitempty"`
+
+	// onReceipt receives delivery receipts for messages that require ack
+	onReceipt func(DeliveryReceipt)
 }
 
 // NewMessage creates a new message
@@ -98,6 +111,21 @@ type Client struct {
 	send     chan *Message
 	logger   *zap.Logger
 	metadata map[string]interface{}
+
+	// seq orders the client's registrations on the hub, oldest first
+	seq uint64
+
+	// rateViolations counts the client's messages beyond a rate limit; only
+	// the read goroutine touches it
+	rateViolations int
+
+	// sendMu guards closing send against concurrent Send calls
+	sendMu sync.Mutex
+	closed bool
+	// closeMessage is the close frame payload written once send is closed
+	closeMessage []byte
+	// writeDone is closed when WritePump exits
+	writeDone chan struct{}
 }
 
 // NewClient creates a new WebSocket client
@@ -112,13 +140,15 @@ func NewClient(hub *Hub, conn *websocket.Conn, userID uint, username string, log
 		send:     make(chan *Message, sendBufferSize),
 		logger:   logger,
 		metadata: make(map[string]interface{}),
+
+		writeDone: make(chan struct{}),
 	}
 }
 
 // ReadPump pumps messages from the WebSocket connection to the hub
 func (c *Client) ReadPump() {
 	defer func() {
-		c.hub.unregister <- c
+		submit(c.hub, c.hub.unregister, c)
 		c.conn.Close()
 	}()
 
@@ -163,6 +193,9 @@ func (c *Client) WritePump() {
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
+		if c.writeDone != nil {
+			close(c.writeDone)
+		}
 	}()
 
 	for {
@@ -173,7 +206,7 @@ func (c *Client) WritePump() {
 			}
 			if !ok {
 				// The hub closed the channel
-				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				_ = c.conn.WriteMessage(websocket.CloseMessage, c.closeMessage)
 				return
 			}
 
@@ -201,19 +234,26 @@ func (c *Client) handleMessage(message *Message) {
 	switch message.Type {
 	case MessageTypePing:
 		// Respond with pong
-		c.send <- &Message{
+		c.Send(&Message{
 			Type:      MessageTypePong,
 			Timestamp: time.Now(),
-		}
+		})
 
 	case MessageTypeSubscribe:
-		// Subscribe to a room
-		if room, ok := message.Data.(string); ok {
-			c.hub.JoinRoom(c, room)
-			c.send <- &Message{
-				Type:      MessageTypeAck,
-				Data:      map[string]string{"action": "subscribed", "room": room},
-				Timestamp: time.Now(),
+		// Subscribe to a room; the hub acks or rejects the join. A client
+		// rejoining a room that keeps history sends {"room", "lastSeq"} to
+		// have the messages after lastSeq replayed.
+		switch data := message.Data.(type) {
+		case string:
+			c.hub.requestJoin(c, data, nil)
+		case map[string]interface{}:
+			if room, ok := data["room"].(string); ok {
+				var replayAfter *uint64
+				if lastSeq, ok := data["lastSeq"].(float64); ok && lastSeq >= 0 {
+					seq := uint64(lastSeq)
+					replayAfter = &seq
+				}
+				c.hub.requestJoin(c, room, replayAfter)
 			}
 		}
 
@@ -221,15 +261,29 @@ func (c *Client) handleMessage(message *Message) {
 		// Unsubscribe from a room
 		if room, ok := message.Data.(string); ok {
 			c.hub.LeaveRoom(c, room)
-			c.send <- &Message{
+			c.Send(&Message{
 				Type:      MessageTypeAck,
 				Data:      map[string]string{"action": "unsubscribed", "room": room},
 				Timestamp: time.Now(),
-			}
+			})
+		}
+
+	case MessageTypeAck:
+		// Acknowledge a message that required ack
+		c.hub.acknowledge(c, message.ID)
+
+	case MessageTypeTyping:
+		// Tell the room the user is typing; only members may do so
+		if c.hub.inRoom(c, message.Room) && c.hub.allowMessage(c, message.Room) {
+			c.hub.Typing(message.Room, c.UserID)
 		}
 
 	case MessageTypeMessage:
-		// Broadcast message to room if specified, otherwise to all
+		// Broadcast message to room if specified, otherwise to all, within
+		// the client's and the room's rate limits
+		if !c.hub.allowMessage(c, message.Room) {
+			return
+		}
 		message.UserID = c.UserID
 		message.Timestamp = time.Now()
 		c.hub.Broadcast(message)
@@ -242,8 +296,15 @@ func (c *Client) handleMessage(message *Message) {
 	}
 }
 
-// Send sends a message to the client
+// Send sends a message to the client. Messages sent after the hub closed
+// the client are dropped.
 func (c *Client) Send(message *Message) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.closed {
+		return
+	}
 	select {
 	case c.send <- message:
 	default:
@@ -253,9 +314,23 @@ func (c *Client) Send(message *Message) {
 	}
 }
 
+// closeSend closes the send channel, making WritePump write a close frame
+// with the given payload and disconnect. It is safe to call more than once.
+func (c *Client) closeSend(closeMessage []byte) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.closeMessage = closeMessage
+	close(c.send)
+}
+
 // Close closes the client connection
 func (c *Client) Close() {
-	c.hub.unregister <- c
+	submit(c.hub, c.hub.unregister, c)
 }
 
 // SetMetadata sets client metadata
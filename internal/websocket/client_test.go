@@ -244,6 +244,26 @@ func TestClient_HandleMessage_Ping(t *testing.T) {
 	}
 }
 
+func TestClient_HandleMessage_AfterClose(t *testing.T) {
+	client := &Client{
+		ID:       "closed-test",
+		UserID:   1,
+		Rooms:    make(map[string]bool),
+		hub:      newTestHub(t),
+		send:     make(chan *Message, sendBufferSize),
+		logger:   zap.NewNop(),
+		metadata: make(map[string]interface{}),
+	}
+
+	// An evicted client's ReadPump can still be handling messages
+	client.closeSend(nil)
+	client.handleMessage(&Message{Type: MessageTypePing})
+
+	if _, ok := <-client.send; ok {
+		t.Error("closed client should not receive a pong")
+	}
+}
+
 func TestClient_HandleMessage_Subscribe(t *testing.T) {
 	hub := newTestHub(t)
 	go hub.Run()
@@ -9,9 +9,15 @@ import (
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 
+	"github.com/jrjohn/arcana-cloud-go/internal/dto/response"
 	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
+// bearerSubprotocol is the Sec-WebSocket-Protocol entry that marks the next
+// entry as an access token, for browser clients that cannot set headers
+// ("Sec-WebSocket-Protocol: bearer, <token>")
+const bearerSubprotocol = "bearer"
+
 // WebSocketConfig holds WebSocket configuration
 type WebSocketConfig struct {
 	Enabled           bool          `mapstructure:"enabled"`
@@ -22,6 +28,12 @@ type WebSocketConfig struct {
 	HandshakeTimeout  time.Duration `mapstructure:"handshake_timeout"`
 	EnableCompression bool          `mapstructure:"enable_compression"`
 	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+	// AllowAnonymous permits connections that present no token. A token that
+	// is presented must still be valid.
+	AllowAnonymous bool `mapstructure:"allow_anonymous"`
+	// BackplaneChannel is the Redis channel hubs relay broadcasts on when a
+	// Redis backplane is used
+	BackplaneChannel string `mapstructure:"backplane_channel"`
 }
 
 // DefaultWebSocketConfig returns default configuration
@@ -35,16 +47,19 @@ func DefaultWebSocketConfig() *WebSocketConfig {
 		HandshakeTimeout:  10 * time.Second,
 		EnableCompression: true,
 		HeartbeatInterval: 30 * time.Second,
+		AllowAnonymous:    false,
+		BackplaneChannel:  DefaultBackplaneChannel,
 	}
 }
 
 // Handler handles WebSocket connections
 type Handler struct {
-	config      *WebSocketConfig
-	hub         *Hub
-	upgrader    websocket.Upgrader
-	jwtProvider *security.JWTProvider
-	logger      *zap.Logger
+	config        *WebSocketConfig
+	hub           *Hub
+	upgrader      websocket.Upgrader
+	jwtProvider   *security.JWTProvider
+	tokenDenylist security.TokenDenylist
+	logger        *zap.Logger
 }
 
 // NewHandler creates a new WebSocket handler
@@ -72,42 +87,66 @@ func NewHandler(
 	return h
 }
 
+// SetTokenDenylist makes the handshake reject revoked access tokens. Without
+// it access tokens stay valid until they expire.
+func (h *Handler) SetTokenDenylist(denylist security.TokenDenylist) {
+	h.tokenDenylist = denylist
+}
+
 // RegisterRoutes registers WebSocket routes
 func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	router.GET(h.config.Path, h.handleWebSocket)
 	router.GET(h.config.Path+"/status", h.handleStatus)
 }
 
-// handleWebSocket handles WebSocket upgrade requests
+// handleWebSocket handles WebSocket upgrade requests. The handshake is
+// rejected with 401 unless it carries a valid, unrevoked access token or
+// anonymous connections are allowed. If the token denylist cannot be read
+// the token is accepted.
 func (h *Handler) handleWebSocket(c *gin.Context) {
-	// Authenticate user (optional - can be done via query param or header)
+	if h.hub.isShuttingDown() {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, response.NewError[any]("server is shutting down"))
+		return
+	}
+
 	var userID uint
 	var username string
 
-	// Try to get token from query parameter
-	token := c.Query("token")
-	if token == "" {
-		// Try Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
-				token = parts[1]
+	token, subprotocol := extractToken(c.Request)
+	switch {
+	case token != "":
+		if h.jwtProvider == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewError[any]("invalid token"))
+			return
+		}
+		claims, err := h.jwtProvider.ValidateAccessToken(token)
+		if err != nil {
+			if err == security.ErrExpiredToken {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewError[any]("token has expired"))
+			} else {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewError[any]("invalid token"))
 			}
+			return
+		}
+		if h.isRevoked(c, claims) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewError[any]("token has been revoked"))
+			return
 		}
+		userID = claims.UserID
+		username = claims.Username
+	case !h.config.AllowAnonymous:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, response.NewError[any]("authentication required"))
+		return
 	}
 
-	// Validate token if provided
-	if token != "" && h.jwtProvider != nil {
-		claims, err := h.jwtProvider.ValidateAccessToken(token)
-		if err == nil {
-			userID = claims.UserID
-			username = claims.Username
-		}
+	// Echo the bearer subprotocol, otherwise browsers drop the connection
+	var responseHeader http.Header
+	if subprotocol != "" {
+		responseHeader = http.Header{"Sec-Websocket-Protocol": {subprotocol}}
 	}
 
 	// Upgrade to WebSocket
-	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, responseHeader)
 	if err != nil {
 		h.logger.Error("Failed to upgrade connection",
 			zap.Error(err),
@@ -119,7 +158,10 @@ func (h *Handler) handleWebSocket(c *gin.Context) {
 	client := NewClient(h.hub, conn, userID, username, h.logger)
 
 	// Register client
-	h.hub.register <- client
+	if !submit(h.hub, h.hub.register, client) {
+		conn.Close()
+		return
+	}
 
 	// Send welcome message
 	client.Send(&Message{
@@ -138,18 +180,54 @@ func (h *Handler) handleWebSocket(c *gin.Context) {
 	go client.ReadPump()
 }
 
+// isRevoked reports whether the token was revoked, failing open if the
+// denylist cannot be read
+func (h *Handler) isRevoked(c *gin.Context, claims *security.UserClaims) bool {
+	if h.tokenDenylist == nil {
+		return false
+	}
+	revoked, err := h.tokenDenylist.IsRevoked(c.Request.Context(), claims)
+	return err == nil && revoked
+}
+
+// extractToken returns the access token from the token query parameter, the
+// Authorization header or the Sec-WebSocket-Protocol header, in that order.
+// subprotocol is set when the token came from Sec-WebSocket-Protocol.
+func extractToken(r *http.Request) (token, subprotocol string) {
+	if token = r.URL.Query().Get("token"); token != "" {
+		return token, ""
+	}
+
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+		return parts[1], ""
+	}
+
+	protocols := websocket.Subprotocols(r)
+	for i := 0; i+1 < len(protocols); i++ {
+		if strings.ToLower(protocols[i]) == bearerSubprotocol {
+			return protocols[i+1], protocols[i]
+		}
+	}
+
+	return "", ""
+}
+
 // handleStatus returns WebSocket hub status
 func (h *Handler) handleStatus(c *gin.Context) {
 	metrics := h.hub.GetMetrics()
 
 	c.JSON(http.StatusOK, gin.H{
-		"enabled":            h.config.Enabled,
-		"activeConnections":  metrics.ActiveConnections,
-		"totalConnections":   metrics.TotalConnections,
-		"totalMessages":      metrics.TotalMessages,
-		"totalBroadcasts":    metrics.TotalBroadcasts,
-		"activeRooms":        metrics.TotalRooms,
-		"onlineUsers":        len(h.hub.GetOnlineUsers()),
+		"enabled":             h.config.Enabled,
+		"activeConnections":   metrics.ActiveConnections,
+		"totalConnections":    metrics.TotalConnections,
+		"totalMessages":       metrics.TotalMessages,
+		"totalBroadcasts":     metrics.TotalBroadcasts,
+		"activeRooms":         metrics.TotalRooms,
+		"onlineUsers":         len(h.hub.GetOnlineUsers()),
+		"droppedMessages":     metrics.DroppedMessages,
+		"rejectedConnections": metrics.RejectedConnections,
+		"evictedConnections":  metrics.EvictedConnections,
 	})
 }
 
@@ -169,7 +247,8 @@ func (h *Handler) checkOrigin(r *http.Request) bool {
 	return false
 }
 
-// StartHeartbeat starts a goroutine that sends heartbeats
+// StartHeartbeat starts a goroutine that sends heartbeats until the hub
+// shuts down
 func (h *Handler) StartHeartbeat() {
 	if h.config.HeartbeatInterval <= 0 {
 		return
@@ -179,8 +258,13 @@ func (h *Handler) StartHeartbeat() {
 		ticker := time.NewTicker(h.config.HeartbeatInterval)
 		defer ticker.Stop()
 
-		for range ticker.C {
-			h.hub.SendHeartbeat()
+		for {
+			select {
+			case <-ticker.C:
+				h.hub.SendHeartbeat()
+			case <-h.hub.done:
+				return
+			}
 		}
 	}()
 }
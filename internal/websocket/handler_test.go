@@ -1,13 +1,23 @@
 package websocket
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/config"
+	"github.com/jrjohn/arcana-cloud-go/internal/domain/entity"
+	"github.com/jrjohn/arcana-cloud-go/internal/security"
 )
 
 func init() {
@@ -128,3 +138,146 @@ func TestHandler_StartHeartbeat_ZeroInterval(t *testing.T) {
 		handler.StartHeartbeat()
 	})
 }
+
+// newAuthTestServer starts a server running the WebSocket handler and returns
+// its ws:// URL and a valid access token for user 42
+func newAuthTestServer(t *testing.T, allowAnonymous bool) (*Hub, string, string) {
+	t.Helper()
+	return newDenylistTestServer(t, allowAnonymous, nil)
+}
+
+// newDenylistTestServer is newAuthTestServer with the handler checking denylist
+func newDenylistTestServer(t *testing.T, allowAnonymous bool, denylist security.TokenDenylist) (*Hub, string, string) {
+	t.Helper()
+
+	provider := security.NewJWTProvider(&config.JWTConfig{
+		Secret:               "test-secret-key-for-testing",
+		AccessTokenDuration:  time.Hour,
+		RefreshTokenDuration: 24 * time.Hour,
+		Issuer:               "test",
+	})
+	token, err := provider.GenerateAccessToken(&entity.User{
+		ID:       42,
+		Username: "alice",
+		Role:     entity.RoleUser,
+	})
+	require.NoError(t, err)
+
+	cfg := DefaultWebSocketConfig()
+	cfg.AllowAnonymous = allowAnonymous
+	hub := NewHub(zap.NewNop())
+	go hub.Run()
+
+	router := gin.New()
+	handler := NewHandler(cfg, hub, provider, zap.NewNop())
+	if denylist != nil {
+		handler.SetTokenDenylist(denylist)
+	}
+	handler.RegisterRoutes(router.Group(""))
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return hub, "ws" + strings.TrimPrefix(server.URL, "http") + cfg.Path, token
+}
+
+// readWelcome reads the connected event and returns its userId
+func readWelcome(t *testing.T, conn *websocket.Conn) float64 {
+	t.Helper()
+
+	var msg Message
+	require.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "connected", msg.Event)
+	data, ok := msg.Data.(map[string]interface{})
+	require.True(t, ok)
+	return data["userId"].(float64)
+}
+
+// TestHandler_HandleWebSocket_ValidToken accepts a valid token from each source
+func TestHandler_HandleWebSocket_ValidToken(t *testing.T) {
+	hub, url, token := newAuthTestServer(t, false)
+
+	tests := []struct {
+		name         string
+		url          string
+		header       http.Header
+		subprotocols []string
+	}{
+		{"query", url + "?token=" + token, nil, nil},
+		{"authorization header", url, http.Header{"Authorization": {"Bearer " + token}}, nil},
+		{"subprotocol", url, nil, []string{"bearer", token}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialer := websocket.Dialer{Subprotocols: tt.subprotocols}
+			conn, resp, err := dialer.Dial(tt.url, tt.header)
+			require.NoError(t, err)
+			defer conn.Close()
+			assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+			if tt.subprotocols != nil {
+				assert.Equal(t, "bearer", conn.Subprotocol())
+			}
+
+			assert.Equal(t, float64(42), readWelcome(t, conn))
+			assert.Eventually(t, func() bool { return hub.IsUserOnline(42) }, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+// TestHandler_HandleWebSocket_InvalidToken rejects the handshake with 401
+func TestHandler_HandleWebSocket_InvalidToken(t *testing.T) {
+	for _, allowAnonymous := range []bool{false, true} {
+		_, url, _ := newAuthTestServer(t, allowAnonymous)
+
+		conn, resp, err := websocket.DefaultDialer.Dial(url+"?token=not-a-jwt", nil)
+		require.Error(t, err)
+		assert.Nil(t, conn)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+}
+
+// TestHandler_HandleWebSocket_Anonymous only accepts tokenless connections when allowed
+func TestHandler_HandleWebSocket_Anonymous(t *testing.T) {
+	_, url, _ := newAuthTestServer(t, false)
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, url, _ = newAuthTestServer(t, true)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, float64(0), readWelcome(t, conn))
+}
+
+// stubDenylist reports every token as revoked, or fails to be read
+type stubDenylist struct {
+	revoked bool
+	err     error
+}
+
+func (d stubDenylist) Revoke(context.Context, string, time.Time) error { return nil }
+func (d stubDenylist) RevokeAllForUser(context.Context, uint, time.Duration) error {
+	return nil
+}
+func (d stubDenylist) IsRevoked(context.Context, *security.UserClaims) (bool, error) {
+	return d.revoked, d.err
+}
+
+// TestHandler_HandleWebSocket_RevokedToken rejects revoked tokens and fails
+// open when the denylist is unavailable
+func TestHandler_HandleWebSocket_RevokedToken(t *testing.T) {
+	_, url, token := newDenylistTestServer(t, true, stubDenylist{revoked: true})
+	conn, resp, err := websocket.DefaultDialer.Dial(url+"?token="+token, nil)
+	require.Error(t, err)
+	assert.Nil(t, conn)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, url, token = newDenylistTestServer(t, false, stubDenylist{err: errors.New("redis unavailable")})
+	conn, _, err = websocket.DefaultDialer.Dial(url+"?token="+token, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, float64(42), readWelcome(t, conn))
+}
@@ -0,0 +1,342 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHistoryKeyPrefix prefixes the Redis keys room history is kept under
+	DefaultHistoryKeyPrefix = "arcana:websocket:history:"
+
+	// DefaultReplayLimit caps the messages replayed to a client on join
+	DefaultReplayLimit = 100
+
+	// historyTimeout bounds how long a broadcast or replay waits on the history store
+	historyTimeout = 5 * time.Second
+)
+
+var (
+	ErrHistoryDisabled = errors.New("room history is not enabled")
+	ErrInvalidHistory  = errors.New("room history must keep a bounded number or age of messages")
+)
+
+// HistoryPolicy bounds the messages kept for a room. At least one bound must
+// be set.
+type HistoryPolicy struct {
+	// MaxMessages keeps the last N messages; zero means no count limit
+	MaxMessages int
+	// MaxAge keeps messages younger than this; zero means no age limit
+	MaxAge time.Duration
+}
+
+// HistoryStore keeps the recent messages of rooms so clients that reconnect
+// can catch up. Each room's messages are numbered from 1, without gaps.
+type HistoryStore interface {
+	// Append assigns message the next sequence number of room, stores it and
+	// trims the room to policy. It returns the sequence number.
+	Append(ctx context.Context, room string, message *Message, policy HistoryPolicy) (uint64, error)
+	// After returns up to limit stored messages of room numbered after
+	// afterSeq, oldest first
+	After(ctx context.Context, room string, afterSeq uint64, limit int) ([]*Message, error)
+}
+
+// SetHistoryStore sets the store room history is kept in. Without one, no
+// room keeps history.
+func (h *Hub) SetHistoryStore(store HistoryStore) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.history = store
+}
+
+// EnableRoomHistory keeps the messages broadcast to a room, within policy, so
+// they can be replayed. Presence and typing events are never kept. Every
+// instance broadcasting to the room must enable it, since the instance
+// broadcasting a message stores it.
+func (h *Hub) EnableRoomHistory(room string, policy HistoryPolicy) error {
+	if policy.MaxMessages < 0 || policy.MaxAge < 0 || (policy.MaxMessages == 0 && policy.MaxAge == 0) {
+		return ErrInvalidHistory
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.historyPolicies[room] = policy
+	return nil
+}
+
+// DisableRoomHistory stops keeping a room's messages. Messages already kept
+// expire according to the store.
+func (h *Hub) DisableRoomHistory(room string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	delete(h.historyPolicies, room)
+}
+
+// GetRoomHistory returns up to limit messages of a room numbered after
+// afterSeq, oldest first. A client that saw message N asks for those after N;
+// zero returns the oldest messages kept. It returns ErrHistoryDisabled unless
+// the room keeps history.
+func (h *Hub) GetRoomHistory(room string, afterSeq uint64, limit int) ([]*Message, error) {
+	h.mutex.RLock()
+	store := h.history
+	policy, ok := h.historyPolicies[room]
+	h.mutex.RUnlock()
+
+	if store == nil || !ok {
+		return nil, ErrHistoryDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyTimeout)
+	defer cancel()
+
+	messages, err := store.After(ctx, room, afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// The store trims on append, so a quiet room may still hold expired messages
+	if policy.MaxAge > 0 {
+		cutoff := time.Now().Add(-policy.MaxAge)
+		kept := messages[:0]
+		for _, message := range messages {
+			if !message.Timestamp.Before(cutoff) {
+				kept = append(kept, message)
+			}
+		}
+		messages = kept
+	}
+	return messages, nil
+}
+
+// recordHistory stores a room broadcast when the room keeps history, setting
+// its sequence number. A store failure is logged and the message is
+// broadcast without one.
+func (h *Hub) recordHistory(message *Message) {
+	if message.Room == "" || len(message.Rooms) > 0 ||
+		message.Type == MessageTypePresence || message.Type == MessageTypeTyping {
+		return
+	}
+
+	h.mutex.RLock()
+	store := h.history
+	policy, ok := h.historyPolicies[message.Room]
+	h.mutex.RUnlock()
+
+	if store == nil || !ok {
+		return
+	}
+
+	if message.Timestamp.IsZero() {
+		message.Timestamp = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyTimeout)
+	defer cancel()
+
+	seq, err := store.Append(ctx, message.Room, message, policy)
+	if err != nil {
+		h.logger.Warn("Failed to store room message",
+			zap.String("room", message.Room),
+			zap.String("message_id", message.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	message.Seq = seq
+}
+
+// replayHistory sends a client the messages of a room it missed after
+// afterSeq. It runs on its own goroutine so the store is not read on the hub
+// goroutine; replayed messages may therefore interleave with live ones, and
+// clients order them by sequence number and drop duplicates.
+func (h *Hub) replayHistory(client *Client, room string, afterSeq uint64) {
+	messages, err := h.GetRoomHistory(room, afterSeq, DefaultReplayLimit)
+	if err != nil {
+		if !errors.Is(err, ErrHistoryDisabled) {
+			h.logger.Warn("Failed to replay room history",
+				zap.String("client_id", client.ID),
+				zap.String("room", room),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+	for _, message := range messages {
+		client.Send(message)
+	}
+}
+
+// RedisHistoryStore keeps room history in Redis, shared by every instance.
+// Each room uses a sorted set of messages scored by sequence number, a
+// counter, and with MaxAge a sorted set of append times used to trim it.
+type RedisHistoryStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisHistoryStore creates a store keeping history under keys with the
+// given prefix. An empty prefix uses DefaultHistoryKeyPrefix.
+func NewRedisHistoryStore(client *redis.Client, prefix string) *RedisHistoryStore {
+	if prefix == "" {
+		prefix = DefaultHistoryKeyPrefix
+	}
+	return &RedisHistoryStore{client: client, prefix: prefix}
+}
+
+// appendScript numbers and stores a message, then trims the room to its
+// policy. Members are prefixed with their sequence number so identical
+// payloads stay distinct. The counter never expires, so numbering continues
+// after a quiet room's messages expire.
+//
+// KEYS[1] messages, KEYS[2] counter, KEYS[3] append times
+// ARGV[1] payload, ARGV[2] max messages, ARGV[3] max age (ms), ARGV[4] now (ms),
+// ARGV[5] the append time before which messages expire (ms)
+var appendScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[2])
+redis.call('ZADD', KEYS[1], seq, seq .. ':' .. ARGV[1])
+
+local maxMessages = tonumber(ARGV[2])
+if maxMessages > 0 then
+	redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -maxMessages - 1)
+end
+
+local maxAge = tonumber(ARGV[3])
+if maxAge > 0 then
+	redis.call('ZADD', KEYS[3], ARGV[4], seq)
+	local expired = redis.call('ZREVRANGEBYSCORE', KEYS[3], '(' .. ARGV[5], '-inf', 'LIMIT', 0, 1)
+	if #expired > 0 then
+		redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', expired[1])
+		redis.call('ZREMRANGEBYSCORE', KEYS[3], '-inf', '(' .. ARGV[5])
+	end
+	if maxMessages > 0 then
+		redis.call('ZREMRANGEBYRANK', KEYS[3], 0, -maxMessages - 1)
+	end
+	redis.call('PEXPIRE', KEYS[1], maxAge)
+	redis.call('PEXPIRE', KEYS[3], maxAge)
+end
+
+return seq
+`)
+
+// Append stores a message under the room's next sequence number
+func (s *RedisHistoryStore) Append(ctx context.Context, room string, message *Message, policy HistoryPolicy) (uint64, error) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return 0, err
+	}
+
+	key := s.prefix + room
+	now := time.Now()
+	seq, err := appendScript.Run(ctx, s.client,
+		[]string{key, key + ":seq", key + ":times"},
+		payload, policy.MaxMessages, policy.MaxAge.Milliseconds(), now.UnixMilli(), now.Add(-policy.MaxAge).UnixMilli(),
+	).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(seq), nil
+}
+
+// After returns the room's messages numbered after afterSeq
+func (s *RedisHistoryStore) After(ctx context.Context, room string, afterSeq uint64, limit int) ([]*Message, error) {
+	members, err := s.client.ZRangeByScore(ctx, s.prefix+room, &redis.ZRangeBy{
+		Min:   "(" + strconv.FormatUint(afterSeq, 10),
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0, len(members))
+	for _, member := range members {
+		seq, payload, ok := strings.Cut(member, ":")
+		if !ok {
+			continue
+		}
+		var message Message
+		if err := json.Unmarshal([]byte(payload), &message); err != nil {
+			continue
+		}
+		message.Seq, _ = strconv.ParseUint(seq, 10, 64)
+		messages = append(messages, &message)
+	}
+	return messages, nil
+}
+
+// MemoryHistoryStore keeps room history in memory, for a single instance
+type MemoryHistoryStore struct {
+	mu    sync.Mutex
+	rooms map[string]*memoryRoomHistory
+}
+
+type memoryRoomHistory struct {
+	seq      uint64
+	messages []*Message
+}
+
+// NewMemoryHistoryStore creates an empty in-memory history store
+func NewMemoryHistoryStore() *MemoryHistoryStore {
+	return &MemoryHistoryStore{rooms: make(map[string]*memoryRoomHistory)}
+}
+
+// Append stores a copy of message under the room's next sequence number
+func (s *MemoryHistoryStore) Append(_ context.Context, room string, message *Message, policy HistoryPolicy) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, ok := s.rooms[room]
+	if !ok {
+		history = &memoryRoomHistory{}
+		s.rooms[room] = history
+	}
+	history.seq++
+	stored := *message
+	stored.Seq = history.seq
+	history.messages = append(history.messages, &stored)
+
+	if policy.MaxMessages > 0 && len(history.messages) > policy.MaxMessages {
+		history.messages = append([]*Message(nil), history.messages[len(history.messages)-policy.MaxMessages:]...)
+	}
+	if policy.MaxAge > 0 {
+		cutoff := time.Now().Add(-policy.MaxAge)
+		expired := 0
+		for expired < len(history.messages) && history.messages[expired].Timestamp.Before(cutoff) {
+			expired++
+		}
+		history.messages = history.messages[expired:]
+	}
+	return history.seq, nil
+}
+
+// After returns the room's messages numbered after afterSeq
+func (s *MemoryHistoryStore) After(_ context.Context, room string, afterSeq uint64, limit int) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history, ok := s.rooms[room]
+	if !ok {
+		return nil, nil
+	}
+
+	var messages []*Message
+	for _, message := range history.messages {
+		if message.Seq <= afterSeq {
+			continue
+		}
+		if limit > 0 && len(messages) >= limit {
+			break
+		}
+		stored := *message
+		messages = append(messages, &stored)
+	}
+	return messages, nil
+}
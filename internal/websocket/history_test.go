@@ -0,0 +1,176 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
+)
+
+// newHistoryHub starts a hub keeping the history of "lobby" in memory
+func newHistoryHub(t *testing.T, policy HistoryPolicy) *Hub {
+	t.Helper()
+	hub := NewHub(zap.NewNop())
+	hub.SetHistoryStore(NewMemoryHistoryStore())
+	require.NoError(t, hub.EnableRoomHistory("lobby", policy))
+	go hub.Run()
+	return hub
+}
+
+// TestHub_EnableRoomHistory_Invalid requires a bound on the history kept
+func TestHub_EnableRoomHistory_Invalid(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	assert.ErrorIs(t, hub.EnableRoomHistory("lobby", HistoryPolicy{}), ErrInvalidHistory)
+	assert.ErrorIs(t, hub.EnableRoomHistory("lobby", HistoryPolicy{MaxMessages: -1}), ErrInvalidHistory)
+}
+
+// TestHub_RoomHistory_Sequence numbers room broadcasts and returns those after a sequence number
+func TestHub_RoomHistory_Sequence(t *testing.T) {
+	hub := newHistoryHub(t, HistoryPolicy{MaxMessages: 10})
+	member := newTestClient("member", 0)
+	hub.registerClient(member)
+	hub.handleJoinRoom(&RoomOperation{Client: member, Room: "lobby"})
+
+	for _, id := range []string{"m1", "m2", "m3"} {
+		hub.BroadcastToRoom("lobby", &Message{ID: id, Type: MessageTypeMessage})
+	}
+	for want := uint64(1); want <= 3; want++ {
+		msg := receive(member)
+		require.NotNil(t, msg)
+		assert.Equal(t, want, msg.Seq)
+	}
+
+	history, err := hub.GetRoomHistory("lobby", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "m2", history[0].ID)
+	assert.Equal(t, uint64(3), history[1].Seq)
+
+	history, err = hub.GetRoomHistory("lobby", 0, 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "m1", history[0].ID)
+}
+
+// TestHub_RoomHistory_OptIn keeps nothing for other rooms or ephemeral events
+func TestHub_RoomHistory_OptIn(t *testing.T) {
+	hub := newHistoryHub(t, HistoryPolicy{MaxMessages: 10})
+
+	hub.BroadcastToRoom("other", &Message{ID: "m1", Type: MessageTypeMessage})
+	hub.Typing("lobby", 7)
+
+	_, err := hub.GetRoomHistory("other", 0, 10)
+	assert.ErrorIs(t, err, ErrHistoryDisabled)
+
+	history, err := hub.GetRoomHistory("lobby", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+// TestHub_RoomHistory_Bounded trims history by count and by age
+func TestHub_RoomHistory_Bounded(t *testing.T) {
+	hub := newHistoryHub(t, HistoryPolicy{MaxMessages: 2})
+	for i := 0; i < 5; i++ {
+		hub.BroadcastToRoom("lobby", &Message{Type: MessageTypeMessage})
+	}
+	history, err := hub.GetRoomHistory("lobby", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, uint64(4), history[0].Seq)
+
+	require.NoError(t, hub.EnableRoomHistory("lobby", HistoryPolicy{MaxAge: 20 * time.Millisecond}))
+	hub.BroadcastToRoom("lobby", &Message{Type: MessageTypeMessage})
+	time.Sleep(30 * time.Millisecond)
+
+	// Expired messages are not returned even before the next append trims them
+	history, err = hub.GetRoomHistory("lobby", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+// TestClient_HandleMessage_SubscribeReplay replays the messages after lastSeq on rejoin
+func TestClient_HandleMessage_SubscribeReplay(t *testing.T) {
+	hub := newHistoryHub(t, HistoryPolicy{MaxMessages: 10})
+	for _, id := range []string{"m1", "m2", "m3"} {
+		hub.BroadcastToRoom("lobby", &Message{ID: id, Type: MessageTypeMessage})
+	}
+	// Let the broadcasts go out before the client rejoins, so only the replay delivers them
+	require.Eventually(t, func() bool { return hub.GetMetrics().TotalBroadcasts == 3 }, time.Second, time.Millisecond)
+
+	client := newTestClient("rejoin", 1)
+	client.hub = hub
+	client.logger = zap.NewNop()
+	hub.registerClient(client)
+
+	client.handleMessage(&Message{
+		Type: MessageTypeSubscribe,
+		Data: map[string]interface{}{"room": "lobby", "lastSeq": float64(1)},
+	})
+
+	ack := receive(client)
+	require.NotNil(t, ack)
+	assert.Equal(t, MessageTypeAck, ack.Type)
+	for _, want := range []string{"m2", "m3"} {
+		msg := receive(client)
+		require.NotNil(t, msg)
+		assert.Equal(t, want, msg.ID)
+	}
+	assert.Nil(t, receive(client))
+}
+
+// TestMemoryHistoryStore_SequencePerRoom numbers each room's messages separately
+func TestMemoryHistoryStore_SequencePerRoom(t *testing.T) {
+	store := NewMemoryHistoryStore()
+	ctx := context.Background()
+	policy := HistoryPolicy{MaxMessages: 10}
+
+	seq, _ := store.Append(ctx, "a", &Message{ID: "a1"}, policy)
+	assert.Equal(t, uint64(1), seq)
+	seq, _ = store.Append(ctx, "b", &Message{ID: "b1"}, policy)
+	assert.Equal(t, uint64(1), seq)
+	seq, _ = store.Append(ctx, "a", &Message{ID: "a2"}, policy)
+	assert.Equal(t, uint64(2), seq)
+
+	messages, err := store.After(ctx, "missing", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+// TestRedisHistoryStore numbers, trims and returns room messages in Redis
+func TestRedisHistoryStore(t *testing.T) {
+	testutil.SkipIfNoRedis(t)
+	client := testutil.NewTestRedisClient(t, testutil.DefaultTestConfig())
+	prefix := "test:websocket:history:" + t.Name() + ":"
+	defer testutil.CleanupRedisKeys(context.Background(), client, prefix+"*")
+
+	store := NewRedisHistoryStore(client, prefix)
+	ctx := context.Background()
+	policy := HistoryPolicy{MaxMessages: 2, MaxAge: time.Minute}
+
+	for i, id := range []string{"m1", "m2", "m3"} {
+		seq, err := store.Append(ctx, "lobby", &Message{ID: id, Type: MessageTypeMessage, Timestamp: time.Now()}, policy)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(i+1), seq)
+	}
+
+	messages, err := store.After(ctx, "lobby", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "m2", messages[0].ID)
+	assert.Equal(t, uint64(2), messages[0].Seq)
+
+	messages, err = store.After(ctx, "lobby", 2, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "m3", messages[0].ID)
+}
+
+// TestNewRedisHistoryStore_DefaultPrefix falls back to the default key prefix
+func TestNewRedisHistoryStore_DefaultPrefix(t *testing.T) {
+	assert.Equal(t, DefaultHistoryKeyPrefix, NewRedisHistoryStore(nil, "").prefix)
+}
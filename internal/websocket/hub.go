@@ -1,12 +1,104 @@
 package websocket
 
 import (
+	"context"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// backplanePublishTimeout bounds how long a broadcast waits on the backplane
+const backplanePublishTimeout = 5 * time.Second
+
+// ConnectionLimitPolicy decides what happens when a user exceeds
+// HubConfig.MaxConnectionsPerUser
+type ConnectionLimitPolicy string
+
+const (
+	// ConnectionLimitReject closes the new connection
+	ConnectionLimitReject ConnectionLimitPolicy = "reject"
+	// ConnectionLimitEvictOldest closes the user's oldest connection to make
+	// room for the new one
+	ConnectionLimitEvictOldest ConnectionLimitPolicy = "evict_oldest"
+)
+
+// HubConfig holds hub configuration
+type HubConfig struct {
+	// MaxConnectionsPerUser caps concurrent connections per authenticated
+	// user. Zero means unlimited; anonymous connections are not limited.
+	MaxConnectionsPerUser int                   `mapstructure:"max_connections_per_user"`
+	ConnectionLimitPolicy ConnectionLimitPolicy `mapstructure:"connection_limit_policy"`
+
+	// ClientRateLimit limits the messages each client sends, whatever the
+	// room; rooms may add their own limit with SetRoomRateLimit
+	ClientRateLimit   MessageRateLimit `mapstructure:"client_rate_limit"`
+	RateLimitPolicy   RateLimitPolicy  `mapstructure:"rate_limit_policy"`
+	RateLimitMaxDelay time.Duration    `mapstructure:"rate_limit_max_delay"` // for the delay policy
+	// MaxRateLimitViolations disconnects a client once this many of its
+	// messages exceeded a rate limit; zero never disconnects
+	MaxRateLimitViolations int `mapstructure:"max_rate_limit_violations"`
+
+	// AckTimeout is how long a client has to acknowledge a message that
+	// requires ack before its delivery is reported dropped;
+	// DefaultAckTimeout if not positive
+	AckTimeout time.Duration `mapstructure:"ack_timeout"`
+}
+
+// DefaultAckTimeout is the AckTimeout used unless configured otherwise
+const DefaultAckTimeout = 30 * time.Second
+
+// DefaultHubConfig returns default configuration
+func DefaultHubConfig() *HubConfig {
+	return &HubConfig{
+		MaxConnectionsPerUser: 0,
+		ConnectionLimitPolicy: ConnectionLimitReject,
+		RateLimitPolicy:       RateLimitDrop,
+		RateLimitMaxDelay:     time.Second,
+		AckTimeout:            DefaultAckTimeout,
+	}
+}
+
+// DeliveryStatus is the outcome of delivering a message to one client
+type DeliveryStatus string
+
+const (
+	// DeliveryDelivered means the client acknowledged the message
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDropped means the message never reached the client
+	DeliveryDropped DeliveryStatus = "dropped"
 )
 
+// Reasons a delivery was dropped
+const (
+	DropReasonBufferFull   = "buffer_full"
+	DropReasonDisconnected = "disconnected"
+	DropReasonAckTimeout   = "ack_timeout"
+)
+
+// DeliveryReceipt reports the delivery of a message to one client
+type DeliveryReceipt struct {
+	MessageID string
+	ClientID  string
+	UserID    uint
+	Status    DeliveryStatus
+	// Reason explains a dropped delivery
+	Reason    string
+	Timestamp time.Time
+}
+
+// pendingAck tracks the clients that have yet to acknowledge a message
+type pendingAck struct {
+	onReceipt func(DeliveryReceipt)
+	clients   map[*Client]bool
+	// timeout drops the clients still pending after the ack timeout
+	timeout *time.Timer
+}
+
 // Hub maintains active clients and broadcasts messages
 type Hub struct {
 	// Registered clients
@@ -39,6 +131,52 @@ type Hub struct {
 
 	// Metrics
 	metrics *HubMetrics
+
+	// Configuration
+	config *HubConfig
+
+	// Registration counter, used to find a user's oldest connection
+	nextSeq uint64
+
+	// Instance ID used as the origin of published broadcasts
+	id string
+
+	// Optional backplane relaying broadcasts to other instances
+	backplane Backplane
+
+	// Outstanding deliveries of messages that require ack, by message ID
+	acks  map[string]*pendingAck
+	ackMu sync.Mutex
+
+	// Room metadata, alongside roomClients
+	rooms map[string]*RoomInfo
+
+	// Optional hook deciding who may join a room
+	authorizer RoomAuthorizer
+
+	// Presence of users connected to this hub
+	presence map[uint]PresenceStatus
+
+	// Presence by room learned from other instances through the backplane
+	remotePresence map[string]map[uint]PresenceStatus
+
+	// Hub-generated messages waiting for the current operation to finish
+	events []*Message
+
+	// Optional message rate limiters, by client ID, for every message and
+	// per room
+	clientLimiter *resilience.KeyedRateLimiter
+	roomLimiters  map[string]*resilience.KeyedRateLimiter
+
+	// Optional store of room history, and the rooms keeping it
+	history         HistoryStore
+	historyPolicies map[string]HistoryPolicy
+
+	// Shutdown state: quit stops Run, done is closed once it has stopped
+	running      bool
+	shuttingDown bool
+	quit         chan struct{}
+	done         chan struct{}
 }
 
 // HubMetrics holds hub metrics (internal, contains mutex)
@@ -48,26 +186,69 @@ type HubMetrics struct {
 	TotalMessages     int64
 	TotalBroadcasts   int64
 	TotalRooms        int
-	mutex             sync.RWMutex
+	// DroppedMessages counts messages skipped because a client's send
+	// buffer was full
+	DroppedMessages int64
+	// RejectedConnections counts connections refused by the per-user limit
+	RejectedConnections int64
+	// EvictedConnections counts connections closed to make room for a newer
+	// one under the per-user limit
+	EvictedConnections int64
+	// RateLimitedMessages counts client messages dropped by a rate limit
+	RateLimitedMessages int64
+	// RateLimitDisconnects counts clients closed for exceeding rate limits
+	RateLimitDisconnects int64
+	mutex                sync.RWMutex
 }
 
 // HubMetricsSnapshot is a read-only snapshot of HubMetrics (safe to copy)
 type HubMetricsSnapshot struct {
-	TotalConnections  int64
-	ActiveConnections int64
-	TotalMessages     int64
-	TotalBroadcasts   int64
-	TotalRooms        int
+	TotalConnections     int64
+	ActiveConnections    int64
+	TotalMessages        int64
+	TotalBroadcasts      int64
+	TotalRooms           int
+	DroppedMessages      int64
+	RejectedConnections  int64
+	EvictedConnections   int64
+	RateLimitedMessages  int64
+	RateLimitDisconnects int64
 }
 
 // RoomOperation represents a room join/leave operation
 type RoomOperation struct {
 	Client *Client
 	Room   string
+
+	// ack confirms a successful join to the client, for joins it requested
+	ack bool
+
+	// replayAfter, when set, replays the room history after this sequence
+	// number once the client has joined
+	replayAfter *uint64
 }
 
-// NewHub creates a new hub
+// NewHub creates a new hub that only reaches clients on this instance
 func NewHub(logger *zap.Logger) *Hub {
+	return NewHubWithConfig(logger, DefaultHubConfig(), nil)
+}
+
+// NewHubWithBackplane creates a new hub that relays broadcasts to hubs on
+// other instances through backplane. A nil backplane keeps broadcasts local.
+func NewHubWithBackplane(logger *zap.Logger, backplane Backplane) *Hub {
+	return NewHubWithConfig(logger, DefaultHubConfig(), backplane)
+}
+
+// NewHubWithConfig creates a new hub with the given configuration and
+// optional backplane. A nil config uses DefaultHubConfig.
+func NewHubWithConfig(logger *zap.Logger, config *HubConfig, backplane Backplane) *Hub {
+	if config == nil {
+		config = DefaultHubConfig()
+	}
+	var clientLimiter *resilience.KeyedRateLimiter
+	if config.ClientRateLimit.enabled() {
+		clientLimiter = newMessageLimiter("websocket-client", config.ClientRateLimit, config.RateLimitMaxDelay)
+	}
 	return &Hub{
 		clients:     make(map[*Client]bool),
 		userClients: make(map[uint]map[*Client]bool),
@@ -79,11 +260,43 @@ func NewHub(logger *zap.Logger) *Hub {
 		leaveRoom:   make(chan *RoomOperation),
 		logger:      logger,
 		metrics:     &HubMetrics{},
+		config:      config,
+		id:          uuid.New().String(),
+		backplane:   backplane,
+		acks:        make(map[string]*pendingAck),
+		rooms:       make(map[string]*RoomInfo),
+		presence:    make(map[uint]PresenceStatus),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+
+		remotePresence:  make(map[string]map[uint]PresenceStatus),
+		historyPolicies: make(map[string]HistoryPolicy),
+		clientLimiter:   clientLimiter,
+		roomLimiters:    make(map[string]*resilience.KeyedRateLimiter),
 	}
 }
 
-// Run starts the hub
+// Run starts the hub and blocks until Shutdown. With a backplane it first
+// subscribes to broadcasts from other instances; if that fails the hub runs
+// local-only.
 func (h *Hub) Run() {
+	h.mutex.Lock()
+	if h.shuttingDown {
+		h.mutex.Unlock()
+		return
+	}
+	h.running = true
+	h.mutex.Unlock()
+	defer close(h.done)
+
+	if h.backplane != nil {
+		if err := h.backplane.Subscribe(context.Background(), h.deliverRemote); err != nil {
+			h.logger.Warn("Failed to subscribe to backplane, broadcasts stay local",
+				zap.Error(err),
+			)
+		}
+	}
+
 	for {
 		select {
 		case client := <-h.register:
@@ -100,21 +313,39 @@ func (h *Hub) Run() {
 
 		case message := <-h.broadcast:
 			h.handleBroadcast(message)
+
+		case <-h.quit:
+			return
 		}
+
+		h.flushEvents()
 	}
 }
 
-// registerClient registers a new client
+// registerClient registers a new client, enforcing the per-user connection
+// limit
 func (h *Hub) registerClient(client *Client) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
+	if h.shuttingDown {
+		client.closeSend(goingAwayFrame)
+		return
+	}
+
+	if client.UserID > 0 && !h.makeRoomForUser(client) {
+		return
+	}
+
+	h.nextSeq++
+	client.seq = h.nextSeq
 	h.clients[client] = true
 
-	// Add to user clients
+	// Add to user clients; a user's first connection brings them online
 	if client.UserID > 0 {
 		if _, ok := h.userClients[client.UserID]; !ok {
 			h.userClients[client.UserID] = make(map[*Client]bool)
+			h.presence[client.UserID] = PresenceOnline
 		}
 		h.userClients[client.UserID][client] = true
 	}
@@ -130,48 +361,133 @@ func (h *Hub) registerClient(client *Client) {
 	)
 }
 
-// unregisterClient unregisters a client
+// makeRoomForUser applies the connection limit policy when the client's user
+// is at MaxConnectionsPerUser. It returns false if the client was rejected.
+// Must be called with h.mutex held.
+func (h *Hub) makeRoomForUser(client *Client) bool {
+	limit := h.config.MaxConnectionsPerUser
+	if limit <= 0 || len(h.userClients[client.UserID]) < limit {
+		return true
+	}
+
+	closeMessage := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "connection limit exceeded")
+
+	if h.config.ConnectionLimitPolicy != ConnectionLimitEvictOldest {
+		client.closeSend(closeMessage)
+
+		h.metrics.mutex.Lock()
+		h.metrics.RejectedConnections++
+		h.metrics.mutex.Unlock()
+
+		h.logger.Warn("Client rejected, connection limit reached",
+			zap.String("client_id", client.ID),
+			zap.Uint("user_id", client.UserID),
+			zap.Int("limit", limit),
+		)
+		return false
+	}
+
+	for len(h.userClients[client.UserID]) >= limit {
+		var oldest *Client
+		for c := range h.userClients[client.UserID] {
+			if oldest == nil || c.seq < oldest.seq {
+				oldest = c
+			}
+		}
+		h.removeClient(oldest, closeMessage)
+
+		h.metrics.mutex.Lock()
+		h.metrics.EvictedConnections++
+		h.metrics.mutex.Unlock()
+
+		h.logger.Warn("Client evicted, connection limit reached",
+			zap.String("client_id", oldest.ID),
+			zap.Uint("user_id", oldest.UserID),
+			zap.Int("limit", limit),
+		)
+	}
+	return true
+}
+
+// unregisterClient unregisters a client. Clients already removed, such as
+// evicted or rejected ones whose pumps exit later, are ignored.
 func (h *Hub) unregisterClient(client *Client) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
 	if _, ok := h.clients[client]; ok {
-		delete(h.clients, client)
-		close(client.send)
-
-		// Remove from user clients
-		if client.UserID > 0 {
-			if clients, ok := h.userClients[client.UserID]; ok {
-				delete(clients, client)
-				if len(clients) == 0 {
-					delete(h.userClients, client.UserID)
-				}
+		h.removeClient(client, nil)
+
+		h.logger.Debug("Client unregistered",
+			zap.String("client_id", client.ID),
+		)
+	}
+}
+
+// removeClient removes a registered client from all bookkeeping and closes
+// it with the given close frame payload. Rooms the user is no longer in are
+// told the user went offline. Must be called with h.mutex held.
+func (h *Hub) removeClient(client *Client, closeMessage []byte) {
+	delete(h.clients, client)
+	client.closeSend(closeMessage)
+	h.dropPendingAcks(client)
+
+	// Remove from user clients; the last connection takes the user offline
+	if client.UserID > 0 {
+		for room := range client.Rooms {
+			if !h.userInRoom(client.UserID, room, client) {
+				h.queueEvent(newPresenceMessage(room, client.UserID, PresenceOffline))
 			}
 		}
-
-		// Remove from all rooms
-		for room, clients := range h.roomClients {
+		if clients, ok := h.userClients[client.UserID]; ok {
 			delete(clients, client)
 			if len(clients) == 0 {
-				delete(h.roomClients, room)
+				delete(h.userClients, client.UserID)
+				delete(h.presence, client.UserID)
 			}
 		}
+	}
 
-		h.metrics.mutex.Lock()
-		h.metrics.ActiveConnections--
-		h.metrics.mutex.Unlock()
-
-		h.logger.Debug("Client unregistered",
-			zap.String("client_id", client.ID),
-		)
+	// Remove from all rooms
+	for room, clients := range h.roomClients {
+		delete(clients, client)
+		h.dropRoomIfEmpty(room)
 	}
+
+	h.metrics.mutex.Lock()
+	h.metrics.ActiveConnections--
+	h.metrics.TotalRooms = len(h.roomClients)
+	h.metrics.mutex.Unlock()
 }
 
-// handleJoinRoom handles a room join operation
+// handleJoinRoom handles a room join operation. The client joins only
+// after the room authorizer and the room's member limit accept it. Rejected
+// joins are reported to the client.
 func (h *Hub) handleJoinRoom(op *RoomOperation) {
+	if err := h.authorizeJoin(op.Client, op.Room); err != nil {
+		h.rejectJoin(op, err)
+		return
+	}
+
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
+	info, ok := h.rooms[op.Room]
+	if !ok {
+		info = &RoomInfo{Name: op.Room, CreatedAt: time.Now(), CreatorID: op.Client.UserID}
+	}
+	members := h.roomClients[op.Room]
+	if info.MaxMembers > 0 && !members[op.Client] && len(members) >= info.MaxMembers {
+		h.rejectJoin(op, ErrRoomFull)
+		return
+	}
+	h.rooms[op.Room] = info
+
+	// Announce the user to the room when this is their first connection in it
+	if status, ok := h.presence[op.Client.UserID]; ok && !h.userInRoom(op.Client.UserID, op.Room, nil) {
+		h.queueEvent(newPresenceMessage(op.Room, op.Client.UserID, status))
+	}
+
 	if _, ok := h.roomClients[op.Room]; !ok {
 		h.roomClients[op.Room] = make(map[*Client]bool)
 	}
@@ -182,12 +498,43 @@ func (h *Hub) handleJoinRoom(op *RoomOperation) {
 	h.metrics.TotalRooms = len(h.roomClients)
 	h.metrics.mutex.Unlock()
 
+	if op.ack {
+		op.Client.Send(&Message{
+			Type:      MessageTypeAck,
+			Data:      map[string]string{"action": "subscribed", "room": op.Room},
+			Timestamp: time.Now(),
+		})
+	}
+	if op.replayAfter != nil {
+		go h.replayHistory(op.Client, op.Room, *op.replayAfter)
+	}
+
 	h.logger.Debug("Client joined room",
 		zap.String("client_id", op.Client.ID),
 		zap.String("room", op.Room),
 	)
 }
 
+// rejectJoin tells the client its join was refused
+func (h *Hub) rejectJoin(op *RoomOperation, err error) {
+	op.Client.Send(&Message{
+		Type: MessageTypeError,
+		Room: op.Room,
+		Data: map[string]string{
+			"action": "subscribe",
+			"room":   op.Room,
+			"error":  err.Error(),
+		},
+		Timestamp: time.Now(),
+	})
+
+	h.logger.Debug("Client room join rejected",
+		zap.String("client_id", op.Client.ID),
+		zap.String("room", op.Room),
+		zap.Error(err),
+	)
+}
+
 // handleLeaveRoom handles a room leave operation
 func (h *Hub) handleLeaveRoom(op *RoomOperation) {
 	h.mutex.Lock()
@@ -195,9 +542,10 @@ func (h *Hub) handleLeaveRoom(op *RoomOperation) {
 
 	if clients, ok := h.roomClients[op.Room]; ok {
 		delete(clients, op.Client)
-		if len(clients) == 0 {
-			delete(h.roomClients, op.Room)
-		}
+		h.dropRoomIfEmpty(op.Room)
+	}
+	if op.Client.Rooms[op.Room] && op.Client.UserID > 0 && !h.userInRoom(op.Client.UserID, op.Room, nil) {
+		h.queueEvent(newPresenceMessage(op.Room, op.Client.UserID, PresenceOffline))
 	}
 	delete(op.Client.Rooms, op.Room)
 
@@ -223,6 +571,14 @@ func (h *Hub) handleBroadcast(message *Message) {
 	var targets map[*Client]bool
 
 	switch {
+	case len(message.Rooms) > 0:
+		// Send to several rooms, once per client
+		targets = make(map[*Client]bool)
+		for _, room := range message.Rooms {
+			for client := range h.roomClients[room] {
+				targets[client] = true
+			}
+		}
 	case message.Room != "":
 		// Send to room
 		targets = h.roomClients[message.Room]
@@ -234,13 +590,20 @@ func (h *Hub) handleBroadcast(message *Message) {
 		targets = h.clients
 	}
 
+	if message.onReceipt != nil {
+		h.deliverWithAck(message, targets)
+		return
+	}
+
+	// Presence and typing events are about message.UserID and are not
+	// echoed back to that user
+	skipSubject := message.Type == MessageTypePresence || message.Type == MessageTypeTyping
+
 	for client := range targets {
-		select {
-		case client.send <- message:
-			h.metrics.mutex.Lock()
-			h.metrics.TotalMessages++
-			h.metrics.mutex.Unlock()
-		default:
+		if skipSubject && client.UserID == message.UserID {
+			continue
+		}
+		if !h.enqueue(client, message) {
 			// Client's send buffer is full, skip
 			h.logger.Warn("Client send buffer full",
 				zap.String("client_id", client.ID),
@@ -249,31 +612,260 @@ func (h *Hub) handleBroadcast(message *Message) {
 	}
 }
 
-// Broadcast sends a message to all clients
+// enqueue queues a message on a client's send buffer without blocking and
+// reports whether it fit. Must be called with h.mutex held.
+func (h *Hub) enqueue(client *Client, message *Message) bool {
+	h.metrics.mutex.Lock()
+	defer h.metrics.mutex.Unlock()
+
+	select {
+	case client.send <- message:
+		h.metrics.TotalMessages++
+		return true
+	default:
+		h.metrics.DroppedMessages++
+		return false
+	}
+}
+
+// deliverWithAck queues a message that requires ack and tracks each target
+// until it acknowledges. Clients whose buffer is full get a dropped receipt.
+// Must be called with h.mutex held.
+func (h *Hub) deliverWithAck(message *Message, targets map[*Client]bool) {
+	var dropped []DeliveryReceipt
+
+	// Track before queueing so a fast ack cannot arrive before its entry
+	h.ackMu.Lock()
+	pending := &pendingAck{onReceipt: message.onReceipt, clients: make(map[*Client]bool)}
+	h.acks[message.ID] = pending
+	for client := range targets {
+		pending.clients[client] = true
+		if !h.enqueue(client, message) {
+			delete(pending.clients, client)
+			dropped = append(dropped, newReceipt(message.ID, client, DeliveryDropped, DropReasonBufferFull))
+			h.logger.Warn("Client send buffer full",
+				zap.String("client_id", client.ID),
+				zap.String("message_id", message.ID),
+			)
+		}
+	}
+	if len(pending.clients) == 0 {
+		delete(h.acks, message.ID)
+	} else {
+		pending.timeout = time.AfterFunc(h.ackTimeout(), func() {
+			h.expireAck(message.ID, pending)
+		})
+	}
+	h.ackMu.Unlock()
+
+	for _, receipt := range dropped {
+		message.onReceipt(receipt)
+	}
+}
+
+// ackTimeout returns the configured ack timeout, or DefaultAckTimeout
+func (h *Hub) ackTimeout() time.Duration {
+	if h.config.AckTimeout > 0 {
+		return h.config.AckTimeout
+	}
+	return DefaultAckTimeout
+}
+
+// expireAck settles a delivery whose ack timeout passed, reporting the
+// clients that have not acknowledged it as dropped
+func (h *Hub) expireAck(messageID string, pending *pendingAck) {
+	h.ackMu.Lock()
+	if h.acks[messageID] != pending {
+		// Settled in the meantime
+		h.ackMu.Unlock()
+		return
+	}
+	delete(h.acks, messageID)
+	clients := make([]*Client, 0, len(pending.clients))
+	for client := range pending.clients {
+		clients = append(clients, client)
+	}
+	pending.clients = nil
+	h.ackMu.Unlock()
+
+	for _, client := range clients {
+		h.logger.Debug("Client did not acknowledge message",
+			zap.String("client_id", client.ID),
+			zap.String("message_id", messageID),
+		)
+		pending.onReceipt(newReceipt(messageID, client, DeliveryDropped, DropReasonAckTimeout))
+	}
+}
+
+// settleAck removes a delivery every client has settled and stops its
+// timeout. Must be called with h.ackMu held.
+func (h *Hub) settleAck(messageID string, pending *pendingAck) {
+	delete(h.acks, messageID)
+	if pending.timeout != nil {
+		pending.timeout.Stop()
+	}
+}
+
+// acknowledge records a client's ack of a message and emits a delivered
+// receipt. Acks for unknown or already settled deliveries are ignored.
+func (h *Hub) acknowledge(client *Client, messageID string) {
+	h.ackMu.Lock()
+	pending, ok := h.acks[messageID]
+	if !ok || !pending.clients[client] {
+		h.ackMu.Unlock()
+		return
+	}
+	delete(pending.clients, client)
+	if len(pending.clients) == 0 {
+		h.settleAck(messageID, pending)
+	}
+	h.ackMu.Unlock()
+
+	pending.onReceipt(newReceipt(messageID, client, DeliveryDelivered, ""))
+}
+
+// dropPendingAcks settles a disconnecting client's outstanding deliveries as
+// dropped
+func (h *Hub) dropPendingAcks(client *Client) {
+	type drop struct {
+		messageID string
+		onReceipt func(DeliveryReceipt)
+	}
+	var drops []drop
+
+	h.ackMu.Lock()
+	for messageID, pending := range h.acks {
+		if !pending.clients[client] {
+			continue
+		}
+		delete(pending.clients, client)
+		if len(pending.clients) == 0 {
+			h.settleAck(messageID, pending)
+		}
+		drops = append(drops, drop{messageID, pending.onReceipt})
+	}
+	h.ackMu.Unlock()
+
+	for _, d := range drops {
+		d.onReceipt(newReceipt(d.messageID, client, DeliveryDropped, DropReasonDisconnected))
+	}
+}
+
+func newReceipt(messageID string, client *Client, status DeliveryStatus, reason string) DeliveryReceipt {
+	return DeliveryReceipt{
+		MessageID: messageID,
+		ClientID:  client.ID,
+		UserID:    client.UserID,
+		Status:    status,
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+}
+
+// Broadcast sends a message to all clients, or to its room when set
 func (h *Hub) Broadcast(message *Message) {
-	h.broadcast <- message
+	h.recordHistory(message)
+	if submit(h, h.broadcast, message) {
+		h.publish(message)
+	}
 }
 
-// BroadcastToRoom sends a message to all clients in a room
+// BroadcastToRoom sends a message to all clients in a room. In a room that
+// keeps history the message is stored and numbered first.
 func (h *Hub) BroadcastToRoom(room string, message *Message) {
 	message.Room = room
-	h.broadcast <- message
+	h.recordHistory(message)
+	if submit(h, h.broadcast, message) {
+		h.publish(message)
+	}
+}
+
+// BroadcastToRooms sends a message to all clients in any of the rooms. A
+// client in several of the rooms receives it once, and it counts as a single
+// broadcast. It is not kept in room history.
+func (h *Hub) BroadcastToRooms(rooms []string, message *Message) {
+	message.Rooms = append([]string(nil), rooms...)
+	if submit(h, h.broadcast, message) {
+		h.publish(message)
+	}
 }
 
 // BroadcastToUser sends a message to all clients of a specific user
 func (h *Hub) BroadcastToUser(userID uint, message *Message) {
 	message.UserID = userID
-	h.broadcast <- message
+	if submit(h, h.broadcast, message) {
+		h.publish(message)
+	}
 }
 
-// JoinRoom adds a client to a room
+// publish relays a message to other instances through the backplane
+func (h *Hub) publish(message *Message) {
+	if h.backplane == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backplanePublishTimeout)
+	defer cancel()
+
+	if err := h.backplane.Publish(ctx, &Envelope{Origin: h.id, Message: message}); err != nil {
+		h.logger.Warn("Failed to publish broadcast to backplane",
+			zap.String("message_id", message.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// deliverRemote delivers a broadcast from the backplane to local clients,
+// skipping broadcasts this hub published itself
+func (h *Hub) deliverRemote(envelope *Envelope) {
+	if envelope.Origin == h.id {
+		return
+	}
+	if envelope.Message.Type == MessageTypePresence {
+		h.trackRemotePresence(envelope.Message)
+	}
+	submit(h, h.broadcast, envelope.Message)
+}
+
+// BroadcastToUserWithAck sends a message to all clients of a user and asks
+// each to acknowledge it. onReceipt is called once per local client with a
+// delivered or dropped receipt, dropped if the client does not acknowledge
+// within the ack timeout. It runs on hub, client or timer goroutines, some
+// holding hub locks, so it must not block or call back into the hub. Clients
+// on other instances receive the message without receipts. A missing message
+// ID is generated.
+func (h *Hub) BroadcastToUserWithAck(userID uint, message *Message, onReceipt func(DeliveryReceipt)) {
+	if message.ID == "" {
+		message.ID = uuid.New().String()
+	}
+	message.RequireAck = true
+	message.onReceipt = onReceipt
+	h.BroadcastToUser(userID, message)
+}
+
+// JoinRoom adds a client to a room, subject to the room authorizer and the
+// room's member limit
 func (h *Hub) JoinRoom(client *Client, room string) {
-	h.joinRoom <- &RoomOperation{Client: client, Room: room}
+	submit(h, h.joinRoom, &RoomOperation{Client: client, Room: room})
+}
+
+// requestJoin adds a client to a room on its own request, acknowledging the
+// join once it is accepted and then replaying the history after replayAfter,
+// if set
+func (h *Hub) requestJoin(client *Client, room string, replayAfter *uint64) {
+	submit(h, h.joinRoom, &RoomOperation{Client: client, Room: room, ack: true, replayAfter: replayAfter})
 }
 
 // LeaveRoom removes a client from a room
 func (h *Hub) LeaveRoom(client *Client, room string) {
-	h.leaveRoom <- &RoomOperation{Client: client, Room: room}
+	submit(h, h.leaveRoom, &RoomOperation{Client: client, Room: room})
+}
+
+// inRoom reports whether a client is in a room
+func (h *Hub) inRoom(client *Client, room string) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.roomClients[room][client]
 }
 
 // GetClientCount returns the number of active clients
@@ -305,11 +897,16 @@ func (h *Hub) GetMetrics() HubMetricsSnapshot {
 	h.metrics.mutex.RLock()
 	defer h.metrics.mutex.RUnlock()
 	return HubMetricsSnapshot{
-		TotalConnections:  h.metrics.TotalConnections,
-		ActiveConnections: h.metrics.ActiveConnections,
-		TotalMessages:     h.metrics.TotalMessages,
-		TotalBroadcasts:   h.metrics.TotalBroadcasts,
-		TotalRooms:        h.metrics.TotalRooms,
+		TotalConnections:     h.metrics.TotalConnections,
+		ActiveConnections:    h.metrics.ActiveConnections,
+		TotalMessages:        h.metrics.TotalMessages,
+		TotalBroadcasts:      h.metrics.TotalBroadcasts,
+		TotalRooms:           h.metrics.TotalRooms,
+		DroppedMessages:      h.metrics.DroppedMessages,
+		RejectedConnections:  h.metrics.RejectedConnections,
+		EvictedConnections:   h.metrics.EvictedConnections,
+		RateLimitedMessages:  h.metrics.RateLimitedMessages,
+		RateLimitDisconnects: h.metrics.RateLimitDisconnects,
 	}
 }
 
@@ -333,9 +930,10 @@ func (h *Hub) GetOnlineUsers() []uint {
 	return users
 }
 
-// SendHeartbeat sends heartbeat to all clients
+// SendHeartbeat sends heartbeat to all clients. Heartbeats are not
+// published, since every instance sends its own.
 func (h *Hub) SendHeartbeat() {
-	h.Broadcast(&Message{
+	submit(h, h.broadcast, &Message{
 		Type:      MessageTypePing,
 		Timestamp: time.Now(),
 	})
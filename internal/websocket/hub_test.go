@@ -1,9 +1,11 @@
 package websocket
 
 import (
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
@@ -326,6 +328,39 @@ func TestHub_handleBroadcast_ToRoom(t *testing.T) {
 	hub.mutex.Unlock()
 }
 
+// TestHub_BroadcastToRooms delivers once to clients in overlapping rooms
+func TestHub_BroadcastToRooms(t *testing.T) {
+	hub := NewHub(testHubLogger())
+	go hub.Run()
+
+	both := newTestClient("both", 0)
+	onlyA := newTestClient("only-a", 0)
+	onlyB := newTestClient("only-b", 0)
+	outside := newTestClient("outside", 0)
+	for _, client := range []*Client{both, onlyA, onlyB, outside} {
+		hub.registerClient(client)
+	}
+	hub.handleJoinRoom(&RoomOperation{Client: both, Room: "a"})
+	hub.handleJoinRoom(&RoomOperation{Client: both, Room: "b"})
+	hub.handleJoinRoom(&RoomOperation{Client: onlyA, Room: "a"})
+	hub.handleJoinRoom(&RoomOperation{Client: onlyB, Room: "b"})
+
+	hub.BroadcastToRooms([]string{"a", "b", "empty"}, &Message{ID: "m1", Type: MessageTypeEvent})
+
+	for _, client := range []*Client{both, onlyA, onlyB} {
+		msg := receive(client)
+		if assert.NotNil(t, msg, client.ID) {
+			assert.Equal(t, "m1", msg.ID)
+		}
+		assert.Nil(t, receive(client), "%s received a duplicate", client.ID)
+	}
+	assert.Nil(t, receive(outside))
+
+	metrics := hub.GetMetrics()
+	assert.Equal(t, int64(1), metrics.TotalBroadcasts)
+	assert.Equal(t, int64(3), metrics.TotalMessages)
+}
+
 // TestHub_handleBroadcast_FullBuffer logs warning for full send buffer
 func TestHub_handleBroadcast_FullBuffer(t *testing.T) {
 	hub := NewHub(testHubLogger())
@@ -432,3 +467,247 @@ func TestHub_MultipleClientsPerUser(t *testing.T) {
 	// User offline now
 	assert.False(t, hub.IsUserOnline(42))
 }
+
+// newLimitedHub creates a hub allowing two connections per user
+func newLimitedHub(policy ConnectionLimitPolicy) *Hub {
+	return NewHubWithConfig(testHubLogger(), &HubConfig{
+		MaxConnectionsPerUser: 2,
+		ConnectionLimitPolicy: policy,
+	}, nil)
+}
+
+// assertClosedWithLimit checks the client was closed with a policy violation frame
+func assertClosedWithLimit(t *testing.T, client *Client) {
+	t.Helper()
+	_, ok := <-client.send
+	assert.False(t, ok, "send channel should be closed")
+	assert.Equal(t,
+		websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "connection limit exceeded"),
+		client.closeMessage)
+}
+
+// TestHub_ConnectionLimit_Reject refuses connections beyond the limit
+func TestHub_ConnectionLimit_Reject(t *testing.T) {
+	hub := newLimitedHub(ConnectionLimitReject)
+
+	c1 := newTestClient("c1", 1)
+	c2 := newTestClient("c2", 1)
+	c3 := newTestClient("c3", 1)
+	hub.registerClient(c1)
+	hub.registerClient(c2)
+	hub.registerClient(c3)
+
+	assert.Equal(t, 2, hub.GetClientCount())
+	assert.Len(t, hub.userClients[1], 2)
+	assert.False(t, hub.userClients[1][c3])
+	assertClosedWithLimit(t, c3)
+
+	// Sending to a rejected client is dropped rather than panicking
+	c3.Send(NewMessage(MessageTypeMessage, "late"))
+
+	// The rejected client's pumps unregistering later is a no-op
+	hub.unregisterClient(c3)
+	metrics := hub.GetMetrics()
+	assert.Equal(t, int64(1), metrics.RejectedConnections)
+	assert.Equal(t, int64(2), metrics.ActiveConnections)
+
+	// Once a connection closes, the user can connect again
+	hub.unregisterClient(c1)
+	c4 := newTestClient("c4", 1)
+	hub.registerClient(c4)
+	assert.True(t, hub.userClients[1][c4])
+}
+
+// TestHub_ConnectionLimit_EvictOldest closes the user's oldest connection
+func TestHub_ConnectionLimit_EvictOldest(t *testing.T) {
+	hub := newLimitedHub(ConnectionLimitEvictOldest)
+
+	c1 := newTestClient("c1", 1)
+	c2 := newTestClient("c2", 1)
+	c3 := newTestClient("c3", 1)
+	hub.registerClient(c1)
+	hub.registerClient(c2)
+	hub.handleJoinRoom(&RoomOperation{Client: c1, Room: "lobby"})
+	hub.registerClient(c3)
+
+	assert.Equal(t, 2, hub.GetClientCount())
+	assert.False(t, hub.userClients[1][c1])
+	assert.True(t, hub.userClients[1][c2])
+	assert.True(t, hub.userClients[1][c3])
+	assert.Equal(t, 0, hub.GetRoomClientCount("lobby"))
+	assertClosedWithLimit(t, c1)
+
+	// The evicted client's ReadPump unregistering later must not touch the
+	// user's remaining connections or double count
+	hub.unregisterClient(c1)
+	assert.Len(t, hub.userClients[1], 2)
+	metrics := hub.GetMetrics()
+	assert.Equal(t, int64(1), metrics.EvictedConnections)
+	assert.Equal(t, int64(2), metrics.ActiveConnections)
+	assert.Equal(t, 0, metrics.TotalRooms)
+}
+
+// TestHub_ConnectionLimit_PerUserAndAnonymous applies the limit per user only
+func TestHub_ConnectionLimit_PerUserAndAnonymous(t *testing.T) {
+	hub := newLimitedHub(ConnectionLimitReject)
+
+	for i := 0; i < 3; i++ {
+		hub.registerClient(newTestClient("anon", 0))
+	}
+	hub.registerClient(newTestClient("u1", 1))
+	hub.registerClient(newTestClient("u2", 2))
+
+	assert.Equal(t, 5, hub.GetClientCount())
+	assert.Equal(t, int64(0), hub.GetMetrics().RejectedConnections)
+}
+
+// TestHub_ConnectionLimit_ConcurrentClose registers and closes connections
+// through the hub loop concurrently and checks the bookkeeping stays consistent
+func TestHub_ConnectionLimit_ConcurrentClose(t *testing.T) {
+	hub := newLimitedHub(ConnectionLimitEvictOldest)
+	go hub.Run()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := newTestClient("c", 1)
+			hub.register <- client
+			hub.unregister <- client
+		}()
+	}
+	wg.Wait()
+
+	assert.Eventually(t, func() bool { return hub.GetClientCount() == 0 }, time.Second, 5*time.Millisecond)
+	assert.False(t, hub.IsUserOnline(1))
+	assert.Equal(t, int64(0), hub.GetMetrics().ActiveConnections)
+}
+
+// receiptRecorder collects delivery receipts
+type receiptRecorder struct {
+	mu       sync.Mutex
+	receipts []DeliveryReceipt
+}
+
+func (r *receiptRecorder) record(receipt DeliveryReceipt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.receipts = append(r.receipts, receipt)
+}
+
+func (r *receiptRecorder) get() []DeliveryReceipt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]DeliveryReceipt(nil), r.receipts...)
+}
+
+// TestHub_BroadcastToUserWithAck_Delivered emits a receipt once the client acks
+func TestHub_BroadcastToUserWithAck_Delivered(t *testing.T) {
+	hub := newTestHub(t)
+	go hub.Run()
+	client := newTestClient("acker", 5)
+	client.hub = hub
+	client.logger = zap.NewNop()
+	hub.registerClient(client)
+
+	recorder := &receiptRecorder{}
+	hub.BroadcastToUserWithAck(5, &Message{Type: MessageTypeNotification}, recorder.record)
+
+	msg := receive(client)
+	if assert.NotNil(t, msg) {
+		assert.True(t, msg.RequireAck)
+		assert.NotEmpty(t, msg.ID)
+	}
+	assert.Empty(t, recorder.get(), "no receipt before the ack")
+
+	client.handleMessage(&Message{Type: MessageTypeAck, ID: msg.ID})
+	client.handleMessage(&Message{Type: MessageTypeAck, ID: msg.ID}) // duplicate ack is ignored
+
+	receipts := recorder.get()
+	if assert.Len(t, receipts, 1) {
+		assert.Equal(t, DeliveryDelivered, receipts[0].Status)
+		assert.Equal(t, msg.ID, receipts[0].MessageID)
+		assert.Equal(t, "acker", receipts[0].ClientID)
+		assert.Equal(t, uint(5), receipts[0].UserID)
+	}
+	assert.Empty(t, hub.acks)
+}
+
+// TestHub_BroadcastToUserWithAck_BufferFull records a dropped receipt
+func TestHub_BroadcastToUserWithAck_BufferFull(t *testing.T) {
+	hub := newTestHub(t)
+	full := &Client{ID: "full", UserID: 5, Rooms: make(map[string]bool), send: make(chan *Message)}
+	ok := newTestClient("ok", 5)
+	hub.registerClient(full)
+	hub.registerClient(ok)
+
+	recorder := &receiptRecorder{}
+	msg := &Message{ID: "m1", UserID: 5, RequireAck: true, onReceipt: recorder.record}
+	hub.handleBroadcast(msg)
+
+	receipts := recorder.get()
+	if assert.Len(t, receipts, 1) {
+		assert.Equal(t, DeliveryDropped, receipts[0].Status)
+		assert.Equal(t, DropReasonBufferFull, receipts[0].Reason)
+		assert.Equal(t, "full", receipts[0].ClientID)
+	}
+	assert.Equal(t, map[*Client]bool{ok: true}, hub.acks["m1"].clients)
+	assert.Equal(t, int64(1), hub.GetMetrics().DroppedMessages)
+}
+
+// TestHub_BroadcastToUserWithAck_Disconnected drops outstanding deliveries on unregister
+func TestHub_BroadcastToUserWithAck_Disconnected(t *testing.T) {
+	hub := newTestHub(t)
+	client := newTestClient("leaver", 5)
+	hub.registerClient(client)
+
+	recorder := &receiptRecorder{}
+	hub.handleBroadcast(&Message{ID: "m1", UserID: 5, RequireAck: true, onReceipt: recorder.record})
+	hub.unregisterClient(client)
+
+	receipts := recorder.get()
+	if assert.Len(t, receipts, 1) {
+		assert.Equal(t, DeliveryDropped, receipts[0].Status)
+		assert.Equal(t, DropReasonDisconnected, receipts[0].Reason)
+	}
+	assert.Empty(t, hub.acks)
+}
+
+// TestHub_handleBroadcast_NoAckNotTracked keeps plain broadcasts out of ack tracking
+func TestHub_handleBroadcast_NoAckNotTracked(t *testing.T) {
+	hub := newTestHub(t)
+	client := newTestClient("plain", 5)
+	hub.registerClient(client)
+
+	hub.handleBroadcast(&Message{ID: "m1", UserID: 5})
+
+	assert.NotNil(t, receive(client))
+	assert.Empty(t, hub.acks)
+}
+
+// TestHub_BroadcastToUserWithAck_Timeout drops deliveries that are never acked
+func TestHub_BroadcastToUserWithAck_Timeout(t *testing.T) {
+	hub := NewHubWithConfig(zap.NewNop(), &HubConfig{AckTimeout: 20 * time.Millisecond}, nil)
+	silent := newTestClient("silent", 5)
+	silent.hub = hub
+	silent.logger = zap.NewNop()
+	hub.registerClient(silent)
+
+	recorder := &receiptRecorder{}
+	hub.handleBroadcast(&Message{ID: "m1", UserID: 5, RequireAck: true, onReceipt: recorder.record})
+
+	assert.Eventually(t, func() bool { return len(recorder.get()) == 1 }, time.Second, 5*time.Millisecond)
+	receipts := recorder.get()
+	assert.Equal(t, DeliveryDropped, receipts[0].Status)
+	assert.Equal(t, DropReasonAckTimeout, receipts[0].Reason)
+	assert.Equal(t, "silent", receipts[0].ClientID)
+
+	hub.ackMu.Lock()
+	assert.Empty(t, hub.acks)
+	hub.ackMu.Unlock()
+
+	// A late ack is ignored
+	silent.handleMessage(&Message{Type: MessageTypeAck, ID: "m1"})
+	assert.Len(t, recorder.get(), 1)
+}
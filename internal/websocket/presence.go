@@ -0,0 +1,181 @@
+package websocket
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PresenceStatus is a user's presence as shown to other room members
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceAway    PresenceStatus = "away"
+	PresenceBusy    PresenceStatus = "busy"
+	PresenceOffline PresenceStatus = "offline"
+)
+
+// newPresenceMessage builds the presence event sent to a room
+func newPresenceMessage(room string, userID uint, status PresenceStatus) *Message {
+	return &Message{
+		Type:   MessageTypePresence,
+		Room:   room,
+		UserID: userID,
+		Data: map[string]interface{}{
+			"userId": userID,
+			"status": string(status),
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// SetPresence sets the presence of a user connected to this hub and
+// announces it to every room the user is in. It returns false if the user has
+// no connection on this hub.
+func (h *Hub) SetPresence(userID uint, status PresenceStatus) bool {
+	h.mutex.Lock()
+	if _, ok := h.userClients[userID]; !ok {
+		h.mutex.Unlock()
+		return false
+	}
+	h.presence[userID] = status
+	rooms := h.userRooms(userID)
+	h.mutex.Unlock()
+
+	for _, room := range rooms {
+		h.BroadcastToRoom(room, newPresenceMessage(room, userID, status))
+	}
+	return true
+}
+
+// GetPresence returns a user's presence, as tracked locally or learned from
+// other instances through the backplane. Unknown users are offline.
+func (h *Hub) GetPresence(userID uint) PresenceStatus {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if status, ok := h.presence[userID]; ok {
+		return status
+	}
+	for _, users := range h.remotePresence {
+		if status, ok := users[userID]; ok {
+			return status
+		}
+	}
+	return PresenceOffline
+}
+
+// GetRoomPresence returns the presence of every user in a room, including
+// users connected to other instances when a backplane is used
+func (h *Hub) GetRoomPresence(room string) map[uint]PresenceStatus {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	presence := make(map[uint]PresenceStatus)
+	for userID, status := range h.remotePresence[room] {
+		presence[userID] = status
+	}
+	for client := range h.roomClients[room] {
+		if status, ok := h.presence[client.UserID]; ok {
+			presence[client.UserID] = status
+		}
+	}
+	return presence
+}
+
+// Typing tells a room that a user is typing. Typing events are fanned out
+// but never stored.
+func (h *Hub) Typing(room string, userID uint) {
+	h.BroadcastToRoom(room, &Message{
+		Type:      MessageTypeTyping,
+		UserID:    userID,
+		Data:      map[string]interface{}{"userId": userID},
+		Timestamp: time.Now(),
+	})
+}
+
+// userRooms returns the rooms any of the user's connections are in. Must be
+// called with h.mutex held.
+func (h *Hub) userRooms(userID uint) []string {
+	seen := make(map[string]bool)
+	var rooms []string
+	for client := range h.userClients[userID] {
+		for room := range client.Rooms {
+			if !seen[room] {
+				seen[room] = true
+				rooms = append(rooms, room)
+			}
+		}
+	}
+	return rooms
+}
+
+// userInRoom reports whether a connection of the user other than except is
+// in the room. Must be called with h.mutex held.
+func (h *Hub) userInRoom(userID uint, room string, except *Client) bool {
+	for client := range h.roomClients[room] {
+		if client != except && client.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// queueEvent queues a hub-generated message for delivery once the current
+// hub operation finishes. Must be called with h.mutex held.
+func (h *Hub) queueEvent(message *Message) {
+	h.events = append(h.events, message)
+}
+
+// flushEvents delivers queued hub-generated messages locally and relays them
+// through the backplane. It runs on the hub goroutine, so the messages are
+// delivered directly rather than through the broadcast channel.
+func (h *Hub) flushEvents() {
+	h.mutex.Lock()
+	events := h.events
+	h.events = nil
+	h.mutex.Unlock()
+
+	for _, message := range events {
+		h.handleBroadcast(message)
+		if h.backplane != nil {
+			go h.publish(message)
+		}
+	}
+}
+
+// trackRemotePresence records presence announced by other instances so it
+// can be reported by GetPresence and GetRoomPresence
+func (h *Hub) trackRemotePresence(message *Message) {
+	data, ok := message.Data.(map[string]interface{})
+	if !ok || message.Room == "" || message.UserID == 0 {
+		return
+	}
+	status, _ := data["status"].(string)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	users, ok := h.remotePresence[message.Room]
+	if PresenceStatus(status) == PresenceOffline || status == "" {
+		if ok {
+			delete(users, message.UserID)
+			if len(users) == 0 {
+				delete(h.remotePresence, message.Room)
+			}
+		}
+		return
+	}
+	if !ok {
+		users = make(map[uint]PresenceStatus)
+		h.remotePresence[message.Room] = users
+	}
+	users[message.UserID] = PresenceStatus(status)
+
+	h.logger.Debug("Remote presence updated",
+		zap.Uint("user_id", message.UserID),
+		zap.String("room", message.Room),
+		zap.String("status", status),
+	)
+}
@@ -0,0 +1,134 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// connect registers a client for the user on a running hub and joins it to rooms
+func connect(t *testing.T, hub *Hub, id string, userID uint, rooms ...string) *Client {
+	t.Helper()
+	client := newTestClient(id, userID)
+	client.hub = hub
+	client.logger = zap.NewNop()
+	hub.register <- client
+	for _, room := range rooms {
+		hub.JoinRoom(client, room)
+	}
+	require.Eventually(t, func() bool {
+		for _, room := range rooms {
+			if !hub.inRoom(client, room) {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 5*time.Millisecond)
+	return client
+}
+
+// assertPresence checks the next message is a presence event for the user
+func assertPresence(t *testing.T, client *Client, room string, userID uint, status PresenceStatus) {
+	t.Helper()
+	msg := receive(client)
+	require.NotNil(t, msg, "%s received no presence event", client.ID)
+	assert.Equal(t, MessageTypePresence, msg.Type)
+	assert.Equal(t, room, msg.Room)
+	assert.Equal(t, userID, msg.UserID)
+	assert.Equal(t, string(status), msg.Data.(map[string]interface{})["status"])
+}
+
+// TestHub_Presence_ConnectAndDisconnect announces users joining and leaving a room
+func TestHub_Presence_ConnectAndDisconnect(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	go hub.Run()
+
+	alice := connect(t, hub, "alice", 1, "lobby")
+	bob := connect(t, hub, "bob", 2, "lobby")
+
+	assertPresence(t, alice, "lobby", 2, PresenceOnline)
+	assert.Nil(t, receive(bob), "users are not told about themselves")
+	assert.Equal(t, PresenceOnline, hub.GetPresence(2))
+	assert.Equal(t, map[uint]PresenceStatus{1: PresenceOnline, 2: PresenceOnline}, hub.GetRoomPresence("lobby"))
+
+	// A second connection of bob does not re-announce him
+	bob2 := connect(t, hub, "bob-2", 2, "lobby")
+	assert.Nil(t, receive(alice))
+
+	// Bob goes offline only when his last connection closes
+	hub.unregister <- bob
+	assert.Nil(t, receive(alice))
+	hub.unregister <- bob2
+	assertPresence(t, alice, "lobby", 2, PresenceOffline)
+
+	assert.Equal(t, PresenceOffline, hub.GetPresence(2))
+	assert.Equal(t, map[uint]PresenceStatus{1: PresenceOnline}, hub.GetRoomPresence("lobby"))
+	hub.mutex.RLock()
+	assert.NotContains(t, hub.presence, uint(2))
+	hub.mutex.RUnlock()
+}
+
+// TestHub_SetPresence announces status changes to the user's rooms
+func TestHub_SetPresence(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	go hub.Run()
+
+	alice := connect(t, hub, "alice", 1, "lobby")
+	bob := connect(t, hub, "bob", 2, "lobby", "dev")
+	carol := connect(t, hub, "carol", 3, "dev")
+	assertPresence(t, alice, "lobby", 2, PresenceOnline)
+	assertPresence(t, bob, "dev", 3, PresenceOnline)
+
+	assert.True(t, hub.SetPresence(2, PresenceAway))
+	assertPresence(t, alice, "lobby", 2, PresenceAway)
+	assertPresence(t, carol, "dev", 2, PresenceAway)
+	assert.Nil(t, receive(bob))
+	assert.Equal(t, PresenceAway, hub.GetPresence(2))
+
+	assert.False(t, hub.SetPresence(99, PresenceBusy))
+	assert.Equal(t, PresenceOffline, hub.GetPresence(99))
+}
+
+// TestHub_Typing fans out typing events to other room members only
+func TestHub_Typing(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	go hub.Run()
+
+	alice := connect(t, hub, "alice", 1, "lobby")
+	bob := connect(t, hub, "bob", 2, "lobby")
+	outsider := connect(t, hub, "outsider", 3)
+	assertPresence(t, alice, "lobby", 2, PresenceOnline)
+
+	bob.handleMessage(&Message{Type: MessageTypeTyping, Room: "lobby"})
+
+	msg := receive(alice)
+	require.NotNil(t, msg)
+	assert.Equal(t, MessageTypeTyping, msg.Type)
+	assert.Equal(t, uint(2), msg.UserID)
+	assert.Nil(t, receive(bob))
+	assert.Nil(t, receive(outsider))
+
+	// Typing in a room the client is not in is ignored
+	outsider.handleMessage(&Message{Type: MessageTypeTyping, Room: "lobby"})
+	assert.Nil(t, receive(alice))
+}
+
+// TestHub_Presence_Backplane shares presence with hubs on other instances
+func TestHub_Presence_Backplane(t *testing.T) {
+	hubA, hubB := startBackplaneHubs(t)
+
+	bob := connect(t, hubB, "bob", 2, "lobby")
+	alice := connect(t, hubA, "alice", 1, "lobby")
+
+	assertPresence(t, bob, "lobby", 1, PresenceOnline)
+	assert.Equal(t, PresenceOnline, hubB.GetPresence(1))
+	assert.Equal(t, map[uint]PresenceStatus{1: PresenceOnline, 2: PresenceOnline}, hubB.GetRoomPresence("lobby"))
+
+	hubA.unregister <- alice
+	assertPresence(t, bob, "lobby", 1, PresenceOffline)
+	assert.Equal(t, PresenceOffline, hubB.GetPresence(1))
+	assert.Equal(t, map[uint]PresenceStatus{2: PresenceOnline}, hubB.GetRoomPresence("lobby"))
+}
@@ -0,0 +1,128 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// RateLimitPolicy decides what happens to a client message beyond its rate
+type RateLimitPolicy string
+
+const (
+	// RateLimitDrop drops the message and tells the client
+	RateLimitDrop RateLimitPolicy = "drop"
+	// RateLimitDelay holds the message, and the client's further reads, until
+	// the rate allows it or HubConfig.RateLimitMaxDelay passes, then drops it
+	RateLimitDelay RateLimitPolicy = "delay"
+)
+
+// MessageRateLimit limits the messages a client sends to Rate per Period,
+// with bursts of up to Burst. A zero Rate means unlimited.
+type MessageRateLimit struct {
+	Rate   int           `mapstructure:"rate"`
+	Period time.Duration `mapstructure:"period"`
+	Burst  int           `mapstructure:"burst"`
+}
+
+// enabled reports whether the limit restricts anything
+func (l MessageRateLimit) enabled() bool {
+	return l.Rate > 0 && l.Period > 0
+}
+
+// newMessageLimiter creates a limiter keeping a bucket per client
+func newMessageLimiter(name string, limit MessageRateLimit, maxDelay time.Duration) *resilience.KeyedRateLimiter {
+	cfg := resilience.DefaultKeyedRateLimiterConfig(name)
+	cfg.Rate = limit.Rate
+	cfg.Period = limit.Period
+	cfg.BurstSize = max(limit.Burst, 1)
+	cfg.WaitTimeout = maxDelay
+	return resilience.NewKeyedRateLimiter(cfg)
+}
+
+// SetRoomRateLimit limits the messages each client sends to a room, on top
+// of HubConfig.ClientRateLimit. The room must exist; a zero limit removes it.
+func (h *Hub) SetRoomRateLimit(room string, limit MessageRateLimit) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	info, ok := h.rooms[room]
+	if !ok {
+		return ErrRoomNotFound
+	}
+	info.RateLimit = limit
+	delete(h.roomLimiters, room)
+	if limit.enabled() {
+		h.roomLimiters[room] = newMessageLimiter("websocket-room-"+room, limit, h.config.RateLimitMaxDelay)
+	}
+	return nil
+}
+
+// allowMessage applies the client's and the room's rate limits to a message
+// the client sent to room, which may be empty. A message beyond the limits is
+// counted and reported to the client, and once the client has exceeded them
+// MaxRateLimitViolations times it is disconnected. It runs on the client's
+// read goroutine.
+func (h *Hub) allowMessage(client *Client, room string) bool {
+	h.mutex.RLock()
+	roomLimiter := h.roomLimiters[room]
+	h.mutex.RUnlock()
+
+	for _, limiter := range []*resilience.KeyedRateLimiter{h.clientLimiter, roomLimiter} {
+		if limiter == nil || h.takeToken(limiter, client.ID) {
+			continue
+		}
+
+		h.metrics.mutex.Lock()
+		h.metrics.RateLimitedMessages++
+		h.metrics.mutex.Unlock()
+
+		client.rateViolations++
+		if limit := h.config.MaxRateLimitViolations; limit > 0 && client.rateViolations >= limit {
+			h.disconnectForRate(client)
+			return false
+		}
+
+		client.Send(&Message{
+			Type:      MessageTypeError,
+			Room:      room,
+			Data:      map[string]string{"action": "message", "room": room, "error": resilience.ErrRateLimitExceeded.Error()},
+			Timestamp: time.Now(),
+		})
+		return false
+	}
+	return true
+}
+
+// takeToken takes a token for key, waiting for one under the delay policy
+func (h *Hub) takeToken(limiter *resilience.KeyedRateLimiter, key string) bool {
+	if h.config.RateLimitPolicy == RateLimitDelay {
+		return limiter.Wait(context.Background(), key) == nil
+	}
+	return limiter.Allow(key)
+}
+
+// disconnectForRate closes a client that kept exceeding its rate limit
+func (h *Hub) disconnectForRate(client *Client) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	h.removeClient(client, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "rate limit exceeded"))
+
+	h.metrics.mutex.Lock()
+	h.metrics.RateLimitDisconnects++
+	h.metrics.mutex.Unlock()
+
+	h.logger.Warn("Client disconnected, rate limit exceeded",
+		zap.String("client_id", client.ID),
+		zap.Uint("user_id", client.UserID),
+		zap.Int("violations", client.rateViolations),
+	)
+}
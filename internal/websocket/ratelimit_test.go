@@ -0,0 +1,178 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jrjohn/arcana-cloud-go/internal/resilience"
+)
+
+// newRateLimitedHub creates a running hub with the given rate limit settings
+func newRateLimitedHub(t *testing.T, config *HubConfig) *Hub {
+	t.Helper()
+	hub := NewHubWithConfig(testHubLogger(), config, nil)
+	go hub.Run()
+	return hub
+}
+
+// joinRateLimitedRoom registers a client and joins it to room
+func joinRateLimitedRoom(t *testing.T, hub *Hub, id string, userID uint, room string) *Client {
+	t.Helper()
+	client := newTestClient(id, userID)
+	client.hub = hub
+	hub.registerClient(client)
+	hub.handleJoinRoom(&RoomOperation{Client: client, Room: room})
+	require.True(t, hub.inRoom(client, room))
+	drain(client)
+	return client
+}
+
+// drain discards the messages queued for a client
+func drain(client *Client) {
+	for receive(client) != nil {
+	}
+}
+
+// chat builds a chat message to room
+func chat(room string) *Message {
+	return &Message{Type: MessageTypeMessage, Room: room, Data: "hi"}
+}
+
+// TestHub_ClientRateLimit_Drop drops messages beyond the client rate and tells the sender
+func TestHub_ClientRateLimit_Drop(t *testing.T) {
+	hub := newRateLimitedHub(t, &HubConfig{
+		ClientRateLimit: MessageRateLimit{Rate: 2, Period: time.Minute, Burst: 2},
+		RateLimitPolicy: RateLimitDrop,
+	})
+	sender := joinRateLimitedRoom(t, hub, "sender", 1, "lobby")
+
+	for range 3 {
+		sender.handleMessage(chat("lobby"))
+	}
+
+	var broadcasts, errs int
+	for msg := receive(sender); msg != nil; msg = receive(sender) {
+		switch msg.Type {
+		case MessageTypeMessage:
+			broadcasts++
+		case MessageTypeError:
+			errs++
+			data := msg.Data.(map[string]string)
+			assert.Equal(t, resilience.ErrRateLimitExceeded.Error(), data["error"])
+		}
+	}
+	assert.Equal(t, 2, broadcasts)
+	assert.Equal(t, 1, errs)
+	assert.Equal(t, int64(1), hub.GetMetrics().RateLimitedMessages)
+}
+
+// TestHub_ClientRateLimit_PerClient limits each client separately
+func TestHub_ClientRateLimit_PerClient(t *testing.T) {
+	hub := newRateLimitedHub(t, &HubConfig{
+		ClientRateLimit: MessageRateLimit{Rate: 1, Period: time.Minute, Burst: 1},
+	})
+	first := joinRateLimitedRoom(t, hub, "first", 1, "lobby")
+	second := joinRateLimitedRoom(t, hub, "second", 2, "lobby")
+
+	assert.True(t, hub.allowMessage(first, "lobby"))
+	assert.False(t, hub.allowMessage(first, "lobby"))
+	assert.True(t, hub.allowMessage(second, "lobby"), "another client has its own budget")
+}
+
+// TestHub_SetRoomRateLimit limits messages to one room only
+func TestHub_SetRoomRateLimit(t *testing.T) {
+	hub := newRateLimitedHub(t, DefaultHubConfig())
+	_, err := hub.CreateRoom("busy", 1, 0)
+	require.NoError(t, err)
+	_, err = hub.CreateRoom("quiet", 1, 0)
+	require.NoError(t, err)
+
+	limit := MessageRateLimit{Rate: 1, Period: time.Minute, Burst: 1}
+	require.NoError(t, hub.SetRoomRateLimit("busy", limit))
+	assert.ErrorIs(t, hub.SetRoomRateLimit("missing", limit), ErrRoomNotFound)
+
+	info, ok := hub.GetRoomInfo("busy")
+	require.True(t, ok)
+	assert.Equal(t, limit, info.RateLimit)
+
+	client := joinRateLimitedRoom(t, hub, "c1", 1, "busy")
+	assert.True(t, hub.allowMessage(client, "busy"))
+	assert.False(t, hub.allowMessage(client, "busy"))
+	assert.True(t, hub.allowMessage(client, "quiet"), "other rooms are not limited")
+	assert.True(t, hub.allowMessage(client, ""), "broadcasts to all are not room limited")
+
+	// A zero limit lifts it
+	require.NoError(t, hub.SetRoomRateLimit("busy", MessageRateLimit{}))
+	assert.True(t, hub.allowMessage(client, "busy"))
+}
+
+// TestHub_RateLimit_Delay holds messages until the rate allows them
+func TestHub_RateLimit_Delay(t *testing.T) {
+	hub := newRateLimitedHub(t, &HubConfig{
+		ClientRateLimit:   MessageRateLimit{Rate: 1, Period: 50 * time.Millisecond, Burst: 1},
+		RateLimitPolicy:   RateLimitDelay,
+		RateLimitMaxDelay: time.Second,
+	})
+	client := joinRateLimitedRoom(t, hub, "c1", 1, "lobby")
+
+	start := time.Now()
+	assert.True(t, hub.allowMessage(client, "lobby"))
+	assert.True(t, hub.allowMessage(client, "lobby"), "the message waits for the rate")
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Zero(t, hub.GetMetrics().RateLimitedMessages)
+}
+
+// TestHub_RateLimit_Delay_DropsAfterMaxDelay drops messages that would wait too long
+func TestHub_RateLimit_Delay_DropsAfterMaxDelay(t *testing.T) {
+	hub := newRateLimitedHub(t, &HubConfig{
+		ClientRateLimit:   MessageRateLimit{Rate: 1, Period: time.Minute, Burst: 1},
+		RateLimitPolicy:   RateLimitDelay,
+		RateLimitMaxDelay: 20 * time.Millisecond,
+	})
+	client := joinRateLimitedRoom(t, hub, "c1", 1, "lobby")
+
+	assert.True(t, hub.allowMessage(client, "lobby"))
+	assert.False(t, hub.allowMessage(client, "lobby"))
+	assert.Equal(t, int64(1), hub.GetMetrics().RateLimitedMessages)
+}
+
+// TestHub_RateLimit_DisconnectsRepeatOffender closes clients that keep exceeding the limit
+func TestHub_RateLimit_DisconnectsRepeatOffender(t *testing.T) {
+	hub := newRateLimitedHub(t, &HubConfig{
+		ClientRateLimit:        MessageRateLimit{Rate: 1, Period: time.Minute, Burst: 1},
+		MaxRateLimitViolations: 2,
+	})
+	client := joinRateLimitedRoom(t, hub, "c1", 1, "lobby")
+
+	assert.True(t, hub.allowMessage(client, "lobby"))
+	assert.False(t, hub.allowMessage(client, "lobby"))
+	assert.Equal(t, 1, hub.GetClientCount(), "one violation is tolerated")
+	assert.False(t, hub.allowMessage(client, "lobby"))
+
+	assert.Equal(t, 0, hub.GetClientCount())
+	assert.Equal(t,
+		websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "rate limit exceeded"),
+		client.closeMessage)
+	metrics := hub.GetMetrics()
+	assert.Equal(t, int64(2), metrics.RateLimitedMessages)
+	assert.Equal(t, int64(1), metrics.RateLimitDisconnects)
+
+	// Messages read before the close are dropped without counting twice
+	assert.False(t, hub.allowMessage(client, "lobby"))
+	assert.Equal(t, int64(1), hub.GetMetrics().RateLimitDisconnects)
+}
+
+// TestHub_NoRateLimitByDefault leaves messages unlimited without configuration
+func TestHub_NoRateLimitByDefault(t *testing.T) {
+	hub := newRateLimitedHub(t, DefaultHubConfig())
+	client := joinRateLimitedRoom(t, hub, "c1", 1, "lobby")
+
+	for range 100 {
+		require.True(t, hub.allowMessage(client, "lobby"))
+	}
+	assert.Zero(t, hub.GetMetrics().RateLimitedMessages)
+}
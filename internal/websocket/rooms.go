@@ -0,0 +1,118 @@
+package websocket
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRoomExists   = errors.New("room already exists")
+	ErrRoomNotFound = errors.New("room not found")
+	ErrRoomFull     = errors.New("room is full")
+)
+
+// RoomAuthorizer decides whether a client may join a room; a non-nil error
+// rejects the join and is reported to the client. It runs on the hub
+// goroutine, so it must not block, but it may call the hub's read methods
+// such as GetRoomInfo.
+type RoomAuthorizer func(client *Client, room string) error
+
+// RoomInfo holds room metadata
+type RoomInfo struct {
+	Name      string
+	CreatedAt time.Time
+	// CreatorID is the user that created the room, or first joined it
+	CreatorID uint
+	// MaxMembers caps concurrent connections in the room; zero means
+	// unlimited
+	MaxMembers int
+	// RateLimit limits the messages each member sends to the room; see
+	// SetRoomRateLimit
+	RateLimit MessageRateLimit
+
+	// explicit rooms come from CreateRoom and outlive their members;
+	// rooms created implicitly by a join are dropped once empty
+	explicit bool
+}
+
+// SetRoomAuthorizer sets the hook consulted before every room join. A nil
+// authorizer lets anyone join.
+func (h *Hub) SetRoomAuthorizer(authorizer RoomAuthorizer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.authorizer = authorizer
+}
+
+// CreateRoom creates a room ahead of its first join, so its metadata is kept
+// while it has no members
+func (h *Hub) CreateRoom(name string, creatorID uint, maxMembers int) (RoomInfo, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if info, ok := h.rooms[name]; ok && info.explicit {
+		return RoomInfo{}, ErrRoomExists
+	}
+
+	info := &RoomInfo{
+		Name:       name,
+		CreatedAt:  time.Now(),
+		CreatorID:  creatorID,
+		MaxMembers: maxMembers,
+		explicit:   true,
+	}
+	h.rooms[name] = info
+	delete(h.roomLimiters, name)
+	return *info, nil
+}
+
+// DeleteRoom removes a room created with CreateRoom. Current members stay in
+// the room until it empties.
+func (h *Hub) DeleteRoom(name string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	info, ok := h.rooms[name]
+	if !ok || !info.explicit {
+		return ErrRoomNotFound
+	}
+	info.explicit = false
+	h.dropRoomIfEmpty(name)
+	return nil
+}
+
+// GetRoomInfo returns a room's metadata
+func (h *Hub) GetRoomInfo(name string) (RoomInfo, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	info, ok := h.rooms[name]
+	if !ok {
+		return RoomInfo{}, false
+	}
+	return *info, true
+}
+
+// authorizeJoin runs the room authorizer, if any
+func (h *Hub) authorizeJoin(client *Client, room string) error {
+	h.mutex.RLock()
+	authorizer := h.authorizer
+	h.mutex.RUnlock()
+
+	if authorizer == nil {
+		return nil
+	}
+	return authorizer(client, room)
+}
+
+// dropRoomIfEmpty removes a room with no members, keeping the metadata of
+// rooms created with CreateRoom. Must be called with h.mutex held.
+func (h *Hub) dropRoomIfEmpty(room string) {
+	if len(h.roomClients[room]) > 0 {
+		return
+	}
+	delete(h.roomClients, room)
+	if info, ok := h.rooms[room]; ok && !info.explicit {
+		delete(h.rooms, room)
+		delete(h.roomLimiters, room)
+	}
+}
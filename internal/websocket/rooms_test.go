@@ -0,0 +1,120 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// assertJoinRejected checks the next message reports a rejected join
+func assertJoinRejected(t *testing.T, client *Client, room, reason string) {
+	t.Helper()
+	msg := receive(client)
+	require.NotNil(t, msg, "%s was not told about the rejected join", client.ID)
+	assert.Equal(t, MessageTypeError, msg.Type)
+	assert.Equal(t, map[string]string{"action": "subscribe", "room": room, "error": reason}, msg.Data)
+}
+
+// assertJoined checks the next message acks the join
+func assertJoined(t *testing.T, client *Client, room string) {
+	t.Helper()
+	msg := receive(client)
+	require.NotNil(t, msg, "%s was not told about the join", client.ID)
+	assert.Equal(t, MessageTypeAck, msg.Type)
+	assert.Equal(t, map[string]string{"action": "subscribed", "room": room}, msg.Data)
+}
+
+// TestHub_RoomAuthorizer lets only invited users into a private room
+func TestHub_RoomAuthorizer(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	go hub.Run()
+
+	invited := map[uint]bool{1: true}
+	hub.SetRoomAuthorizer(func(client *Client, room string) error {
+		if room == "private" && !invited[client.UserID] {
+			return errors.New("not invited")
+		}
+		return nil
+	})
+
+	guest := connect(t, hub, "guest", 1)
+	stranger := connect(t, hub, "stranger", 2)
+
+	stranger.handleMessage(&Message{Type: MessageTypeSubscribe, Data: "private"})
+	assertJoinRejected(t, stranger, "private", "not invited")
+	assert.False(t, hub.inRoom(stranger, "private"))
+
+	guest.handleMessage(&Message{Type: MessageTypeSubscribe, Data: "private"})
+	assertJoined(t, guest, "private")
+	assert.True(t, hub.inRoom(guest, "private"))
+
+	// Server-side joins are authorized too
+	hub.JoinRoom(stranger, "private")
+	assertJoinRejected(t, stranger, "private", "not invited")
+	assert.Equal(t, 1, hub.GetRoomClientCount("private"))
+}
+
+// TestHub_CreateRoom_MaxMembers enforces the member limit at join time
+func TestHub_CreateRoom_MaxMembers(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	go hub.Run()
+
+	info, err := hub.CreateRoom("small", 7, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "small", info.Name)
+	assert.Equal(t, uint(7), info.CreatorID)
+	assert.Equal(t, 1, info.MaxMembers)
+	assert.WithinDuration(t, time.Now(), info.CreatedAt, time.Second)
+
+	_, err = hub.CreateRoom("small", 8, 0)
+	assert.ErrorIs(t, err, ErrRoomExists)
+
+	first := connect(t, hub, "first", 1)
+	second := connect(t, hub, "second", 2)
+
+	first.handleMessage(&Message{Type: MessageTypeSubscribe, Data: "small"})
+	assertJoined(t, first, "small")
+
+	second.handleMessage(&Message{Type: MessageTypeSubscribe, Data: "small"})
+	assertJoinRejected(t, second, "small", ErrRoomFull.Error())
+	assert.Equal(t, 1, hub.GetRoomClientCount("small"))
+
+	// Rejoining as an existing member is not refused
+	first.handleMessage(&Message{Type: MessageTypeSubscribe, Data: "small"})
+	assertJoined(t, first, "small")
+}
+
+// TestHub_RoomInfo_Lifecycle keeps explicit rooms and drops implicit ones once empty
+func TestHub_RoomInfo_Lifecycle(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	client := newTestClient("client", 3)
+	hub.registerClient(client)
+
+	// A room created by joining takes the joining user as creator
+	hub.handleJoinRoom(&RoomOperation{Client: client, Room: "adhoc"})
+	info, ok := hub.GetRoomInfo("adhoc")
+	require.True(t, ok)
+	assert.Equal(t, uint(3), info.CreatorID)
+	assert.Equal(t, 0, info.MaxMembers)
+
+	hub.handleLeaveRoom(&RoomOperation{Client: client, Room: "adhoc"})
+	_, ok = hub.GetRoomInfo("adhoc")
+	assert.False(t, ok)
+
+	// A created room outlives its members until deleted
+	_, err := hub.CreateRoom("lobby", 1, 0)
+	require.NoError(t, err)
+	hub.handleJoinRoom(&RoomOperation{Client: client, Room: "lobby"})
+	hub.unregisterClient(client)
+	_, ok = hub.GetRoomInfo("lobby")
+	assert.True(t, ok)
+
+	require.NoError(t, hub.DeleteRoom("lobby"))
+	_, ok = hub.GetRoomInfo("lobby")
+	assert.False(t, ok)
+	assert.ErrorIs(t, hub.DeleteRoom("lobby"), ErrRoomNotFound)
+}
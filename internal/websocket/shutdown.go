@@ -0,0 +1,105 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// goingAwayFrame is the close frame sent to clients when the hub shuts down
+var goingAwayFrame = websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+
+// submit sends v on one of the hub's channels unless the hub has stopped,
+// reporting whether it was sent
+func submit[T any](h *Hub, ch chan T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// Shutdown stops the hub gracefully. It stops accepting registrations, sends
+// every client a close message followed by a going-away close frame, waits
+// for their send buffers to drain until ctx is done, closes the connections
+// and stops Run. It returns ctx's error if the deadline cut it short.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mutex.Lock()
+	if h.shuttingDown {
+		h.mutex.Unlock()
+		return nil
+	}
+	h.shuttingDown = true
+	running := h.running
+
+	clients := make([]*Client, 0, len(h.clients))
+	for client := range h.clients {
+		h.enqueue(client, &Message{
+			Type:      MessageTypeClose,
+			Data:      map[string]interface{}{"reason": "server shutting down"},
+			Timestamp: time.Now(),
+		})
+		h.removeClient(client, goingAwayFrame)
+		clients = append(clients, client)
+	}
+	events := h.events
+	h.events = nil
+	h.mutex.Unlock()
+
+	h.logger.Info("Shutting down WebSocket hub", zap.Int("clients", len(clients)))
+
+	// Tell other instances these users went offline
+	for _, message := range events {
+		h.publish(message)
+	}
+	if h.backplane != nil {
+		if err := h.backplane.Close(); err != nil {
+			h.logger.Warn("Failed to close backplane", zap.Error(err))
+		}
+	}
+
+	err := h.drain(ctx, clients)
+	for _, client := range clients {
+		if client.conn != nil {
+			client.conn.Close()
+		}
+	}
+
+	close(h.quit)
+	if !running {
+		close(h.done)
+		return err
+	}
+	select {
+	case <-h.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return err
+}
+
+// drain waits for each client's WritePump to flush its buffer and exit
+func (h *Hub) drain(ctx context.Context, clients []*Client) error {
+	for _, client := range clients {
+		if client.writeDone == nil {
+			continue
+		}
+		select {
+		case <-client.writeDone:
+		case <-ctx.Done():
+			h.logger.Warn("WebSocket clients not drained before shutdown deadline")
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// isShuttingDown reports whether Shutdown has been called
+func (h *Hub) isShuttingDown() bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.shuttingDown
+}
@@ -0,0 +1,102 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestHub_Shutdown notifies connected clients, closes them and stops Run
+func TestHub_Shutdown(t *testing.T) {
+	hub, url, _ := newAuthTestServer(t, true)
+
+	conns := make([]*websocket.Conn, 2)
+	for i := range conns {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		readWelcome(t, conn)
+		conns[i] = conn
+	}
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 2 }, time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, hub.Shutdown(ctx))
+
+	for _, conn := range conns {
+		var msg Message
+		require.NoError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, MessageTypeClose, msg.Type)
+
+		_, _, err := conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "err = %v", err)
+	}
+
+	assert.Equal(t, 0, hub.GetClientCount())
+	assert.Equal(t, int64(0), hub.GetMetrics().ActiveConnections)
+	select {
+	case <-hub.done:
+	default:
+		t.Error("Run should have stopped")
+	}
+
+	// New connections are refused
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	// A second shutdown is a no-op
+	assert.NoError(t, hub.Shutdown(ctx))
+}
+
+// TestHub_Shutdown_NotRunning stops a hub whose Run never started
+func TestHub_Shutdown_NotRunning(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	client := newTestClient("client", 1)
+	hub.registerClient(client)
+
+	require.NoError(t, hub.Shutdown(context.Background()))
+
+	msg := receive(client)
+	require.NotNil(t, msg)
+	assert.Equal(t, MessageTypeClose, msg.Type)
+	_, ok := <-client.send
+	assert.False(t, ok, "send channel should be closed")
+	assert.Equal(t, goingAwayFrame, client.closeMessage)
+	assert.Equal(t, 0, hub.GetClientCount())
+
+	// Run returns immediately and hub operations no longer block
+	hub.Run()
+	hub.Broadcast(NewMessage(MessageTypeMessage, "late"))
+	hub.JoinRoom(client, "lobby")
+
+	late := newTestClient("late", 2)
+	hub.registerClient(late)
+	assert.Equal(t, 0, hub.GetClientCount())
+	_, ok = <-late.send
+	assert.False(t, ok, "clients registering during shutdown are closed")
+}
+
+// TestHub_Shutdown_Deadline returns the context error when clients do not drain
+func TestHub_Shutdown_Deadline(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	go hub.Run()
+
+	// A client whose WritePump never runs
+	client := newTestClient("stuck", 1)
+	client.writeDone = make(chan struct{})
+	hub.registerClient(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, hub.Shutdown(ctx), context.DeadlineExceeded)
+	assert.Equal(t, 0, hub.GetClientCount())
+}
@@ -0,0 +1,45 @@
+package integration
+
+import (
+	"testing"
+
+	gormdao "github.com/jrjohn/arcana-cloud-go/internal/domain/dao/impl/gorm"
+	"github.com/jrjohn/arcana-cloud-go/internal/testutil"
+)
+
+// ========================================
+// SQLite Tests
+// ========================================
+//
+// These run the shared DAO suite against an in-memory SQLite database and
+// need no build tag or external services.
+
+func TestSQLite_UserDAO(t *testing.T) {
+	db := testutil.NewTestSQLiteDB(t)
+
+	userDAO := gormdao.NewUserDAO(db)
+	runUserDAOTests(t, userDAO)
+}
+
+func TestSQLite_RefreshTokenDAO(t *testing.T) {
+	db := testutil.NewTestSQLiteDB(t)
+
+	userDAO := gormdao.NewUserDAO(db)
+	tokenDAO := gormdao.NewRefreshTokenDAO(db)
+	runRefreshTokenDAOTests(t, userDAO, tokenDAO)
+}
+
+func TestSQLite_PluginDAO(t *testing.T) {
+	db := testutil.NewTestSQLiteDB(t)
+
+	pluginDAO := gormdao.NewPluginDAO(db)
+	runPluginDAOTests(t, pluginDAO)
+}
+
+func TestSQLite_PluginExtensionDAO(t *testing.T) {
+	db := testutil.NewTestSQLiteDB(t)
+
+	pluginDAO := gormdao.NewPluginDAO(db)
+	extDAO := gormdao.NewPluginExtensionDAO(db)
+	runPluginExtensionDAOTests(t, pluginDAO, extDAO)
+}